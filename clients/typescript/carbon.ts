@@ -0,0 +1,1199 @@
+// Code generated by cmd/apigen from pkg/api. DO NOT EDIT.
+
+export interface AddMessageRequest {
+  group_id: string;
+  saga?: string;
+  schema?: string;
+  messages: Message[];
+  user?: UserProfile;
+}
+
+export interface BackupInfo {
+  id: string;
+  group_id?: string;
+  key: string;
+  size: number;
+  created_at: string;
+}
+
+export interface BackupRequest {
+  group_id?: string;
+}
+
+export interface BackupsResponse {
+  backups: BackupInfo[];
+}
+
+export interface BulkAddRequest {
+  group_id: string;
+  episodes: EpisodeData[];
+  partial?: boolean;
+}
+
+export interface BulkIngestResult {
+  group_id?: string;
+  succeeded?: number;
+  failed?: number;
+  results?: EpisodeResult[];
+}
+
+export interface BulkSearchQuery {
+  query_id?: string;
+  query: string;
+}
+
+export interface BulkSearchRequest {
+  group_id: string;
+  queries: BulkSearchQuery[];
+  union?: boolean;
+}
+
+export interface BulkSearchResponse {
+  results?: Record<string, EntityEdge[]>;
+  union?: UnionSearchResult[];
+}
+
+export interface Change {
+  group_id: string;
+  seq: number;
+  kind: string;
+  uuid: string;
+  op: string;
+  at: string;
+}
+
+export interface ChangesResponse {
+  changes: Change[];
+}
+
+export interface CommunityDetection {
+  group_id: string;
+  status: string;
+  started_at: string;
+  finished_at?: string;
+  communities: number;
+  error?: string;
+}
+
+export interface CompactRequest {
+  group_id: string;
+  dry_run?: boolean;
+}
+
+export interface CompactionReport {
+  group_id: string;
+  ran_at: string;
+  dry_run: boolean;
+  digests: EpisodeDigest[];
+}
+
+export interface ConcurrencyPatch {
+  bulk_ingest?: number;
+  bulk_search?: number;
+  extraction_workers?: number;
+}
+
+export interface ConcurrencyStats {
+  bulk_ingest: LimiterStats;
+  bulk_search: LimiterStats;
+  extraction_workers: LimiterStats;
+}
+
+export interface ConsistencyReport {
+  group_id: string;
+  checked_at: string;
+  checked: number;
+  inconsistent: number;
+  regenerated: number;
+  failed: number;
+  entities: EntityConsistency[];
+}
+
+export interface ConsistencyRequest {
+  group_id: string;
+  regenerate?: boolean;
+}
+
+export interface Contradiction {
+  facts: EntityEdge[];
+  reason?: string;
+}
+
+export interface ContradictionReport {
+  group_id: string;
+  scanned_at: string;
+  checked: number;
+  failed: number;
+  contradictions: Contradiction[];
+}
+
+export interface ContradictionsRequest {
+  group_id: string;
+}
+
+export interface Coverage {
+  best: number;
+  relevant: number;
+  answerable: boolean;
+}
+
+export interface CreateSessionRequest {
+  group_id: string;
+  name?: string;
+}
+
+export interface DateRange {
+  from?: string;
+  to?: string;
+}
+
+export interface DeadLetter {
+  uuid: string;
+  group_id: string;
+  name: string;
+  episode: EpisodeData;
+  error: string;
+  attempts: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface DeadLettersResponse {
+  dead_letters: DeadLetter[];
+}
+
+export interface DedupeEdgesReport {
+  group_id: string;
+  ran_at: string;
+  dry_run: boolean;
+  scanned: number;
+  removed: number;
+  merged: MergedEdge[];
+}
+
+export interface DedupeEdgesRequest {
+  group_id: string;
+  dry_run?: boolean;
+}
+
+export interface DetectRequest {
+  group_id: string;
+  wait?: boolean;
+}
+
+export interface EntityConsistency {
+  uuid: string;
+  name: string;
+  summary: string;
+  issues?: string[];
+  regenerated: boolean;
+  new_summary?: string;
+  error?: string;
+}
+
+export interface EntityEdge {
+  uuid: string;
+  source_node_uuid: string;
+  target_node_uuid: string;
+  group_id: string;
+  name: string;
+  fact: string;
+  created_at: string;
+  expired_at?: string;
+  valid_at: string;
+  invalid_at?: string;
+  episodes: string[];
+  mention_count?: number;
+  fact_embedding?: number[];
+  attributes?: Record<string, unknown>;
+  confidence?: number;
+  citation?: string;
+}
+
+export interface EntityGaps {
+  uuid: string;
+  name: string;
+  checklist?: string;
+  known_attributes: Record<string, unknown>;
+  known_relations: Record<string, string[]>;
+  missing_attributes: string[];
+  missing_relations: string[];
+  type: string;
+  questions: string[];
+}
+
+export interface EntityNode {
+  uuid: string;
+  name: string;
+  group_id: string;
+  created_at: string;
+  summary?: string;
+  attributes?: Record<string, unknown>;
+  labels: string[];
+  name_embedding?: number[];
+  version: number;
+}
+
+export interface EntityPatch {
+  name?: string;
+  summary?: string;
+  attributes?: Record<string, unknown>;
+  version?: number;
+}
+
+export interface EntityResults {
+  uuid: string;
+  name: string;
+  summary?: string;
+  facts: EntityEdge[];
+}
+
+export interface EntitySalience {
+  uuid: string;
+  name: string;
+  summary?: string;
+  labels: string[];
+  degree: number;
+  mentions: number;
+  last_seen_at?: string;
+  score: number;
+}
+
+export interface EpisodeData {
+  content: string;
+  saga?: string;
+  schema?: string;
+  source?: string;
+}
+
+export interface EpisodeDigest {
+  uuid?: string;
+  episodes: string[];
+  from: string;
+  to: string;
+  entities: number;
+  facts: number;
+}
+
+export interface EpisodeResult {
+  index?: number;
+  status?: string;
+  error?: string;
+  episode?: EpisodeData;
+}
+
+export interface EpisodesResponse {
+  episodes: EpisodicNode[];
+}
+
+export interface EpisodicNode {
+  uuid: string;
+  name: string;
+  group_id: string;
+  created_at: string;
+  valid_at: string;
+  content: string;
+  source: string;
+  source_description: string;
+  entity_edges: string[];
+  topics?: string[];
+  content_ref?: string;
+}
+
+export interface ErrorResponse {
+  error: string;
+  fields?: FieldError[];
+}
+
+export interface ExportEdge {
+  type: string;
+  source_uuid: string;
+  target_uuid: string;
+  properties: Record<string, unknown>;
+}
+
+export interface ExportNode {
+  labels: string[];
+  properties: Record<string, unknown>;
+}
+
+export interface FactChecklist {
+  attributes?: string[];
+  relations?: string[];
+}
+
+export interface FactGaps {
+  uuid: string;
+  name: string;
+  checklist?: string;
+  known_attributes: Record<string, unknown>;
+  known_relations: Record<string, string[]>;
+  missing_attributes: string[];
+  missing_relations: string[];
+}
+
+export interface FactGapsRequest {
+  checklist?: string;
+  attributes?: string[];
+  relations?: string[];
+}
+
+export interface FactPath {
+  nodes: PathNode[];
+  edges: EntityEdge[];
+  chain: string;
+  summary?: string;
+}
+
+export interface FieldError {
+  field: string;
+  constraint: string;
+  param?: string;
+  message: string;
+}
+
+export interface GraphExport {
+  version: number;
+  group_id?: string;
+  exported_at: string;
+  nodes: ExportNode[];
+  edges: ExportEdge[];
+}
+
+export interface GraphView {
+  nodes: GraphViewNode[];
+  links: GraphViewLink[];
+}
+
+export interface GraphViewLink {
+  id: string;
+  source: string;
+  target: string;
+  label: string;
+  fact: string;
+}
+
+export interface GraphViewNode {
+  id: string;
+  label: string;
+  summary?: string;
+  depth: number;
+}
+
+export interface GroupLimits {
+  max_entities?: number;
+  max_edges?: number;
+  max_episodes?: number;
+  on_limit?: string;
+  pinned?: string[];
+}
+
+export interface GroupNode {
+  group_id: string;
+  name: string;
+  owner?: string;
+  created_at: string;
+  updated_at: string;
+  settings: GroupSettings;
+  metadata?: Record<string, unknown>;
+}
+
+export interface GroupPatch {
+  name?: string;
+  owner?: string;
+  settings?: GroupSettings;
+  metadata?: Record<string, unknown>;
+}
+
+export interface GroupSettings {
+  ontology?: string;
+  retention_days?: number;
+  model?: string;
+  prompts?: PromptOverrides;
+  verify_facts?: boolean;
+  checklists?: Record<string, FactChecklist>;
+  attribute_schemas?: Record<string, Record<string, unknown>>;
+  invalid_attributes?: string;
+  limits?: GroupLimits;
+  fact_lifetimes?: Record<string, number>;
+  synonyms?: Record<string, string>;
+  transforms?: string[];
+  moderation?: ModerationPolicy;
+}
+
+export interface GroupStats {
+  group_id: string;
+  entities: number;
+  edges: number;
+  valid_edges: number;
+  invalid_edges: number;
+  episodes: number;
+  sagas: number;
+  communities: number;
+  average_degree: number;
+  last_entity_at?: string;
+  last_edge_at?: string;
+  last_episode_at?: string;
+  last_updated_at?: string;
+}
+
+export interface GroupSummary {
+  group_id: string;
+  name?: string;
+  metadata?: Record<string, unknown>;
+  episodes: number;
+  created_at: string;
+  last_episode_at: string;
+}
+
+export interface GroupsResponse {
+  groups: GroupSummary[];
+}
+
+export interface IngestJob {
+  uuid: string;
+  group_id: string;
+  status: string;
+  total: number;
+  processed: number;
+  failed?: number[];
+  error?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface IngestProfile {
+  stages: StageTiming[];
+  duration_ms: number;
+  error?: string;
+}
+
+export interface IngestProfileRequest {
+  content?: string;
+}
+
+export interface KnowledgeGaps {
+  group_id: string;
+  entities: EntityGaps[];
+}
+
+export interface LimiterStats {
+  limit: number;
+  active: number;
+  waiting: number;
+  waiting_batch: number;
+  waiting_by_group?: Record<string, number>;
+  acquired: number;
+  avg_wait_ms: number;
+  max_wait_ms: number;
+  oldest_wait_ms: number;
+}
+
+export interface Mem0AddRequest {
+  user_id?: string;
+  agent_id?: string;
+  run_id?: string;
+  messages?: Message[];
+  metadata?: Record<string, unknown>;
+}
+
+export interface Mem0Memory {
+  id: string;
+  memory: string;
+  event?: string;
+  user_id?: string;
+  agent_id?: string;
+  run_id?: string;
+  metadata?: Record<string, unknown>;
+  created_at?: string;
+}
+
+export interface Mem0Results {
+  results: Mem0Memory[];
+}
+
+export interface Mem0SearchRequest {
+  user_id?: string;
+  agent_id?: string;
+  run_id?: string;
+  query?: string;
+  limit?: number;
+}
+
+export interface MergedEdge {
+  kept_uuid: string;
+  removed_uuids: string[];
+  source_node_uuid: string;
+  target_node_uuid: string;
+  name: string;
+  fact: string;
+  episodes: string[];
+}
+
+export interface Message {
+  role?: string;
+  content: string;
+}
+
+export interface ModerationPolicy {
+  action?: string;
+  categories?: string[];
+}
+
+export interface ModerationRecord {
+  uuid: string;
+  group_id: string;
+  episode_name: string;
+  episode_uuid?: string;
+  action: string;
+  categories: string[];
+  content_sha256: string;
+  created_at: string;
+}
+
+export interface ModerationResponse {
+  records: ModerationRecord[];
+}
+
+export interface Neighborhood {
+  center: string;
+  depth: number;
+  nodes: NeighborhoodNode[];
+  edges: EntityEdge[];
+  communities: NeighborhoodCommunity[];
+}
+
+export interface NeighborhoodCommunity {
+  uuid: string;
+  name: string;
+  summary?: string;
+  members: string[];
+}
+
+export interface NeighborhoodNode {
+  entity: EntityNode;
+  hops: number;
+}
+
+export interface OrphanGCRequest {
+  group_id: string;
+  mode?: string;
+  dry_run?: boolean;
+}
+
+export interface OrphanReport {
+  group_id: string;
+  ran_at: string;
+  mode: string;
+  dry_run: boolean;
+  orphans: EntityNode[];
+}
+
+export interface PathNode {
+  uuid: string;
+  name: string;
+}
+
+export interface ProfileRequest {
+  group_id: string;
+  refresh?: boolean;
+}
+
+export interface PromptOverrides {
+  extract_nodes?: string;
+  extract_edges?: string;
+  dedupe_nodes?: string;
+  dedupe_edges?: string;
+  summarize_nodes?: string;
+  summarize_communities?: string;
+  community_name?: string;
+  summary_consistency?: string;
+  summarize_path?: string;
+  verify_facts?: string;
+  digest_episodes?: string;
+  synthesize_profile?: string;
+  gap_questions?: string;
+}
+
+export interface Provenance {
+  fact: EntityEdge;
+  episodes: EpisodicNode[];
+}
+
+export interface ReembedReport {
+  group_id: string;
+  ran_at: string;
+  model: string;
+  dry_run: boolean;
+  entities: number;
+  communities: number;
+  facts: number;
+  failed: number;
+  promoted: boolean;
+}
+
+export interface ReembedRequest {
+  group_id: string;
+  model?: string;
+  dry_run?: boolean;
+}
+
+export interface RestoreReport {
+  group_id?: string;
+  backup_id: string;
+  nodes: number;
+  edges: number;
+  restored_at: string;
+  pre_restore_backup_id?: string;
+}
+
+export interface RestoreRequest {
+  group_id?: string;
+  snapshot: string;
+  graph?: boolean;
+}
+
+export interface ScratchEntry {
+  group_id: string;
+  key: string;
+  value: unknown;
+  created_at: string;
+  updated_at: string;
+  expires_at?: string;
+}
+
+export interface ScratchRequest {
+  value?: unknown;
+  ttl_seconds?: number;
+}
+
+export interface ScratchpadResponse {
+  entries: ScratchEntry[];
+}
+
+export interface SearchCandidate {
+  uuid: string;
+  fact: string;
+  score?: number;
+  embedding_model?: string;
+}
+
+export interface SearchFilter {
+  relation_types?: string[];
+  entity_labels?: string[];
+  attributes?: Record<string, unknown>;
+  valid_at?: DateRange;
+  created_at?: DateRange;
+  metric?: string;
+  min_score?: number;
+  include_expired?: boolean;
+  hyde?: boolean;
+  rerank_depth?: number;
+  rerank_skip_margin?: number;
+  topics?: string[];
+  sentiments?: string[];
+  emotions?: string[];
+}
+
+export interface SearchRequest {
+  group_id: string;
+  query: string;
+  mode?: string;
+  limit?: number;
+  filter?: SearchFilter;
+  debug?: boolean;
+}
+
+export interface SearchResponse {
+  results?: EntityEdge[];
+  entities?: EntityResults[];
+  coverage?: Coverage;
+  paths?: FactPath[];
+  trace?: SearchTrace;
+}
+
+export interface SearchTrace {
+  strategy: string;
+  embedding_models?: string[];
+  metric?: string;
+  min_score?: number;
+  candidates: SearchCandidate[];
+  filtered?: string[];
+  reranked?: string[];
+  rerank_error?: string;
+  rerank_cached?: boolean;
+  rerank_skipped?: boolean;
+  linked_entities?: string[];
+  hypothetical?: string;
+  results: string[];
+  timings: StageTiming[];
+}
+
+export interface Session {
+  id: string;
+  group_id: string;
+  name: string;
+  created_at: string;
+}
+
+export interface SessionMessagesRequest {
+  schema?: string;
+  messages: Message[];
+}
+
+export interface StageTiming {
+  stage: string;
+  duration_ms: number;
+}
+
+export interface StatusResponse {
+  status: string;
+}
+
+export interface StreamEpisode {
+  group_id?: string;
+  content: string;
+  saga?: string;
+  schema?: string;
+  source?: string;
+}
+
+export interface StreamResult {
+  index: number;
+  group_id?: string;
+  status: string;
+  error?: string;
+}
+
+export interface StructuredEntity {
+  name: string;
+  type?: string;
+  summary?: string;
+  attributes?: Record<string, unknown>;
+}
+
+export interface StructuredEpisodeRequest {
+  group_id: string;
+  name?: string;
+  content?: string;
+  saga?: string;
+  entities: StructuredEntity[];
+  facts?: StructuredFact[];
+}
+
+export interface StructuredFact {
+  source: string;
+  target: string;
+  relation: string;
+  fact: string;
+  valid_at?: string;
+  attributes?: Record<string, unknown>;
+  exclusive?: boolean;
+}
+
+export interface StructuredResult {
+  episode_uuid: string;
+  entities: Record<string, string>;
+}
+
+export interface SynonymsRequest {
+  synonyms: Record<string, string>;
+}
+
+export interface SynonymsResponse {
+  synonyms: Record<string, string>;
+}
+
+export interface SynthesizedProfile {
+  group_id: string;
+  preferences: string[];
+  facts: string[];
+  relationships: string[];
+  open_questions: string[];
+  generated_at: string;
+}
+
+export interface TopEntitiesResponse {
+  entities: EntitySalience[];
+}
+
+export interface UnionSearchResult {
+  uuid: string;
+  source_node_uuid: string;
+  target_node_uuid: string;
+  group_id: string;
+  name: string;
+  fact: string;
+  created_at: string;
+  expired_at?: string;
+  valid_at: string;
+  invalid_at?: string;
+  episodes: string[];
+  mention_count?: number;
+  fact_embedding?: number[];
+  attributes?: Record<string, unknown>;
+  confidence?: number;
+  citation?: string;
+  queries: string[];
+}
+
+export interface UserProfile {
+  name?: string;
+  attributes?: Record<string, unknown>;
+}
+
+/** Error returned for non-2xx responses. fields lists the offending fields of an invalid request. */
+export class CarbonError extends Error {
+  constructor(
+    public readonly status: number,
+    message: string,
+    public readonly fields: FieldError[] = [],
+  ) {
+    super(message);
+  }
+}
+
+/** Typed client for the carbon HTTP API. Streaming ingest (POST /bulk/messages/stream) is not covered. */
+export class CarbonClient {
+  constructor(
+    private readonly baseURL: string,
+    private readonly fetchImpl: typeof fetch = fetch,
+    private readonly apiKey?: string,
+  ) {}
+
+  private async request<T>(
+    method: string,
+    path: string,
+    query?: Record<string, string | number | undefined>,
+    body?: unknown,
+  ): Promise<T> {
+    const url = new URL(this.baseURL.replace(/\/$/, "") + path);
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers: Record<string, string> = {};
+    if (body !== undefined) headers["Content-Type"] = "application/json";
+    if (this.apiKey) headers["Authorization"] = `Bearer ${this.apiKey}`;
+    const resp = await this.fetchImpl(url.toString(), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const data = await resp.json().catch(() => ({}));
+    if (!resp.ok) {
+      const err = data as ErrorResponse;
+      throw new CarbonError(resp.status, err.error ?? resp.statusText, err.fields);
+    }
+    return data as T;
+  }
+
+  /** POST /messages. Ingest messages as episodes, one at a time. */
+  addMessages(req: AddMessageRequest): Promise<StatusResponse> {
+    return this.request("POST", `/messages`, undefined, req);
+  }
+
+  /** POST /episodes/structured. Ingest entities and facts as given, without LLM extraction. */
+  addStructuredEpisode(req: StructuredEpisodeRequest): Promise<StructuredResult> {
+    return this.request("POST", `/episodes/structured`, undefined, req);
+  }
+
+  /** POST /sessions. Start a conversation session backed by a saga, or resume the saga with the given name. */
+  createSession(req: CreateSessionRequest): Promise<Session> {
+    return this.request("POST", `/sessions`, undefined, req);
+  }
+
+  /** GET /sessions/:id. Get a session. */
+  getSession(id: string): Promise<Session> {
+    return this.request("GET", `/sessions/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** POST /sessions/:id/messages. Ingest messages into a session's group and saga, in order, recording each speaker's role. */
+  addSessionMessages(id: string, req: SessionMessagesRequest): Promise<StatusResponse> {
+    return this.request("POST", `/sessions/${encodeURIComponent(id)}/messages`, undefined, req);
+  }
+
+  /** POST /search. Search facts, or multi-hop fact paths in "paths" mode. */
+  search(req: SearchRequest): Promise<SearchResponse> {
+    return this.request("POST", `/search`, undefined, req);
+  }
+
+  /** POST /communities/detect. Detect and summarize communities of a group. */
+  detectCommunities(req: DetectRequest): Promise<StatusResponse> {
+    return this.request("POST", `/communities/detect`, undefined, req);
+  }
+
+  /** GET /communities/detect/:group_id. Get the running or latest community detection of a group. */
+  getCommunityDetection(groupID: string): Promise<CommunityDetection> {
+    return this.request("GET", `/communities/detect/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** POST /bulk/messages. Ingest a batch of episodes. Per-episode results are only returned when partial is set. */
+  bulkAddEpisodes(req: BulkAddRequest): Promise<BulkIngestResult> {
+    return this.request("POST", `/bulk/messages`, undefined, req);
+  }
+
+  /** POST /bulk/messages/retry. Re-ingest the failed episodes of a partial bulk result. */
+  retryBulkEpisodes(req: BulkIngestResult): Promise<BulkIngestResult> {
+    return this.request("POST", `/bulk/messages/retry`, undefined, req);
+  }
+
+  /** POST /bulk/search. Run several searches in one request. */
+  bulkSearch(req: BulkSearchRequest): Promise<BulkSearchResponse> {
+    return this.request("POST", `/bulk/search`, undefined, req);
+  }
+
+  /** GET /entities/:uuid. Get an entity with its current version. */
+  getEntity(uuid: string): Promise<EntityNode> {
+    return this.request("GET", `/entities/${encodeURIComponent(uuid)}`, undefined, undefined);
+  }
+
+  /** GET /entities/:uuid/neighborhood. Get the entities within a few hops of an entity, the valid facts linking them and their communities. */
+  getNeighborhood(uuid: string, query: { depth?: number }): Promise<Neighborhood> {
+    return this.request("GET", `/entities/${encodeURIComponent(uuid)}/neighborhood`, query, undefined);
+  }
+
+  /** PATCH /entities/:uuid. Update an entity, optionally only if it is still at a given version. */
+  updateEntity(uuid: string, req: EntityPatch): Promise<EntityNode> {
+    return this.request("PATCH", `/entities/${encodeURIComponent(uuid)}`, undefined, req);
+  }
+
+  /** POST /entities/:uuid/summarize. Rebuild an entity summary from its facts. */
+  regenerateSummary(uuid: string): Promise<EntityNode> {
+    return this.request("POST", `/entities/${encodeURIComponent(uuid)}/summarize`, undefined, undefined);
+  }
+
+  /** POST /entities/:uuid/gaps. List checklist attributes and relations an entity has no facts for. */
+  findFactGaps(uuid: string, req: FactGapsRequest): Promise<FactGaps> {
+    return this.request("POST", `/entities/${encodeURIComponent(uuid)}/gaps`, undefined, req);
+  }
+
+  /** GET /facts/:uuid. Get a fact by UUID, or by the group's citation handle (F-...), including invalidated facts. */
+  getFact(uuid: string, query: { group_id?: string }): Promise<EntityEdge> {
+    return this.request("GET", `/facts/${encodeURIComponent(uuid)}`, query, undefined);
+  }
+
+  /** GET /facts/:uuid/provenance. Get a fact with the full content of the episodes it was extracted from. */
+  getProvenance(uuid: string): Promise<Provenance> {
+    return this.request("GET", `/facts/${encodeURIComponent(uuid)}/provenance`, undefined, undefined);
+  }
+
+  /** POST /jobs/ingest. Start a checkpointed background bulk ingest. */
+  createIngestJob(req: BulkAddRequest): Promise<IngestJob> {
+    return this.request("POST", `/jobs/ingest`, undefined, req);
+  }
+
+  /** POST /maintenance/consistency. Check entity summaries against their facts. Requires the admin scope. */
+  checkConsistency(req: ConsistencyRequest): Promise<ConsistencyReport> {
+    return this.request("POST", `/maintenance/consistency`, undefined, req);
+  }
+
+  /** GET /maintenance/consistency/:group_id. Get the latest consistency report of a group. Requires the admin scope. */
+  getConsistencyReport(groupID: string): Promise<ConsistencyReport> {
+    return this.request("GET", `/maintenance/consistency/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** POST /maintenance/contradictions. Scan a group's valid facts for pairs that can't both be true. Requires the admin scope. */
+  scanContradictions(req: ContradictionsRequest): Promise<ContradictionReport> {
+    return this.request("POST", `/maintenance/contradictions`, undefined, req);
+  }
+
+  /** POST /maintenance/dedupe-edges. Merge duplicate facts of a group. Requires the admin scope. */
+  dedupeEdges(req: DedupeEdgesRequest): Promise<DedupeEdgesReport> {
+    return this.request("POST", `/maintenance/dedupe-edges`, undefined, req);
+  }
+
+  /** GET /maintenance/dedupe-edges/:group_id. Get the latest dedupe-edges report of a group. Requires the admin scope. */
+  getDedupeEdgesReport(groupID: string): Promise<DedupeEdgesReport> {
+    return this.request("GET", `/maintenance/dedupe-edges/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** POST /maintenance/orphans. Quarantine or delete entities without facts or mentions. Requires the admin scope. */
+  collectOrphans(req: OrphanGCRequest): Promise<OrphanReport> {
+    return this.request("POST", `/maintenance/orphans`, undefined, req);
+  }
+
+  /** GET /maintenance/orphans/:group_id. Get the latest orphan report of a group. Requires the admin scope. */
+  getOrphanReport(groupID: string): Promise<OrphanReport> {
+    return this.request("GET", `/maintenance/orphans/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** POST /maintenance/compact. Replace runs of old episodes with digest episodes. Requires the admin scope. */
+  compactEpisodes(req: CompactRequest): Promise<CompactionReport> {
+    return this.request("POST", `/maintenance/compact`, undefined, req);
+  }
+
+  /** GET /maintenance/compact/:group_id. Get the latest compaction report of a group. Requires the admin scope. */
+  getCompactionReport(groupID: string): Promise<CompactionReport> {
+    return this.request("GET", `/maintenance/compact/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** POST /maintenance/reembed. Re-embed entity names and facts stored with another embedding model. Requires the admin scope. */
+  reembed(req: ReembedRequest): Promise<ReembedReport> {
+    return this.request("POST", `/maintenance/reembed`, undefined, req);
+  }
+
+  /** GET /maintenance/reembed/:group_id. Get the latest reembed report of a group. Requires the admin scope. */
+  getReembedReport(groupID: string): Promise<ReembedReport> {
+    return this.request("GET", `/maintenance/reembed/${encodeURIComponent(groupID)}`, undefined, undefined);
+  }
+
+  /** GET /deadletters. List episodes that failed to ingest, most recently failed first. */
+  listDeadLetters(query: { group_id?: string }): Promise<DeadLettersResponse> {
+    return this.request("GET", `/deadletters`, query, undefined);
+  }
+
+  /** POST /deadletters/:id/requeue. Ingest a dead letter's episode again, deleting the dead letter once it succeeds. */
+  requeueDeadLetter(id: string): Promise<DeadLetter> {
+    return this.request("POST", `/deadletters/${encodeURIComponent(id)}/requeue`, undefined, undefined);
+  }
+
+  /** GET /jobs/:id. Get the progress of an ingest job. */
+  getIngestJob(id: string): Promise<IngestJob> {
+    return this.request("GET", `/jobs/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** GET /episodes. List a group's most recent episodes, newest first, optionally only those tagged with a topic. */
+  listEpisodes(query: { group_id: string; topic?: string; last_n?: number }): Promise<EpisodesResponse> {
+    return this.request("GET", `/episodes`, query, undefined);
+  }
+
+  /** GET /changes. List a group's changes after a sequence number, oldest first. */
+  getChanges(query: { group_id: string; since?: number; limit?: number }): Promise<ChangesResponse> {
+    return this.request("GET", `/changes`, query, undefined);
+  }
+
+  /** POST /profile. Get the group's user profile (preferences, facts, relationships, open questions), written by the LLM from the graph on first use or on refresh. */
+  synthesizeProfile(req: ProfileRequest): Promise<SynthesizedProfile> {
+    return this.request("POST", `/profile`, undefined, req);
+  }
+
+  /** GET /graph. Get a group's graph in D3 format. The server also accepts format=cytoscape and format=html. */
+  getGraph(query: { group_id: string; center?: string; depth?: number }): Promise<GraphView> {
+    return this.request("GET", `/graph`, query, undefined);
+  }
+
+  /** GET /groups. List groups. */
+  listGroups(): Promise<GroupsResponse> {
+    return this.request("GET", `/groups`, undefined, undefined);
+  }
+
+  /** GET /groups/:id. Get a group and its settings. */
+  getGroup(id: string): Promise<GroupNode> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}`, undefined, undefined);
+  }
+
+  /** PATCH /groups/:id. Update a group's metadata and settings. Requires the admin scope. */
+  updateGroup(id: string, req: GroupPatch): Promise<GroupNode> {
+    return this.request("PATCH", `/groups/${encodeURIComponent(id)}`, undefined, req);
+  }
+
+  /** GET /groups/:id/stats. Get node and edge counts of a group. */
+  getGroupStats(id: string): Promise<GroupStats> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/stats`, undefined, undefined);
+  }
+
+  /** GET /groups/:id/top-entities. Rank a group's entities by degree, mentions and recency. */
+  topEntities(id: string, query: { limit?: number }): Promise<TopEntitiesResponse> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/top-entities`, query, undefined);
+  }
+
+  /** GET /groups/:id/gaps. Report what the group's key entities lack by their entity types' checklists and attribute schemas, with questions to ask the user. */
+  findKnowledgeGaps(id: string, query: { limit?: number }): Promise<KnowledgeGaps> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/gaps`, query, undefined);
+  }
+
+  /** GET /groups/:id/contradictions. List the contradicting facts the latest contradiction scan found that are still valid. */
+  getContradictions(id: string): Promise<ContradictionReport> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/contradictions`, undefined, undefined);
+  }
+
+  /** GET /groups/:id/moderation. List the audit records of the group's episodes that moderation blocked or flagged, newest first. Requires the admin scope. */
+  listModerationRecords(id: string, query: { limit?: number }): Promise<ModerationResponse> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/moderation`, query, undefined);
+  }
+
+  /** GET /groups/:id/export. Export every node and relationship of a group with their stored properties, unfiltered by access policies. Requires the admin scope. */
+  exportGroup(id: string): Promise<GraphExport> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/export`, undefined, undefined);
+  }
+
+  /** GET /groups/:id/synonyms. Get a group's synonym table, used by text search and entity linking. */
+  getSynonyms(id: string): Promise<SynonymsResponse> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/synonyms`, undefined, undefined);
+  }
+
+  /** PATCH /groups/:id/synonyms. Add, replace or (with null) remove entries of a group's synonym table. Requires the admin scope. */
+  updateSynonyms(id: string, req: SynonymsRequest): Promise<SynonymsResponse> {
+    return this.request("PATCH", `/groups/${encodeURIComponent(id)}/synonyms`, undefined, req);
+  }
+
+  /** GET /groups/:id/scratchpad. List a group's unexpired scratchpad entries, ordered by key. */
+  listScratch(id: string): Promise<ScratchpadResponse> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/scratchpad`, undefined, undefined);
+  }
+
+  /** GET /groups/:id/scratchpad/:key. Get a scratchpad entry. */
+  getScratch(id: string, key: string): Promise<ScratchEntry> {
+    return this.request("GET", `/groups/${encodeURIComponent(id)}/scratchpad/${encodeURIComponent(key)}`, undefined, undefined);
+  }
+
+  /** PUT /groups/:id/scratchpad/:key. Set a scratchpad entry, optionally expiring after a TTL. Scratchpad entries are kept outside the knowledge graph. */
+  setScratch(id: string, key: string, req: ScratchRequest): Promise<ScratchEntry> {
+    return this.request("PUT", `/groups/${encodeURIComponent(id)}/scratchpad/${encodeURIComponent(key)}`, undefined, req);
+  }
+
+  /** DELETE /groups/:id/scratchpad/:key. Delete a scratchpad entry. */
+  deleteScratch(id: string, key: string): Promise<StatusResponse> {
+    return this.request("DELETE", `/groups/${encodeURIComponent(id)}/scratchpad/${encodeURIComponent(key)}`, undefined, undefined);
+  }
+
+  /** GET /admin/backups. List stored snapshots, newest first. Requires the admin scope. */
+  listBackups(query: { group_id?: string }): Promise<BackupsResponse> {
+    return this.request("GET", `/admin/backups`, query, undefined);
+  }
+
+  /** POST /admin/backups. Snapshot a group, or the graph as configured, to the backup store. */
+  createBackup(req: BackupRequest): Promise<BackupsResponse> {
+    return this.request("POST", `/admin/backups`, undefined, req);
+  }
+
+  /** POST /admin/restore. Replace a group, or the whole graph, with a snapshot after snapshotting its current state. */
+  restoreBackup(req: RestoreRequest): Promise<RestoreReport> {
+    return this.request("POST", `/admin/restore`, undefined, req);
+  }
+
+  /** GET /admin/concurrency. Get the bulk ingest and bulk search limits with their queue depth and wait times. */
+  getConcurrency(): Promise<ConcurrencyStats> {
+    return this.request("GET", `/admin/concurrency`, undefined, undefined);
+  }
+
+  /** PATCH /admin/concurrency. Change the bulk ingest and bulk search limits until the server restarts. */
+  updateConcurrency(req: ConcurrencyPatch): Promise<ConcurrencyStats> {
+    return this.request("PATCH", `/admin/concurrency`, undefined, req);
+  }
+
+  /** POST /debug/ingest-profile. Ingest an episode into a throwaway group and report how long each pipeline stage took. Requires [debug] enabled. */
+  profileIngest(req: IngestProfileRequest): Promise<IngestProfile> {
+    return this.request("POST", `/debug/ingest-profile`, undefined, req);
+  }
+
+  /** POST /v1/memories/. mem0: ingest messages and return the memories they added or invalidated. */
+  addMemories(req: Mem0AddRequest): Promise<Mem0Results> {
+    return this.request("POST", `/v1/memories/`, undefined, req);
+  }
+
+  /** GET /v1/memories/. mem0: list the current memories of a user, agent or run. */
+  listMemories(query: { user_id?: string; agent_id?: string; run_id?: string }): Promise<Mem0Results> {
+    return this.request("GET", `/v1/memories/`, query, undefined);
+  }
+
+  /** GET /v1/memories/:memory_id/. mem0: get a memory by ID. */
+  getMemory(memoryID: string): Promise<Mem0Memory> {
+    return this.request("GET", `/v1/memories/${encodeURIComponent(memoryID)}/`, undefined, undefined);
+  }
+
+  /** POST /v1/memories/search/. mem0: search the memories of a user, agent or run. */
+  searchMemories(req: Mem0SearchRequest): Promise<Mem0Results> {
+    return this.request("POST", `/v1/memories/search/`, undefined, req);
+  }
+}
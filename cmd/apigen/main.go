@@ -0,0 +1,38 @@
+// Command apigen writes the generated Go and TypeScript API clients.
+//
+//	go run ./cmd/apigen -root .
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/agenthands/carbon/internal/apigen"
+)
+
+func main() {
+	root := flag.String("root", ".", "Repository root")
+	flag.Parse()
+
+	for _, out := range []struct {
+		path string
+		gen  func() ([]byte, error)
+	}{
+		{"pkg/client/client_gen.go", apigen.Go},
+		{"clients/typescript/carbon.ts", apigen.TypeScript},
+	} {
+		src, err := out.gen()
+		if err != nil {
+			log.Fatalf("Failed to generate %s: %v", out.path, err)
+		}
+		path := filepath.Join(*root, out.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/agenthands/carbon/pkg/client"
+)
+
+// Fake data the conversation templates are filled with.
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "David", "Elena", "Farid", "Grace", "Hiro", "Ines", "Jonas", "Keiko", "Liam", "Maya", "Noah", "Olga", "Priya", "Rafael", "Sofia", "Tomas", "Yara"}
+	lastNames  = []string{"Chen", "Meyer", "Diaz", "Okafor", "Novak", "Larsen", "Haddad", "Tanaka", "Rossi", "Kowalski", "Silva", "Nguyen", "Fischer", "Patel", "Moreau"}
+	companies  = []string{"Acme Corp", "Globex", "Initech", "Umbrella Labs", "Stark Industries", "Wayne Logistics", "Hooli", "Vandelay Imports", "Soylent Foods", "Cyberdyne Systems"}
+	cities     = []string{"Berlin", "Lisbon", "Toronto", "Nairobi", "Osaka", "Austin", "Melbourne", "Warsaw", "Bogotá", "Oslo", "Seoul", "Dublin"}
+	roles      = []string{"software engineer", "product manager", "data scientist", "designer", "nurse", "teacher", "accountant", "architect", "sales lead", "researcher"}
+	hobbies    = []string{"hiking", "chess", "baking sourdough", "climbing", "playing the cello", "running marathons", "photography", "gardening", "sailing", "woodworking"}
+	pets       = []string{"a dog named Biscuit", "two cats", "a parrot called Kiwi", "a rescue greyhound", "a tortoise named Flash"}
+)
+
+// turnTemplates are the user turns of a conversation, in order; each is
+// followed by an assistant reply. Placeholders are filled per conversation.
+var turnTemplates = []string{
+	"Hi, I'm {name}. I work as a {role} at {company}.",
+	"I live in {city} with {pet}.",
+	"My manager is {colleague}, who joined {company} last year.",
+	"On weekends I like {hobby}, usually with {friend}.",
+	"Actually, I just moved from {city} to {city2} for a new job at {company2}.",
+	"{friend} is visiting me in {city2} next month; we met at university.",
+	"I've started {hobby2} too, {colleague} got me into it.",
+	"I'm planning a trip to {city3} in the spring with {friend}.",
+}
+
+var replyTemplates = []string{
+	"Nice to meet you, {first}! How do you like working at {company}?",
+	"{city} sounds lovely. How is {pet} doing?",
+	"Got it, {colleague} is your manager.",
+	"That sounds fun! How long have you been {hobby}?",
+	"Congratulations on the move to {city2} and the new role at {company2}!",
+	"I hope you and {friend} have a great time in {city2}.",
+	"Good to hear {colleague} introduced you to {hobby2}.",
+	"{city3} is a great choice for spring.",
+}
+
+// conversation is a generated multi-turn chat of one persona.
+type conversation struct {
+	groupID string
+	turns   []api.Message
+	query   string // Search run once the conversation is ingested
+}
+
+func pick(rng *rand.Rand, from []string) string {
+	return from[rng.IntN(len(from))]
+}
+
+// pickOther picks a value of from other than not.
+func pickOther(rng *rand.Rand, from []string, not ...string) string {
+	for {
+		if v := pick(rng, from); !slices.Contains(not, v) {
+			return v
+		}
+	}
+}
+
+func fullName(rng *rand.Rand) string {
+	return pick(rng, firstNames) + " " + pick(rng, lastNames)
+}
+
+// newConversation fills the templates for a random persona, with up to turns
+// user turns and their replies.
+func newConversation(rng *rand.Rand, groupID string, turns int) conversation {
+	first := pick(rng, firstNames)
+	city, company, hobby := pick(rng, cities), pick(rng, companies), pick(rng, hobbies)
+	city2 := pickOther(rng, cities, city)
+	friend := fullName(rng)
+	r := strings.NewReplacer(
+		"{name}", first+" "+pick(rng, lastNames), "{first}", first,
+		"{role}", pick(rng, roles), "{pet}", pick(rng, pets),
+		"{company}", company, "{company2}", pickOther(rng, companies, company),
+		"{city}", city, "{city2}", city2, "{city3}", pickOther(rng, cities, city, city2),
+		"{hobby}", hobby, "{hobby2}", pickOther(rng, hobbies, hobby),
+		"{colleague}", fullName(rng), "{friend}", friend,
+	)
+
+	c := conversation{groupID: groupID, query: pick(rng, []string{
+		"Where does " + first + " live?", "Where does " + first + " work?",
+		"What are " + first + "'s hobbies?", "Who is " + friend + "?",
+	})}
+	for i := range min(turns, len(turnTemplates)) {
+		c.turns = append(c.turns,
+			api.Message{Role: "user", Content: r.Replace(turnTemplates[i])},
+			api.Message{Role: "assistant", Content: r.Replace(replyTemplates[i])})
+	}
+	return c
+}
+
+// loadStats collects the outcome of one kind of request.
+type loadStats struct {
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+// loadReport summarizes the requests of one kind.
+type loadReport struct {
+	Requests int            `json:"requests"`
+	Errors   int            `json:"errors"`
+	ByError  map[string]int `json:"by_error,omitempty"` // Error count by status code, or the error for failed connections
+	MeanMs   float64        `json:"mean_ms"`
+	P50Ms    float64        `json:"p50_ms"`
+	P90Ms    float64        `json:"p90_ms"`
+	P99Ms    float64        `json:"p99_ms"`
+	MaxMs    float64        `json:"max_ms"`
+}
+
+func (s *loadStats) report() loadReport {
+	r := loadReport{Requests: len(s.latencies), ByError: s.errors}
+	for _, n := range s.errors {
+		r.Errors += n
+	}
+	if len(s.latencies) == 0 {
+		return r
+	}
+	sorted := slices.Clone(s.latencies)
+	slices.Sort(sorted)
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	at := func(q float64) float64 { return ms(sorted[int(q*float64(len(sorted)-1))]) }
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	r.MeanMs = ms(total / time.Duration(len(sorted)))
+	r.P50Ms, r.P90Ms, r.P99Ms = at(0.5), at(0.9), at(0.99)
+	r.MaxMs = ms(sorted[len(sorted)-1])
+	return r
+}
+
+// errorKind groups errors for the report: API errors by status code, others
+// by message without the request URL.
+func errorKind(err error) string {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		return fmt.Sprint(apiErr.StatusCode)
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// runLoadTest generates synthetic conversations and pushes them through the
+// HTTP API of a running server, one message per request at --rate requests
+// per second overall, then prints the latency and error distribution of the
+// ingest and search requests.
+func runLoadTest(args []string) {
+	fs := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	baseURL := fs.String("url", "http://localhost:8080", "carbon server to load")
+	apiKey := fs.String("api-key", os.Getenv("CARBON_API_KEY"), "API key sent as a bearer token")
+	conversations := fs.Int("conversations", 20, "conversations to ingest, each in its own group")
+	turns := fs.Int("turns", 4, fmt.Sprintf("user turns per conversation, up to %d; each adds a user and an assistant message", len(turnTemplates)))
+	rate := fs.Float64("rate", 5, "requests per second across all conversations")
+	concurrency := fs.Int("concurrency", 4, "conversations ingested at once")
+	seed := fs.Uint64("seed", uint64(time.Now().UnixNano()), "seed of the generated data, to repeat a run")
+	fs.Parse(args)
+	if *conversations < 1 || *turns < 1 || *rate <= 0 || *concurrency < 1 {
+		log.Fatal("loadtest needs --conversations, --turns, --rate and --concurrency above 0")
+	}
+
+	c := client.New(*baseURL)
+	c.APIKey = *apiKey
+	rng := rand.New(rand.NewPCG(*seed, 0))
+	run := fmt.Sprintf("loadtest-%d", time.Now().Unix())
+	work := make(chan conversation)
+	go func() {
+		defer close(work)
+		for i := range *conversations {
+			work <- newConversation(rng, fmt.Sprintf("%s-%d", run, i), *turns)
+		}
+	}()
+
+	ctx := context.Background()
+	tick := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer tick.Stop()
+	var mu sync.Mutex
+	stats := map[string]*loadStats{"ingest": {}, "search": {}}
+	call := func(kind string, request func() error) {
+		<-tick.C
+		start := time.Now()
+		err := request()
+		mu.Lock()
+		defer mu.Unlock()
+		s := stats[kind]
+		s.latencies = append(s.latencies, time.Since(start))
+		if err != nil {
+			if s.errors == nil {
+				s.errors = make(map[string]int)
+			}
+			s.errors[errorKind(err)]++
+		}
+	}
+
+	log.Printf("Ingesting %d conversations into groups %s-* at %g requests/s (seed %d)", *conversations, run, *rate, *seed)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range *concurrency {
+		wg.Go(func() {
+			for conv := range work {
+				for _, msg := range conv.turns {
+					call("ingest", func() error {
+						_, err := c.AddMessages(ctx, &api.AddMessageRequest{GroupID: conv.groupID, Messages: []api.Message{msg}})
+						return err
+					})
+				}
+				call("search", func() error {
+					_, err := c.Search(ctx, &api.SearchRequest{GroupID: conv.groupID, Query: conv.query})
+					return err
+				})
+			}
+		})
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	requests := len(stats["ingest"].latencies) + len(stats["search"].latencies)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(map[string]interface{}{
+		"run":            run,
+		"seed":           *seed,
+		"duration_s":     elapsed.Seconds(),
+		"requests_per_s": float64(requests) / elapsed.Seconds(),
+		"ingest":         stats["ingest"].report(),
+		"search":         stats["search"].report(),
+	})
+}
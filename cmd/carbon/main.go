@@ -0,0 +1,111 @@
+// Command carbon manages the snapshots of the configured [backup] store and
+// runs graph maintenance tasks against the configured backend.
+//
+//	go run ./cmd/carbon backup [--group <group_id>]
+//	go run ./cmd/carbon backups [--group <group_id>]
+//	go run ./cmd/carbon restore --snapshot <id|latest> --group <group_id>
+//	go run ./cmd/carbon restore --snapshot <id|latest> --graph
+//	go run ./cmd/carbon maintenance consistency [--group <group_id>] [--regenerate]
+//	go run ./cmd/carbon maintenance dedupe-edges [--group <group_id>] [--dry-run]
+//	go run ./cmd/carbon maintenance orphans [--group <group_id>] [--mode quarantine|delete] [--dry-run]
+//	go run ./cmd/carbon maintenance compact [--group <group_id>] [--dry-run]
+//	go run ./cmd/carbon maintenance reembed [--group <group_id>] [--model <name>] [--dry-run]
+//	go run ./cmd/carbon loadtest [--url <url>] [--conversations 20] [--rate 5]
+//
+// Without --group, backup snapshots as the [backup] scope says (including
+// retention) and backups lists every snapshot. restore wipes the group, or with
+// --graph the whole graph, and re-imports the snapshot after snapshotting the
+// current state. maintenance runs its task for every group unless --group
+// names one.
+//
+// loadtest doesn't touch the store: it pushes synthetic conversations through
+// the HTTP API of a running server and reports the latency and error
+// distribution of its requests.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/server"
+	"github.com/joho/godotenv"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: carbon <command> [flags]\n\ncommands:\n")
+	fmt.Fprintf(os.Stderr, "  backup       snapshot a group, or the graph as [backup] scope says\n")
+	fmt.Fprintf(os.Stderr, "  backups      list snapshots, newest first\n")
+	fmt.Fprintf(os.Stderr, "  restore      replace a group (or the whole graph) with a snapshot\n")
+	fmt.Fprintf(os.Stderr, "  maintenance  run a graph maintenance task (consistency, dedupe-edges, orphans, compact, reembed)\n")
+	fmt.Fprintf(os.Stderr, "  loadtest     ingest synthetic conversations into a running server and report latencies\n")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	_ = godotenv.Load()
+
+	command, args := os.Args[1], os.Args[2:]
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	groupID := fs.String("group", "", "group to back up, list or restore")
+
+	var run func(ctx context.Context, g *core.Graphiti) (interface{}, error)
+	switch command {
+	case "backup":
+		run = func(ctx context.Context, g *core.Graphiti) (interface{}, error) {
+			if *groupID == "" {
+				return g.RunBackup(ctx)
+			}
+			info, err := g.Backup(ctx, *groupID)
+			if err != nil {
+				return nil, err
+			}
+			return []model.BackupInfo{*info}, nil
+		}
+	case "backups":
+		run = func(ctx context.Context, g *core.Graphiti) (interface{}, error) {
+			return g.ListBackups(ctx, *groupID)
+		}
+	case "restore":
+		snapshot := fs.String("snapshot", "", `backup ID, or "latest"`)
+		graph := fs.Bool("graph", false, "replace the whole graph from a whole-graph snapshot")
+		run = func(ctx context.Context, g *core.Graphiti) (interface{}, error) {
+			if *snapshot == "" || (*groupID == "") == !*graph {
+				return nil, fmt.Errorf("restore needs --snapshot and either --group or --graph")
+			}
+			return g.RestoreBackup(ctx, *groupID, *snapshot)
+		}
+	case "maintenance":
+		runMaintenance(args)
+		return
+	case "loadtest":
+		runLoadTest(args)
+		return
+	default:
+		usage()
+	}
+	fs.Parse(args)
+
+	ctx := context.Background()
+	g, err := server.NewEngine(server.LoadConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer g.Driver.Close(ctx)
+
+	result, err := run(ctx, g)
+	if err != nil {
+		log.Fatalf("%s failed: %v", command, err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(result)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/server"
+)
+
+func maintenanceUsage() {
+	fmt.Fprintf(os.Stderr, "usage: carbon maintenance <task> [--group <group_id>] [flags]\n\ntasks:\n")
+	fmt.Fprintf(os.Stderr, "  consistency   flag entity summaries that contradict their valid facts\n")
+	fmt.Fprintf(os.Stderr, "  dedupe-edges  merge duplicate RELATES_TO edges\n")
+	fmt.Fprintf(os.Stderr, "  orphans       quarantine or delete entities with no mentions and no valid facts\n")
+	fmt.Fprintf(os.Stderr, "  compact       replace runs of old episodes with digest episodes\n")
+	fmt.Fprintf(os.Stderr, "  reembed       re-embed entities, communities and facts stored with another embedding model\n")
+	os.Exit(2)
+}
+
+// runMaintenance runs a maintenance task for --group, or for every group
+// without it, printing one report per group.
+func runMaintenance(args []string) {
+	if len(args) < 1 {
+		maintenanceUsage()
+	}
+	task, args := args[0], args[1:]
+	fs := flag.NewFlagSet("maintenance "+task, flag.ExitOnError)
+	groupID := fs.String("group", "", "group to process (default: all groups)")
+
+	var run func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error)
+	switch task {
+	case "consistency":
+		regenerate := fs.Bool("regenerate", false, "rebuild summaries flagged as inconsistent")
+		run = func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error) {
+			return g.CheckSummaryConsistency(ctx, groupID, *regenerate)
+		}
+	case "dedupe-edges":
+		dryRun := fs.Bool("dry-run", false, "report duplicates without merging them")
+		run = func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error) {
+			return g.DedupeEdges(ctx, groupID, *dryRun)
+		}
+	case "orphans":
+		mode := fs.String("mode", "", "quarantine or delete (default: configured mode)")
+		dryRun := fs.Bool("dry-run", false, "list orphans without collecting them")
+		run = func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error) {
+			return g.CollectOrphans(ctx, groupID, *mode, *dryRun)
+		}
+	case "compact":
+		dryRun := fs.Bool("dry-run", false, "list the runs of episodes without digesting them")
+		run = func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error) {
+			return g.CompactEpisodes(ctx, groupID, *dryRun)
+		}
+	case "reembed":
+		model := fs.String("model", "", "configured embedding model to migrate to (default: the active one)")
+		dryRun := fs.Bool("dry-run", false, "count stale embeddings without re-embedding them")
+		run = func(ctx context.Context, g *core.Graphiti, groupID string) (interface{}, error) {
+			return g.Reembed(ctx, groupID, *model, *dryRun)
+		}
+	default:
+		maintenanceUsage()
+	}
+	fs.Parse(args)
+
+	ctx := context.Background()
+	g, err := server.NewEngine(server.LoadConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer g.Driver.Close(ctx)
+
+	groups := []string{*groupID}
+	if *groupID == "" {
+		all, err := g.ListGroups(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		groups = groups[:0]
+		for _, group := range all {
+			groups = append(groups, group.GroupID)
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	failed := false
+	for _, group := range groups {
+		report, err := run(ctx, g, group)
+		if err != nil {
+			log.Printf("%s failed for group %s: %v", task, group, err)
+			failed = true
+			continue
+		}
+		enc.Encode(report)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/agenthands/carbon/internal/server"
 )
 
+// shutdownTimeout bounds draining requests and flushing queued work on exit.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using defaults")
@@ -19,10 +28,43 @@ func main() {
 	}
 
 	srv := server.NewServer()
-	r := srv.SetupRouter()
+	listeners := []*http.Server{{Addr: ":" + port, Handler: srv.SetupRouter()}}
+
+	// Optional second listener speaking the Python Graphiti service's REST API
+	if compatPort := os.Getenv("GRAPHITI_COMPAT_PORT"); compatPort != "" {
+		listeners = append(listeners, &http.Server{Addr: ":" + compatPort, Handler: srv.SetupGraphitiRouter()})
+		log.Printf("Starting Graphiti-compatible API on port %s", compatPort)
+	}
+
+	// Optional listener serving MCP clients
+	if mcpPort := os.Getenv("MCP_PORT"); mcpPort != "" {
+		listeners = append(listeners, &http.Server{Addr: ":" + mcpPort, Handler: srv.SetupMCPRouter()})
+		log.Printf("Starting MCP server on port %s", mcpPort)
+	}
 
 	log.Printf("Starting server on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal(err)
+	for _, l := range listeners {
+		go func() {
+			if err := l.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, l := range listeners {
+		if err := l.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to drain listener %s: %v", l.Addr, err)
+		}
+	}
+	if err := srv.Close(shutdownCtx); err != nil {
+		log.Printf("Shutdown errors: %v", err)
 	}
 }
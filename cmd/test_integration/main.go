@@ -1,13 +1,14 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"time"
+
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/agenthands/carbon/pkg/client"
 )
 
 const (
@@ -20,26 +21,25 @@ func main() {
 
 	fmt.Println("Starting Integration Test...")
 
+	ctx := context.Background()
+	c := client.New(baseURL)
 	groupID := "test-group-" + fmt.Sprintf("%d", time.Now().Unix())
 
 	// 1. Ingest Messages
 	fmt.Println("1. Ingesting Messages...")
-	messages := []map[string]string{
-		{"role": "user", "content": "My name is Alice and I am a software engineer."},
-		{"role": "assistant", "content": "Nice to meet you, Alice."},
-		{"role": "user", "content": "I live in San Francisco and love hiking."},
-	}
-	
-	payload := map[string]interface{}{
-		"group_id": groupID,
-		"messages": messages,
-	}
-	
-	validIngest := sendRequest("POST", "/messages", payload)
-	if !validIngest {
-		fmt.Println("FAILED: Ingest messages")
+	status, err := c.AddMessages(ctx, &api.AddMessageRequest{
+		GroupID: groupID,
+		Messages: []api.Message{
+			{Role: "user", Content: "My name is Alice and I am a software engineer."},
+			{Role: "assistant", Content: "Nice to meet you, Alice."},
+			{Role: "user", Content: "I live in San Francisco and love hiking."},
+		},
+	})
+	if err != nil {
+		fmt.Printf("FAILED: Ingest messages: %v\n", err)
 		os.Exit(1)
 	}
+	printResponse(status)
 	fmt.Println("PASSED: Ingest messages")
 
 	// Allow some time for async processing if any (currently synchronous)
@@ -47,49 +47,16 @@ func main() {
 
 	// 2. Search
 	fmt.Println("2. Searching Graph...")
-	searchPayload := map[string]string{
-		"group_id": groupID,
-		"query":    "Alice",
-	}
-	
-	validSearch := sendRequest("POST", "/search", searchPayload)
-	if !validSearch {
-		fmt.Println("FAILED: Search")
+	results, err := c.Search(ctx, &api.SearchRequest{GroupID: groupID, Query: "Alice"})
+	if err != nil {
+		fmt.Printf("FAILED: Search: %v\n", err)
 		os.Exit(1)
 	}
+	printResponse(results)
 	fmt.Println("PASSED: Search")
 }
 
-func sendRequest(method, endpoint string, payload interface{}) bool {
-	var body io.Reader
-	if payload != nil {
-		jsonBytes, _ := json.Marshal(payload)
-		body = bytes.NewBuffer(jsonBytes)
-	}
-
-	req, err := http.NewRequest(method, baseURL+endpoint, body)
-	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return false
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending request: %v\n", err)
-		return false
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		fmt.Printf("Request failed with status %d: %s\n", resp.StatusCode, string(respBody))
-		return false
-	}
-	
-	respBody, _ := io.ReadAll(resp.Body)
-	fmt.Printf("Response: %s\n", string(respBody))
-
-	return true
+func printResponse(resp interface{}) {
+	data, _ := json.Marshal(resp)
+	fmt.Printf("Response: %s\n", string(data))
 }
@@ -0,0 +1,348 @@
+// Package apigen generates the Go and TypeScript HTTP clients from api.Routes.
+// Run it with `go generate ./pkg/client`.
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+const header = "Code generated by cmd/apigen from pkg/api. DO NOT EDIT."
+
+// operation is a non-streaming route prepared for the templates.
+type operation struct {
+	api.Route
+	Params    []string // Path parameter names
+	Query     []queryField
+	QueryType reflect.Type
+	Request   reflect.Type
+	Result    reflect.Type
+}
+
+type queryField struct {
+	Name     string // Query parameter name
+	Field    string // Go field name
+	Type     reflect.Type
+	Required bool
+}
+
+func operations() ([]operation, error) {
+	var ops []operation
+	for _, r := range api.Routes {
+		if r.Stream {
+			continue // Hand-written in each client
+		}
+		op := operation{Route: r, Params: api.PathParams(r.Path), Result: reflect.TypeOf(r.Response)}
+		if r.Request != nil {
+			op.Request = reflect.TypeOf(r.Request)
+		}
+		if r.Query != nil {
+			op.QueryType = reflect.TypeOf(r.Query)
+			for _, f := range reflect.VisibleFields(reflect.TypeOf(r.Query)) {
+				name := f.Tag.Get("query")
+				if name == "" {
+					continue
+				}
+				switch t := f.Type; {
+				case t.Kind() == reflect.String, t.Kind() == reflect.Int,
+					t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Int:
+				default:
+					return nil, fmt.Errorf("%s: unsupported query parameter type %s", r.Name, t)
+				}
+				op.Query = append(op.Query, queryField{
+					Name: name, Field: f.Name, Type: f.Type,
+					Required: strings.Contains(f.Tag.Get("binding"), "required"),
+				})
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// Go returns the source of pkg/client/client_gen.go.
+func Go() ([]byte, error) {
+	ops, err := operations()
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := goTemplate.Execute(&body, ops); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\npackage client\n\nimport (\n", header)
+	for _, group := range [][]struct{ path, use string }{
+		{{"context", "context."}, {"net/url", "url."}, {"strconv", "strconv."}},
+		{{"github.com/agenthands/carbon/internal/core/model", "model."}, {"github.com/agenthands/carbon/pkg/api", "api."}},
+	} {
+		buf.WriteString("\n")
+		for _, imp := range group {
+			if bytes.Contains(body.Bytes(), []byte(imp.use)) {
+				fmt.Fprintf(&buf, "\t%q\n", imp.path)
+			}
+		}
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go client: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"goType":    goType,
+	"goParam":   goParam,
+	"goPath":    goPath,
+	"queryExpr": queryExpr,
+}).Parse(`{{range .}}
+// {{.Name}} calls {{.Method}} {{.Path}}. {{.Summary}}
+func (c *Client) {{.Name}}(ctx context.Context{{range .Params}}, {{goParam .}} string{{end}}{{if .Query}}, q {{goType .QueryType}}{{end}}{{if .Request}}, req *{{goType .Request}}{{end}}) (*{{goType .Result}}, error) {
+	{{- if .Query}}
+	query := url.Values{}
+	{{- range .Query}}
+	{{queryExpr .}}
+	{{- end}}
+	{{- end}}
+	var resp {{goType .Result}}
+	if err := c.do(ctx, "{{.Method}}", {{goPath .Path}}, {{if .Query}}query{{else}}nil{{end}}, {{if .Request}}req{{else}}nil{{end}}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+{{end}}`))
+
+// goType names t as seen from package client.
+func goType(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// goParam turns a path parameter name into a Go identifier ("group_id" -> "groupID").
+func goParam(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "id" || parts[i] == "uuid" {
+			parts[i] = strings.ToUpper(parts[i])
+		} else if parts[i] != "" {
+			parts[i] = string(unicode.ToUpper(rune(parts[i][0]))) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// goPath builds a Go expression for a gin path with escaped parameters.
+func goPath(p string) string {
+	var parts []string
+	literal := ""
+	for _, s := range strings.Split(p, "/")[1:] {
+		literal += "/"
+		if strings.HasPrefix(s, ":") {
+			parts = append(parts, fmt.Sprintf("%q", literal), "url.PathEscape("+goParam(s[1:])+")")
+			literal = ""
+			continue
+		}
+		literal += s
+	}
+	if literal != "" {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func queryExpr(f queryField) string {
+	switch f.Type.Kind() {
+	case reflect.Pointer:
+		return fmt.Sprintf("if q.%s != nil {\n\t\tquery.Set(%q, strconv.Itoa(*q.%s))\n\t}", f.Field, f.Name, f.Field)
+	case reflect.Int:
+		return fmt.Sprintf("if q.%s != 0 {\n\t\tquery.Set(%q, strconv.Itoa(q.%s))\n\t}", f.Field, f.Name, f.Field)
+	default:
+		return fmt.Sprintf("if q.%s != \"\" {\n\t\tquery.Set(%q, q.%s)\n\t}", f.Field, f.Name, f.Field)
+	}
+}
+
+// TypeScript returns the source of clients/typescript/carbon.ts.
+func TypeScript() ([]byte, error) {
+	ops, err := operations()
+	if err != nil {
+		return nil, err
+	}
+
+	// Interfaces for every named struct reachable from the routes, sorted by name
+	types := make(map[string]reflect.Type)
+	requests := make(map[string]bool) // Types sent by the client: fields are optional unless required
+	var collect func(t reflect.Type, request bool)
+	collect = func(t reflect.Type, request bool) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			collect(t.Elem(), request)
+		case reflect.Struct:
+			if t == timeType || types[t.Name()] != nil && (requests[t.Name()] || !request) {
+				return
+			}
+			types[t.Name()] = t
+			requests[t.Name()] = requests[t.Name()] || request
+			for _, f := range api.JSONFields(t) {
+				collect(f.Field.Type, request)
+			}
+		}
+	}
+	for _, r := range api.Routes {
+		if r.Request != nil {
+			collect(reflect.TypeOf(r.Request), true)
+		}
+		collect(reflect.TypeOf(r.Response), false)
+	}
+	collect(reflect.TypeOf(api.ErrorResponse{}), false)
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\n", header)
+	for _, name := range names {
+		fmt.Fprintf(&buf, "export interface %s {\n", name)
+		for _, f := range api.JSONFields(types[name]) {
+			optional := ""
+			required := !f.OmitEmpty && f.Field.Type.Kind() != reflect.Pointer
+			if requests[name] {
+				required = strings.Contains(f.Field.Tag.Get("binding"), "required")
+			}
+			if !required {
+				optional = "?"
+			}
+			fmt.Fprintf(&buf, "  %s%s: %s;\n", f.Name, optional, tsType(f.Field.Type))
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(tsClientPrelude)
+	for _, op := range ops {
+		var args []string
+		for _, p := range op.Params {
+			args = append(args, goParam(p)+": string")
+		}
+		if len(op.Query) > 0 {
+			var fields []string
+			for _, q := range op.Query {
+				optional := "?"
+				if q.Required {
+					optional = ""
+				}
+				fields = append(fields, fmt.Sprintf("%s%s: %s", q.Name, optional, tsType(q.Type)))
+			}
+			args = append(args, "query: { "+strings.Join(fields, "; ")+" }")
+		}
+		if op.Request != nil {
+			args = append(args, "req: "+op.Request.Name())
+		}
+		tsPath := "`" + api.OpenAPIPath(op.Path) + "`"
+		for _, p := range op.Params {
+			tsPath = strings.Replace(tsPath, "{"+p+"}", "${encodeURIComponent("+goParam(p)+")}", 1)
+		}
+		query, body := "undefined", "undefined"
+		if len(op.Query) > 0 {
+			query = "query"
+		}
+		if op.Request != nil {
+			body = "req"
+		}
+		fmt.Fprintf(&buf, "\n  /** %s %s. %s */\n", op.Method, op.Path, op.Summary)
+		fmt.Fprintf(&buf, "  %s(%s): Promise<%s> {\n", lowerFirst(op.Name), strings.Join(args, ", "), op.Result.Name())
+		fmt.Fprintf(&buf, "    return this.request(%q, %s, %s, %s);\n  }\n", op.Method, tsPath, query, body)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+const tsClientPrelude = `/** Error returned for non-2xx responses. fields lists the offending fields of an invalid request. */
+export class CarbonError extends Error {
+  constructor(
+    public readonly status: number,
+    message: string,
+    public readonly fields: FieldError[] = [],
+  ) {
+    super(message);
+  }
+}
+
+/** Typed client for the carbon HTTP API. Streaming ingest (POST /bulk/messages/stream) is not covered. */
+export class CarbonClient {
+  constructor(
+    private readonly baseURL: string,
+    private readonly fetchImpl: typeof fetch = fetch,
+    private readonly apiKey?: string,
+  ) {}
+
+  private async request<T>(
+    method: string,
+    path: string,
+    query?: Record<string, string | number | undefined>,
+    body?: unknown,
+  ): Promise<T> {
+    const url = new URL(this.baseURL.replace(/\/$/, "") + path);
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers: Record<string, string> = {};
+    if (body !== undefined) headers["Content-Type"] = "application/json";
+    if (this.apiKey) headers["Authorization"] = ` + "`Bearer ${this.apiKey}`" + `;
+    const resp = await this.fetchImpl(url.toString(), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const data = await resp.json().catch(() => ({}));
+    if (!resp.ok) {
+      const err = data as ErrorResponse;
+      throw new CarbonError(resp.status, err.error ?? resp.statusText, err.fields);
+    }
+    return data as T;
+  }
+`
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func tsType(t reflect.Type) string {
+	if t == timeType {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return tsType(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return tsType(t.Elem()) + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsType(t.Elem()) + ">"
+	case reflect.Struct:
+		return t.Name()
+	default:
+		return "unknown"
+	}
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
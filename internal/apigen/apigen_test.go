@@ -0,0 +1,28 @@
+package apigen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedClientsUpToDate(t *testing.T) {
+	for path, gen := range map[string]func() ([]byte, error){
+		"../../pkg/client/client_gen.go":     Go,
+		"../../clients/typescript/carbon.ts": TypeScript,
+	} {
+		want, err := gen()
+		require.NoError(t, err)
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s is stale; run go generate ./pkg/client", path)
+	}
+}
+
+func TestGoPath(t *testing.T) {
+	assert.Equal(t, `"/groups"`, goPath("/groups"))
+	assert.Equal(t, `"/groups/" + url.PathEscape(id) + "/stats"`, goPath("/groups/:id/stats"))
+	assert.Equal(t, `"/maintenance/orphans/" + url.PathEscape(groupID)`, goPath("/maintenance/orphans/:group_id"))
+}
@@ -0,0 +1,70 @@
+// Package blob stores opaque objects, such as graph snapshots, on local disk or
+// in an S3-compatible bucket.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Store is a flat key/value object store. Keys use "/" as separator.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrNotFound when key doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete succeeds when key doesn't exist.
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose key starts with prefix, sorted by key.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// New opens the store described by cfg.
+func New(cfg config.BlobStoreConfig) (Store, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "file":
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("blob store dir is not set")
+		}
+		return NewFileStore(cfg.Dir), nil
+	case "s3":
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("blob store bucket is not set")
+		}
+		accessEnv, secretEnv := cfg.AccessKeyEnv, cfg.SecretKeyEnv
+		if accessEnv == "" {
+			accessEnv = "AWS_ACCESS_KEY_ID"
+		}
+		if secretEnv == "" {
+			secretEnv = "AWS_SECRET_ACCESS_KEY"
+		}
+		s := &S3Store{
+			Endpoint:     cfg.Endpoint,
+			Region:       cfg.Region,
+			Bucket:       cfg.Bucket,
+			Prefix:       cfg.Prefix,
+			AccessKey:    os.Getenv(accessEnv),
+			SecretKey:    os.Getenv(secretEnv),
+			SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if s.AccessKey == "" || s.SecretKey == "" {
+			return nil, fmt.Errorf("blob store credentials: %s and %s must be set", accessEnv, secretEnv)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported blob store type: %s", cfg.Type)
+	}
+}
@@ -9,18 +9,52 @@ import (
 
 type ExtractionPrompts struct {
 	Nodes string `toml:"nodes"`
+	// Edges takes the node list and the episode content.
 	Edges string `toml:"edges"`
+	// Context, when set, is put before the nodes and edges prompts with the
+	// previous episodes (one per line) as its %s, giving extraction the
+	// conversation so far.
+	Context string `toml:"context"`
+	// Verify takes the numbered extracted facts and the episode content and
+	// asks which facts the content supports ([ingest] verify_facts).
+	Verify string `toml:"verify"`
+	// Coreference takes the previous episodes and the episode content and
+	// rewrites the content with references resolved ([ingest] resolve_coreferences).
+	Coreference string `toml:"coreference"`
+	// Query lists the entities a search query mentions, for entity linking.
+	Query string `toml:"query"`
+	// Hypothetical writes a passage answering a search query, whose embedding
+	// is searched instead of the query's ([search] hyde).
+	Hypothetical string `toml:"hypothetical"`
+	// Topics takes the allowed topics (one per line, or none) and the episode
+	// content and lists the content's topics ([ingest] topics = "llm").
+	Topics string `toml:"topics"`
+	// Sentiment takes the user's name and a fact about them and asks how the
+	// user feels about it ([ingest] sentiment).
+	Sentiment string `toml:"sentiment"`
 }
 
 type DeduplicationPrompts struct {
 	Nodes string `toml:"nodes"`
 	Edges string `toml:"edges"`
+	// Contradictions takes an entity's valid facts and lists the pairs that
+	// can't both be true, for the contradiction scan.
+	Contradictions string `toml:"contradictions"`
 }
 
 type SummaryPrompts struct {
 	Nodes         string `toml:"nodes"`
 	Communities   string `toml:"communities"`
 	CommunityName string `toml:"community_name"`
+	Consistency   string `toml:"consistency"`
+	Path          string `toml:"path"`
+	Episodes      string `toml:"episodes"`
+	// Profile takes the group's most salient entities and facts and writes
+	// the user profile of POST /profile.
+	Profile string `toml:"profile"`
+	// GapQuestions takes key entities with what their checklists say is
+	// missing and writes questions an agent could ask to fill the gaps.
+	GapQuestions string `toml:"gap_questions"`
 }
 
 type LLMConfig struct {
@@ -29,26 +63,492 @@ type LLMConfig struct {
 	EmbeddingModel string `toml:"embedding_model"`
 	APIKey         string `toml:"api_key"`
 	BaseURL        string `toml:"base_url"`
+	// ReplayMode is "record" to capture prompts/responses to ReplayDir, or
+	// "replay" to serve them back without contacting the provider.
+	ReplayMode string `toml:"replay_mode"`
+	ReplayDir  string `toml:"replay_dir"`
+	// Breaker fast-fails LLM calls while the provider keeps failing.
+	Breaker BreakerConfig `toml:"breaker"`
+	// ContextTokens is the model's context window. Prompts are measured before
+	// each call and their optional context (previous episodes, node lists) is
+	// shrunk to leave ResponseTokens free. 0 falls back to options.num_ctx;
+	// without either, prompts are sent as built.
+	ContextTokens int `toml:"context_tokens"`
+	// ResponseTokens is the room kept for the answer. Default 1024.
+	ResponseTokens int `toml:"response_tokens"`
+	// Options tune generation for every operation; Operations override them
+	// per operation ("extraction", "deduplication", "summary", "rerank").
+	// Only the ollama provider applies them.
+	Options    LLMOptions            `toml:"options"`
+	Operations map[string]LLMOptions `toml:"operations"`
+}
+
+type LLMOptions struct {
+	// KeepAlive is how long Ollama keeps the model loaded after a call, e.g.
+	// "10m"; "-1" keeps it loaded. Empty uses the server default.
+	KeepAlive string `toml:"keep_alive"`
+	// NumCtx is the context window in tokens. 0 uses the model default.
+	NumCtx int `toml:"num_ctx"`
+	// Temperature is the sampling temperature. Unset uses the model default.
+	Temperature *float64 `toml:"temperature"`
+}
+
+type BreakerConfig struct {
+	// FailureRate (0-1] opens the breaker once this share of the calls in the
+	// current window failed. 0 disables the breaker.
+	FailureRate float64 `toml:"failure_rate"`
+	// MinRequests is how many calls a window needs before it can trip the breaker. Default 10.
+	MinRequests int `toml:"min_requests"`
+	// WindowSeconds is the length of the window failures are counted over. Default 60.
+	WindowSeconds int `toml:"window_seconds"`
+	// CooldownSeconds is how long the breaker stays open before probing the provider. Default 30.
+	CooldownSeconds int `toml:"cooldown_seconds"`
+	// HalfOpenProbes is how many probe calls must succeed to close the breaker
+	// again; only that many run at once while half-open. Default 1.
+	HalfOpenProbes int `toml:"half_open_probes"`
 }
 
 type MemgraphConfig struct {
 	URI      string `toml:"uri"`
 	User     string `toml:"user"`
 	Password string `toml:"password"`
+	// Database selects a database on a multi-tenant instance. Empty uses the default one.
+	Database string `toml:"database"`
+}
+
+type GraphConfig struct {
+	// Backend selects the GraphDriver: "memgraph" (default), "memory" or "sqlite".
+	Backend string `toml:"backend"`
+	// Path is the database file used by the sqlite backend.
+	Path string `toml:"path"`
+	// ReadURI routes read-only queries (search, group reads) to a replica. Empty uses the writer.
+	ReadURI string `toml:"read_uri"`
+	// Retry reruns Memgraph queries that fail with transient errors.
+	Retry GraphRetryConfig `toml:"retry"`
+	// Routes keep the groups whose group_id starts with a prefix in their own
+	// Memgraph database or instance. Other groups stay on [memgraph].
+	Routes []GraphRouteConfig `toml:"routes"`
+	// Shards spread groups over several Memgraph databases or instances by
+	// hashing their group_id, in place of [memgraph].
+	Shards []GraphShardConfig `toml:"shards"`
+}
+
+type GraphShardConfig struct {
+	// Name decides which groups the shard holds; renaming a shard moves them.
+	// Default "shard-<index>".
+	Name string `toml:"name"`
+	// URI, ReadURI, Database, User and Password connect to the shard as in
+	// [[graph.routes]].
+	URI      string `toml:"uri"`
+	ReadURI  string `toml:"read_uri"`
+	Database string `toml:"database"`
+	User     string `toml:"user"`
+	Password string `toml:"password"`
+}
+
+type GraphRouteConfig struct {
+	// Prefix selects the groups routed here; the longest matching prefix wins.
+	Prefix string `toml:"prefix"`
+	// URI of the Memgraph instance. Empty uses [memgraph] uri.
+	URI string `toml:"uri"`
+	// ReadURI is a read replica for the routed groups. Empty uses the writer,
+	// or [graph] read_uri when URI is empty too.
+	ReadURI string `toml:"read_uri"`
+	// Database on the instance. Empty uses [memgraph] database.
+	Database string `toml:"database"`
+	// User and Password default to those of [memgraph].
+	User     string `toml:"user"`
+	Password string `toml:"password"`
+}
+
+type GraphRetryConfig struct {
+	// MaxAttempts is how many times a query runs before a transient error is
+	// returned. Default 3; 1 disables retries.
+	MaxAttempts int `toml:"max_attempts"`
+	// InitialBackoffMs is the wait before the first retry, doubled for each
+	// further one and jittered. Default 100.
+	InitialBackoffMs int `toml:"initial_backoff_ms"`
+	// MaxBackoffMs caps the wait between retries. Default 5000.
+	MaxBackoffMs int `toml:"max_backoff_ms"`
 }
 
 type ConcurrencyConfig struct {
+	// BulkIngest bounds the episodes bulk ingestion works on at once, across requests. Default 2.
 	BulkIngest int `toml:"bulk_ingest"`
+	// BulkSearch bounds the queries bulk searches run at once, across requests. Default 5.
 	BulkSearch int `toml:"bulk_search"`
+	// ExtractionWorkers bounds the episodes in LLM-bound processing at once,
+	// across requests. Free workers take turns between groups. Default 8.
+	ExtractionWorkers int `toml:"extraction_workers"`
+	// EdgeWorkers bounds parallel contradiction checks and summaries within one episode.
+	EdgeWorkers int `toml:"edge_workers"`
+}
+
+type SummaryQueueConfig struct {
+	// Enabled moves node summarization off the ingest path into a background worker.
+	Enabled bool `toml:"enabled"`
+	// IntervalMinutes is the minimum time between summary updates of one node;
+	// facts arriving in between are coalesced into the next update.
+	IntervalMinutes int `toml:"interval_minutes"`
+}
+
+type SearchConfig struct {
+	// EntityLinking resolves entities mentioned in a query to graph nodes and
+	// ranks facts about them first. Needs the [extraction] query prompt.
+	EntityLinking bool `toml:"entity_linking"`
+	// LinkThreshold is the minimum name-embedding similarity for a link. Default 0.8.
+	LinkThreshold float64 `toml:"link_threshold"`
+	// Metric scores vector matches: "cosine" (default), "dot" or "euclidean"
+	// (1 / (1 + distance)). Thresholds are in the metric's units.
+	Metric string `toml:"metric"`
+	// MinScore drops vector matches scoring below it. 0 keeps all.
+	MinScore float64 `toml:"min_score"`
+	// ReinforcementWeight ranks facts stated by more episodes higher: a
+	// fact's rank is divided by 1 + weight * ln(mention count). 0 keeps
+	// the retrieval order.
+	ReinforcementWeight float64 `toml:"reinforcement_weight"`
+	// HyDE searches by the embedding of a hypothetical answer to the query,
+	// written with the [extraction] hypothetical prompt, which finds more
+	// facts for abstract queries. Requests may override it.
+	HyDE bool `toml:"hyde"`
+	// RerankDepth reranks only the top candidates, keeping the rest in
+	// retrieval order after them. 0 reranks all.
+	RerankDepth int `toml:"rerank_depth"`
+	// RerankSkipMargin skips reranking when the best vector score exceeds
+	// the second best by at least this much. 0 always reranks.
+	RerankSkipMargin float64 `toml:"rerank_skip_margin"`
+	// RelevanceThreshold is the confidence at which a search result counts
+	// as relevant in the coverage of a response. Default 0.7.
+	RelevanceThreshold float64 `toml:"relevance_threshold"`
+}
+
+type CommunityConfig struct {
+	// CoMentions adds a link between every two entities mentioned by the same
+	// episodes to the graph communities are detected on, weighted by the
+	// number of those episodes, so entities without facts between them still
+	// cluster.
+	CoMentions bool `toml:"co_mentions"`
+	// MinCoMentions is how many episodes must mention two entities together
+	// to link them. Default 2.
+	MinCoMentions int `toml:"min_co_mentions"`
+	// MatchThreshold is the Jaccard overlap of members at which a detected
+	// community continues an existing one, keeping its UUID. Default 0.5.
+	MatchThreshold float64 `toml:"match_threshold"`
+	// StableThreshold is the overlap at or above which a continued community
+	// also keeps its name and summary instead of being summarized again.
+	// Default 0.8.
+	StableThreshold float64 `toml:"stable_threshold"`
+}
+
+type SearchCacheConfig struct {
+	// Enabled caches search results per (group, normalized query, filter) until the group's next ingest.
+	Enabled bool `toml:"enabled"`
+	// Backend is "memory" (default) or "redis".
+	Backend string `toml:"backend"`
+	// TTLSeconds bounds how long a result is served. Default 300.
+	TTLSeconds int `toml:"ttl_seconds"`
+	// MaxEntries bounds the memory backend; the oldest entries are evicted first. Default 1000.
+	MaxEntries int `toml:"max_entries"`
+	// Rerank caches the reranker's orderings per (group, query, candidate
+	// set) in the same backend, whether or not Enabled is set, so searches
+	// retrieving the same candidates skip the reranker until the group's next ingest.
+	Rerank bool `toml:"rerank"`
+
+	RedisAddr     string `toml:"redis_addr"`
+	RedisPassword string `toml:"redis_password"`
+	RedisDB       int    `toml:"redis_db"`
+}
+
+type EmbeddingConfig struct {
+	// Models lists the embedders in priority order. The first is active: it embeds
+	// new entities and facts and is the target of the reembed job. Search also
+	// queries the vectors of the others until they are re-embedded. Empty uses the
+	// [llm] embedder.
+	Models []EmbedderConfig `toml:"models"`
+	// BatchSize is how many vectors the reembed job computes and stages per write. Default 100.
+	BatchSize int `toml:"batch_size"`
+	// RequestsPerSecond caps the reembed job's embedding calls. 0 is unlimited.
+	RequestsPerSecond float64 `toml:"requests_per_second"`
+}
+
+type EmbedderConfig struct {
+	// Name tags every vector this embedder produces (as "<name>@<dimension>"). Defaults to Model.
+	Name     string `toml:"name"`
+	Provider string `toml:"provider"` // "openai", "gemini" or "ollama"
+	Model    string `toml:"model"`
+	APIKey   string `toml:"api_key"`
+	BaseURL  string `toml:"base_url"`
+}
+
+type OrphanGCConfig struct {
+	// Mode is "quarantine" (default) to relabel orphans as QuarantinedEntity or "delete" to remove them.
+	Mode string `toml:"mode"`
+	// GraceMinutes skips entities younger than this, whose episode may still be ingesting. Default 60.
+	GraceMinutes int `toml:"grace_minutes"`
+	// IntervalMinutes runs the collector over every group on a schedule. 0 disables it.
+	IntervalMinutes int `toml:"interval_minutes"`
+}
+
+type CompactionConfig struct {
+	// MinAgeDays only compacts episodes older than this. Default 30.
+	MinAgeDays int `toml:"min_age_days"`
+	// KeepRecent leaves each group's latest episodes alone whatever their age.
+	KeepRecent int `toml:"keep_recent"`
+	// RunSize is how many consecutive episodes one digest replaces. Default 20.
+	RunSize int `toml:"run_size"`
+	// IntervalMinutes compacts every group on a schedule. 0 disables it.
+	IntervalMinutes int `toml:"interval_minutes"`
+}
+
+type ContradictionsConfig struct {
+	// IntervalMinutes scans every group for contradicting valid facts on a
+	// schedule. 0 disables it.
+	IntervalMinutes int `toml:"interval_minutes"`
+}
+
+type IngestConfig struct {
+	// MaxContentChars caps an episode's content in characters. 0 is unlimited.
+	MaxContentChars int `toml:"max_content_chars"`
+	// MaxContentTokens caps an episode's estimated tokens so extraction prompts
+	// stay within the model's context window. 0 is unlimited.
+	MaxContentTokens int `toml:"max_content_tokens"`
+	// Overflow handles content over a limit: "reject" (default), "truncate" to
+	// keep its beginning, or "chunk" to ingest it as consecutive episodes.
+	Overflow string `toml:"overflow"`
+	// ContextEpisodes is how many previous episodes extraction sees. Default 5.
+	ContextEpisodes int `toml:"context_episodes"`
+	// ContextSelection picks them: "recent" (default) takes the latest ones,
+	// "relevant" the ones most similar to the new content by embedding among
+	// the latest ContextCandidates (default 50). Without an embedder it is "recent".
+	ContextSelection  string `toml:"context_selection"`
+	ContextCandidates int    `toml:"context_candidates"`
+	// ResolveCoreferences rewrites each episode with the [extraction]
+	// coreference prompt before extraction, so pronouns and references to
+	// entities of previous episodes ("she", "the company") name them. The
+	// episode is stored as written.
+	ResolveCoreferences bool `toml:"resolve_coreferences"`
+	// NormalizeDates resolves the date expressions of each extracted fact
+	// ("last Tuesday", "in 2019", "next month") against the episode time: the
+	// first one becomes the fact's valid_at and all are kept in its
+	// "temporal" attribute.
+	NormalizeDates bool `toml:"normalize_dates"`
+	// NormalizeQuantities stores extracted entity attributes that are
+	// quantities ("$500", "28-year-old", "5km") as numbers in a canonical unit,
+	// with the unit in "<attribute>_unit", and reads search filter values the
+	// same way.
+	NormalizeQuantities bool `toml:"normalize_quantities"`
+	// VerifyFacts runs the [extraction] verify prompt over each episode's
+	// extracted facts and drops those its content doesn't support. Groups can
+	// override it in their settings.
+	VerifyFacts bool `toml:"verify_facts"`
+	// Duplicates is what happens to an episode that repeats one of the group's
+	// latest DuplicateWindow (default 20) episodes: "ingest" (default) extracts
+	// it again, "skip" drops it and "link" saves it with the mentions and fact
+	// provenance of the episode it repeats, without extraction. Episodes repeat
+	// when their text matches ignoring case and whitespace or, with an
+	// embedder, when their embeddings' cosine similarity reaches
+	// DuplicateThreshold (default 0.97).
+	Duplicates         string  `toml:"duplicates"`
+	DuplicateWindow    int     `toml:"duplicate_window"`
+	DuplicateThreshold float64 `toml:"duplicate_threshold"`
+	// UserEntity gives every group a canonical entity for the person speaking
+	// in its episodes, named UserName (default "User"). Extracted entities for
+	// first-person references ("I", "me", "the user") or that name resolve to
+	// it instead of becoming entities of their own.
+	UserEntity bool   `toml:"user_entity"`
+	UserName   string `toml:"user_name"`
+	// FactLifetimes are the hours, by relation type, that facts of inherently
+	// transient relations (e.g. IS_VISITING) stay current. Their expired_at is
+	// set that long after valid_at, and search leaves them out once it passes.
+	FactLifetimes map[string]int `toml:"fact_lifetimes"`
+	// Topics tags each episode with topics, stored on the episode for topic
+	// filters in episode listings and fact search: "llm" asks the [extraction]
+	// topics prompt, "keywords" tags the TopicKeywords topics whose keywords
+	// the content contains as words. Off when empty. With TopicKeywords set,
+	// "llm" picks among their topics. MaxTopics (default 3) caps the tags.
+	Topics        string              `toml:"topics"`
+	TopicKeywords map[string][]string `toml:"topic_keywords"`
+	MaxTopics     int                 `toml:"max_topics"`
+	// Sentiment annotates each new fact about the group's user entity with
+	// the user's sentiment ("positive", "negative" or "neutral") and emotion
+	// ("likes", "dislikes", "frustrated", ...) from the [extraction] sentiment
+	// prompt, stored as the fact's attributes for search filters.
+	Sentiment bool `toml:"sentiment"`
+}
+
+type GroupLimitsConfig struct {
+	// MaxEntities, MaxEdges and MaxEpisodes cap the size of every group; 0 is
+	// unlimited. Groups override them in their settings.
+	MaxEntities int `toml:"max_entities"`
+	MaxEdges    int `toml:"max_edges"`
+	MaxEpisodes int `toml:"max_episodes"`
+	// OnLimit is what ingesting into a group at a limit does: "reject"
+	// (default) fails the episode, "evict_oldest" deletes the group's oldest
+	// episodes, entities or facts to make room, and "compact" merges duplicate
+	// facts, deletes invalidated ones and orphans and digests old episodes as
+	// [compaction] allows, rejecting the episode if that frees too little.
+	OnLimit string `toml:"on_limit"`
+}
+
+type AccessConfig struct {
+	// Policies guard attributes and relation types behind scopes. Without
+	// policies every caller reads everything.
+	Policies []AccessPolicy `toml:"policies"`
+	// Keys are the API keys callers send as "Authorization: Bearer <key>",
+	// each granting scopes. Callers without a key hold no scopes. Once keys are
+	// configured, /admin endpoints require a key with the "admin" scope.
+	Keys []APIKeyConfig `toml:"keys"`
+}
+
+type AccessPolicy struct {
+	Scope      string   `toml:"scope"`
+	Attributes []string `toml:"attributes"` // Entity and fact attribute names
+	Relations  []string `toml:"relations"`  // Fact relation types, e.g. "DIAGNOSED_WITH"
+}
+
+type APIKeyConfig struct {
+	Key    string   `toml:"key"`
+	Scopes []string `toml:"scopes"`
+}
+
+type EncryptionConfig struct {
+	// Enabled encrypts with AES-256-GCM, using the key in KeyEnv.
+	Enabled bool `toml:"enabled"`
+	// KeyEnv names the environment variable holding the base64-encoded 32-byte key.
+	// Default CARBON_ENCRYPTION_KEY.
+	KeyEnv string `toml:"key_env"`
+	// Attributes lists the entity attributes stored encrypted; "*" encrypts them all.
+	Attributes []string `toml:"attributes"`
+	// EpisodeContent stores episode content, and the episodes of ingest job checkpoints, encrypted.
+	EpisodeContent bool `toml:"episode_content"`
+}
+
+type BackupConfig struct {
+	// IntervalMinutes snapshots the graph on a schedule. 0 disables scheduled backups.
+	IntervalMinutes int `toml:"interval_minutes"`
+	// Scope is "group" (default) for one snapshot per group or "graph" for a
+	// single snapshot of the whole graph.
+	Scope string `toml:"scope"`
+	// Retain keeps this many of the newest snapshots per group. 0 keeps them all.
+	Retain int `toml:"retain"`
+	// MaxAgeDays deletes snapshots older than this. 0 keeps them regardless of age.
+	MaxAgeDays int `toml:"max_age_days"`
+	// Store is where snapshots are written.
+	Store BlobStoreConfig `toml:"store"`
+}
+
+type BlobStoreConfig struct {
+	// Type is "file" (default) or "s3". "s3" also works with S3-compatible
+	// stores such as MinIO or Google Cloud Storage's XML API.
+	Type string `toml:"type"`
+	// Dir is the root directory of a "file" store.
+	Dir string `toml:"dir"`
+	// Endpoint overrides the S3 endpoint, e.g. "https://storage.googleapis.com".
+	// Default "https://s3.<region>.amazonaws.com".
+	Endpoint string `toml:"endpoint"`
+	Region   string `toml:"region"`
+	Bucket   string `toml:"bucket"`
+	// Prefix is prepended to every object key.
+	Prefix string `toml:"prefix"`
+	// AccessKeyEnv and SecretKeyEnv name the environment variables holding the
+	// credentials. Default AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+	AccessKeyEnv string `toml:"access_key_env"`
+	SecretKeyEnv string `toml:"secret_key_env"`
+}
+
+type ContentStoreConfig struct {
+	// ThresholdChars moves episode content longer than this many characters to
+	// Store, leaving only a pointer in the graph. 0 stops offloading; content
+	// offloaded before is still read from Store.
+	ThresholdChars int `toml:"threshold_chars"`
+	// Store holds the offloaded content.
+	Store BlobStoreConfig `toml:"store"`
+}
+
+type ChangesConfig struct {
+	// Enabled appends every entity, episode and fact that is created, updated
+	// or invalidated to its group's change log, read with GET /changes. Each
+	// such write costs one more query.
+	Enabled bool `toml:"enabled"`
+}
+
+type ModerationConfig struct {
+	// Provider checks episodes before they are ingested: "keywords" matches
+	// Keywords, "openai" calls the OpenAI moderation API. Empty disables
+	// moderation.
+	Provider string `toml:"provider"`
+	// Action is what happens to an episode that is flagged, unless its
+	// group's policy says otherwise: "block" (default) rejects it, "flag"
+	// ingests it. Either way an audit record is kept.
+	Action string `toml:"action"`
+	// Keywords lists, per category, the words and phrases that flag an episode.
+	Keywords map[string][]string `toml:"keywords"`
+	// Model is the OpenAI moderation model. Default "omni-moderation-latest".
+	Model string `toml:"model"`
+	// APIKey and BaseURL of the OpenAI API. Default [llm] api_key and base_url.
+	APIKey  string `toml:"api_key"`
+	BaseURL string `toml:"base_url"`
+}
+
+// TransformConfig is a user-defined program that groups can run on what is
+// extracted from their episodes (see the group setting "transforms").
+type TransformConfig struct {
+	// Name is how group settings refer to the transform.
+	Name string `toml:"name"`
+	// Command runs the transform, e.g. ["wasmtime", "run", "normalize.wasm"]
+	// for a WASM module or ["node", "filter.js"]. It is run directly, not
+	// through a shell, with an empty environment, but otherwise with the
+	// server's privileges.
+	Command []string `toml:"command"`
+	// TimeoutMs bounds one run. Default 2000.
+	TimeoutMs int `toml:"timeout_ms"`
+}
+
+type DebugConfig struct {
+	// Enabled serves net/http/pprof under /debug/pprof/ and enables POST
+	// /debug/ingest-profile. Like /admin endpoints, they require a key with the
+	// "admin" scope once API keys are configured.
+	Enabled bool `toml:"enabled"`
+}
+
+type MCPConfig struct {
+	// GroupBinding gives MCP clients a group of their own, used when they
+	// pass no group_id, and keeps them out of every other group: "session"
+	// binds each MCP session to its own group, "key" binds every session of
+	// an API key to the key's group, so a user's memory outlives their
+	// sessions. Empty (default) binds none, and group_id must be passed.
+	GroupBinding string `toml:"group_binding"`
+	// GroupPrefix starts the names of bound groups. Default "mcp-".
+	GroupPrefix string `toml:"group_prefix"`
 }
 
 type Config struct {
-	LLM           LLMConfig            `toml:"llm"`
-	Memgraph      MemgraphConfig       `toml:"memgraph"`
-	Extraction    ExtractionPrompts    `toml:"extraction"`
-	Deduplication DeduplicationPrompts `toml:"deduplication"`
-	Summary       SummaryPrompts       `toml:"summary"`
-	Concurrency   ConcurrencyConfig    `toml:"concurrency"`
+	LLM            LLMConfig            `toml:"llm"`
+	Memgraph       MemgraphConfig       `toml:"memgraph"`
+	Graph          GraphConfig          `toml:"graph"`
+	Extraction     ExtractionPrompts    `toml:"extraction"`
+	Deduplication  DeduplicationPrompts `toml:"deduplication"`
+	Summary        SummaryPrompts       `toml:"summary"`
+	Concurrency    ConcurrencyConfig    `toml:"concurrency"`
+	SummaryQueue   SummaryQueueConfig   `toml:"summary_queue"`
+	OrphanGC       OrphanGCConfig       `toml:"orphan_gc"`
+	Compaction     CompactionConfig     `toml:"compaction"`
+	Contradictions ContradictionsConfig `toml:"contradictions"`
+	Search         SearchConfig         `toml:"search"`
+	Community      CommunityConfig      `toml:"community"`
+	SearchCache    SearchCacheConfig    `toml:"search_cache"`
+	Embedding      EmbeddingConfig      `toml:"embedding"`
+	Ingest         IngestConfig         `toml:"ingest"`
+	GroupLimits    GroupLimitsConfig    `toml:"group_limits"`
+	Access         AccessConfig         `toml:"access"`
+	Encryption     EncryptionConfig     `toml:"encryption"`
+	Backup         BackupConfig         `toml:"backup"`
+	ContentStore   ContentStoreConfig   `toml:"content_store"`
+	Changes        ChangesConfig        `toml:"changes"`
+	Transforms     []TransformConfig    `toml:"transforms"`
+	Moderation     ModerationConfig     `toml:"moderation"`
+	Debug          DebugConfig          `toml:"debug"`
+	MCP            MCPConfig            `toml:"mcp"`
 }
 
 func Load(path string) (*Config, error) {
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+type scopesKey struct{}
+
+// WithScopes limits reads made with the returned context to what scopes grant
+// under the [access] policies: guarded attributes are removed from entities and
+// facts, and facts of guarded relation types are left out. Reads whose context
+// carries no scopes are not restricted.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, append([]string{}, scopes...))
+}
+
+// accessFilter holds the attributes and relation types a caller may not read.
+// A nil filter hides nothing.
+type accessFilter struct {
+	attributes map[string]bool
+	relations  map[string]bool // Normalized relation types
+}
+
+// accessFilter returns what ctx's scopes hide: everything some policy guards
+// that no policy of a held scope grants.
+func (g *Graphiti) accessFilter(ctx context.Context) *accessFilter {
+	scopes, ok := ctx.Value(scopesKey{}).([]string)
+	if !ok || g.Config == nil {
+		return nil
+	}
+	granted := &accessFilter{attributes: map[string]bool{}, relations: map[string]bool{}}
+	for _, p := range g.Config.Access.Policies {
+		if slices.Contains(scopes, p.Scope) {
+			for _, a := range p.Attributes {
+				granted.attributes[a] = true
+			}
+			for _, r := range p.Relations {
+				granted.relations[normalizeRelation(r)] = true
+			}
+		}
+	}
+	f := &accessFilter{attributes: map[string]bool{}, relations: map[string]bool{}}
+	for _, p := range g.Config.Access.Policies {
+		for _, a := range p.Attributes {
+			if !granted.attributes[a] {
+				f.attributes[a] = true
+			}
+		}
+		for _, r := range p.Relations {
+			if r = normalizeRelation(r); !granted.relations[r] {
+				f.relations[r] = true
+			}
+		}
+	}
+	if len(f.attributes) == 0 && len(f.relations) == 0 {
+		return nil
+	}
+	return f
+}
+
+// allows reports whether a fact of relation type rel may be read.
+func (f *accessFilter) allows(rel string) bool {
+	return f == nil || !f.relations[normalizeRelation(rel)]
+}
+
+// edges returns the readable facts, with guarded attributes removed.
+func (f *accessFilter) edges(edges []model.EntityEdge) []model.EntityEdge {
+	if f == nil {
+		return edges
+	}
+	out := make([]model.EntityEdge, 0, len(edges))
+	for _, e := range edges {
+		if f.allows(e.Name) {
+			e.Attributes = f.attrs(e.Attributes)
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// attrs returns a copy of attributes without the guarded ones. The input,
+// which may be shared with the search cache, is never modified.
+func (f *accessFilter) attrs(attributes map[string]interface{}) map[string]interface{} {
+	if f == nil || len(attributes) == 0 {
+		return attributes
+	}
+	out := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		if !f.attributes[k] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// path reports whether every fact of a chain may be read.
+func (f *accessFilter) path(p model.FactPath) bool {
+	for _, e := range p.Edges {
+		if !f.allows(e.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey distinguishes the search cache entries of callers who see different facts.
+func (f *accessFilter) cacheKey() string {
+	if f == nil {
+		return ""
+	}
+	var hidden []string
+	for a := range f.attributes {
+		hidden = append(hidden, "a:"+a)
+	}
+	for r := range f.relations {
+		hidden = append(hidden, "r:"+r)
+	}
+	slices.Sort(hidden)
+	return "|" + strings.Join(hidden, ",")
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessPolicies(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	g.Config.Access.Policies = []config.AccessPolicy{{Scope: "hr", Attributes: []string{"location"}, Relations: []string{"works at"}}}
+	g.SearchCache = NewSearchCache(config.SearchCacheConfig{Enabled: true})
+	anonymous := WithScopes(ctx, nil)
+	hr := WithScopes(ctx, []string{"hr"})
+
+	uuids := func(ctx context.Context, filter *model.SearchFilter) []string {
+		edges, err := g.SearchWithFilter(ctx, "g1", "fact", filter)
+		require.NoError(t, err)
+		return edgeUUIDs(edges)
+	}
+	// In-process reads without scopes are unrestricted; cached results are per caller
+	assert.Equal(t, []string{"e1", "e2", "e3"}, uuids(ctx, nil))
+	assert.Equal(t, []string{"e3"}, uuids(anonymous, nil))
+	assert.Equal(t, []string{"e1", "e2", "e3"}, uuids(hr, nil))
+
+	// Guarded attributes can't be read or probed with a filter
+	alice, err := g.GetEntity(anonymous, "alice")
+	require.NoError(t, err)
+	assert.NotContains(t, alice.Attributes, "location")
+	assert.Empty(t, uuids(anonymous, &model.SearchFilter{Attributes: map[string]interface{}{"location": "Paris"}}))
+	alice, err = g.GetEntity(hr, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", alice.Attributes["location"])
+
+	_, err = g.GetFact(anonymous, "e1")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+	facts, err := g.ListFacts(anonymous, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e3"}, edgeUUIDs(facts))
+
+	gaps, err := g.FindFactGaps(anonymous, "alice", "", model.FactChecklist{Attributes: []string{"location"}, Relations: []string{"WORKS_AT"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"location"}, gaps.MissingAttributes)
+	assert.Equal(t, []string{"WORKS_AT"}, gaps.MissingRelations)
+
+	view, err := g.GetGraphView(anonymous, "g1", "", 0)
+	require.NoError(t, err)
+	assert.Len(t, view.Links, 1)
+}
@@ -0,0 +1,374 @@
+// Package attrschema checks entity attributes against the JSON Schema an
+// ontology declares for their entity type. It supports the keywords that
+// describe attribute values: type, enum, minimum, maximum, minLength,
+// maxLength, pattern, format, items, properties and additionalProperties.
+// Values are coerced to the declared type where that loses nothing: "42" to
+// 42, "$500" to 500, "yes" to true, 7 to "7", a lone value to a one-item array.
+package attrschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/quantity"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	Types                []string
+	Enum                 []interface{}
+	Minimum, Maximum     *float64
+	MinLength, MaxLength *int
+	Pattern              *regexp.Regexp
+	Format               string
+	Items                *Schema
+	Properties           map[string]*Schema
+	AdditionalProperties *bool
+}
+
+// document is a schema as written, before compiling.
+type document struct {
+	Type                 interface{}          `json:"type"`
+	Enum                 []interface{}        `json:"enum"`
+	Minimum              *float64             `json:"minimum"`
+	Maximum              *float64             `json:"maximum"`
+	MinLength            *int                 `json:"minLength"`
+	MaxLength            *int                 `json:"maxLength"`
+	Pattern              string               `json:"pattern"`
+	Format               string               `json:"format"`
+	Items                *document            `json:"items"`
+	Properties           map[string]*document `json:"properties"`
+	AdditionalProperties *bool                `json:"additionalProperties"`
+}
+
+var knownTypes = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true, "null": true}
+
+var knownFormats = map[string]bool{"": true, "date": true, "date-time": true, "email": true}
+
+// Compile parses a JSON Schema given as decoded JSON.
+func Compile(raw map[string]interface{}) (*Schema, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return compile(&doc, "")
+}
+
+func compile(doc *document, path string) (*Schema, error) {
+	s := &Schema{
+		Enum: doc.Enum, Minimum: doc.Minimum, Maximum: doc.Maximum,
+		MinLength: doc.MinLength, MaxLength: doc.MaxLength, Format: doc.Format,
+		AdditionalProperties: doc.AdditionalProperties,
+	}
+	switch t := doc.Type.(type) {
+	case nil:
+	case string:
+		s.Types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%stype must be a string or a list of strings", at(path))
+			}
+			s.Types = append(s.Types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%stype must be a string or a list of strings", at(path))
+	}
+	for _, t := range s.Types {
+		if !knownTypes[t] {
+			return nil, fmt.Errorf("%sunknown type %q", at(path), t)
+		}
+	}
+	if !knownFormats[doc.Format] {
+		return nil, fmt.Errorf("%sunsupported format %q", at(path), doc.Format)
+	}
+	if doc.Pattern != "" {
+		re, err := regexp.Compile(doc.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%sinvalid pattern: %w", at(path), err)
+		}
+		s.Pattern = re
+	}
+	if doc.Items != nil {
+		items, err := compile(doc.Items, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		s.Items = items
+	}
+	for name, prop := range doc.Properties {
+		if prop == nil {
+			continue
+		}
+		compiled, err := compile(prop, join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		s.Properties[name] = compiled
+	}
+	return s, nil
+}
+
+// CheckAttributes validates each attribute the schema's properties declare,
+// and with additionalProperties false rejects the undeclared ones. It returns
+// the attributes with valid values coerced, and the errors of the invalid ones
+// by name; those are left out of valid.
+func (s *Schema) CheckAttributes(attrs map[string]interface{}) (valid map[string]interface{}, invalid map[string]error) {
+	valid = make(map[string]interface{}, len(attrs))
+	for _, name := range sortedKeys(attrs) {
+		prop, declared := s.Properties[name]
+		switch {
+		case declared:
+			v, err := prop.coerce(attrs[name], name)
+			if err != nil {
+				if invalid == nil {
+					invalid = make(map[string]error)
+				}
+				invalid[name] = err
+				continue
+			}
+			valid[name] = v
+		case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+			if invalid == nil {
+				invalid = make(map[string]error)
+			}
+			invalid[name] = fmt.Errorf("%s: not declared by the schema", name)
+		default:
+			valid[name] = attrs[name]
+		}
+	}
+	return valid, invalid
+}
+
+// Coerce validates v, converting it to the schema's type where possible.
+func (s *Schema) Coerce(v interface{}) (interface{}, error) {
+	return s.coerce(v, "")
+}
+
+func (s *Schema) coerce(v interface{}, path string) (interface{}, error) {
+	if len(s.Types) > 0 {
+		var err error
+		for _, t := range s.Types {
+			var coerced interface{}
+			if coerced, err = coerceType(t, v); err == nil {
+				v = coerced
+				break
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s%w", at(path), err)
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return nil, fmt.Errorf("%smust be one of %s", at(path), enumList(s.Enum))
+	}
+	if n, ok := v.(float64); ok {
+		if s.Minimum != nil && n < *s.Minimum {
+			return nil, fmt.Errorf("%smust be at least %v", at(path), *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return nil, fmt.Errorf("%smust be at most %v", at(path), *s.Maximum)
+		}
+	}
+	if str, ok := v.(string); ok {
+		if err := s.checkString(str); err != nil {
+			return nil, fmt.Errorf("%s%w", at(path), err)
+		}
+	}
+	if list, ok := v.([]interface{}); ok && s.Items != nil {
+		coerced := make([]interface{}, len(list))
+		for i, item := range list {
+			var err error
+			if coerced[i], err = s.Items.coerce(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		v = coerced
+	}
+	if obj, ok := v.(map[string]interface{}); ok && (s.Properties != nil || s.AdditionalProperties != nil) {
+		coerced, invalid := s.CheckAttributes(obj)
+		if len(invalid) > 0 {
+			return nil, fmt.Errorf("%s%w", at(path), invalid[sortedKeys(invalid)[0]])
+		}
+		v = coerced
+	}
+	return v, nil
+}
+
+func (s *Schema) checkString(str string) error {
+	n := len([]rune(str))
+	if s.MinLength != nil && n < *s.MinLength {
+		return fmt.Errorf("must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		return fmt.Errorf("must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(str) {
+		return fmt.Errorf("must match %s", s.Pattern)
+	}
+	switch s.Format {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			return fmt.Errorf("must be a date (YYYY-MM-DD)")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			return fmt.Errorf("must be an RFC 3339 date-time")
+		}
+	case "email":
+		if at := strings.IndexByte(str, '@'); at < 1 || at == len(str)-1 || strings.ContainsAny(str, " \t") {
+			return fmt.Errorf("must be an email address")
+		}
+	}
+	return nil
+}
+
+// coerceType converts v to the JSON type t, or explains why it can't.
+func coerceType(t string, v interface{}) (interface{}, error) {
+	switch t {
+	case "null":
+		if v == nil {
+			return nil, nil
+		}
+	case "string":
+		switch x := v.(type) {
+		case string:
+			return x, nil
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64), nil
+		case int:
+			return strconv.Itoa(x), nil
+		case bool:
+			return strconv.FormatBool(x), nil
+		}
+	case "number", "integer":
+		n, ok := toNumber(v)
+		if !ok {
+			break
+		}
+		if t == "integer" && n != math.Trunc(n) {
+			return nil, fmt.Errorf("must be an integer, got %v", n)
+		}
+		return n, nil
+	case "boolean":
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case string:
+			switch strings.ToLower(strings.TrimSpace(x)) {
+			case "true", "yes", "y":
+				return true, nil
+			case "false", "no", "n":
+				return false, nil
+			}
+		}
+	case "array":
+		switch x := v.(type) {
+		case []interface{}:
+			return x, nil
+		case nil:
+		default:
+			return []interface{}{x}, nil
+		}
+	case "object":
+		if x, ok := v.(map[string]interface{}); ok {
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("must be %s %s, got %s", article(t), t, describe(v))
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case string:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+			return n, true
+		}
+		if q, ok := quantity.Parse(x); ok {
+			return q.Value, true
+		}
+	}
+	return 0, false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	b, _ := json.Marshal(v)
+	for _, e := range enum {
+		if eb, _ := json.Marshal(e); string(eb) == string(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describe(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(x)
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprint(v)
+}
+
+func article(t string) string {
+	if strings.ContainsRune("aeiou", rune(t[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
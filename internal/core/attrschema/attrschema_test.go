@@ -0,0 +1,90 @@
+package attrschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	for _, raw := range []map[string]interface{}{
+		{"type": "decimal"},
+		{"type": 3},
+		{"format": "uuid"},
+		{"pattern": "("},
+		{"properties": map[string]interface{}{"age": map[string]interface{}{"type": "int"}}},
+	} {
+		_, err := Compile(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestCheckAttributes(t *testing.T) {
+	schema, err := Compile(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"age":      map[string]interface{}{"type": "integer", "minimum": 0},
+			"salary":   map[string]interface{}{"type": "number"},
+			"employed": map[string]interface{}{"type": "boolean"},
+			"zip":      map[string]interface{}{"type": "string", "pattern": "^[0-9]{5}$"},
+			"role":     map[string]interface{}{"enum": []interface{}{"engineer", "manager"}},
+			"skills":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"born":     map[string]interface{}{"type": "string", "format": "date"},
+			"height":   map[string]interface{}{"type": []interface{}{"number", "null"}},
+		},
+	})
+	require.NoError(t, err)
+
+	valid, invalid := schema.CheckAttributes(map[string]interface{}{
+		"age":      "28-year-old",
+		"salary":   "$120k",
+		"employed": "yes",
+		"zip":      12345.0,
+		"role":     "engineer",
+		"skills":   "go",
+		"born":     "1998-02-03",
+		"height":   nil,
+		"nickname": "Al",
+	})
+	assert.Empty(t, invalid)
+	assert.Equal(t, map[string]interface{}{
+		"age":      28.0,
+		"salary":   120000.0,
+		"employed": true,
+		"zip":      "12345",
+		"role":     "engineer",
+		"skills":   []interface{}{"go"},
+		"born":     "1998-02-03",
+		"height":   nil,
+		"nickname": "Al",
+	}, valid)
+
+	valid, invalid = schema.CheckAttributes(map[string]interface{}{
+		"age":      "-3",
+		"salary":   "a lot",
+		"employed": "maybe",
+		"zip":      "ABCDE",
+		"role":     "intern",
+		"skills":   []interface{}{"go", map[string]interface{}{}},
+		"born":     "last year",
+		"name":     "Alice",
+	})
+	assert.Equal(t, map[string]interface{}{"name": "Alice"}, valid)
+	assert.Len(t, invalid, 7)
+	assert.EqualError(t, invalid["age"], "age: must be at least 0")
+	assert.EqualError(t, invalid["salary"], `salary: must be a number, got "a lot"`)
+	assert.EqualError(t, invalid["role"], `role: must be one of "engineer", "manager"`)
+	assert.EqualError(t, invalid["skills"], "skills[1]: must be a string, got an object")
+}
+
+func TestCheckAttributes_AdditionalProperties(t *testing.T) {
+	schema, err := Compile(map[string]interface{}{
+		"properties":           map[string]interface{}{"age": map[string]interface{}{"type": "integer"}},
+		"additionalProperties": false,
+	})
+	require.NoError(t, err)
+
+	valid, invalid := schema.CheckAttributes(map[string]interface{}{"age": 3.0, "mood": "happy"})
+	assert.Equal(t, map[string]interface{}{"age": 3.0}, valid)
+	assert.EqualError(t, invalid["mood"], "mood: not declared by the schema")
+}
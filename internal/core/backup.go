@@ -0,0 +1,542 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var (
+	ErrBackupsDisabled = errors.New("backups are not configured")
+	ErrBackupNotFound  = errors.New("backup not found")
+	ErrInvalidBackup   = errors.New("invalid backup")
+)
+
+// LatestBackup names a group's newest snapshot in GetBackup and RestoreBackup.
+const LatestBackup = "latest"
+
+// importBatchSize caps the nodes or edges written per import query.
+const importBatchSize = 500
+
+const backupIDLayout = "20060102T150405.000Z"
+
+// ExportGraph copies every node and relationship of a group, or of the whole
+// graph when groupID is empty, with their stored properties. Offloaded episode
+// content is fetched into the export, so it doesn't depend on the content store.
+func (g *Graphiti) ExportGraph(ctx context.Context, groupID string) (*model.GraphExport, error) {
+	var group interface{}
+	if groupID != "" {
+		group = groupID
+	}
+	params := map[string]interface{}{"group_id": group}
+	out := &model.GraphExport{Version: model.ExportVersion, GroupID: groupID, ExportedAt: time.Now().UTC()}
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.ExportNodesQuery, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export nodes: %w", err)
+	}
+	for _, rec := range res.Records {
+		var node struct {
+			Labels []string `db:"labels"`
+		}
+		if err := driver.ScanRecord(rec, &node); err != nil {
+			return nil, fmt.Errorf("failed to read exported node: %w", err)
+		}
+		raw, _ := rec.Get("properties")
+		props := exportProperties(raw)
+		if slices.Contains(node.Labels, "Episodic") {
+			if err := g.inlineEpisodeContent(ctx, props); err != nil {
+				return nil, fmt.Errorf("failed to export episode: %w", err)
+			}
+		}
+		out.Nodes = append(out.Nodes, model.ExportNode{Labels: node.Labels, Properties: props})
+	}
+
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.ExportEdgesQuery, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export edges: %w", err)
+	}
+	for _, rec := range res.Records {
+		var edge model.ExportEdge
+		if err := driver.ScanRecord(rec, &edge); err != nil {
+			return nil, fmt.Errorf("failed to read exported edge: %w", err)
+		}
+		props, _ := rec.Get("properties")
+		edge.Properties = exportProperties(props)
+		out.Edges = append(out.Edges, edge)
+	}
+	return out, nil
+}
+
+// exportProperties copies a property map, turning driver temporal values into
+// time.Time so they serialize as RFC 3339 like the SQLite backend stores them.
+func exportProperties(raw interface{}) map[string]interface{} {
+	props, _ := raw.(map[string]interface{})
+	out := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		out[k] = exportValue(v)
+	}
+	return out
+}
+
+func exportValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, string, bool, int64, float64, time.Time:
+		return v
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = exportValue(item)
+		}
+		return out
+	}
+	if rv := reflect.ValueOf(v); rv.Type().ConvertibleTo(reflect.TypeOf(time.Time{})) {
+		return rv.Convert(reflect.TypeOf(time.Time{})).Interface()
+	}
+	return v
+}
+
+// backupPrefix is the key prefix of a group's snapshots; whole-graph snapshots
+// live under "graph/".
+func backupPrefix(groupID string) string {
+	if groupID == "" {
+		return "graph/"
+	}
+	return "groups/" + keySegment(groupID) + "/"
+}
+
+// keySegment escapes s for use as one segment of a blob key.
+func keySegment(s string) string {
+	seg := url.PathEscape(s)
+	if seg == "." || seg == ".." {
+		seg = strings.ReplaceAll(seg, ".", "%2E")
+	}
+	return seg
+}
+
+// parseBackupKey is the inverse of backupPrefix(groupID) + id + ".json.gz".
+func parseBackupKey(key string) (groupID, id string, ok bool) {
+	name, ok := strings.CutSuffix(key, ".json.gz")
+	if !ok {
+		return "", "", false
+	}
+	if id, ok := strings.CutPrefix(name, "graph/"); ok && !strings.Contains(id, "/") {
+		return "", id, true
+	}
+	rest, ok := strings.CutPrefix(name, "groups/")
+	if !ok {
+		return "", "", false
+	}
+	dir, id, ok := strings.Cut(rest, "/")
+	if !ok || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	groupID, err := url.PathUnescape(dir)
+	if err != nil {
+		return "", "", false
+	}
+	return groupID, id, true
+}
+
+// Backup writes a gzipped export of the group, or of the whole graph when
+// groupID is empty, to the backup store.
+func (g *Graphiti) Backup(ctx context.Context, groupID string) (*model.BackupInfo, error) {
+	if g.Backups == nil {
+		return nil, ErrBackupsDisabled
+	}
+	export, err := g.ExportGraph(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(export); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+
+	info := &model.BackupInfo{
+		ID:        export.ExportedAt.Format(backupIDLayout),
+		GroupID:   groupID,
+		Size:      int64(buf.Len()),
+		CreatedAt: export.ExportedAt.Truncate(time.Millisecond), // As listed, parsed from ID
+	}
+	info.Key = backupPrefix(groupID) + info.ID + ".json.gz"
+	if err := g.Backups.Put(ctx, info.Key, buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return info, nil
+}
+
+// RunBackup snapshots the graph as [backup] scope says, one snapshot per group
+// or a single one of the whole graph, then applies the retention policy. It
+// continues past groups that fail and reports them together.
+func (g *Graphiti) RunBackup(ctx context.Context) ([]model.BackupInfo, error) {
+	if g.Backups == nil {
+		return nil, ErrBackupsDisabled
+	}
+	groups := []string{""}
+	switch scope := g.Config.Backup.Scope; scope {
+	case "", model.BackupScopeGroup:
+		all, err := g.ListGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups = groups[:0]
+		for _, group := range all {
+			groups = append(groups, group.GroupID)
+		}
+	case model.BackupScopeGraph:
+	default:
+		return nil, fmt.Errorf("unsupported backup scope: %s", scope)
+	}
+
+	var infos []model.BackupInfo
+	var errs []error
+	for _, groupID := range groups {
+		info, err := g.Backup(ctx, groupID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("group %q: %w", groupID, err))
+			continue
+		}
+		infos = append(infos, *info)
+	}
+	if err := g.PruneBackups(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return infos, errors.Join(errs...)
+}
+
+// ListBackups lists the snapshots of a group, newest first. An empty groupID
+// lists every snapshot in the store.
+func (g *Graphiti) ListBackups(ctx context.Context, groupID string) ([]model.BackupInfo, error) {
+	if g.Backups == nil {
+		return nil, ErrBackupsDisabled
+	}
+	prefix := ""
+	if groupID != "" {
+		prefix = backupPrefix(groupID)
+	}
+	objects, err := g.Backups.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	infos := make([]model.BackupInfo, 0, len(objects))
+	for _, obj := range objects {
+		group, id, ok := parseBackupKey(obj.Key)
+		if !ok {
+			continue
+		}
+		info := model.BackupInfo{ID: id, GroupID: group, Key: obj.Key, Size: obj.Size, CreatedAt: obj.ModTime.UTC()}
+		if t, err := time.Parse(backupIDLayout, id); err == nil {
+			info.CreatedAt = t
+		}
+		infos = append(infos, info)
+	}
+	sort.SliceStable(infos, func(i, j int) bool { return infos[i].CreatedAt.After(infos[j].CreatedAt) })
+	return infos, nil
+}
+
+// PruneBackups deletes the snapshots beyond [backup] retain and those older
+// than max_age_days. Each group's newest snapshot is always kept.
+func (g *Graphiti) PruneBackups(ctx context.Context) error {
+	cfg := g.Config.Backup
+	if cfg.Retain <= 0 && cfg.MaxAgeDays <= 0 {
+		return nil
+	}
+	infos, err := g.ListBackups(ctx, "")
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -cfg.MaxAgeDays)
+	kept := map[string]int{}
+	var errs []error
+	for _, info := range infos { // Newest first
+		dir := path.Dir(info.Key)
+		n := kept[dir]
+		expired := n > 0 && (cfg.Retain > 0 && n >= cfg.Retain || cfg.MaxAgeDays > 0 && info.CreatedAt.Before(cutoff))
+		if !expired {
+			kept[dir]++
+			continue
+		}
+		if err := g.Backups.Delete(ctx, info.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunBackups takes a backup each interval until ctx is done.
+func (g *Graphiti) RunBackups(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			infos, err := g.RunBackup(ctx)
+			if err != nil {
+				log.Printf("Backup failed: %v", err)
+			}
+			if len(infos) > 0 {
+				log.Printf("Backup: wrote %d snapshots", len(infos))
+			}
+		}
+	}
+}
+
+// GetBackup reads a snapshot of the group (or, for an empty groupID, of the
+// whole graph) by ID or LatestBackup.
+func (g *Graphiti) GetBackup(ctx context.Context, groupID, id string) (*model.GraphExport, error) {
+	if g.Backups == nil {
+		return nil, ErrBackupsDisabled
+	}
+	if id == LatestBackup {
+		infos, err := g.ListBackups(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		id = ""
+		for _, info := range infos {
+			if info.GroupID == groupID {
+				id = info.ID
+				break
+			}
+		}
+		if id == "" {
+			return nil, ErrBackupNotFound
+		}
+	}
+	if id == "" || strings.Contains(id, "/") {
+		return nil, ErrBackupNotFound
+	}
+
+	data, err := g.Backups.Get(ctx, backupPrefix(groupID)+id+".json.gz")
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, ErrBackupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	dec := json.NewDecoder(zr)
+	dec.UseNumber()
+	var export model.GraphExport
+	if err := dec.Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if export.Version != model.ExportVersion {
+		return nil, fmt.Errorf("%w: unsupported export version %d", ErrInvalidBackup, export.Version)
+	}
+	if export.GroupID != groupID {
+		return nil, fmt.Errorf("%w: snapshot is of group %q", ErrInvalidBackup, export.GroupID)
+	}
+	for _, n := range export.Nodes {
+		importProperties(n.Properties)
+	}
+	for _, e := range export.Edges {
+		importProperties(e.Properties)
+	}
+	return &export, nil
+}
+
+// importProperties turns the json.Numbers of a decoded snapshot back into the
+// int64 and float64 values they were exported from, and the timestamps of
+// temporal properties (all named "*_at") back into time.Time.
+func importProperties(props map[string]interface{}) {
+	for k, v := range props {
+		if s, ok := v.(string); ok && strings.HasSuffix(k, "_at") {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				props[k] = t
+				continue
+			}
+		}
+		props[k] = importValue(v)
+	}
+}
+
+func importValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case []interface{}:
+		for i, item := range val {
+			val[i] = importValue(item)
+		}
+	}
+	return v
+}
+
+// ImportGraph writes the nodes and relationships of an export into the graph.
+// It does not remove existing data; see RestoreBackup. Change logs in the
+// export are skipped: the graph keeps its own.
+func (g *Graphiti) ImportGraph(ctx context.Context, export *model.GraphExport) (nodes, edges int, err error) {
+	for _, e := range export.Edges {
+		if _, ok := driver.ImportEdgesQueries[e.Type]; !ok {
+			return 0, 0, fmt.Errorf("%w: unknown relationship type %q", ErrInvalidBackup, e.Type)
+		}
+	}
+
+	imported := make([]model.ExportNode, 0, len(export.Nodes))
+	for _, n := range export.Nodes {
+		if slices.Contains(n.Labels, "Change") || slices.Contains(n.Labels, "ChangeSequence") {
+			continue
+		}
+		if slices.Contains(n.Labels, "Episodic") {
+			n.Properties = maps.Clone(n.Properties)
+			if err := g.restoreEpisodeContent(ctx, n.Properties); err != nil {
+				return 0, 0, fmt.Errorf("failed to import episode: %w", err)
+			}
+		}
+		imported = append(imported, n)
+	}
+	for start := 0; start < len(imported); start += importBatchSize {
+		batch := imported[start:min(start+importBatchSize, len(imported))]
+		params := make([]map[string]interface{}, len(batch))
+		for i, n := range batch {
+			params[i] = map[string]interface{}{"labels": n.Labels, "properties": n.Properties}
+		}
+		n, err := g.importBatch(ctx, driver.ImportNodesQuery, "nodes", params)
+		if err != nil {
+			return nodes, edges, fmt.Errorf("failed to import nodes: %w", err)
+		}
+		nodes += n
+	}
+
+	byType := map[string][]map[string]interface{}{}
+	var types []string
+	for _, e := range export.Edges {
+		if _, ok := byType[e.Type]; !ok {
+			types = append(types, e.Type)
+		}
+		byType[e.Type] = append(byType[e.Type], map[string]interface{}{
+			"source_uuid": e.SourceUUID, "target_uuid": e.TargetUUID, "properties": e.Properties,
+		})
+	}
+	for _, relType := range types {
+		all := byType[relType]
+		for start := 0; start < len(all); start += importBatchSize {
+			n, err := g.importBatch(ctx, driver.ImportEdgesQueries[relType], "edges", all[start:min(start+importBatchSize, len(all))])
+			if err != nil {
+				return nodes, edges, fmt.Errorf("failed to import %s edges: %w", relType, err)
+			}
+			edges += n
+		}
+	}
+	return nodes, edges, nil
+}
+
+func (g *Graphiti) importBatch(ctx context.Context, query, key string, batch []map[string]interface{}) (int, error) {
+	res, err := g.Driver.ExecuteQuery(ctx, query, map[string]interface{}{key: batch})
+	if err != nil {
+		return 0, err
+	}
+	var out struct {
+		Imported int `db:"imported"`
+	}
+	if len(res.Records) > 0 {
+		if err := driver.ScanRecord(res.Records[0], &out); err != nil {
+			return 0, err
+		}
+	}
+	return out.Imported, nil
+}
+
+// RestoreBackup replaces a group with one of its snapshots (by ID or
+// LatestBackup), or the whole graph with a whole-graph snapshot when groupID
+// is empty. The current state is snapshotted first, so a restore can itself be
+// undone. A restore that fails midway leaves the group partially imported and
+// can be rerun.
+func (g *Graphiti) RestoreBackup(ctx context.Context, groupID, id string) (*model.RestoreReport, error) {
+	export, err := g.GetBackup(ctx, groupID, id)
+	if err != nil {
+		return nil, err
+	}
+	report := &model.RestoreReport{GroupID: groupID, BackupID: id}
+	if id == LatestBackup {
+		report.BackupID = export.ExportedAt.Format(backupIDLayout)
+	}
+
+	pre, err := g.Backup(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot before restore: %w", err)
+	}
+	report.PreRestoreBackupID = pre.ID
+
+	// Groups whose cached searches the restore invalidates
+	groups := map[string]bool{groupID: true}
+	if groupID == "" {
+		current, err := g.ListGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range current {
+			groups[group.GroupID] = true
+		}
+		for _, n := range export.Nodes {
+			if group, ok := n.Properties["group_id"].(string); ok {
+				groups[group] = true
+			}
+		}
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.ClearGraphQuery, nil); err != nil {
+			return nil, fmt.Errorf("failed to clear graph: %w", err)
+		}
+	} else if _, err := g.Driver.ExecuteQuery(ctx, driver.DeleteGroupQuery, map[string]interface{}{"group_id": groupID}); err != nil {
+		return nil, fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	for group := range groups {
+		if group == "" {
+			continue
+		}
+		if err := g.recordChange(ctx, group, model.ChangeKindGroup, group, model.ChangeOpDelete); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Nodes, report.Edges, err = g.ImportGraph(ctx, export)
+	for group := range groups {
+		g.invalidateSearchCache(ctx, group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.RestoredAt = time.Now().UTC()
+	return report, nil
+}
+
+// NewBackupStore opens the [backup] store, or returns nil when none is configured.
+func NewBackupStore(cfg config.BackupConfig) (blob.Store, error) {
+	if cfg.Store == (config.BlobStoreConfig{}) {
+		return nil, nil
+	}
+	return blob.New(cfg.Store)
+}
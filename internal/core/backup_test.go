@@ -0,0 +1,230 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportGraph(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	seedEntity(t, g.Driver, "g2", "other", map[string]interface{}{"attributes": "{}"})
+
+	export, err := g.ExportGraph(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, model.ExportVersion, export.Version)
+	assert.Equal(t, "g1", export.GroupID)
+	require.Len(t, export.Nodes, 3)
+	assert.Equal(t, []string{"Entity", "Company"}, export.Nodes[0].Labels)
+	assert.Equal(t, "acme", export.Nodes[0].Properties["uuid"])
+	require.Len(t, export.Edges, 3)
+	assert.Equal(t, model.ExportEdge{
+		Type: "RELATES_TO", SourceUUID: "alice", TargetUUID: "acme",
+		Properties: export.Edges[0].Properties,
+	}, export.Edges[0])
+	assert.Equal(t, "e1 fact", export.Edges[0].Properties["fact"])
+
+	all, err := g.ExportGraph(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all.Nodes, 4)
+}
+
+func TestBackups(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	g.Backups = blob.NewFileStore(t.TempDir())
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, map[string]interface{}{
+		"uuid": "ep1", "name": "ep1", "group_id": "g1", "content": "hello", "created_at": time.Now().UTC().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	_, err = g.Driver.ExecuteQuery(ctx, driver.EnsureGroupQuery, map[string]interface{}{
+		"group_id": "g1", "created_at": time.Now().UTC().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	infos, err := g.RunBackup(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "g1", infos[0].GroupID)
+
+	data, err := g.Backups.Get(ctx, infos[0].Key)
+	require.NoError(t, err)
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var export model.GraphExport
+	require.NoError(t, json.NewDecoder(zr).Decode(&export))
+	assert.Len(t, export.Nodes, 5)
+	assert.Len(t, export.Edges, 3)
+
+	// Whole-graph snapshots sit beside group ones
+	g.Config.Backup.Scope = model.BackupScopeGraph
+	graphInfos, err := g.RunBackup(ctx)
+	require.NoError(t, err)
+	require.Len(t, graphInfos, 1)
+	assert.Empty(t, graphInfos[0].GroupID)
+
+	listed, err := g.ListBackups(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, infos[0].ID, listed[0].ID)
+	listed, err = g.ListBackups(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+
+	// Retention keeps the newest snapshots of each group
+	for i := 0; i < 2; i++ {
+		time.Sleep(2 * time.Millisecond)
+		_, err := g.Backup(ctx, "g1")
+		require.NoError(t, err)
+	}
+	g.Config.Backup.Retain = 2
+	require.NoError(t, g.PruneBackups(ctx))
+	listed, err = g.ListBackups(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.True(t, listed[0].CreatedAt.After(infos[0].CreatedAt))
+	listed, err = g.ListBackups(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, listed, 3)
+
+	g.Backups = nil
+	_, err = g.Backup(ctx, "g1")
+	assert.ErrorIs(t, err, ErrBackupsDisabled)
+}
+
+func TestBackupKeys(t *testing.T) {
+	for _, groupID := range []string{"", "g1", "team/a b", ".."} {
+		group, id, ok := parseBackupKey(backupPrefix(groupID) + "20261016T120000.000Z.json.gz")
+		require.True(t, ok, groupID)
+		assert.Equal(t, groupID, group)
+		assert.Equal(t, "20261016T120000.000Z", id)
+	}
+	_, _, ok := parseBackupKey("groups/g1/nested/x.json.gz")
+	assert.False(t, ok)
+}
+
+func exportJSON(t *testing.T, g *Graphiti, groupID string) string {
+	export, err := g.ExportGraph(context.Background(), groupID)
+	require.NoError(t, err)
+	data, err := json.Marshal(struct {
+		Nodes []model.ExportNode
+		Edges []model.ExportEdge
+	}{export.Nodes, export.Edges})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	g.Backups = blob.NewFileStore(t.TempDir())
+	queued, err := g.CreateIngestJob(ctx, "g1", []model.EpisodeData{{Content: "queued"}})
+	require.NoError(t, err)
+	seedEntity(t, g.Driver, "g2", "other", map[string]interface{}{"attributes": "{}"})
+
+	before := exportJSON(t, g, "g1")
+	snapshot, err := g.Backup(ctx, "g1")
+	require.NoError(t, err)
+
+	// Diverge: drop a fact and add an entity
+	_, err = g.Driver.ExecuteQuery(ctx, driver.DeleteEntityEdgeQuery, map[string]interface{}{"uuid": "e1"})
+	require.NoError(t, err)
+	seedEntity(t, g.Driver, "g1", "carol", map[string]interface{}{"attributes": "{}"})
+	diverged := exportJSON(t, g, "g1")
+
+	report, err := g.RestoreBackup(ctx, "g1", LatestBackup)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.ID, report.BackupID)
+	assert.Equal(t, 5, report.Nodes) // 3 entities and the queued job with its batch
+	assert.Equal(t, 3, report.Edges)
+	assert.Equal(t, before, exportJSON(t, g, "g1"))
+	_, err = g.GetEntity(ctx, "other")
+	assert.NoError(t, err, "other groups are untouched")
+	job, err := g.GetIngestJob(ctx, queued.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, job.Total)
+	queuedEpisodes, err := g.getIngestJobBatch(ctx, job, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", queuedEpisodes[0].Content)
+
+	// The pre-restore snapshot undoes the restore
+	_, err = g.RestoreBackup(ctx, "g1", report.PreRestoreBackupID)
+	require.NoError(t, err)
+	assert.Equal(t, diverged, exportJSON(t, g, "g1"))
+
+	_, err = g.RestoreBackup(ctx, "g1", "20000101T000000.000Z")
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+	_, err = g.RestoreBackup(ctx, "g2", LatestBackup)
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+
+	// Whole-graph snapshots replace everything
+	whole, err := g.Backup(ctx, "")
+	require.NoError(t, err)
+	wholeBefore := exportJSON(t, g, "")
+	_, err = g.Driver.ExecuteQuery(ctx, driver.DeleteGroupQuery, map[string]interface{}{"group_id": "g2"})
+	require.NoError(t, err)
+	_, err = g.RestoreBackup(ctx, "", whole.ID)
+	require.NoError(t, err)
+	assert.Equal(t, wholeBefore, exportJSON(t, g, ""))
+}
+
+func TestRestoreBackup_OffloadedContentAndTimes(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	g.Backups = blob.NewFileStore(t.TempDir())
+	g.ContentStore = blob.NewFileStore(t.TempDir())
+	g.Config.ContentStore.ThresholdChars = 10
+	now := time.Now().UTC().Truncate(time.Second)
+	long := "Alice has the flu and stays home."
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", long, now))
+
+	export, err := g.ExportGraph(ctx, "g1")
+	require.NoError(t, err)
+	var exported map[string]interface{}
+	for _, n := range export.Nodes {
+		if n.Properties["uuid"] == "ep1" {
+			exported = n.Properties
+		}
+	}
+	assert.Equal(t, long, exported["content"], "offloaded content is inlined")
+	_, err = g.Backup(ctx, "g1")
+	require.NoError(t, err)
+
+	// The snapshot restores the content even after the store lost it
+	require.NoError(t, g.ContentStore.Delete(ctx, "episodes/g1/ep1"))
+	_, err = g.RestoreBackup(ctx, "g1", LatestBackup)
+	require.NoError(t, err)
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesByUUIDQuery, map[string]interface{}{"uuids": []string{"ep1"}})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	var ep model.EpisodicNode
+	require.NoError(t, driver.ScanRecord(res.Records[0], &ep))
+	assert.Equal(t, "episodes/g1/ep1", ep.ContentRef)
+	assert.True(t, ep.CreatedAt.Equal(now))
+	restored, err := g.ExportGraph(ctx, "g1")
+	require.NoError(t, err)
+	for _, n := range restored.Nodes {
+		if n.Properties["uuid"] == "ep1" {
+			assert.IsType(t, time.Time{}, n.Properties["created_at"], "timestamps are restored as datetimes")
+		}
+	}
+	content, _, err := g.readEpisodeContent(ctx, ep, true)
+	require.NoError(t, err)
+	assert.Equal(t, long, content)
+
+	// Without a content store the content stays in the graph
+	g.ContentStore = nil
+	props := map[string]interface{}{"uuid": "ep1", "group_id": "g1", "content": long, "content_ref": "episodes/g1/ep1"}
+	require.NoError(t, g.restoreEpisodeContent(ctx, props))
+	assert.Equal(t, map[string]interface{}{"uuid": "ep1", "group_id": "g1", "content": long}, props)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// BulkAddEpisodesPartial ingests episodes like BulkAddEpisodes but keeps going
+// past per-episode failures (extraction, node save, episode write, or the
+// context ending before the episode was scheduled), reporting each outcome.
+// An error is returned only when the whole batch could not run.
+func (g *Graphiti) BulkAddEpisodesPartial(ctx context.Context, groupID string, episodes []model.EpisodeData) (*model.BulkIngestResult, error) {
+	epErrs, err := g.bulkAdd(ctx, groupID, episodes, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BulkIngestResult{GroupID: groupID, Results: make([]model.EpisodeResult, len(episodes))}
+	for i, ep := range episodes {
+		res := model.EpisodeResult{Index: i, Status: model.EpisodeStatusSuccess, Episode: ep}
+		if epErrs[i] != nil {
+			res.Status = model.EpisodeStatusFailed
+			res.Error = epErrs[i].Error()
+		}
+		result.Results[i] = res
+	}
+	countResults(result)
+	return result, nil
+}
+
+// RetryFailed re-ingests the failed episodes of a previous partial ingest and
+// returns prev's results with those entries updated. Indices keep referring to
+// the original request, so the call can be repeated until nothing fails.
+// Dead letters of episodes that now succeed are deleted.
+func (g *Graphiti) RetryFailed(ctx context.Context, prev *model.BulkIngestResult) (*model.BulkIngestResult, error) {
+	var indices []int
+	var episodes []model.EpisodeData
+	for i, res := range prev.Results {
+		if res.Status == model.EpisodeStatusFailed {
+			indices = append(indices, i)
+			episodes = append(episodes, res.Episode)
+		}
+	}
+
+	merged := &model.BulkIngestResult{GroupID: prev.GroupID, Results: append([]model.EpisodeResult(nil), prev.Results...)}
+	if len(episodes) > 0 {
+		retry, err := g.BulkAddEpisodesPartial(ctx, prev.GroupID, episodes)
+		if err != nil {
+			return nil, err
+		}
+		for j, res := range retry.Results {
+			res.Index = merged.Results[indices[j]].Index
+			merged.Results[indices[j]] = res
+			if res.Status == model.EpisodeStatusSuccess {
+				if err := g.deleteDeadLetter(ctx, deadLetterID(prev.GroupID, "message", res.Episode)); err != nil {
+					log.Printf("Failed to clear dead letter: %v", err)
+				}
+			}
+		}
+	}
+	countResults(merged)
+	return merged, nil
+}
+
+func countResults(r *model.BulkIngestResult) {
+	r.Succeeded, r.Failed = 0, 0
+	for _, res := range r.Results {
+		if res.Status == model.EpisodeStatusFailed {
+			r.Failed++
+		} else {
+			r.Succeeded++
+		}
+	}
+}
+
+// StreamEpisodes ingests episodes as they arrive on in, running up to
+// Concurrency.BulkIngest at a time across requests, and sends one result per episode to out in
+// completion order. Reading from in stalls while all workers are busy, which
+// pushes back on the producer. out is closed once in is closed and drained.
+func (g *Graphiti) StreamEpisodes(ctx context.Context, in <-chan model.StreamEpisode, out chan<- model.StreamResult) {
+	defer close(out)
+	ctx = asBatch(ctx)
+
+	var wg sync.WaitGroup
+
+	for ep := range in {
+		if err := g.bulkIngest.Acquire(ctx, ep.GroupID); err != nil {
+			out <- model.StreamResult{Index: ep.Index, GroupID: ep.GroupID, Status: model.EpisodeStatusFailed, Error: err.Error()}
+			continue
+		}
+		wg.Add(1)
+		go func(ep model.StreamEpisode) {
+			defer wg.Done()
+			defer g.bulkIngest.Release()
+
+			res := model.StreamResult{Index: ep.Index, GroupID: ep.GroupID, Status: model.EpisodeStatusSuccess}
+			if err := g.AddEpisode(ctx, ep.GroupID, "message", ep.Content, ep.Saga, ep.Schema); err != nil {
+				res.Status = model.EpisodeStatusFailed
+				res.Error = err.Error()
+			}
+			out <- res
+		}(ep)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyLLM fails extraction for prompts containing a marker until healed.
+type flakyLLM struct {
+	mu     sync.Mutex
+	marker string
+	healed bool
+}
+
+func (f *flakyLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.healed && strings.Contains(prompt, f.marker) {
+		return "", errors.New("model overloaded")
+	}
+	return `{"extracted_entities": []}`, nil
+}
+
+func TestBulkAddEpisodesPartial_ContinuesAndRetries(t *testing.T) {
+	ctx := context.Background()
+	llmClient := &flakyLLM{marker: "bad"}
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	episodes := []model.EpisodeData{{Content: "good one"}, {Content: "bad one"}, {Content: "good two"}}
+
+	// The all-or-nothing API still aborts
+	assert.Error(t, g.BulkAddEpisodes(ctx, "g1", episodes))
+
+	result, err := g.BulkAddEpisodesPartial(ctx, "g1", episodes)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, model.EpisodeStatusFailed, result.Results[1].Status)
+	assert.Contains(t, result.Results[1].Error, "model overloaded")
+	assert.Equal(t, []model.EpisodeData{{Content: "bad one"}}, result.FailedEpisodes())
+
+	llmClient.healed = true
+	retried, err := g.RetryFailed(ctx, result)
+	require.NoError(t, err)
+	assert.Equal(t, 3, retried.Succeeded)
+	assert.Equal(t, 0, retried.Failed)
+	assert.Equal(t, 1, retried.Results[1].Index)
+	assert.Equal(t, model.EpisodeStatusSuccess, retried.Results[1].Status)
+}
+
+func TestBulkAddEpisodesPartial_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{Response: `{}`}, nil, nil, &config.Config{})
+	cancel()
+
+	result, err := g.BulkAddEpisodesPartial(ctx, "g1", []model.EpisodeData{{Content: "a"}, {Content: "b"}, {Content: "c"}})
+	require.NoError(t, err)
+	// Scheduling stops once the context is done; unscheduled episodes are reported for retry
+	assert.Equal(t, 3, result.Succeeded+result.Failed)
+	for _, res := range result.Results {
+		if res.Status == model.EpisodeStatusFailed {
+			assert.Contains(t, res.Error, context.Canceled.Error())
+		}
+	}
+}
+
+func TestStreamEpisodes(t *testing.T) {
+	ctx := context.Background()
+	llmClient := &flakyLLM{marker: "bad"}
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	in := make(chan model.StreamEpisode)
+	out := make(chan model.StreamResult)
+	go g.StreamEpisodes(ctx, in, out)
+	go func() {
+		defer close(in)
+		for i, content := range []string{"good", "bad", "good again"} {
+			in <- model.StreamEpisode{Index: i, GroupID: "g1", EpisodeData: model.EpisodeData{Content: content}}
+		}
+	}()
+
+	statuses := make(map[int]string)
+	for res := range out {
+		statuses[res.Index] = res.Status
+	}
+	assert.Equal(t, map[int]string{
+		0: model.EpisodeStatusSuccess,
+		1: model.EpisodeStatusFailed,
+		2: model.EpisodeStatusSuccess,
+	}, statuses)
+}
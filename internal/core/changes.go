@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (g *Graphiti) changesEnabled() bool {
+	return g.Config != nil && g.Config.Changes.Enabled
+}
+
+type factChangesKey struct{}
+
+// FactChange is a fact an ingest created or invalidated; Op is
+// model.ChangeOpCreate or model.ChangeOpInvalidate.
+type FactChange struct {
+	UUID string
+	Op   string
+}
+
+// FactChanges collects the facts created or invalidated under a context from
+// WithFactChanges, whether or not [changes] is enabled.
+type FactChanges struct {
+	mu      sync.Mutex
+	changes []FactChange
+}
+
+// WithFactChanges returns a context whose ingests report the facts they
+// create or invalidate to the returned FactChanges.
+func WithFactChanges(ctx context.Context) (context.Context, *FactChanges) {
+	fc := &FactChanges{}
+	return context.WithValue(ctx, factChangesKey{}, fc), fc
+}
+
+// List returns the changes in the order they were made.
+func (fc *FactChanges) List() []FactChange {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return append([]FactChange(nil), fc.changes...)
+}
+
+// recordChange appends a write to the group's change log when [changes] is
+// enabled, and reports created and invalidated facts to WithFactChanges.
+func (g *Graphiti) recordChange(ctx context.Context, groupID, kind, uuid, op string) error {
+	if fc, ok := ctx.Value(factChangesKey{}).(*FactChanges); ok && kind == model.ChangeKindFact &&
+		(op == model.ChangeOpCreate || op == model.ChangeOpInvalidate) {
+		fc.mu.Lock()
+		fc.changes = append(fc.changes, FactChange{UUID: uuid, Op: op})
+		fc.mu.Unlock()
+	}
+	if !g.changesEnabled() {
+		return nil
+	}
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.AppendChangeQuery, map[string]interface{}{
+		"uuid":          g.UUIDGenerator(),
+		"sequence_uuid": g.UUIDGenerator(),
+		"group_id":      groupID,
+		"kind":          kind,
+		"target_uuid":   uuid,
+		"op":            op,
+		"at":            time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return fmt.Errorf("failed to record change of %s %s: %w", kind, uuid, err)
+	}
+	return nil
+}
+
+// recordDeletions logs what a delete query removed, read from the uuid and
+// group_id of its records.
+func (g *Graphiti) recordDeletions(ctx context.Context, kind string, res neo4j.EagerResult) error {
+	if !g.changesEnabled() {
+		return nil
+	}
+	deleted, err := driver.ScanRecords[struct {
+		UUID    string `db:"uuid"`
+		GroupID string `db:"group_id"`
+	}](res)
+	if err != nil {
+		return fmt.Errorf("failed to read deleted %s: %w", kind, err)
+	}
+	for _, d := range deleted {
+		if err := g.recordChange(ctx, d.GroupID, kind, d.UUID, model.ChangeOpDelete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordEntitySave logs an entity written by SaveEntityNodeQuery: created if
+// res shows its first version, updated otherwise.
+func (g *Graphiti) recordEntitySave(ctx context.Context, groupID, uuid string, res neo4j.EagerResult) error {
+	if !g.changesEnabled() {
+		return nil
+	}
+	op := model.ChangeOpUpdate
+	if len(res.Records) > 0 {
+		var saved struct {
+			Version int `db:"version"`
+		}
+		if err := driver.ScanRecord(res.Records[0], &saved); err != nil {
+			return fmt.Errorf("failed to read entity version: %w", err)
+		}
+		if saved.Version == 1 {
+			op = model.ChangeOpCreate
+		}
+	}
+	return g.recordChange(ctx, groupID, model.ChangeKindEntity, uuid, op)
+}
+
+// GetChanges returns the group's changes numbered after since, oldest first
+// and at most limit of them (100 when limit isn't positive). Pass the Seq of
+// the last change seen to continue from it.
+func (g *Graphiti) GetChanges(ctx context.Context, groupID string, since int64, limit int) ([]model.Change, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetChangesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"since":    since,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch changes: %w", err)
+	}
+	changes, err := driver.ScanRecords[model.Change](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changes: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeLog(t *testing.T) {
+	ctx := context.Background()
+	var edges string
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+		Summary:    config.SummaryPrompts{Nodes: "summarize %s %s"},
+		Changes:    config.ChangesConfig{Enabled: true},
+	}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "edges"):
+			return edges
+		case strings.HasPrefix(prompt, "Does the New Fact contradict"):
+			return `{"contradicted_edge_uuids": []}`
+		}
+		return `{"summary": "updated"}`
+	}), nil, nil, cfg)
+	now := time.Now().UTC()
+	var nodes []model.EntityNode
+	for _, name := range []string{"a", "b"} {
+		n := model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: now}
+		require.NoError(t, g.saveEntity(ctx, n))
+		nodes = append(nodes, n)
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", "a knows b", now))
+	edges = `{"extracted_edges": [{"source_node_uuid": "a", "target_node_uuid": "b", "relation_type": "KNOWS", "fact": "a knows b"}]}`
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep1", "g1", "", nil, now))
+	summary := "edited"
+	_, err := g.UpdateEntity(ctx, "b", model.EntityPatch{Summary: &summary})
+	require.NoError(t, err)
+
+	changes, err := g.GetChanges(ctx, "g1", 0, 0)
+	require.NoError(t, err)
+	type entry struct{ kind, uuid, op string }
+	var got []entry
+	for i, c := range changes {
+		assert.Equal(t, int64(i+1), c.Seq)
+		assert.Equal(t, "g1", c.GroupID)
+		assert.False(t, c.At.IsZero())
+		uuid := c.UUID
+		if c.Kind == model.ChangeKindFact {
+			uuid = "fact"
+		}
+		got = append(got, entry{c.Kind, uuid, c.Op})
+	}
+	assert.Equal(t, []entry{
+		{model.ChangeKindEntity, "a", model.ChangeOpCreate},
+		{model.ChangeKindEntity, "b", model.ChangeOpCreate},
+		{model.ChangeKindEpisode, "ep1", model.ChangeOpCreate},
+		{model.ChangeKindFact, "fact", model.ChangeOpCreate},
+	}, got[:4])
+	// Both summaries are saved, in either order, before the edit
+	assert.ElementsMatch(t, []entry{
+		{model.ChangeKindEntity, "a", model.ChangeOpUpdate},
+		{model.ChangeKindEntity, "b", model.ChangeOpUpdate},
+	}, got[4:6])
+	assert.Equal(t, []entry{{model.ChangeKindEntity, "b", model.ChangeOpUpdate}}, got[6:])
+
+	// Readers continue from the last sequence number they saw
+	page, err := g.GetChanges(ctx, "g1", 3, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []int64{4, 5}, []int64{page[0].Seq, page[1].Seq})
+	page, err = g.GetChanges(ctx, "g1", int64(len(changes)), 0)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+
+	// Each group numbers its own changes
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "c", Name: "c", GroupID: "g2", CreatedAt: now}))
+	other, err := g.GetChanges(ctx, "g2", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, int64(1), other[0].Seq)
+
+	// Disabled, nothing is logged
+	g.Config.Changes.Enabled = false
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "d", Name: "d", GroupID: "g2", CreatedAt: now}))
+	other, err = g.GetChanges(ctx, "g2", 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, other, 1)
+}
+
+func TestChangeLog_Deletions(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Changes: config.ChangesConfig{Enabled: true}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(string) string { return "{}" }), nil, nil, cfg)
+	now := time.Now().UTC()
+	for _, name := range []string{"a", "b", "orphan"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: now.Add(-2 * time.Hour)}))
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", "a knows b", now))
+	seedFact(t, g.Driver, "g1", "f1", "a", "b", "KNOWS", "a knows b", nil)
+
+	require.NoError(t, g.DeleteFact(ctx, "f1"))
+	require.NoError(t, g.DeleteEpisode(ctx, "ep1"))
+	_, err := g.CollectOrphans(ctx, "g1", model.OrphanModeQuarantine, false)
+	require.NoError(t, err)
+	_, err = g.DeleteGroup(ctx, "g1")
+	require.NoError(t, err)
+
+	changes, err := g.GetChanges(ctx, "g1", 0, 0)
+	require.NoError(t, err)
+	type entry struct{ kind, uuid string }
+	var deleted []entry
+	for _, c := range changes {
+		if c.Op == model.ChangeOpDelete {
+			deleted = append(deleted, entry{c.Kind, c.UUID})
+		}
+	}
+	require.GreaterOrEqual(t, len(deleted), 4)
+	assert.Equal(t, []entry{{model.ChangeKindFact, "f1"}, {model.ChangeKindEpisode, "ep1"}}, deleted[:2])
+	// Every entity is an orphan once the episode and fact are gone
+	assert.ElementsMatch(t, []entry{{model.ChangeKindEntity, "a"}, {model.ChangeKindEntity, "b"}, {model.ChangeKindEntity, "orphan"}}, deleted[2:len(deleted)-1])
+	// The log outlives the group's data
+	assert.Equal(t, entry{model.ChangeKindGroup, "g1"}, deleted[len(deleted)-1])
+	assert.Equal(t, int64(len(changes)), changes[len(changes)-1].Seq)
+}
+
+func TestWithFactChanges(t *testing.T) {
+	ctx := context.Background()
+	var edges string
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "edges"):
+			return edges
+		case strings.HasPrefix(prompt, "Does the New Fact contradict"):
+			return `{"contradicted_edge_uuids": ["first"]}`
+		}
+		return `{"summary": "updated"}`
+	}), nil, nil, &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"}})
+	now := time.Now().UTC()
+	var nodes []model.EntityNode
+	for _, name := range []string{"a", "b", "c"} {
+		n := model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: now}
+		require.NoError(t, g.saveEntity(ctx, n))
+		nodes = append(nodes, n)
+	}
+	seedFact(t, g.Driver, "g1", "first", "a", "b", "LIVES_IN", "a lives in b", nil)
+
+	// Only the facts this ingest created or invalidated are reported, changes log or not
+	tracked, changes := WithFactChanges(ctx)
+	edges = `{"extracted_edges": [{"source_node_uuid": "a", "target_node_uuid": "c", "relation_type": "LIVES_IN", "fact": "a lives in c"}]}`
+	require.NoError(t, g.processEntityEdgesAndSummaries(tracked, nodes, "ep1", "g1", "", nil, now))
+	list := changes.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, FactChange{UUID: "first", Op: model.ChangeOpInvalidate}, list[0])
+	assert.Equal(t, model.ChangeOpCreate, list[1].Op)
+	created, err := g.GetFact(ctx, list[1].UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "a lives in c", created.Fact)
+}
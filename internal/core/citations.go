@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// ErrCitationGroupRequired is returned by ResolveCitation without a group:
+// handles are only unique within one.
+var ErrCitationGroupRequired = errors.New("group_id is required to resolve a citation")
+
+// citationPrefix starts every citation handle; citationLengths are how many
+// leading characters of the fact's UUID follow it, tried shortest first until
+// the handle is free in the group.
+const citationPrefix = "F-"
+
+var citationLengths = []int{8, 12, 16}
+
+// IsCitationHandle reports whether s has the form of a citation handle.
+func IsCitationHandle(s string) bool {
+	return strings.HasPrefix(s, citationPrefix) && len(s) > len(citationPrefix)
+}
+
+// citationHandle picks the short handle answers can cite a new fact by, such
+// as "F-3f2a9c1b". It is stored with the fact, so it never changes; when the
+// shortest one is taken in the group, a longer prefix of the UUID is used.
+// Callers hold g.citations until the fact is saved.
+func (g *Graphiti) citationHandle(ctx context.Context, groupID, uuid string) (string, error) {
+	for _, n := range citationLengths {
+		if n >= len(uuid) {
+			break
+		}
+		handle := citationPrefix + uuid[:n]
+		owner, err := g.citationOwner(ctx, groupID, handle)
+		if err != nil {
+			return "", err
+		}
+		if owner == "" {
+			return handle, nil
+		}
+	}
+	return citationPrefix + uuid, nil
+}
+
+// citationOwner returns the UUID of the group's fact with handle, or "".
+func (g *Graphiti) citationOwner(ctx context.Context, groupID, handle string) (string, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityEdgeByCitationQuery, map[string]interface{}{
+		"group_id": groupID,
+		"citation": handle,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up citation %s: %w", handle, err)
+	}
+	matches, err := driver.ScanRecords[struct {
+		UUID string `db:"uuid"`
+	}](res)
+	if err != nil {
+		return "", fmt.Errorf("failed to read citation %s: %w", handle, err)
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	return matches[0].UUID, nil
+}
+
+// ResolveCitation returns the group's fact a citation handle refers to, or
+// ErrFactNotFound.
+func (g *Graphiti) ResolveCitation(ctx context.Context, groupID, handle string) (*model.EntityEdge, error) {
+	if groupID == "" {
+		return nil, ErrCitationGroupRequired
+	}
+	if !IsCitationHandle(handle) {
+		return nil, ErrFactNotFound
+	}
+	uuid, err := g.citationOwner(ctx, groupID, handle)
+	if err != nil {
+		return nil, err
+	}
+	if uuid == "" {
+		return nil, ErrFactNotFound
+	}
+	return g.GetFact(ctx, uuid)
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCitations(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	seedEntity(t, g.Driver, "g2", "carol", map[string]interface{}{"attributes": "{}"})
+	for _, e := range []struct{ uuid, group, source, target string }{
+		{"3f2a9c1b-0000-4000-8000-000000000001", "g1", "alice", "bob"},
+		{"3f2a9c1b-0000-4000-8000-000000000002", "g1", "bob", "alice"},
+		{"3f2a9c1b-0000-4000-8000-000000000003", "g2", "carol", "carol"},
+	} {
+		require.NoError(t, g.saveEntityEdge(ctx, e.group, map[string]interface{}{
+			"uuid": e.uuid, "source_uuid": e.source, "target_uuid": e.target, "name": "KNOWS", "fact": "cited fact " + e.uuid,
+			"group_id": e.group, "valid_at": "2024-01-01T00:00:00Z", "created_at": "2024-01-01T00:00:00Z", "invalid_at": "",
+		}))
+	}
+
+	// A prefix taken in the group gets a longer handle; other groups don't count
+	edges, err := g.Search(ctx, "g1", "cited fact")
+	require.NoError(t, err)
+	citations := make(map[string]string)
+	for _, e := range edges {
+		citations[e.UUID] = e.Citation
+	}
+	assert.Equal(t, map[string]string{
+		"3f2a9c1b-0000-4000-8000-000000000001": "F-3f2a9c1b",
+		"3f2a9c1b-0000-4000-8000-000000000002": "F-3f2a9c1b-000",
+	}, citations)
+
+	fact, err := g.ResolveCitation(ctx, "g1", "F-3f2a9c1b-000")
+	require.NoError(t, err)
+	assert.Equal(t, "3f2a9c1b-0000-4000-8000-000000000002", fact.UUID)
+	assert.Equal(t, "F-3f2a9c1b-000", fact.Citation)
+
+	fact, err = g.ResolveCitation(ctx, "g2", "F-3f2a9c1b")
+	require.NoError(t, err)
+	assert.Equal(t, "3f2a9c1b-0000-4000-8000-000000000003", fact.UUID)
+
+	_, err = g.ResolveCitation(ctx, "", "F-3f2a9c1b")
+	assert.ErrorIs(t, err, ErrCitationGroupRequired)
+	_, err = g.ResolveCitation(ctx, "g1", "F-00000000")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+	_, err = g.ResolveCitation(ctx, "g1", "F-")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+}
@@ -4,8 +4,24 @@ import (
 	"github.com/agenthands/carbon/internal/core/model"
 )
 
+// EdgeWeight is how strongly an edge ties its two entities. A nil EdgeWeight
+// weighs every edge 1; edges weighing 0 or less are ignored.
+type EdgeWeight func(e model.EntityEdge) float64
+
+// MentionWeight weighs a fact by the number of episodes that stated it.
+func MentionWeight(e model.EntityEdge) float64 {
+	return float64(max(e.MentionCount, 1))
+}
+
+func (w EdgeWeight) of(e model.EntityEdge) float64 {
+	if w == nil {
+		return 1
+	}
+	return w(e)
+}
+
 type CommunityDetector interface {
-	Detect(nodes []model.EntityNode, edges []model.EntityEdge) ([][]model.EntityNode, error)
+	Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight EdgeWeight) ([][]model.EntityNode, error)
 }
 
 type SimpleDetector struct {}
@@ -14,7 +30,8 @@ func NewSimpleDetector() CommunityDetector {
 	return NewLabelPropagationDetector()
 }
 
-func (d *SimpleDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge) ([][]model.EntityNode, error) {
+// Detect returns the connected components; any positive weight connects.
+func (d *SimpleDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight EdgeWeight) ([][]model.EntityNode, error) {
 	nodeMap := make(map[string]model.EntityNode)
 	adj := make(map[string][]string)
 	
@@ -31,6 +48,9 @@ func (d *SimpleDetector) Detect(nodes []model.EntityNode, edges []model.EntityEd
 		if _, ok := nodeMap[e.TargetUUID]; !ok {
 			continue
 		}
+		if weight.of(e) <= 0 {
+			continue
+		}
 
 		adj[e.SourceUUID] = append(adj[e.SourceUUID], e.TargetUUID)
 		adj[e.TargetUUID] = append(adj[e.TargetUUID], e.SourceUUID)
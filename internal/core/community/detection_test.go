@@ -22,7 +22,7 @@ func TestDetect(t *testing.T) {
 	}
 
 	detector := NewSimpleDetector()
-	communities, err := detector.Detect(nodes, edges)
+	communities, err := detector.Detect(nodes, edges, nil)
 
 	assert.NoError(t, err)
 	// Expect A-B-C as one community. D is size 1, so filtered out.
@@ -51,7 +51,7 @@ func TestDetect_MultipleCommunities(t *testing.T) {
 	}
 	
 	detector := NewSimpleDetector()
-	communities, err := detector.Detect(nodes, edges)
+	communities, err := detector.Detect(nodes, edges, nil)
 	
 	assert.NoError(t, err)
 	assert.Len(t, communities, 2)
@@ -17,30 +17,31 @@ func NewLabelPropagationDetector() *LabelPropagationDetector {
 	}
 }
 
-func (d *LabelPropagationDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge) ([][]model.EntityNode, error) {
+func (d *LabelPropagationDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight EdgeWeight) ([][]model.EntityNode, error) {
 	if len(nodes) == 0 {
 		return nil, nil
 	}
 
 	// 1. Initialize Adjacency List (Graph)
-	// Weighted by edge count between nodes (simple weighting for now: 1 edge = weight 1)
-	// Or use edge attributes/frequency if available. 
-	// For now, simple undirected unweighted graph, but we count multiple edges as stronger connection.
+	// Undirected, weighted by the sum of the weights of the edges between two
+	// nodes, so multiple or often-repeated facts are a stronger connection.
 	
-	adj := make(map[string]map[string]int) // node -> neighbor -> weight
+	adj := make(map[string]map[string]float64) // node -> neighbor -> weight
 	nodeMap := make(map[string]model.EntityNode)
 	
 	for _, n := range nodes {
 		nodeMap[n.UUID] = n
-		adj[n.UUID] = make(map[string]int)
+		adj[n.UUID] = make(map[string]float64)
 	}
 
 	for _, e := range edges {
 		if _, ok := nodeMap[e.SourceUUID]; !ok { continue }
 		if _, ok := nodeMap[e.TargetUUID]; !ok { continue }
+		w := weight.of(e)
+		if w <= 0 { continue }
 		
-		adj[e.SourceUUID][e.TargetUUID]++
-		adj[e.TargetUUID][e.SourceUUID]++ // Undirected
+		adj[e.SourceUUID][e.TargetUUID] += w
+		adj[e.TargetUUID][e.SourceUUID] += w // Undirected
 	}
 
 	// 2. Initialize Labels
@@ -70,8 +71,8 @@ func (d *LabelPropagationDetector) Detect(nodes []model.EntityNode, edges []mode
 			}
 
 			// Count label frequencies among neighbors weighted by edge weight
-			labelCounts := make(map[string]int)
-			maxCount := 0
+			labelCounts := make(map[string]float64)
+			maxCount := 0.0
 			
 			for v, weight := range neighbors {
 				label := labels[v]
@@ -1,6 +1,7 @@
 package community
 
 import (
+	"slices"
 	"sort"
 	"testing"
 
@@ -21,7 +22,7 @@ func TestLPA_DisconnectedComponents(t *testing.T) {
 	}
 
 	detector := NewLabelPropagationDetector()
-	communities, err := detector.Detect(nodes, edges)
+	communities, err := detector.Detect(nodes, edges, nil)
 	assert.NoError(t, err)
 
 	assert.Len(t, communities, 2)
@@ -58,7 +59,7 @@ func TestLPA_BridgeNode(t *testing.T) {
 	}
 
 	detector := NewLabelPropagationDetector()
-	communities, err := detector.Detect(nodes, edges)
+	communities, err := detector.Detect(nodes, edges, nil)
 	assert.NoError(t, err)
 
 	// Could be 1 or 2 depending on propagation.
@@ -85,9 +86,49 @@ func TestLPA_LargeClique(t *testing.T) {
 	}
 
 	detector := NewLabelPropagationDetector()
-	communities, err := detector.Detect(nodes, edges)
+	communities, err := detector.Detect(nodes, edges, nil)
 	assert.NoError(t, err)
 
 	assert.Len(t, communities, 1)
 	assert.Len(t, communities[0], 5)
 }
+
+func TestLPA_WeightedEdges(t *testing.T) {
+	// Triangles [1-2-3] and [4-5-6], with 0 stated alongside both: once each
+	// with 4 and 5, and with 1 in as many episodes as triangle 1-2-3's facts.
+	nodes := []model.EntityNode{
+		{UUID: "1"}, {UUID: "2"}, {UUID: "3"},
+		{UUID: "4"}, {UUID: "5"}, {UUID: "6"},
+		{UUID: "0"},
+	}
+	edges := []model.EntityEdge{
+		{SourceUUID: "1", TargetUUID: "2", MentionCount: 3}, {SourceUUID: "2", TargetUUID: "3", MentionCount: 3}, {SourceUUID: "3", TargetUUID: "1", MentionCount: 3},
+		{SourceUUID: "4", TargetUUID: "5"}, {SourceUUID: "5", TargetUUID: "6"}, {SourceUUID: "6", TargetUUID: "4"},
+		{SourceUUID: "0", TargetUUID: "1", MentionCount: 3},
+		{SourceUUID: "0", TargetUUID: "4"}, {SourceUUID: "0", TargetUUID: "5"},
+	}
+	communityOf := func(communities [][]model.EntityNode, uuid string) []string {
+		for _, c := range communities {
+			var uuids []string
+			for _, n := range c {
+				uuids = append(uuids, n.UUID)
+			}
+			sort.Strings(uuids)
+			if slices.Contains(uuids, uuid) {
+				return uuids
+			}
+		}
+		return nil
+	}
+
+	// Unweighted, two facts outweigh one
+	detector := NewLabelPropagationDetector()
+	communities, err := detector.Detect(nodes, edges, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "4", "5", "6"}, communityOf(communities, "0"))
+
+	communities, err = detector.Detect(nodes, edges, MentionWeight)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, communityOf(communities, "0"))
+	assert.Equal(t, []string{"4", "5", "6"}, communityOf(communities, "4"))
+}
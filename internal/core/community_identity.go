@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// savedCommunity is a community stored by an earlier detection.
+type savedCommunity struct {
+	UUID      string    `db:"uuid"`
+	Name      string    `db:"name"`
+	Summary   string    `db:"summary"`
+	CreatedAt time.Time `db:"created_at"`
+	Members   []string  `db:"members"`
+}
+
+// communityThresholds returns the [community] member overlaps at which a
+// detected community continues an existing one and keeps its name and summary.
+func (g *Graphiti) communityThresholds() (match, stable float64) {
+	match, stable = 0.5, 0.8
+	if g.Config != nil {
+		c := g.Config.Community
+		if c.MatchThreshold > 0 {
+			match = c.MatchThreshold
+		}
+		if c.StableThreshold > 0 {
+			stable = c.StableThreshold
+		}
+	}
+	return match, stable
+}
+
+func (g *Graphiti) getGroupCommunities(ctx context.Context, groupID string) ([]savedCommunity, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupCommunitiesQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch communities: %w", err)
+	}
+	communities, err := driver.ScanRecords[savedCommunity](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read communities: %w", err)
+	}
+	return communities, nil
+}
+
+// ListCommunities returns the group's saved communities with their summaries.
+func (g *Graphiti) ListCommunities(ctx context.Context, groupID string) ([]model.CommunityNode, error) {
+	saved, err := g.getGroupCommunities(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	communities := make([]model.CommunityNode, len(saved))
+	for i, c := range saved {
+		communities[i] = model.CommunityNode{UUID: c.UUID, Name: c.Name, GroupID: groupID, CreatedAt: c.CreatedAt, Summary: c.Summary}
+	}
+	return communities, nil
+}
+
+func memberUUIDs(nodes []model.EntityNode) []string {
+	uuids := make([]string, len(nodes))
+	for i, n := range nodes {
+		uuids[i] = n.UUID
+	}
+	return uuids
+}
+
+// jaccard is the share of the members of a and b that both have.
+func jaccard(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, uuid := range a {
+		set[uuid] = true
+	}
+	shared, union := 0, len(set)
+	seen := make(map[string]bool, len(b))
+	for _, uuid := range b {
+		if seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		if set[uuid] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// matchCommunities pairs detected communities, by index, with the existing
+// ones they continue: the pairs overlapping most are matched first, each
+// community at most once, and pairs under threshold never.
+func matchCommunities(existing []savedCommunity, detected [][]model.EntityNode, threshold float64) map[int]savedCommunity {
+	type pair struct {
+		detected, existing int
+		overlap            float64
+	}
+	var pairs []pair
+	for i, nodes := range detected {
+		members := memberUUIDs(nodes)
+		for j, c := range existing {
+			if overlap := jaccard(c.Members, members); overlap >= threshold && overlap > 0 {
+				pairs = append(pairs, pair{i, j, overlap})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].overlap > pairs[b].overlap })
+
+	matches := make(map[int]savedCommunity)
+	taken := make(map[int]bool)
+	for _, p := range pairs {
+		if _, ok := matches[p.detected]; ok || taken[p.existing] {
+			continue
+		}
+		matches[p.detected] = existing[p.existing]
+		taken[p.existing] = true
+	}
+	return matches
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// ErrDetectionRunning is returned by DetectAndSummarizeCommunities while
+// another detection of the same group is running.
+var ErrDetectionRunning = errors.New("community detection already running")
+
+// ErrDetectionNotFound is returned for groups with no detection run on this server.
+var ErrDetectionNotFound = errors.New("community detection not found")
+
+// detectionRuns holds the latest community detection of each group so that
+// only one runs per group at a time.
+type detectionRuns struct {
+	mu   sync.Mutex
+	runs map[string]*detectionRun
+}
+
+type detectionRun struct {
+	status model.CommunityDetection
+	done   chan struct{} // Closed when the run finishes
+}
+
+func newDetectionRuns() *detectionRuns {
+	return &detectionRuns{runs: make(map[string]*detectionRun)}
+}
+
+// start registers a running detection of groupID, or returns
+// ErrDetectionRunning if one already is. A nil registry tracks nothing.
+func (r *detectionRuns) start(groupID string) (*detectionRun, error) {
+	run := &detectionRun{
+		status: model.CommunityDetection{GroupID: groupID, Status: model.JobStatusRunning, StartedAt: time.Now().UTC()},
+		done:   make(chan struct{}),
+	}
+	if r == nil {
+		return run, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur, ok := r.runs[groupID]; ok && cur.status.Status == model.JobStatusRunning {
+		return nil, ErrDetectionRunning
+	}
+	r.runs[groupID] = run
+	return run, nil
+}
+
+// finish records the outcome of run and wakes its waiters.
+func (r *detectionRuns) finish(run *detectionRun, communities int, err error) {
+	if r != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	now := time.Now().UTC()
+	run.status.FinishedAt = &now
+	run.status.Communities = communities
+	run.status.Status = model.JobStatusCompleted
+	if err != nil {
+		run.status.Status = model.JobStatusFailed
+		run.status.Error = err.Error()
+	}
+	close(run.done)
+}
+
+// get returns the latest run of groupID and a copy of its status.
+func (r *detectionRuns) get(groupID string) (*detectionRun, model.CommunityDetection, bool) {
+	if r == nil {
+		return nil, model.CommunityDetection{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[groupID]
+	if !ok {
+		return nil, model.CommunityDetection{}, false
+	}
+	return run, run.status, true
+}
+
+// GetCommunityDetection returns the group's running or latest finished
+// community detection, or ErrDetectionNotFound. Runs are tracked in memory,
+// per server.
+func (g *Graphiti) GetCommunityDetection(groupID string) (*model.CommunityDetection, error) {
+	_, status, ok := g.communityRuns.get(groupID)
+	if !ok {
+		return nil, ErrDetectionNotFound
+	}
+	return &status, nil
+}
+
+// WaitCommunityDetection waits for the group's running community detection to
+// finish and returns its outcome; a finished one is returned immediately.
+func (g *Graphiti) WaitCommunityDetection(ctx context.Context, groupID string) (*model.CommunityDetection, error) {
+	run, _, ok := g.communityRuns.get(groupID)
+	if !ok {
+		return nil, ErrDetectionNotFound
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-run.done:
+	}
+	g.communityRuns.mu.Lock()
+	defer g.communityRuns.mu.Unlock()
+	status := run.status
+	return &status, nil
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/community"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDetector keeps the edges it is asked to cluster.
+type recordingDetector struct{ edges []model.EntityEdge }
+
+func (d *recordingDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight community.EdgeWeight) ([][]model.EntityNode, error) {
+	d.edges = edges
+	return nil, nil
+}
+
+func TestDetectCommunities_CoMentions(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	for _, uuid := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: uuid, Name: uuid, GroupID: "g1"}))
+	}
+	for i, m := range []struct{ episode, entity string }{
+		{"ep1", "alice"}, {"ep1", "bob"},
+		{"ep2", "alice"}, {"ep2", "bob"}, {"ep2", "carol"},
+	} {
+		require.NoError(t, g.saveEpisodeNode(ctx, m.episode, m.episode, "g1", "hi", time.Now()))
+		_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+			"uuid": fmt.Sprintf("m%d", i), "source_uuid": m.episode, "target_uuid": m.entity, "group_id": "g1",
+		})
+		require.NoError(t, err)
+	}
+	detector := &recordingDetector{}
+	g.CommunityDetector = detector
+
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	assert.Empty(t, detector.edges)
+
+	g.Config.Community.CoMentions = true
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	require.Len(t, detector.edges, 1)
+	e := detector.edges[0]
+	assert.Equal(t, "alice", e.SourceUUID)
+	assert.Equal(t, "bob", e.TargetUUID)
+	assert.Equal(t, model.CoMentionRelation, e.Name)
+	assert.Equal(t, 2, e.MentionCount)
+
+	g.Config.Community.MinCoMentions = 1
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	assert.Len(t, detector.edges, 3)
+}
+
+// blockingDetector holds each detection until release is closed.
+type blockingDetector struct{ started, release chan struct{} }
+
+func (d *blockingDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight community.EdgeWeight) ([][]model.EntityNode, error) {
+	d.started <- struct{}{}
+	<-d.release
+	return nil, nil
+}
+
+func TestDetectCommunities_OnePerGroup(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	detector := &blockingDetector{started: make(chan struct{}, 2), release: make(chan struct{})}
+	g.CommunityDetector = detector
+
+	_, err := g.GetCommunityDetection("g1")
+	assert.ErrorIs(t, err, ErrDetectionNotFound)
+
+	done := make(chan error)
+	go func() { done <- g.DetectAndSummarizeCommunities(ctx, "g1") }()
+	<-detector.started
+
+	status, err := g.GetCommunityDetection("g1")
+	require.NoError(t, err)
+	assert.Equal(t, model.JobStatusRunning, status.Status)
+	assert.Nil(t, status.FinishedAt)
+	assert.ErrorIs(t, g.DetectAndSummarizeCommunities(ctx, "g1"), ErrDetectionRunning)
+
+	// Other groups are not held up
+	go func() { done <- g.DetectAndSummarizeCommunities(ctx, "g2") }()
+	<-detector.started
+
+	waited := make(chan *model.CommunityDetection)
+	go func() {
+		status, err := g.WaitCommunityDetection(ctx, "g1")
+		assert.NoError(t, err)
+		waited <- status
+	}()
+	close(detector.release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+	status = <-waited
+	assert.Equal(t, model.JobStatusCompleted, status.Status)
+	assert.NotNil(t, status.FinishedAt)
+
+	// Finished detections no longer block new ones
+	go func() { <-detector.started }()
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+}
+
+// fixedDetector finds the communities of entity UUIDs it is set to.
+type fixedDetector struct{ communities [][]string }
+
+func (d *fixedDetector) Detect(nodes []model.EntityNode, edges []model.EntityEdge, weight community.EdgeWeight) ([][]model.EntityNode, error) {
+	byUUID := make(map[string]model.EntityNode)
+	for _, n := range nodes {
+		byUUID[n.UUID] = n
+	}
+	var out [][]model.EntityNode
+	for _, uuids := range d.communities {
+		var members []model.EntityNode
+		for _, uuid := range uuids {
+			members = append(members, byUUID[uuid])
+		}
+		out = append(out, members)
+	}
+	return out, nil
+}
+
+func TestDetectCommunities_KeepsIdentity(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Summary: config.SummaryPrompts{Communities: "summarize %s", CommunityName: "name %s"}}
+	calls := 0
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		calls++
+		if strings.HasPrefix(prompt, "name") {
+			return fmt.Sprintf(`{"name": "Name %d"}`, calls)
+		}
+		return fmt.Sprintf(`{"summary": "Summary %d"}`, calls)
+	}), nil, nil, cfg)
+	for _, uuid := range []string{"a", "b", "c", "d", "e", "f", "x"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: uuid, Name: uuid, GroupID: "g1", Summary: "about " + uuid}))
+	}
+	detector := &fixedDetector{communities: [][]string{{"a", "b", "c", "d", "e"}, {"f", "x"}}}
+	g.CommunityDetector = detector
+
+	communities := func() map[string]savedCommunity {
+		saved, err := g.getGroupCommunities(ctx, "g1")
+		require.NoError(t, err)
+		byName := make(map[string]savedCommunity)
+		for _, c := range saved {
+			sort.Strings(c.Members)
+			byName[c.Name] = c
+		}
+		return byName
+	}
+
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	first := communities()
+	require.Len(t, first, 2)
+	require.Equal(t, 4, calls)
+
+	// Unchanged membership keeps name and summary without asking the LLM
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	assert.Equal(t, first, communities())
+	assert.Equal(t, 4, calls)
+
+	// One member more stays stable; two fewer keeps the UUID but is summarized again
+	detector.communities = [][]string{{"a", "b", "c", "d", "e", "x"}, {"a", "b", "c"}}
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	second := communities()
+	require.Len(t, second, 2)
+	big := second["Name 2"]
+	assert.Equal(t, first["Name 2"].UUID, big.UUID)
+	assert.Equal(t, first["Name 2"].Summary, big.Summary)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "x"}, big.Members)
+	assert.Equal(t, 6, calls)
+
+	// The dissolved community {f, x} is gone; the new one got a new identity
+	for name, c := range second {
+		assert.NotEqual(t, first["Name 4"].UUID, c.UUID, name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, second["Name 6"].Members)
+
+	// Shrinking {a..e, x} to {a, b, d, e} (overlap 4/6) continues it under a new name
+	detector.communities = [][]string{{"a", "b", "d", "e"}}
+	require.NoError(t, g.DetectAndSummarizeCommunities(ctx, "g1"))
+	third := communities()
+	require.Len(t, third, 1)
+	assert.Equal(t, big.UUID, third["Name 8"].UUID)
+	assert.Equal(t, big.CreatedAt, third["Name 8"].CreatedAt)
+}
+
+func TestMatchCommunities(t *testing.T) {
+	nodes := func(uuids ...string) []model.EntityNode {
+		var out []model.EntityNode
+		for _, uuid := range uuids {
+			out = append(out, model.EntityNode{UUID: uuid})
+		}
+		return out
+	}
+	existing := []savedCommunity{
+		{UUID: "c1", Members: []string{"a", "b", "c", "d"}},
+		{UUID: "c2", Members: []string{"a", "b"}},
+	}
+	// {a, b} overlaps both but c2 fully; {a, b, c} then takes c1 (3/4)
+	matches := matchCommunities(existing, [][]model.EntityNode{nodes("a", "b", "c"), nodes("a", "b"), nodes("y", "z")}, 0.5)
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "c1", matches[0].UUID)
+	assert.Equal(t, "c2", matches[1].UUID)
+
+	assert.InDelta(t, 0.75, jaccard([]string{"a", "b", "c", "d"}, []string{"a", "b", "c"}), 1e-9)
+	assert.Zero(t, jaccard(nil, nil))
+}
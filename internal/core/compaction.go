@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// compactionSettings returns the configured [compaction] settings with defaults applied.
+func (g *Graphiti) compactionSettings() (minAge time.Duration, keepRecent, runSize int) {
+	minAge, runSize = 30*24*time.Hour, 20
+	if g.Config != nil {
+		c := g.Config.Compaction
+		if c.MinAgeDays > 0 {
+			minAge = time.Duration(c.MinAgeDays) * 24 * time.Hour
+		}
+		keepRecent = c.KeepRecent
+		if c.RunSize > 0 {
+			runSize = c.RunSize
+		}
+	}
+	return minAge, keepRecent, runSize
+}
+
+// CompactEpisodes replaces each run of the group's old episodes with one digest
+// episode that the LLM writes from their content. Entities and facts the
+// episodes produced are kept: the digest mentions their entities and takes
+// their place in the facts' provenance. Only full runs of consecutive
+// episodes past the age cutoff and outside the most recent ones are
+// compacted, and earlier digests are never digested again. A dry run only
+// lists the runs.
+func (g *Graphiti) CompactEpisodes(ctx context.Context, groupID string, dryRun bool) (*model.CompactionReport, error) {
+	ctx = asBatch(ctx)
+	if group, err := g.GetGroup(ctx, groupID); err == nil {
+		g = g.forGroup(group)
+	}
+	runs, err := g.compactionRuns(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &model.CompactionReport{GroupID: groupID, RanAt: now, DryRun: dryRun, Digests: []model.EpisodeDigest{}}
+	if !dryRun && len(runs) > 0 {
+		defer g.invalidateSearchCache(ctx, groupID)
+	}
+	for _, run := range runs {
+		first, last := run[0], run[len(run)-1]
+		digest := model.EpisodeDigest{From: first.ValidAt, To: last.ValidAt}
+		for _, ep := range run {
+			digest.Episodes = append(digest.Episodes, ep.UUID)
+		}
+		if !dryRun {
+			if err := g.compactRun(ctx, groupID, run, &digest); err != nil {
+				return nil, err
+			}
+		}
+		report.Digests = append(report.Digests, digest)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if err := g.saveReport(ctx, model.ReportKindCompaction, groupID, now, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// compactionRuns returns the group's runs of episodes to compact, oldest
+// first. A digest between two episodes ends a run so that digests always
+// cover consecutive episodes.
+func (g *Graphiti) compactionRuns(ctx context.Context, groupID string) ([][]model.EpisodicNode, error) {
+	minAge, keepRecent, runSize := g.compactionSettings()
+	stats, err := g.GetGroupStats(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Episodes <= keepRecent {
+		return nil, nil
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"limit":    stats.Episodes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	episodes, err := driver.ScanRecords[model.EpisodicNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read episodes: %w", err)
+	}
+
+	// Newest first: leave the most recent and those within the cutoff
+	cutoff := time.Now().UTC().Add(-minAge)
+	episodes = episodes[min(keepRecent, len(episodes)):]
+	if i := slices.IndexFunc(episodes, func(ep model.EpisodicNode) bool { return ep.CreatedAt.Before(cutoff) }); i >= 0 {
+		episodes = episodes[i:]
+	} else {
+		return nil, nil
+	}
+	slices.Reverse(episodes)
+
+	var runs [][]model.EpisodicNode
+	var run []model.EpisodicNode
+	for _, ep := range episodes {
+		if ep.Source == model.EpisodeSourceDigest {
+			run = nil
+			continue
+		}
+		run = append(run, ep)
+		if len(run) == runSize {
+			runs = append(runs, run)
+			run = nil
+		}
+	}
+	return runs, nil
+}
+
+// compactRun saves the digest of run, moves the run's mentions and fact
+// provenance to it, then deletes the run's episodes.
+func (g *Graphiti) compactRun(ctx context.Context, groupID string, run []model.EpisodicNode, digest *model.EpisodeDigest) error {
+	var contents []string
+	for _, ep := range run {
+		content, _, err := g.readEpisodeContent(ctx, ep, true)
+		if err != nil {
+			return fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+		}
+		contents = append(contents, content)
+	}
+	text, err := g.Summarizer.DigestEpisodes(ctx, contents)
+	if err != nil {
+		return err
+	}
+
+	first, last := run[0], run[len(run)-1]
+	digest.UUID = g.UUIDGenerator()
+	// The digest takes the place of the run in the group's episode order
+	if err := g.saveEpisode(ctx, model.EpisodicNode{
+		UUID:              digest.UUID,
+		Name:              fmt.Sprintf("Digest of %d episodes", len(run)),
+		GroupID:           groupID,
+		CreatedAt:         last.CreatedAt,
+		ValidAt:           first.ValidAt,
+		Content:           text,
+		Source:            model.EpisodeSourceDigest,
+		SourceDescription: fmt.Sprintf("digest of episodes from %s to %s", first.ValidAt.Format(time.RFC3339), last.ValidAt.Format(time.RFC3339)),
+	}); err != nil {
+		return fmt.Errorf("failed to save digest episode: %w", err)
+	}
+
+	params := map[string]interface{}{"group_id": groupID, "uuids": digest.Episodes}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeMentionsQuery, params)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the mentions of episodes: %w", err)
+	}
+	now := time.Now().UTC()
+	for _, rec := range res.Records {
+		uuid, _ := rec.Get("uuid")
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+			"uuid":        g.UUIDGenerator(),
+			"source_uuid": digest.UUID,
+			"target_uuid": uuid,
+			"group_id":    groupID,
+			"created_at":  now.Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("failed to link digest episode: %w", err)
+		}
+		digest.Entities++
+	}
+
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeFactsQuery, params)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the facts of episodes: %w", err)
+	}
+	facts, err := driver.ScanRecords[struct {
+		UUID     string   `db:"uuid"`
+		Episodes []string `db:"episodes"`
+	}](res)
+	if err != nil {
+		return fmt.Errorf("failed to read the facts of episodes: %w", err)
+	}
+	for _, f := range facts {
+		episodes := slices.DeleteFunc(f.Episodes, func(uuid string) bool { return slices.Contains(digest.Episodes, uuid) })
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.SetEdgeEpisodesQuery, map[string]interface{}{
+			"uuid":     f.UUID,
+			"episodes": append(episodes, digest.UUID),
+		}); err != nil {
+			return fmt.Errorf("failed to move fact %s to digest episode: %w", f.UUID, err)
+		}
+		digest.Facts++
+	}
+
+	for _, ep := range run {
+		if err := g.DeleteEpisode(ctx, ep.UUID); err != nil && !errors.Is(err, ErrEpisodeNotFound) {
+			return fmt.Errorf("failed to delete compacted episode %s: %w", ep.UUID, err)
+		}
+	}
+	return nil
+}
+
+// GetCompactionReport returns the group's latest compaction report, or ErrReportNotFound.
+func (g *Graphiti) GetCompactionReport(ctx context.Context, groupID string) (*model.CompactionReport, error) {
+	var report model.CompactionReport
+	if err := g.loadReport(ctx, model.ReportKindCompaction, groupID, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// RunCompaction compacts the episodes of every group each interval until ctx is done.
+func (g *Graphiti) RunCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			groups, err := g.ListGroups(ctx)
+			if err != nil {
+				log.Printf("Compaction: failed to list groups: %v", err)
+				continue
+			}
+			for _, group := range groups {
+				report, err := g.CompactEpisodes(ctx, group.GroupID, false)
+				if err != nil {
+					log.Printf("Compaction failed for group %s: %v", group.GroupID, err)
+					continue
+				}
+				if len(report.Digests) > 0 {
+					log.Printf("Compaction: wrote %d digests in group %s", len(report.Digests), group.GroupID)
+				}
+			}
+		}
+	}
+}
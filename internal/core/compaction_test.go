@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactEpisodes(t *testing.T) {
+	ctx := context.Background()
+	var prompts []string
+	llmClient := llmFunc(func(prompt string) string {
+		prompts = append(prompts, prompt)
+		return `{"summary": "Alice moved to Lisbon."}`
+	})
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Summary:    config.SummaryPrompts{Episodes: "digest %s"},
+		Compaction: config.CompactionConfig{KeepRecent: 1, RunSize: 2},
+	})
+
+	old := time.Now().UTC().AddDate(0, 0, -100)
+	for i, uuid := range []string{"ep0", "ep1", "ep2", "ep3", "ep4"} {
+		require.NoError(t, g.saveEpisodeNode(ctx, uuid, uuid, "g1", "content of "+uuid, old.Add(time.Duration(i)*time.Hour)))
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "recent", "recent", "g1", "hi", time.Now().UTC()))
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "alice", Name: "Alice", GroupID: "g1", CreatedAt: old}))
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "lisbon", Name: "Lisbon", GroupID: "g1", CreatedAt: old}))
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+		"uuid": "m1", "source_uuid": "ep1", "target_uuid": "alice", "group_id": "g1",
+	})
+	require.NoError(t, err)
+	seedFact(t, g.Driver, "g1", "e1", "alice", "lisbon", "LIVES_IN", "Alice lives in Lisbon", map[string]interface{}{
+		"episodes": []string{"ep0", "recent"},
+	})
+
+	report, err := g.CompactEpisodes(ctx, "g1", true)
+	require.NoError(t, err)
+	require.Len(t, report.Digests, 2)
+	assert.Equal(t, []string{"ep0", "ep1"}, report.Digests[0].Episodes)
+	assert.Equal(t, []string{"ep2", "ep3"}, report.Digests[1].Episodes)
+	assert.Empty(t, prompts)
+
+	report, err = g.CompactEpisodes(ctx, "g1", false)
+	require.NoError(t, err)
+	require.Len(t, report.Digests, 2)
+	digest := report.Digests[0]
+	assert.Equal(t, 1, digest.Entities)
+	assert.Equal(t, 1, digest.Facts)
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[0], "[1] content of ep0\n[2] content of ep1")
+
+	// The digests replace the runs in place; ep4 waits for a full run
+	episodes, err := g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	var uuids []string
+	for _, ep := range episodes {
+		uuids = append(uuids, ep.UUID)
+	}
+	assert.Equal(t, []string{"recent", "ep4", report.Digests[1].UUID, digest.UUID}, uuids)
+	assert.Equal(t, model.EpisodeSourceDigest, episodes[3].Source)
+	assert.Equal(t, "Alice moved to Lisbon.", episodes[3].Content)
+
+	// Knowledge derived from the compacted episodes points at the digest
+	edge, err := g.GetFact(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"recent", digest.UUID}, edge.Episodes)
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeMentionsQuery, map[string]interface{}{"uuids": []string{digest.UUID}})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	mentioned, _ := res.Records[0].Get("uuid")
+	assert.Equal(t, "alice", mentioned)
+
+	stored, err := g.GetCompactionReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, stored.Digests, 2)
+
+	// Digests are not digested again and break runs
+	report, err = g.CompactEpisodes(ctx, "g1", false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Digests)
+}
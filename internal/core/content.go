@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/llm"
+)
+
+var ErrContentTooLarge = errors.New("episode content too large")
+
+// ContentLimit is the most characters an episode may hold under the [ingest]
+// character and token limits, or 0 when unlimited.
+func (g *Graphiti) ContentLimit() int {
+	if g.Config == nil {
+		return 0
+	}
+	limit := g.Config.Ingest.MaxContentChars
+	if tokens := g.Config.Ingest.MaxContentTokens; tokens > 0 && (limit == 0 || tokens*llm.CharsPerToken < limit) {
+		limit = tokens * llm.CharsPerToken
+	}
+	return limit
+}
+
+// CheckContent returns an ErrContentTooLarge error for content that ingesting
+// would reject under the [ingest] limits.
+func (g *Graphiti) CheckContent(content string) error {
+	_, err := g.fitContent(content)
+	return err
+}
+
+// fitContent applies the [ingest] overflow strategy to content and returns
+// the episode contents to ingest in its place.
+func (g *Graphiti) fitContent(content string) ([]string, error) {
+	limit := g.ContentLimit()
+	n := utf8.RuneCountInString(content)
+	if limit == 0 || n <= limit {
+		return []string{content}, nil
+	}
+	switch overflow := g.Config.Ingest.Overflow; overflow {
+	case "", model.OverflowReject:
+		return nil, fmt.Errorf("%w: %d characters (about %d tokens), the limit is %d characters",
+			ErrContentTooLarge, n, llm.EstimateTokens(content), limit)
+	case model.OverflowTruncate:
+		head, _ := splitContent(content, limit)
+		log.Printf("Truncated episode content from %d to %d characters", n, utf8.RuneCountInString(head))
+		return []string{head}, nil
+	case model.OverflowChunk:
+		var chunks []string
+		for content != "" {
+			var head string
+			head, content = splitContent(content, limit)
+			if head != "" {
+				chunks = append(chunks, head)
+			}
+		}
+		return chunks, nil
+	default:
+		return nil, fmt.Errorf("unknown ingest overflow %q", overflow)
+	}
+}
+
+// contentBoundaries are where splitContent prefers to cut, strongest first.
+var contentBoundaries = [][]string{{"\n\n"}, {"\n"}, {". ", "? ", "! "}, {" "}}
+
+// splitContent cuts s after at most limit characters, at the strongest
+// boundary in the second half of that window, and returns both parts trimmed.
+func splitContent(s string, limit int) (head, rest string) {
+	if utf8.RuneCountInString(s) <= limit {
+		return strings.TrimSpace(s), ""
+	}
+	cut := 0
+	for i := 0; i < limit; i++ {
+		_, size := utf8.DecodeRuneInString(s[cut:])
+		cut += size
+	}
+	window := s[:cut]
+	for _, seps := range contentBoundaries {
+		best := -1
+		for _, sep := range seps {
+			if i := strings.LastIndex(window, sep); i >= 0 && i+len(sep) > best {
+				best = i + len(sep)
+			}
+		}
+		if best > len(window)/2 {
+			cut = best
+			break
+		}
+	}
+	return strings.TrimSpace(s[:cut]), strings.TrimSpace(s[cut:])
+}
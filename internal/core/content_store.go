@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var (
+	ErrNoContentStore    = errors.New("episode content is offloaded but no content store is configured")
+	ErrForeignContentRef = errors.New("episode content reference points outside the episode")
+)
+
+// NewContentStore opens the [content_store] store, or returns nil when none is configured.
+func NewContentStore(cfg config.ContentStoreConfig) (blob.Store, error) {
+	if cfg.Store == (config.BlobStoreConfig{}) {
+		return nil, nil
+	}
+	return blob.New(cfg.Store)
+}
+
+func episodeContentPrefix(groupID string) string {
+	return "episodes/" + keySegment(groupID) + "/"
+}
+
+func episodeContentKey(groupID, uuid string) string {
+	return episodeContentPrefix(groupID) + keySegment(uuid)
+}
+
+// offloadEpisode moves stored (the episode's content as written, possibly
+// encrypted) to the content store when plain is over the threshold. It returns
+// what the graph should hold as content and, when offloaded, the store key.
+func (g *Graphiti) offloadEpisode(ctx context.Context, groupID, uuid, plain, stored string) (content, ref string, err error) {
+	threshold := g.Config.ContentStore.ThresholdChars
+	if g.ContentStore == nil || threshold <= 0 || utf8.RuneCountInString(plain) <= threshold {
+		return stored, "", nil
+	}
+	key := episodeContentKey(groupID, uuid)
+	if err := g.ContentStore.Put(ctx, key, []byte(stored)); err != nil {
+		return "", "", fmt.Errorf("failed to offload episode content: %w", err)
+	}
+	return "", key, nil
+}
+
+// contentRef returns ep's content store key, or ErrForeignContentRef when the
+// key is not the one offloadEpisode derives from the episode's group and UUID.
+func contentRef(ep model.EpisodicNode) (string, error) {
+	if ep.ContentRef == "" {
+		return "", nil
+	}
+	if ep.ContentRef != episodeContentKey(ep.GroupID, ep.UUID) {
+		return "", ErrForeignContentRef
+	}
+	return ep.ContentRef, nil
+}
+
+// readEpisodeContent decrypts an episode's stored content. Offloaded content is
+// only fetched when resolve is set; otherwise it comes back empty with its key.
+func (g *Graphiti) readEpisodeContent(ctx context.Context, ep model.EpisodicNode, resolve bool) (content, ref string, err error) {
+	if ref, err = contentRef(ep); err != nil {
+		return "", "", err
+	}
+	if ref == "" {
+		content, err = g.decryptEpisode(ep.UUID, ep.Content)
+		return content, "", err
+	}
+	if !resolve {
+		return "", ref, nil
+	}
+	if g.ContentStore == nil {
+		return "", ref, ErrNoContentStore
+	}
+	data, err := g.ContentStore.Get(ctx, ref)
+	if err != nil {
+		return "", ref, fmt.Errorf("failed to fetch episode content: %w", err)
+	}
+	content, err = g.decryptEpisode(ep.UUID, string(data))
+	return content, ref, err
+}
+
+// deleteEpisodeContent removes the offloaded content of an episode, if any.
+func (g *Graphiti) deleteEpisodeContent(ctx context.Context, ep model.EpisodicNode) error {
+	ref, err := contentRef(ep)
+	if err != nil || ref == "" || g.ContentStore == nil {
+		return err
+	}
+	return g.ContentStore.Delete(ctx, ref)
+}
+
+// exportedEpisode reads the fields of an exported episode's properties that
+// locate its offloaded content.
+func exportedEpisode(props map[string]interface{}) model.EpisodicNode {
+	var ep model.EpisodicNode
+	ep.UUID, _ = props["uuid"].(string)
+	ep.GroupID, _ = props["group_id"].(string)
+	ep.ContentRef, _ = props["content_ref"].(string)
+	return ep
+}
+
+// inlineEpisodeContent copies an exported episode's offloaded content, as
+// stored, into its content property so the export holds it. Content already
+// missing from the store is left out, so the rest can still be snapshotted.
+func (g *Graphiti) inlineEpisodeContent(ctx context.Context, props map[string]interface{}) error {
+	ep := exportedEpisode(props)
+	ref, err := contentRef(ep)
+	if err != nil || ref == "" {
+		return err
+	}
+	if g.ContentStore == nil {
+		return ErrNoContentStore
+	}
+	data, err := g.ContentStore.Get(ctx, ref)
+	if errors.Is(err, blob.ErrNotFound) {
+		log.Printf("Exporting episode %s without its offloaded content: %v", ep.UUID, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch content of episode %s: %w", ep.UUID, err)
+	}
+	props["content"] = string(data)
+	return nil
+}
+
+// restoreEpisodeContent moves the content inlined by inlineEpisodeContent
+// back to the content store, or keeps it in the graph when there is none.
+func (g *Graphiti) restoreEpisodeContent(ctx context.Context, props map[string]interface{}) error {
+	ep := exportedEpisode(props)
+	ref, err := contentRef(ep)
+	if err != nil || ref == "" {
+		return err
+	}
+	content, _ := props["content"].(string)
+	if content == "" {
+		return nil // Not inlined; the store may still have it
+	}
+	if g.ContentStore == nil {
+		delete(props, "content_ref")
+		return nil
+	}
+	if err := g.ContentStore.Put(ctx, ref, []byte(content)); err != nil {
+		return fmt.Errorf("failed to restore content of episode %s: %w", ep.UUID, err)
+	}
+	props["content"] = ""
+	return nil
+}
+
+// deleteGroupContent removes the offloaded content of a group's episodes, or
+// of every group when groupID is empty.
+func (g *Graphiti) deleteGroupContent(ctx context.Context, groupID string) error {
+	if g.ContentStore == nil {
+		return nil
+	}
+	prefix := "episodes/"
+	if groupID != "" {
+		prefix = episodeContentPrefix(groupID)
+	}
+	objects, err := g.ContentStore.List(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list offloaded content: %w", err)
+	}
+	var errs []error
+	for _, obj := range objects {
+		if err := g.ContentStore.Delete(ctx, obj.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetProvenance returns a fact with the episodes it was extracted from, oldest
+// first, with offloaded content fetched from the content store.
+func (g *Graphiti) GetProvenance(ctx context.Context, factUUID string) (*model.Provenance, error) {
+	fact, err := g.GetFact(ctx, factUUID)
+	if err != nil {
+		return nil, err
+	}
+	prov := &model.Provenance{Fact: *fact, Episodes: []model.EpisodicNode{}}
+	if len(fact.Episodes) == 0 {
+		return prov, nil
+	}
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesByUUIDQuery, map[string]interface{}{
+		"uuids": fact.Episodes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	episodes, err := driver.ScanRecords[model.EpisodicNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read episodes: %w", err)
+	}
+	for i := range episodes {
+		ep := &episodes[i]
+		if ep.Content, ep.ContentRef, err = g.readEpisodeContent(ctx, *ep, true); err != nil {
+			return nil, fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+		}
+	}
+	prov.Episodes = episodes
+	return prov, nil
+}
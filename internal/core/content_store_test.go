@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContentStore(t *testing.T) {
+	store, err := NewContentStore(config.ContentStoreConfig{ThresholdChars: 10})
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	store, err = NewContentStore(config.ContentStoreConfig{Store: config.BlobStoreConfig{Type: "file", Dir: t.TempDir()}})
+	require.NoError(t, err)
+	assert.NotNil(t, store)
+}
+
+func TestContentStoreOffload(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	store := blob.NewFileStore(t.TempDir())
+	g.ContentStore = store
+	g.Config.ContentStore.ThresholdChars = 10
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	long := "Alice works at Acme in Paris."
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", "Short.", now))
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep2", "ep2", "g1", long, now.Add(time.Hour)))
+	seedFact(t, g.Driver, "g1", "e4", "alice", "acme", "WORKS_AT", "Alice works at Acme", map[string]interface{}{
+		"valid_at": "2024-01-10T00:00:00Z", "created_at": "2024-01-10T00:00:00Z", "episodes": []string{"ep2", "ep1"},
+	})
+
+	// The graph holds a reference; the content is in the store
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{"group_id": "g1", "limit": 1})
+	require.NoError(t, err)
+	content, _ := res.Records[0].Get("content")
+	assert.Equal(t, "", content)
+	ref, _ := res.Records[0].Get("content_ref")
+	assert.Equal(t, "episodes/g1/ep2", ref)
+	data, err := store.Get(ctx, "episodes/g1/ep2")
+	require.NoError(t, err)
+	assert.Equal(t, long, string(data))
+
+	episodes, err := g.GetEpisodes(ctx, "g1", 2)
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+	assert.Equal(t, "", episodes[0].Content)
+	assert.Equal(t, "episodes/g1/ep2", episodes[0].ContentRef)
+	assert.Equal(t, "Short.", episodes[1].Content)
+	assert.Equal(t, "", episodes[1].ContentRef)
+
+	previous, err := g.retrievePreviousEpisodes(ctx, "g1", "", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Short."}, previous)
+
+	prov, err := g.GetProvenance(ctx, "e4")
+	require.NoError(t, err)
+	assert.Equal(t, "e4", prov.Fact.UUID)
+	require.Len(t, prov.Episodes, 2)
+	assert.Equal(t, "ep1", prov.Episodes[0].UUID)
+	assert.Equal(t, "Short.", prov.Episodes[0].Content)
+	assert.Equal(t, "ep2", prov.Episodes[1].UUID)
+	assert.Equal(t, long, prov.Episodes[1].Content)
+	assert.Equal(t, "episodes/g1/ep2", prov.Episodes[1].ContentRef)
+
+	_, err = g.GetProvenance(ctx, "missing")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+
+	g.ContentStore = nil
+	_, err = g.GetProvenance(ctx, "e4")
+	assert.ErrorIs(t, err, ErrNoContentStore)
+	g.ContentStore = store
+
+	require.NoError(t, g.DeleteEpisode(ctx, "ep2"))
+	_, err = store.Get(ctx, "episodes/g1/ep2")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep3", "ep3", "g1", long, now))
+	_, err = g.DeleteGroup(ctx, "g1")
+	require.NoError(t, err)
+	objects, err := store.List(ctx, "episodes/")
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestContentStoreEncrypted(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewAESCipher(make([]byte, 32))
+	require.NoError(t, err)
+	g := filterTestGraph(t)
+	g.Cipher = cipher
+	g.Config.Encryption.EpisodeContent = true
+	g.ContentStore = blob.NewFileStore(t.TempDir())
+	g.Config.ContentStore.ThresholdChars = 10
+
+	long := "Alice has the flu and stays home."
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", long, time.Now()))
+	data, err := g.ContentStore.Get(ctx, "episodes/g1/ep1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), encryptedPrefix))
+
+	content, ref, err := g.readEpisodeContent(ctx, model.EpisodicNode{UUID: "ep1", GroupID: "g1", ContentRef: "episodes/g1/ep1"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, long, content)
+	assert.Equal(t, "episodes/g1/ep1", ref)
+}
+
+func TestContentStoreRejectsForeignReferences(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	store := blob.NewFileStore(t.TempDir())
+	g.ContentStore = store
+	g.Config.ContentStore.ThresholdChars = 10
+
+	secret := "Victim's private notes on the merger."
+	require.NoError(t, g.saveEpisodeNode(ctx, "victim-ep", "victim-ep", "victim", secret, time.Now()))
+
+	// Content that looks like a pointer is just content
+	spoof := "blob:v1:episodes/victim/victim-ep"
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, map[string]interface{}{
+		"uuid": "ep1", "group_id": "attacker", "content": spoof, "created_at": time.Now().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	ep, err := g.GetEpisode(ctx, "ep1")
+	require.NoError(t, err)
+	assert.Equal(t, spoof, ep.Content)
+	assert.Equal(t, "", ep.ContentRef)
+
+	// A reference to another episode's key is refused, on read and delete
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, map[string]interface{}{
+		"uuid": "ep2", "group_id": "attacker", "content_ref": "episodes/victim/victim-ep", "created_at": time.Now().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	_, err = g.GetEpisode(ctx, "ep2")
+	assert.ErrorIs(t, err, ErrForeignContentRef)
+
+	require.NoError(t, g.DeleteEpisode(ctx, "ep1"))
+	assert.ErrorIs(t, g.DeleteEpisode(ctx, "ep2"), ErrForeignContentRef)
+	data, err := store.Get(ctx, "episodes/victim/victim-ep")
+	require.NoError(t, err)
+	assert.Equal(t, secret, string(data))
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contentTestGraph(ingest config.IngestConfig) *Graphiti {
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}, Ingest: ingest}
+	return NewGraphiti(driver.NewMemoryDriver(), &flakyLLM{marker: "never"}, nil, nil, cfg)
+}
+
+func TestSplitContent(t *testing.T) {
+	head, rest := splitContent("First paragraph.\n\nSecond one is longer.", 30)
+	assert.Equal(t, "First paragraph.", head)
+	assert.Equal(t, "Second one is longer.", rest)
+
+	head, rest = splitContent("One sentence. Another sentence here.", 20)
+	assert.Equal(t, "One sentence.", head)
+	assert.Equal(t, "Another sentence here.", rest)
+
+	// Without a boundary in the second half of the window, cut mid-word on a rune boundary
+	head, rest = splitContent("ééééééé", 3)
+	assert.Equal(t, "ééé", head)
+	assert.Equal(t, "éééé", rest)
+}
+
+func TestContentLimit(t *testing.T) {
+	assert.Zero(t, contentTestGraph(config.IngestConfig{}).ContentLimit())
+	assert.Equal(t, 40, contentTestGraph(config.IngestConfig{MaxContentChars: 40}).ContentLimit())
+	assert.Equal(t, 20, contentTestGraph(config.IngestConfig{MaxContentChars: 40, MaxContentTokens: 5}).ContentLimit())
+}
+
+func TestAddEpisodeContentOverflow(t *testing.T) {
+	ctx := context.Background()
+	content := "Alice moved to Berlin. She works at a bakery. Bob visits her often."
+
+	g := contentTestGraph(config.IngestConfig{MaxContentChars: 30})
+	err := g.AddEpisode(ctx, "g1", "message", content, "", "")
+	assert.ErrorIs(t, err, ErrContentTooLarge)
+	episodes, err := g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	assert.Empty(t, episodes)
+
+	g = contentTestGraph(config.IngestConfig{MaxContentChars: 30, Overflow: model.OverflowTruncate})
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", content, "", ""))
+	episodes, err = g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, "Alice moved to Berlin.", episodes[0].Content)
+
+	g = contentTestGraph(config.IngestConfig{MaxContentChars: 30, Overflow: model.OverflowChunk})
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", content, "", ""))
+	episodes, err = g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	var contents []string
+	for _, ep := range episodes {
+		contents = append(contents, ep.Content)
+	}
+	assert.ElementsMatch(t, []string{"Alice moved to Berlin.", "She works at a bakery.", "Bob visits her often."}, contents)
+}
+
+func TestBulkAddContentOverflow(t *testing.T) {
+	ctx := context.Background()
+	long := strings.Repeat("word ", 20)
+
+	g := contentTestGraph(config.IngestConfig{MaxContentTokens: 5})
+	result, err := g.BulkAddEpisodesPartial(ctx, "g1", []model.EpisodeData{{Content: "short"}, {Content: long}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, model.EpisodeStatusFailed, result.Results[1].Status)
+	assert.Contains(t, result.Results[1].Error, "episode content too large")
+
+	err = g.BulkAddEpisodes(ctx, "g1", []model.EpisodeData{{Content: "short"}, {Content: long}})
+	assert.ErrorIs(t, err, ErrContentTooLarge)
+
+	g = contentTestGraph(config.IngestConfig{MaxContentTokens: 5, Overflow: model.OverflowChunk})
+	result, err = g.BulkAddEpisodesPartial(ctx, "g1", []model.EpisodeData{{Content: "short"}, {Content: long}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	episodes, err := g.GetEpisodes(ctx, "g1", 100)
+	require.NoError(t, err)
+	assert.Len(t, episodes, 6, "the long episode is ingested as five 20-character chunks")
+}
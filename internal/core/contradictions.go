@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// maxContradictionFacts caps the facts of one entity compared in one LLM
+// call; the facts of busier entities past it are not checked.
+const maxContradictionFacts = 50
+
+// ScanContradictions asks the LLM, for every entity of the group with two or
+// more valid facts, which of those facts can't both be true. The report is
+// stored and can be read back with GetContradictionReport.
+func (g *Graphiti) ScanContradictions(ctx context.Context, groupID string) (*model.ContradictionReport, error) {
+	edges, err := g.getGroupEdges(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group edges: %w", err)
+	}
+	facts := make(map[string][]model.EntityEdge)
+	for _, e := range edges {
+		facts[e.SourceUUID] = append(facts[e.SourceUUID], e)
+		if e.TargetUUID != e.SourceUUID {
+			facts[e.TargetUUID] = append(facts[e.TargetUUID], e)
+		}
+	}
+	var entities []string
+	for uuid, fs := range facts {
+		if len(fs) > 1 {
+			entities = append(entities, uuid)
+		}
+	}
+	sort.Strings(entities)
+
+	report := &model.ContradictionReport{GroupID: groupID, ScannedAt: time.Now().UTC(), Contradictions: []model.Contradiction{}}
+	byUUID := make(map[string]model.EntityEdge, len(edges))
+	for _, e := range edges {
+		byUUID[e.UUID] = e
+	}
+	seen := make(map[[2]string]bool)
+	var mu sync.Mutex
+	forEachBounded(g.bulkIngest.Limit(), len(entities), func(i int) {
+		fs := facts[entities[i]]
+		if len(fs) > maxContradictionFacts {
+			fs = fs[:maxContradictionFacts]
+		}
+		conflicts, err := g.Deduplicator.FindContradictions(ctx, fs)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			log.Printf("Failed to scan facts of entity %s for contradictions: %v", entities[i], err)
+			report.Failed++
+			return
+		}
+		report.Checked++
+		for _, c := range conflicts {
+			pair := [2]string{c.EdgeUUIDs[0], c.EdgeUUIDs[1]}
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			if seen[pair] { // Both facts' entities were checked
+				continue
+			}
+			seen[pair] = true
+			report.Contradictions = append(report.Contradictions, model.Contradiction{
+				Facts:  []model.EntityEdge{byUUID[pair[0]], byUUID[pair[1]]},
+				Reason: c.Reason,
+			})
+		}
+	})
+	sort.Slice(report.Contradictions, func(i, j int) bool {
+		a, b := report.Contradictions[i].Facts, report.Contradictions[j].Facts
+		if a[0].UUID != b[0].UUID {
+			return a[0].UUID < b[0].UUID
+		}
+		return a[1].UUID < b[1].UUID
+	})
+
+	if err := g.saveReport(ctx, model.ReportKindContradictions, groupID, report.ScannedAt, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetContradictionReport returns the group's latest contradiction report, or
+// ErrReportNotFound. Contradictions are left out once either fact has been
+// invalidated or deleted, and when the caller may not read either fact.
+func (g *Graphiti) GetContradictionReport(ctx context.Context, groupID string) (*model.ContradictionReport, error) {
+	var report model.ContradictionReport
+	if err := g.loadReport(ctx, model.ReportKindContradictions, groupID, &report); err != nil {
+		return nil, err
+	}
+	edges, err := g.getGroupEdges(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group edges: %w", err)
+	}
+	valid := make(map[string]bool, len(edges))
+	for _, e := range edges {
+		valid[e.UUID] = true
+	}
+
+	access := g.accessFilter(ctx)
+	current := []model.Contradiction{}
+	for _, c := range report.Contradictions {
+		keep := true
+		for _, f := range c.Facts {
+			keep = keep && valid[f.UUID] && access.allows(f.Name)
+		}
+		if keep {
+			current = append(current, c)
+		}
+	}
+	report.Contradictions = current
+	return &report, nil
+}
+
+// RunContradictionScan scans every group for contradictions each interval until ctx is done.
+func (g *Graphiti) RunContradictionScan(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			groups, err := g.ListGroups(ctx)
+			if err != nil {
+				log.Printf("Contradiction scan: failed to list groups: %v", err)
+				continue
+			}
+			for _, group := range groups {
+				report, err := g.ScanContradictions(ctx, group.GroupID)
+				if err != nil {
+					log.Printf("Contradiction scan failed for group %s: %v", group.GroupID, err)
+					continue
+				}
+				if len(report.Contradictions) > 0 {
+					log.Printf("Contradiction scan: found %d contradictions in group %s", len(report.Contradictions), group.GroupID)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanContradictions(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{})
+	g.Deduplicator.Prompts.Contradictions = "%s"
+	g.Deduplicator.LLM = llmFunc(func(string) string {
+		// Reported for both alice and seattle; also a pair naming an unknown fact
+		return `{"contradictions": [{"edge_uuids": ["e2", "e1"], "reason": "one home"}, {"edge_uuids": ["e1", "nope"]}]}`
+	})
+
+	_, err := g.GetContradictionReport(ctx, "g1")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+
+	seedEntities(t, d, "g1", "alice", "seattle", "paris")
+	for _, e := range [][3]string{{"e1", "seattle", "LIVES_IN"}, {"e2", "paris", "LIVES_IN"}, {"e3", "seattle", "VISITED"}} {
+		seedFact(t, d, "g1", e[0], "alice", e[1], e[2], e[0], nil)
+	}
+
+	report, err := g.ScanContradictions(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Checked, "alice and seattle have two or more facts")
+	require.Len(t, report.Contradictions, 1)
+	assert.Equal(t, "e1", report.Contradictions[0].Facts[0].UUID)
+	assert.Equal(t, "e2", report.Contradictions[0].Facts[1].UUID)
+	assert.Equal(t, "one home", report.Contradictions[0].Reason)
+
+	report, err = g.GetContradictionReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, report.Contradictions, 1)
+
+	// Facts hidden from the caller hide their contradictions
+	g.Config.Access.Policies = []config.AccessPolicy{{Scope: "home", Relations: []string{"LIVES_IN"}}}
+	report, err = g.GetContradictionReport(WithScopes(ctx, nil), "g1")
+	require.NoError(t, err)
+	assert.Empty(t, report.Contradictions)
+	g.Config.Access.Policies = nil
+
+	// Resolved once either fact is invalidated
+	require.NoError(t, g.invalidateEdge(ctx, "e1", time.Now()))
+	report, err = g.GetContradictionReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Empty(t, report.Contradictions)
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"math"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// matchConfidence is a vector match's confidence: the cosine similarity of
+// the query and the fact, recovered from the score of the metric that ranked
+// it, with opposed vectors at 0. Dot products grow with the embeddings'
+// magnitudes and euclidean scores shrink with them, so only cosine puts every
+// metric on one [0, 1] scale. Text matches, which contain the query
+// verbatim, are given 1 by the caller.
+func matchConfidence(metric string, score, queryNorm, factNorm float64) float64 {
+	cosine := score
+	if metric == model.MetricDot || metric == model.MetricEuclidean {
+		if queryNorm == 0 || factNorm == 0 {
+			return 0
+		}
+		if metric == model.MetricDot {
+			cosine = score / (queryNorm * factNorm)
+		} else {
+			// score is 1 / (1 + |q - f|), and |q - f|² = |q|² + |f|² - 2 q·f
+			d := 1/score - 1
+			cosine = (queryNorm*queryNorm + factNorm*factNorm - d*d) / (2 * queryNorm * factNorm)
+		}
+	}
+	if math.IsNaN(cosine) {
+		return 0
+	}
+	return min(max(cosine, 0), 1) // Rounding can leave cosine past ±1
+}
+
+// vectorNorm is the euclidean length of v.
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// Coverage summarizes the confidence of search results: the best one and how
+// many reach [search] relevance_threshold.
+func (g *Graphiti) Coverage(edges []model.EntityEdge) model.Coverage {
+	threshold := 0.7
+	if g.Config != nil && g.Config.Search.RelevanceThreshold > 0 {
+		threshold = g.Config.Search.RelevanceThreshold
+	}
+	var coverage model.Coverage
+	for _, e := range edges {
+		if e.Confidence == nil {
+			continue
+		}
+		coverage.Best = max(coverage.Best, *e.Confidence)
+		if *e.Confidence >= threshold {
+			coverage.Relevant++
+		}
+	}
+	coverage.Answerable = coverage.Relevant > 0
+	return coverage
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchConfidenceAndCoverage(t *testing.T) {
+	ctx := context.Background()
+	g := scoredTestGraph(t, reverseReranker{})
+
+	edges, err := g.Search(ctx, "g1", "q")
+	require.NoError(t, err)
+	confidence := make(map[string]float64)
+	for _, e := range edges {
+		require.NotNil(t, e.Confidence)
+		confidence[e.UUID] = *e.Confidence
+	}
+	assert.Equal(t, 1.0, confidence["f1"])
+	assert.InDelta(t, 0.6, confidence["f2"], 1e-6)
+	assert.Equal(t, 0.0, confidence["f3"])
+	assert.Equal(t, model.Coverage{Best: 1, Relevant: 1, Answerable: true}, g.Coverage(edges))
+
+	g.Config.Search.RelevanceThreshold = 0.5
+	assert.Equal(t, 2, g.Coverage(edges).Relevant)
+	assert.Equal(t, model.Coverage{}, g.Coverage(nil))
+
+	// Every metric reports the cosine similarity, whatever the vectors' lengths
+	scaled := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, mapEmbedder{"q": {2, 0}}, nil, &config.Config{})
+	seedEntities(t, scaled.Driver, "g1", "a", "b")
+	for uuid, vec := range map[string][]float32{"f1": {3, 0}, "f2": {1.8, 2.4}, "f3": {0, 3}} {
+		seedFact(t, scaled.Driver, "g1", uuid, "a", "b", "KNOWS", uuid, map[string]interface{}{
+			"created_at": "2024-01-01T00:00:00Z", "fact_embedding": vec, "fact_embedding_model": embeddingTag(scaled.EmbeddingModel, vec),
+		})
+	}
+	for _, metric := range []string{model.MetricCosine, model.MetricDot, model.MetricEuclidean} {
+		edges, err := scaled.SearchWithFilter(ctx, "g1", "q", &model.SearchFilter{Metric: metric})
+		require.NoError(t, err)
+		require.Len(t, edges, 3, metric)
+		for _, e := range edges {
+			confidence[e.UUID] = *e.Confidence
+		}
+		assert.InDelta(t, 1.0, confidence["f1"], 1e-6, metric)
+		assert.InDelta(t, 0.6, confidence["f2"], 1e-6, metric)
+		assert.InDelta(t, 0.0, confidence["f3"], 1e-6, metric)
+	}
+
+	// Text matches contain the query
+	text := filterTestGraph(t)
+	edges, err = text.Search(ctx, "g1", "e1 fact")
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, 1.0, *edges[0].Confidence)
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// deadLetterNamespace derives dead letter IDs from their payload.
+var deadLetterNamespace = uuid.MustParse("5d1c3a4e-8f0b-4c43-9b7e-2f1d6a0c9e21")
+
+func deadLetterID(groupID, name string, ep model.EpisodeData) string {
+	key, _ := json.Marshal([]string{groupID, name, ep.Content, ep.Saga, ep.Schema, ep.Source})
+	return uuid.NewSHA1(deadLetterNamespace, key).String()
+}
+
+// recordDeadLetter keeps an episode that failed to ingest. Failures caused by
+// the caller (oversized content in reject mode, or ctx ending) are not kept:
+// the caller saw the error, and interrupted ingest jobs re-run the episode.
+// Neither is content moderation blocked.
+func (g *Graphiti) recordDeadLetter(ctx context.Context, groupID, name string, ep model.EpisodeData, cause error) {
+	if ctx.Err() != nil || errors.Is(cause, ErrContentTooLarge) || errors.Is(cause, ErrGroupLimit) || errors.Is(cause, ErrContentBlocked) {
+		return
+	}
+	payload, err := json.Marshal(ep)
+	if err != nil {
+		log.Printf("Failed to encode dead letter: %v", err)
+		return
+	}
+	episode, err := g.encryptPayload(string(payload))
+	if err != nil {
+		log.Printf("Failed to encrypt dead letter: %v", err)
+		return
+	}
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveDeadLetterQuery, map[string]interface{}{
+		"uuid":       deadLetterID(groupID, name, ep),
+		"group_id":   groupID,
+		"name":       name,
+		"episode":    episode,
+		"error":      cause.Error(),
+		"updated_at": time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		log.Printf("Failed to save dead letter for group %s: %v (episode error: %v)", groupID, err, cause)
+	}
+}
+
+// ListDeadLetters returns the group's dead letters, or every group's when
+// groupID is empty, most recently failed first.
+func (g *Graphiti) ListDeadLetters(ctx context.Context, groupID string) ([]model.DeadLetter, error) {
+	var group interface{}
+	if groupID != "" {
+		group = groupID
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.ListDeadLettersQuery, map[string]interface{}{"group_id": group})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	letters := make([]model.DeadLetter, 0, len(res.Records))
+	for _, rec := range res.Records {
+		letter, err := g.readDeadLetter(rec)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *letter)
+	}
+	return letters, nil
+}
+
+// GetDeadLetter returns a dead letter, or ErrDeadLetterNotFound.
+func (g *Graphiti) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetDeadLetterQuery, map[string]interface{}{"uuid": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dead letter: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	return g.readDeadLetter(res.Records[0])
+}
+
+// RequeueDeadLetter ingests a dead letter's episode again and deletes the dead
+// letter once it succeeds. If it fails again, the dead letter is kept with the
+// new error and returned along with it.
+func (g *Graphiti) RequeueDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	letter, err := g.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ep := letter.Episode
+	if err := g.addEpisode(ctx, letter.GroupID, letter.Name, ep.Content, ep.Saga, ep.Schema); err != nil {
+		g.recordDeadLetter(ctx, letter.GroupID, letter.Name, ep, err)
+		if updated, getErr := g.GetDeadLetter(ctx, id); getErr == nil {
+			letter = updated
+		}
+		return letter, err
+	}
+	if err := g.deleteDeadLetter(ctx, id); err != nil {
+		return letter, err
+	}
+	return letter, nil
+}
+
+func (g *Graphiti) deleteDeadLetter(ctx context.Context, id string) error {
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.DeleteDeadLetterQuery, map[string]interface{}{"uuid": id}); err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	return nil
+}
+
+func (g *Graphiti) readDeadLetter(rec *neo4j.Record) (*model.DeadLetter, error) {
+	var letter model.DeadLetter
+	if err := driver.ScanRecord(rec, &letter); err != nil {
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+	raw, _ := rec.Get("episode")
+	payload, _ := raw.(string)
+	payload, err := g.decryptPayload(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letter %s: %w", letter.UUID, err)
+	}
+	if err := json.Unmarshal([]byte(payload), &letter.Episode); err != nil {
+		return nil, fmt.Errorf("invalid episode for dead letter %s: %w", letter.UUID, err)
+	}
+	return &letter, nil
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	llmClient := &flakyLLM{marker: "bad"}
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	require.Error(t, g.AddEpisode(ctx, "g1", "message", "bad news", "", ""))
+	require.Error(t, g.AddEpisode(ctx, "g1", "message", "bad news", "", ""))
+	letters, err := g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, model.EpisodeData{Content: "bad news"}, letters[0].Episode)
+	assert.Equal(t, "message", letters[0].Name)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Contains(t, letters[0].Error, "model overloaded")
+
+	result, err := g.BulkAddEpisodesPartial(ctx, "g2", []model.EpisodeData{{Content: "bad bulk", Source: "import"}})
+	require.NoError(t, err)
+	letters, err = g.ListDeadLetters(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, letters, 2)
+	letters, err = g.ListDeadLetters(ctx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	// Interrupted ingests are not dead letters
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Error(t, g.AddEpisode(cancelled, "g3", "message", "bad timing", "", ""))
+	letters, err = g.ListDeadLetters(ctx, "g3")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	letters, err = g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	id := letters[0].UUID
+	letter, err := g.RequeueDeadLetter(ctx, id)
+	require.Error(t, err)
+	assert.Equal(t, 3, letter.Attempts)
+
+	llmClient.healed = true
+	_, err = g.RequeueDeadLetter(ctx, id)
+	require.NoError(t, err)
+	_, err = g.GetDeadLetter(ctx, id)
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+	_, err = g.RequeueDeadLetter(ctx, id)
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+
+	_, err = g.RetryFailed(ctx, result)
+	require.NoError(t, err)
+	letters, err = g.ListDeadLetters(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+// rejectFactsHook fails every episode at the fact stage.
+type rejectFactsHook struct{}
+
+func (rejectFactsHook) PostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error) {
+	return entities, nil
+}
+
+func (rejectFactsHook) PostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error) {
+	return nil, errors.New("facts rejected")
+}
+
+func TestDeadLetters_EdgeProcessingFails(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return `{"extracted_edges": []}`
+		}
+		return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`
+	}), nil, nil, &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"}})
+	require.NoError(t, g.RegisterHook(rejectFactsHook{}))
+
+	err := g.AddEpisode(ctx, "g1", "message", "Alice knows Bob", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "facts rejected")
+	letters, err := g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Contains(t, letters[0].Error, "facts rejected")
+}
+
+func TestDeadLetters_CircuitOpen(t *testing.T) {
+	nodeLine := regexp.MustCompile(`UUID: (\S+), Name: (\w+)`)
+	for _, stage := range []string{"edges", "summarize"} {
+		t.Run(stage, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewGraphiti(driver.NewMemoryDriver(), llmErrFunc(func(prompt string) (string, error) {
+				switch {
+				case strings.HasPrefix(prompt, stage):
+					return "", fmt.Errorf("provider: %w", llm.ErrCircuitOpen)
+				case strings.HasPrefix(prompt, "edges"):
+					uuids := map[string]string{}
+					for _, m := range nodeLine.FindAllStringSubmatch(prompt, -1) {
+						uuids[m[2]] = m[1]
+					}
+					return `{"extracted_edges": [{"source_node_uuid": "` + uuids["Alice"] + `", "target_node_uuid": "` + uuids["Bob"] + `", "relation_type": "KNOWS", "fact": "Alice knows Bob"}]}`, nil
+				}
+				return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`, nil
+			}), nil, nil, &config.Config{
+				Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+				Summary:    config.SummaryPrompts{Nodes: "summarize %s %s"},
+			})
+
+			err := g.AddEpisode(ctx, "g1", "message", "Alice knows Bob", "", "")
+			assert.ErrorIs(t, err, llm.ErrCircuitOpen)
+			letters, err := g.ListDeadLetters(ctx, "g1")
+			require.NoError(t, err)
+			assert.Len(t, letters, 1)
+		})
+	}
+}
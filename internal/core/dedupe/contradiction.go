@@ -5,19 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 
+	"github.com/agenthands/carbon/internal/core/common"
 	"github.com/agenthands/carbon/internal/core/model"
 )
 
 func (d *Deduplicator) ResolveEdgeContradictions(ctx context.Context, newFact string, existingEdges []model.EntityEdge) ([]string, error) {
+	result, err := d.CheckEdgeHistory(ctx, newFact, existingEdges)
+	if err != nil {
+		return nil, err
+	}
+	return result.ContradictedEdgeUUIDs, nil
+}
+
+// CheckEdgeHistory asks which of the existing facts the new fact contradicts
+// and, among those invalidated before (InvalidAt set), which one it states
+// again. Invalidated facts are listed with the time they stopped being true.
+func (d *Deduplicator) CheckEdgeHistory(ctx context.Context, newFact string, existingEdges []model.EntityEdge) (*model.ContradictionResult, error) {
 	if len(existingEdges) == 0 {
-		return nil, nil // No contradictions possible
+		return &model.ContradictionResult{}, nil // No contradictions possible
 	}
 
 	// Construct Existing Facts String
 	var existingFactsStr string
 	for _, edge := range existingEdges {
-		existingFactsStr += fmt.Sprintf("- UUID: %s, Fact: %s\n", edge.UUID, edge.Fact)
+		existingFactsStr += fmt.Sprintf("- UUID: %s, Fact: %s", edge.UUID, edge.Fact)
+		if edge.InvalidAt != nil {
+			existingFactsStr += fmt.Sprintf(", No longer true since: %s", edge.InvalidAt.Format(time.RFC3339))
+		}
+		existingFactsStr += "\n"
 	}
 
 	// Use Configured Prompt or Default
@@ -30,9 +47,13 @@ New Fact: %s
 Existing Facts:
 %s
 
+Facts marked "No longer true since" were contradicted before; never list them as contradicted.
+If the New Fact states one of them again (e.g. "moved back to Seattle" after "lives in Seattle"
+stopped being true), return its UUID as "reinstated_edge_uuid".
+
 Return a JSON object with a list of UUIDs of the EXISTING facts that are contradicted by the new fact.
-Example: { "contradicted_edge_uuids": ["uuid-1"] }
-If none, return empty list.`
+Example: { "contradicted_edge_uuids": ["uuid-1"], "reinstated_edge_uuid": "uuid-2" }
+If none, return empty list and omit "reinstated_edge_uuid".`
 	}
 
 	prompt := fmt.Sprintf(promptTemplate, newFact, existingFactsStr)
@@ -51,7 +72,43 @@ If none, return empty list.`
 		return nil, fmt.Errorf("failed to parse contradiction result: %w", err)
 	}
 
-	return result.ContradictedEdgeUUIDs, nil
+	return &result, nil
+}
+
+// FindContradictions asks which pairs of facts, all currently valid, can't
+// both be true. Pairs naming a UUID outside facts are dropped.
+func (d *Deduplicator) FindContradictions(ctx context.Context, facts []model.EntityEdge) ([]model.FactConflict, error) {
+	if d.Prompts.Contradictions == "" {
+		return nil, fmt.Errorf("contradictions prompt is not configured")
+	}
+	if len(facts) < 2 {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(facts))
+	var factsList string
+	for _, f := range facts {
+		known[f.UUID] = true
+		factsList += fmt.Sprintf("- UUID: %s, Fact: %s\n", f.UUID, f.Fact)
+	}
+
+	response, err := d.LLM.Generate(ctx, fmt.Sprintf(d.Prompts.Contradictions, factsList))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate contradiction scan: %w", err)
+	}
+	result, err := common.ParseJSON[model.FactContradictions](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contradiction scan: %w", err)
+	}
+
+	var conflicts []model.FactConflict
+	for _, c := range result.Contradictions {
+		if len(c.EdgeUUIDs) != 2 || c.EdgeUUIDs[0] == c.EdgeUUIDs[1] || !known[c.EdgeUUIDs[0]] || !known[c.EdgeUUIDs[1]] {
+			continue
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, nil
 }
 
 func extractJSON(s string) string {
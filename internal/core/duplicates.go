@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// duplicateSettings returns the [ingest] duplicate handling with defaults applied.
+func (g *Graphiti) duplicateSettings() (mode string, window int, threshold float64, err error) {
+	mode, window, threshold = model.DuplicatesIngest, 20, 0.97
+	if g.Config == nil {
+		return mode, window, threshold, nil
+	}
+	c := g.Config.Ingest
+	switch c.Duplicates {
+	case "", model.DuplicatesIngest:
+	case model.DuplicatesSkip, model.DuplicatesLink:
+		mode = c.Duplicates
+	default:
+		return "", 0, 0, fmt.Errorf("invalid [ingest] duplicates '%s'", c.Duplicates)
+	}
+	if c.DuplicateWindow > 0 {
+		window = c.DuplicateWindow
+	}
+	if c.DuplicateThreshold > 0 {
+		threshold = c.DuplicateThreshold
+	}
+	return mode, window, threshold, nil
+}
+
+// normalizeEpisode is the text episodes are compared by: lower case with
+// whitespace collapsed.
+func normalizeEpisode(content string) string {
+	return strings.Join(strings.Fields(strings.ToLower(content)), " ")
+}
+
+// findDuplicateEpisode returns the UUID of the most recent of the group's
+// latest episodes that content repeats, or "" if none does or duplicate
+// handling is off. Offloaded episodes are not compared.
+func (g *Graphiti) findDuplicateEpisode(ctx context.Context, groupID, content string) (string, error) {
+	mode, window, threshold, err := g.duplicateSettings()
+	if err != nil || mode == model.DuplicatesIngest {
+		return "", err
+	}
+	defer timeStage(ctx, "find_duplicate")()
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"limit":    window,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch recent episodes: %w", err)
+	}
+	episodes, err := driver.ScanRecords[model.EpisodicNode](res)
+	if err != nil {
+		return "", fmt.Errorf("failed to read recent episodes: %w", err)
+	}
+
+	normalized := normalizeEpisode(content)
+	var contents []string
+	for _, ep := range episodes {
+		text, _, err := g.readEpisodeContent(ctx, ep, false)
+		if err != nil {
+			return "", fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+		}
+		if text != "" && normalizeEpisode(text) == normalized {
+			return ep.UUID, nil
+		}
+		contents = append(contents, text)
+	}
+	if g.Embedder == nil || len(episodes) == 0 {
+		return "", nil
+	}
+
+	vec, err := g.Embedder.Embed(ctx, content)
+	if err != nil || len(vec) == 0 {
+		log.Printf("Failed to embed episode for duplicate detection, comparing text only: %v", err)
+		return "", nil
+	}
+	for i, ep := range episodes {
+		if contents[i] == "" {
+			continue
+		}
+		other, err := g.Embedder.Embed(ctx, contents[i])
+		if err != nil {
+			log.Printf("Failed to embed episode %s for duplicate detection: %v", ep.UUID, err)
+			continue
+		}
+		if cosine32(vec, other) >= threshold {
+			return ep.UUID, nil
+		}
+	}
+	return "", nil
+}
+
+// linkDuplicateEpisode saves an episode that repeats original without
+// extracting it: it mentions the original's entities, joins the provenance
+// of the original's facts and is added to saga, if any.
+func (g *Graphiti) linkDuplicateEpisode(ctx context.Context, episodeUUID, name, groupID, content, saga, original string, now time.Time) error {
+	if err := g.saveEpisodeNode(ctx, episodeUUID, name, groupID, content, now); err != nil {
+		return fmt.Errorf("failed to save episode: %w", err)
+	}
+
+	params := map[string]interface{}{"group_id": groupID, "uuids": []string{original}}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeMentionsQuery, params)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the mentions of episode %s: %w", original, err)
+	}
+	for _, rec := range res.Records {
+		uuid, _ := rec.Get("uuid")
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+			"uuid":        g.UUIDGenerator(),
+			"source_uuid": episodeUUID,
+			"target_uuid": uuid,
+			"group_id":    groupID,
+			"created_at":  now.Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("failed to link episode: %w", err)
+		}
+	}
+
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeFactsQuery, params)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the facts of episode %s: %w", original, err)
+	}
+	facts, err := driver.ScanRecords[struct {
+		UUID     string   `db:"uuid"`
+		Episodes []string `db:"episodes"`
+	}](res)
+	if err != nil {
+		return fmt.Errorf("failed to read the facts of episode %s: %w", original, err)
+	}
+	for _, f := range facts {
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.SetEdgeEpisodesQuery, map[string]interface{}{
+			"uuid":     f.UUID,
+			"episodes": append(f.Episodes, episodeUUID),
+		}); err != nil {
+			return fmt.Errorf("failed to link fact %s to episode: %w", f.UUID, err)
+		}
+	}
+
+	if saga != "" {
+		if err := g.handleSaga(ctx, saga, groupID, episodeUUID, now); err != nil {
+			return fmt.Errorf("failed to handle saga: %w", err)
+		}
+	}
+	return nil
+}
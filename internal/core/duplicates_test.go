@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateEpisodes(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	llmClient := llmFunc(func(prompt string) string {
+		calls++
+		return `{"extracted_entities": []}`
+	})
+	newGraph := func(ingest config.IngestConfig, embedder mapEmbedder) *Graphiti {
+		cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}, Ingest: ingest}
+		if embedder != nil {
+			return NewGraphiti(driver.NewMemoryDriver(), llmClient, embedder, nil, cfg)
+		}
+		return NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+	}
+	episodeCount := func(g *Graphiti) int {
+		episodes, err := g.GetEpisodes(ctx, "g1", 10)
+		require.NoError(t, err)
+		return len(episodes)
+	}
+
+	// Off by default
+	g := newGraph(config.IngestConfig{}, nil)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I live in Berlin.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I live in Berlin.", "", ""))
+	assert.Equal(t, 2, episodeCount(g))
+	assert.Equal(t, 2, calls)
+
+	// Case and whitespace don't matter
+	calls = 0
+	g = newGraph(config.IngestConfig{Duplicates: model.DuplicatesSkip}, nil)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I live in Berlin.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "  i live in\nBerlin. ", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I live in Lisbon.", "", ""))
+	assert.Equal(t, 2, episodeCount(g))
+	assert.Equal(t, 2, calls)
+
+	// Near-duplicates by embedding
+	calls = 0
+	g = newGraph(config.IngestConfig{Duplicates: model.DuplicatesSkip}, mapEmbedder{
+		"I live in Berlin.":         {1, 0},
+		"I'm living in Berlin.":     {0.99, 0.05},
+		"My sister lives in Paris.": {0, 1},
+	})
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I live in Berlin.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "I'm living in Berlin.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "My sister lives in Paris.", "", ""))
+	assert.Equal(t, 2, episodeCount(g))
+	assert.Equal(t, 2, calls)
+
+	// Linked repeats share the original's entities and facts
+	calls = 0
+	g = newGraph(config.IngestConfig{Duplicates: model.DuplicatesLink}, nil)
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "message", "g1", "Alice lives in Berlin.", mustTime("2024-01-01T00:00:00Z")))
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "alice", Name: "Alice", GroupID: "g1"}))
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "berlin", Name: "Berlin", GroupID: "g1"}))
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+		"uuid": "m1", "source_uuid": "ep1", "target_uuid": "alice", "group_id": "g1",
+	})
+	require.NoError(t, err)
+	seedFact(t, g.Driver, "g1", "e1", "alice", "berlin", "LIVES_IN", "Alice lives in Berlin", map[string]interface{}{"episodes": []string{"ep1"}})
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice lives in Berlin.", "", ""))
+	assert.Zero(t, calls)
+	episodes, err := g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+	repeat := episodes[0].UUID
+	assert.NotEqual(t, "ep1", repeat)
+	edge, err := g.GetFact(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ep1", repeat}, edge.Episodes)
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodeMentionsQuery, map[string]interface{}{"uuids": []string{repeat}})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	mentioned, _ := res.Records[0].Get("uuid")
+	assert.Equal(t, "alice", mentioned)
+
+	g = newGraph(config.IngestConfig{Duplicates: "merge"}, nil)
+	assert.Error(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""))
+}
@@ -0,0 +1,273 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSaveDriver fails edge writes and passes everything else to a memory driver.
+type failingSaveDriver struct {
+	*driver.MemoryDriver
+}
+
+func (d failingSaveDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if query == driver.SaveEntityEdgeQuery {
+		return neo4j.EagerResult{}, errors.New("disk full")
+	}
+	return d.MemoryDriver.ExecuteQuery(ctx, query, params)
+}
+
+func edgeTestGraph(t *testing.T, d driver.GraphDriver, llmClient llmFunc) (*Graphiti, []model.EntityNode) {
+	cfg := &config.Config{
+		Extraction:  config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+		Summary:     config.SummaryPrompts{Nodes: "summarize %s %s"},
+		Concurrency: config.ConcurrencyConfig{EdgeWorkers: 4},
+	}
+	g := NewGraphiti(d, llmClient, nil, nil, cfg)
+	now := time.Now().UTC()
+	var nodes []model.EntityNode
+	for _, name := range []string{"a", "b", "c"} {
+		seedEntity(t, d, "g1", name, map[string]interface{}{"created_at": now.Format(time.RFC3339), "attributes": "{}"})
+		nodes = append(nodes, model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: now})
+	}
+	return g, nodes
+}
+
+const threeSourceEdges = `{"extracted_edges": [
+	{"source_node_uuid": "a", "target_node_uuid": "b", "relation_type": "KNOWS", "fact": "a knows b"},
+	{"source_node_uuid": "b", "target_node_uuid": "c", "relation_type": "KNOWS", "fact": "b knows c"},
+	{"source_node_uuid": "c", "target_node_uuid": "a", "relation_type": "KNOWS", "fact": "c knows a"}
+]}`
+
+func TestProcessEntityEdges_SummariesRunInParallel(t *testing.T) {
+	var inflight, maxInflight int32
+	var mu sync.Mutex
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return threeSourceEdges
+		}
+		n := atomic.AddInt32(&inflight, 1)
+		mu.Lock()
+		if n > maxInflight {
+			maxInflight = n
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inflight, -1)
+		return `{"summary": "updated"}`
+	})
+
+	require.NoError(t, g.processEntityEdgesAndSummaries(context.Background(), nodes, "ep1", "g1", "", nil, time.Now().UTC()))
+	assert.Greater(t, maxInflight, int32(1))
+
+	edges, err := g.getGroupEdges(context.Background(), "g1")
+	require.NoError(t, err)
+	assert.Len(t, edges, 3)
+}
+
+func TestProcessEntityEdges_CollectsErrors(t *testing.T) {
+	g, nodes := edgeTestGraph(t, failingSaveDriver{driver.NewMemoryDriver()}, func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return threeSourceEdges
+		}
+		return `{"summary": "updated"}`
+	})
+
+	err := g.processEntityEdgesAndSummaries(context.Background(), nodes, "ep1", "g1", "", nil, time.Now().UTC())
+	require.Error(t, err)
+	for _, fact := range []string{"a knows b", "b knows c", "c knows a"} {
+		assert.Contains(t, err.Error(), fmt.Sprintf("failed to save edge %q", fact))
+	}
+}
+
+func TestAddEpisode_EdgePromptIsGroundedInContent(t *testing.T) {
+	var mu sync.Mutex
+	var edgePrompts []string
+	llmClient := llmFunc(func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "nodes|"):
+			return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`
+		case strings.Contains(prompt, "edges "):
+			mu.Lock()
+			edgePrompts = append(edgePrompts, prompt)
+			mu.Unlock()
+			return `{"extracted_edges": []}`
+		}
+		return `{}`
+	})
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{
+		Nodes:   "nodes|%s|%s",
+		Edges:   "edges %s<MESSAGE>%s</MESSAGE>",
+		Context: "<PREVIOUS>%s</PREVIOUS>",
+	}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	ctx := context.Background()
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep1", "Alice hired Bob at Acme.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep2", "Bob thanked Alice.", "", ""))
+
+	require.Len(t, edgePrompts, 2)
+	assert.Contains(t, edgePrompts[0], "<MESSAGE>Alice hired Bob at Acme.</MESSAGE>")
+	assert.NotContains(t, edgePrompts[0], "<PREVIOUS>")
+	assert.Contains(t, edgePrompts[1], "<PREVIOUS>Alice hired Bob at Acme.</PREVIOUS>")
+	assert.Contains(t, edgePrompts[1], "<MESSAGE>Bob thanked Alice.</MESSAGE>")
+}
+
+func TestAddEpisode_VerifyFactsPerGroup(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "nodes|"):
+			return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`
+		case strings.HasPrefix(prompt, "edges "):
+			lines := strings.Split(strings.TrimPrefix(prompt, "edges "), "\n")
+			source := strings.TrimPrefix(strings.Split(lines[0], ",")[0], "- UUID: ")
+			target := strings.TrimPrefix(strings.Split(lines[1], ",")[0], "- UUID: ")
+			return fmt.Sprintf(`{"extracted_edges": [
+				{"source_node_uuid": %q, "target_node_uuid": %q, "relation_type": "HIRED", "fact": "Alice hired Bob"},
+				{"source_node_uuid": %q, "target_node_uuid": %q, "relation_type": "MARRIED", "fact": "Alice married Bob"}
+			]}`, source, target, source, target)
+		case strings.HasPrefix(prompt, "verify "):
+			return `{"verdicts": [{"id": 1, "supported": true}, {"id": 2, "supported": false}]}`
+		}
+		return `{}`
+	})
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "nodes|%s|%s", Edges: "edges %s", Verify: "verify %s %s"},
+		Ingest:     config.IngestConfig{VerifyFacts: true},
+	}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+	ctx := context.Background()
+
+	facts := func(groupID string) []string {
+		edges, err := g.getGroupEdges(ctx, groupID)
+		require.NoError(t, err)
+		var facts []string
+		for _, e := range edges {
+			facts = append(facts, e.Fact)
+		}
+		return facts
+	}
+
+	require.NoError(t, g.AddEpisode(ctx, "verified", "ep1", "Alice hired Bob.", "", ""))
+	assert.Equal(t, []string{"Alice hired Bob"}, facts("verified"))
+
+	off := false
+	_, err := g.UpdateGroup(ctx, "unverified", model.GroupPatch{Settings: &model.GroupSettings{VerifyFacts: &off}})
+	require.NoError(t, err)
+	require.NoError(t, g.AddEpisode(ctx, "unverified", "ep1", "Alice hired Bob.", "", ""))
+	assert.ElementsMatch(t, []string{"Alice hired Bob", "Alice married Bob"}, facts("unverified"))
+}
+
+func TestAddEpisode_ResolvesCoreferences(t *testing.T) {
+	var mu sync.Mutex
+	var nodePrompts []string
+	llmClient := llmFunc(func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "coref|"):
+			return `{"resolved_content": "Alice moved to Berlin."}`
+		case strings.HasPrefix(prompt, "nodes|"):
+			mu.Lock()
+			nodePrompts = append(nodePrompts, prompt)
+			mu.Unlock()
+			return `{"extracted_entities": [{"name": "Alice"}]}`
+		}
+		return `{}`
+	})
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "nodes|%s|%s", Edges: "edges %s", Coreference: "coref|%s|%s"},
+		Ingest:     config.IngestConfig{ResolveCoreferences: true},
+	}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+	ctx := context.Background()
+
+	// The first episode has nothing to resolve against
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep1", "Alice loves Berlin.", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep2", "She moved there.", "", ""))
+	require.NoError(t, g.BulkAddEpisodes(ctx, "g1", []model.EpisodeData{{Content: "She really did."}}))
+
+	require.Len(t, nodePrompts, 3)
+	assert.True(t, strings.HasSuffix(nodePrompts[0], "|Alice loves Berlin."))
+	assert.True(t, strings.HasSuffix(nodePrompts[1], "|Alice moved to Berlin."))
+	assert.True(t, strings.HasSuffix(nodePrompts[2], "|Alice moved to Berlin."))
+
+	// Episodes keep their content as written
+	episodes, err := g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	var contents []string
+	for _, ep := range episodes {
+		contents = append(contents, ep.Content)
+	}
+	assert.ElementsMatch(t, []string{"Alice loves Berlin.", "She moved there.", "She really did."}, contents)
+}
+
+func TestProcessEntityEdges_NormalizesDates(t *testing.T) {
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return `{"extracted_edges": [
+				{"source_node_uuid": "a", "target_node_uuid": "b", "relation_type": "MET", "fact": "a met b last Tuesday"},
+				{"source_node_uuid": "b", "target_node_uuid": "c", "relation_type": "KNOWS", "fact": "b knows c"}
+			]}`
+		}
+		return `{"summary": "updated"}`
+	})
+	g.Config.Ingest.NormalizeDates = true
+	// A Thursday
+	now := time.Date(2026, time.October, 15, 17, 30, 0, 0, time.UTC)
+	require.NoError(t, g.processEntityEdgesAndSummaries(context.Background(), nodes, "ep1", "g1", "", nil, now))
+
+	edges, err := g.ListFacts(context.Background(), "g1")
+	require.NoError(t, err)
+	byFact := make(map[string]model.EntityEdge)
+	for _, e := range edges {
+		fact, err := g.GetFact(context.Background(), e.UUID)
+		require.NoError(t, err)
+		byFact[e.Fact] = *fact
+	}
+	met := byFact["a met b last Tuesday"]
+	assert.Equal(t, time.Date(2026, time.October, 13, 0, 0, 0, 0, time.UTC), met.ValidAt.UTC())
+	require.Contains(t, met.Attributes, "temporal")
+	assert.Contains(t, fmt.Sprint(met.Attributes["temporal"]), "last Tuesday")
+
+	// Facts without dates stay valid from the episode time
+	assert.Equal(t, now, byFact["b knows c"].ValidAt.UTC())
+	assert.NotContains(t, byFact["b knows c"].Attributes, "temporal")
+}
+
+func TestProcessEntityEdges_ReinforcesRepeatedFacts(t *testing.T) {
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return threeSourceEdges
+		}
+		return `{"summary": "updated"}`
+	})
+	ctx := context.Background()
+	for _, episode := range []string{"ep1", "ep2", "ep2"} {
+		require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, episode, "g1", "", nil, time.Now().UTC()))
+	}
+
+	edges, err := g.getGroupEdges(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, edges, 3)
+	fact, err := g.GetFact(ctx, edges[0].UUID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ep1", "ep2"}, fact.Episodes)
+	assert.Equal(t, 2, fact.MentionCount) // An episode counts once
+
+	results, err := g.Search(ctx, "g1", "knows")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, 2, results[0].MentionCount)
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+	"golang.org/x/time/rate"
+)
+
+// taggedEmbedding is a vector with the "<model>@<dimension>" tag stored next to it.
+type taggedEmbedding struct {
+	Vector []float32
+	Model  string
+}
+
+func embeddingTag(name string, vec []float32) string {
+	return fmt.Sprintf("%s@%d", name, len(vec))
+}
+
+// defaultEmbeddingModel names the [llm] embedder's vectors when no [embedding] models are configured.
+func defaultEmbeddingModel(cfg *config.Config) string {
+	switch {
+	case cfg.LLM.EmbeddingModel != "":
+		return cfg.LLM.EmbeddingModel
+	case cfg.LLM.Provider != "":
+		return cfg.LLM.Provider
+	}
+	return "default"
+}
+
+// UseEmbedders makes the first embedder active and keeps the rest for
+// searching vectors that have not been re-embedded yet.
+func (g *Graphiti) UseEmbedders(embedders []llm.NamedEmbedder) {
+	if len(embedders) == 0 {
+		return
+	}
+	g.Embedder = embedders[0].EmbedderClient
+	g.EmbeddingModel = embedders[0].Name
+	g.FallbackEmbedders = embedders[1:]
+}
+
+// embed returns text's embedding from the active embedder and its model tag.
+// Both are empty when there is no embedder.
+func (g *Graphiti) embed(ctx context.Context, text string) ([]float32, string, error) {
+	if g.Embedder == nil {
+		return nil, "", nil
+	}
+	vec, err := g.Embedder.Embed(ctx, text)
+	if err != nil || len(vec) == 0 {
+		return nil, "", err
+	}
+	return vec, embeddingTag(g.EmbeddingModel, vec), nil
+}
+
+// queryEmbeddings embeds a query with the active embedder, then with each
+// fallback embedder, so vectors of every configured model can be searched.
+// Only a failure of the active embedder is returned.
+func (g *Graphiti) queryEmbeddings(ctx context.Context, text string) ([]taggedEmbedding, error) {
+	if embedded, ok := ctx.Value(queryEmbeddingsKey{}).(map[string][]taggedEmbedding); ok {
+		if embeddings, ok := embedded[text]; ok {
+			return embeddings, nil
+		}
+	}
+	vec, tag, err := g.embed(ctx, text)
+	if err != nil || vec == nil {
+		return nil, err
+	}
+	embeddings := []taggedEmbedding{{Vector: vec, Model: tag}}
+	for _, e := range g.FallbackEmbedders {
+		vec, err := e.Embed(ctx, text)
+		if err != nil {
+			log.Printf("Fallback embedder %s failed: %v", e.Name, err)
+			continue
+		}
+		if len(vec) > 0 {
+			embeddings = append(embeddings, taggedEmbedding{Vector: vec, Model: embeddingTag(e.Name, vec)})
+		}
+	}
+	return embeddings, nil
+}
+
+type queryEmbeddingsKey struct{}
+
+// withQueryEmbeddings embeds texts with each configured embedder, one batch
+// per embedder, and returns ctx carrying the vectors for queryEmbeddings to
+// reuse. If the active embedder fails, ctx is returned as is and each search
+// embeds its query itself.
+func (g *Graphiti) withQueryEmbeddings(ctx context.Context, texts []string) context.Context {
+	if g.Embedder == nil || len(texts) == 0 {
+		return ctx
+	}
+	texts = appendUnique(nil, texts...)
+	vecs, err := llm.EmbedAll(ctx, g.Embedder, texts)
+	if err != nil {
+		log.Printf("Failed to embed %d queries: %v", len(texts), err)
+		return ctx
+	}
+	embedded := make(map[string][]taggedEmbedding, len(texts))
+	for i, text := range texts {
+		if len(vecs[i]) > 0 {
+			embedded[text] = []taggedEmbedding{{Vector: vecs[i], Model: embeddingTag(g.EmbeddingModel, vecs[i])}}
+		} else {
+			embedded[text] = nil
+		}
+	}
+	for _, e := range g.FallbackEmbedders {
+		vecs, err := llm.EmbedAll(ctx, e, texts)
+		if err != nil {
+			log.Printf("Fallback embedder %s failed: %v", e.Name, err)
+			continue
+		}
+		for i, text := range texts {
+			if len(vecs[i]) > 0 && embedded[text] != nil {
+				embedded[text] = append(embedded[text], taggedEmbedding{Vector: vecs[i], Model: embeddingTag(e.Name, vecs[i])})
+			}
+		}
+	}
+	return context.WithValue(ctx, queryEmbeddingsKey{}, embedded)
+}
+
+var ErrEmbeddingModelNotFound = errors.New("embedding model not configured")
+
+// namedEmbedder returns the configured embedder called name; "" is the active one.
+func (g *Graphiti) namedEmbedder(name string) (llm.NamedEmbedder, error) {
+	if g.Embedder != nil && (name == "" || name == g.EmbeddingModel) {
+		return llm.NamedEmbedder{Name: g.EmbeddingModel, EmbedderClient: g.Embedder}, nil
+	}
+	for _, e := range g.FallbackEmbedders {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	if name == "" {
+		return llm.NamedEmbedder{}, fmt.Errorf("%w: no embedder", ErrEmbeddingModelNotFound)
+	}
+	return llm.NamedEmbedder{}, fmt.Errorf("%w: %s", ErrEmbeddingModelNotFound, name)
+}
+
+// Reembed migrates a group's entity, community and fact embeddings to the
+// named embedding model ("" for the active one). Vectors tagged with another
+// model, or stored before embeddings were tagged, are recomputed in batches of
+// [embedding] batch_size at no more than requests_per_second, and staged next
+// to the live ones. Only when every vector succeeded are they promoted, all in
+// one write, so search switches models for the whole group at once; otherwise
+// the live vectors stay untouched and the job can be rerun. A dry run only
+// counts the stale vectors.
+func (g *Graphiti) Reembed(ctx context.Context, groupID, modelName string, dryRun bool) (*model.ReembedReport, error) {
+	embedder, err := g.namedEmbedder(modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	type item struct {
+		uuid, text string
+		fact       bool
+	}
+	var items []item
+	params := map[string]interface{}{
+		"group_id":     groupID,
+		"model_prefix": embedder.Name + "@",
+	}
+	stale := func(query, kind string, fact bool) (int, error) {
+		res, err := g.Driver.ExecuteReadQuery(ctx, query, params)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find stale %s embeddings: %w", kind, err)
+		}
+		for _, rec := range res.Records {
+			uuid, _ := rec.Get("uuid")
+			text, _ := rec.Get("name")
+			if fact {
+				text, _ = rec.Get("fact")
+			}
+			u, _ := uuid.(string)
+			t, _ := text.(string)
+			items = append(items, item{uuid: u, text: t, fact: fact})
+		}
+		return len(res.Records), nil
+	}
+
+	now := time.Now().UTC()
+	report := &model.ReembedReport{GroupID: groupID, RanAt: now, Model: embedder.Name, DryRun: dryRun}
+	if report.Entities, err = stale(driver.GetStaleEntityEmbeddingsQuery, "entity", false); err != nil {
+		return nil, err
+	}
+	if report.Communities, err = stale(driver.GetStaleCommunityEmbeddingsQuery, "community", false); err != nil {
+		return nil, err
+	}
+	if report.Facts, err = stale(driver.GetStaleFactEmbeddingsQuery, "fact", true); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	batchSize, workers := 100, 4
+	var limiter *rate.Limiter
+	if g.Config != nil {
+		if g.Config.Embedding.BatchSize > 0 {
+			batchSize = g.Config.Embedding.BatchSize
+		}
+		if g.Config.Embedding.RequestsPerSecond > 0 {
+			limiter = rate.NewLimiter(rate.Limit(g.Config.Embedding.RequestsPerSecond), 1)
+		}
+		if g.Config.Concurrency.EdgeWorkers > 0 {
+			workers = g.Config.Concurrency.EdgeWorkers
+		}
+	}
+
+	for start := 0; start < len(items); start += batchSize {
+		batch := items[start:min(start+batchSize, len(items))]
+		embeddings := make([]map[string]interface{}, len(batch))
+		forEachBounded(workers, len(batch), func(i int) {
+			if limiter != nil {
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+			}
+			vec, err := embedder.Embed(ctx, batch[i].text)
+			if err != nil || len(vec) == 0 {
+				log.Printf("Reembed: failed to embed %s: %v", batch[i].uuid, err)
+				return
+			}
+			embeddings[i] = map[string]interface{}{
+				"uuid":            batch[i].uuid,
+				"embedding":       vec,
+				"embedding_model": embeddingTag(embedder.Name, vec),
+			}
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("reembed interrupted: %w", err)
+		}
+
+		var names, facts []map[string]interface{}
+		for i, emb := range embeddings {
+			switch {
+			case emb == nil:
+				report.Failed++
+			case batch[i].fact:
+				facts = append(facts, emb)
+			default:
+				names = append(names, emb)
+			}
+		}
+		if len(names) > 0 {
+			if _, err := g.Driver.ExecuteQuery(ctx, driver.StageNameEmbeddingsQuery, map[string]interface{}{"embeddings": names}); err != nil {
+				return nil, fmt.Errorf("failed to stage name embeddings: %w", err)
+			}
+		}
+		if len(facts) > 0 {
+			if _, err := g.Driver.ExecuteQuery(ctx, driver.StageFactEmbeddingsQuery, map[string]interface{}{"embeddings": facts}); err != nil {
+				return nil, fmt.Errorf("failed to stage fact embeddings: %w", err)
+			}
+		}
+	}
+
+	if report.Failed == 0 && len(items) > 0 {
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.PromoteEmbeddingsQuery, map[string]interface{}{
+			"group_id": groupID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to promote embeddings: %w", err)
+		}
+		report.Promoted = true
+		g.invalidateSearchCache(ctx, groupID)
+	}
+
+	if err := g.saveReport(ctx, model.ReportKindReembed, groupID, now, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetReembedReport returns the group's latest reembed report, or ErrReportNotFound.
+func (g *Graphiti) GetReembedReport(ctx context.Context, groupID string) (*model.ReembedReport, error) {
+	var report model.ReembedReport
+	if err := g.loadReport(ctx, model.ReportKindReembed, groupID, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
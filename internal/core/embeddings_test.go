@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// embeddingTestGraph returns a graph whose entities, community and fact were
+// embedded by the 2-dimensional "old" model.
+func embeddingTestGraph(t *testing.T) (*Graphiti, mapEmbedder, mapEmbedder) {
+	ctx := context.Background()
+	oldModel := mapEmbedder{"Alice": {1, 0}, "Alice likes tea": {1, 0}, "tea": {1, 0}}
+	newModel := mapEmbedder{"Alice": {0, 0, 1}, "Alice likes tea": {0, 1, 0}, "tea": {0, 1, 0}, "Tea drinkers": {0, 1, 1}}
+
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "old", EmbedderClient: oldModel}})
+
+	_, err := g.SaveEntityNodeWithUUID(ctx, "alice", "Alice", "g1", "")
+	require.NoError(t, err)
+	_, err = g.SaveEntityNodeWithUUID(ctx, "tea", "tea", "g1", "")
+	require.NoError(t, err)
+	vec, tag, err := g.embed(ctx, "Alice likes tea")
+	require.NoError(t, err)
+	assert.Equal(t, "old@2", tag)
+	seedFact(t, g.Driver, "g1", "f1", "alice", "tea", "LIKES", "Alice likes tea", map[string]interface{}{
+		"created_at": "2024-01-01T00:00:00Z", "fact_embedding": vec, "fact_embedding_model": tag,
+	})
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveCommunityNodeQuery, map[string]interface{}{
+		"uuid": "c1", "name": "Tea drinkers", "group_id": "g1", "created_at": "2024-01-01T00:00:00Z",
+		"name_embedding": []float32{0, 1}, "name_embedding_model": "old@2",
+	})
+	require.NoError(t, err)
+	return g, oldModel, newModel
+}
+
+func TestSearchQueriesEachEmbeddingModel(t *testing.T) {
+	ctx := context.Background()
+	g, oldModel, newModel := embeddingTestGraph(t)
+
+	// The new model alone cannot see vectors of the old one
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}})
+	edges, err := g.Search(ctx, "g1", "tea")
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+
+	// Until re-embedded, the old model's vectors are searched through its fallback
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}, {Name: "old", EmbedderClient: oldModel}})
+	edges, err = g.Search(ctx, "g1", "tea")
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "f1", edges[0].UUID)
+}
+
+func TestSearchMatchesUntaggedEmbeddingsByDimension(t *testing.T) {
+	ctx := context.Background()
+	g, _, newModel := embeddingTestGraph(t)
+	seedFact(t, g.Driver, "g1", "f1", "alice", "tea", "LIKES", "Alice likes tea", map[string]interface{}{
+		"created_at": "2024-01-01T00:00:00Z", "fact_embedding": []float32{0, 1, 0}, "fact_embedding_model": "",
+	})
+
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}})
+	edges, err := g.Search(ctx, "g1", "tea")
+	require.NoError(t, err)
+	assert.Len(t, edges, 1)
+}
+
+func TestReembed(t *testing.T) {
+	ctx := context.Background()
+	g, oldModel, newModel := embeddingTestGraph(t)
+	g.Config.Embedding = config.EmbeddingConfig{BatchSize: 2, RequestsPerSecond: 1000}
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}, {Name: "old", EmbedderClient: oldModel}})
+
+	report, err := g.Reembed(ctx, "g1", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "new", report.Model)
+	assert.Equal(t, 2, report.Entities)
+	assert.Equal(t, 1, report.Communities)
+	assert.Equal(t, 1, report.Facts)
+	assert.False(t, report.Promoted)
+	_, err = g.GetReembedReport(ctx, "g1")
+	assert.ErrorIs(t, err, ErrReportNotFound, "dry runs are not saved")
+
+	report, err = g.Reembed(ctx, "g1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Entities)
+	assert.Equal(t, 1, report.Facts)
+	assert.Zero(t, report.Failed)
+	assert.True(t, report.Promoted)
+
+	saved, err := g.GetReembedReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, report.Facts, saved.Facts)
+
+	report, err = g.Reembed(ctx, "g1", "new", true)
+	require.NoError(t, err)
+	assert.Zero(t, report.Entities+report.Communities+report.Facts)
+
+	// The old model is no longer needed
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}})
+	edges, err := g.Search(ctx, "g1", "tea")
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "f1", edges[0].UUID)
+}
+
+func TestReembedKeepsLiveVectorsWhenAnyFails(t *testing.T) {
+	ctx := context.Background()
+	g, oldModel, newModel := embeddingTestGraph(t)
+	delete(newModel, "Tea drinkers")
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}, {Name: "old", EmbedderClient: oldModel}})
+
+	report, err := g.Reembed(ctx, "g1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.False(t, report.Promoted)
+
+	// Search still runs on the old vectors only
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: newModel}})
+	edges, err := g.Search(ctx, "g1", "tea")
+	require.NoError(t, err)
+	assert.Empty(t, edges)
+
+	report, err = g.Reembed(ctx, "g1", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Facts, "nothing was promoted")
+}
+
+func TestReembedToFallbackModel(t *testing.T) {
+	ctx := context.Background()
+	g, oldModel, newModel := embeddingTestGraph(t)
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "old", EmbedderClient: oldModel}, {Name: "new", EmbedderClient: newModel}})
+
+	report, err := g.Reembed(ctx, "g1", "new", false)
+	require.NoError(t, err)
+	assert.Equal(t, "new", report.Model)
+	assert.True(t, report.Promoted)
+
+	_, err = g.Reembed(ctx, "g1", "other", false)
+	assert.ErrorIs(t, err, ErrEmbeddingModelNotFound)
+}
+
+func TestReembedWithoutEmbedder(t *testing.T) {
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	_, err := g.Reembed(context.Background(), "g1", "", false)
+	assert.ErrorIs(t, err, ErrEmbeddingModelNotFound)
+}
+
+// batchEmbedder is a mapEmbedder that counts its calls and embeds batches.
+type batchEmbedder struct {
+	mapEmbedder
+	single  int
+	batches [][]string
+}
+
+func (b *batchEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	b.single++
+	return b.mapEmbedder.Embed(ctx, text)
+}
+
+func (b *batchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	b.batches = append(b.batches, texts)
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vecs[i] = b.mapEmbedder[text]
+	}
+	return vecs, nil
+}
+
+func TestBulkSearchEmbedsQueriesInOneBatch(t *testing.T) {
+	g, oldModel, newModel := embeddingTestGraph(t)
+	active := &batchEmbedder{mapEmbedder: newModel}
+	g.UseEmbedders([]llm.NamedEmbedder{{Name: "new", EmbedderClient: active}, {Name: "old", EmbedderClient: oldModel}})
+
+	results, err := g.BulkSearch(context.Background(), "g1", []model.BulkSearchQuery{
+		{QueryID: "a", Query: "tea"},
+		{QueryID: "b", Query: "Alice"},
+		{QueryID: "c", Query: "tea"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"tea", "Alice"}}, active.batches)
+	assert.Zero(t, active.single)
+	// Fallback embedders without batches still serve their vectors
+	require.Len(t, results["a"], 1)
+	assert.Equal(t, "f1", results["a"][0].UUID)
+	assert.Equal(t, results["a"], results["c"])
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+// encryptedPrefix marks a stored value as encrypted and bound to where it is
+// stored (see sealedFor). Values without it, such as those written before
+// encryption was enabled, are read as plaintext.
+const encryptedPrefix = "enc:v2:"
+
+// legacyEncryptedPrefix marks values encrypted without associated data. They
+// are only decrypted where values are configured to be stored encrypted, since
+// anything a caller sends there is encrypted again.
+const legacyEncryptedPrefix = "enc:v1:"
+
+const defaultEncryptionKeyEnv = "CARBON_ENCRYPTION_KEY"
+
+var ErrNoEncryptionKey = errors.New("value is encrypted but no encryption key is configured")
+
+// Cipher encrypts sensitive values before they are written to the graph.
+// Implement it to keep keys in a KMS; NewCipher reads a key from the environment.
+// Decrypt must fail unless given the associatedData the value was encrypted
+// with, as AEAD ciphers do, so a ciphertext copied elsewhere can't be read.
+type Cipher interface {
+	Encrypt(plaintext, associatedData []byte) ([]byte, error)
+	Decrypt(ciphertext, associatedData []byte) ([]byte, error)
+}
+
+// aesCipher is AES-GCM with a random nonce prepended to each ciphertext.
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher returns an AES-GCM cipher for a 16, 24 or 32-byte key.
+func NewAESCipher(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AES-GCM: %w", err)
+	}
+	return &aesCipher{aead: aead}, nil
+}
+
+func (c *aesCipher) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func (c *aesCipher) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	n := c.aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, errors.New("ciphertext too short")
+	}
+	return c.aead.Open(nil, ciphertext[:n], ciphertext[n:], associatedData)
+}
+
+// NewCipher returns the AES-GCM cipher of an [encryption] config, or nil when
+// encryption is disabled.
+func NewCipher(cfg config.EncryptionConfig) (Cipher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	env := cfg.KeyEnv
+	if env == "" {
+		env = defaultEncryptionKeyEnv
+	}
+	encoded := os.Getenv(env)
+	if encoded == "" {
+		return nil, fmt.Errorf("encryption is enabled but %s is not set", env)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", env, err)
+	}
+	return NewAESCipher(key)
+}
+
+// encryptsAttribute reports whether entity attribute name is stored encrypted.
+func (g *Graphiti) encryptsAttribute(name string) bool {
+	if g.Cipher == nil || g.Config == nil {
+		return false
+	}
+	attrs := g.Config.Encryption.Attributes
+	return slices.Contains(attrs, "*") || slices.Contains(attrs, name)
+}
+
+// encryptsEpisodes reports whether episode content is stored encrypted.
+func (g *Graphiti) encryptsEpisodes() bool {
+	return g.Cipher != nil && g.Config != nil && g.Config.Encryption.EpisodeContent
+}
+
+// sealedFor is the associated data binding an encrypted value to where it is
+// stored: an entity attribute ("<uuid>/<key>"), an episode ("<uuid>") or, for
+// payloads only carbon writes, nothing.
+func sealedFor(parts ...string) []byte {
+	return []byte(strings.Join(parts, "/"))
+}
+
+func (g *Graphiti) encryptText(s string, ad []byte) (string, error) {
+	sealed, err := g.Cipher.Encrypt([]byte(s), ad)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptText returns s, decrypted if it was stored encrypted for ad. Where
+// values are not configured to be encrypted (configured false), s may be a
+// caller's plaintext that merely looks encrypted, so it is returned as is
+// unless it decrypts for ad. Without a Cipher nothing can tell them apart.
+func (g *Graphiti) decryptText(s string, ad []byte, configured bool) (string, error) {
+	encoded, ok := strings.CutPrefix(s, encryptedPrefix)
+	if !ok {
+		if encoded, ok = strings.CutPrefix(s, legacyEncryptedPrefix); !ok {
+			return s, nil
+		}
+		if g.Cipher != nil && !configured {
+			return s, nil
+		}
+		ad = nil
+	}
+	if g.Cipher == nil {
+		return "", ErrNoEncryptionKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err == nil {
+		var plain []byte
+		if plain, err = g.Cipher.Decrypt(sealed, ad); err == nil {
+			return string(plain), nil
+		}
+	}
+	if !configured {
+		return s, nil
+	}
+	return "", fmt.Errorf("failed to decrypt: %w", err)
+}
+
+// encryptEpisode returns the content of episode uuid as it is stored.
+func (g *Graphiti) encryptEpisode(uuid, content string) (string, error) {
+	if !g.encryptsEpisodes() || content == "" {
+		return content, nil
+	}
+	return g.encryptText(content, sealedFor(uuid))
+}
+
+// decryptEpisode returns the stored content of episode uuid as it was sent.
+func (g *Graphiti) decryptEpisode(uuid, stored string) (string, error) {
+	return g.decryptText(stored, sealedFor(uuid), g.encryptsEpisodes())
+}
+
+// encryptPayload and decryptPayload store what carbon itself serializes, like
+// dead letters and ingest jobs, as encrypted as episode content.
+func (g *Graphiti) encryptPayload(payload string) (string, error) {
+	if !g.encryptsEpisodes() || payload == "" {
+		return payload, nil
+	}
+	return g.encryptText(payload, nil)
+}
+
+func (g *Graphiti) decryptPayload(stored string) (string, error) {
+	return g.decryptText(stored, nil, true)
+}
+
+// encryptAttributes returns a copy of entity uuid's attributes with the
+// configured ones replaced by their encrypted JSON encoding. Values are always
+// encrypted, even those that look encrypted already: callers only ever hold
+// decrypted attributes.
+func (g *Graphiti) encryptAttributes(uuid string, attributes map[string]interface{}) (map[string]interface{}, error) {
+	if g.Cipher == nil || len(attributes) == 0 {
+		return attributes, nil
+	}
+	out := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		out[k] = v
+		if !g.encryptsAttribute(k) {
+			continue
+		}
+		plain, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute %s: %w", k, err)
+		}
+		if out[k], err = g.encryptText(string(plain), sealedFor(uuid, k)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// decryptAttributes decrypts the encrypted values of entity uuid's attributes in place.
+func (g *Graphiti) decryptAttributes(uuid string, attributes map[string]interface{}) error {
+	for k, v := range attributes {
+		s, ok := v.(string)
+		if !ok || !(strings.HasPrefix(s, encryptedPrefix) || strings.HasPrefix(s, legacyEncryptedPrefix)) {
+			continue
+		}
+		plain, err := g.decryptText(s, sealedFor(uuid, k), g.encryptsAttribute(k))
+		if err != nil {
+			return fmt.Errorf("attribute %s: %w", k, err)
+		}
+		if plain == s {
+			continue // Plaintext that looks encrypted
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(plain), &value); err != nil {
+			return fmt.Errorf("failed to decode attribute %s: %w", k, err)
+		}
+		attributes[k] = value
+	}
+	return nil
+}
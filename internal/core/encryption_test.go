@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCipher(t *testing.T) {
+	c, err := NewCipher(config.EncryptionConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	t.Setenv("TEST_CARBON_KEY", "")
+	_, err = NewCipher(config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_CARBON_KEY"})
+	assert.ErrorContains(t, err, "TEST_CARBON_KEY is not set")
+
+	t.Setenv("TEST_CARBON_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+	_, err = NewCipher(config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_CARBON_KEY"})
+	assert.ErrorContains(t, err, "invalid encryption key")
+
+	t.Setenv("TEST_CARBON_KEY", base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	c, err = NewCipher(config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_CARBON_KEY"})
+	require.NoError(t, err)
+	sealed, err := c.Encrypt([]byte("secret"), []byte("alice/diagnosis"))
+	require.NoError(t, err)
+	plain, err := c.Decrypt(sealed, []byte("alice/diagnosis"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plain))
+	_, err = c.Decrypt(sealed, []byte("bob/diagnosis"))
+	assert.Error(t, err)
+}
+
+func TestEncryptedAtRest(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewAESCipher(make([]byte, 32))
+	require.NoError(t, err)
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s"},
+		Encryption: config.EncryptionConfig{Attributes: []string{"diagnosis"}, EpisodeContent: true},
+	}
+	g := NewGraphiti(driver.NewMemoryDriver(), &flakyLLM{marker: "never"}, nil, nil, cfg)
+	g.Cipher = cipher
+
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{
+		UUID: "alice", Name: "Alice", GroupID: "g1",
+		Attributes: map[string]interface{}{"diagnosis": "flu", "city": "Paris"},
+	}))
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityNodeQuery, map[string]interface{}{"uuid": "alice"})
+	require.NoError(t, err)
+	stored, _ := res.Records[0].Get("attributes")
+	assert.NotContains(t, stored, "flu")
+	assert.Contains(t, stored, `"city":"Paris"`)
+
+	alice, err := g.GetEntity(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"diagnosis": "flu", "city": "Paris"}, alice.Attributes)
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice has the flu.", "", ""))
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{"group_id": "g1", "limit": 1})
+	require.NoError(t, err)
+	content, _ := res.Records[0].Get("content")
+	assert.True(t, strings.HasPrefix(content.(string), encryptedPrefix))
+	episodes, err := g.GetEpisodes(ctx, "g1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice has the flu.", episodes[0].Content)
+
+	job, err := g.CreateIngestJob(ctx, "g1", []model.EpisodeData{{Content: "Bob has a cold."}})
+	require.NoError(t, err)
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.GetIngestJobBatchQuery, map[string]interface{}{"uuid": ingestJobBatchID(job, 0)})
+	require.NoError(t, err)
+	payload, _ := res.Records[0].Get("episodes")
+	assert.True(t, strings.HasPrefix(payload.(string), encryptedPrefix))
+	queued, err := g.getIngestJobBatch(ctx, job, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Bob has a cold.", queued[0].Content)
+
+	// Without the key, encrypted values can't be read
+	g.Cipher = nil
+	_, err = g.GetEntity(ctx, "alice")
+	assert.ErrorIs(t, err, ErrNoEncryptionKey)
+	_, err = g.GetEpisodes(ctx, "g1", 1)
+	assert.ErrorIs(t, err, ErrNoEncryptionKey)
+}
+
+func TestSearchFilterOnEncryptedAttribute(t *testing.T) {
+	g := filterTestGraph(t)
+	cipher, err := NewAESCipher(make([]byte, 32))
+	require.NoError(t, err)
+	g.Cipher = cipher
+	g.Config.Encryption.Attributes = []string{"*"}
+	alice, err := g.GetEntity(context.Background(), "alice")
+	require.NoError(t, err)
+	require.NoError(t, g.saveEntity(context.Background(), *alice))
+
+	assert.Equal(t, []string{"e1", "e3"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"location": "Paris"}}))
+}
+
+func TestEncryptionRejectsSpoofedValues(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewAESCipher(make([]byte, 32))
+	require.NoError(t, err)
+	cfg := &config.Config{Encryption: config.EncryptionConfig{Attributes: []string{"diagnosis"}}}
+	g := NewGraphiti(driver.NewMemoryDriver(), &flakyLLM{marker: "never"}, nil, nil, cfg)
+	g.Cipher = cipher
+
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{
+		UUID: "alice", Name: "Alice", GroupID: "g1",
+		Attributes: map[string]interface{}{"diagnosis": "flu"},
+	}))
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityNodeQuery, map[string]interface{}{"uuid": "alice"})
+	require.NoError(t, err)
+	var stored map[string]interface{}
+	require.NoError(t, decodeJSONField(res.Records[0], "attributes", &stored))
+	sealed := stored["diagnosis"].(string)
+	legacy, err := cipher.Encrypt([]byte(`"flu"`), nil)
+	require.NoError(t, err)
+	legacySealed := legacyEncryptedPrefix + base64.StdEncoding.EncodeToString(legacy)
+
+	// Alice's ciphertext copied to Bob, configured or not, and values that only
+	// look encrypted read back as sent
+	copied := map[string]interface{}{"diagnosis": sealed, "note": sealed, "legacy": legacySealed}
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "bob", Name: "Bob", GroupID: "g1", Attributes: copied}))
+	bob, err := g.GetEntity(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, copied, bob.Attributes)
+
+	res, err = g.Driver.ExecuteReadQuery(ctx, driver.GetEntityNodeQuery, map[string]interface{}{"uuid": "bob"})
+	require.NoError(t, err)
+	require.NoError(t, decodeJSONField(res.Records[0], "attributes", &stored))
+	assert.NotEqual(t, sealed, stored["diagnosis"], "configured values are always encrypted")
+
+	// Values written before associated data was used are still read
+	stored["diagnosis"] = legacySealed
+	require.NoError(t, g.decryptAttributes("carol", stored))
+	assert.Equal(t, "flu", stored["diagnosis"])
+}
@@ -0,0 +1,281 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrEntityNotFound = errors.New("entity not found")
+
+// ErrVersionConflict is returned by UpdateEntity when the entity changed
+// since the version the update was based on.
+var ErrVersionConflict = errors.New("entity version conflict")
+
+// GetEntity returns an entity node by UUID, or ErrEntityNotFound.
+func (g *Graphiti) GetEntity(ctx context.Context, uuid string) (*model.EntityNode, error) {
+	node, err := g.getEntity(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	node.Attributes = g.accessFilter(ctx).attrs(node.Attributes)
+	return node, nil
+}
+
+// ListEntities returns the UUID, name and summary of every entity in the
+// group, ordered by name.
+func (g *Graphiti) ListEntities(ctx context.Context, groupID string) ([]model.EntityNode, error) {
+	nodes, err := g.getGroupNodes(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entities: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].UUID < nodes[j].UUID
+	})
+	return nodes, nil
+}
+
+// getEntity is GetEntity with every attribute, for callers that write the node back.
+func (g *Graphiti) getEntity(ctx context.Context, uuid string) (*model.EntityNode, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityNodeQuery, map[string]interface{}{
+		"uuid": uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entity: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrEntityNotFound
+	}
+
+	var node model.EntityNode
+	if err := driver.ScanRecord(res.Records[0], &node); err != nil {
+		return nil, fmt.Errorf("failed to read entity: %w", err)
+	}
+	if err := decodeJSONField(res.Records[0], "attributes", &node.Attributes); err != nil {
+		return nil, fmt.Errorf("invalid attributes for entity %s: %w", uuid, err)
+	}
+	if err := g.decryptAttributes(uuid, node.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to read entity %s: %w", uuid, err)
+	}
+	return &node, nil
+}
+
+// UpdateEntity applies patch to an entity and returns it with its new version.
+// A patch with a Version fails with ErrVersionConflict if the entity was
+// written since that version was read, by a client or by ingestion.
+func (g *Graphiti) UpdateEntity(ctx context.Context, uuid string, patch model.EntityPatch) (*model.EntityNode, error) {
+	node, err := g.getEntity(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Version != nil && *patch.Version != node.Version {
+		return nil, ErrVersionConflict
+	}
+	params := map[string]interface{}{
+		"uuid":                 uuid,
+		"version":              nil,
+		"name_embedding":       nil,
+		"name_embedding_model": nil,
+	}
+	if patch.Version != nil {
+		params["version"] = *patch.Version
+	}
+	if patch.Name != nil && *patch.Name != node.Name {
+		node.Name = *patch.Name
+		if emb, embeddingModel, err := g.embed(ctx, node.Name); err == nil && emb != nil {
+			params["name_embedding"] = emb
+			params["name_embedding_model"] = embeddingModel
+		}
+	}
+	if patch.Summary != nil {
+		node.Summary = *patch.Summary
+	}
+	if len(patch.Attributes) > 0 && node.Attributes == nil {
+		node.Attributes = make(map[string]interface{}, len(patch.Attributes))
+	}
+	for k, v := range patch.Attributes {
+		if v == nil {
+			delete(node.Attributes, k)
+		} else {
+			node.Attributes[k] = v
+		}
+	}
+
+	attributes, err := g.encryptAttributes(node.UUID, node.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	attrsJSON := []byte("{}")
+	if len(attributes) > 0 {
+		if attrsJSON, err = json.Marshal(attributes); err != nil {
+			return nil, fmt.Errorf("failed to encode attributes: %w", err)
+		}
+	}
+	params["name"] = node.Name
+	params["summary"] = node.Summary
+	params["attributes"] = string(attrsJSON)
+
+	res, err := g.Driver.ExecuteQuery(ctx, driver.UpdateEntityNodeQuery, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update entity: %w", err)
+	}
+	if len(res.Records) == 0 {
+		if patch.Version != nil {
+			return nil, ErrVersionConflict
+		}
+		return nil, ErrEntityNotFound // Deleted since it was read
+	}
+	var updated struct {
+		Version int `db:"version"`
+	}
+	if err := driver.ScanRecord(res.Records[0], &updated); err != nil {
+		return nil, fmt.Errorf("failed to read entity version: %w", err)
+	}
+	node.Version = updated.Version
+	g.invalidateSearchCache(ctx, node.GroupID)
+	if err := g.recordChange(ctx, node.GroupID, model.ChangeKindEntity, uuid, model.ChangeOpUpdate); err != nil {
+		return nil, err
+	}
+	node.Attributes = g.accessFilter(ctx).attrs(node.Attributes)
+	return node, nil
+}
+
+// RegenerateSummary rebuilds an entity's summary from scratch using all of its
+// currently valid facts, discarding the incrementally maintained summary. Use it
+// after merges, invalidations or prompt changes. An entity without valid facts
+// gets an empty summary.
+func (g *Graphiti) RegenerateSummary(ctx context.Context, uuid string) (*model.EntityNode, error) {
+	node, err := g.getEntity(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	group, err := g.GetGroup(ctx, node.GroupID)
+	if err != nil && !errors.Is(err, ErrGroupNotFound) {
+		return nil, err
+	}
+	g = g.forGroup(group)
+
+	facts, err := g.entityFacts(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	node.Summary = ""
+	if len(facts) > 0 {
+		summary, err := g.Summarizer.SummarizeNode(ctx, *node, facts)
+		if err != nil {
+			return nil, err
+		}
+		node.Summary = summary
+	}
+	if err := g.saveEntity(ctx, *node); err != nil {
+		return nil, fmt.Errorf("failed to save entity: %w", err)
+	}
+	node.Version++
+	node.Attributes = g.accessFilter(ctx).attrs(node.Attributes)
+	return node, nil
+}
+
+// entityFacts returns the currently valid facts touching an entity, oldest first.
+func (g *Graphiti) entityFacts(ctx context.Context, uuid string) ([]string, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityFactsQuery, map[string]interface{}{
+		"uuid": uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entity facts: %w", err)
+	}
+	var facts []string
+	for _, rec := range res.Records {
+		if fact, _ := rec.Get("fact"); fact != nil {
+			if s, ok := fact.(string); ok && s != "" {
+				facts = append(facts, s)
+			}
+		}
+	}
+	return facts, nil
+}
+
+var ErrChecklistNotFound = errors.New("checklist not found")
+
+// FindFactGaps reports which checklist attributes and relations an entity
+// lacks, so an agent can decide what to ask next. When name is set the
+// checklist comes from the group settings and extra is merged into it.
+func (g *Graphiti) FindFactGaps(ctx context.Context, uuid, name string, extra model.FactChecklist) (*model.FactGaps, error) {
+	node, err := g.GetEntity(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	checklist := extra
+	if name != "" {
+		group, err := g.GetGroup(ctx, node.GroupID)
+		if err != nil && !errors.Is(err, ErrGroupNotFound) {
+			return nil, err
+		}
+		named, ok := model.FactChecklist{}, false
+		if group != nil {
+			named, ok = group.Settings.Checklists[name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrChecklistNotFound, name)
+		}
+		checklist.Attributes = append(append([]string(nil), named.Attributes...), extra.Attributes...)
+		checklist.Relations = append(append([]string(nil), named.Relations...), extra.Relations...)
+	}
+	checklist.Attributes = appendUnique(nil, checklist.Attributes...)
+	checklist.Relations = appendUnique(nil, checklist.Relations...)
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityFactsQuery, map[string]interface{}{
+		"uuid": uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entity facts: %w", err)
+	}
+	facts, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity facts: %w", err)
+	}
+	facts = g.accessFilter(ctx).edges(facts)
+
+	gaps := &model.FactGaps{
+		UUID:              node.UUID,
+		Name:              node.Name,
+		Checklist:         name,
+		KnownAttributes:   map[string]interface{}{},
+		KnownRelations:    map[string][]string{},
+		MissingAttributes: []string{},
+		MissingRelations:  []string{},
+	}
+	for _, attr := range checklist.Attributes {
+		if v, ok := node.Attributes[attr]; ok && v != nil && v != "" {
+			gaps.KnownAttributes[attr] = v
+		} else {
+			gaps.MissingAttributes = append(gaps.MissingAttributes, attr)
+		}
+	}
+	for _, rel := range checklist.Relations {
+		for _, f := range facts {
+			if normalizeRelation(f.Name) == normalizeRelation(rel) {
+				gaps.KnownRelations[rel] = append(gaps.KnownRelations[rel], f.Fact)
+			}
+		}
+		if _, ok := gaps.KnownRelations[rel]; !ok {
+			gaps.MissingRelations = append(gaps.MissingRelations, rel)
+		}
+	}
+	return gaps, nil
+}
+
+// normalizeRelation maps "works at", "Works_At" and "WORKS_AT" to one key.
+func normalizeRelation(rel string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(strings.ReplaceAll(rel, "_", " ")), "_"))
+}
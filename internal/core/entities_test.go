@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegenerateSummary_UsesAllValidFacts(t *testing.T) {
+	ctx := context.Background()
+	var summaryPrompt string
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return threeSourceEdges
+		}
+		if strings.HasPrefix(prompt, "summarize") {
+			summaryPrompt = prompt
+		}
+		return `{"summary": "rebuilt"}`
+	})
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep1", "g1", "", nil, time.Now().UTC()))
+
+	// Invalidate one of a's two facts
+	edges, err := g.getEdgesFromSource(ctx, "c")
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	require.NoError(t, g.invalidateEdge(ctx, edges[0].UUID, time.Now().UTC()))
+
+	node, err := g.RegenerateSummary(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "rebuilt", node.Summary)
+	assert.Contains(t, summaryPrompt, "a knows b")
+	assert.NotContains(t, summaryPrompt, "c knows a")
+	assert.NotContains(t, summaryPrompt, "rebuilt") // Starts from an empty summary
+
+	stored, err := g.GetEntity(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "rebuilt", stored.Summary)
+
+	_, err = g.RegenerateSummary(ctx, "missing")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
+
+func TestFindFactGaps(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	now := time.Now().UTC()
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "alice", Name: "Alice", GroupID: "g1", CreatedAt: now,
+		Attributes: map[string]interface{}{"email": "alice@example.com", "phone": ""}}))
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: "acme", Name: "Acme", GroupID: "g1", CreatedAt: now}))
+	for _, e := range []struct{ uuid, name, fact, invalidAt string }{
+		{"e1", "WORKS_AT", "Alice works at Acme", ""},
+		{"e2", "LIVES_IN", "Alice lived near Acme", now.Format(time.RFC3339)},
+	} {
+		seedFact(t, g.Driver, "g1", e.uuid, "alice", "acme", e.name, e.fact, map[string]interface{}{"invalid_at": e.invalidAt})
+	}
+	_, err := g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{
+		Checklists: map[string]model.FactChecklist{
+			"Person": {Attributes: []string{"email", "phone"}, Relations: []string{"works at", "LIVES_IN"}},
+		},
+	}})
+	require.NoError(t, err)
+
+	gaps, err := g.FindFactGaps(ctx, "alice", "Person", model.FactChecklist{Attributes: []string{"birthday", "email"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"email": "alice@example.com"}, gaps.KnownAttributes)
+	assert.Equal(t, []string{"phone", "birthday"}, gaps.MissingAttributes)
+	assert.Equal(t, map[string][]string{"works at": {"Alice works at Acme"}}, gaps.KnownRelations)
+	assert.Equal(t, []string{"LIVES_IN"}, gaps.MissingRelations, "invalidated facts do not count")
+
+	_, err = g.FindFactGaps(ctx, "alice", "Company", model.FactChecklist{})
+	assert.ErrorIs(t, err, ErrChecklistNotFound)
+	_, err = g.FindFactGaps(ctx, "missing", "", model.FactChecklist{Relations: []string{"WORKS_AT"}})
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
+
+func TestUpdateEntity_Versions(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(string) string { return `{}` }), nil, nil, &config.Config{})
+	require.NoError(t, g.saveEntity(ctx, model.EntityNode{
+		UUID: "a", Name: "Alice", GroupID: "g1", CreatedAt: time.Now().UTC(),
+		Attributes: map[string]interface{}{"city": "Berlin", "age": 30.0},
+	}))
+	node, err := g.GetEntity(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, node.Version)
+
+	// Two clients edit version 1; the second one loses
+	name, v := "Alice Smith", node.Version
+	updated, err := g.UpdateEntity(ctx, "a", model.EntityPatch{Name: &name, Attributes: map[string]interface{}{"age": nil, "role": "cto"}, Version: &v})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, map[string]interface{}{"city": "Berlin", "role": "cto"}, updated.Attributes)
+	summary := "stale"
+	_, err = g.UpdateEntity(ctx, "a", model.EntityPatch{Summary: &summary, Version: &v})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// Ingestion writes bump the version too
+	require.NoError(t, g.saveEntity(ctx, *updated))
+	v = updated.Version
+	_, err = g.UpdateEntity(ctx, "a", model.EntityPatch{Summary: &summary, Version: &v})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// Without a version the update always applies
+	updated, err = g.UpdateEntity(ctx, "a", model.EntityPatch{Summary: &summary})
+	require.NoError(t, err)
+	assert.Equal(t, 4, updated.Version)
+	stored, err := g.GetEntity(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Smith", stored.Name)
+	assert.Equal(t, "stale", stored.Summary)
+	assert.Equal(t, 4, stored.Version)
+
+	_, err = g.UpdateEntity(ctx, "missing", model.EntityPatch{Summary: &summary})
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// GroupByEntity groups ranked facts under their subject entity, with its name
+// and summary. Entities come in the order of their best-ranked fact and keep
+// their facts' order. Facts whose subject was deleted are grouped under its UUID.
+func (g *Graphiti) GroupByEntity(ctx context.Context, edges []model.EntityEdge) ([]model.EntityResults, error) {
+	var grouped []model.EntityResults
+	index := make(map[string]int)
+	for _, e := range edges {
+		i, ok := index[e.SourceUUID]
+		if !ok {
+			results := model.EntityResults{UUID: e.SourceUUID}
+			node, err := g.GetEntity(ctx, e.SourceUUID)
+			if err != nil && !errors.Is(err, ErrEntityNotFound) {
+				return nil, err
+			}
+			if node != nil {
+				results.Name, results.Summary = node.Name, node.Summary
+			}
+			i = len(grouped)
+			index[e.SourceUUID] = i
+			grouped = append(grouped, results)
+		}
+		grouped[i].Facts = append(grouped[i].Facts, e)
+	}
+	return grouped, nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByEntity(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	summary := "Alice is an engineer."
+	_, err := g.UpdateEntity(ctx, "alice", model.EntityPatch{Summary: &summary})
+	require.NoError(t, err)
+
+	edges, err := g.Search(ctx, "g1", "fact")
+	require.NoError(t, err)
+	edges = append(edges, model.EntityEdge{UUID: "e4", SourceUUID: "deleted", TargetUUID: "acme"})
+	grouped, err := g.GroupByEntity(ctx, edges)
+	require.NoError(t, err)
+
+	require.Len(t, grouped, 3)
+	facts := func(r model.EntityResults) []string {
+		var uuids []string
+		for _, f := range r.Facts {
+			uuids = append(uuids, f.UUID)
+		}
+		return uuids
+	}
+	assert.Equal(t, "alice", grouped[0].Name)
+	assert.Equal(t, summary, grouped[0].Summary)
+	assert.Equal(t, []string{"e1", "e3"}, facts(grouped[0]))
+	assert.Equal(t, "bob", grouped[1].Name)
+	assert.Equal(t, []string{"e2"}, facts(grouped[1]))
+	assert.Equal(t, model.EntityResults{UUID: "deleted", Facts: edges[3:]}, grouped[2])
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// episodeContext holds the previous episodes extraction may see, and for
+// "relevant" selection their embeddings to rank them against new content.
+type episodeContext struct {
+	g        *Graphiti
+	episodes []string
+	vectors  [][]float32
+	limit    int
+}
+
+// loadEpisodeContext fetches the candidate context episodes of a group under
+// the [ingest] context settings, excluding excludeUUID.
+func (g *Graphiti) loadEpisodeContext(ctx context.Context, groupID, excludeUUID string) (*episodeContext, error) {
+	limit, candidates, selection := 5, 50, ""
+	if g.Config != nil {
+		if g.Config.Ingest.ContextEpisodes > 0 {
+			limit = g.Config.Ingest.ContextEpisodes
+		}
+		if g.Config.Ingest.ContextCandidates > 0 {
+			candidates = g.Config.Ingest.ContextCandidates
+		}
+		selection = g.Config.Ingest.ContextSelection
+	}
+	switch selection {
+	case "", model.ContextRecent:
+	case model.ContextRelevant:
+		if g.Embedder != nil && candidates > limit {
+			break
+		}
+		selection = model.ContextRecent
+	default:
+		return nil, fmt.Errorf("invalid [ingest] context_selection '%s'", selection)
+	}
+
+	c := &episodeContext{g: g, limit: limit}
+	if selection != model.ContextRelevant {
+		episodes, err := g.retrievePreviousEpisodes(ctx, groupID, excludeUUID, limit)
+		c.episodes = episodes
+		return c, err
+	}
+
+	episodes, err := g.retrievePreviousEpisodes(ctx, groupID, excludeUUID, candidates)
+	if err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(episodes))
+	for i, ep := range episodes {
+		if vectors[i], err = g.Embedder.Embed(ctx, ep); err != nil {
+			log.Printf("Failed to embed context episode, using the most recent: %v", err)
+			vectors = nil
+			break
+		}
+	}
+	c.episodes, c.vectors = episodes, vectors
+	return c, nil
+}
+
+// For returns the context episodes for content, most relevant (or most recent)
+// first so prompt budgeting drops the least useful ones.
+func (c *episodeContext) For(ctx context.Context, content string) []string {
+	if c == nil {
+		return nil
+	}
+	recent := c.episodes[:min(c.limit, len(c.episodes))]
+	if c.vectors == nil {
+		return recent
+	}
+	vec, err := c.g.Embedder.Embed(ctx, content)
+	if err != nil || len(vec) == 0 {
+		log.Printf("Failed to embed episode for context selection, using the most recent: %v", err)
+		return recent
+	}
+
+	order := make([]int, len(c.episodes))
+	scores := make([]float64, len(c.episodes))
+	for i := range order {
+		order[i] = i
+		scores[i] = cosine32(vec, c.vectors[i])
+	}
+	// Stable, so equally similar episodes stay newest first
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+	selected := make([]string, 0, len(recent))
+	for _, i := range order[:len(recent)] {
+		selected = append(selected, c.episodes[i])
+	}
+	return selected
+}
+
+// resolveCoreferences rewrites content with its pronouns and references
+// resolved to the entity names of the previous episodes, when [ingest]
+// resolve_coreferences is on. Extraction falls back to content as written if
+// resolving fails.
+func (g *Graphiti) resolveCoreferences(ctx context.Context, content string, previousEpisodes []string) string {
+	if g.Config == nil || !g.Config.Ingest.ResolveCoreferences || g.Config.Extraction.Coreference == "" || len(previousEpisodes) == 0 {
+		return content
+	}
+	defer timeStage(ctx, "resolve_coreferences")()
+	resolved, err := g.Extractor.ResolveCoreferences(ctx, content, previousEpisodes)
+	if err != nil {
+		log.Printf("Coreference resolution failed, extracting from the content as written: %v", err)
+		return content
+	}
+	return resolved
+}
+
+// cosine32 returns the cosine similarity of a and b, or 0 if either is empty or zero.
+func cosine32(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpisodeContextSelection(t *testing.T) {
+	embedder := mapEmbedder{
+		"we had a tea party": {1, 0},
+		"coffee at noon":     {0, 1},
+		"more tea please":    {1, 0.1},
+	}
+	cfg := &config.Config{Ingest: config.IngestConfig{ContextEpisodes: 1, ContextSelection: model.ContextRelevant}}
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, embedder, nil, cfg)
+
+	ctx := context.Background()
+	start := time.Now().UTC()
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "ep1", "g1", "we had a tea party", start))
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep2", "ep2", "g1", "coffee at noon", start.Add(time.Minute)))
+
+	// The older but similar episode wins over the latest one
+	episodeCtx, err := g.loadEpisodeContext(ctx, "g1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"we had a tea party"}, episodeCtx.For(ctx, "more tea please"))
+
+	g.Config.Ingest.ContextSelection = model.ContextRecent
+	episodeCtx, err = g.loadEpisodeContext(ctx, "g1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"coffee at noon"}, episodeCtx.For(ctx, "more tea please"))
+
+	// Without an embedder, relevant selection falls back to the most recent
+	g.Config.Ingest.ContextSelection = model.ContextRelevant
+	g.Embedder = nil
+	episodeCtx, err = g.loadEpisodeContext(ctx, "g1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"coffee at noon"}, episodeCtx.For(ctx, "more tea please"))
+
+	g.Config.Ingest.ContextSelection = "random"
+	_, err = g.loadEpisodeContext(ctx, "g1", "")
+	assert.ErrorContains(t, err, "context_selection")
+}
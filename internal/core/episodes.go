@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var ErrEpisodeNotFound = errors.New("episode not found")
+
+// GetEpisodes returns the group's lastN most recent episodes, newest first.
+// Offloaded content is left empty with its ContentRef set; GetProvenance
+// fetches it.
+func (g *Graphiti) GetEpisodes(ctx context.Context, groupID string, lastN int) ([]model.EpisodicNode, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"limit":    lastN,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	return g.readEpisodes(ctx, res)
+}
+
+// GetEpisode returns an episode by UUID with offloaded content fetched, or
+// ErrEpisodeNotFound.
+func (g *Graphiti) GetEpisode(ctx context.Context, uuid string) (*model.EpisodicNode, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesByUUIDQuery, map[string]interface{}{
+		"uuids": []string{uuid},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episode: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrEpisodeNotFound
+	}
+	var ep model.EpisodicNode
+	if err := driver.ScanRecord(res.Records[0], &ep); err != nil {
+		return nil, fmt.Errorf("failed to read episode: %w", err)
+	}
+	if ep.Content, ep.ContentRef, err = g.readEpisodeContent(ctx, ep, true); err != nil {
+		return nil, fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+	}
+	return &ep, nil
+}
+
+// readEpisodes scans episode listings, leaving offloaded content out.
+func (g *Graphiti) readEpisodes(ctx context.Context, res neo4j.EagerResult) ([]model.EpisodicNode, error) {
+	episodes, err := driver.ScanRecords[model.EpisodicNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read episodes: %w", err)
+	}
+	for i := range episodes {
+		ep := &episodes[i]
+		if ep.Content, ep.ContentRef, err = g.readEpisodeContent(ctx, *ep, false); err != nil {
+			return nil, fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+		}
+	}
+	return episodes, nil
+}
+
+// DeleteEpisode removes an episode and its MENTIONS edges, or returns
+// ErrEpisodeNotFound. Entities and facts it produced are kept; entities left
+// unreferenced are picked up by orphan GC.
+func (g *Graphiti) DeleteEpisode(ctx context.Context, uuid string) error {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.DeleteEpisodeQuery, map[string]interface{}{"uuid": uuid})
+	if err != nil {
+		return fmt.Errorf("failed to delete episode: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return ErrEpisodeNotFound
+	}
+	var ep model.EpisodicNode
+	if err := driver.ScanRecord(res.Records[0], &ep); err != nil {
+		return fmt.Errorf("failed to read deleted episode: %w", err)
+	}
+	if err := g.deleteEpisodeContent(ctx, ep); err != nil {
+		return fmt.Errorf("failed to delete offloaded content: %w", err)
+	}
+	return g.recordDeletions(ctx, model.ChangeKindEpisode, res)
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// factLifetime returns how long facts of relation stay current under
+// [ingest] fact_lifetimes and the group's settings, or 0 if they don't expire.
+// Relation types are compared ignoring case.
+func (g *Graphiti) factLifetime(relation string) time.Duration {
+	if g.Config == nil {
+		return 0
+	}
+	for r, hours := range g.Config.Ingest.FactLifetimes {
+		if strings.EqualFold(r, relation) && hours > 0 {
+			return time.Duration(hours) * time.Hour
+		}
+	}
+	return 0
+}
+
+// renewExpiry pushes back the expiry of a transient fact stated again at now.
+// Facts of relations without a lifetime are left alone.
+func (g *Graphiti) renewExpiry(ctx context.Context, edgeUUID, relation string, now time.Time) error {
+	lifetime := g.factLifetime(relation)
+	if lifetime == 0 {
+		return nil
+	}
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.SetEdgeExpiryQuery, map[string]interface{}{
+		"uuid":       edgeUUID,
+		"expired_at": now.Add(lifetime).Format(time.RFC3339),
+	}); err != nil {
+		return fmt.Errorf("failed to renew expiry of fact %s: %w", edgeUUID, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactLifetimes(t *testing.T) {
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return `{"extracted_edges": [
+				{"source_node_uuid": "a", "target_node_uuid": "b", "relation_type": "IS_VISITING", "fact": "a is visiting b"},
+				{"source_node_uuid": "b", "target_node_uuid": "c", "relation_type": "KNOWS", "fact": "b knows c"}
+			]}`
+		}
+		return `{"summary": "updated"}`
+	})
+	g.Config.Ingest.FactLifetimes = map[string]int{"is_visiting": 1}
+	ctx := context.Background()
+	facts := func(filter *model.SearchFilter) map[string]*time.Time {
+		edges, err := g.SearchWithFilter(ctx, "g1", "", filter)
+		require.NoError(t, err)
+		found := make(map[string]*time.Time)
+		for _, e := range edges {
+			found[e.Fact] = e.ExpiredAt
+		}
+		return found
+	}
+
+	// Stated two hours ago, the visit expired an hour ago
+	then := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep1", "g1", "", nil, then))
+	assert.Equal(t, map[string]*time.Time{"b knows c": nil}, facts(nil))
+	all := facts(&model.SearchFilter{IncludeExpired: true})
+	require.Contains(t, all, "a is visiting b")
+	require.NotNil(t, all["a is visiting b"])
+	assert.Equal(t, then.Add(time.Hour), all["a is visiting b"].UTC())
+
+	// Stating it again renews it
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep2", "g1", "", nil, time.Now().UTC()))
+	assert.Len(t, facts(nil), 2)
+
+	// Groups add or override lifetimes
+	scoped := g.forGroup(&model.GroupNode{GroupID: "g1", Settings: model.GroupSettings{FactLifetimes: map[string]int{"STAYS_AT": 24}}})
+	assert.Equal(t, 24*time.Hour, scoped.factLifetime("STAYS_AT"))
+	assert.Equal(t, time.Hour, scoped.factLifetime("IS_VISITING"))
+	assert.Zero(t, g.factLifetime("STAYS_AT"))
+
+	err := validateGroupSettings(model.GroupSettings{FactLifetimes: map[string]int{"IS_VISITING": -1}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
@@ -2,6 +2,7 @@ package extraction
 
 import (
 	"context"
+	"strings"
 	"testing"
 	
 	"github.com/agenthands/carbon/internal/config"
@@ -71,7 +72,7 @@ func TestExtractEdges(t *testing.T) {
 		{UUID: "uuid-2", Name: "Bob"},
 	}
 	
-	edges, err := extractor.ExtractEdges(ctx, nodes, nil)
+	edges, err := extractor.ExtractEdges(ctx, nodes, "", nil)
 	
 	assert.NoError(t, err)
 	assert.Len(t, edges, 1)
@@ -79,3 +80,130 @@ func TestExtractEdges(t *testing.T) {
 	assert.Equal(t, "uuid-2", edges[0].TargetNodeUUID)
 	assert.Equal(t, "FRIEND", edges[0].RelationType)
 }
+
+// TestExtractEdgesPrompt checks that the edges prompt gets the episode content
+// and previous episodes to ground facts in, and that older templates taking
+// only the node list still render.
+func TestExtractEdgesPrompt(t *testing.T) {
+	ctx := context.Background()
+	rec := &promptRecorder{MockLLMClient: MockLLMClient{Response: `{"extracted_edges": []}`}}
+	extractor := NewExtractor(rec, config.ExtractionPrompts{
+		Edges:   "<NODES>%s</NODES><MESSAGE>%s</MESSAGE>",
+		Context: "<PREVIOUS>%s</PREVIOUS>",
+	})
+	nodes := []model.EntityNode{{UUID: "uuid-1", Name: "Alice"}, {UUID: "uuid-2", Name: "Bob"}}
+
+	_, err := extractor.ExtractEdges(ctx, nodes, "Alice hired Bob.", []string{"Bob applied at Acme."})
+	assert.NoError(t, err)
+	assert.Equal(t, "<PREVIOUS>Bob applied at Acme.</PREVIOUS>"+
+		"<NODES>- UUID: uuid-1, Name: Alice\n- UUID: uuid-2, Name: Bob\n</NODES>"+
+		"<MESSAGE>Alice hired Bob.</MESSAGE>", rec.prompts[0])
+
+	extractor.Prompts = config.ExtractionPrompts{Edges: "<NODES>%s</NODES>"}
+	_, err = extractor.ExtractEdges(ctx, nodes, "Alice hired Bob.", []string{"Bob applied at Acme."})
+	assert.NoError(t, err)
+	assert.Equal(t, "<NODES>- UUID: uuid-1, Name: Alice\n- UUID: uuid-2, Name: Bob\n</NODES>", rec.prompts[1])
+}
+
+func TestResolveCoreferences(t *testing.T) {
+	ctx := context.Background()
+	rec := &promptRecorder{MockLLMClient: MockLLMClient{Response: `{"resolved_content": "Alice moved to Berlin."}`}}
+	extractor := NewExtractor(rec, config.ExtractionPrompts{Coreference: "<PREVIOUS>%s</PREVIOUS><MESSAGE>%s</MESSAGE>"})
+
+	resolved, err := extractor.ResolveCoreferences(ctx, "She moved there.", []string{"Alice loves Berlin.", "Bob too."})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice moved to Berlin.", resolved)
+	assert.Equal(t, "<PREVIOUS>Alice loves Berlin.\nBob too.</PREVIOUS><MESSAGE>She moved there.</MESSAGE>", rec.prompts[0])
+
+	// An empty rewrite keeps the content as written
+	rec.Response = `{"resolved_content": ""}`
+	resolved, err = extractor.ResolveCoreferences(ctx, "She moved there.", []string{"Alice loves Berlin."})
+	assert.NoError(t, err)
+	assert.Equal(t, "She moved there.", resolved)
+}
+
+func TestVerifyEdges(t *testing.T) {
+	rec := &promptRecorder{MockLLMClient: MockLLMClient{Response: `{"verdicts": [{"id": 1, "supported": true}, {"id": 2, "supported": false}]}`}}
+	extractor := NewExtractor(rec, config.ExtractionPrompts{Verify: "<FACTS>%s</FACTS><MESSAGE>%s</MESSAGE>"})
+	nodes := []model.EntityNode{{UUID: "uuid-1", Name: "Alice"}, {UUID: "uuid-2", Name: "Bob"}}
+	edges := []model.ExtractedEdge{
+		{SourceNodeUUID: "uuid-1", TargetNodeUUID: "uuid-2", RelationType: "HIRED", Fact: "Alice hired Bob"},
+		{SourceNodeUUID: "uuid-2", TargetNodeUUID: "uuid-1", RelationType: "MARRIED", Fact: "Bob married Alice"},
+		{SourceNodeUUID: "uuid-1", TargetNodeUUID: "uuid-2", RelationType: "PAYS", Fact: "Alice pays Bob"},
+	}
+
+	verified, err := extractor.VerifyEdges(context.Background(), nodes, edges, "Alice hired Bob.")
+	assert.NoError(t, err)
+	// The third fact got no verdict and is dropped with the unsupported one
+	assert.Equal(t, edges[:1], verified)
+	assert.Equal(t, "<FACTS>1. Alice -[HIRED]-> Bob: Alice hired Bob\n"+
+		"2. Bob -[MARRIED]-> Alice: Bob married Alice\n"+
+		"3. Alice -[PAYS]-> Bob: Alice pays Bob\n</FACTS><MESSAGE>Alice hired Bob.</MESSAGE>", rec.prompts[0])
+}
+
+type promptRecorder struct {
+	MockLLMClient
+	prompts []string
+}
+
+func (p *promptRecorder) Generate(ctx context.Context, prompt string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.MockLLMClient.Generate(ctx, prompt)
+}
+
+// TestPromptBudget checks that optional context is dropped to keep prompts
+// within MaxPromptTokens.
+func TestPromptBudget(t *testing.T) {
+	ctx := context.Background()
+	rec := &promptRecorder{MockLLMClient: MockLLMClient{Response: `{"extracted_entities": [], "extracted_edges": []}`}}
+	extractor := NewExtractor(rec, config.ExtractionPrompts{
+		Nodes:   "<%s> %s",
+		Edges:   "%s",
+		Context: "%s\n",
+	})
+	previous := []string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40)}
+
+	// Without a budget, everything is sent
+	_, err := extractor.ExtractNodes(ctx, "hello", "Person", previous)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join(previous, "\n")+"\n<Person> hello", rec.prompts[0])
+
+	// 20 tokens leave room for one previous episode (about 10 tokens) besides the message
+	extractor.MaxPromptTokens = 20
+	_, err = extractor.ExtractNodes(ctx, "hello", "Person", previous)
+	assert.NoError(t, err)
+	assert.Equal(t, previous[0]+"\n<Person> hello", rec.prompts[1])
+	assert.Len(t, previous, 3)
+
+	extractor.MaxPromptTokens = 1
+	_, err = extractor.ExtractNodes(ctx, "hello", "Person", previous)
+	assert.NoError(t, err)
+	assert.Equal(t, "<Person> hello", rec.prompts[2])
+
+	nodes := make([]model.EntityNode, 10)
+	for i := range nodes {
+		nodes[i] = model.EntityNode{UUID: strings.Repeat(string(rune('0'+i)), 36), Name: "Node"}
+	}
+	extractor.MaxPromptTokens = 40
+	_, err = extractor.ExtractEdges(ctx, nodes, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(rec.prompts[3], "- UUID"))
+
+	extractor.MaxPromptTokens = 60
+	_, err = extractor.ExtractEdges(ctx, nodes, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(rec.prompts[4], "- UUID"))
+
+	// Previous episodes go before any node does
+	_, err = extractor.ExtractEdges(ctx, nodes, "", previous)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(rec.prompts[5], "- UUID"))
+	assert.NotContains(t, rec.prompts[5], previous[0])
+
+	extractor.MaxPromptTokens = 155
+	_, err = extractor.ExtractEdges(ctx, nodes, "", previous)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, strings.Count(rec.prompts[6], "- UUID"))
+	assert.Contains(t, rec.prompts[6], previous[0])
+	assert.NotContains(t, rec.prompts[6], previous[1])
+}
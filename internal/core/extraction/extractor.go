@@ -3,6 +3,9 @@ package extraction
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 
 	"github.com/agenthands/carbon/internal/config"
 	"github.com/agenthands/carbon/internal/core/common"
@@ -13,6 +16,9 @@ import (
 type Extractor struct {
 	LLM     llm.LLMClient
 	Prompts config.ExtractionPrompts
+	// MaxPromptTokens, when set, bounds prompts by dropping their optional
+	// context: previous episodes, then nodes beyond the first two in ExtractEdges.
+	MaxPromptTokens int
 }
 
 func NewExtractor(llmClient llm.LLMClient, prompts config.ExtractionPrompts) *Extractor {
@@ -25,7 +31,17 @@ func NewExtractor(llmClient llm.LLMClient, prompts config.ExtractionPrompts) *Ex
 // ExtractNodes extracts entities from the given content using the LLM.
 func (e *Extractor) ExtractNodes(ctx context.Context, content string, schema string, previousEpisodes []string) ([]model.ExtractedEntity, error) {
 	// Construct the prompt similar to Python's extract_message
-	prompt := fmt.Sprintf(e.Prompts.Nodes, schema, content)
+	nodesPrompt := fmt.Sprintf(e.Prompts.Nodes, schema, content)
+	prompt := nodesPrompt
+	if e.Prompts.Context != "" && len(previousEpisodes) > 0 {
+		// previousEpisodes is shared by concurrent bulk extractions: only read sub-slices of it
+		prompt, _ = e.fitPrompt("previous episodes", len(previousEpisodes), 0, func(n int) string {
+			if n == 0 {
+				return nodesPrompt
+			}
+			return fmt.Sprintf(e.Prompts.Context, strings.Join(previousEpisodes[:n], "\n")) + nodesPrompt
+		})
+	}
 
 	response, err := e.LLM.Generate(ctx, prompt)
 	if err != nil {
@@ -40,14 +56,137 @@ func (e *Extractor) ExtractNodes(ctx context.Context, content string, schema str
 	return result.ExtractedEntities, nil
 }
 
-func (e *Extractor) ExtractEdges(ctx context.Context, nodes []model.EntityNode, previousEpisodes []string) ([]model.ExtractedEdge, error) {
+// ResolveCoreferences rewrites content so that pronouns and references to
+// entities of the previous episodes ("she", "the company") name them
+// explicitly. Previous episodes are dropped to fit MaxPromptTokens.
+func (e *Extractor) ResolveCoreferences(ctx context.Context, content string, previousEpisodes []string) (string, error) {
+	prompt, _ := e.fitPrompt("previous episodes", len(previousEpisodes), 0, func(n int) string {
+		return fmt.Sprintf(e.Prompts.Coreference, strings.Join(previousEpisodes[:n], "\n"), content)
+	})
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate resolved content: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.ResolvedContent](response)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve coreferences: %w", err)
+	}
+	if result.ResolvedContent == "" {
+		return content, nil
+	}
+	return result.ResolvedContent, nil
+}
+
+// ExtractQueryEntities returns the names of the entities mentioned in a search query.
+func (e *Extractor) ExtractQueryEntities(ctx context.Context, query string) ([]string, error) {
+	prompt := fmt.Sprintf(e.Prompts.Query, query)
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query entities: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.ExtractedEntities](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract query entities: %w", err)
+	}
+
+	names := make([]string, 0, len(result.ExtractedEntities))
+	for _, ent := range result.ExtractedEntities {
+		if ent.Name != "" {
+			names = append(names, ent.Name)
+		}
+	}
+	return names, nil
+}
+
+// HypotheticalAnswer writes a passage that could answer a search query, for
+// searching by its embedding instead of the query's.
+func (e *Extractor) HypotheticalAnswer(ctx context.Context, query string) (string, error) {
+	prompt := fmt.Sprintf(e.Prompts.Hypothetical, query)
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate hypothetical answer: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.HypotheticalPassage](response)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse hypothetical answer: %w", err)
+	}
+	return strings.TrimSpace(result.Passage), nil
+}
+
+// ExtractTopics lists the topics content is about, only from allowed when it is not empty.
+func (e *Extractor) ExtractTopics(ctx context.Context, content string, allowed []string) ([]string, error) {
+	prompt := fmt.Sprintf(e.Prompts.Topics, strings.Join(allowed, "\n"), content)
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate topics: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.ExtractedTopics](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract topics: %w", err)
+	}
+	return result.Topics, nil
+}
+
+// ExtractSentiment judges how the user named user feels about fact.
+func (e *Extractor) ExtractSentiment(ctx context.Context, user, fact string) (*model.FactSentiment, error) {
+	prompt := fmt.Sprintf(e.Prompts.Sentiment, user, fact)
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sentiment: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.FactSentiment](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract sentiment: %w", err)
+	}
+	return &result, nil
+}
+
+// ExtractEdges extracts the relationships between nodes that content states.
+// Edges prompts take the node list and the content; previous episodes are put
+// before them through the Context prompt. Older prompts with only the node
+// list still work.
+func (e *Extractor) ExtractEdges(ctx context.Context, nodes []model.EntityNode, content string, previousEpisodes []string) ([]model.ExtractedEdge, error) {
 	// Simple serialization of nodes for context
-	var nodeContext string
-	for _, n := range nodes {
-		nodeContext += fmt.Sprintf("- UUID: %s, Name: %s\n", n.UUID, n.Name)
+	lines := make([]string, len(nodes))
+	for i, n := range nodes {
+		lines[i] = fmt.Sprintf("- UUID: %s, Name: %s\n", n.UUID, n.Name)
+	}
+	render := func(episodes, nodes int) string {
+		var prompt string
+		if strings.Count(e.Prompts.Edges, "%s") < 2 {
+			prompt = fmt.Sprintf(e.Prompts.Edges, strings.Join(lines[:nodes], ""))
+		} else {
+			prompt = fmt.Sprintf(e.Prompts.Edges, strings.Join(lines[:nodes], ""), content)
+		}
+		if episodes > 0 {
+			prompt = fmt.Sprintf(e.Prompts.Context, strings.Join(previousEpisodes[:episodes], "\n")) + prompt
+		}
+		return prompt
 	}
 
-	prompt := fmt.Sprintf(e.Prompts.Edges, nodeContext)
+	// Drop previous episodes first, then nodes beyond the first two
+	var prompt string
+	episodes := 0
+	if e.Prompts.Context != "" && len(previousEpisodes) > 0 {
+		prompt, episodes = e.fitPrompt("previous episodes", len(previousEpisodes), 0, func(n int) string {
+			return render(n, len(lines))
+		})
+	}
+	if episodes == 0 {
+		prompt, _ = e.fitPrompt("nodes", len(lines), 2, func(n int) string {
+			return render(0, n)
+		})
+	}
 
 	response, err := e.LLM.Generate(ctx, prompt)
 	if err != nil {
@@ -61,3 +200,64 @@ func (e *Extractor) ExtractEdges(ctx context.Context, nodes []model.EntityNode,
 
 	return result.ExtractedEdges, nil
 }
+
+// VerifyEdges asks the LLM whether content supports each extracted edge and
+// returns the edges it confirmed. Edges without a verdict are dropped too.
+func (e *Extractor) VerifyEdges(ctx context.Context, nodes []model.EntityNode, edges []model.ExtractedEdge, content string) ([]model.ExtractedEdge, error) {
+	if len(edges) == 0 {
+		return edges, nil
+	}
+	names := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		names[n.UUID] = n.Name
+	}
+	var facts strings.Builder
+	for i, edge := range edges {
+		fmt.Fprintf(&facts, "%d. %s -[%s]-> %s: %s\n", i+1, names[edge.SourceNodeUUID], edge.RelationType, names[edge.TargetNodeUUID], edge.Fact)
+	}
+	prompt := fmt.Sprintf(e.Prompts.Verify, facts.String(), content)
+
+	response, err := e.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate fact verdicts: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.FactVerdicts](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify facts: %w", err)
+	}
+
+	supported := make(map[int]bool, len(result.Verdicts))
+	for _, v := range result.Verdicts {
+		supported[v.ID] = v.Supported
+	}
+	verified := make([]model.ExtractedEdge, 0, len(edges))
+	for i, edge := range edges {
+		if supported[i+1] {
+			verified = append(verified, edge)
+		}
+	}
+	if dropped := len(edges) - len(verified); dropped > 0 {
+		log.Printf("Dropped %d of %d facts the source text doesn't support", dropped, len(edges))
+	}
+	return verified, nil
+}
+
+// fitPrompt renders the prompt with the first n of total context items, for
+// the largest n (but at least min) whose prompt fits MaxPromptTokens. A prompt
+// that doesn't fit even at min is sent anyway.
+func (e *Extractor) fitPrompt(what string, total, min int, render func(n int) string) (string, int) {
+	if min > total {
+		min = total
+	}
+	prompt := render(total)
+	if e.MaxPromptTokens <= 0 || llm.CountTokens(e.LLM, prompt) <= e.MaxPromptTokens {
+		return prompt, total
+	}
+	// Prompts grow with n, so the first n that doesn't fit bounds the search
+	over := min + sort.Search(total-min, func(i int) bool {
+		return llm.CountTokens(e.LLM, render(min+i+1)) > e.MaxPromptTokens
+	})
+	log.Printf("Prompt over %d tokens: kept %d of %d %s", e.MaxPromptTokens, over, total, what)
+	return render(over), over
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrFactNotFound = errors.New("fact not found")
+
+// GetFact returns a fact (RELATES_TO edge) by UUID, including invalidated ones, or ErrFactNotFound.
+func (g *Graphiti) GetFact(ctx context.Context, uuid string) (*model.EntityEdge, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityEdgeQuery, map[string]interface{}{
+		"uuid": uuid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fact: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrFactNotFound
+	}
+
+	var edge model.EntityEdge
+	if err := driver.ScanRecord(res.Records[0], &edge); err != nil {
+		return nil, fmt.Errorf("failed to read fact: %w", err)
+	}
+	if err := decodeJSONField(res.Records[0], "attributes", &edge.Attributes); err != nil {
+		return nil, fmt.Errorf("invalid attributes for fact %s: %w", uuid, err)
+	}
+	access := g.accessFilter(ctx)
+	if !access.allows(edge.Name) {
+		return nil, ErrFactNotFound
+	}
+	edge.Attributes = access.attrs(edge.Attributes)
+	return &edge, nil
+}
+
+// ListFacts returns the group's currently valid facts, oldest first.
+func (g *Graphiti) ListFacts(ctx context.Context, groupID string) ([]model.EntityEdge, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupEdgeEpisodesQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group facts: %w", err)
+	}
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group facts: %w", err)
+	}
+	edges = g.accessFilter(ctx).edges(edges)
+	for i := range edges {
+		edges[i].GroupID = groupID
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].CreatedAt.Before(edges[j].CreatedAt) })
+	return edges, nil
+}
+
+// DeleteFact removes a fact outright, or returns ErrFactNotFound. Facts that
+// stopped being true should be invalidated instead so history is kept.
+func (g *Graphiti) DeleteFact(ctx context.Context, uuid string) error {
+	fact, err := g.GetFact(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	res, err := g.Driver.ExecuteQuery(ctx, driver.DeleteEntityEdgeQuery, map[string]interface{}{"uuid": uuid})
+	if err != nil {
+		return fmt.Errorf("failed to delete fact: %w", err)
+	}
+	g.invalidateSearchCache(ctx, fact.GroupID)
+	return g.recordDeletions(ctx, model.ChangeKindFact, res)
+}
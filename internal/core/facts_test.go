@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFactAndListFacts(t *testing.T) {
+	ctx := context.Background()
+	g, _ := edgeTestGraph(t, driver.NewMemoryDriver(), llmFunc(func(string) string { return "{}" }))
+	for _, e := range []struct{ uuid, source, target, fact, createdAt string }{
+		{"e2", "b", "c", "b knows c", "2024-01-02T00:00:00Z"},
+		{"e1", "a", "b", "a knows b", "2024-01-01T00:00:00Z"},
+	} {
+		seedFact(t, g.Driver, "g1", e.uuid, e.source, e.target, "KNOWS", e.fact, map[string]interface{}{
+			"created_at": e.createdAt, "valid_at": e.createdAt,
+		})
+	}
+
+	facts, err := g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, facts, 2)
+	assert.Equal(t, "e1", facts[0].UUID)
+
+	require.NoError(t, g.invalidateEdge(ctx, "e1", time.Now().UTC()))
+	facts, err = g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, facts, 1)
+
+	fact, err := g.GetFact(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "a knows b", fact.Fact)
+	assert.Equal(t, "a", fact.SourceUUID)
+	assert.NotNil(t, fact.InvalidAt)
+
+	_, err = g.GetFact(ctx, "missing")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// searchFilterParams translates a filter into the optional parameters of the
+// search queries. Unset predicates are passed as nil, which disables them.
+func searchFilterParams(f *model.SearchFilter) (map[string]interface{}, error) {
+	params := map[string]interface{}{
+		"relation_types":      nil,
+		"entity_labels":       nil,
+		"attribute_fragments": nil,
+		"valid_from":          nil,
+		"valid_to":            nil,
+		"created_from":        nil,
+		"created_to":          nil,
+		"live_at":             time.Now().UTC().Format(time.RFC3339),
+		"topic_episodes":      nil, // Set by search from filter.Topics
+		"sentiment_fragments": nil,
+		"emotion_fragments":   nil,
+	}
+	if f == nil {
+		return params, nil
+	}
+	if f.IncludeExpired {
+		params["live_at"] = nil
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(f.RelationTypes) > 0 {
+		params["relation_types"] = f.RelationTypes
+	}
+	if len(f.EntityLabels) > 0 {
+		params["entity_labels"] = f.EntityLabels
+	}
+	if len(f.Attributes) > 0 {
+		// Entity attributes are stored as compact JSON, so `"key":value` is a
+		// cheap prefilter; matchesAttributes makes the comparison exact.
+		fragments := make([]string, 0, len(f.Attributes))
+		for k, v := range f.Attributes {
+			key, err := json.Marshal(k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attribute filter key %q: %w", k, err)
+			}
+			val, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attribute filter value for %q: %w", k, err)
+			}
+			fragments = append(fragments, string(key)+":"+string(val))
+		}
+		params["attribute_fragments"] = fragments
+	}
+	// Fact attributes are compact JSON too; the fragments match any listed value
+	for _, a := range []struct {
+		param, key string
+		values     []string
+	}{{"sentiment_fragments", "sentiment", f.Sentiments}, {"emotion_fragments", "emotion", f.Emotions}} {
+		if len(a.values) == 0 {
+			continue
+		}
+		fragments := make([]string, len(a.values))
+		for i, v := range a.values {
+			val, _ := json.Marshal(normalizeEmotion(v)) // Strings always encode
+			fragments[i] = `"` + a.key + `":` + string(val)
+		}
+		params[a.param] = fragments
+	}
+	for _, r := range []struct {
+		rng      *model.DateRange
+		from, to string
+	}{{f.ValidAt, "valid_from", "valid_to"}, {f.CreatedAt, "created_from", "created_to"}} {
+		if r.rng == nil {
+			continue
+		}
+		if r.rng.From != nil {
+			params[r.from] = r.rng.From.UTC().Format(time.RFC3339)
+		}
+		if r.rng.To != nil {
+			params[r.to] = r.rng.To.UTC().Format(time.RFC3339)
+		}
+	}
+	return params, nil
+}
+
+// similarityParams resolves the vector search metric and cutoff: the filter's,
+// else the configured ones. A nil min_score keeps every match.
+func (g *Graphiti) similarityParams(f *model.SearchFilter) (map[string]interface{}, error) {
+	params := map[string]interface{}{"metric": model.MetricCosine, "min_score": nil}
+	if g.Config != nil {
+		if !model.ValidMetric(g.Config.Search.Metric) {
+			return nil, fmt.Errorf("invalid [search] metric '%s'", g.Config.Search.Metric)
+		}
+		if g.Config.Search.Metric != "" {
+			params["metric"] = g.Config.Search.Metric
+		}
+		if g.Config.Search.MinScore != 0 {
+			params["min_score"] = g.Config.Search.MinScore
+		}
+	}
+	if f != nil {
+		if f.Metric != "" {
+			params["metric"] = f.Metric
+		}
+		if f.MinScore != nil {
+			params["min_score"] = *f.MinScore
+		}
+	}
+	return params, nil
+}
+
+// filterByAttributes keeps the edges whose source or target entity has every
+// filtered attribute with an equal value.
+func (g *Graphiti) filterByAttributes(ctx context.Context, edges []model.EntityEdge, attrs map[string]interface{}) ([]model.EntityEdge, error) {
+	if len(attrs) == 0 {
+		return edges, nil
+	}
+	want := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		b, _ := json.Marshal(v) // Already validated by searchFilterParams
+		want[k] = string(b)
+	}
+
+	entities := make(map[string]*model.EntityNode)
+	entity := func(uuid string) (*model.EntityNode, error) {
+		if n, ok := entities[uuid]; ok {
+			return n, nil
+		}
+		n, err := g.GetEntity(ctx, uuid)
+		if errors.Is(err, ErrEntityNotFound) {
+			n, err = nil, nil // Deleted since the search ran; matches nothing
+		}
+		if err != nil {
+			return nil, err
+		}
+		entities[uuid] = n
+		return n, nil
+	}
+
+	filtered := edges[:0]
+	for _, e := range edges {
+		source, err := entity(e.SourceUUID)
+		if err != nil {
+			return nil, err
+		}
+		target, err := entity(e.TargetUUID)
+		if err != nil {
+			return nil, err
+		}
+		if matchesAttributes(source, target, want) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+func matchesAttributes(source, target *model.EntityNode, want map[string]string) bool {
+	for k, v := range want {
+		if attributeJSON(source, k) != v && attributeJSON(target, k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func attributeJSON(n *model.EntityNode, key string) string {
+	if n == nil {
+		return ""
+	}
+	v, ok := n.Attributes[key]
+	if !ok {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func filterTestGraph(t *testing.T) *Graphiti {
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	for _, n := range []struct {
+		uuid, attrs string
+		labels      []string
+	}{
+		{"alice", `{"location":"Paris","nested":{"location":"Rome"}}`, []string{"Person"}},
+		{"bob", `{"location":"Rome"}`, []string{"Person"}},
+		{"acme", `{}`, []string{"Company"}},
+	} {
+		seedEntity(t, g.Driver, "g1", n.uuid, map[string]interface{}{"attributes": n.attrs, "labels": n.labels})
+	}
+	for _, e := range []struct{ uuid, source, target, name, validAt string }{
+		{"e1", "alice", "acme", "WORKS_AT", "2024-01-10T00:00:00Z"},
+		{"e2", "bob", "acme", "WORKS_AT", "2024-03-10T00:00:00Z"},
+		{"e3", "alice", "bob", "KNOWS", "2024-02-10T00:00:00Z"},
+	} {
+		seedFact(t, g.Driver, "g1", e.uuid, e.source, e.target, e.name, e.uuid+" fact", map[string]interface{}{
+			"valid_at": e.validAt, "created_at": e.validAt,
+		})
+	}
+	return g
+}
+
+func searchUUIDs(t *testing.T, g *Graphiti, filter *model.SearchFilter) []string {
+	edges, err := g.SearchWithFilter(context.Background(), "g1", "fact", filter)
+	require.NoError(t, err)
+	uuids := []string{}
+	for _, e := range edges {
+		uuids = append(uuids, e.UUID)
+	}
+	return uuids
+}
+
+func TestSearchWithFilter(t *testing.T) {
+	g := filterTestGraph(t)
+
+	assert.Equal(t, []string{"e1", "e2", "e3"}, searchUUIDs(t, g, nil))
+	assert.Equal(t, []string{"e1", "e2"}, searchUUIDs(t, g, &model.SearchFilter{RelationTypes: []string{"WORKS_AT"}}))
+	assert.Equal(t, []string{"e1", "e2"}, searchUUIDs(t, g, &model.SearchFilter{EntityLabels: []string{"Company"}}))
+	assert.Equal(t, []string{"e1", "e3"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"location": "Paris"}}))
+	// Nested attributes pass the Cypher prefilter but not the exact check
+	assert.Equal(t, []string{"e2", "e3"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"location": "Rome"}}))
+
+	from, to := mustTime("2024-02-01T00:00:00Z"), mustTime("2024-03-01T00:00:00Z")
+	assert.Equal(t, []string{"e3"}, searchUUIDs(t, g, &model.SearchFilter{ValidAt: &model.DateRange{From: &from, To: &to}}))
+	assert.Equal(t, []string{"e2", "e3"}, searchUUIDs(t, g, &model.SearchFilter{CreatedAt: &model.DateRange{From: &from}}))
+	assert.Equal(t, []string{"e2"}, searchUUIDs(t, g, &model.SearchFilter{
+		RelationTypes: []string{"WORKS_AT"},
+		ValidAt:       &model.DateRange{From: &from},
+	}))
+
+	_, err := g.SearchWithFilter(context.Background(), "g1", "fact", &model.SearchFilter{ValidAt: &model.DateRange{From: &to, To: &from}})
+	assert.Error(t, err)
+	// Labels are passed as a parameter, so any string only fails to match
+	assert.Empty(t, searchUUIDs(t, g, &model.SearchFilter{EntityLabels: []string{"Company`) DETACH DELETE (n"}}))
+}
+
+func TestSearchSimilarityMetric(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, mapEmbedder{"q": {1, 0}}, nil, &config.Config{})
+	seedEntities(t, g.Driver, "g1", "a", "b")
+	// far is parallel to the query but long; near is closer yet at an angle
+	for uuid, emb := range map[string][]float32{"far": {2, 0}, "near": {0.5, 0.5}} {
+		seedFact(t, g.Driver, "g1", uuid, "a", "b", "KNOWS", uuid, map[string]interface{}{"fact_embedding": emb})
+	}
+	search := func(filter *model.SearchFilter) []string {
+		edges, err := g.SearchWithFilter(ctx, "g1", "q", filter)
+		require.NoError(t, err)
+		uuids := []string{}
+		for _, e := range edges {
+			uuids = append(uuids, e.UUID)
+		}
+		return uuids
+	}
+	minScore := func(v float64) *float64 { return &v }
+
+	assert.Equal(t, []string{"far", "near"}, search(nil))
+	assert.Equal(t, []string{"near", "far"}, search(&model.SearchFilter{Metric: model.MetricEuclidean}))
+	assert.Equal(t, []string{"far"}, search(&model.SearchFilter{MinScore: minScore(0.8)}))
+	assert.Equal(t, []string{"far", "near"}, search(&model.SearchFilter{Metric: model.MetricDot, MinScore: minScore(0.5)}))
+	assert.Equal(t, []string{"far"}, search(&model.SearchFilter{Metric: model.MetricDot, MinScore: minScore(1)}))
+
+	// The configured metric and cutoff apply unless the request overrides them
+	g.Config.Search = config.SearchConfig{Metric: model.MetricEuclidean, MinScore: 0.55}
+	assert.Equal(t, []string{"near"}, search(nil))
+	assert.Equal(t, []string{"far", "near"}, search(&model.SearchFilter{Metric: model.MetricCosine, MinScore: minScore(0)}))
+
+	_, err := g.SearchWithFilter(ctx, "g1", "q", &model.SearchFilter{Metric: "manhattan"})
+	assert.Error(t, err)
+}
+
+func TestSearchWithFilter_NormalizedQuantities(t *testing.T) {
+	g := filterTestGraph(t)
+	g.Config.Ingest.NormalizeQuantities = true
+	ctx := context.Background()
+
+	// Extraction stores quantities as numbers in their canonical unit
+	nodes := g.convertToEntityNodes([]model.ExtractedEntity{
+		{Name: "carol", Attributes: map[string]interface{}{"commute": "5km", "city": "Oslo"}},
+	}, "", "g1", mustTime("2024-01-01T00:00:00Z"))
+	require.Len(t, nodes, 1)
+	assert.Equal(t, map[string]interface{}{"commute": 5000.0, "commute_unit": "m", "city": "Oslo"}, nodes[0].Attributes)
+	nodes[0].UUID = "carol"
+	require.NoError(t, g.saveEntity(ctx, nodes[0]))
+	seedFact(t, g.Driver, "g1", "e4", "carol", "acme", "WORKS_AT", "e4 fact", map[string]interface{}{
+		"valid_at": "2024-04-10T00:00:00Z", "created_at": "2024-04-10T00:00:00Z",
+	})
+
+	// Filter values are read the same way, whatever unit they are written in
+	assert.Equal(t, []string{"e4"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"commute": "5 km"}}))
+	assert.Equal(t, []string{"e4"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"commute": "5000m"}}))
+	assert.Equal(t, []string{"e4"}, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"commute": 5000}}))
+	assert.Empty(t, searchUUIDs(t, g, &model.SearchFilter{Attributes: map[string]interface{}{"commute": "5 miles"}}))
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// GetGraphView returns the group's entity graph for visualization. When center
+// is set, only nodes within depth hops of it (following valid facts in either
+// direction, at most MaxNeighborhoodDepth) are included, read one hop per query
+// like GetNeighborhood. A center that isn't an entity of the group is
+// ErrEntityNotFound.
+func (g *Graphiti) GetGraphView(ctx context.Context, groupID, center string, depth int) (*model.GraphView, error) {
+	var (
+		nodes []model.EntityNode
+		edges []model.EntityEdge
+		err   error
+	)
+	depths := make(map[string]int)
+	if center == "" {
+		if nodes, err = g.getGroupNodes(ctx, groupID); err != nil {
+			return nil, fmt.Errorf("failed to fetch nodes: %w", err)
+		}
+		if edges, err = g.getGroupEdges(ctx, groupID); err != nil {
+			return nil, fmt.Errorf("failed to fetch edges: %w", err)
+		}
+		for _, n := range nodes {
+			depths[n.UUID] = 0
+		}
+	} else {
+		if nodes, edges, depths, err = g.graphAround(ctx, groupID, center, depth); err != nil {
+			return nil, err
+		}
+	}
+
+	view := &model.GraphView{Nodes: []model.GraphViewNode{}, Links: []model.GraphViewLink{}}
+	for _, n := range nodes {
+		d, ok := depths[n.UUID]
+		if !ok {
+			continue
+		}
+		view.Nodes = append(view.Nodes, model.GraphViewNode{ID: n.UUID, Label: n.Name, Summary: n.Summary, Depth: d})
+	}
+
+	access := g.accessFilter(ctx)
+	for _, e := range edges {
+		if !access.allows(e.Name) {
+			continue
+		}
+		_, okSource := depths[e.SourceUUID]
+		_, okTarget := depths[e.TargetUUID]
+		if !okSource || !okTarget {
+			continue
+		}
+		view.Links = append(view.Links, model.GraphViewLink{ID: e.UUID, Source: e.SourceUUID, Target: e.TargetUUID, Label: e.Name, Fact: e.Fact})
+	}
+	return view, nil
+}
+
+// graphAround reads the entities within depth hops of center, the facts among
+// them and each entity's hop distance, nearest first.
+func (g *Graphiti) graphAround(ctx context.Context, groupID, center string, depth int) ([]model.EntityNode, []model.EntityEdge, map[string]int, error) {
+	centerNode, err := g.GetEntity(ctx, center)
+	if err == nil && centerNode.GroupID != groupID {
+		err = ErrEntityNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("center node %s in group %s: %w", center, groupID, err)
+	}
+	depth = max(0, min(depth, MaxNeighborhoodDepth))
+
+	depths := map[string]int{center: 0}
+	seen := make(map[string]bool)
+	var edges []model.EntityEdge
+	frontier := []string{center}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		hopEdges, err := g.neighborEdges(ctx, groupID, frontier)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var next []string
+		for _, e := range hopEdges {
+			if seen[e.UUID] {
+				continue
+			}
+			seen[e.UUID] = true
+			edges = append(edges, e)
+			for _, n := range []string{e.SourceUUID, e.TargetUUID} {
+				if _, ok := depths[n]; !ok {
+					depths[n] = d
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+	// The last hop's facts can link its entities to each other
+	if len(frontier) > 0 && depth > 0 {
+		lastEdges, err := g.neighborEdges(ctx, groupID, frontier)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, e := range lastEdges {
+			if !seen[e.UUID] {
+				seen[e.UUID] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	others := make([]string, 0, len(depths)-1)
+	for n := range depths {
+		if n != center {
+			others = append(others, n)
+		}
+	}
+	nodes := []model.EntityNode{*centerNode}
+	if len(others) > 0 {
+		found, err := g.entityNodes(ctx, others)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sort.SliceStable(found, func(i, j int) bool {
+			if depths[found[i].UUID] != depths[found[j].UUID] {
+				return depths[found[i].UUID] < depths[found[j].UUID]
+			}
+			return found[i].Name < found[j].Name
+		})
+		nodes = append(nodes, found...)
+	}
+	return nodes, edges, depths, nil
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGraphView_Depth(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{})
+
+	// a -> b -> c, plus isolated d
+	seedEntities(t, d, "g1", "a", "b", "c", "d")
+	for _, e := range [][3]string{{"ab", "a", "b"}, {"bc", "b", "c"}} {
+		seedFact(t, d, "g1", e[0], e[1], e[2], "LINKS", e[0], nil)
+	}
+
+	full, err := g.GetGraphView(ctx, "g1", "", 0)
+	require.NoError(t, err)
+	assert.Len(t, full.Nodes, 4)
+	assert.Len(t, full.Links, 2)
+
+	one, err := g.GetGraphView(ctx, "g1", "a", 1)
+	require.NoError(t, err)
+	assert.Len(t, one.Nodes, 2)
+	assert.Len(t, one.Links, 1)
+
+	two, err := g.GetGraphView(ctx, "g1", "b", 5) // Capped at MaxNeighborhoodDepth
+	require.NoError(t, err)
+	assert.Len(t, two.Nodes, 3)
+	assert.Len(t, two.Links, 2)
+	assert.Equal(t, "b", two.Nodes[0].ID)
+
+	_, err = g.GetGraphView(ctx, "g1", "missing", 1)
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+	_, err = g.GetGraphView(ctx, "g2", "a", 1)
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
@@ -3,17 +3,23 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/agenthands/carbon/internal/blob"
 	"github.com/agenthands/carbon/internal/config"
 	"github.com/agenthands/carbon/internal/core/community"
 	"github.com/agenthands/carbon/internal/core/dedupe"
 	"github.com/agenthands/carbon/internal/core/extraction"
 	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/core/quantity"
 	"github.com/agenthands/carbon/internal/core/summary"
+	"github.com/agenthands/carbon/internal/core/temporal"
 	"github.com/agenthands/carbon/internal/driver"
 	"github.com/agenthands/carbon/internal/llm"
 )
@@ -22,6 +28,10 @@ type Graphiti struct {
 	Driver       driver.GraphDriver
 	LLM          llm.LLMClient
 	Embedder     llm.EmbedderClient
+	// EmbeddingModel names Embedder's model; vectors are stored tagged "<model>@<dimension>".
+	EmbeddingModel string
+	// FallbackEmbedders are older models whose vectors search still queries until they are re-embedded.
+	FallbackEmbedders []llm.NamedEmbedder
 	Extractor    *extraction.Extractor
 	Deduplicator *dedupe.Deduplicator
 	Summarizer   *summary.Summarizer
@@ -29,24 +39,72 @@ type Graphiti struct {
 	Reranker     llm.RerankerClient
 	Config       *config.Config
 	UUIDGenerator func() string
+	// SummaryQueue, when set, receives node summary updates instead of AddEpisode running them inline.
+	SummaryQueue *SummaryQueue
+	// SearchCache, when set, serves repeated searches until the group next changes.
+	SearchCache SearchCache
+	// Moderator, when set, checks episodes as [moderation] and group policies say before they are ingested.
+	Moderator llm.Moderator
+	// RerankCache, when set, serves the reranker's orderings of candidate sets it ranked before.
+	RerankCache SearchCache
+	// Cipher, when set, encrypts the attributes and episode content listed under [encryption].
+	Cipher Cipher
+	// Backups, when set, is where Backup writes graph snapshots.
+	Backups blob.Store
+	// ContentStore, when set, holds episode content over [content_store] threshold_chars.
+	ContentStore blob.Store
+
+	// Shared by all requests; see UpdateConcurrency
+	bulkIngest *Limiter
+	bulkSearch *Limiter
+	extraction *Limiter // LLM-bound episode processing, fair between groups
+
+	communityRuns *detectionRuns
+	hooks         *hooks // See RegisterHook
+	// Held from picking a new fact's citation handle until the fact is saved
+	citations *sync.Mutex
+	// Held while a group's limits are checked and enforced
+	limitLocks *groupLocks
 }
 
 func NewGraphiti(driver driver.GraphDriver, llmClient llm.LLMClient, embedderClient llm.EmbedderClient, reranker llm.RerankerClient, cfg *config.Config) *Graphiti {
 	if reranker == nil {
-		reranker = llm.NewSimpleLLMReranker(llmClient)
+		reranker = llm.NewSimpleLLMReranker(llm.ForOperation(llmClient, llm.OpRerank))
 	}
-	return &Graphiti{
+	var summaryQueue *SummaryQueue
+	if cfg.SummaryQueue.Enabled {
+		interval := time.Duration(cfg.SummaryQueue.IntervalMinutes) * time.Minute
+		summaryQueue = NewSummaryQueue(interval)
+	}
+	bulkIngest, bulkSearch, extraction := newLimiters(cfg)
+	g := &Graphiti{
 		Driver:       driver,
 		LLM:          llmClient,
 		Embedder:     embedderClient,
+		EmbeddingModel: defaultEmbeddingModel(cfg),
 		Reranker:     reranker,
-		Extractor:    extraction.NewExtractor(llmClient, cfg.Extraction),
-		Deduplicator: dedupe.NewDeduplicator(llmClient, cfg.Deduplication),
-		Summarizer:   summary.NewSummarizer(llmClient, cfg.Summary),
+		Extractor:    newExtractor(llmClient, cfg),
+		Deduplicator: dedupe.NewDeduplicator(llm.ForOperation(llmClient, llm.OpDeduplication), cfg.Deduplication),
+		Summarizer:   summary.NewSummarizer(llm.ForOperation(llmClient, llm.OpSummary), cfg.Summary),
 		CommunityDetector: community.NewSimpleDetector(),
 		Config:       cfg,
 		UUIDGenerator: func() string { return uuid.New().String() },
-	}
+		SummaryQueue: summaryQueue,
+		SearchCache:  NewSearchCache(cfg.SearchCache),
+		RerankCache:  NewRerankCache(cfg.SearchCache),
+		Moderator:    newModerator(cfg),
+		bulkIngest:   bulkIngest,
+		bulkSearch:   bulkSearch,
+		extraction:   extraction,
+		communityRuns: newDetectionRuns(),
+		hooks:         &hooks{},
+		citations:     &sync.Mutex{},
+		limitLocks:    &groupLocks{},
+	}
+	if len(cfg.Transforms) > 0 {
+		g.hooks.postExtract = append(g.hooks.postExtract, newTransformHook(g, cfg.Transforms))
+	}
+	return g
 }
 
 func (g *Graphiti) BuildIndices(ctx context.Context) error {
@@ -54,8 +112,13 @@ func (g *Graphiti) BuildIndices(ctx context.Context) error {
 }
 
 func (g *Graphiti) SaveEntityNode(ctx context.Context, name, groupID, summary string) (*model.EntityNode, error) {
+	return g.SaveEntityNodeWithUUID(ctx, g.UUIDGenerator(), name, groupID, summary)
+}
+
+// SaveEntityNodeWithUUID creates or overwrites the entity with the given UUID.
+func (g *Graphiti) SaveEntityNodeWithUUID(ctx context.Context, uuid, name, groupID, summary string) (*model.EntityNode, error) {
 	node := &model.EntityNode{
-		UUID:      g.UUIDGenerator(),
+		UUID:      uuid,
 		Name:      name,
 		GroupID:   groupID,
 		CreatedAt: time.Now().UTC(),
@@ -63,11 +126,9 @@ func (g *Graphiti) SaveEntityNode(ctx context.Context, name, groupID, summary st
 		Labels:    []string{"Entity"},
 	}
 
-	if g.Embedder != nil {
-		vec, err := g.Embedder.Embed(ctx, name)
-		if err == nil {
-			node.NameEmbedding = vec
-		}
+	vec, embeddingModel, err := g.embed(ctx, name)
+	if err == nil {
+		node.NameEmbedding = vec
 	}
 
 	var attrsJSON string
@@ -88,30 +149,126 @@ func (g *Graphiti) SaveEntityNode(ctx context.Context, name, groupID, summary st
 		"created_at":     node.CreatedAt,
 		"summary":        node.Summary,
 		"name_embedding": node.NameEmbedding,
+		"name_embedding_model": embeddingModel,
 		"attributes":     attrsJSON,
 		"labels":         node.Labels,
 	}
 
-	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEntityNodeQuery, params)
+	res, err := g.Driver.ExecuteQuery(ctx, driver.SaveEntityNodeQuery, params)
 	if err != nil {
 		return nil, err
 	}
+	if err := g.recordEntitySave(ctx, groupID, uuid, res); err != nil {
+		return nil, err
+	}
 
 	return node, nil
 }
 
+// AddEpisode ingests one episode. Episodes that fail are kept as dead letters
+// (see ListDeadLetters) until requeued.
 func (g *Graphiti) AddEpisode(ctx context.Context, groupID, name, content, saga, schema string) error {
-	return g.addEpisodeInternal(ctx, groupID, name, content, saga, schema, nil)
+	err := g.addEpisode(ctx, groupID, name, content, saga, schema)
+	if err != nil {
+		g.recordDeadLetter(ctx, groupID, name, model.EpisodeData{Content: content, Saga: saga, Schema: schema}, err)
+	}
+	return err
 }
 
-func (g *Graphiti) addEpisodeInternal(ctx context.Context, groupID, name, content, saga, schema string, preResolvedNodes []model.EntityNode) error {
+func (g *Graphiti) addEpisode(ctx context.Context, groupID, name, content, saga, schema string) error {
+	chunks, err := g.fitContent(content)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err := g.addEpisodeInternal(ctx, groupID, name, chunk, saga, schema, nil, "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addEpisodeInternal saves an episode and runs the extraction pipeline on it.
+// Bulk ingestion passes the nodes it already extracted and resolved, the
+// content they were extracted from after coreference resolution, and its
+// moderation verdict; otherwise they are computed here.
+func (g *Graphiti) addEpisodeInternal(ctx context.Context, groupID, name, content, saga, schema string, preResolvedNodes []model.EntityNode, resolvedContent string, verdict *moderationVerdict) error {
 	episodeUUID := g.UUIDGenerator()
 	now := time.Now().UTC()
+	defer g.invalidateSearchCache(ctx, groupID)
+
+	group, err := g.ensureGroup(ctx, groupID, now)
+	if err != nil {
+		return err
+	}
+	// Run the rest of the pipeline with the group's prompt/model overrides
+	g = g.forGroup(group)
+
+	// Agents often resend a message; a repeat isn't extracted again
+	var original string
+	if preResolvedNodes == nil {
+		if original, err = g.findDuplicateEpisode(ctx, groupID, content); err != nil {
+			return err
+		}
+		if original != "" && g.Config.Ingest.Duplicates == model.DuplicatesSkip {
+			log.Printf("Skipped an episode of group %s repeating episode %s", groupID, original)
+			return nil
+		}
+	}
+
+	// Nothing of blocked content is kept
+	if verdict == nil {
+		v, err := g.moderate(ctx, group, name, content)
+		if err != nil {
+			return err
+		}
+		verdict = &v
+	}
+
+	if err := g.enforceGroupLimits(ctx, group); err != nil {
+		return err
+	}
+	if original != "" {
+		log.Printf("Linked episode %s of group %s to the episode it repeats, %s", episodeUUID, groupID, original)
+		if err := g.linkDuplicateEpisode(ctx, episodeUUID, name, groupID, content, saga, original, now); err != nil {
+			return err
+		}
+		return g.recordModeration(ctx, groupID, name, episodeUUID, content, *verdict)
+	}
+
+	// Take turns with other groups for the LLM-bound work
+	done := timeStage(ctx, "queue")
+	if err := g.extraction.Acquire(ctx, groupID); err != nil {
+		return err
+	}
+	defer g.extraction.Release()
+	done()
 
 	// 1. Create Episode Node
+	done = timeStage(ctx, "save_episode")
 	if err := g.saveEpisodeNode(ctx, episodeUUID, name, groupID, content, now); err != nil {
 		return fmt.Errorf("failed to save episode: %w", err)
 	}
+	done()
+	if err := g.recordModeration(ctx, groupID, name, episodeUUID, content, *verdict); err != nil {
+		return err
+	}
+	g.tagTopics(ctx, episodeUUID, content)
+
+	// Get context from previous episodes
+	done = timeStage(ctx, "load_context")
+	episodeCtx, err := g.loadEpisodeContext(ctx, groupID, episodeUUID)
+	if err != nil {
+		return fmt.Errorf("failed to load context episodes: %w", err)
+	}
+	prevEpisodes := episodeCtx.For(ctx, content)
+	done()
+	if resolvedContent == "" {
+		resolvedContent = g.resolveCoreferences(ctx, content, prevEpisodes)
+		if resolvedContent, err = g.hooks.runPreExtract(ctx, groupID, resolvedContent); err != nil {
+			return err
+		}
+	}
 
 	var nodes []model.EntityNode
 
@@ -119,43 +276,63 @@ func (g *Graphiti) addEpisodeInternal(ctx context.Context, groupID, name, conten
 		nodes = preResolvedNodes
 	} else {
 		// 2. Extract Entities
-		// Get context from previous episodes
-		prevEpisodes, _ := g.retrievePreviousEpisodes(ctx, groupID, episodeUUID, 5)
-
+		if schema == "" {
+			schema = group.Settings.Ontology
+		}
 		if schema == "" {
 			schema = "Person, Place, Organization"
 		}
-		extractedEntities, err := g.Extractor.ExtractNodes(ctx, content, schema, prevEpisodes)
+		done = timeStage(ctx, "extract_nodes")
+		extractedEntities, err := g.Extractor.ExtractNodes(ctx, resolvedContent, schema, prevEpisodes)
 		if err != nil {
 			return fmt.Errorf("extraction failed: %w", err)
 		}
+		extractedEntities = checkAttributes(extractedEntities, schema, group.Settings)
+		if extractedEntities, err = g.hooks.runPostExtractEntities(ctx, groupID, extractedEntities); err != nil {
+			return err
+		}
+		done()
 
 		// Convert Extracted to EntityNode
-		newNodes := g.convertToEntityNodes(extractedEntities, groupID, now)
+		newNodes := g.convertToEntityNodes(extractedEntities, schema, groupID, now)
 
-		// 3. Deduplicate against existing
+		// 3. Deduplicate against existing, first-person references against the user
+		done = timeStage(ctx, "dedupe_nodes")
+		newNodes, user, err := g.bindUserEntity(ctx, groupID, newNodes)
+		if err != nil {
+			return fmt.Errorf("failed to bind user entity: %w", err)
+		}
 		existingNodes, err := g.getGroupNodes(ctx, groupID)
 		if err == nil && len(existingNodes) > 0 && len(newNodes) > 0 {
 			newNodes = g.resolveDuplicates(ctx, newNodes, existingNodes)
 		}
+		if user != nil {
+			newNodes = append([]model.EntityNode{*user}, newNodes...)
+		}
 		nodes = newNodes
+		done()
 	}
 
 	// 4. Save Entities and MENTIONS edges
 	// Note: If preResolvedNodes were passed, they are already saved/resolved by BulkAddEpisodes.
 	// But we still need to create MENTIONS edges.
 	// saveNewEntitiesAndMentions executes MERGE for nodes, so it's safe to run again.
-	g.saveNewEntitiesAndMentions(ctx, nodes, episodeUUID, groupID, now)
+	done = timeStage(ctx, "save_nodes")
+	if err := g.saveNewEntitiesAndMentions(ctx, nodes, episodeUUID, groupID, now); err != nil {
+		return err
+	}
+	done()
 
 	// 5. Extract Edges (Entity-Entity) & Summarize
 	if len(nodes) > 1 {
-		if err := g.processEntityEdgesAndSummaries(ctx, nodes, episodeUUID, groupID, now); err != nil {
-			// Log error but continue
+		if err := g.processEntityEdgesAndSummaries(ctx, nodes, episodeUUID, groupID, resolvedContent, prevEpisodes, now); err != nil {
+			return fmt.Errorf("failed to process edges: %w", err)
 		}
 	}
 
 	// 6. Start Saga Processing if saga name is provided
 	if saga != "" {
+		defer timeStage(ctx, "saga")()
 		if err := g.handleSaga(ctx, saga, groupID, episodeUUID, now); err != nil {
 			return fmt.Errorf("failed to handle saga: %w", err)
 		}
@@ -171,7 +348,7 @@ func (g *Graphiti) retrievePreviousEpisodes(ctx context.Context, groupID string,
 		limit = 5 // Default context window
 	}
 
-	res, err := g.Driver.ExecuteQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetRecentEpisodesQuery, map[string]interface{}{
 		"group_id": groupID,
 		"limit":    limit + 1, // Fetch +1 to account for potential exclusion
 	})
@@ -179,16 +356,22 @@ func (g *Graphiti) retrievePreviousEpisodes(ctx context.Context, groupID string,
 		return nil, err
 	}
 
+	rows, err := driver.ScanRecords[model.EpisodicNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent episodes: %w", err)
+	}
+
 	var episodes []string
-	// Iterate through records
-	for _, rec := range res.Records {
-		uuid, _ := rec.Get("uuid")
-		if uuidStr, ok := uuid.(string); ok && uuidStr == excludeUUID {
+	for _, ep := range rows {
+		if ep.UUID == excludeUUID {
 			continue
 		}
-		
-		if content, ok := rec.Get("content"); ok && content != nil {
-			episodes = append(episodes, content.(string))
+		content, _, err := g.readEpisodeContent(ctx, ep, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read episode %s: %w", ep.UUID, err)
+		}
+		if content != "" {
+			episodes = append(episodes, content)
 		}
 		if len(episodes) >= limit {
 			break
@@ -198,31 +381,61 @@ func (g *Graphiti) retrievePreviousEpisodes(ctx context.Context, groupID string,
 }
 
 func (g *Graphiti) saveEpisodeNode(ctx context.Context, uuid, name, groupID, content string, now time.Time) error {
+	return g.saveEpisode(ctx, model.EpisodicNode{
+		UUID: uuid, Name: name, GroupID: groupID, Content: content, CreatedAt: now, ValidAt: now,
+		Source: "user", SourceDescription: "user message",
+	})
+}
+
+// saveEpisode stores ep with its content encrypted or offloaded as configured.
+func (g *Graphiti) saveEpisode(ctx context.Context, ep model.EpisodicNode) error {
+	stored, err := g.encryptEpisode(ep.UUID, ep.Content)
+	if err != nil {
+		return err
+	}
+	content, ref, err := g.offloadEpisode(ctx, ep.GroupID, ep.UUID, ep.Content, stored)
+	if err != nil {
+		return err
+	}
 	params := map[string]interface{}{
-		"uuid":               uuid,
-		"name":               name, 
-		"group_id":           groupID, 
-		"created_at":         now.Format(time.RFC3339),
-		"valid_at":           now.Format(time.RFC3339),
+		"uuid":               ep.UUID,
+		"name":               ep.Name, 
+		"group_id":           ep.GroupID, 
+		"created_at":         ep.CreatedAt.Format(time.RFC3339),
+		"valid_at":           ep.ValidAt.Format(time.RFC3339),
 		"content":            content,
-		"source":             "user", 
-		"source_description": "user message",
+		"content_ref":        ref,
+		"source":             ep.Source, 
+		"source_description": ep.SourceDescription,
 		"entity_edges":       []string{},
 	}
-	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, params)
-	return err
+	if _, err = g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, params); err != nil {
+		return err
+	}
+	return g.recordChange(ctx, ep.GroupID, model.ChangeKindEpisode, ep.UUID, model.ChangeOpCreate)
 }
 
-func (g *Graphiti) convertToEntityNodes(extracted []model.ExtractedEntity, groupID string, now time.Time) []model.EntityNode {
+// convertToEntityNodes labels each entity with its ontology type as well as
+// Entity, e.g. :Entity:Person.
+func (g *Graphiti) convertToEntityNodes(extracted []model.ExtractedEntity, ontology, groupID string, now time.Time) []model.EntityNode {
+	types := ontologyTypes(ontology)
 	var nodes []model.EntityNode
 	for _, e := range extracted {
+		attrs := e.Attributes
+		if g.Config != nil && g.Config.Ingest.NormalizeQuantities {
+			attrs = quantity.NormalizeAttributes(attrs)
+		}
+		labels := []string{"Entity"}
+		if label := driver.LabelName(entityTypeName(e, types)); label != "" && label != "Entity" {
+			labels = append(labels, label)
+		}
 		nodes = append(nodes, model.EntityNode{
 			UUID:       g.UUIDGenerator(),
 			Name:       e.Name,
 			GroupID:    groupID,
 			CreatedAt:  now,
-			Attributes: e.Attributes,
-			Labels:     []string{"Entity"},
+			Attributes: attrs,
+			Labels:     labels,
 		})
 	}
 	return nodes
@@ -254,12 +467,12 @@ func (g *Graphiti) resolveDuplicates(ctx context.Context, newNodes, existingNode
 	return newNodes
 }
 
-func (g *Graphiti) saveNewEntitiesAndMentions(ctx context.Context, nodes []model.EntityNode, episodeUUID, groupID string, now time.Time) {
+func (g *Graphiti) saveNewEntitiesAndMentions(ctx context.Context, nodes []model.EntityNode, episodeUUID, groupID string, now time.Time) error {
 	for _, node := range nodes {
 		if err := g.saveEntity(ctx, node); err != nil {
-			continue
+			return fmt.Errorf("failed to save entity %s: %w", node.Name, err)
 		}
-		
+
 		edgeUUID := g.UUIDGenerator()
 		edgeParams := map[string]interface{}{
 			"uuid":        edgeUUID,
@@ -268,97 +481,283 @@ func (g *Graphiti) saveNewEntitiesAndMentions(ctx context.Context, nodes []model
 			"group_id":    groupID,
 			"created_at":  now.Format(time.RFC3339),
 		}
-		
-		g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, edgeParams)
+
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, edgeParams); err != nil {
+			return fmt.Errorf("failed to save mention of %s: %w", node.Name, err)
+		}
 	}
+	return nil
 }
 
-func (g *Graphiti) processEntityEdgesAndSummaries(ctx context.Context, nodes []model.EntityNode, episodeUUID, groupID string, now time.Time) error {
-	edges, err := g.Extractor.ExtractEdges(ctx, nodes, nil)
+// processEntityEdgesAndSummaries extracts the facts between nodes that the
+// episode's content states, then saves them and refreshes the node summaries.
+func (g *Graphiti) processEntityEdgesAndSummaries(ctx context.Context, nodes []model.EntityNode, episodeUUID, groupID, content string, previousEpisodes []string, now time.Time) error {
+	done := timeStage(ctx, "extract_edges")
+	edges, err := g.Extractor.ExtractEdges(ctx, nodes, content, previousEpisodes)
 	if err != nil {
 		return err
 	}
-	
-	nodeFacts := make(map[string][]string)
-	
-	for _, e := range edges {
-		// 1. Get existing edges from source node (needed for contradiction check across targets)
-		relatedEdges, err := g.getEdgesFromSource(ctx, e.SourceNodeUUID)
-		if err != nil {
-			continue
+	done()
+	if g.Config != nil && g.Config.Ingest.VerifyFacts && g.Config.Extraction.Verify != "" && content != "" {
+		done = timeStage(ctx, "verify_facts")
+		if edges, err = g.Extractor.VerifyEdges(ctx, nodes, edges, content); err != nil {
+			return err
 		}
+		done()
+	}
+	if edges, err = g.hooks.runPostExtractFacts(ctx, groupID, edges); err != nil {
+		return err
+	}
 
-		// 2. Check for Exact Match (Deduplication)
-		isDuplicate := false
-		for _, re := range relatedEdges {
-			// Strict dedupe: source (implicit), target, relation, fact MUST match
-			if re.TargetUUID == e.TargetNodeUUID && re.Fact == e.Fact && re.Name == e.RelationType {
-				isDuplicate = true
-				break
+	limit := 4
+	if g.Config != nil && g.Config.Concurrency.EdgeWorkers > 0 {
+		limit = g.Config.Concurrency.EdgeWorkers
+	}
+
+	// An edge's duplicate and contradiction checks read the edges already saved
+	// for its source node, so edges sharing a source run in order while
+	// different sources run in parallel.
+	var sources []string
+	bySource := make(map[string][]int)
+	for i, e := range edges {
+		if _, ok := bySource[e.SourceNodeUUID]; !ok {
+			sources = append(sources, e.SourceNodeUUID)
+		}
+		bySource[e.SourceNodeUUID] = append(bySource[e.SourceNodeUUID], i)
+	}
+
+	var mu sync.Mutex
+	added := make([]bool, len(edges))
+	var errs []error
+
+	done = timeStage(ctx, "resolve_edges")
+	forEachBounded(limit, len(sources), func(i int) {
+		for _, j := range bySource[sources[i]] {
+			addFact, err := g.processEdge(ctx, edges[j], episodeUUID, groupID, now)
+			added[j] = addFact
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
 			}
 		}
+	})
 
-		if isDuplicate {
-			// Edge exists, track fact for summary but skip saving edge
+	done()
+
+	// Facts are listed in extraction order, whichever source finished first,
+	// so summary prompts are the same on every run (see llm.ReplayClient)
+	nodeFacts := make(map[string][]string)
+	for j, e := range edges {
+		if added[j] {
 			nodeFacts[e.SourceNodeUUID] = append(nodeFacts[e.SourceNodeUUID], e.Fact)
 			nodeFacts[e.TargetNodeUUID] = append(nodeFacts[e.TargetNodeUUID], e.Fact)
-			continue
 		}
+	}
 
-		// 3. Check for Contradictions
-		if len(relatedEdges) > 0 {
-			contradictedUUIDs, err := g.Deduplicator.ResolveEdgeContradictions(ctx, e.Fact, relatedEdges)
+	// Summarize Nodes
+	done = timeStage(ctx, "summarize")
+	if g.SummaryQueue != nil {
+		for _, node := range nodes {
+			if facts, hasFacts := nodeFacts[node.UUID]; hasFacts {
+				g.SummaryQueue.Enqueue(g, node, facts)
+			}
+		}
+	} else {
+		forEachBounded(limit, len(nodes), func(i int) {
+			node := nodes[i]
+			mu.Lock()
+			facts, hasFacts := nodeFacts[node.UUID]
+			mu.Unlock()
+			if !hasFacts {
+				return
+			}
+			newSummary, err := g.Summarizer.SummarizeNode(ctx, node, facts)
+			if err == nil {
+				node.Summary = newSummary
+				err = g.saveEntity(ctx, node)
+			}
 			if err != nil {
-				fmt.Printf("Error checking contradictions: %v\n", err)
-			} else if len(contradictedUUIDs) > 0 {
-				// Invalidate contradicted edges
-				for _, cuuid := range contradictedUUIDs {
-					// Use new edge validity as invalid_at for old edge
-					// Actually, use current time or episode time
-					g.invalidateEdge(ctx, cuuid, now)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to summarize %s: %w", node.Name, err))
+				mu.Unlock()
+			}
+		})
+	}
+	done()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("edge processing errors: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// processEdge dedupes, resolves contradictions for and saves one extracted
+// edge. addFact reports whether the fact should feed the endpoint summaries.
+func (g *Graphiti) processEdge(ctx context.Context, e model.ExtractedEdge, episodeUUID, groupID string, now time.Time) (addFact bool, err error) {
+	return g.resolveEdge(ctx, e, nil, episodeUUID, groupID, now)
+}
+
+// resolveEdge is processEdge for an extracted edge or, when given is set, a
+// structured fact, which is resolved without the LLM: only exclusive facts
+// contradict others, and its attributes and validity are taken as given.
+func (g *Graphiti) resolveEdge(ctx context.Context, e model.ExtractedEdge, given *model.StructuredFact, episodeUUID, groupID string, now time.Time) (addFact bool, err error) {
+	// 1. Get the source node's edges, invalidated ones included (needed for
+	// contradiction check across targets and to recognize facts stated again)
+	history, err := g.getEdgeHistoryFromSource(ctx, e.SourceNodeUUID)
+	if err != nil {
+		return false, err
+	}
+
+	// 2. Check for Exact Match (Deduplication)
+	var reinstated string
+	active := make(map[string]bool)
+	for _, re := range history {
+		// Strict dedupe: source (implicit), target, relation, fact MUST match
+		if re.TargetUUID == e.TargetNodeUUID && re.Fact == e.Fact && re.Name == e.RelationType {
+			if re.InvalidAt == nil {
+				// Edge exists: count the new mention, track fact for summary but skip saving edge
+				if err := g.reinforceEdge(ctx, re.UUID, episodeUUID); err != nil {
+					return true, err
 				}
+				if err := g.renewExpiry(ctx, re.UUID, e.RelationType, now); err != nil {
+					return true, err
+				}
+				return true, g.recordChange(ctx, groupID, model.ChangeKindFact, re.UUID, model.ChangeOpUpdate)
 			}
+			reinstated = re.UUID
 		}
-
-		edgeParams := map[string]interface{}{
-			"uuid":           g.UUIDGenerator(),
-			"source_uuid":    e.SourceNodeUUID,
-			"target_uuid":    e.TargetNodeUUID,
-			"name":           e.RelationType,
-			"fact":           e.Fact,
-			"group_id":       groupID,
-			"created_at":     now.Format(time.RFC3339),
-			"expired_at":     "",
-			"valid_at":       now.Format(time.RFC3339),
-			"invalid_at":     "",
-			"episodes":       []string{episodeUUID},
-			"fact_embedding": nil,
-			"attributes":     "{}",
+		if re.InvalidAt == nil {
+			active[re.UUID] = true
 		}
-		
+	}
 
-		if g.Embedder != nil {
-			if emb, err := g.Embedder.Embed(ctx, e.Fact); err == nil {
-				edgeParams["fact_embedding"] = emb
+	// 3. Check for Contradictions, and for an invalidated fact stated again
+	var errs []error
+	if len(history) > 0 {
+		var result *model.ContradictionResult
+		if given != nil {
+			result = exclusiveContradictions(e, given.Exclusive, history)
+		} else if result, err = g.Deduplicator.CheckEdgeHistory(ctx, e.Fact, history); err != nil {
+			errs = append(errs, fmt.Errorf("failed to check contradictions for %q: %w", e.Fact, err))
+			result = &model.ContradictionResult{}
+		}
+		for _, cuuid := range result.ContradictedEdgeUUIDs {
+			if !active[cuuid] {
+				continue // Already invalid, or not a fact of this source
+			}
+			// Use new edge validity as invalid_at for old edge
+			if err := g.invalidateEdge(ctx, cuuid, now); err != nil {
+				errs = append(errs, err)
+			} else if err := g.recordChange(ctx, groupID, model.ChangeKindFact, cuuid, model.ChangeOpInvalidate); err != nil {
+				errs = append(errs, err)
 			}
 		}
-
-		g.Driver.ExecuteQuery(ctx, driver.SaveEntityEdgeQuery, edgeParams)
-		
-		nodeFacts[e.SourceNodeUUID] = append(nodeFacts[e.SourceNodeUUID], e.Fact)
-		nodeFacts[e.TargetNodeUUID] = append(nodeFacts[e.TargetNodeUUID], e.Fact)
+		if reinstated == "" {
+			reinstated = reinstatedEdge(history, result.ReinstatedEdgeUUID, e.TargetNodeUUID)
+		}
+	}
+	if reinstated != "" {
+		// The old fact is true again: reopen it rather than saving a duplicate
+		if err := g.reinstateEdge(ctx, reinstated, episodeUUID); err != nil {
+			errs = append(errs, err)
+		} else if err := g.renewExpiry(ctx, reinstated, e.RelationType, now); err != nil {
+			errs = append(errs, err)
+		} else if err := g.recordChange(ctx, groupID, model.ChangeKindFact, reinstated, model.ChangeOpUpdate); err != nil {
+			errs = append(errs, err)
+		}
+		return true, errors.Join(errs...)
+	}
+
+	edgeUUID := g.UUIDGenerator()
+	edgeParams := map[string]interface{}{
+		"uuid":           edgeUUID,
+		"source_uuid":    e.SourceNodeUUID,
+		"target_uuid":    e.TargetNodeUUID,
+		"name":           e.RelationType,
+		"fact":           e.Fact,
+		"group_id":       groupID,
+		"created_at":     now.Format(time.RFC3339),
+		"expired_at":     "",
+		"valid_at":       now.Format(time.RFC3339),
+		"invalid_at":     "",
+		"episodes":       []string{episodeUUID},
+		"fact_embedding": nil,
+		"fact_embedding_model": "",
+		"attributes":     "{}",
+	}
+
+	// Annotate how the user feels about facts about them
+	var attrs map[string]interface{}
+	if given != nil {
+		attrs = maps.Clone(given.Attributes)
+	} else {
+		attrs = g.factSentiment(ctx, groupID, e)
 	}
 
-	// Summarize Nodes
-	for _, node := range nodes {
-		if facts, hasFacts := nodeFacts[node.UUID]; hasFacts {
-			if newSummary, err := g.Summarizer.SummarizeNode(ctx, node, facts); err == nil {
-				node.Summary = newSummary
-				g.saveEntity(ctx, node)
+	// Date the fact by the dates it mentions ("last Tuesday", "in 2019") rather than by ingestion
+	validAt := now
+	if g.Config != nil && g.Config.Ingest.NormalizeDates {
+		if exprs := temporal.Normalize(e.Fact, now); len(exprs) > 0 {
+			if attrs == nil {
+				attrs = make(map[string]interface{})
 			}
+			attrs["temporal"] = exprs
+			validAt = exprs[0].Time.UTC()
 		}
 	}
-	return nil
+	if given != nil && given.ValidAt != nil {
+		validAt = given.ValidAt.UTC()
+	}
+
+	fact := model.EntityEdge{
+		UUID: edgeUUID, SourceUUID: e.SourceNodeUUID, TargetUUID: e.TargetNodeUUID, GroupID: groupID,
+		Name: e.RelationType, Fact: e.Fact, CreatedAt: now, ValidAt: validAt, Episodes: []string{episodeUUID}, Attributes: attrs,
+	}
+	if err := g.hooks.runPreSaveFact(ctx, &fact); err != nil {
+		errs = append(errs, fmt.Errorf("failed to save edge %q: %w", e.Fact, err))
+		return false, errors.Join(errs...)
+	}
+	validAt, attrs = fact.ValidAt.UTC(), fact.Attributes
+	edgeParams["name"], edgeParams["fact"] = fact.Name, fact.Fact
+	edgeParams["valid_at"] = validAt.Format(time.RFC3339)
+	if len(attrs) > 0 {
+		attrsJSON, err := json.Marshal(attrs)
+		if err != nil {
+			return false, fmt.Errorf("failed to encode attributes of %q: %w", e.Fact, err)
+		}
+		edgeParams["attributes"] = string(attrsJSON)
+	}
+
+	// Facts of transient relations ("is visiting") stop being current after their lifetime
+	if lifetime := g.factLifetime(fact.Name); lifetime > 0 {
+		edgeParams["expired_at"] = validAt.Add(lifetime).Format(time.RFC3339)
+	}
+
+	if emb, embeddingModel, err := g.embed(ctx, fact.Fact); err == nil && emb != nil {
+		edgeParams["fact_embedding"] = emb
+		edgeParams["fact_embedding_model"] = embeddingModel
+	}
+
+	if err := g.saveEntityEdge(ctx, groupID, edgeParams); err != nil {
+		errs = append(errs, fmt.Errorf("failed to save edge %q: %w", e.Fact, err))
+	} else if err := g.recordChange(ctx, groupID, model.ChangeKindFact, edgeUUID, model.ChangeOpCreate); err != nil {
+		errs = append(errs, err)
+	}
+	return true, errors.Join(errs...)
+}
+
+// saveEntityEdge saves a new fact under a citation handle free in its group.
+func (g *Graphiti) saveEntityEdge(ctx context.Context, groupID string, params map[string]interface{}) error {
+	g.citations.Lock()
+	defer g.citations.Unlock()
+	citation, err := g.citationHandle(ctx, groupID, params["uuid"].(string))
+	if err != nil {
+		return err
+	}
+	params["citation"] = citation
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveEntityEdgeQuery, params)
+	return err
 }
 
 func (g *Graphiti) linkNextEpisode(ctx context.Context, prevUUID, nextUUID, groupID string, now time.Time) error {
@@ -385,58 +784,103 @@ func (g *Graphiti) linkSagaHasEpisode(ctx context.Context, sagaUUID, episodeUUID
 	return err
 }
 
+// DetectAndSummarizeCommunities detects the group's communities and saves them
+// with their summaries. Only one detection of a group runs at a time: others
+// get ErrDetectionRunning until it finishes.
 func (g *Graphiti) DetectAndSummarizeCommunities(ctx context.Context, groupID string) error {
+	run, err := g.communityRuns.start(groupID)
+	if err != nil {
+		return err
+	}
+	saved, err := g.detectCommunities(ctx, groupID)
+	g.communityRuns.finish(run, saved, err)
+	if err == nil {
+		g.hooks.runGroupChanged(ctx, groupID)
+	}
+	return err
+}
+
+// detectCommunities runs a detection of the group and returns how many communities it saved.
+func (g *Graphiti) detectCommunities(ctx context.Context, groupID string) (int, error) {
+	if group, err := g.GetGroup(ctx, groupID); err == nil {
+		g = g.forGroup(group)
+	}
+
 	// 1. Fetch Group Nodes
 	nodes, err := g.getGroupNodes(ctx, groupID)
-	if err != nil { return err }
+	if err != nil { return 0, err }
 	
 	// 2. Fetch Group Edges
 	edges, err := g.getGroupEdges(ctx, groupID)
-	if err != nil { return err }
+	if err != nil { return 0, err }
+	if g.Config != nil && g.Config.Community.CoMentions {
+		coMentions, err := g.getCoMentionEdges(ctx, groupID)
+		if err != nil { return 0, err }
+		edges = append(edges, coMentions...)
+	}
 	
 	// 3. Detect Communities
-	communities, err := g.CommunityDetector.Detect(nodes, edges)
-	if err != nil { return err }
+	// Facts stated by more episodes tie their entities closer
+	communities, err := g.CommunityDetector.Detect(nodes, edges, community.MentionWeight)
+	if err != nil { return 0, err }
 	
 	now := time.Now().UTC()
+	saved := 0
 	
 	fmt.Printf("Detected %d communities for group %s\n", len(communities), groupID)
 
+	// Continue the existing communities the detected ones overlap
+	existing, err := g.getGroupCommunities(ctx, groupID)
+	if err != nil { return 0, err }
+	matchAt, stableAt := g.communityThresholds()
+	matches := matchCommunities(existing, communities, matchAt)
+	continued := make(map[string]bool)
+
 	// 4. Summarize and Save
 	for i, commNodes := range communities {
 		if len(commNodes) == 0 { continue }
 		
-		summaryText, err := g.Summarizer.SummarizeCommunity(ctx, commNodes)
-		if err != nil {
-			fmt.Printf("Error summarizing community: %v\n", err)
-			continue
+		commUUID, createdAt := g.UUIDGenerator(), now
+		prev, matched := matches[i]
+		if matched {
+			commUUID, createdAt = prev.UUID, prev.CreatedAt
+			continued[commUUID] = true
 		}
-		
-		name := fmt.Sprintf("Community %d", i+1)
-		
-		if summaryText != "" {
-			if n, err := g.Summarizer.GenerateCommunityName(ctx, summaryText); err == nil && n != "" {
-				name = n
+
+		var summaryText, name string
+		if matched && jaccard(prev.Members, memberUUIDs(commNodes)) >= stableAt {
+			// Barely changed: keep the name and summary it is known by
+			summaryText, name = prev.Summary, prev.Name
+		} else {
+			summaryText, err = g.Summarizer.SummarizeCommunity(ctx, commNodes)
+			if err != nil {
+				fmt.Printf("Error summarizing community: %v\n", err)
+				continue
+			}
+			
+			name = fmt.Sprintf("Community %d", i+1)
+			
+			if summaryText != "" {
+				if n, err := g.Summarizer.GenerateCommunityName(ctx, summaryText); err == nil && n != "" {
+					name = n
+				}
 			}
 		}
 		
-		commUUID := g.UUIDGenerator()
-		
 		// Save Community Node
 		commParams := map[string]interface{}{
 			"uuid":           commUUID,
 			"name":           name,
 			"group_id":       groupID,
-			"created_at":     now.Format(time.RFC3339),
+			"created_at":     createdAt.Format(time.RFC3339),
 			"summary":        summaryText,
 			"name_embedding": nil,
+			"name_embedding_model": "",
 		}
 		
-		if g.Embedder != nil {
-			vec, err := g.Embedder.Embed(ctx, name)
-			if err == nil {
-				commParams["name_embedding"] = vec
-			}
+		if vec, embeddingModel, err := g.embed(ctx, name); err == nil && vec != nil {
+			commParams["name_embedding"] = vec
+			commParams["name_embedding_model"] = embeddingModel
 		}
 
 		if _, err := g.Driver.ExecuteQuery(ctx, driver.SaveCommunityNodeQuery, commParams); err != nil {
@@ -445,6 +889,12 @@ func (g *Graphiti) DetectAndSummarizeCommunities(ctx context.Context, groupID st
 		}
 		
 		// Save Membership Edges
+		if matched {
+			if _, err := g.Driver.ExecuteQuery(ctx, driver.DeleteCommunityMembersQuery, map[string]interface{}{"uuid": commUUID}); err != nil {
+				fmt.Printf("Error clearing community members: %v\n", err)
+				continue
+			}
+		}
 		for _, n := range commNodes {
 			edgeParams := map[string]interface{}{
 				"uuid":        g.UUIDGenerator(),
@@ -457,75 +907,78 @@ func (g *Graphiti) DetectAndSummarizeCommunities(ctx context.Context, groupID st
 				fmt.Printf("Error saving community edge: %v\n", err)
 			}
 		}
+		saved++
 	}
-	return nil
+
+	// Communities no detected one continues have dissolved
+	for _, c := range existing {
+		if continued[c.UUID] { continue }
+		if _, err := g.Driver.ExecuteQuery(ctx, driver.DeleteCommunityQuery, map[string]interface{}{"uuid": c.UUID}); err != nil {
+			return saved, fmt.Errorf("failed to delete community %s: %w", c.UUID, err)
+		}
+	}
+	return saved, nil
 }
 
 func (g *Graphiti) getGroupNodes(ctx context.Context, groupID string) ([]model.EntityNode, error) {
-	res, err := g.Driver.ExecuteQuery(ctx, driver.GetGroupNodesQuery, map[string]interface{}{
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupNodesQuery, map[string]interface{}{
 		"group_id": groupID,
 	})
 	if err != nil {
 		return nil, err
 	}
 	
-	var nodes []model.EntityNode
-	for _, rec := range res.Records {
-		uuidVal, _ := rec.Get("uuid")
-		name, _ := rec.Get("name")
-		summaryVal, _ := rec.Get("summary")
-		
-		sumStr := ""
-		if summaryVal != nil {
-			sumStr = summaryVal.(string)
-		}
-
-		nodes = append(nodes, model.EntityNode{
-			UUID:    uuidVal.(string),
-			Name:    name.(string),
-			Summary: sumStr,
-			GroupID: groupID,
-		})
+	nodes, err := driver.ScanRecords[model.EntityNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group nodes: %w", err)
+	}
+	for i := range nodes {
+		nodes[i].GroupID = groupID
 	}
-	// Debug:
-	// fmt.Printf("DEBUG: DETECT Nodes: %d\n", len(nodes))
-	// nodeMap := make(map[string]string)
-	// for _, n := range nodes { 
-	// 	fmt.Printf(" - Node: %s (Name: %s)\n", n.UUID, n.Name) 
-	// 	nodeMap[n.UUID] = n.Name
-	// }
-	
 	return nodes, nil
 }
 
 func (g *Graphiti) getGroupEdges(ctx context.Context, groupID string) ([]model.EntityEdge, error) {
-	res, err := g.Driver.ExecuteQuery(ctx, driver.GetGroupEdgesQuery, map[string]interface{}{
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupEdgesQuery, map[string]interface{}{
 		"group_id": groupID,
 	})
 	if err != nil {
 		return nil, err
 	}
 	
-	var edges []model.EntityEdge
-	for _, rec := range res.Records {
-		uuidVal, _ := rec.Get("uuid")
-		source, _ := rec.Get("source_uuid")
-		target, _ := rec.Get("target_uuid")
-		fact, _ := rec.Get("fact")
-		
-		edges = append(edges, model.EntityEdge{
-			UUID:       uuidVal.(string),
-			SourceUUID: source.(string),
-			TargetUUID: target.(string),
-			Fact:       fact.(string),
-			GroupID:    groupID,
-		})
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group edges: %w", err)
+	}
+	for i := range edges {
+		edges[i].GroupID = groupID
+	}
+	return edges, nil
+}
+
+// getCoMentionEdges links the group's entities that at least [community]
+// min_co_mentions episodes mention together, with the number of those
+// episodes as the link's mention count.
+func (g *Graphiti) getCoMentionEdges(ctx context.Context, groupID string) ([]model.EntityEdge, error) {
+	minCount := 2
+	if g.Config.Community.MinCoMentions > 0 {
+		minCount = g.Config.Community.MinCoMentions
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetCoMentionsQuery, map[string]interface{}{
+		"group_id":  groupID,
+		"min_count": minCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch co-mentions: %w", err)
+	}
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read co-mentions: %w", err)
+	}
+	for i := range edges {
+		edges[i].GroupID = groupID
+		edges[i].Name = model.CoMentionRelation
 	}
-	
-	// Debug:
-	// fmt.Printf("DEBUG: DETECT Edges: %d\n", len(edges))
-	// for _, e := range edges { fmt.Printf(" - Edge: %s --[%s]-> %s\n", e.SourceUUID, e.Fact, e.TargetUUID) }
-	
 	return edges, nil
 }
 
@@ -539,9 +992,12 @@ func (g *Graphiti) checkEdgeExists(ctx context.Context, source, target, name, fa
 		return false, err
 	}
 	
-	for _, rec := range res.Records {
-		fVal, ok := rec.Get("fact")
-		if ok && fVal != nil && fVal.(string) == fact {
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return false, fmt.Errorf("failed to read active edges: %w", err)
+	}
+	for _, e := range edges {
+		if e.Fact == fact {
 			return true, nil
 		}
 	}
@@ -556,20 +1012,12 @@ func (g *Graphiti) getEdgesFromSource(ctx context.Context, source string) ([]mod
 		return nil, err
 	}
 	
-	var edges []model.EntityEdge
-	for _, rec := range res.Records {
-		uuid, _ := rec.Get("uuid")
-		fact, _ := rec.Get("fact")
-		name, _ := rec.Get("name")
-		target, _ := rec.Get("target_uuid")
-		
-		edges = append(edges, model.EntityEdge{
-			UUID:         uuid.(string),
-			SourceUUID:   source,
-			TargetUUID:   target.(string),
-			Name:         name.(string),
-			Fact:         fact.(string),
-		})
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edges from source: %w", err)
+	}
+	for i := range edges {
+		edges[i].SourceUUID = source
 	}
 	return edges, nil
 }
@@ -583,168 +1031,372 @@ func (g *Graphiti) invalidateEdge(ctx context.Context, uuid string, invalidAt ti
 }
 
 func (g *Graphiti) Search(ctx context.Context, groupID, query string) ([]model.EntityEdge, error) {
-	// Hybrid Search Implementation
-	
-	// 1. Get Embedding
-	var queryVector []float32
-	if g.Embedder != nil {
-		vec, err := g.Embedder.Embed(ctx, query)
-		if err == nil {
-			queryVector = vec
+	return g.SearchWithFilter(ctx, groupID, query, nil)
+}
+
+// SearchWithFilter is Search restricted to facts matching filter (nil matches all).
+func (g *Graphiti) SearchWithFilter(ctx context.Context, groupID, query string, filter *model.SearchFilter) ([]model.EntityEdge, error) {
+	edges, err := g.search(ctx, groupID, query, filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	return g.hooks.runPostSearch(ctx, groupID, query, edges)
+}
+
+// SearchDebug is SearchWithFilter that also returns a trace of each stage.
+// It always runs the search instead of serving it from the cache.
+func (g *Graphiti) SearchDebug(ctx context.Context, groupID, query string, filter *model.SearchFilter) ([]model.EntityEdge, *model.SearchTrace, error) {
+	trace := &model.SearchTrace{Candidates: []model.SearchCandidate{}, Results: []string{}}
+	edges, err := g.search(ctx, groupID, query, filter, trace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if edges, err = g.hooks.runPostSearch(ctx, groupID, query, edges); err != nil {
+		return nil, nil, err
+	}
+	return edges, trace, nil
+}
+
+// search runs SearchWithFilter, recording each stage in trace when it is not nil.
+func (g *Graphiti) search(ctx context.Context, groupID, query string, filter *model.SearchFilter, trace *model.SearchTrace) ([]model.EntityEdge, error) {
+	if filter != nil && len(filter.Attributes) > 0 && g.Config != nil && g.Config.Ingest.NormalizeQuantities {
+		// Match quantities the way ingestion stored them: {"distance": "5km"} finds 5000 m
+		normalized := *filter
+		normalized.Attributes = quantity.NormalizeAttributes(filter.Attributes)
+		filter = &normalized
+	}
+	filterParams, err := searchFilterParams(filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil && g.Cipher != nil {
+		// Encrypted attributes can't be prefiltered in the graph; filterByAttributes still matches them
+		for k := range filter.Attributes {
+			if g.encryptsAttribute(k) {
+				filterParams["attribute_fragments"] = nil
+			}
 		}
 	}
+	similarity, err := g.similarityParams(filter)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range similarity {
+		filterParams[k] = v
+	}
+	access := g.accessFilter(ctx)
+	var cacheKey string
+	var cacheGen int64
+	if g.SearchCache != nil {
+		cacheKey = searchCacheKey(query, filter) + access.cacheKey()
+		cacheGen = g.SearchCache.Generation(ctx, groupID)
+		if edges, ok := g.SearchCache.Get(ctx, groupID, cacheKey); ok && trace == nil {
+			return edges, nil
+		}
+	}
+	if filter != nil && len(filter.Topics) > 0 {
+		if filterParams["topic_episodes"], err = g.topicEpisodes(ctx, groupID, filter.Topics); err != nil {
+			return nil, err
+		}
+	}
+
+	// Hybrid Search Implementation
+	start := time.Now()
+	
+	// 1. Get Embeddings, one per configured model (active first).
+	// Without them (no embedder, or it failed) fall back to text search.
+	queryVectors, _ := g.queryEmbeddings(ctx, g.searchText(ctx, query, filter, trace))
+	start = traceStage(trace, "embed", start)
 	
 	// 2. Construct Query
 	// By default, text search on Edge Facts
-	cypher := `
-		MATCH (n:Entity)-[e:RELATES_TO]->(m:Entity)
-		WHERE e.group_id = $group_id AND e.fact CONTAINS $query
-		RETURN e.uuid AS uuid, 
-		       n.uuid AS source_uuid, 
-		       m.uuid AS target_uuid, 
-		       e.name AS name,
-		       e.fact AS fact, 
-		       e.created_at AS created_at,
-		       e.episodes AS episodes
-		LIMIT 20
-	`
-	
 	params := map[string]interface{}{
 		"group_id": groupID,
 		"query":    query,
 	}
-
-	if len(queryVector) > 0 {
-		params["embedding"] = queryVector
-		// Vector Search on Edge Fact Embeddings
-		cypher = `
-            MATCH (n:Entity)-[e:RELATES_TO]->(m:Entity)
-            WHERE e.group_id = $group_id AND e.fact_embedding IS NOT NULL
-            WITH e, n, m,
-                 reduce(dot = 0.0, i in range(0, size(e.fact_embedding)-1) | dot + e.fact_embedding[i] * $embedding[i]) / 
-                 (sqrt(reduce(s1 = 0.0, x in e.fact_embedding | s1 + x^2)) * sqrt(reduce(s2 = 0.0, y in $embedding | s2 + y^2))) AS score
-            ORDER BY score DESC
-            RETURN e.uuid AS uuid, 
-                   n.uuid AS source_uuid, 
-                   m.uuid AS target_uuid, 
-                   e.name AS name,
-                   e.fact AS fact, 
-                   e.created_at AS created_at,
-                   e.episodes AS episodes,
-                   score
-            LIMIT 20
-        `
+	for k, v := range filterParams {
+		params[k] = v
 	}
-	
-	result, err := g.Driver.ExecuteQuery(ctx, cypher, params)
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
-	}
-	
+
 	var edges []model.EntityEdge
-	for _, record := range result.Records {
-		uuidStr, _ := record.Get("uuid")
-		sourceStr, _ := record.Get("source_uuid")
-		targetStr, _ := record.Get("target_uuid")
-		nameStr, _ := record.Get("name")
-		factStr, _ := record.Get("fact")
-		createdAtStr, _ := record.Get("created_at") // Parsing needed?
-		episodesVal, _ := record.Get("episodes")
-
-		edge := model.EntityEdge{
-			UUID:       uuidStr.(string),
-			SourceUUID: sourceStr.(string),
-			TargetUUID: targetStr.(string), // Assuming these are UUID strings from graph
-			GroupID:    groupID,
-			Name:       nameStr.(string),
-			Fact:       factStr.(string),
-		}
-		
-		// Parse CreatedAt
-		if tStr, ok := createdAtStr.(string); ok {
-			if t, err := time.Parse(time.RFC3339, tStr); err == nil {
-				edge.CreatedAt = t
+	if len(queryVectors) == 0 {
+		// Each rewrite through the group's synonyms is matched too, after the query
+		found := make(map[string]bool)
+		for _, variant := range queryVariants(query, g.groupSynonyms(ctx, groupID)) {
+			params["query"] = variant
+			result, err := g.Driver.ExecuteReadQuery(ctx, driver.SearchEdgesByTextQuery, params)
+			if err != nil {
+				return nil, fmt.Errorf("search failed: %w", err)
+			}
+			matched, err := driver.ScanRecords[model.EntityEdge](result)
+			if err != nil {
+				return nil, fmt.Errorf("search failed: %w", err)
+			}
+			matched = access.edges(matched)
+			traceCandidates(trace, result, matched, "")
+			for _, e := range matched {
+				if !found[e.UUID] {
+					found[e.UUID] = true
+					edges = append(edges, e)
+				}
 			}
 		}
-
-		// Parse Episodes (could be list or string depending on stored format, assuming list of strings in Cypher or serialized)
-		if epList, ok := episodesVal.([]interface{}); ok {
-			for _, ep := range epList {
-				if s, ok := ep.(string); ok {
-					edge.Episodes = append(edge.Episodes, s)
+	}
+	// Vector Search on Edge Fact Embeddings of each model; a fact found
+	// through the active model keeps that position
+	seen := make(map[string]bool)
+	scores := make(map[string]float64)
+	confidences := make(map[string]float64)
+	metric, _ := params["metric"].(string)
+	for _, qv := range queryVectors {
+		params["embedding"] = qv.Vector
+		params["embedding_model"] = qv.Model
+		result, err := g.Driver.ExecuteReadQuery(ctx, driver.SearchEdgesByVectorQuery, params)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		found, err := driver.ScanRecords[model.EntityEdge](result)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		found = access.edges(found)
+		traceCandidates(trace, result, found, qv.Model)
+		modelScores, factNorms := recordScores(result), recordFloats(result, "fact_norm")
+		queryNorm := vectorNorm(qv.Vector)
+		for _, e := range found {
+			if !seen[e.UUID] {
+				seen[e.UUID] = true
+				edges = append(edges, e)
+				if score, ok := modelScores[e.UUID]; ok {
+					scores[e.UUID] = score
+					confidences[e.UUID] = matchConfidence(metric, score, queryNorm, factNorms[e.UUID])
 				}
 			}
 		}
-
-		edges = append(edges, edge)
+	}
+	for i := range edges {
+		edges[i].GroupID = groupID
+		confidence, ok := confidences[edges[i].UUID]
+		if !ok {
+			confidence = 1 // Text matches contain the query verbatim
+		}
+		edges[i].Confidence = &confidence
+	}
+	start = traceStage(trace, "retrieve", start)
+	if filter != nil {
+		if edges, err = g.filterByAttributes(ctx, edges, filter.Attributes); err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		traceFiltered(trace, edges)
+		start = traceStage(trace, "filter", start)
 	}
 
 	// Reranking
-	if g.Reranker != nil && len(edges) > 1 {
-		facts := make([]string, len(edges))
-		for i, e := range edges {
-			facts[i] = e.Fact
+	// Only the top rerank depth of candidates is reranked, and none when the
+	// best vector score leads by the skip margin
+	depth, margin := g.rerankLimits(filter)
+	head, tail := edges, []model.EntityEdge(nil)
+	if depth > 0 && depth < len(edges) {
+		head, tail = edges[:depth], edges[depth:]
+	}
+	skipped := decisiveLead(edges, scores, margin)
+	if skipped && trace != nil {
+		trace.RerankSkipped = true
+	}
+	if g.Reranker != nil && len(head) > 1 && !skipped {
+		indices, cached, err := g.rankEdges(ctx, groupID, query, head)
+		if err != nil && trace != nil {
+			trace.RerankError = err.Error()
+		}
+		if trace != nil {
+			trace.RerankCached = cached
 		}
-
-		indices, err := g.Reranker.Rank(ctx, query, facts)
 		if err == nil && len(indices) > 0 {
 			var reordered []model.EntityEdge
 			seen := make(map[int]bool)
 			// Reorder based on rank
 			for _, idx := range indices {
-				if idx >= 0 && idx < len(edges) && !seen[idx] {
-					reordered = append(reordered, edges[idx])
+				if idx >= 0 && idx < len(head) && !seen[idx] {
+					reordered = append(reordered, head[idx])
 					seen[idx] = true
 				}
 			}
 			// Append remaining (if any were missed by reranker)
-			for i := range edges {
+			for i := range head {
 				if !seen[i] {
-					reordered = append(reordered, edges[i])
+					reordered = append(reordered, head[i])
 				}
 			}
-			edges = reordered
+			edges = append(reordered, tail...)
+			if trace != nil {
+				trace.Reranked = edgeUUIDs(edges)
+			}
 		}
+		start = traceStage(trace, "rerank", start)
+	}
+
+	if weight := g.reinforcementWeight(); weight > 0 && len(edges) > 1 {
+		edges = rankReinforcedEdges(edges, weight)
+		start = traceStage(trace, "reinforce", start)
 	}
 
+	// Entity linking: facts about entities the query names rank first
+	linked, err := g.linkQueryEntities(ctx, groupID, query)
+	if err != nil {
+		fmt.Printf("Entity linking failed: %v\n", err)
+	}
+	edges = boostLinkedEdges(edges, linked)
+	traceStage(trace, "link", start)
+	if trace != nil {
+		trace.Strategy = "text"
+		for _, qv := range queryVectors {
+			trace.Strategy = "vector"
+			trace.EmbeddingModels = append(trace.EmbeddingModels, qv.Model)
+		}
+		if trace.Strategy == "vector" {
+			trace.Metric, _ = params["metric"].(string)
+			if minScore, ok := params["min_score"].(float64); ok {
+				trace.MinScore = &minScore
+			}
+		}
+		trace.LinkedEntities = linked
+		trace.Results = edgeUUIDs(edges)
+	}
+
+	if g.SearchCache != nil {
+		g.SearchCache.Set(ctx, groupID, cacheKey, cacheGen, edges)
+	}
 	return edges, nil
 }
 
 	// BulkAddEpisodes adds multiple episodes in a true batch process
 func (g *Graphiti) BulkAddEpisodes(ctx context.Context, groupID string, episodes []model.EpisodeData) error {
+	_, err := g.bulkAdd(ctx, groupID, episodes, false, nil)
+	return err
+}
+
+// bulkAdd runs the batch pipeline. In partial mode per-episode failures are
+// returned in the slice (indexed like episodes) and kept as dead letters
+// instead of aborting the batch;
+// only failures that affect the whole batch are returned as the error.
+// resolved, when non-nil, maps entity names to UUIDs settled by earlier batches
+// of the same job: those names skip LLM deduplication, and the map is updated
+// with every node this batch saves.
+func (g *Graphiti) bulkAdd(ctx context.Context, groupID string, episodes []model.EpisodeData, partial bool, resolved map[string]string) ([]error, error) {
+	ctx = asBatch(ctx)
+	// Apply the [ingest] content limits; an episode's chunks are ingested as separate episodes
+	epErrs := make([]error, len(episodes))
+	var fitted []model.EpisodeData
+	var origin []int
+	for i, ep := range episodes {
+		chunks, err := g.fitContent(ep.Content)
+		if err != nil {
+			if !partial {
+				return nil, fmt.Errorf("ep[%d]: %w", i, err)
+			}
+			epErrs[i] = err
+			continue
+		}
+		for _, chunk := range chunks {
+			ep.Content = chunk
+			fitted = append(fitted, ep)
+			origin = append(origin, i)
+		}
+	}
+
+	fittedErrs, err := g.ingestBatch(ctx, groupID, fitted, partial, resolved)
+	if err != nil {
+		return nil, err
+	}
+	for j, err := range fittedErrs {
+		if err != nil && epErrs[origin[j]] == nil {
+			epErrs[origin[j]] = err
+		}
+	}
+	for i, err := range epErrs {
+		if err != nil {
+			g.recordDeadLetter(ctx, groupID, "message", episodes[i], err)
+		}
+	}
+	return epErrs, nil
+}
+
+// ingestBatch is bulkAdd for episodes within the content limits.
+func (g *Graphiti) ingestBatch(ctx context.Context, groupID string, episodes []model.EpisodeData, partial bool, resolved map[string]string) ([]error, error) {
 	now := time.Now().UTC()
+	epErrs := make([]error, len(episodes))
+	defer g.invalidateSearchCache(ctx, groupID)
+
+	group, err := g.ensureGroup(ctx, groupID, now)
+	if err != nil {
+		return nil, err
+	}
+	// Run the rest of the pipeline with the group's prompt/model overrides
+	g = g.forGroup(group)
 
 	// 1. Prepare Episodes and Context
 	// Get shared context for batch
-	prevEpisodes, _ := g.retrievePreviousEpisodes(ctx, groupID, "", 5)
+	episodeCtx, err := g.loadEpisodeContext(ctx, groupID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load context episodes: %w", err)
+	}
 	
 	type extractionResult struct {
 		index    int
 		entities []model.ExtractedEntity
+		content  string // after coreference resolution
+		ontology string
 		err      error
 	}
 
-	// Determine concurrency limit
-	limit := 2
-	if g.Config != nil && g.Config.Concurrency.BulkIngest > 0 {
-		limit = g.Config.Concurrency.BulkIngest
-	}
-
 	resultsChan := make(chan extractionResult, len(episodes))
+	verdicts := make([]moderationVerdict, len(episodes))
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, limit) // concurrency for LLM calls
 
-	// 2. Concurrent Extraction
+	// 2. Concurrent Extraction, sharing the bulk ingest limit with other requests
 	for i, ep := range episodes {
+		// Stop queueing work once the caller gives up; pending episodes fail with the context error
+		if err := g.bulkIngest.Acquire(ctx, groupID); err != nil {
+			resultsChan <- extractionResult{index: i, err: err}
+			continue
+		}
 		wg.Add(1)
-		sem <- struct{}{}
 		go func(idx int, e model.EpisodeData) {
 			defer wg.Done()
-			defer func() { <-sem }()
+			defer g.bulkIngest.Release()
 			
+			schema := e.Schema
+			if schema == "" {
+				schema = group.Settings.Ontology
+			}
+
+			if err := g.extraction.Acquire(ctx, groupID); err != nil {
+				resultsChan <- extractionResult{index: idx, err: err}
+				return
+			}
+			defer g.extraction.Release()
+
+			// Blocked episodes aren't extracted
+			verdict, err := g.moderate(ctx, group, "message", e.Content)
+			if err != nil {
+				resultsChan <- extractionResult{index: idx, err: err}
+				return
+			}
+			verdicts[idx] = verdict
+
 			// Extract Entities
-			entities, err := g.Extractor.ExtractNodes(ctx, e.Content, e.Schema, prevEpisodes) // Use shared context
-			resultsChan <- extractionResult{index: idx, entities: entities, err: err}
+			prevEpisodes := episodeCtx.For(ctx, e.Content) // Shared candidates, picked per episode
+			content, err := g.hooks.runPreExtract(ctx, groupID, g.resolveCoreferences(ctx, e.Content, prevEpisodes))
+			if err != nil {
+				resultsChan <- extractionResult{index: idx, err: err}
+				return
+			}
+			entities, err := g.Extractor.ExtractNodes(ctx, content, schema, prevEpisodes)
+			entities = checkAttributes(entities, schema, group.Settings)
+			if err == nil {
+				entities, err = g.hooks.runPostExtractEntities(ctx, groupID, entities)
+			}
+			resultsChan <- extractionResult{index: idx, entities: entities, content: content, ontology: schema, err: err}
 		}(i, ep)
 	}
 	wg.Wait()
@@ -752,32 +1404,37 @@ func (g *Graphiti) BulkAddEpisodes(ctx context.Context, groupID string, episodes
 
 	// Collect results mapped by index
 	episodeExtracted := make(map[int][]model.ExtractedEntity)
+	resolvedContents := make([]string, len(episodes))
+	ontologies := make([]string, len(episodes))
 	var errs []string
 
 	for res := range resultsChan {
 		if res.err != nil {
 			errs = append(errs, fmt.Sprintf("ep[%d]: %v", res.index, res.err))
+			epErrs[res.index] = fmt.Errorf("extraction failed: %w", res.err)
 			continue
 		}
 		episodeExtracted[res.index] = res.entities
+		resolvedContents[res.index] = res.content
+		ontologies[res.index] = res.ontology
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("bulk extraction errors: %v", errs)
+	if len(errs) > 0 && !partial {
+		return nil, fmt.Errorf("bulk extraction errors: %v", errs)
 	}
 
 	// 3. Global Deduplication (Batch + DB)
 	// Flatten all extracted entities to nodes
 	var allTempNodes []model.EntityNode
-	for _, entities := range episodeExtracted {
-		nodes := g.convertToEntityNodes(entities, groupID, now)
+	for idx, entities := range episodeExtracted {
+		nodes := g.convertToEntityNodes(entities, ontologies[idx], groupID, now)
 		allTempNodes = append(allTempNodes, nodes...)
 	}
 
 	// First, fetch existing entities from DB
 	existingNodes, err := g.getGroupNodes(ctx, groupID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch existing nodes: %w", err)
+		return nil, fmt.Errorf("failed to fetch existing nodes: %w", err)
 	}
 	
 	// Dedupe within batch (ByName)
@@ -794,16 +1451,41 @@ func (g *Graphiti) BulkAddEpisodes(ctx context.Context, groupID string, episodes
 	}
 
 	// Resolve against DB
-	finalNodes := g.resolveDuplicates(ctx, batchNodes, existingNodes)
+	var toResolve, finalNodes []model.EntityNode
+	for _, n := range batchNodes {
+		if uuid, ok := resolved[n.Name]; ok {
+			n.UUID = uuid
+			for _, en := range existingNodes {
+				if en.UUID == uuid {
+					n.Summary = en.Summary
+					break
+				}
+			}
+			finalNodes = append(finalNodes, n)
+			continue
+		}
+		toResolve = append(toResolve, n)
+	}
+	if resolved == nil || len(toResolve) > 0 {
+		finalNodes = append(finalNodes, g.resolveDuplicates(ctx, toResolve, existingNodes)...)
+	}
 
 	// 4. Save Nodes
 	// Build a map of Name -> FinalNode for quick lookup later
 	finalNodeMap := make(map[string]model.EntityNode)
+	failedNodes := make(map[string]error)
 	for _, n := range finalNodes {
 		if err := g.saveEntity(ctx, n); err != nil {
-			return fmt.Errorf("failed to save node %s: %w", n.Name, err)
+			if !partial {
+				return nil, fmt.Errorf("failed to save node %s: %w", n.Name, err)
+			}
+			failedNodes[n.Name] = fmt.Errorf("failed to save node %s: %w", n.Name, err)
+			continue
 		}
 		finalNodeMap[n.Name] = n
+		if resolved != nil {
+			resolved[n.Name] = n.UUID
+		}
 	}
 	
 	// Also map existing nodes in case resolution picked one of them and it wasn't in finalNodes (resolveDuplicates returns mixed?)
@@ -813,63 +1495,80 @@ func (g *Graphiti) BulkAddEpisodes(ctx context.Context, groupID string, episodes
 	
 	// 5. Run AddEpisode Concurrently (using pre-resolved nodes)
 	
-	sem2 := make(chan struct{}, limit)
-	errChan2 := make(chan error, len(episodes))
+	phase2Errs := make([]error, len(episodes))
 	
+episodes:
 	for i, ep := range episodes {
+		if epErrs[i] != nil {
+			continue
+		}
 		// Reconstruct the node list for this episode using the resolved map
 		extracted := episodeExtracted[i]
 		var episodeResolvedNodes []model.EntityNode
 		for _, ex := range extracted {
+			if err, failed := failedNodes[ex.Name]; failed {
+				epErrs[i] = err
+				continue episodes
+			}
 			if resolved, ok := finalNodeMap[ex.Name]; ok {
 				episodeResolvedNodes = append(episodeResolvedNodes, resolved)
 			}
 		}
 
+		if err := g.bulkIngest.Acquire(ctx, groupID); err != nil {
+			phase2Errs[i] = fmt.Errorf("failed to add episode: %w", err)
+			continue
+		}
 		wg.Add(1)
-		sem2 <- struct{}{}
-		go func(e model.EpisodeData, nodes []model.EntityNode) {
+		go func(idx int, e model.EpisodeData, nodes []model.EntityNode, content string) {
 			defer wg.Done()
-			defer func() { <-sem2 }()
+			defer g.bulkIngest.Release()
 			
 			// Call internal method with pre-resolved nodes to skip double extraction
-			if err := g.addEpisodeInternal(ctx, groupID, "message", e.Content, e.Saga, e.Schema, nodes); err != nil {
-				errChan2 <- fmt.Errorf("failed to add episode: %w", err)
+			if err := g.addEpisodeInternal(ctx, groupID, "message", e.Content, e.Saga, e.Schema, nodes, content, &verdicts[idx]); err != nil {
+				phase2Errs[idx] = fmt.Errorf("failed to add episode: %w", err)
 			}
-		}(ep, episodeResolvedNodes)
+		}(i, ep, episodeResolvedNodes, resolvedContents[i])
 	}
 	wg.Wait()
-	close(errChan2)
 	
-	if len(errChan2) > 0 {
-		var errMsgs []string
-		for err := range errChan2 {
+	var errMsgs []string
+	for i, err := range phase2Errs {
+		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
+			epErrs[i] = err
 		}
-		return fmt.Errorf("bulk add (phase 2) errors: %v", errMsgs)
+	}
+	if len(errMsgs) > 0 && !partial {
+		return nil, fmt.Errorf("bulk add (phase 2) errors: %v", errMsgs)
 	}
 
-	return nil
+	return epErrs, nil
 }
 
-// BulkSearch executes multiple search queries concurrently
+// BulkSearch executes multiple search queries concurrently. The queries are
+// embedded up front, in one request per embedder when it supports batches.
 func (g *Graphiti) BulkSearch(ctx context.Context, groupID string, queries []model.BulkSearchQuery) (map[string][]model.EntityEdge, error) {
-	limit := 5
-	if g.Config != nil && g.Config.Concurrency.BulkSearch > 0 {
-		limit = g.Config.Concurrency.BulkSearch
+	ctx = asBatch(ctx)
+	texts := make([]string, len(queries))
+	for i, q := range queries {
+		texts[i] = q.Query
 	}
-	sem := make(chan struct{}, limit)
+	ctx = g.withQueryEmbeddings(ctx, texts)
 	var wg sync.WaitGroup
 	results := make(map[string][]model.EntityEdge)
 	var mu sync.Mutex
 	errChan := make(chan error, len(queries))
 
 	for _, q := range queries {
+		if err := g.bulkSearch.Acquire(ctx, groupID); err != nil {
+			errChan <- err
+			break
+		}
 		wg.Add(1)
-		sem <- struct{}{}
 		go func(query model.BulkSearchQuery) {
 			defer wg.Done()
-			defer func() { <-sem }()
+			defer g.bulkSearch.Release()
 			
 			res, err := g.Search(ctx, groupID, query.Query)
 			if err != nil {
@@ -897,9 +1596,16 @@ func (g *Graphiti) BulkSearch(ctx context.Context, groupID string, queries []mod
 
 	// Helper: Save Entity Node
 	func (g *Graphiti) saveEntity(ctx context.Context, node model.EntityNode) error {
+	if err := g.hooks.runPreSaveEntity(ctx, &node); err != nil {
+		return err
+	}
+	attributes, err := g.encryptAttributes(node.UUID, node.Attributes)
+	if err != nil {
+		return err
+	}
 	var attrsJSON string
-	if len(node.Attributes) > 0 {
-		if b, err := json.Marshal(node.Attributes); err == nil {
+	if len(attributes) > 0 {
+		if b, err := json.Marshal(attributes); err == nil {
 			attrsJSON = string(b)
 		} else {
 			attrsJSON = "{}" // Default on error
@@ -915,30 +1621,25 @@ func (g *Graphiti) BulkSearch(ctx context.Context, groupID string, queries []mod
 		"created_at":     node.CreatedAt.Format(time.RFC3339),
 		"summary":        node.Summary, 
 		"name_embedding": nil, 
+		"name_embedding_model": "",
 		"attributes":     attrsJSON,
-		"labels":         []string{},
+		"labels":         node.Labels,
 	}
 	
-	if g.Embedder != nil {
-		emb, err := g.Embedder.Embed(ctx, node.Name)
-		if err == nil {
-			params["name_embedding"] = emb
-		}
+	if emb, embeddingModel, err := g.embed(ctx, node.Name); err == nil && emb != nil {
+		params["name_embedding"] = emb
+		params["name_embedding_model"] = embeddingModel
 	}
 
-	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEntityNodeQuery, params)
-	return err
+	res, err := g.Driver.ExecuteQuery(ctx, driver.SaveEntityNodeQuery, params)
+	if err != nil {
+		return err
+	}
+	return g.recordEntitySave(ctx, node.GroupID, node.UUID, res)
 }
 
 func (g *Graphiti) SearchEdges(ctx context.Context, groupID, query string) ([]model.EntityEdge, error) {
-	cypher := `
-		MATCH (s:Entity)-[e:RELATES_TO]->(t:Entity)
-		WHERE e.group_id = $group_id AND e.fact CONTAINS $query
-		RETURN e.uuid as uuid, e.source_uuid as source, e.target_uuid as target, e.name as name, e.fact as fact
-		LIMIT 10
-	`
-	
-	res, err := g.Driver.ExecuteQuery(ctx, cypher, map[string]interface{}{
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.SearchEdgesQuery, map[string]interface{}{
 		"group_id": groupID,
 		"query":    query,
 	})
@@ -946,22 +1647,13 @@ func (g *Graphiti) SearchEdges(ctx context.Context, groupID, query string) ([]mo
 		return nil, err
 	}
 	
-	var edges []model.EntityEdge
-	for _, rec := range res.Records {
-		uuid, _ := rec.Get("uuid")
-		source, _ := rec.Get("source")
-		target, _ := rec.Get("target")
-		name, _ := rec.Get("name")
-		fact, _ := rec.Get("fact")
-		
-		edges = append(edges, model.EntityEdge{
-			UUID:           uuid.(string),
-			SourceUUID:     source.(string),
-			TargetUUID:     target.(string),
-			Name:           name.(string),
-			Fact:           fact.(string),
-			GroupID:        groupID,
-		})
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edges: %w", err)
+	}
+	edges = g.accessFilter(ctx).edges(edges)
+	for i := range edges {
+		edges[i].GroupID = groupID
 	}
 	return edges, nil
 }
@@ -998,13 +1690,11 @@ func (g *Graphiti) getOrCreateSaga(ctx context.Context, name, groupID string, no
 	}
 
 	if len(res.Records) > 0 {
-		rec := res.Records[0]
-		uuidVal, _ := rec.Get("uuid")
-		return &model.SagaNode{
-			UUID:      uuidVal.(string),
-			Name:      name,
-			GroupID:   groupID,
-		}, nil
+		var saga model.SagaNode
+		if err := driver.ScanRecord(res.Records[0], &saga); err != nil {
+			return nil, fmt.Errorf("failed to read saga: %w", err)
+		}
+		return &saga, nil
 	}
 
 	newNode := &model.SagaNode{
@@ -1038,8 +1728,11 @@ func (g *Graphiti) findPreviousEpisodeInSaga(ctx context.Context, sagaUUID, curr
 	}
 
 	if len(res.Records) > 0 {
-		uuidVal, _ := res.Records[0].Get("uuid")
-		return uuidVal.(string), nil
+		var ep model.EpisodicNode
+		if err := driver.ScanRecord(res.Records[0], &ep); err != nil {
+			return "", fmt.Errorf("failed to read previous episode: %w", err)
+		}
+		return ep.UUID, nil
 	}
 	return "", nil
 }
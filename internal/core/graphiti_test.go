@@ -80,7 +80,7 @@ func TestAddEpisode(t *testing.T) {
 		return fmt.Sprintf("uuid-%d", uuidCounter)
 	}
 	
-	err := g.AddEpisode(context.Background(), "group-1", "Ep1", "Alice met Bob.", "")
+	err := g.AddEpisode(context.Background(), "group-1", "Ep1", "Alice met Bob.", "", "")
 	
 	assert.NoError(t, err)
 	// ... existing test content ...
@@ -150,7 +150,7 @@ func TestAddEpisode_Deduplication(t *testing.T) {
 	}
 	
 	// Add Episode
-	err := g.AddEpisode(context.Background(), "group-1", "Ep2", "Alice is back.", "")
+	err := g.AddEpisode(context.Background(), "group-1", "Ep2", "Alice is back.", "", "")
 	assert.NoError(t, err)
 	
 	// Verify Dedupe Logic:
@@ -180,7 +180,7 @@ func TestAddEpisode_ExtractionError(t *testing.T) {
 	
 	g := NewGraphiti(mockDriver, mockLLM, &MockEmbedder{}, nil, cfg)
 	
-	err := g.AddEpisode(context.Background(), "group-1", "Ep1", "content", "")
+	err := g.AddEpisode(context.Background(), "group-1", "Ep1", "content", "", "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "extraction failed")
 }
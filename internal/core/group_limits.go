@@ -0,0 +1,239 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// ErrGroupLimit is returned for episodes ingested into a group at one of its
+// size limits that the limit's action couldn't make room for.
+var ErrGroupLimit = errors.New("group size limit reached")
+
+// groupLimits returns the configured [group_limits] with the group's own
+// limits applied over them.
+func (g *Graphiti) groupLimits(group *model.GroupNode) model.GroupLimits {
+	var limits model.GroupLimits
+	if g.Config != nil {
+		c := g.Config.GroupLimits
+		limits = model.GroupLimits{MaxEntities: c.MaxEntities, MaxEdges: c.MaxEdges, MaxEpisodes: c.MaxEpisodes, OnLimit: c.OnLimit}
+	}
+	if o := group.Settings.Limits; o != nil {
+		if o.MaxEntities > 0 {
+			limits.MaxEntities = o.MaxEntities
+		}
+		if o.MaxEdges > 0 {
+			limits.MaxEdges = o.MaxEdges
+		}
+		if o.MaxEpisodes > 0 {
+			limits.MaxEpisodes = o.MaxEpisodes
+		}
+		if o.OnLimit != "" {
+			limits.OnLimit = o.OnLimit
+		}
+		limits.Pinned = o.Pinned
+	}
+	if limits.OnLimit == "" {
+		limits.OnLimit = model.LimitActionReject
+	}
+	return limits
+}
+
+func validLimitAction(action string) bool {
+	switch action {
+	case "", model.LimitActionReject, model.LimitActionEvictOldest, model.LimitActionCompact:
+		return true
+	}
+	return false
+}
+
+// limitKind is one of the counts a group limit caps.
+type limitKind struct {
+	name   string
+	count  func(*model.GroupStats) int
+	limit  func(model.GroupLimits) int
+	oldest string // Query for the UUIDs of the kind, oldest first
+	remove func(g *Graphiti, ctx context.Context, uuid string) error
+}
+
+var limitKinds = []limitKind{
+	{"episodes", func(s *model.GroupStats) int { return s.Episodes }, func(l model.GroupLimits) int { return l.MaxEpisodes },
+		driver.GetOldestEpisodesQuery, (*Graphiti).DeleteEpisode},
+	{"entities", func(s *model.GroupStats) int { return s.Entities }, func(l model.GroupLimits) int { return l.MaxEntities },
+		driver.GetOldestEntitiesQuery, deleteByUUID(driver.DeleteEntityNodeQuery, model.ChangeKindEntity)},
+	{"facts", func(s *model.GroupStats) int { return s.Edges }, func(l model.GroupLimits) int { return l.MaxEdges },
+		driver.GetOldestEdgesQuery, deleteByUUID(driver.DeleteEntityEdgeQuery, model.ChangeKindFact)},
+}
+
+func deleteByUUID(query, kind string) func(g *Graphiti, ctx context.Context, uuid string) error {
+	return func(g *Graphiti, ctx context.Context, uuid string) error {
+		res, err := g.Driver.ExecuteQuery(ctx, query, map[string]interface{}{"uuid": uuid})
+		if err != nil {
+			return err
+		}
+		return g.recordDeletions(ctx, kind, res)
+	}
+}
+
+// excess is how many of kind must go for the group to take one more episode.
+func (k limitKind) excess(stats *model.GroupStats, limits model.GroupLimits) int {
+	if limit := k.limit(limits); limit > 0 && k.count(stats) >= limit {
+		return k.count(stats) - limit + 1
+	}
+	return 0
+}
+
+// enforceGroupLimits makes room for a new episode in a group at one of its
+// limits as the limits' OnLimit says, or fails with ErrGroupLimit. Limits are
+// checked before each episode, so one episode can take a group past its
+// entity and fact limits; the next ingest brings it back.
+func (g *Graphiti) enforceGroupLimits(ctx context.Context, group *model.GroupNode) error {
+	limits := g.groupLimits(group)
+	if limits.MaxEntities == 0 && limits.MaxEdges == 0 && limits.MaxEpisodes == 0 {
+		return nil
+	}
+	// Concurrent episodes would each evict for the same excess
+	defer g.limitLocks.lock(group.GroupID)()
+	stats, err := g.GetGroupStats(ctx, group.GroupID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(limitKinds, func(k limitKind) bool { return k.excess(stats, limits) > 0 }) {
+		return nil
+	}
+
+	switch limits.OnLimit {
+	case model.LimitActionEvictOldest:
+		for i, kind := range limitKinds {
+			// Evicting one kind can free others, e.g. an entity takes its facts along
+			if i > 0 {
+				if stats, err = g.GetGroupStats(ctx, group.GroupID); err != nil {
+					return err
+				}
+			}
+			if n := kind.excess(stats, limits); n > 0 {
+				if err := g.evictOldest(ctx, group.GroupID, kind, n, limits.Pinned); err != nil {
+					return err
+				}
+				log.Printf("Group %s at its %s limit: evicted the %d oldest", group.GroupID, kind.name, n)
+			}
+		}
+	case model.LimitActionCompact:
+		if err := g.compactGroup(ctx, group.GroupID, stats, limits); err != nil {
+			return err
+		}
+	}
+	if limits.OnLimit != model.LimitActionReject {
+		if stats, err = g.GetGroupStats(ctx, group.GroupID); err != nil {
+			return err
+		}
+	}
+
+	var over []string
+	for _, kind := range limitKinds {
+		if kind.excess(stats, limits) > 0 {
+			over = append(over, fmt.Sprintf("%d of %d %s", kind.count(stats), kind.limit(limits), kind.name))
+		}
+	}
+	if len(over) > 0 {
+		return fmt.Errorf("%w: group %s has %s", ErrGroupLimit, group.GroupID, strings.Join(over, ", "))
+	}
+	return nil
+}
+
+// evictOldest deletes the group's n oldest of kind, other than its user entity
+// and the pinned entities.
+func (g *Graphiti) evictOldest(ctx context.Context, groupID string, kind limitKind, n int, pinned []string) error {
+	uuids, err := g.oldest(ctx, groupID, kind.oldest, n, append([]string{userEntityID(groupID)}, pinned...)...)
+	if err != nil {
+		return err
+	}
+	for _, uuid := range uuids {
+		if err := kind.remove(g, ctx, uuid); err != nil {
+			return fmt.Errorf("failed to evict %s %s: %w", kind.name, uuid, err)
+		}
+	}
+	return nil
+}
+
+// compactGroup shrinks a group without losing current knowledge: duplicate
+// facts are merged, invalidated facts deleted and orphaned entities (past
+// the orphan GC grace period) deleted. At the episode limit, runs of old
+// episodes are replaced with digests as [compaction] allows.
+func (g *Graphiti) compactGroup(ctx context.Context, groupID string, stats *model.GroupStats, limits model.GroupLimits) error {
+	if limits.MaxEpisodes > 0 && stats.Episodes >= limits.MaxEpisodes {
+		if _, err := g.CompactEpisodes(ctx, groupID, false); err != nil {
+			return err
+		}
+	}
+	if _, err := g.DedupeEdges(ctx, groupID, false); err != nil {
+		return err
+	}
+	if stats.InvalidEdges > 0 {
+		// Invalidated facts come first
+		invalid, err := g.oldest(ctx, groupID, driver.GetOldestEdgesQuery, stats.InvalidEdges)
+		if err != nil {
+			return err
+		}
+		for _, uuid := range invalid {
+			if err := deleteByUUID(driver.DeleteEntityEdgeQuery, model.ChangeKindFact)(g, ctx, uuid); err != nil {
+				return fmt.Errorf("failed to delete invalidated fact %s: %w", uuid, err)
+			}
+		}
+	}
+	if _, err := g.CollectOrphans(ctx, groupID, model.OrphanModeDelete, false); err != nil {
+		return err
+	}
+	log.Printf("Group %s at its limits: compacted", groupID)
+	return nil
+}
+
+// oldest returns the UUIDs of the group's n oldest of what query lists,
+// leaving out exclude where the query takes it.
+func (g *Graphiti) oldest(ctx context.Context, groupID, query string, n int, exclude ...string) ([]string, error) {
+	if exclude == nil {
+		exclude = []string{}
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, query, map[string]interface{}{"group_id": groupID, "limit": n, "exclude": exclude})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the oldest of group %s: %w", groupID, err)
+	}
+	var uuids []string
+	for _, rec := range res.Records {
+		if uuid, ok := rec.Get("uuid"); ok {
+			if s, ok := uuid.(string); ok {
+				uuids = append(uuids, s)
+			}
+		}
+	}
+	return uuids, nil
+}
+
+// groupLocks hands out one mutex per group.
+type groupLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the group's mutex and returns its unlock.
+func (l *groupLocks) lock(groupID string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	m, ok := l.locks[groupID]
+	if !ok {
+		m = &sync.Mutex{}
+		l.locks[groupID] = m
+	}
+	l.mu.Unlock()
+	m.Lock()
+	return m.Unlock
+}
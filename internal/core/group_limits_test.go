@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupLimits(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string { return `{"extracted_entities": []}` })
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Extraction:  config.ExtractionPrompts{Nodes: "%s|%s"},
+		GroupLimits: config.GroupLimitsConfig{MaxEpisodes: 2},
+	})
+	ctx := context.Background()
+	for uuid, at := range map[string]string{"ep1": "2024-01-01T00:00:00Z", "ep2": "2024-02-01T00:00:00Z"} {
+		_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, map[string]interface{}{
+			"uuid": uuid, "name": uuid, "group_id": "g1", "content": uuid, "created_at": at,
+		})
+		require.NoError(t, err)
+	}
+	setLimits := func(groupID string, limits model.GroupLimits) {
+		_, err := g.UpdateGroup(ctx, groupID, model.GroupPatch{Settings: &model.GroupSettings{Limits: &limits}})
+		require.NoError(t, err)
+	}
+	episodeUUIDs := func() []string {
+		episodes, err := g.GetEpisodes(ctx, "g1", 10)
+		require.NoError(t, err)
+		var uuids []string
+		for _, ep := range episodes {
+			uuids = append(uuids, ep.UUID)
+		}
+		return uuids
+	}
+
+	// Rejected by default, without a dead letter
+	assert.ErrorIs(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""), ErrGroupLimit)
+	letters, err := g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+	assert.Len(t, episodeUUIDs(), 2)
+
+	setLimits("g1", model.GroupLimits{OnLimit: model.LimitActionEvictOldest})
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""))
+	uuids := episodeUUIDs()
+	assert.Len(t, uuids, 2)
+	assert.NotContains(t, uuids, "ep1")
+	assert.Contains(t, uuids, "ep2")
+
+	// Compaction doesn't drop episodes
+	setLimits("g1", model.GroupLimits{OnLimit: model.LimitActionCompact})
+	assert.ErrorIs(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""), ErrGroupLimit)
+
+	// It deletes invalidated facts
+	setLimits("g2", model.GroupLimits{MaxEdges: 2, OnLimit: model.LimitActionCompact})
+	for _, uuid := range []string{"alice", "acme"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: uuid, Name: uuid, GroupID: "g2", CreatedAt: time.Now().UTC(), Labels: []string{"Entity"}}))
+	}
+	for uuid, invalidAt := range map[string]string{"old": "2024-06-01T00:00:00Z", "current": ""} {
+		seedFact(t, g.Driver, "g2", uuid, "alice", "acme", "WORKS_AT", "Alice works at Acme ("+uuid+")", map[string]interface{}{
+			"created_at": "2024-01-01T00:00:00Z", "invalid_at": invalidAt,
+		})
+	}
+	require.NoError(t, g.AddEpisode(ctx, "g2", "message", "hello", "", ""))
+	_, err = g.GetFact(ctx, "old")
+	assert.ErrorIs(t, err, ErrFactNotFound)
+	_, err = g.GetFact(ctx, "current")
+	assert.NoError(t, err)
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Limits: &model.GroupLimits{OnLimit: "drop"}}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
+
+func TestGroupLimits_EvictionKeepsUserAndPinned(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string { return `{"extracted_entities": []}` })
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s"},
+	})
+	ctx := context.Background()
+	user := userEntityID("g1")
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, uuid := range []string{user, "pinned", "old", "new"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{
+			UUID: uuid, Name: uuid, GroupID: "g1", Labels: []string{"Entity"}, CreatedAt: created.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+	_, err := g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Limits: &model.GroupLimits{
+		MaxEntities: 4, OnLimit: model.LimitActionEvictOldest, Pinned: []string{"pinned"},
+	}}})
+	require.NoError(t, err)
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""))
+	for _, uuid := range []string{user, "pinned", "new"} {
+		_, err := g.GetEntity(ctx, uuid)
+		assert.NoError(t, err, uuid)
+	}
+	_, err = g.GetEntity(ctx, "old")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"log"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/dedupe"
+	"github.com/agenthands/carbon/internal/core/extraction"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/core/summary"
+	"github.com/agenthands/carbon/internal/llm"
+)
+
+// forGroup returns a Graphiti whose LLM pipeline applies the group's prompt and model
+// overrides. It returns g itself when the group has none.
+func (g *Graphiti) forGroup(group *model.GroupNode) *Graphiti {
+	if group == nil {
+		return g
+	}
+	s := group.Settings
+	if s.Model == "" && s.Prompts == (model.PromptOverrides{}) && s.VerifyFacts == nil && len(s.FactLifetimes) == 0 {
+		return g
+	}
+
+	llmClient := g.LLM
+	if s.Model != "" {
+		if o, ok := g.LLM.(llm.ModelOverrider); ok {
+			llmClient = o.WithModel(s.Model)
+		} else {
+			log.Printf("Warning: LLM client %T does not support model overrides; ignoring model %q for group %s", g.LLM, s.Model, group.GroupID)
+		}
+	}
+
+	cfg := *g.Config
+	override := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	override(&cfg.Extraction.Nodes, s.Prompts.ExtractNodes)
+	override(&cfg.Extraction.Edges, s.Prompts.ExtractEdges)
+	override(&cfg.Deduplication.Nodes, s.Prompts.DedupeNodes)
+	override(&cfg.Deduplication.Edges, s.Prompts.DedupeEdges)
+	override(&cfg.Summary.Nodes, s.Prompts.SummarizeNodes)
+	override(&cfg.Summary.Communities, s.Prompts.SummarizeCommunities)
+	override(&cfg.Summary.CommunityName, s.Prompts.CommunityName)
+	override(&cfg.Summary.Consistency, s.Prompts.SummaryConsistency)
+	override(&cfg.Summary.Path, s.Prompts.SummarizePath)
+	override(&cfg.Extraction.Verify, s.Prompts.VerifyFacts)
+	override(&cfg.Summary.Episodes, s.Prompts.DigestEpisodes)
+	override(&cfg.Summary.Profile, s.Prompts.SynthesizeProfile)
+	override(&cfg.Summary.GapQuestions, s.Prompts.GapQuestions)
+	if s.VerifyFacts != nil {
+		cfg.Ingest.VerifyFacts = *s.VerifyFacts
+	}
+	if len(s.FactLifetimes) > 0 {
+		lifetimes := make(map[string]int, len(cfg.Ingest.FactLifetimes)+len(s.FactLifetimes))
+		for relation, hours := range cfg.Ingest.FactLifetimes {
+			lifetimes[relation] = hours
+		}
+		for relation, hours := range s.FactLifetimes {
+			lifetimes[relation] = hours
+		}
+		cfg.Ingest.FactLifetimes = lifetimes
+	}
+
+	scoped := *g
+	scoped.LLM = llmClient
+	scoped.Config = &cfg
+	scoped.Extractor = newExtractor(llmClient, &cfg)
+	scoped.Deduplicator = dedupe.NewDeduplicator(llm.ForOperation(llmClient, llm.OpDeduplication), cfg.Deduplication)
+	scoped.Summarizer = summary.NewSummarizer(llm.ForOperation(llmClient, llm.OpSummary), cfg.Summary)
+	return &scoped
+}
+
+// newExtractor builds the extractor of an engine or group, with prompts kept
+// within the extraction model's context window.
+func newExtractor(llmClient llm.LLMClient, cfg *config.Config) *extraction.Extractor {
+	e := extraction.NewExtractor(llm.ForOperation(llmClient, llm.OpExtraction), cfg.Extraction)
+	e.MaxPromptTokens = llm.PromptBudget(cfg.LLM, llm.OpExtraction)
+	return e
+}
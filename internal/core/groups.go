@@ -0,0 +1,211 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+// ListGroups enumerates every group that has ingested an episode.
+func (g *Graphiti) ListGroups(ctx context.Context) ([]model.GroupSummary, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.ListGroupsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	groups := make([]model.GroupSummary, 0, len(res.Records))
+	for _, rec := range res.Records {
+		var group model.GroupSummary
+		if err := driver.ScanRecord(rec, &group); err != nil {
+			return nil, fmt.Errorf("failed to read group: %w", err)
+		}
+		if err := decodeJSONField(rec, "metadata", &group.Metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata for group %s: %w", group.GroupID, err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// GetGroup returns the group's metadata node, or ErrGroupNotFound.
+func (g *Graphiti) GetGroup(ctx context.Context, groupID string) (*model.GroupNode, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrGroupNotFound
+	}
+	return decodeGroup(res.Records[0])
+}
+
+// UpdateGroup applies patch to the group, creating the group node if needed.
+// Settings with attribute schemas that don't compile, or naming transforms
+// that aren't configured, fail with ErrInvalidGroupSettings.
+func (g *Graphiti) UpdateGroup(ctx context.Context, groupID string, patch model.GroupPatch) (*model.GroupNode, error) {
+	if patch.Settings != nil {
+		if err := validateGroupSettings(*patch.Settings); err != nil {
+			return nil, err
+		}
+		if err := g.validateTransforms(patch.Settings.Transforms); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now().UTC()
+	group, err := g.ensureGroup(ctx, groupID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		group.Name = *patch.Name
+	}
+	if patch.Owner != nil {
+		group.Owner = *patch.Owner
+	}
+	if patch.Settings != nil {
+		group.Settings = *patch.Settings
+	}
+	if len(patch.Metadata) > 0 && group.Metadata == nil {
+		group.Metadata = make(map[string]interface{})
+	}
+	for k, v := range patch.Metadata {
+		if v == nil {
+			delete(group.Metadata, k) // null removes a key
+		} else {
+			group.Metadata[k] = v
+		}
+	}
+	group.UpdatedAt = now
+
+	settingsJSON, err := json.Marshal(group.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode group settings: %w", err)
+	}
+	metadataJSON := []byte("{}")
+	if len(group.Metadata) > 0 {
+		if metadataJSON, err = json.Marshal(group.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to encode group metadata: %w", err)
+		}
+	}
+
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveGroupQuery, map[string]interface{}{
+		"group_id":   groupID,
+		"name":       group.Name,
+		"owner":      group.Owner,
+		"created_at": group.CreatedAt.Format(time.RFC3339),
+		"updated_at": now.Format(time.RFC3339),
+		"settings":   string(settingsJSON),
+		"metadata":   string(metadataJSON),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+	return group, nil
+}
+
+// ensureGroup creates the group node on first use and returns its current state.
+func (g *Graphiti) ensureGroup(ctx context.Context, groupID string, now time.Time) (*model.GroupNode, error) {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.EnsureGroupQuery, map[string]interface{}{
+		"group_id":   groupID,
+		"created_at": now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure group: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return &model.GroupNode{GroupID: groupID, Name: groupID, CreatedAt: now, UpdatedAt: now}, nil
+	}
+	return decodeGroup(res.Records[0])
+}
+
+func decodeGroup(rec *neo4j.Record) (*model.GroupNode, error) {
+	var group model.GroupNode
+	if err := driver.ScanRecord(rec, &group); err != nil {
+		return nil, fmt.Errorf("failed to read group: %w", err)
+	}
+	if err := decodeJSONField(rec, "settings", &group.Settings); err != nil {
+		return nil, fmt.Errorf("invalid settings for group %s: %w", group.GroupID, err)
+	}
+	if err := decodeJSONField(rec, "metadata", &group.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata for group %s: %w", group.GroupID, err)
+	}
+	return &group, nil
+}
+
+// decodeJSONField unmarshals a property stored as a JSON string, like entity attributes.
+func decodeJSONField(rec *neo4j.Record, key string, dest interface{}) error {
+	raw, ok := rec.Get(key)
+	if !ok {
+		return nil
+	}
+	s, ok := raw.(string)
+	if !ok || s == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), dest)
+}
+
+// DeleteGroup removes every node of the group, including its settings, jobs
+// and reports, and returns how many were deleted.
+func (g *Graphiti) DeleteGroup(ctx context.Context, groupID string) (int, error) {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.DeleteGroupQuery, map[string]interface{}{"group_id": groupID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete group: %w", err)
+	}
+	g.invalidateSearchCache(ctx, groupID)
+	if err := g.deleteGroupContent(ctx, groupID); err != nil {
+		return 0, err
+	}
+	if err := g.recordChange(ctx, groupID, model.ChangeKindGroup, groupID, model.ChangeOpDelete); err != nil {
+		return 0, err
+	}
+	return deletedCount(res)
+}
+
+// ClearGraph removes all groups. The schema version is kept.
+func (g *Graphiti) ClearGraph(ctx context.Context) (int, error) {
+	groups, err := g.ListGroups(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res, err := g.Driver.ExecuteQuery(ctx, driver.ClearGraphQuery, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear graph: %w", err)
+	}
+	for _, group := range groups {
+		g.invalidateSearchCache(ctx, group.GroupID)
+	}
+	if err := g.deleteGroupContent(ctx, ""); err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		if err := g.recordChange(ctx, group.GroupID, model.ChangeKindGroup, group.GroupID, model.ChangeOpDelete); err != nil {
+			return 0, err
+		}
+	}
+	return deletedCount(res)
+}
+
+func deletedCount(res neo4j.EagerResult) (int, error) {
+	if len(res.Records) == 0 {
+		return 0, nil
+	}
+	var row struct {
+		Deleted int `db:"deleted"`
+	}
+	if err := driver.ScanRecord(res.Records[0], &row); err != nil {
+		return 0, fmt.Errorf("failed to read deleted count: %w", err)
+	}
+	return row.Deleted, nil
+}
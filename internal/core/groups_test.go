@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGroups(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+
+	for _, groupID := range []string{"beta", "alpha"} {
+		_, err := g.ensureGroup(ctx, groupID, mustTime("2024-01-01T00:00:00Z"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "message", "beta", "hi", mustTime("2024-01-02T00:00:00Z")))
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep2", "message", "alpha", "hi", mustTime("2024-01-01T00:00:00Z")))
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep3", "message", "alpha", "hi", mustTime("2024-01-05T00:00:00Z")))
+
+	groups, err := g.ListGroups(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "alpha", groups[0].GroupID)
+	assert.Equal(t, 2, groups[0].Episodes)
+	assert.Equal(t, mustTime("2024-01-01T00:00:00Z"), groups[0].CreatedAt)
+	assert.Equal(t, mustTime("2024-01-05T00:00:00Z"), groups[0].LastEpisodeAt)
+	assert.Equal(t, "beta", groups[1].GroupID)
+	assert.Equal(t, 1, groups[1].Episodes)
+}
+
+func TestGroupLifecycle(t *testing.T) {
+	ctx := context.Background()
+	mockLLM := &MockLLM{ResponseQueue: []string{`{"extracted_entities": []}`}}
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), mockLLM, nil, nil, cfg)
+
+	_, err := g.GetGroup(ctx, "g1")
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+
+	// First ingest creates the group lazily
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "hello", "", ""))
+	group, err := g.GetGroup(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, "g1", group.Name)
+
+	name := "Acme"
+	group, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{
+		Name:     &name,
+		Settings: &model.GroupSettings{Ontology: "Customer, Product", RetentionDays: 30},
+		Metadata: map[string]interface{}{"tier": "gold"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", group.Name)
+
+	group, err = g.GetGroup(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", group.Name)
+	assert.Equal(t, 30, group.Settings.RetentionDays)
+	assert.Equal(t, "gold", group.Metadata["tier"])
+
+	// The pipeline picks up the ontology override when no schema is given
+	var prompt string
+	g.Extractor.LLM = llmFunc(func(p string) string { prompt = p; return `{"extracted_entities": []}` })
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "hello again", "", ""))
+	assert.Contains(t, prompt, "Customer, Product")
+}
+
+type overridableLLM struct {
+	model   string
+	prompts *[]string
+}
+
+func (o *overridableLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	*o.prompts = append(*o.prompts, o.model+":"+prompt)
+	return `{"extracted_entities": []}`, nil
+}
+
+func (o *overridableLLM) WithModel(model string) llm.LLMClient {
+	return &overridableLLM{model: model, prompts: o.prompts}
+}
+
+func TestGroupPromptAndModelOverrides(t *testing.T) {
+	ctx := context.Background()
+	var prompts []string
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "default %s %s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), &overridableLLM{model: "base", prompts: &prompts}, nil, nil, cfg)
+
+	_, err := g.UpdateGroup(ctx, "tenant", model.GroupPatch{Settings: &model.GroupSettings{
+		Model:   "tuned",
+		Prompts: model.PromptOverrides{ExtractNodes: "custom %s %s"},
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, g.AddEpisode(ctx, "tenant", "message", "hi", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "other", "message", "hi", "", ""))
+
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[0], "tuned:custom")
+	assert.Contains(t, prompts[1], "base:default")
+	// The shared config is not mutated by the override
+	assert.Equal(t, "default %s %s", g.Config.Extraction.Nodes)
+}
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// PreExtractHook rewrites an episode's text before entities and facts are
+// extracted from it. The episode itself is stored as sent.
+type PreExtractHook interface {
+	PreExtract(ctx context.Context, groupID, content string) (string, error)
+}
+
+// PostExtractHook sees what the LLM extracted from an episode before it is
+// resolved against the graph, and returns what to keep.
+type PostExtractHook interface {
+	PostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error)
+	PostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error)
+}
+
+// PreSaveHook sees every entity saved and every new fact before it is
+// written, and may change it.
+type PreSaveHook interface {
+	PreSaveEntity(ctx context.Context, node *model.EntityNode) error
+	PreSaveFact(ctx context.Context, fact *model.EntityEdge) error
+}
+
+// PostSearchHook sees the results of every fact search and returns the ones
+// to answer with. Results may be shared with the search cache, so a hook
+// returns a new slice rather than changing them in place.
+type PostSearchHook interface {
+	PostSearch(ctx context.Context, groupID, query string, results []model.EntityEdge) ([]model.EntityEdge, error)
+}
+
+// GroupChangeHook is told after a write changed a group's entities, facts,
+// episodes or communities. It runs on the writing goroutine, so it should
+// return quickly.
+type GroupChangeHook interface {
+	GroupChanged(ctx context.Context, groupID string)
+}
+
+// hooks holds the hooks registered on a Graphiti, run in registration order.
+// An error from a hook fails what it was called for.
+type hooks struct {
+	mu          sync.RWMutex
+	preExtract  []PreExtractHook
+	postExtract []PostExtractHook
+	preSave     []PreSaveHook
+	postSearch  []PostSearchHook
+	groupChange []GroupChangeHook
+}
+
+// RegisterHook adds hook to every pipeline stage whose hook interface it
+// implements. It fails for values implementing none of them. Hooks are meant
+// to be registered while setting up, before the Graphiti serves requests.
+func (g *Graphiti) RegisterHook(hook interface{}) error {
+	if g.hooks == nil {
+		g.hooks = &hooks{}
+	}
+	h := g.hooks
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	registered := false
+	if hk, ok := hook.(PreExtractHook); ok {
+		h.preExtract = append(h.preExtract, hk)
+		registered = true
+	}
+	if hk, ok := hook.(PostExtractHook); ok {
+		h.postExtract = append(h.postExtract, hk)
+		registered = true
+	}
+	if hk, ok := hook.(PreSaveHook); ok {
+		h.preSave = append(h.preSave, hk)
+		registered = true
+	}
+	if hk, ok := hook.(PostSearchHook); ok {
+		h.postSearch = append(h.postSearch, hk)
+		registered = true
+	}
+	if hk, ok := hook.(GroupChangeHook); ok {
+		h.groupChange = append(h.groupChange, hk)
+		registered = true
+	}
+	if !registered {
+		return fmt.Errorf("%T implements no hook interface", hook)
+	}
+	return nil
+}
+
+func (h *hooks) runPreExtract(ctx context.Context, groupID, content string) (string, error) {
+	if h == nil {
+		return content, nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.preExtract {
+		var err error
+		if content, err = hk.PreExtract(ctx, groupID, content); err != nil {
+			return "", fmt.Errorf("pre-extract hook: %w", err)
+		}
+	}
+	return content, nil
+}
+
+func (h *hooks) runPostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error) {
+	if h == nil {
+		return entities, nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.postExtract {
+		var err error
+		if entities, err = hk.PostExtractEntities(ctx, groupID, entities); err != nil {
+			return nil, fmt.Errorf("post-extract hook: %w", err)
+		}
+	}
+	return entities, nil
+}
+
+func (h *hooks) runPostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error) {
+	if h == nil {
+		return facts, nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.postExtract {
+		var err error
+		if facts, err = hk.PostExtractFacts(ctx, groupID, facts); err != nil {
+			return nil, fmt.Errorf("post-extract hook: %w", err)
+		}
+	}
+	return facts, nil
+}
+
+func (h *hooks) runPreSaveEntity(ctx context.Context, node *model.EntityNode) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.preSave {
+		if err := hk.PreSaveEntity(ctx, node); err != nil {
+			return fmt.Errorf("pre-save hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hooks) runPreSaveFact(ctx context.Context, fact *model.EntityEdge) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.preSave {
+		if err := hk.PreSaveFact(ctx, fact); err != nil {
+			return fmt.Errorf("pre-save hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *hooks) runPostSearch(ctx context.Context, groupID, query string, results []model.EntityEdge) ([]model.EntityEdge, error) {
+	if h == nil {
+		return results, nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.postSearch {
+		var err error
+		if results, err = hk.PostSearch(ctx, groupID, query, results); err != nil {
+			return nil, fmt.Errorf("post-search hook: %w", err)
+		}
+	}
+	return results, nil
+}
+
+func (h *hooks) runGroupChanged(ctx context.Context, groupID string) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, hk := range h.groupChange {
+		hk.GroupChanged(ctx, groupID)
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditHook implements every hook interface.
+type auditHook struct {
+	searched []string
+	changed  []string
+}
+
+func (h *auditHook) PreExtract(ctx context.Context, groupID, content string) (string, error) {
+	return strings.ReplaceAll(content, "Bob", "Robert"), nil
+}
+
+func (h *auditHook) PostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error) {
+	var kept []model.ExtractedEntity
+	for _, e := range entities {
+		if e.Name != "Spam" {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+func (h *auditHook) PostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error) {
+	var kept []model.ExtractedEdge
+	for _, f := range facts {
+		if f.RelationType != "HATES" {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+func (h *auditHook) PreSaveEntity(ctx context.Context, node *model.EntityNode) error {
+	if node.Attributes == nil {
+		node.Attributes = map[string]interface{}{}
+	}
+	node.Attributes["audited"] = true
+	return nil
+}
+
+func (h *auditHook) PreSaveFact(ctx context.Context, fact *model.EntityEdge) error {
+	fact.Fact = "[checked] " + fact.Fact
+	return nil
+}
+
+func (h *auditHook) PostSearch(ctx context.Context, groupID, query string, results []model.EntityEdge) ([]model.EntityEdge, error) {
+	h.searched = append(h.searched, query)
+	return nil, nil
+}
+
+func (h *auditHook) GroupChanged(ctx context.Context, groupID string) {
+	h.changed = append(h.changed, groupID)
+}
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	nodeLine := regexp.MustCompile(`UUID: (\S+), Name: (\w+)`)
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "edges"):
+			uuids := map[string]string{}
+			for _, m := range nodeLine.FindAllStringSubmatch(prompt, -1) {
+				uuids[m[2]] = m[1]
+			}
+			return `{"extracted_edges": [
+				{"source_node_uuid": "` + uuids["Alice"] + `", "target_node_uuid": "` + uuids["Robert"] + `", "relation_type": "KNOWS", "fact": "Alice knows Robert"},
+				{"source_node_uuid": "` + uuids["Alice"] + `", "target_node_uuid": "` + uuids["Robert"] + `", "relation_type": "HATES", "fact": "Alice hates Robert"}
+			]}`
+		case strings.HasPrefix(prompt, "summarize"):
+			return `{"summary": "s"}`
+		default:
+			assert.Contains(t, prompt, "Robert", "entities are extracted from the rewritten text")
+			return `{"extracted_entities": [{"name": "Alice"}, {"name": "Robert"}, {"name": "Spam"}]}`
+		}
+	}), nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+		Summary:    config.SummaryPrompts{Nodes: "summarize %s %s"},
+	})
+	hook := &auditHook{}
+	require.NoError(t, g.RegisterHook(hook))
+	assert.Error(t, g.RegisterHook("not a hook"))
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice knows Bob", "", ""))
+	assert.Equal(t, []string{"g1"}, hook.changed)
+
+	episodes, err := g.GetEpisodes(ctx, "g1", 10)
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, "Alice knows Bob", episodes[0].Content, "the episode is stored as sent")
+
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+		node, err := g.GetEntity(ctx, n.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, true, node.Attributes["audited"])
+	}
+	assert.ElementsMatch(t, []string{"Alice", "Robert"}, names)
+
+	facts, err := g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "[checked] Alice knows Robert", facts[0].Fact)
+
+	results, err := g.Search(ctx, "g1", "knows")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, []string{"knows"}, hook.searched)
+}
+
+// failingHook fails the stage named by stage.
+type failingHook struct{ stage string }
+
+func (h failingHook) fail(stage string) error {
+	if h.stage == stage {
+		return errors.New(stage + " refused")
+	}
+	return nil
+}
+
+func (h failingHook) PostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error) {
+	return entities, h.fail("entities")
+}
+
+func (h failingHook) PostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error) {
+	return facts, h.fail("facts")
+}
+
+func (h failingHook) PreSaveEntity(ctx context.Context, node *model.EntityNode) error {
+	return h.fail("save_entity")
+}
+
+func (h failingHook) PreSaveFact(ctx context.Context, fact *model.EntityEdge) error {
+	return h.fail("save_fact")
+}
+
+func TestHooks_Errors(t *testing.T) {
+	for _, stage := range []string{"entities", "facts", "save_entity", "save_fact"} {
+		t.Run(stage, func(t *testing.T) {
+			ctx := context.Background()
+			nodeLine := regexp.MustCompile(`UUID: (\S+), Name: (\w+)`)
+			g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+				switch {
+				case strings.HasPrefix(prompt, "edges"):
+					uuids := map[string]string{}
+					for _, m := range nodeLine.FindAllStringSubmatch(prompt, -1) {
+						uuids[m[2]] = m[1]
+					}
+					return `{"extracted_edges": [{"source_node_uuid": "` + uuids["Alice"] + `", "target_node_uuid": "` + uuids["Bob"] + `", "relation_type": "KNOWS", "fact": "Alice knows Bob"}]}`
+				case strings.HasPrefix(prompt, "summarize"):
+					return `{"summary": "s"}`
+				default:
+					return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`
+				}
+			}), nil, nil, &config.Config{
+				Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+				Summary:    config.SummaryPrompts{Nodes: "summarize %s %s"},
+			})
+			require.NoError(t, g.RegisterHook(failingHook{stage: stage}))
+
+			err := g.AddEpisode(ctx, "g1", "message", "Alice knows Bob", "", "")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), stage+" refused")
+			letters, err := g.ListDeadLetters(ctx, "g1")
+			require.NoError(t, err)
+			assert.Len(t, letters, 1)
+		})
+	}
+}
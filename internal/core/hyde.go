@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"log"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// useHyDE reports whether a search embeds a hypothetical answer instead of
+// its query: as filter asks, or else as [search] hyde says. It takes an
+// embedder and the [extraction] hypothetical prompt.
+func (g *Graphiti) useHyDE(filter *model.SearchFilter) bool {
+	if g.Embedder == nil || g.Config == nil || g.Config.Extraction.Hypothetical == "" {
+		return false
+	}
+	if filter != nil && filter.HyDE != nil {
+		return *filter.HyDE
+	}
+	return g.Config.Search.HyDE
+}
+
+// searchText returns the text whose embedding a search looks for: the query,
+// or under HyDE a passage the LLM writes to answer it. If writing the passage
+// fails the query is used.
+func (g *Graphiti) searchText(ctx context.Context, query string, filter *model.SearchFilter, trace *model.SearchTrace) string {
+	if !g.useHyDE(filter) {
+		return query
+	}
+	passage, err := g.Extractor.HypotheticalAnswer(ctx, query)
+	if err != nil || passage == "" {
+		log.Printf("HyDE failed, searching by the query: %v", err)
+		return query
+	}
+	if trace != nil {
+		trace.Hypothetical = passage
+	}
+	return passage
+}
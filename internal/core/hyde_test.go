@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHyDE(t *testing.T) {
+	ctx := context.Background()
+	embedder := mapEmbedder{"what does Alice drink?": {0, 1}, "Alice drinks tea every morning.": {1, 0}}
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Hypothetical: "hyde %s"}}
+	mockLLM := &MockLLM{Response: `{"passage": "Alice drinks tea every morning."}`}
+	g := NewGraphiti(driver.NewMemoryDriver(), mockLLM, embedder, nil, cfg)
+	seedEntities(t, g.Driver, "g1", "alice", "tea")
+	seedFact(t, g.Driver, "g1", "f1", "alice", "tea", "LIKES", "Alice likes tea", map[string]interface{}{
+		"created_at": "2024-01-01T00:00:00Z", "fact_embedding": []float32{1, 0}, "fact_embedding_model": embeddingTag(g.EmbeddingModel, []float32{1, 0}),
+	})
+	minScore, on, off := 0.9, true, false
+	search := func(hyde *bool) ([]model.EntityEdge, *model.SearchTrace) {
+		edges, trace, err := g.SearchDebug(ctx, "g1", "what does Alice drink?", &model.SearchFilter{MinScore: &minScore, HyDE: hyde})
+		require.NoError(t, err)
+		return edges, trace
+	}
+
+	// The query itself is too far from the fact
+	edges, trace := search(nil)
+	assert.Empty(t, edges)
+	assert.Empty(t, trace.Hypothetical)
+
+	// A hypothetical answer is close to it, when the request or the config asks for one
+	edges, trace = search(&on)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "Alice drinks tea every morning.", trace.Hypothetical)
+	g.Config.Search.HyDE = true
+	edges, _ = search(nil)
+	assert.Len(t, edges, 1)
+	edges, _ = search(&off)
+	assert.Empty(t, edges)
+
+	// An unusable answer falls back to the query
+	mockLLM.Response = "not json"
+	edges, trace = search(nil)
+	assert.Empty(t, edges)
+	assert.Empty(t, trace.Hypothetical)
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrJobNotFound = errors.New("ingest job not found")
+
+// ingestJobBatchSize is how many episodes run between checkpoints, and are
+// stored per IngestJobBatch node.
+const ingestJobBatchSize = 50
+
+// CreateIngestJob persists a bulk load as a pending job. Run it with RunIngestJob.
+// The episodes are stored in batches before the job, so a listed job always
+// has all of them.
+func (g *Graphiti) CreateIngestJob(ctx context.Context, groupID string, episodes []model.EpisodeData) (*model.IngestJob, error) {
+	now := time.Now().UTC()
+	job := &model.IngestJob{
+		UUID:      g.UUIDGenerator(),
+		GroupID:   groupID,
+		Status:    model.JobStatusPending,
+		Total:     len(episodes),
+		CreatedAt: now,
+		UpdatedAt: now,
+		EntityMap: make(map[string]string),
+	}
+	for start := 0; start < len(episodes); start += ingestJobBatchSize {
+		batch := episodes[start:min(start+ingestJobBatchSize, len(episodes))]
+		if err := g.saveIngestJobBatch(ctx, job, start/ingestJobBatchSize, batch); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.saveIngestJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetIngestJob returns the job's checkpointed state, or ErrJobNotFound.
+func (g *Graphiti) GetIngestJob(ctx context.Context, jobID string) (*model.IngestJob, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetIngestJobQuery, map[string]interface{}{
+		"uuid": jobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ingest job: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	rec := res.Records[0]
+	var job model.IngestJob
+	if err := driver.ScanRecord(rec, &job); err != nil {
+		return nil, fmt.Errorf("failed to read ingest job: %w", err)
+	}
+	if err := decodeJSONField(rec, "failed", &job.Failed); err != nil {
+		return nil, fmt.Errorf("invalid failed list for job %s: %w", jobID, err)
+	}
+	if err := decodeJSONField(rec, "entity_map", &job.EntityMap); err != nil {
+		return nil, fmt.Errorf("invalid entity map for job %s: %w", jobID, err)
+	}
+	if job.EntityMap == nil {
+		job.EntityMap = make(map[string]string)
+	}
+	return &job, nil
+}
+
+// RunIngestJob processes a job from its last checkpoint, saving progress after
+// every batch. Episodes that fail are recorded and skipped. If ctx ends
+// mid-batch the job stays running and the interrupted batch is re-run on
+// resume, so its episodes are ingested at least once.
+func (g *Graphiti) RunIngestJob(ctx context.Context, jobID string) (*model.IngestJob, error) {
+	job, err := g.GetIngestJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == model.JobStatusCompleted || job.Status == model.JobStatusFailed {
+		return job, nil
+	}
+
+	job.Status = model.JobStatusRunning
+	for job.Processed < job.Total {
+		episodes, err := g.getIngestJobBatch(ctx, job, job.Processed/ingestJobBatchSize)
+		if err != nil {
+			return job, err
+		}
+		end := job.Processed + len(episodes)
+
+		epErrs, err := g.bulkAdd(ctx, job.GroupID, episodes, true, job.EntityMap)
+		if ctx.Err() != nil {
+			return job, ctx.Err()
+		}
+		if err != nil {
+			job.Status = model.JobStatusFailed
+			job.Error = err.Error()
+			if saveErr := g.saveIngestJob(ctx, job); saveErr != nil {
+				log.Printf("Failed to checkpoint ingest job %s: %v", job.UUID, saveErr)
+			}
+			return job, err
+		}
+		for i, epErr := range epErrs {
+			if epErr != nil {
+				job.Failed = append(job.Failed, job.Processed+i)
+			}
+		}
+		job.Processed = end
+		if err := g.saveIngestJob(ctx, job); err != nil {
+			return job, err
+		}
+	}
+
+	job.Status = model.JobStatusCompleted
+	if err := g.saveIngestJob(ctx, job); err != nil {
+		return job, err
+	}
+	return job, nil
+}
+
+// ResumeIngestJobs runs every pending or running job to completion, oldest
+// first, and returns the jobs it completed. A job that can't be resumed is
+// logged and marked failed, and the others still run. Call it on startup to
+// pick up loads interrupted by a crash.
+func (g *Graphiti) ResumeIngestJobs(ctx context.Context) ([]string, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.ListIngestJobsByStatusQuery, map[string]interface{}{
+		"statuses": []string{model.JobStatusPending, model.JobStatusRunning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingest jobs: %w", err)
+	}
+
+	var resumed []string
+	for _, rec := range res.Records {
+		id, _ := rec.Get("uuid")
+		jobID, _ := id.(string)
+		_, err := g.RunIngestJob(ctx, jobID)
+		if ctx.Err() != nil {
+			return resumed, ctx.Err() // Interrupted again; left to the next start
+		}
+		if err != nil {
+			log.Printf("Failed to resume ingest job %s: %v", jobID, err)
+			g.failIngestJob(ctx, jobID, err)
+			continue
+		}
+		resumed = append(resumed, jobID)
+	}
+	return resumed, nil
+}
+
+// failIngestJob marks a job failed with err, keeping its progress.
+func (g *Graphiti) failIngestJob(ctx context.Context, jobID string, err error) {
+	if _, saveErr := g.Driver.ExecuteQuery(ctx, driver.FailIngestJobQuery, map[string]interface{}{
+		"uuid":       jobID,
+		"status":     model.JobStatusFailed,
+		"error":      err.Error(),
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+	}); saveErr != nil {
+		log.Printf("Failed to mark ingest job %s failed: %v", jobID, saveErr)
+	}
+}
+
+// ingestJobBatchID names batch seq of job, holding its episodes from
+// seq*ingestJobBatchSize on.
+func ingestJobBatchID(job *model.IngestJob, seq int) string {
+	return fmt.Sprintf("%s/%d", job.UUID, seq)
+}
+
+func (g *Graphiti) saveIngestJobBatch(ctx context.Context, job *model.IngestJob, seq int, episodes []model.EpisodeData) error {
+	episodesJSON, err := json.Marshal(episodes)
+	if err != nil {
+		return fmt.Errorf("failed to encode job episodes: %w", err)
+	}
+	payload, err := g.encryptPayload(string(episodesJSON))
+	if err != nil {
+		return err
+	}
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveIngestJobBatchQuery, map[string]interface{}{
+		"uuid":     ingestJobBatchID(job, seq),
+		"job_uuid": job.UUID,
+		"group_id": job.GroupID,
+		"seq":      seq,
+		"episodes": payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save ingest job batch: %w", err)
+	}
+	return nil
+}
+
+// getIngestJobBatch returns the episodes of batch seq of job.
+func (g *Graphiti) getIngestJobBatch(ctx context.Context, job *model.IngestJob, seq int) ([]model.EpisodeData, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetIngestJobBatchQuery, map[string]interface{}{
+		"uuid": ingestJobBatchID(job, seq),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes of job %s: %w", job.UUID, err)
+	}
+	if len(res.Records) == 0 {
+		return nil, fmt.Errorf("missing batch %d of job %s", seq, job.UUID)
+	}
+	raw, _ := res.Records[0].Get("episodes")
+	payload, _ := raw.(string)
+	if payload, err = g.decryptPayload(payload); err != nil {
+		return nil, fmt.Errorf("failed to read episodes of job %s: %w", job.UUID, err)
+	}
+	var episodes []model.EpisodeData
+	if err := json.Unmarshal([]byte(payload), &episodes); err != nil {
+		return nil, fmt.Errorf("invalid episodes for job %s: %w", job.UUID, err)
+	}
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("empty batch %d of job %s", seq, job.UUID)
+	}
+	return episodes, nil
+}
+
+// saveIngestJob checkpoints job's progress. Its episodes are saved once, by
+// saveIngestJobBatch.
+func (g *Graphiti) saveIngestJob(ctx context.Context, job *model.IngestJob) error {
+	entityMapJSON, err := json.Marshal(job.EntityMap)
+	if err != nil {
+		return fmt.Errorf("failed to encode job entity map: %w", err)
+	}
+	failedJSON, err := json.Marshal(job.Failed)
+	if err != nil {
+		return fmt.Errorf("failed to encode job failures: %w", err)
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveIngestJobQuery, map[string]interface{}{
+		"uuid":       job.UUID,
+		"group_id":   job.GroupID,
+		"status":     job.Status,
+		"total":      job.Total,
+		"processed":  job.Processed,
+		"failed":     string(failedJSON),
+		"entity_map": string(entityMapJSON),
+		"error":      job.Error,
+		"created_at": job.CreatedAt.Format(time.RFC3339),
+		"updated_at": job.UpdatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save ingest job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIngestJob_CheckpointsAcrossBatches(t *testing.T) {
+	ctx := context.Background()
+	llmClient := llmFunc(func(prompt string) string {
+		return `{"extracted_entities": [{"name": "Alice", "entity_type_id": 0}]}`
+	})
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	episodes := make([]model.EpisodeData, ingestJobBatchSize+5)
+	for i := range episodes {
+		episodes[i] = model.EpisodeData{Content: fmt.Sprintf("Alice message %d", i)}
+	}
+	job, err := g.CreateIngestJob(ctx, "g1", episodes)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobStatusPending, job.Status)
+
+	// Episodes are stored a batch per checkpoint, not on the job
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetIngestJobQuery, map[string]interface{}{"uuid": job.UUID})
+	require.NoError(t, err)
+	assert.NotContains(t, res.Records[0].Keys, "episodes")
+	last, err := g.getIngestJobBatch(ctx, job, 1)
+	require.NoError(t, err)
+	assert.Equal(t, episodes[ingestJobBatchSize:], last)
+
+	job, err = g.RunIngestJob(ctx, job.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobStatusCompleted, job.Status)
+
+	stored, err := g.GetIngestJob(ctx, job.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, len(episodes), stored.Processed)
+	assert.Empty(t, stored.Failed)
+	require.Contains(t, stored.EntityMap, "Alice")
+
+	// The second batch reused the first batch's node instead of creating another Alice
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, stored.EntityMap["Alice"], nodes[0].UUID)
+}
+
+func TestResumeIngestJobs_AfterRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "graph.db")
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+
+	d, err := driver.NewSQLiteDriver(path)
+	require.NoError(t, err)
+	g := NewGraphiti(d, &flakyLLM{marker: "bad"}, nil, nil, cfg)
+	job, err := g.CreateIngestJob(ctx, "g1", []model.EpisodeData{{Content: "one"}, {Content: "bad two"}, {Content: "three"}})
+	require.NoError(t, err)
+	require.NoError(t, d.Close(ctx)) // Crash before the job ran
+
+	d, err = driver.NewSQLiteDriver(path)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+	g = NewGraphiti(d, &flakyLLM{marker: "bad"}, nil, nil, cfg)
+
+	resumed, err := g.ResumeIngestJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{job.UUID}, resumed)
+
+	stored, err := g.GetIngestJob(ctx, job.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobStatusCompleted, stored.Status)
+	assert.Equal(t, 3, stored.Processed)
+	assert.Equal(t, []int{1}, stored.Failed)
+
+	_, err = g.GetIngestJob(ctx, "missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestResumeIngestJobs_SkipsBrokenJob(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), &flakyLLM{marker: "bad"}, nil, nil, cfg)
+
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveIngestJobQuery, map[string]interface{}{
+		"uuid": "broken", "group_id": "g1", "status": model.JobStatusRunning, "total": 1, "processed": 0,
+		"failed": "[]", "entity_map": "{}", "error": "",
+		"created_at": "2000-01-01T00:00:00Z", "updated_at": "2000-01-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveIngestJobBatchQuery, map[string]interface{}{
+		"uuid": "broken/0", "job_uuid": "broken", "group_id": "g1", "seq": 0, "episodes": "not json",
+	})
+	require.NoError(t, err)
+	job, err := g.CreateIngestJob(ctx, "g1", []model.EpisodeData{{Content: "one"}})
+	require.NoError(t, err)
+
+	resumed, err := g.ResumeIngestJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{job.UUID}, resumed)
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetIngestJobQuery, map[string]interface{}{"uuid": "broken"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	status, _ := res.Records[0].Get("status")
+	jobErr, _ := res.Records[0].Get("error")
+	assert.Equal(t, model.JobStatusFailed, status)
+	assert.Contains(t, jobErr, "invalid episodes")
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+const DefaultGapEntities = 10
+
+// FindKnowledgeGaps reports what the group's limit most salient entities
+// with gaps lack, and asks the [summary] gap_questions prompt for questions
+// an agent could ask the user to fill them. What an entity should have comes
+// from the group settings for its entity type (one of its labels): the
+// checklist of that name, and the properties of its attribute schema.
+// Entities of types with neither are not checked.
+func (g *Graphiti) FindKnowledgeGaps(ctx context.Context, groupID string, limit int) (*model.KnowledgeGaps, error) {
+	if limit <= 0 {
+		limit = DefaultGapEntities
+	}
+	report := &model.KnowledgeGaps{GroupID: groupID, Entities: []model.EntityGaps{}}
+	group, err := g.GetGroup(ctx, groupID)
+	if errors.Is(err, ErrGroupNotFound) {
+		return report, nil // No settings, so nothing is expected
+	}
+	if err != nil {
+		return nil, err
+	}
+	expected := expectedFacts(group.Settings)
+	if len(expected) == 0 {
+		return report, nil
+	}
+
+	top, err := g.TopEntities(ctx, groupID, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range top {
+		typ, checklist, ok := entityChecklist(e.Labels, expected)
+		if !ok {
+			continue
+		}
+		gaps, err := g.FindFactGaps(ctx, e.UUID, "", checklist)
+		if errors.Is(err, ErrEntityNotFound) {
+			continue // Deleted since ranking
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(gaps.MissingAttributes) == 0 && len(gaps.MissingRelations) == 0 {
+			continue
+		}
+		report.Entities = append(report.Entities, model.EntityGaps{FactGaps: *gaps, Type: typ, Questions: []string{}})
+		if len(report.Entities) == limit {
+			break
+		}
+	}
+	if len(report.Entities) == 0 {
+		return report, nil
+	}
+
+	questions, err := g.forGroup(group).Summarizer.SuggestQuestions(ctx, report.Entities)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(report.Entities))
+	for i, e := range report.Entities {
+		index[e.UUID] = i
+	}
+	for _, q := range questions {
+		if i, ok := index[q.UUID]; ok && q.Question != "" {
+			report.Entities[i].Questions = append(report.Entities[i].Questions, q.Question)
+		}
+	}
+	return report, nil
+}
+
+// expectedFacts merges, per entity type, the group's checklist of that name
+// with the properties of its attribute schema.
+func expectedFacts(settings model.GroupSettings) map[string]model.FactChecklist {
+	expected := make(map[string]model.FactChecklist, len(settings.Checklists))
+	for typ, checklist := range settings.Checklists {
+		expected[typ] = checklist
+	}
+	for typ, schema := range settings.AttributeSchemas {
+		props, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		checklist := expected[typ]
+		checklist.Attributes = appendUnique(append([]string(nil), checklist.Attributes...), keys...)
+		expected[typ] = checklist
+	}
+	return expected
+}
+
+// entityChecklist returns the first of labels naming an entity type with
+// expected facts, matched like relation types, and what it expects.
+func entityChecklist(labels []string, expected map[string]model.FactChecklist) (string, model.FactChecklist, bool) {
+	types := make([]string, 0, len(expected))
+	for typ := range expected {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, l := range labels {
+		for _, typ := range types {
+			if normalizeRelation(l) == normalizeRelation(typ) {
+				return typ, expected[typ], true
+			}
+		}
+	}
+	return "", model.FactChecklist{}, false
+}
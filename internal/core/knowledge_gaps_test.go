@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindKnowledgeGaps(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{
+		Summary: config.SummaryPrompts{GapQuestions: "%s"},
+	})
+	var prompts []string
+	g.Summarizer.LLM = llmFunc(func(prompt string) string {
+		prompts = append(prompts, prompt)
+		return `{"questions": [{"uuid": "alice", "question": "Where does Alice work?"}, {"uuid": "nope", "question": "?"}]}`
+	})
+
+	report, err := g.FindKnowledgeGaps(ctx, "g1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, report.Entities, "no group settings, nothing expected")
+
+	now := time.Now().UTC()
+	for _, n := range []model.EntityNode{
+		{UUID: "alice", Name: "Alice", Labels: []string{"Entity", "Person"}, Attributes: map[string]interface{}{"email": "a@example.com"}},
+		{UUID: "bob", Name: "Bob", Labels: []string{"Entity", "Person"}, Attributes: map[string]interface{}{"email": "b@example.com", "age": 40.0}},
+		{UUID: "acme", Name: "Acme", Labels: []string{"Entity", "Organization"}},
+	} {
+		n.GroupID, n.CreatedAt = "g1", now
+		require.NoError(t, g.saveEntity(ctx, n))
+	}
+	seedFact(t, g.Driver, "g1", "e1", "bob", "acme", "WORKS_AT", "Bob works at Acme", nil)
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{
+		Checklists: map[string]model.FactChecklist{"person": {Relations: []string{"WORKS_AT"}}},
+		AttributeSchemas: map[string]map[string]interface{}{
+			"person": {"type": "object", "properties": map[string]interface{}{"email": map[string]interface{}{"type": "string"}, "age": map[string]interface{}{"type": "number"}}},
+		},
+	}})
+	require.NoError(t, err)
+
+	report, err = g.FindKnowledgeGaps(ctx, "g1", 0)
+	require.NoError(t, err)
+	require.Len(t, report.Entities, 1, "bob has everything and organizations expect nothing")
+	alice := report.Entities[0]
+	assert.Equal(t, "alice", alice.UUID)
+	assert.Equal(t, "person", alice.Type)
+	assert.Equal(t, []string{"age"}, alice.MissingAttributes)
+	assert.Equal(t, []string{"WORKS_AT"}, alice.MissingRelations)
+	assert.Equal(t, []string{"Where does Alice work?"}, alice.Questions)
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "Missing attributes: age, Missing relations: WORKS_AT")
+}
@@ -0,0 +1,251 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+var ErrInvalidConcurrency = errors.New("invalid concurrency limit")
+
+// Limiter is a semaphore shared by every request doing one kind of work,
+// whose limit can change at runtime. Free slots go to interactive waiters
+// before batch ones (see WithPriority). Within a priority, waiters queue per
+// key (a group ID) and slots go round-robin between the keys with waiters,
+// first come first served within a key, so one group's backlog can't hold off
+// the others. The time waiters spent queued is recorded for Stats.
+type Limiter struct {
+	mu     sync.Mutex
+	limit  int
+	active int
+	tiers  [2]waitQueue // Interactive, then batch
+
+	acquired  int64
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+type waiter struct {
+	ready chan struct{} // Closed once the waiter holds a slot
+	since time.Time
+}
+
+// waitQueue holds the waiters of one priority by key.
+type waitQueue struct {
+	queues map[string][]*waiter
+	turns  []string // Keys with waiters, next to be served first
+}
+
+func (q *waitQueue) push(key string, w *waiter) {
+	if q.queues == nil {
+		q.queues = make(map[string][]*waiter)
+	}
+	if len(q.queues[key]) == 0 {
+		q.turns = append(q.turns, key)
+	}
+	q.queues[key] = append(q.queues[key], w)
+}
+
+// pop takes the next waiter, sending its key to the back of the line.
+func (q *waitQueue) pop() *waiter {
+	key := q.turns[0]
+	queue := q.queues[key]
+	q.turns = q.turns[1:]
+	if len(queue) > 1 {
+		q.queues[key] = queue[1:]
+		q.turns = append(q.turns, key)
+	} else {
+		delete(q.queues, key)
+	}
+	return queue[0]
+}
+
+// remove drops w from the queue, reporting false if it was already popped.
+func (q *waitQueue) remove(key string, w *waiter) bool {
+	i := slices.Index(q.queues[key], w)
+	if i < 0 {
+		return false
+	}
+	q.queues[key] = slices.Delete(q.queues[key], i, i+1)
+	if len(q.queues[key]) == 0 {
+		delete(q.queues, key)
+		q.turns = slices.DeleteFunc(q.turns, func(k string) bool { return k == key })
+	}
+	return true
+}
+
+// NewLimiter returns a Limiter allowing limit holders at once (at least one).
+func NewLimiter(limit int) *Limiter {
+	return &Limiter{limit: max(limit, 1)}
+}
+
+// Acquire waits for a slot, queued under key at ctx's priority, or returns
+// ctx's error without one.
+func (l *Limiter) Acquire(ctx context.Context, key string) error {
+	l.mu.Lock()
+	if l.active < l.limit && l.waiting() == 0 {
+		l.active++
+		l.record(0)
+		l.mu.Unlock()
+		return nil
+	}
+	w := &waiter{ready: make(chan struct{}), since: time.Now()}
+	tier := &l.tiers[0]
+	if priorityOf(ctx) == model.PriorityBatch {
+		tier = &l.tiers[1]
+	}
+	tier.push(key, w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !tier.remove(key, w) {
+			// Granted while giving up: hand the slot on
+			l.active--
+			l.grant()
+		}
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.grant()
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit changes the limit. Raising it admits waiters at once; lowering it
+// lets current holders finish and admits no one until they are under it.
+func (l *Limiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = max(limit, 1)
+	l.grant()
+}
+
+// Stats reports the limit, its holders and waiters, and the waits so far.
+func (l *Limiter) Stats() model.LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := model.LimiterStats{
+		Limit: l.limit, Active: l.active, Acquired: l.acquired,
+		MaxWaitMs: float64(l.maxWait) / float64(time.Millisecond),
+	}
+	var oldest time.Time
+	for i, tier := range l.tiers {
+		for key, queue := range tier.queues {
+			stats.Waiting += len(queue)
+			if i == 1 {
+				stats.WaitingBatch += len(queue)
+			}
+			if key != "" {
+				if stats.WaitingByGroup == nil {
+					stats.WaitingByGroup = make(map[string]int)
+				}
+				stats.WaitingByGroup[key] += len(queue)
+			}
+			if oldest.IsZero() || queue[0].since.Before(oldest) {
+				oldest = queue[0].since
+			}
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestWaitMs = float64(time.Since(oldest)) / float64(time.Millisecond)
+	}
+	if l.acquired > 0 {
+		stats.AvgWaitMs = float64(l.totalWait) / float64(l.acquired) / float64(time.Millisecond)
+	}
+	return stats
+}
+
+func (l *Limiter) waiting() int {
+	return len(l.tiers[0].turns) + len(l.tiers[1].turns)
+}
+
+// grant hands free slots to waiters, interactive ones first. l.mu must be held.
+func (l *Limiter) grant() {
+	for l.active < l.limit {
+		var w *waiter
+		switch {
+		case len(l.tiers[0].turns) > 0:
+			w = l.tiers[0].pop()
+		case len(l.tiers[1].turns) > 0:
+			w = l.tiers[1].pop()
+		default:
+			return
+		}
+		l.active++
+		l.record(time.Since(w.since))
+		close(w.ready)
+	}
+}
+
+func (l *Limiter) record(wait time.Duration) {
+	l.acquired++
+	l.totalWait += wait
+	l.maxWait = max(l.maxWait, wait)
+}
+
+// newLimiters creates the limiters of cfg.Concurrency.
+func newLimiters(cfg *config.Config) (bulkIngest, bulkSearch, extraction *Limiter) {
+	ingest, search, workers := 2, 5, 8
+	if cfg.Concurrency.BulkIngest > 0 {
+		ingest = cfg.Concurrency.BulkIngest
+	}
+	if cfg.Concurrency.BulkSearch > 0 {
+		search = cfg.Concurrency.BulkSearch
+	}
+	if cfg.Concurrency.ExtractionWorkers > 0 {
+		workers = cfg.Concurrency.ExtractionWorkers
+	}
+	return NewLimiter(ingest), NewLimiter(search), NewLimiter(workers)
+}
+
+// ConcurrencyStats reports the concurrency limits with their queue depth and
+// wait times.
+func (g *Graphiti) ConcurrencyStats() model.ConcurrencyStats {
+	return model.ConcurrencyStats{
+		BulkIngest:        g.bulkIngest.Stats(),
+		BulkSearch:        g.bulkSearch.Stats(),
+		ExtractionWorkers: g.extraction.Stats(),
+	}
+}
+
+// UpdateConcurrency changes the limits set in patch for the running server;
+// the configuration file is not changed.
+func (g *Graphiti) UpdateConcurrency(patch model.ConcurrencyPatch) (model.ConcurrencyStats, error) {
+	for name, limit := range map[string]*int{"bulk_ingest": patch.BulkIngest, "bulk_search": patch.BulkSearch, "extraction_workers": patch.ExtractionWorkers} {
+		if limit != nil && *limit < 1 {
+			return model.ConcurrencyStats{}, fmt.Errorf("%w: %s must be at least 1", ErrInvalidConcurrency, name)
+		}
+	}
+	if patch.BulkIngest != nil {
+		g.bulkIngest.SetLimit(*patch.BulkIngest)
+	}
+	if patch.BulkSearch != nil {
+		g.bulkSearch.SetLimit(*patch.BulkSearch)
+	}
+	if patch.ExtractionWorkers != nil {
+		g.extraction.SetLimit(*patch.ExtractionWorkers)
+	}
+	return g.ConcurrencyStats(), nil
+}
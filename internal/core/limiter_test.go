@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(1)
+	require.NoError(t, l.Acquire(ctx, ""))
+
+	acquired := make(chan struct{})
+	go func() {
+		if l.Acquire(ctx, "") == nil {
+			close(acquired)
+		}
+	}()
+	require.Eventually(t, func() bool { return l.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+	stats := l.Stats()
+	assert.Equal(t, 1, stats.Active)
+	assert.Equal(t, int64(1), stats.Acquired)
+
+	// Raising the limit admits the waiter without a release
+	l.SetLimit(2)
+	<-acquired
+	stats = l.Stats()
+	assert.Equal(t, 2, stats.Active)
+	assert.Zero(t, stats.Waiting)
+	assert.Equal(t, int64(2), stats.Acquired)
+	assert.Greater(t, stats.MaxWaitMs, 0.0)
+
+	// A waiter whose context ends leaves the queue
+	cancelled, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- l.Acquire(cancelled, "") }()
+	require.Eventually(t, func() bool { return l.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Zero(t, l.Stats().Waiting)
+
+	// Lowering the limit waits for holders to drop below it
+	l.SetLimit(1)
+	l.Release()
+	assert.Equal(t, 1, l.Stats().Active)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Acquire(timeout, ""))
+	l.Release()
+	assert.NoError(t, l.Acquire(ctx, ""))
+}
+
+func TestLimiter_TakesTurnsBetweenGroups(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(1)
+	require.NoError(t, l.Acquire(ctx, "bulk"))
+
+	granted := make(chan string)
+	for i, w := range []struct{ key, name string }{{"bulk", "bulk1"}, {"bulk", "bulk2"}, {"bulk", "bulk3"}, {"chat", "chat1"}} {
+		go func() {
+			if l.Acquire(ctx, w.key) == nil {
+				granted <- w.name
+			}
+		}()
+		require.Eventually(t, func() bool { return l.Stats().Waiting == i+1 }, time.Second, time.Millisecond)
+	}
+	assert.Equal(t, map[string]int{"bulk": 3, "chat": 1}, l.Stats().WaitingByGroup)
+
+	var order []string
+	for range 4 {
+		l.Release()
+		order = append(order, <-granted)
+	}
+	assert.Equal(t, []string{"bulk1", "chat1", "bulk2", "bulk3"}, order)
+}
+
+func TestLimiter_InteractiveBeforeBatch(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(1)
+	require.NoError(t, l.Acquire(ctx, ""))
+
+	granted := make(chan string)
+	for i, w := range []struct {
+		ctx  context.Context
+		name string
+	}{
+		{asBatch(ctx), "batch"},
+		{ctx, "interactive"},
+		{asBatch(WithPriority(ctx, model.PriorityInteractive)), "chosen"}, // The caller's choice stands
+	} {
+		go func() {
+			if l.Acquire(w.ctx, "g1") == nil {
+				granted <- w.name
+			}
+		}()
+		require.Eventually(t, func() bool { return l.Stats().Waiting == i+1 }, time.Second, time.Millisecond)
+	}
+	assert.Equal(t, 1, l.Stats().WaitingBatch)
+
+	var order []string
+	for range 3 {
+		l.Release()
+		order = append(order, <-granted)
+	}
+	assert.Equal(t, []string{"interactive", "chosen", "batch"}, order)
+}
+
+func TestBulkSearch_SharesLimit(t *testing.T) {
+	g := filterTestGraph(t)
+	one, zero := 1, 0
+	_, err := g.UpdateConcurrency(model.ConcurrencyPatch{BulkSearch: &one})
+	require.NoError(t, err)
+
+	results, err := g.BulkSearch(context.Background(), "g1", []model.BulkSearchQuery{{QueryID: "a", Query: "fact"}, {QueryID: "b", Query: "fact"}})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	stats := g.ConcurrencyStats().BulkSearch
+	assert.Equal(t, 1, stats.Limit)
+	assert.Equal(t, int64(2), stats.Acquired)
+	assert.Zero(t, stats.Active)
+
+	_, err = g.UpdateConcurrency(model.ConcurrencyPatch{BulkIngest: &zero})
+	assert.ErrorIs(t, err, ErrInvalidConcurrency)
+}
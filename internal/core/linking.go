@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// linkQueryEntities resolves the entities a query mentions to node UUIDs in
+// the group: by name embedding when an embedder is configured, otherwise by
+// case-insensitive name match. Mentions that are in the group's synonym table
+// are also resolved through what they stand for. It returns nil when linking
+// is disabled.
+func (g *Graphiti) linkQueryEntities(ctx context.Context, groupID, query string) ([]string, error) {
+	if g.Config == nil || !g.Config.Search.EntityLinking || g.Config.Extraction.Query == "" {
+		return nil, nil
+	}
+	mentions, err := g.Extractor.ExtractQueryEntities(ctx, query)
+	if err != nil || len(mentions) == 0 {
+		return nil, err
+	}
+	mentions = expandMentions(mentions, g.groupSynonyms(ctx, groupID))
+
+	threshold := g.Config.Search.LinkThreshold
+	if threshold <= 0 {
+		threshold = 0.8
+	}
+	similarity, err := g.similarityParams(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var linked []string
+	var byName map[string]string
+	for _, mention := range mentions {
+		if g.Embedder != nil {
+			embs, err := g.queryEmbeddings(ctx, mention)
+			if err != nil {
+				return nil, fmt.Errorf("failed to embed mention %q: %w", mention, err)
+			}
+			for _, emb := range embs {
+				res, err := g.Driver.ExecuteReadQuery(ctx, driver.SearchEntitiesByNameVectorQuery, map[string]interface{}{
+					"group_id":        groupID,
+					"embedding":       emb.Vector,
+					"embedding_model": emb.Model,
+					"metric":          similarity["metric"],
+					"min_score":       threshold,
+					"limit":           1,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve mention %q: %w", mention, err)
+				}
+				nodes, err := driver.ScanRecords[model.EntityNode](res)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read linked entities: %w", err)
+				}
+				for _, n := range nodes {
+					linked = appendUnique(linked, n.UUID)
+				}
+			}
+			continue
+		}
+
+		if byName == nil {
+			nodes, err := g.getGroupNodes(ctx, groupID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch group nodes: %w", err)
+			}
+			byName = make(map[string]string, len(nodes))
+			for _, n := range nodes {
+				byName[strings.ToLower(n.Name)] = n.UUID
+			}
+		}
+		if uuid, ok := byName[strings.ToLower(mention)]; ok {
+			linked = appendUnique(linked, uuid)
+		}
+	}
+	return linked, nil
+}
+
+// boostLinkedEdges moves edges touching a linked entity ahead of the rest,
+// keeping the relative order within each part.
+func boostLinkedEdges(edges []model.EntityEdge, linked []string) []model.EntityEdge {
+	if len(linked) == 0 {
+		return edges
+	}
+	isLinked := make(map[string]bool, len(linked))
+	for _, uuid := range linked {
+		isLinked[uuid] = true
+	}
+	boosted := make([]model.EntityEdge, 0, len(edges))
+	var rest []model.EntityEdge
+	for _, e := range edges {
+		if isLinked[e.SourceUUID] || isLinked[e.TargetUUID] {
+			boosted = append(boosted, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	return append(boosted, rest...)
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapEmbedder returns a fixed vector per text.
+type mapEmbedder map[string][]float32
+
+func (m mapEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return m[text], nil
+}
+
+func linkingTestGraph(t *testing.T, embedder mapEmbedder) *Graphiti {
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Query: "query %s"},
+		Search:     config.SearchConfig{EntityLinking: true},
+	}
+	mockLLM := &MockLLM{Response: `{"extracted_entities": [{"name": "alice"}]}`}
+	var g *Graphiti
+	if embedder != nil {
+		g = NewGraphiti(driver.NewMemoryDriver(), mockLLM, embedder, nil, cfg)
+	} else {
+		g = NewGraphiti(driver.NewMemoryDriver(), mockLLM, nil, nil, cfg)
+	}
+
+	ctx := context.Background()
+	for _, name := range []string{"Alice", "Bob"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: time.Now().UTC()}))
+	}
+	for _, e := range []struct{ uuid, source string }{{"e1", "Bob"}, {"e2", "Alice"}} {
+		seedFact(t, g.Driver, "g1", e.uuid, e.source, e.source, "LIKES", e.source+" likes tea", map[string]interface{}{
+			"fact_embedding": []float32{0.5, 0.5},
+		})
+	}
+	return g
+}
+
+func TestSearch_EntityLinkingByName(t *testing.T) {
+	g := linkingTestGraph(t, nil)
+
+	edges, err := g.Search(context.Background(), "g1", "likes tea")
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	assert.Equal(t, "e2", edges[0].UUID)
+
+	g.Config.Search.EntityLinking = false
+	edges, err = g.Search(context.Background(), "g1", "likes tea")
+	require.NoError(t, err)
+	assert.Equal(t, "e1", edges[0].UUID)
+}
+
+func TestSearch_EntityLinkingByEmbedding(t *testing.T) {
+	g := linkingTestGraph(t, mapEmbedder{
+		"Alice":     {1, 0},
+		"Bob":       {0, 1},
+		"alice":     {0.95, 0.05},
+		"likes tea": {0.5, 0.5},
+	})
+
+	linked, err := g.linkQueryEntities(context.Background(), "g1", "likes tea")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice"}, linked)
+
+	edges, err := g.Search(context.Background(), "g1", "likes tea")
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	assert.Equal(t, "e2", edges[0].UUID)
+
+	// Mentions below the similarity threshold are not linked
+	g.Config.Search.LinkThreshold = 0.999
+	linked, err = g.linkQueryEntities(context.Background(), "g1", "likes tea")
+	require.NoError(t, err)
+	assert.Empty(t, linked)
+}
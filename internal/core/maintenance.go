@@ -0,0 +1,256 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrReportNotFound = errors.New("maintenance report not found")
+
+// CheckSummaryConsistency asks the LLM, for every summarized entity in the
+// group, whether its summary contradicts its currently valid facts. With
+// regenerate, flagged summaries are rebuilt via RegenerateSummary. The report
+// is stored and can be read back with GetConsistencyReport.
+func (g *Graphiti) CheckSummaryConsistency(ctx context.Context, groupID string, regenerate bool) (*model.ConsistencyReport, error) {
+	group, err := g.GetGroup(ctx, groupID)
+	if err != nil && !errors.Is(err, ErrGroupNotFound) {
+		return nil, err
+	}
+	scoped := g.forGroup(group)
+
+	nodes, err := g.getGroupNodes(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group nodes: %w", err)
+	}
+
+	limit := g.bulkIngest.Limit()
+
+	report := &model.ConsistencyReport{GroupID: groupID, CheckedAt: time.Now().UTC()}
+	var mu sync.Mutex
+	forEachBounded(limit, len(nodes), func(i int) {
+		node := nodes[i]
+		if node.Summary == "" {
+			return
+		}
+		entry, checked := g.checkEntityConsistency(ctx, scoped, node, regenerate)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if checked {
+			report.Checked++
+		}
+		switch {
+		case entry == nil:
+		case entry.Error != "":
+			report.Failed++
+			report.Entities = append(report.Entities, *entry)
+		default:
+			report.Inconsistent++
+			if entry.Regenerated {
+				report.Regenerated++
+			}
+			report.Entities = append(report.Entities, *entry)
+		}
+	})
+	sort.Slice(report.Entities, func(i, j int) bool { return report.Entities[i].Name < report.Entities[j].Name })
+
+	if err := g.saveReport(ctx, model.ReportKindConsistency, groupID, report.CheckedAt, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkEntityConsistency returns nil for a consistent summary and whether the LLM check ran.
+func (g *Graphiti) checkEntityConsistency(ctx context.Context, scoped *Graphiti, node model.EntityNode, regenerate bool) (*model.EntityConsistency, bool) {
+	entry := &model.EntityConsistency{UUID: node.UUID, Name: node.Name, Summary: node.Summary}
+
+	facts, err := g.entityFacts(ctx, node.UUID)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry, false
+	}
+
+	var issues []string
+	if len(facts) == 0 {
+		// Every fact behind the summary has been invalidated
+		issues = []string{"entity has no valid facts"}
+	} else {
+		verdict, err := scoped.Summarizer.CheckConsistency(ctx, node, facts)
+		if err != nil {
+			entry.Error = err.Error()
+			return entry, false
+		}
+		if verdict.Consistent {
+			return nil, true
+		}
+		issues = verdict.Issues
+	}
+	entry.Issues = issues
+
+	if regenerate {
+		updated, err := g.RegenerateSummary(ctx, node.UUID)
+		if err != nil {
+			entry.Error = err.Error()
+			return entry, true
+		}
+		entry.Regenerated = true
+		entry.NewSummary = updated.Summary
+	}
+	return entry, true
+}
+
+// DedupeEdges merges active RELATES_TO edges that repeat the same fact between
+// the same entities, a leftover of ingests that predate strict deduplication.
+// The oldest edge of each set is kept and inherits the union of the set's
+// episodes; the others are deleted. A dry run only reports what would change.
+func (g *Graphiti) DedupeEdges(ctx context.Context, groupID string, dryRun bool) (*model.DedupeEdgesReport, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupEdgeEpisodesQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group edges: %w", err)
+	}
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group edges: %w", err)
+	}
+
+	// Same key as the strict check in processEdge
+	type edgeKey struct{ source, target, name, fact string }
+	sets := make(map[edgeKey][]model.EntityEdge)
+	var order []edgeKey
+	for _, e := range edges {
+		k := edgeKey{e.SourceUUID, e.TargetUUID, e.Name, e.Fact}
+		if _, ok := sets[k]; !ok {
+			order = append(order, k)
+		}
+		sets[k] = append(sets[k], e)
+	}
+
+	report := &model.DedupeEdgesReport{GroupID: groupID, RanAt: time.Now().UTC(), DryRun: dryRun, Scanned: len(edges)}
+	for _, k := range order {
+		set := sets[k]
+		if len(set) < 2 {
+			continue
+		}
+		sort.Slice(set, func(i, j int) bool {
+			if !set[i].CreatedAt.Equal(set[j].CreatedAt) {
+				return set[i].CreatedAt.Before(set[j].CreatedAt)
+			}
+			return set[i].UUID < set[j].UUID
+		})
+
+		merged := model.MergedEdge{
+			KeptUUID:   set[0].UUID,
+			SourceUUID: k.source,
+			TargetUUID: k.target,
+			Name:       k.name,
+			Fact:       k.fact,
+		}
+		for _, e := range set {
+			merged.Episodes = appendUnique(merged.Episodes, e.Episodes...)
+		}
+		for _, e := range set[1:] {
+			merged.RemovedUUIDs = append(merged.RemovedUUIDs, e.UUID)
+		}
+
+		if !dryRun {
+			if err := g.mergeDuplicateEdges(ctx, merged); err != nil {
+				return nil, err
+			}
+		}
+		report.Removed += len(merged.RemovedUUIDs)
+		report.Merged = append(report.Merged, merged)
+	}
+	sort.Slice(report.Merged, func(i, j int) bool { return report.Merged[i].KeptUUID < report.Merged[j].KeptUUID })
+
+	if !dryRun {
+		g.invalidateSearchCache(ctx, groupID)
+		if err := g.saveReport(ctx, model.ReportKindDedupeEdges, groupID, report.RanAt, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// mergeDuplicateEdges widens the kept edge's episodes before deleting the
+// duplicates, so an interrupted run loses no provenance and can be repeated.
+func (g *Graphiti) mergeDuplicateEdges(ctx context.Context, merged model.MergedEdge) error {
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SetEdgeEpisodesQuery, map[string]interface{}{
+		"uuid":     merged.KeptUUID,
+		"episodes": merged.Episodes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update episodes of edge %s: %w", merged.KeptUUID, err)
+	}
+	for _, uuid := range merged.RemovedUUIDs {
+		res, err := g.Driver.ExecuteQuery(ctx, driver.DeleteEntityEdgeQuery, map[string]interface{}{"uuid": uuid})
+		if err != nil {
+			return fmt.Errorf("failed to delete duplicate edge %s: %w", uuid, err)
+		}
+		if err := g.recordDeletions(ctx, model.ChangeKindFact, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDedupeEdgesReport returns the group's latest edge dedupe report, or ErrReportNotFound.
+func (g *Graphiti) GetDedupeEdgesReport(ctx context.Context, groupID string) (*model.DedupeEdgesReport, error) {
+	var report model.DedupeEdgesReport
+	if err := g.loadReport(ctx, model.ReportKindDedupeEdges, groupID, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetConsistencyReport returns the group's latest consistency report, or ErrReportNotFound.
+func (g *Graphiti) GetConsistencyReport(ctx context.Context, groupID string) (*model.ConsistencyReport, error) {
+	var report model.ConsistencyReport
+	if err := g.loadReport(ctx, model.ReportKindConsistency, groupID, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (g *Graphiti) saveReport(ctx context.Context, kind, groupID string, at time.Time, report interface{}) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s report: %w", kind, err)
+	}
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveMaintenanceReportQuery, map[string]interface{}{
+		"group_id":   groupID,
+		"kind":       kind,
+		"created_at": at.Format(time.RFC3339),
+		"report":     string(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s report: %w", kind, err)
+	}
+	return nil
+}
+
+func (g *Graphiti) loadReport(ctx context.Context, kind, groupID string, dest interface{}) error {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetMaintenanceReportQuery, map[string]interface{}{
+		"group_id": groupID,
+		"kind":     kind,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s report: %w", kind, err)
+	}
+	if len(res.Records) == 0 {
+		return ErrReportNotFound
+	}
+	if err := decodeJSONField(res.Records[0], "report", dest); err != nil {
+		return fmt.Errorf("invalid %s report for group %s: %w", kind, groupID, err)
+	}
+	return nil
+}
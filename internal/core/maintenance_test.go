@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSummaryConsistency(t *testing.T) {
+	ctx := context.Background()
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "edges"):
+			return threeSourceEdges
+		case strings.HasPrefix(prompt, "summarize"):
+			return `{"summary": "rebuilt"}`
+		case strings.Contains(prompt, "stale"):
+			return `{"consistent": false, "issues": ["a no longer knows c"]}`
+		}
+		return `{"consistent": true, "issues": []}`
+	})
+	g.Config.Summary.Consistency = "check %s %s"
+	g.Summarizer.Prompts.Consistency = g.Config.Summary.Consistency
+
+	_, err := g.GetConsistencyReport(ctx, "g1")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep1", "g1", "", nil, time.Now().UTC()))
+	a, err := g.GetEntity(ctx, "a")
+	require.NoError(t, err)
+	a.Summary = "stale summary"
+	require.NoError(t, g.saveEntity(ctx, *a))
+
+	report, err := g.CheckSummaryConsistency(ctx, "g1", false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, 1, report.Inconsistent)
+	require.Len(t, report.Entities, 1)
+	assert.Equal(t, "a", report.Entities[0].UUID)
+	assert.Equal(t, []string{"a no longer knows c"}, report.Entities[0].Issues)
+	assert.False(t, report.Entities[0].Regenerated)
+
+	report, err = g.CheckSummaryConsistency(ctx, "g1", true)
+	require.NoError(t, err)
+	require.Len(t, report.Entities, 1)
+	assert.True(t, report.Entities[0].Regenerated)
+	assert.Equal(t, "rebuilt", report.Entities[0].NewSummary)
+	assert.Equal(t, 1, report.Regenerated)
+
+	stored, err := g.GetConsistencyReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Regenerated)
+	assert.Equal(t, "rebuilt", stored.Entities[0].NewSummary)
+}
+
+func TestDedupeEdges_MergesEpisodes(t *testing.T) {
+	ctx := context.Background()
+	g, _ := edgeTestGraph(t, driver.NewMemoryDriver(), func(string) string { return "{}" })
+	save := func(uuid, target, fact, createdAt, episode string) {
+		seedFact(t, g.Driver, "g1", uuid, "a", target, "KNOWS", fact, map[string]interface{}{
+			"created_at": createdAt, "episodes": []string{episode},
+		})
+	}
+	save("e-new", "b", "a knows b", "2024-01-03T00:00:00Z", "ep3")
+	save("e-old", "b", "a knows b", "2024-01-01T00:00:00Z", "ep1")
+	save("e-mid", "b", "a knows b", "2024-01-02T00:00:00Z", "ep1")
+	save("e-other", "c", "a knows c", "2024-01-01T00:00:00Z", "ep2")
+
+	report, err := g.DedupeEdges(ctx, "g1", true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Scanned)
+	assert.Equal(t, 2, report.Removed)
+	edges, err := g.getGroupEdges(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, edges, 4, "dry run must not modify the graph")
+	_, err = g.GetDedupeEdgesReport(ctx, "g1")
+	assert.ErrorIs(t, err, ErrReportNotFound)
+
+	report, err = g.DedupeEdges(ctx, "g1", false)
+	require.NoError(t, err)
+	require.Len(t, report.Merged, 1)
+	merged := report.Merged[0]
+	assert.Equal(t, "e-old", merged.KeptUUID)
+	assert.Equal(t, []string{"e-mid", "e-new"}, merged.RemovedUUIDs)
+	assert.Equal(t, []string{"ep1", "ep3"}, merged.Episodes)
+
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupEdgeEpisodesQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	remaining, err := driver.ScanRecords[model.EntityEdge](res)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	for _, e := range remaining {
+		if e.UUID == "e-old" {
+			assert.Equal(t, []string{"ep1", "ep3"}, e.Episodes)
+		}
+	}
+
+	// A second run finds nothing left to merge
+	report, err = g.DedupeEdges(ctx, "g1", false)
+	require.NoError(t, err)
+	assert.Zero(t, report.Removed)
+	stored, err := g.GetDedupeEdgesReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Zero(t, stored.Removed)
+}
@@ -2,18 +2,29 @@ package core
 
 import (
 	"context"
+	"maps"
+	"sync"
+	"testing"
+	"time"
 	
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/driver"
 )
 
 type MockDriver struct {
+	mu            sync.Mutex
 	QueryExecuted string
 	QueryParams   map[string]interface{}
 	MockResult    neo4j.EagerResult
 	Err           error
+	ReadQueries   int
 }
 
 func (m *MockDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.QueryExecuted = query
 	m.QueryParams = params
 	if m.Err != nil {
@@ -22,6 +33,13 @@ func (m *MockDriver) ExecuteQuery(ctx context.Context, query string, params map[
 	return m.MockResult, nil
 }
 
+func (m *MockDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	m.mu.Lock()
+	m.ReadQueries++
+	m.mu.Unlock()
+	return m.ExecuteQuery(ctx, query, params)
+}
+
 func (m *MockDriver) BuildIndices(ctx context.Context) error {
 	return nil
 }
@@ -43,10 +61,13 @@ func (m *MockEmbedder) Embed(ctx context.Context, text string) ([]float32, error
 }
 
 type MockLLM struct {
+	mu           sync.Mutex
 	Response     string
 	ResponseQueue []string
 }
 func (m *MockLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if len(m.ResponseQueue) > 0 {
 		resp := m.ResponseQueue[0]
 		m.ResponseQueue = m.ResponseQueue[1:]
@@ -54,3 +75,56 @@ func (m *MockLLM) Generate(ctx context.Context, prompt string) (string, error) {
 	}
 	return m.Response, nil
 }
+
+func mustTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// llmFunc adapts a function to llm.LLMClient for tests that inspect prompts.
+type llmFunc func(prompt string) string
+
+func (f llmFunc) Generate(ctx context.Context, prompt string) (string, error) {
+	return f(prompt), nil
+}
+
+type llmErrFunc func(prompt string) (string, error)
+
+func (f llmErrFunc) Generate(ctx context.Context, prompt string) (string, error) {
+	return f(prompt)
+}
+
+// seedEntities saves an entity of groupID named after each uuid.
+func seedEntities(t testing.TB, d driver.GraphDriver, groupID string, uuids ...string) {
+	t.Helper()
+	for _, uuid := range uuids {
+		seedEntity(t, d, groupID, uuid, nil)
+	}
+}
+
+// seedEntity saves an entity of groupID named after its uuid; props adds or
+// overrides properties such as name, labels or attributes.
+func seedEntity(t testing.TB, d driver.GraphDriver, groupID, uuid string, props map[string]interface{}) {
+	t.Helper()
+	params := map[string]interface{}{"uuid": uuid, "name": uuid, "group_id": groupID}
+	maps.Copy(params, props)
+	_, err := d.ExecuteQuery(context.Background(), driver.SaveEntityNodeQuery, params)
+	require.NoError(t, err)
+}
+
+// seedFact saves a valid fact of groupID between two saved entities; props
+// adds or overrides properties such as valid_at, invalid_at or fact_embedding.
+func seedFact(t testing.TB, d driver.GraphDriver, groupID, uuid, source, target, name, fact string, props map[string]interface{}) {
+	t.Helper()
+	params := map[string]interface{}{
+		"uuid": uuid, "source_uuid": source, "target_uuid": target, "name": name, "fact": fact,
+		"group_id": groupID, "invalid_at": "",
+	}
+	maps.Copy(params, props)
+	res, err := d.ExecuteQuery(context.Background(), driver.SaveEntityEdgeQuery, params)
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Records, "fact %s needs both of its entities", uuid)
+}
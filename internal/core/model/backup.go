@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// ExportVersion is the format version written to GraphExport.Version.
+const ExportVersion = 1
+
+const (
+	BackupScopeGroup = "group"
+	BackupScopeGraph = "graph"
+)
+
+// GraphExport is a raw copy of a group's (or, without GroupID, the whole
+// graph's) nodes and relationships with their stored properties. Encrypted
+// values stay encrypted, and offloaded episode content is inlined.
+type GraphExport struct {
+	Version    int          `json:"version"`
+	GroupID    string       `json:"group_id,omitempty"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Nodes      []ExportNode `json:"nodes"`
+	Edges      []ExportEdge `json:"edges"`
+}
+
+type ExportNode struct {
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type ExportEdge struct {
+	Type       string                 `json:"type" db:"type"`
+	SourceUUID string                 `json:"source_uuid" db:"source_uuid"`
+	TargetUUID string                 `json:"target_uuid" db:"target_uuid"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// BackupInfo describes one stored snapshot. ID is its UTC timestamp, unique per group.
+type BackupInfo struct {
+	ID        string    `json:"id"`
+	GroupID   string    `json:"group_id,omitempty"` // Empty for whole-graph snapshots
+	Key       string    `json:"key"`                // Object key within the backup store
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreReport describes a group (or graph) replaced by a snapshot.
+type RestoreReport struct {
+	GroupID    string    `json:"group_id,omitempty"`
+	BackupID   string    `json:"backup_id"`
+	Nodes      int       `json:"nodes"`
+	Edges      int       `json:"edges"`
+	RestoredAt time.Time `json:"restored_at"`
+	// PreRestoreBackupID is the snapshot of the state the restore replaced.
+	PreRestoreBackupID string `json:"pre_restore_backup_id,omitempty"`
+}
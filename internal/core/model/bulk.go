@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+const (
+	EpisodeStatusSuccess = "success"
+	EpisodeStatusFailed  = "failed"
+)
+
+// EpisodeResult is the outcome of one episode in a partial-results bulk ingest.
+type EpisodeResult struct {
+	Index   int         `json:"index"` // Position in the original request
+	Status  string      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Episode EpisodeData `json:"episode"`
+}
+
+type BulkIngestResult struct {
+	GroupID   string          `json:"group_id"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Results   []EpisodeResult `json:"results"`
+}
+
+// FailedEpisodes returns the episodes that did not ingest, in request order.
+func (r *BulkIngestResult) FailedEpisodes() []EpisodeData {
+	var out []EpisodeData
+	for _, res := range r.Results {
+		if res.Status == EpisodeStatusFailed {
+			out = append(out, res.Episode)
+		}
+	}
+	return out
+}
+
+// StreamEpisode is one NDJSON line of a streaming ingest. GroupID may be left
+// empty to use the stream's default group.
+type StreamEpisode struct {
+	Index   int    `json:"-"`
+	GroupID string `json:"group_id,omitempty"`
+	EpisodeData
+}
+
+// StreamResult is the status line emitted for each streamed episode.
+type StreamResult struct {
+	Index   int    `json:"index"` // Zero-based position among the stream's non-empty lines
+	GroupID string `json:"group_id,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// IngestJob is a checkpointed bulk load. Processed episodes are never re-run
+// after a restart; the entity map keeps resumed batches resolving names to the
+// same nodes without repeating LLM deduplication.
+type IngestJob struct {
+	UUID      string    `json:"uuid" db:"uuid"`
+	GroupID   string    `json:"group_id" db:"group_id"`
+	Status    string    `json:"status" db:"status"`
+	Total     int       `json:"total" db:"total"`
+	Processed int       `json:"processed" db:"processed"` // Episodes before this cursor are done
+	Failed    []int     `json:"failed,omitempty"`         // Indices of episodes that failed to ingest
+	Error     string    `json:"error,omitempty" db:"error"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	EntityMap map[string]string `json:"-"` // Entity name -> resolved UUID
+}
+
+// DeadLetter is an episode that failed to ingest, kept with its last error
+// until it is requeued successfully.
+type DeadLetter struct {
+	UUID      string      `json:"uuid" db:"uuid"`
+	GroupID   string      `json:"group_id" db:"group_id"`
+	Name      string      `json:"name" db:"name"`
+	Episode   EpisodeData `json:"episode"`
+	Error     string      `json:"error" db:"error"`
+	Attempts  int         `json:"attempts" db:"attempts"` // Failed ingests of this payload
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Change is an entry of a group's change log: an entity, episode or fact
+// written to or deleted from the graph, or the deletion of the whole group's
+// data. Seq numbers the group's changes from 1, in order.
+type Change struct {
+	GroupID string    `json:"group_id" db:"group_id"`
+	Seq     int64     `json:"seq" db:"seq"`
+	Kind    string    `json:"kind" db:"kind"` // One of the ChangeKind values
+	UUID    string    `json:"uuid" db:"uuid"` // The entity, episode or fact changed, or the group_id
+	Op      string    `json:"op" db:"op"`     // One of the ChangeOp values
+	At      time.Time `json:"at" db:"at"`
+}
+
+// What a change wrote.
+const (
+	ChangeKindEntity  = "entity"
+	ChangeKindEpisode = "episode"
+	ChangeKindFact    = "fact"
+	// A group change deletes everything the group held before it, as
+	// deleting, clearing or restoring the group does.
+	ChangeKindGroup = "group"
+)
+
+// How a change wrote it.
+const (
+	ChangeOpCreate     = "create"
+	ChangeOpUpdate     = "update"
+	ChangeOpInvalidate = "invalidate"
+	ChangeOpDelete     = "delete"
+)
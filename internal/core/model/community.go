@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// CommunityDetection is the state of a group's latest community detection run
+// on this server. Status is one of the JobStatus values.
+type CommunityDetection struct {
+	GroupID     string     `json:"group_id"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	Communities int        `json:"communities"` // Communities saved, once completed
+	Error       string     `json:"error,omitempty"`
+}
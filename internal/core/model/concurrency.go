@@ -0,0 +1,40 @@
+package model
+
+// Priorities of work waiting for a shared worker; see core.WithPriority.
+const (
+	PriorityInteractive = "interactive"
+	PriorityBatch       = "batch"
+)
+
+// ValidPriority reports whether p names a priority.
+func ValidPriority(p string) bool {
+	return p == PriorityInteractive || p == PriorityBatch
+}
+
+// LimiterStats reports a concurrency limit shared by all requests: the work
+// running and queued under it, and how long work waited for a slot.
+type LimiterStats struct {
+	Limit          int            `json:"limit"`
+	Active         int            `json:"active"`                     // Slots in use
+	Waiting        int            `json:"waiting"`                    // Queue depth
+	WaitingBatch   int            `json:"waiting_batch"`              // Part of Waiting queued at batch priority
+	WaitingByGroup map[string]int `json:"waiting_by_group,omitempty"` // Waiting by the group the work is for
+	Acquired       int64          `json:"acquired"`                   // Slots handed out since the server started
+	AvgWaitMs      float64        `json:"avg_wait_ms"`                // Mean wait per acquired slot
+	MaxWaitMs      float64        `json:"max_wait_ms"`                // Longest wait of an acquired slot
+	OldestWaitMs   float64        `json:"oldest_wait_ms"`             // How long the head of the queue has waited so far
+}
+
+type ConcurrencyStats struct {
+	BulkIngest        LimiterStats `json:"bulk_ingest"`
+	BulkSearch        LimiterStats `json:"bulk_search"`
+	ExtractionWorkers LimiterStats `json:"extraction_workers"`
+}
+
+// ConcurrencyPatch changes the [concurrency] limits of a running server. Nil
+// fields are left unchanged.
+type ConcurrencyPatch struct {
+	BulkIngest        *int `json:"bulk_ingest,omitempty"`
+	BulkSearch        *int `json:"bulk_search,omitempty"`
+	ExtractionWorkers *int `json:"extraction_workers,omitempty"`
+}
@@ -12,4 +12,15 @@ type DeduplicationResult struct {
 
 type ContradictionResult struct {
 	ContradictedEdgeUUIDs []string `json:"contradicted_edge_uuids"`
+	ReinstatedEdgeUUID string `json:"reinstated_edge_uuid,omitempty"` // An invalidated fact the new one states again
+}
+
+// FactContradictions are the pairs of valid facts the LLM judged inconsistent.
+type FactContradictions struct {
+	Contradictions []FactConflict `json:"contradictions"`
+}
+
+type FactConflict struct {
+	EdgeUUIDs []string `json:"edge_uuids"`
+	Reason    string   `json:"reason"`
 }
@@ -3,19 +3,36 @@ package model
 import "time"
 
 type EntityEdge struct {
-	UUID          string                 `json:"uuid"`
-	SourceUUID    string                 `json:"source_node_uuid"`
-	TargetUUID    string                 `json:"target_node_uuid"`
-	GroupID       string                 `json:"group_id"`
-	Name          string                 `json:"name"` // RELATES_TO
-	Fact          string                 `json:"fact"`
-	CreatedAt     time.Time              `json:"created_at"`
-	ExpiredAt     *time.Time             `json:"expired_at,omitempty"`
-	ValidAt       time.Time              `json:"valid_at"`
-	InvalidAt     *time.Time             `json:"invalid_at,omitempty"`
-	Episodes      []string               `json:"episodes"` // List of Episode UUIDs
-	FactEmbedding []float32              `json:"fact_embedding,omitempty"`
+	UUID          string                 `json:"uuid" db:"uuid"`
+	SourceUUID    string                 `json:"source_node_uuid" db:"source_uuid"`
+	TargetUUID    string                 `json:"target_node_uuid" db:"target_uuid"`
+	GroupID       string                 `json:"group_id" db:"group_id"`
+	Name          string                 `json:"name" db:"name"` // RELATES_TO
+	Fact          string                 `json:"fact" db:"fact"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+	ExpiredAt     *time.Time             `json:"expired_at,omitempty" db:"expired_at"`
+	ValidAt       time.Time              `json:"valid_at" db:"valid_at"`
+	InvalidAt     *time.Time             `json:"invalid_at,omitempty" db:"invalid_at"`
+	Episodes      []string               `json:"episodes" db:"episodes"`                     // List of Episode UUIDs
+	MentionCount  int                    `json:"mention_count,omitempty" db:"mention_count"` // Episodes that stated the fact
+	FactEmbedding []float32              `json:"fact_embedding,omitempty" db:"fact_embedding"`
 	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	// Confidence, set on search results, is how well the fact matches the
+	// query, from 0 to 1.
+	Confidence *float64 `json:"confidence,omitempty"`
+	// Citation is a short handle for citing the fact, such as "F-3f2a9c1b",
+	// unique in its group, that GET /facts/:uuid?group_id= resolves.
+	Citation string `json:"citation,omitempty" db:"citation"`
+}
+
+// CoMentionRelation names the links community detection adds between
+// entities mentioned by the same episodes; they are never saved.
+const CoMentionRelation = "CO_MENTIONED"
+
+// Provenance is a fact with the episodes it was extracted from.
+type Provenance struct {
+	Fact     EntityEdge     `json:"fact"`
+	Episodes []EpisodicNode `json:"episodes"`
 }
 
 type EpisodicEdge struct {
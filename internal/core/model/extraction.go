@@ -3,6 +3,7 @@ package model
 // Matches Python ExtractedEntity in graphiti_core/prompts/extract_nodes.py
 type ExtractedEntity struct {
 	Name         string                 `json:"name"`
+	EntityType   string                 `json:"entity_type,omitempty"` // Type name from the ontology
 	EntityTypeID int                    `json:"entity_type_id"`
 	Attributes   map[string]interface{} `json:"attributes,omitempty"`
 }
@@ -12,11 +13,47 @@ type ExtractedEntities struct {
 	ExtractedEntities []ExtractedEntity `json:"extracted_entities"`
 }
 
+// HypotheticalPassage is the answer the LLM imagines for a search query ([search] hyde).
+type HypotheticalPassage struct {
+	Passage string `json:"passage"`
+}
+
+type ExtractedTopics struct {
+	Topics []string `json:"topics"`
+}
+
+// Sentiments of the user toward a fact about them.
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// ValidSentiment reports whether sentiment is one of the Sentiment constants.
+func ValidSentiment(sentiment string) bool {
+	switch sentiment {
+	case SentimentPositive, SentimentNegative, SentimentNeutral:
+		return true
+	}
+	return false
+}
+
+// FactSentiment is how the user feels about a fact about them ([ingest] sentiment).
+type FactSentiment struct {
+	Sentiment string `json:"sentiment"` // "positive", "negative" or "neutral"
+	Emotion   string `json:"emotion"`   // e.g. "likes", "dislikes", "frustrated"; empty when none
+}
+
 // Matches Python EntitySummary
 type EntitySummary struct {
 	Summary string `json:"summary"`
 }
 
+type SummaryConsistency struct {
+	Consistent bool     `json:"consistent"`
+	Issues     []string `json:"issues"`
+}
+
 type CommunityName struct {
 	Name string `json:"name"`
 }
@@ -41,3 +78,19 @@ type ExtractedEdge struct {
 type ExtractedEdges struct {
 	ExtractedEdges []ExtractedEdge `json:"extracted_edges"`
 }
+
+// ResolvedContent is an episode's content with its coreferences resolved.
+type ResolvedContent struct {
+	ResolvedContent string `json:"resolved_content"`
+}
+
+// FactVerdict says whether the source text supports an extracted fact, given
+// by its number in the verify prompt.
+type FactVerdict struct {
+	ID        int  `json:"id"`
+	Supported bool `json:"supported"`
+}
+
+type FactVerdicts struct {
+	Verdicts []FactVerdict `json:"verdicts"`
+}
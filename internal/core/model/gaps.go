@@ -0,0 +1,43 @@
+package model
+
+// FactChecklist lists what should be known about an entity: attribute keys and
+// relation types (matched case-insensitively, with spaces read as underscores).
+type FactChecklist struct {
+	Attributes []string `json:"attributes,omitempty"`
+	Relations  []string `json:"relations,omitempty"`
+}
+
+// FactGaps compares an entity's current attributes and valid facts against a checklist.
+type FactGaps struct {
+	UUID              string                 `json:"uuid"`
+	Name              string                 `json:"name"`
+	Checklist         string                 `json:"checklist,omitempty"` // Named group checklist, if one was used
+	KnownAttributes   map[string]interface{} `json:"known_attributes"`
+	KnownRelations    map[string][]string    `json:"known_relations"` // Relation type -> facts
+	MissingAttributes []string               `json:"missing_attributes"`
+	MissingRelations  []string               `json:"missing_relations"`
+}
+
+// KnowledgeGaps lists what a group's key entities lack by the checklists and
+// attribute schemas of their entity types, most salient entity first.
+type KnowledgeGaps struct {
+	GroupID  string       `json:"group_id"`
+	Entities []EntityGaps `json:"entities"`
+}
+
+// EntityGaps are one entity's gaps and questions that would fill them.
+type EntityGaps struct {
+	FactGaps
+	Type      string   `json:"type"` // Entity type whose checklist applied
+	Questions []string `json:"questions"`
+}
+
+// GapQuestions are the questions the LLM suggests for entity gaps.
+type GapQuestions struct {
+	Questions []GapQuestion `json:"questions"`
+}
+
+type GapQuestion struct {
+	UUID     string `json:"uuid"`
+	Question string `json:"question"`
+}
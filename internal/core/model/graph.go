@@ -0,0 +1,45 @@
+package model
+
+// GraphView is a renderable subgraph in the node/link shape used by D3 force layouts.
+type GraphView struct {
+	Nodes []GraphViewNode `json:"nodes"`
+	Links []GraphViewLink `json:"links"`
+}
+
+type GraphViewNode struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Summary string `json:"summary,omitempty"`
+	Depth   int    `json:"depth"` // Hops from the center node (0 when no center is given)
+}
+
+type GraphViewLink struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Label  string `json:"label"`
+	Fact   string `json:"fact"`
+}
+
+// Neighborhood is the local subgraph around an entity: the entities reachable
+// within Depth hops over currently valid facts, the facts along the way, and
+// the communities those entities belong to.
+type Neighborhood struct {
+	Center      string                  `json:"center"`
+	Depth       int                     `json:"depth"`
+	Nodes       []NeighborhoodNode      `json:"nodes"` // Nearest first, the center at hop 0
+	Edges       []EntityEdge            `json:"edges"`
+	Communities []NeighborhoodCommunity `json:"communities"`
+}
+
+type NeighborhoodNode struct {
+	Entity EntityNode `json:"entity"`
+	Hops   int        `json:"hops"`
+}
+
+type NeighborhoodCommunity struct {
+	UUID    string   `json:"uuid" db:"uuid"`
+	Name    string   `json:"name" db:"name"`
+	Summary string   `json:"summary,omitempty" db:"summary"`
+	Members []string `json:"members" db:"members"` // The neighborhood's entities in the community
+}
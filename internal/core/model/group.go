@@ -0,0 +1,113 @@
+package model
+
+import "time"
+
+// GroupSummary describes a tenant for admin listings.
+type GroupSummary struct {
+	GroupID       string                 `json:"group_id" db:"group_id"`
+	Name          string                 `json:"name,omitempty" db:"name"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Episodes      int                    `json:"episodes" db:"episodes"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+	LastEpisodeAt time.Time              `json:"last_episode_at" db:"last_episode_at"`
+}
+
+// GroupNode holds per-tenant configuration. It is created lazily on first ingest.
+type GroupNode struct {
+	GroupID   string                 `json:"group_id" db:"group_id"`
+	Name      string                 `json:"name" db:"name"`
+	Owner     string                 `json:"owner,omitempty" db:"owner"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at" db:"updated_at"`
+	Settings  GroupSettings          `json:"settings"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type GroupSettings struct {
+	// Ontology replaces the default entity type schema when an episode doesn't supply one.
+	Ontology string `json:"ontology,omitempty"`
+	// RetentionDays is how long episodes are kept; older ones are deleted by
+	// the retention sweep. 0 keeps them forever.
+	RetentionDays int `json:"retention_days,omitempty"`
+	// Model overrides the configured LLM model for this group's pipeline.
+	Model string `json:"model,omitempty"`
+	// Prompts override the configured prompt templates; empty fields keep the defaults.
+	Prompts PromptOverrides `json:"prompts,omitempty"`
+	// VerifyFacts overrides [ingest] verify_facts for this group when set.
+	VerifyFacts *bool `json:"verify_facts,omitempty"`
+	// Checklists name, per entity type of the ontology, the attributes and
+	// relations an entity of that type is expected to have (see gap queries).
+	Checklists map[string]FactChecklist `json:"checklists,omitempty"`
+	// AttributeSchemas are JSON Schemas, by entity type of the ontology, that
+	// extracted attributes are checked and coerced against before saving.
+	AttributeSchemas map[string]map[string]interface{} `json:"attribute_schemas,omitempty"`
+	// InvalidAttributes handles attributes that fail their schema: "reject"
+	// (default) leaves them out, "flag" keeps them and lists their errors in
+	// the entity's "invalid_attributes" attribute.
+	InvalidAttributes string `json:"invalid_attributes,omitempty"`
+	// Limits override the [group_limits] of the configuration; zero fields
+	// keep the configured values.
+	Limits *GroupLimits `json:"limits,omitempty"`
+	// FactLifetimes add to and override the [ingest] fact_lifetimes of the
+	// configuration: hours, by relation type, that a fact stays current.
+	FactLifetimes map[string]int `json:"fact_lifetimes,omitempty"`
+	// Synonyms map terms such as abbreviations to what they stand for
+	// ("SF": "San Francisco"). Text search and entity linking treat either
+	// side as the other.
+	Synonyms map[string]string `json:"synonyms,omitempty"`
+	// Transforms name [[transforms]] of the configuration to run, in order,
+	// on the entities and facts extracted from the group's episodes.
+	Transforms []string `json:"transforms,omitempty"`
+	// Moderation overrides what [moderation] does with the group's episodes.
+	Moderation *ModerationPolicy `json:"moderation,omitempty"`
+}
+
+// GroupLimits cap the size of a group, checked before each episode is
+// ingested. Zero counts are unlimited.
+type GroupLimits struct {
+	MaxEntities int    `json:"max_entities,omitempty"`
+	MaxEdges    int    `json:"max_edges,omitempty"`
+	MaxEpisodes int    `json:"max_episodes,omitempty"`
+	OnLimit     string `json:"on_limit,omitempty"` // One of the LimitAction constants
+	// Pinned are entity UUIDs evict_oldest never removes, like the group's user entity.
+	Pinned []string `json:"pinned,omitempty"`
+}
+
+// What ingesting into a group at one of its limits does.
+const (
+	LimitActionReject      = "reject"
+	LimitActionEvictOldest = "evict_oldest"
+	LimitActionCompact     = "compact"
+)
+
+// How attributes failing their entity type's schema are handled.
+const (
+	InvalidAttributesReject = "reject"
+	InvalidAttributesFlag   = "flag"
+)
+
+// PromptOverrides mirrors the prompt templates in config.toml. Templates must keep
+// the same %s placeholders as the defaults they replace.
+type PromptOverrides struct {
+	ExtractNodes         string `json:"extract_nodes,omitempty"`
+	ExtractEdges         string `json:"extract_edges,omitempty"`
+	DedupeNodes          string `json:"dedupe_nodes,omitempty"`
+	DedupeEdges          string `json:"dedupe_edges,omitempty"`
+	SummarizeNodes       string `json:"summarize_nodes,omitempty"`
+	SummarizeCommunities string `json:"summarize_communities,omitempty"`
+	CommunityName        string `json:"community_name,omitempty"`
+	SummaryConsistency   string `json:"summary_consistency,omitempty"`
+	SummarizePath        string `json:"summarize_path,omitempty"`
+	VerifyFacts          string `json:"verify_facts,omitempty"`
+	DigestEpisodes       string `json:"digest_episodes,omitempty"`
+	SynthesizeProfile    string `json:"synthesize_profile,omitempty"`
+	GapQuestions         string `json:"gap_questions,omitempty"`
+}
+
+// GroupPatch is a partial update; nil fields are left unchanged.
+type GroupPatch struct {
+	Name     *string                `json:"name,omitempty"`
+	Owner    *string                `json:"owner,omitempty"`
+	Settings *GroupSettings         `json:"settings,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
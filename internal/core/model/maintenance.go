@@ -0,0 +1,122 @@
+package model
+
+import "time"
+
+const (
+	ReportKindConsistency    = "summary_consistency"
+	ReportKindDedupeEdges    = "dedupe_edges"
+	ReportKindOrphans        = "orphans"
+	ReportKindReembed        = "reembed"
+	ReportKindCompaction     = "compaction"
+	ReportKindProfile        = "profile"
+	ReportKindContradictions = "contradictions"
+)
+
+// EpisodeSourceDigest is the source of episodes written by compaction.
+const EpisodeSourceDigest = "digest"
+
+// EpisodeSourceStructured is the source of episodes ingested from structured input.
+const EpisodeSourceStructured = "structured"
+
+const (
+	OrphanModeQuarantine = "quarantine"
+	OrphanModeDelete     = "delete"
+)
+
+// ConsistencyReport is the result of checking entity summaries against their valid facts.
+type ConsistencyReport struct {
+	GroupID      string              `json:"group_id"`
+	CheckedAt    time.Time           `json:"checked_at"`
+	Checked      int                 `json:"checked"`
+	Inconsistent int                 `json:"inconsistent"`
+	Regenerated  int                 `json:"regenerated"`
+	Failed       int                 `json:"failed"`
+	Entities     []EntityConsistency `json:"entities"` // Only flagged or failed entities
+}
+
+type EntityConsistency struct {
+	UUID        string   `json:"uuid"`
+	Name        string   `json:"name"`
+	Summary     string   `json:"summary"`
+	Issues      []string `json:"issues,omitempty"`
+	Regenerated bool     `json:"regenerated"`
+	NewSummary  string   `json:"new_summary,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// ContradictionReport lists the pairs of valid facts a contradiction scan
+// judged mutually inconsistent.
+type ContradictionReport struct {
+	GroupID        string          `json:"group_id"`
+	ScannedAt      time.Time       `json:"scanned_at"`
+	Checked        int             `json:"checked"` // Entities whose facts were compared
+	Failed         int             `json:"failed"`  // Entities whose check failed
+	Contradictions []Contradiction `json:"contradictions"`
+}
+
+// Contradiction is two facts that can't both be true.
+type Contradiction struct {
+	Facts  []EntityEdge `json:"facts"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// DedupeEdgesReport lists the duplicate RELATES_TO edges merged in a group.
+type DedupeEdgesReport struct {
+	GroupID string       `json:"group_id"`
+	RanAt   time.Time    `json:"ran_at"`
+	DryRun  bool         `json:"dry_run"`
+	Scanned int          `json:"scanned"` // Active edges examined
+	Removed int          `json:"removed"` // Duplicate edges deleted (or that would be, on a dry run)
+	Merged  []MergedEdge `json:"merged"`
+}
+
+// MergedEdge is one fact whose duplicates were folded into the oldest edge.
+type MergedEdge struct {
+	KeptUUID     string   `json:"kept_uuid"`
+	RemovedUUIDs []string `json:"removed_uuids"`
+	SourceUUID   string   `json:"source_node_uuid"`
+	TargetUUID   string   `json:"target_node_uuid"`
+	Name         string   `json:"name"`
+	Fact         string   `json:"fact"`
+	Episodes     []string `json:"episodes"` // Union of the merged edges' episodes
+}
+
+// OrphanReport lists the entities collected by an orphan GC run.
+type OrphanReport struct {
+	GroupID string       `json:"group_id"`
+	RanAt   time.Time    `json:"ran_at"`
+	Mode    string       `json:"mode"`
+	DryRun  bool         `json:"dry_run"`
+	Orphans []EntityNode `json:"orphans"`
+}
+
+// CompactionReport lists the digest episodes a compaction run wrote.
+type CompactionReport struct {
+	GroupID string          `json:"group_id"`
+	RanAt   time.Time       `json:"ran_at"`
+	DryRun  bool            `json:"dry_run"`
+	Digests []EpisodeDigest `json:"digests"`
+}
+
+// EpisodeDigest is one run of consecutive episodes replaced by a digest.
+type EpisodeDigest struct {
+	UUID     string    `json:"uuid,omitempty"` // Digest episode; empty on a dry run
+	Episodes []string  `json:"episodes"`       // Replaced episodes, oldest first
+	From     time.Time `json:"from"`           // Time of the first replaced episode
+	To       time.Time `json:"to"`             // Time of the last replaced episode
+	Entities int       `json:"entities"`       // Entities the digest now mentions
+	Facts    int       `json:"facts"`          // Facts whose provenance moved to the digest
+}
+
+// ReembedReport counts the embeddings a reembed run migrated to a model.
+type ReembedReport struct {
+	GroupID     string    `json:"group_id"`
+	RanAt       time.Time `json:"ran_at"`
+	Model       string    `json:"model"` // Target embedding model
+	DryRun      bool      `json:"dry_run"`
+	Entities    int       `json:"entities"`    // Stale entity name embeddings found
+	Communities int       `json:"communities"` // Stale community name embeddings found
+	Facts       int       `json:"facts"`       // Stale fact embeddings found
+	Failed      int       `json:"failed"`      // Embeddings that could not be computed
+	Promoted    bool      `json:"promoted"`    // Search switched to the new vectors; false when any failed
+}
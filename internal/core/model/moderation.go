@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// What moderation does with a flagged episode.
+const (
+	ModerationBlock = "block" // Reject the episode
+	ModerationFlag  = "flag"  // Ingest the episode
+	ModerationOff   = "off"   // Don't check the group's episodes
+)
+
+// ModerationPolicy is a group's moderation setting.
+type ModerationPolicy struct {
+	// Action is one of the Moderation constants; empty keeps [moderation] action.
+	Action string `json:"action,omitempty"`
+	// Categories, when set, are the only categories acted on; episodes
+	// flagged only for others are ingested without a record.
+	Categories []string `json:"categories,omitempty"`
+}
+
+// ModerationRecord is the audit record of a flagged episode. The episode's
+// content is not kept, only its SHA-256.
+type ModerationRecord struct {
+	UUID          string    `json:"uuid" db:"uuid"`
+	GroupID       string    `json:"group_id" db:"group_id"`
+	EpisodeName   string    `json:"episode_name" db:"episode_name"`
+	EpisodeUUID   string    `json:"episode_uuid,omitempty" db:"episode_uuid"` // Set for flagged episodes that were ingested
+	Action        string    `json:"action" db:"action"`                       // ModerationBlock or ModerationFlag
+	Categories    []string  `json:"categories" db:"categories"`
+	ContentSHA256 string    `json:"content_sha256" db:"content_sha256"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
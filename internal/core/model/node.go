@@ -3,47 +3,89 @@ package model
 import "time"
 
 type EntityNode struct {
-	UUID          string                 `json:"uuid"`
-	Name          string                 `json:"name"`
-	GroupID       string                 `json:"group_id"`
-	CreatedAt     time.Time              `json:"created_at"`
-	Summary       string                 `json:"summary,omitempty"`
+	UUID          string                 `json:"uuid" db:"uuid"`
+	Name          string                 `json:"name" db:"name"`
+	GroupID       string                 `json:"group_id" db:"group_id"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+	Summary       string                 `json:"summary,omitempty" db:"summary"`
 	Attributes    map[string]interface{} `json:"attributes,omitempty"`
-	Labels        []string               `json:"labels"`
-	NameEmbedding []float32              `json:"name_embedding,omitempty"`
+	Labels        []string               `json:"labels" db:"labels"`
+	NameEmbedding []float32              `json:"name_embedding,omitempty" db:"name_embedding"`
+	Version       int                    `json:"version" db:"version"` // Bumped by every write of the entity
+}
+
+// EntityPatch is a partial update of an entity; nil fields are left
+// unchanged. Attributes are merged into the entity's, a null value removing
+// the attribute. With Version set, the update only applies if the entity is
+// still at that version.
+type EntityPatch struct {
+	Name       *string                `json:"name,omitempty"`
+	Summary    *string                `json:"summary,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Version    *int                   `json:"version,omitempty"`
 }
 
 type EpisodicNode struct {
-	UUID              string    `json:"uuid"`
-	Name              string    `json:"name"`
-	GroupID           string    `json:"group_id"`
-	CreatedAt         time.Time `json:"created_at"`
-	ValidAt           time.Time `json:"valid_at"`
-	Content           string    `json:"content"`
-	Source            string    `json:"source"`
-	SourceDescription string    `json:"source_description"`
-	EntityEdges       []string  `json:"entity_edges"` // List of Edge UUIDs
+	UUID              string    `json:"uuid" db:"uuid"`
+	Name              string    `json:"name" db:"name"`
+	GroupID           string    `json:"group_id" db:"group_id"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	ValidAt           time.Time `json:"valid_at" db:"valid_at"`
+	Content           string    `json:"content" db:"content"`
+	Source            string    `json:"source" db:"source"`
+	SourceDescription string    `json:"source_description" db:"source_description"`
+	EntityEdges       []string  `json:"entity_edges" db:"entity_edges"` // List of Edge UUIDs
+	Topics            []string  `json:"topics,omitempty" db:"topics"`   // Set by [ingest] topics
+	// ContentRef is the content store key of offloaded content, which listings leave out of Content.
+	ContentRef string `json:"content_ref,omitempty" db:"content_ref"`
 }
 
 type CommunityNode struct {
-	UUID          string    `json:"uuid"`
-	Name          string    `json:"name"`
-	GroupID       string    `json:"group_id"`
-	CreatedAt     time.Time `json:"created_at"`
-	Summary       string    `json:"summary"`
-	NameEmbedding []float32 `json:"name_embedding,omitempty"`
+	UUID          string    `json:"uuid" db:"uuid"`
+	Name          string    `json:"name" db:"name"`
+	GroupID       string    `json:"group_id" db:"group_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	Summary       string    `json:"summary" db:"summary"`
+	NameEmbedding []float32 `json:"name_embedding,omitempty" db:"name_embedding"`
 }
 
 type SagaNode struct {
-	UUID      string    `json:"uuid"`
-	Name      string    `json:"name"`
-	GroupID   string    `json:"group_id"`
-	CreatedAt time.Time `json:"created_at"`
+	UUID      string    `json:"uuid" db:"uuid"`
+	Name      string    `json:"name" db:"name"`
+	GroupID   string    `json:"group_id" db:"group_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 type EpisodeData struct {
-	Content string `json:"content"`
+	Content string `json:"content" binding:"required"`
 	Saga    string `json:"saga,omitempty"`
 	Schema  string `json:"schema,omitempty"`
 	Source  string `json:"source,omitempty"`
 }
+
+// How episode content over the [ingest] limits is handled.
+const (
+	OverflowReject   = "reject"
+	OverflowTruncate = "truncate"
+	OverflowChunk    = "chunk"
+)
+
+// How previous episodes are picked as extraction context.
+const (
+	ContextRecent   = "recent"
+	ContextRelevant = "relevant"
+)
+
+// What ingest does with an episode that repeats a recent one.
+const (
+	DuplicatesIngest = "ingest"
+	DuplicatesSkip   = "skip"
+	DuplicatesLink   = "link"
+)
+
+// UserProfile is what a request knows about the person speaking in its
+// episodes, applied to the group's user entity.
+type UserProfile struct {
+	Name       string                 `json:"name,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
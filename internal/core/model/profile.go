@@ -0,0 +1,13 @@
+package model
+
+// IngestProfile reports how long each stage of the ingestion pipeline took
+// for one episode, in the order the stages ran: "queue" (waiting for an
+// extraction worker), "save_episode", "tag_topics", "load_context", "resolve_coreferences",
+// "extract_nodes", "dedupe_nodes", "save_nodes", "extract_edges",
+// "verify_facts", "resolve_edges" and "summarize". Stages that didn't run are
+// left out.
+type IngestProfile struct {
+	Stages     []StageTiming `json:"stages"`
+	DurationMS float64       `json:"duration_ms"` // The whole ingest, including untimed work between stages
+	Error      string        `json:"error,omitempty"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// SynthesizedProfile is an overview of a group's user that the LLM writes from
+// the group's most salient entities and facts. Unlike UserProfile, which a
+// client sends, it is derived from the graph, and kept until refreshed.
+type SynthesizedProfile struct {
+	GroupID       string    `json:"group_id"`
+	Preferences   []string  `json:"preferences"`
+	Facts         []string  `json:"facts"`
+	Relationships []string  `json:"relationships"`
+	OpenQuestions []string  `json:"open_questions"` // What the graph doesn't say yet, as questions to ask
+	GeneratedAt   time.Time `json:"generated_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ScratchEntry is a value an agent keeps in its group's scratchpad: short-term
+// state stored beside the knowledge graph but never extracted or searched.
+type ScratchEntry struct {
+	GroupID   string      `json:"group_id"`
+	Key       string      `json:"key" db:"key"`
+	Value     interface{} `json:"value"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty" db:"expires_at"`
+}
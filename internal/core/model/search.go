@@ -1,5 +1,10 @@
 package model
 
+import (
+	"fmt"
+	"time"
+)
+
 type SearchResult struct {
 	UUID       string    `json:"uuid"`
 	Name       string    `json:"name"`
@@ -11,5 +16,140 @@ type SearchResult struct {
 
 type BulkSearchQuery struct {
 	QueryID string `json:"query_id"`
-	Query   string `json:"query"`
+	Query   string `json:"query" binding:"required"`
+}
+
+// UnionSearchResult is a fact returned by one or more queries of a bulk search
+// run in union mode. Queries lists their IDs in request order.
+type UnionSearchResult struct {
+	EntityEdge
+	Queries []string `json:"queries"`
+}
+
+// FactPath is a chain of valid facts linking two query-relevant entities.
+// Edges[i] joins Nodes[i] and Nodes[i+1]; its SourceUUID gives the fact's direction.
+type FactPath struct {
+	Nodes   []PathNode   `json:"nodes"`
+	Edges   []EntityEdge `json:"edges"`
+	Chain   string       `json:"chain"`             // e.g. "Alice -WORKS_AT-> Google -LOCATED_IN-> Mountain View"
+	Summary string       `json:"summary,omitempty"` // LLM explanation of the chain, when a path prompt is configured
+}
+
+// Coverage tells an agent whether a search found anything it can rely on.
+type Coverage struct {
+	Best       float64 `json:"best"`       // Highest confidence of a result; 0 without results
+	Relevant   int     `json:"relevant"`   // Results at or above the relevance threshold
+	Answerable bool    `json:"answerable"` // Some result is relevant
+}
+
+// EntityResults are the facts a search found about one subject entity, the
+// source of each fact.
+type EntityResults struct {
+	UUID    string       `json:"uuid"`
+	Name    string       `json:"name"`
+	Summary string       `json:"summary,omitempty"`
+	Facts   []EntityEdge `json:"facts"`
+}
+
+type PathNode struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// Similarity metrics of vector search. Scores are higher for closer vectors
+// under every metric; euclidean scores are 1 / (1 + distance).
+const (
+	MetricCosine    = "cosine"
+	MetricDot       = "dot"
+	MetricEuclidean = "euclidean"
+)
+
+// SearchFilter narrows search results. Every set field must match:
+// RelationTypes and EntityLabels match any listed value, Attributes must all
+// equal the value of a top-level attribute on the fact's source or target
+// entity, and date ranges are inclusive of From and exclusive of To.
+// Metric and MinScore override the [search] similarity settings for vector
+// matches, HyDE the [search] hyde setting, and RerankDepth and
+// RerankSkipMargin the [search] settings of the same names.
+type SearchFilter struct {
+	RelationTypes []string               `json:"relation_types,omitempty"`
+	EntityLabels  []string               `json:"entity_labels,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	ValidAt       *DateRange             `json:"valid_at,omitempty"`
+	CreatedAt     *DateRange             `json:"created_at,omitempty"`
+	Metric        string                 `json:"metric,omitempty"`    // "cosine", "dot" or "euclidean"
+	MinScore      *float64               `json:"min_score,omitempty"` // Drop vector matches scoring below this
+	IncludeExpired bool                  `json:"include_expired,omitempty"` // Also match facts past their expired_at
+	HyDE          *bool                  `json:"hyde,omitempty"` // Search by the embedding of a hypothetical answer
+	RerankDepth   *int                   `json:"rerank_depth,omitempty"`       // Rerank only this many top candidates; 0 reranks all
+	RerankSkipMargin *float64            `json:"rerank_skip_margin,omitempty"` // Skip reranking when the top vector score leads the second by this much; 0 never skips
+	Topics        []string               `json:"topics,omitempty"` // Only facts stated by an episode tagged with one of these topics
+	Sentiments    []string               `json:"sentiments,omitempty"` // Only facts the user feels one of these ways about ([ingest] sentiment)
+	Emotions      []string               `json:"emotions,omitempty"`   // Only facts annotated with one of these emotions, e.g. "likes"
+}
+
+type DateRange struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// ValidMetric reports whether metric names a similarity metric; empty selects the default.
+func ValidMetric(metric string) bool {
+	switch metric {
+	case "", MetricCosine, MetricDot, MetricEuclidean:
+		return true
+	}
+	return false
+}
+
+// Validate rejects empty date ranges, unknown metrics and sentiments, and negative rerank limits.
+func (f *SearchFilter) Validate() error {
+	if !ValidMetric(f.Metric) {
+		return fmt.Errorf("invalid metric '%s': must be cosine, dot or euclidean", f.Metric)
+	}
+	if (f.RerankDepth != nil && *f.RerankDepth < 0) || (f.RerankSkipMargin != nil && *f.RerankSkipMargin < 0) {
+		return fmt.Errorf("invalid rerank limits: rerank_depth and rerank_skip_margin must not be negative")
+	}
+	for name, r := range map[string]*DateRange{"valid_at": f.ValidAt, "created_at": f.CreatedAt} {
+		if r != nil && r.From != nil && r.To != nil && !r.From.Before(*r.To) {
+			return fmt.Errorf("invalid %s range: from must be before to", name)
+		}
+	}
+	for _, sentiment := range f.Sentiments {
+		if !ValidSentiment(sentiment) {
+			return fmt.Errorf("invalid sentiment '%s': must be positive, negative or neutral", sentiment)
+		}
+	}
+	return nil
+}
+
+// SearchTrace explains how a search produced its results, so callers can see
+// why a fact did or didn't surface.
+type SearchTrace struct {
+	Strategy        string            `json:"strategy"`                   // "vector" or "text"
+	EmbeddingModels []string          `json:"embedding_models,omitempty"` // Models whose vectors were queried, active first
+	Metric          string            `json:"metric,omitempty"`
+	MinScore        *float64          `json:"min_score,omitempty"`
+	Candidates      []SearchCandidate `json:"candidates"`                // Raw matches in retrieval order
+	Filtered        []string          `json:"filtered,omitempty"`        // Candidates dropped by the attribute filter
+	Reranked        []string          `json:"reranked,omitempty"`        // Fact UUIDs in reranker order; empty when it didn't run
+	RerankError     string            `json:"rerank_error,omitempty"`    // Why reranking was skipped
+	RerankCached    bool              `json:"rerank_cached,omitempty"`   // The reranker's order came from the rerank cache
+	RerankSkipped   bool              `json:"rerank_skipped,omitempty"`  // The top vector score led by the skip margin
+	LinkedEntities  []string          `json:"linked_entities,omitempty"` // Entities named in the query; their facts rank first
+	Hypothetical    string            `json:"hypothetical,omitempty"`    // Passage embedded instead of the query under HyDE
+	Results         []string          `json:"results"`                   // Final order of fact UUIDs
+	Timings         []StageTiming     `json:"timings"`
+}
+
+type SearchCandidate struct {
+	UUID           string   `json:"uuid"`
+	Fact           string   `json:"fact"`
+	Score          *float64 `json:"score,omitempty"`           // Similarity under Metric; absent for text matches
+	EmbeddingModel string   `json:"embedding_model,omitempty"` // Query embedding that matched it
+}
+
+type StageTiming struct {
+	Stage      string  `json:"stage"` // "embed", "retrieve", "filter", "rerank" or "link" for searches
+	DurationMS float64 `json:"duration_ms"`
 }
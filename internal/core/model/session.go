@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Session is a conversation whose messages are ingested, in order, as the
+// episodes of one saga. Its ID is the saga's UUID.
+type Session struct {
+	ID        string    `json:"id" db:"uuid"`
+	GroupID   string    `json:"group_id" db:"group_id"`
+	Name      string    `json:"name" db:"name"` // The saga's name
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type GroupStats struct {
+	GroupID       string     `json:"group_id"`
+	Entities      int        `json:"entities" db:"entities"`
+	Edges         int        `json:"edges" db:"edges"`
+	ValidEdges    int        `json:"valid_edges" db:"valid_edges"`
+	InvalidEdges  int        `json:"invalid_edges"`
+	Episodes      int        `json:"episodes" db:"episodes"`
+	Sagas         int        `json:"sagas" db:"sagas"`
+	Communities   int        `json:"communities" db:"communities"`
+	AverageDegree float64    `json:"average_degree"` // Valid edges per entity, counting both endpoints
+	LastEntityAt  *time.Time `json:"last_entity_at,omitempty" db:"last_entity_at"`
+	LastEdgeAt    *time.Time `json:"last_edge_at,omitempty" db:"last_edge_at"`
+	LastEpisodeAt *time.Time `json:"last_episode_at,omitempty" db:"last_episode_at"`
+	LastUpdatedAt *time.Time `json:"last_updated_at,omitempty"`
+}
+
+// EntitySalience is an entity with the signals ranking it among its group's
+// entities. Score averages Degree and Mentions, each relative to the group's
+// highest, with how recently the entity was seen relative to the group's
+// oldest and newest; it ranges from 0 to 1.
+type EntitySalience struct {
+	UUID       string     `json:"uuid"`
+	Name       string     `json:"name"`
+	Summary    string     `json:"summary,omitempty"`
+	Labels     []string   `json:"labels"`
+	Degree     int        `json:"degree"`   // Currently valid facts about the entity
+	Mentions   int        `json:"mentions"` // Episodes mentioning the entity
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	Score      float64    `json:"score"`
+}
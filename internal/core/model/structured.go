@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// StructuredEpisode is pre-structured input ingested without the LLM: its
+// entities and facts are taken as given. Facts name their entities by the
+// Name of one of Entities.
+type StructuredEpisode struct {
+	Name     string             `json:"name"`
+	Content  string             `json:"content"` // Source text kept as the episode's content; the facts when empty
+	Saga     string             `json:"saga"`
+	Entities []StructuredEntity `json:"entities" binding:"required,min=1,dive"`
+	Facts    []StructuredFact   `json:"facts" binding:"dive"`
+}
+
+type StructuredEntity struct {
+	Name       string                 `json:"name" binding:"required"`
+	Type       string                 `json:"type"` // Entity type, added as a label
+	Summary    string                 `json:"summary"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type StructuredFact struct {
+	Source     string                 `json:"source" binding:"required"` // Entity name
+	Target     string                 `json:"target" binding:"required"` // Entity name
+	Relation   string                 `json:"relation" binding:"required"`
+	Fact       string                 `json:"fact" binding:"required"`
+	ValidAt    *time.Time             `json:"valid_at,omitempty"` // When the fact became true; ingestion time when unset
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// Exclusive invalidates the source's valid facts of the same relation
+	// type with other targets, as a new "lives in" replaces the old one.
+	Exclusive bool `json:"exclusive,omitempty"`
+}
+
+// StructuredResult identifies what a structured episode was saved as.
+type StructuredResult struct {
+	EpisodeUUID string            `json:"episode_uuid"`
+	Entities    map[string]string `json:"entities"` // Entity name -> UUID, existing entities reused
+}
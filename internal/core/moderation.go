@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+)
+
+var ErrContentBlocked = errors.New("episode content blocked by moderation")
+
+const (
+	ModerationKeywords = "keywords"
+	ModerationOpenAI   = "openai"
+)
+
+// newModerator builds the Moderator [moderation] provider selects, or returns
+// nil when moderation is off.
+func newModerator(cfg *config.Config) llm.Moderator {
+	m := cfg.Moderation
+	switch m.Provider {
+	case ModerationKeywords:
+		return newKeywordModerator(m.Keywords)
+	case ModerationOpenAI:
+		apiKey, baseURL := m.APIKey, m.BaseURL
+		if apiKey == "" {
+			apiKey = cfg.LLM.APIKey
+		}
+		if baseURL == "" {
+			baseURL = cfg.LLM.BaseURL
+		}
+		return llm.NewOpenAIModerator(apiKey, m.Model, baseURL)
+	case "":
+		return nil
+	default:
+		log.Printf("Warning: unknown [moderation] provider %q; episodes are not moderated", m.Provider)
+		return nil
+	}
+}
+
+// keywordModerator flags text containing any of a category's keywords as a
+// whole word or phrase, ignoring case.
+type keywordModerator struct {
+	categories []string
+	patterns   map[string][]*regexp.Regexp
+}
+
+func newKeywordModerator(keywords map[string][]string) *keywordModerator {
+	m := &keywordModerator{patterns: make(map[string][]*regexp.Regexp, len(keywords))}
+	for category, words := range keywords {
+		for _, w := range words {
+			if w = strings.TrimSpace(w); w != "" {
+				m.patterns[category] = append(m.patterns[category], regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(w)+`\b`))
+			}
+		}
+		m.categories = append(m.categories, category)
+	}
+	sort.Strings(m.categories)
+	return m
+}
+
+func (m *keywordModerator) Moderate(ctx context.Context, text string) ([]string, error) {
+	var flagged []string
+	for _, category := range m.categories {
+		for _, p := range m.patterns[category] {
+			if p.MatchString(text) {
+				flagged = append(flagged, category)
+				break
+			}
+		}
+	}
+	return flagged, nil
+}
+
+// moderationVerdict is what moderation decided about one episode.
+type moderationVerdict struct {
+	action     string // model.ModerationBlock or model.ModerationFlag; empty lets the episode through unrecorded
+	categories []string
+}
+
+// moderate checks content against the group's moderation policy. Blocked
+// episodes are recorded here and fail with ErrContentBlocked; flagged ones
+// are recorded by recordModeration once their episode is saved.
+func (g *Graphiti) moderate(ctx context.Context, group *model.GroupNode, name, content string) (moderationVerdict, error) {
+	if g.Moderator == nil {
+		return moderationVerdict{}, nil
+	}
+	action := model.ModerationBlock
+	if g.Config != nil && g.Config.Moderation.Action != "" {
+		action = g.Config.Moderation.Action
+	}
+	var only []string
+	if p := group.Settings.Moderation; p != nil {
+		if p.Action != "" {
+			action = p.Action
+		}
+		only = p.Categories
+	}
+	if action == model.ModerationOff {
+		return moderationVerdict{}, nil
+	}
+
+	defer timeStage(ctx, "moderate")()
+	categories, err := g.Moderator.Moderate(ctx, content)
+	if err != nil {
+		return moderationVerdict{}, fmt.Errorf("moderation failed: %w", err)
+	}
+	if len(only) > 0 {
+		categories = slices.DeleteFunc(categories, func(c string) bool { return !slices.Contains(only, c) })
+	}
+	if len(categories) == 0 {
+		return moderationVerdict{}, nil
+	}
+	verdict := moderationVerdict{action: action, categories: categories}
+	if action == model.ModerationBlock {
+		if err := g.recordModeration(ctx, group.GroupID, name, "", content, verdict); err != nil {
+			return verdict, err
+		}
+		return verdict, fmt.Errorf("%w: %s", ErrContentBlocked, strings.Join(categories, ", "))
+	}
+	return verdict, nil
+}
+
+// recordModeration keeps the audit record of a blocked or flagged episode.
+func (g *Graphiti) recordModeration(ctx context.Context, groupID, name, episodeUUID, content string, verdict moderationVerdict) error {
+	if verdict.action == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(content))
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveModerationRecordQuery, map[string]interface{}{
+		"uuid":           g.UUIDGenerator(),
+		"group_id":       groupID,
+		"episode_name":   name,
+		"episode_uuid":   episodeUUID,
+		"action":         verdict.action,
+		"categories":     verdict.categories,
+		"content_sha256": hex.EncodeToString(sum[:]),
+		"created_at":     time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save moderation record: %w", err)
+	}
+	return nil
+}
+
+// ListModerationRecords returns the group's limit most recent moderation
+// records, newest first.
+func (g *Graphiti) ListModerationRecords(ctx context.Context, groupID string, limit int) ([]model.ModerationRecord, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.ListModerationRecordsQuery, map[string]interface{}{
+		"group_id": groupID,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moderation records: %w", err)
+	}
+	records, err := driver.ScanRecords[model.ModerationRecord](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation records: %w", err)
+	}
+	return records, nil
+}
+
+func validModerationPolicy(p *model.ModerationPolicy) bool {
+	if p == nil {
+		return true
+	}
+	switch p.Action {
+	case "", model.ModerationBlock, model.ModerationFlag, model.ModerationOff:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeration(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		return `{"extracted_entities": []}`
+	}), nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s"},
+		Moderation: config.ModerationConfig{Provider: ModerationKeywords, Keywords: map[string][]string{
+			"violence": {"kill"},
+			"spam":     {"buy now"},
+		}},
+	})
+	episodeCount := func(group string) int {
+		episodes, err := g.GetEpisodes(ctx, group, 10)
+		require.NoError(t, err)
+		return len(episodes)
+	}
+
+	// Blocked by default, kept neither as an episode nor as a dead letter
+	err := g.AddEpisode(ctx, "g1", "message", "I will kill the process.", "", "")
+	assert.ErrorIs(t, err, ErrContentBlocked)
+	assert.Zero(t, episodeCount("g1"))
+	letters, err := g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Skills matter.", "", ""), "keywords match whole words")
+
+	// A group that flags instead, and only acts on spam
+	_, err = g.UpdateGroup(ctx, "g2", model.GroupPatch{Settings: &model.GroupSettings{
+		Moderation: &model.ModerationPolicy{Action: model.ModerationFlag, Categories: []string{"spam"}},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, g.AddEpisode(ctx, "g2", "message", "Buy now!", "", ""))
+	require.NoError(t, g.AddEpisode(ctx, "g2", "message", "I will kill the process.", "", ""))
+	assert.Equal(t, 2, episodeCount("g2"))
+
+	records, err := g.ListModerationRecords(ctx, "g1", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, model.ModerationBlock, records[0].Action)
+	assert.Equal(t, []string{"violence"}, records[0].Categories)
+	assert.Empty(t, records[0].EpisodeUUID)
+	assert.Len(t, records[0].ContentSHA256, 64)
+
+	records, err = g.ListModerationRecords(ctx, "g2", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, model.ModerationFlag, records[0].Action)
+	assert.Equal(t, []string{"spam"}, records[0].Categories)
+	assert.NotEmpty(t, records[0].EpisodeUUID)
+
+	_, err = g.UpdateGroup(ctx, "g3", model.GroupPatch{Settings: &model.GroupSettings{
+		Moderation: &model.ModerationPolicy{Action: "warn"},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
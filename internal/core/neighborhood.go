@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+const (
+	DefaultNeighborhoodDepth = 2
+	// MaxNeighborhoodDepth bounds the expansion, since every hop can multiply the subgraph.
+	MaxNeighborhoodDepth = 3
+)
+
+// GetNeighborhood returns the entities within depth hops of uuid over currently
+// valid facts, the facts followed to reach them, and their communities. It
+// expands one hop per query, so only the neighborhood is read, never the
+// whole group. A missing entity is ErrEntityNotFound.
+func (g *Graphiti) GetNeighborhood(ctx context.Context, uuid string, depth int) (*model.Neighborhood, error) {
+	center, err := g.GetEntity(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	depth = max(0, min(depth, MaxNeighborhoodDepth))
+	access := g.accessFilter(ctx)
+
+	hops := map[string]int{uuid: 0}
+	seen := make(map[string]bool)
+	edges := []model.EntityEdge{}
+	frontier := []string{uuid}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		hopEdges, err := g.neighborEdges(ctx, center.GroupID, frontier)
+		if err != nil {
+			return nil, err
+		}
+		var next []string
+		for _, e := range access.edges(hopEdges) {
+			if seen[e.UUID] {
+				continue
+			}
+			seen[e.UUID] = true
+			edges = append(edges, e)
+			for _, n := range []string{e.SourceUUID, e.TargetUUID} {
+				if _, ok := hops[n]; !ok {
+					hops[n] = d
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	nodes := []model.NeighborhoodNode{{Entity: *center, Hops: 0}}
+	if len(hops) > 1 {
+		others := make([]string, 0, len(hops)-1)
+		for n := range hops {
+			if n != uuid {
+				others = append(others, n)
+			}
+		}
+		entities, err := g.entityNodes(ctx, others)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range entities {
+			n.Attributes = access.attrs(n.Attributes)
+			nodes = append(nodes, model.NeighborhoodNode{Entity: n, Hops: hops[n.UUID]})
+		}
+		sort.SliceStable(nodes[1:], func(i, j int) bool {
+			a, b := nodes[1+i], nodes[1+j]
+			if a.Hops != b.Hops {
+				return a.Hops < b.Hops
+			}
+			return a.Entity.Name < b.Entity.Name
+		})
+	}
+
+	members := make([]string, len(nodes))
+	for i, n := range nodes {
+		members[i] = n.Entity.UUID
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityCommunitiesQuery, map[string]interface{}{
+		"uuids": members,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch communities: %w", err)
+	}
+	communities, err := driver.ScanRecords[model.NeighborhoodCommunity](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read communities: %w", err)
+	}
+	if communities == nil {
+		communities = []model.NeighborhoodCommunity{}
+	}
+
+	return &model.Neighborhood{Center: uuid, Depth: depth, Nodes: nodes, Edges: edges, Communities: communities}, nil
+}
+
+// neighborEdges returns the group's valid facts touching any of uuids.
+func (g *Graphiti) neighborEdges(ctx context.Context, groupID string, uuids []string) ([]model.EntityEdge, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetNeighborEdgesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"uuids":    uuids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch neighbor facts: %w", err)
+	}
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read neighbor facts: %w", err)
+	}
+	for i := range res.Records {
+		if err := decodeJSONField(res.Records[i], "attributes", &edges[i].Attributes); err != nil {
+			return nil, fmt.Errorf("invalid attributes for fact %s: %w", edges[i].UUID, err)
+		}
+		edges[i].GroupID = groupID
+	}
+	return edges, nil
+}
+
+// entityNodes returns the entities with the given UUIDs, skipping missing ones.
+func (g *Graphiti) entityNodes(ctx context.Context, uuids []string) ([]model.EntityNode, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntityNodesQuery, map[string]interface{}{
+		"uuids": uuids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entities: %w", err)
+	}
+	nodes, err := driver.ScanRecords[model.EntityNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entities: %w", err)
+	}
+	for i := range res.Records {
+		if err := decodeJSONField(res.Records[i], "attributes", &nodes[i].Attributes); err != nil {
+			return nil, fmt.Errorf("invalid attributes for entity %s: %w", nodes[i].UUID, err)
+		}
+		if err := g.decryptAttributes(nodes[i].UUID, nodes[i].Attributes); err != nil {
+			return nil, fmt.Errorf("failed to read entity %s: %w", nodes[i].UUID, err)
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNeighborhood(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{})
+
+	// a -> b -> c -> d, an invalidated a -> e, and x in another group
+	seedEntities(t, d, "g1", "a", "b", "c", "d", "e")
+	seedEntities(t, d, "g2", "x")
+	for _, e := range [][4]string{{"ab", "a", "b", ""}, {"bc", "b", "c", ""}, {"cd", "c", "d", ""}, {"ae", "a", "e", "2024-01-01T00:00:00Z"}} {
+		seedFact(t, d, "g1", e[0], e[1], e[2], "LINKS", e[0], map[string]interface{}{"invalid_at": e[3]})
+	}
+	_, err := d.ExecuteQuery(ctx, driver.SaveCommunityNodeQuery, map[string]interface{}{"uuid": "c1", "name": "Team", "group_id": "g1"})
+	require.NoError(t, err)
+	for _, m := range []string{"b", "d"} {
+		_, err := d.ExecuteQuery(ctx, driver.SaveCommunityEdgeQuery, map[string]interface{}{
+			"uuid": "c1-" + m, "source_uuid": "c1", "target_uuid": m, "group_id": "g1",
+		})
+		require.NoError(t, err)
+	}
+
+	hood, err := g.GetNeighborhood(ctx, "b", 1)
+	require.NoError(t, err)
+	var names []string
+	for _, n := range hood.Nodes {
+		names = append(names, n.Entity.Name)
+	}
+	assert.Equal(t, []string{"b", "a", "c"}, names)
+	assert.Len(t, hood.Edges, 2)
+	require.Len(t, hood.Communities, 1)
+	assert.Equal(t, []string{"b"}, hood.Communities[0].Members, "only members inside the neighborhood")
+
+	hood, err = g.GetNeighborhood(ctx, "a", 2)
+	require.NoError(t, err)
+	hops := map[string]int{}
+	for _, n := range hood.Nodes {
+		hops[n.Entity.UUID] = n.Hops
+	}
+	assert.Equal(t, map[string]int{"a": 0, "b": 1, "c": 2}, hops, "invalidated facts are not followed")
+	assert.Len(t, hood.Edges, 2)
+
+	_, err = g.GetNeighborhood(ctx, "missing", 2)
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/attrschema"
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+var ErrInvalidGroupSettings = errors.New("invalid group settings")
+
+// entityType is an entity type of an ontology.
+type entityType struct {
+	ID   int
+	Name string
+}
+
+// numberedType matches an ontology entry carrying its own type ID: "1: Person".
+var numberedType = regexp.MustCompile(`^(\d+)\s*[:.)]\s*(.*)$`)
+
+// ontologyTypes lists the entity types of an ontology written as "Person,
+// Place" or one "1: Person - a human being" per line. Types are numbered from
+// 1 in order unless they carry their own number.
+func ontologyTypes(ontology string) []entityType {
+	var types []entityType
+	for _, entry := range strings.FieldsFunc(ontology, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		id := len(types) + 1
+		if m := numberedType.FindStringSubmatch(entry); m != nil {
+			id, _ = strconv.Atoi(m[1])
+			entry = m[2]
+		}
+		// Drop descriptions: "Person: a human", "Person - a human", "Person (a human)"
+		for _, sep := range []string{":", "(", " - "} {
+			if i := strings.Index(entry, sep); i >= 0 {
+				entry = entry[:i]
+			}
+		}
+		if name := strings.TrimSpace(entry); name != "" {
+			types = append(types, entityType{ID: id, Name: name})
+		}
+	}
+	return types
+}
+
+// entityTypeName resolves an extracted entity's type by its type name, else
+// by its type ID. It is empty when neither names a type of the ontology.
+func entityTypeName(e model.ExtractedEntity, types []entityType) string {
+	if e.EntityType != "" {
+		for _, t := range types {
+			if strings.EqualFold(t.Name, strings.TrimSpace(e.EntityType)) {
+				return t.Name
+			}
+		}
+		return ""
+	}
+	for _, t := range types {
+		if t.ID == e.EntityTypeID {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// checkAttributes applies the group's attribute schemas to extracted entities
+// of the ontology's types: attributes are coerced to their declared types, and
+// invalid ones left out or flagged as the group's settings say.
+func checkAttributes(extracted []model.ExtractedEntity, ontology string, settings model.GroupSettings) []model.ExtractedEntity {
+	if len(settings.AttributeSchemas) == 0 {
+		return extracted
+	}
+	types := ontologyTypes(ontology)
+	schemas := make(map[string]*attrschema.Schema)
+	checked := make([]model.ExtractedEntity, len(extracted))
+	for i, e := range extracted {
+		checked[i] = e
+		name := entityTypeName(e, types)
+		raw, ok := settings.AttributeSchemas[name]
+		if !ok || len(e.Attributes) == 0 {
+			continue
+		}
+		schema, compiled := schemas[name]
+		if !compiled {
+			var err error
+			if schema, err = attrschema.Compile(raw); err != nil {
+				log.Printf("Ignoring invalid attribute schema for %s: %v", name, err)
+			}
+			schemas[name] = schema
+		}
+		if schema == nil {
+			continue
+		}
+
+		valid, invalid := schema.CheckAttributes(e.Attributes)
+		if len(invalid) == 0 {
+			checked[i].Attributes = valid
+			continue
+		}
+		errs := make(map[string]interface{}, len(invalid))
+		for attr, err := range invalid {
+			errs[attr] = err.Error()
+		}
+		if settings.InvalidAttributes == model.InvalidAttributesFlag {
+			for attr := range invalid {
+				valid[attr] = e.Attributes[attr]
+			}
+			valid["invalid_attributes"] = errs
+		} else {
+			log.Printf("Rejected attributes of %s %q: %v", name, e.Name, sortedValues(errs))
+		}
+		checked[i].Attributes = valid
+	}
+	return checked
+}
+
+// validateGroupSettings rejects attribute schemas that don't compile, unknown
+// invalid-attribute modes and moderation actions, and negative limits or fact
+// lifetimes.
+func validateGroupSettings(settings model.GroupSettings) error {
+	for name, raw := range settings.AttributeSchemas {
+		if _, err := attrschema.Compile(raw); err != nil {
+			return fmt.Errorf("%w: attribute schema for %s: %v", ErrInvalidGroupSettings, name, err)
+		}
+	}
+	switch settings.InvalidAttributes {
+	case "", model.InvalidAttributesReject, model.InvalidAttributesFlag:
+	default:
+		return fmt.Errorf("%w: invalid_attributes must be %q or %q", ErrInvalidGroupSettings, model.InvalidAttributesReject, model.InvalidAttributesFlag)
+	}
+	if l := settings.Limits; l != nil {
+		if l.MaxEntities < 0 || l.MaxEdges < 0 || l.MaxEpisodes < 0 {
+			return fmt.Errorf("%w: limits must not be negative", ErrInvalidGroupSettings)
+		}
+		if !validLimitAction(l.OnLimit) {
+			return fmt.Errorf("%w: limits.on_limit must be %q, %q or %q", ErrInvalidGroupSettings, model.LimitActionReject, model.LimitActionEvictOldest, model.LimitActionCompact)
+		}
+	}
+	if settings.RetentionDays < 0 {
+		return fmt.Errorf("%w: retention_days must not be negative", ErrInvalidGroupSettings)
+	}
+	for relation, hours := range settings.FactLifetimes {
+		if hours < 0 {
+			return fmt.Errorf("%w: fact lifetime for %s must not be negative", ErrInvalidGroupSettings, relation)
+		}
+	}
+	if !validModerationPolicy(settings.Moderation) {
+		return fmt.Errorf("%w: moderation.action must be %q, %q or %q", ErrInvalidGroupSettings, model.ModerationBlock, model.ModerationFlag, model.ModerationOff)
+	}
+	return validateSynonyms(settings.Synonyms)
+}
+
+func sortedValues(m map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		values[i] = m[k]
+	}
+	return values
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOntologyTypes(t *testing.T) {
+	assert.Equal(t, []entityType{{1, "Person"}, {2, "Place"}, {3, "Non-profit"}},
+		ontologyTypes("Person, Place (a location), Non-profit"))
+	assert.Equal(t, []entityType{{3, "Customer"}, {7, "Product"}},
+		ontologyTypes("3: Customer - someone who buys\n7. Product: what they buy\n"))
+
+	types := ontologyTypes("Person, Place")
+	assert.Equal(t, "Place", entityTypeName(model.ExtractedEntity{EntityType: "place"}, types))
+	assert.Equal(t, "Person", entityTypeName(model.ExtractedEntity{EntityTypeID: 1}, types))
+	assert.Empty(t, entityTypeName(model.ExtractedEntity{EntityType: "Planet", EntityTypeID: 1}, types))
+}
+
+func TestAddEpisode_AttributeSchemas(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string {
+		return `{"extracted_entities": [
+			{"name": "Alice", "entity_type": "Person", "attributes": {"age": "28-year-old", "role": "intern"}},
+			{"name": "Acme", "entity_type": "Company", "attributes": {"age": "old"}}
+		]}`
+	})
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+	})
+	ctx := context.Background()
+
+	settings := model.GroupSettings{
+		Ontology: "Person, Company",
+		AttributeSchemas: map[string]map[string]interface{}{
+			"Person": {"properties": map[string]interface{}{
+				"age":  map[string]interface{}{"type": "integer"},
+				"role": map[string]interface{}{"enum": []interface{}{"engineer", "manager"}},
+			}},
+		},
+	}
+	attrs := func(groupID string) map[string]map[string]interface{} {
+		nodes, err := g.getGroupNodes(ctx, groupID)
+		require.NoError(t, err)
+		byName := make(map[string]map[string]interface{})
+		for _, n := range nodes {
+			node, err := g.GetEntity(ctx, n.UUID)
+			require.NoError(t, err)
+			byName[node.Name] = node.Attributes
+		}
+		return byName
+	}
+
+	_, err := g.UpdateGroup(ctx, "rejecting", model.GroupPatch{Settings: &settings})
+	require.NoError(t, err)
+	require.NoError(t, g.AddEpisode(ctx, "rejecting", "ep1", "Alice, 28, interns at Acme.", "", ""))
+	got := attrs("rejecting")
+	assert.Equal(t, map[string]interface{}{"age": 28.0}, got["Alice"])
+	// Company declares no schema
+	assert.Equal(t, map[string]interface{}{"age": "old"}, got["Acme"])
+
+	settings.InvalidAttributes = model.InvalidAttributesFlag
+	_, err = g.UpdateGroup(ctx, "flagging", model.GroupPatch{Settings: &settings})
+	require.NoError(t, err)
+	require.NoError(t, g.AddEpisode(ctx, "flagging", "ep1", "Alice, 28, interns at Acme.", "", ""))
+	assert.Equal(t, map[string]interface{}{
+		"age":                28.0,
+		"role":               "intern",
+		"invalid_attributes": map[string]interface{}{"role": `role: must be one of "engineer", "manager"`},
+	}, attrs("flagging")["Alice"])
+}
+
+func TestAddEpisode_LabelsEntitiesByType(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string {
+		return `{"extracted_entities": [
+			{"name": "Alice", "entity_type": "person"},
+			{"name": "Carbon", "entity_type_id": 2},
+			{"name": "Berlin", "entity_type": "Planet"}
+		]}`
+	})
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+	})
+	ctx := context.Background()
+	_, err := g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Ontology: "Person, software project"}})
+	require.NoError(t, err)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep1", "Alice works on Carbon in Berlin.", "", ""))
+
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	labels := make(map[string][]string)
+	for _, n := range nodes {
+		node, err := g.GetEntity(ctx, n.UUID)
+		require.NoError(t, err)
+		labels[node.Name] = node.Labels
+	}
+	assert.Equal(t, map[string][]string{
+		"Alice":  {"Entity", "Person"},
+		"Carbon": {"Entity", "SoftwareProject"},
+		"Berlin": {"Entity"},
+	}, labels)
+}
+
+func TestUpdateGroup_RejectsInvalidSchemas(t *testing.T) {
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	ctx := context.Background()
+
+	_, err := g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{
+		AttributeSchemas: map[string]map[string]interface{}{"Person": {"type": "person"}},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{InvalidAttributes: "ignore"}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// CollectOrphans removes or quarantines the group's Entity nodes that no
+// episode mentions and no valid fact touches, e.g. after episode deletion.
+// An empty mode uses the configured one. A dry run only lists the orphans.
+func (g *Graphiti) CollectOrphans(ctx context.Context, groupID, mode string, dryRun bool) (*model.OrphanReport, error) {
+	if mode == "" && g.Config != nil {
+		mode = g.Config.OrphanGC.Mode
+	}
+	if mode == "" {
+		mode = model.OrphanModeQuarantine
+	}
+	if mode != model.OrphanModeQuarantine && mode != model.OrphanModeDelete {
+		return nil, fmt.Errorf("unknown orphan gc mode '%s'", mode)
+	}
+	grace := 60 * time.Minute
+	if g.Config != nil && g.Config.OrphanGC.GraceMinutes > 0 {
+		grace = time.Duration(g.Config.OrphanGC.GraceMinutes) * time.Minute
+	}
+
+	now := time.Now().UTC()
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetOrphanEntitiesQuery, map[string]interface{}{
+		"group_id":       groupID,
+		"created_before": now.Add(-grace).Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphan entities: %w", err)
+	}
+	orphans, err := driver.ScanRecords[model.EntityNode](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read orphan entities: %w", err)
+	}
+
+	report := &model.OrphanReport{GroupID: groupID, RanAt: now, Mode: mode, DryRun: dryRun, Orphans: orphans}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, n := range orphans {
+		query, params := driver.DeleteEntityNodeQuery, map[string]interface{}{"uuid": n.UUID}
+		if mode == model.OrphanModeQuarantine {
+			query = driver.QuarantineEntityQuery
+			params["quarantined_at"] = now.Format(time.RFC3339)
+		}
+		res, err := g.Driver.ExecuteQuery(ctx, query, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s orphan %s: %w", mode, n.UUID, err)
+		}
+		// Quarantined entities leave the graph as far as readers can tell
+		if err := g.recordDeletions(ctx, model.ChangeKindEntity, res); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.saveReport(ctx, model.ReportKindOrphans, groupID, now, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetOrphanReport returns the group's latest orphan GC report, or ErrReportNotFound.
+func (g *Graphiti) GetOrphanReport(ctx context.Context, groupID string) (*model.OrphanReport, error) {
+	var report model.OrphanReport
+	if err := g.loadReport(ctx, model.ReportKindOrphans, groupID, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// RunOrphanGC collects orphans in every group each interval until ctx is done.
+func (g *Graphiti) RunOrphanGC(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			groups, err := g.ListGroups(ctx)
+			if err != nil {
+				log.Printf("Orphan GC: failed to list groups: %v", err)
+				continue
+			}
+			for _, group := range groups {
+				report, err := g.CollectOrphans(ctx, group.GroupID, "", false)
+				if err != nil {
+					log.Printf("Orphan GC failed for group %s: %v", group.GroupID, err)
+					continue
+				}
+				if len(report.Orphans) > 0 {
+					log.Printf("Orphan GC: %s %d entities in group %s", report.Mode, len(report.Orphans), group.GroupID)
+				}
+			}
+		}
+	}
+}
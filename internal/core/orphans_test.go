@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectOrphans(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	old := time.Now().UTC().Add(-2 * time.Hour)
+
+	for _, n := range []struct {
+		uuid    string
+		created time.Time
+	}{{"mentioned", old}, {"linked", old}, {"peer", old}, {"stale", old}, {"lonely", old}, {"fresh", time.Now().UTC()}} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: n.uuid, Name: n.uuid, GroupID: "g1", CreatedAt: n.created}))
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "message", "g1", "hi", old))
+	_, err := g.Driver.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{
+		"uuid": "m1", "source_uuid": "ep1", "target_uuid": "mentioned", "group_id": "g1",
+	})
+	require.NoError(t, err)
+	for _, e := range []struct{ uuid, source, target, invalidAt string }{
+		{"e1", "linked", "peer", ""},
+		{"e2", "stale", "peer", old.Format(time.RFC3339)},
+	} {
+		seedFact(t, g.Driver, "g1", e.uuid, e.source, e.target, "KNOWS", e.uuid, map[string]interface{}{"invalid_at": e.invalidAt})
+	}
+
+	report, err := g.CollectOrphans(ctx, "g1", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrphanModeQuarantine, report.Mode)
+	require.Len(t, report.Orphans, 2)
+	assert.Equal(t, "lonely", report.Orphans[0].UUID)
+	assert.Equal(t, "stale", report.Orphans[1].UUID)
+
+	// Quarantined entities drop out of the group
+	_, err = g.CollectOrphans(ctx, "g1", model.OrphanModeQuarantine, false)
+	require.NoError(t, err)
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 4)
+	_, err = g.GetEntity(ctx, "lonely")
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+
+	stored, err := g.GetOrphanReport(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, stored.Orphans, 2)
+
+	_, err = g.CollectOrphans(ctx, "g1", "archive", false)
+	assert.Error(t, err)
+}
+
+func TestCollectOrphans_DeleteRemovesEdges(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{OrphanGC: config.OrphanGCConfig{Mode: model.OrphanModeDelete}})
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	for _, uuid := range []string{"a", "b"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: uuid, Name: uuid, GroupID: "g1", CreatedAt: old}))
+	}
+	seedFact(t, d, "g1", "e1", "a", "b", "KNOWS", "a knows b", map[string]interface{}{"invalid_at": old.Format(time.RFC3339)})
+
+	report, err := g.CollectOrphans(ctx, "g1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrphanModeDelete, report.Mode)
+	assert.Len(t, report.Orphans, 2)
+
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+	res, err := d.ExecuteQuery(ctx, driver.InvalidateEdgeQuery, map[string]interface{}{"uuid": "e1", "invalid_at": ""})
+	require.NoError(t, err)
+	assert.Empty(t, res.Records, "the invalidated edge is deleted with its endpoints")
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+const (
+	defaultPathLimit = 5
+	// maxPathSeeds bounds how many query-relevant entities are linked pairwise.
+	maxPathSeeds = 5
+)
+
+// pathRecord mirrors a FindPathsQuery row.
+type pathRecord struct {
+	NodeUUIDs   []string `db:"node_uuids"`
+	NodeNames   []string `db:"node_names"`
+	EdgeUUIDs   []string `db:"edge_uuids"`
+	EdgeSources []string `db:"edge_sources"`
+	EdgeTargets []string `db:"edge_targets"`
+	EdgeNames   []string `db:"edge_names"`
+	EdgeFacts   []string `db:"edge_facts"`
+}
+
+// SearchPaths returns up to limit 2-3 hop chains of valid facts between the
+// entities most relevant to query, e.g. Alice -WORKS_AT-> Google -LOCATED_IN->
+// Mountain View. Relevant entities are the endpoints of the top Search hits.
+// With a path prompt configured, each chain is also summarized by the LLM.
+func (g *Graphiti) SearchPaths(ctx context.Context, groupID, query string, limit int) ([]model.FactPath, error) {
+	if limit <= 0 {
+		limit = defaultPathLimit
+	}
+
+	hits, err := g.Search(ctx, groupID, query)
+	if err != nil {
+		return nil, err
+	}
+	var seeds []string
+	for _, e := range hits {
+		for _, uuid := range []string{e.SourceUUID, e.TargetUUID} {
+			if len(seeds) < maxPathSeeds && !slices.Contains(seeds, uuid) {
+				seeds = append(seeds, uuid)
+			}
+		}
+	}
+	if len(seeds) < 2 {
+		return []model.FactPath{}, nil
+	}
+
+	access := g.accessFilter(ctx)
+	paths := []model.FactPath{}
+	seen := make(map[string]bool)
+	for i, seed := range seeds[:len(seeds)-1] {
+		// Later seeds only: a path and its reverse are the same chain
+		res, err := g.Driver.ExecuteReadQuery(ctx, driver.FindPathsQuery, map[string]interface{}{
+			"source_uuid":  seed,
+			"target_uuids": seeds[i+1:],
+			"group_id":     groupID,
+			"limit":        limit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("path search failed: %w", err)
+		}
+		recs, err := driver.ScanRecords[pathRecord](res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read paths: %w", err)
+		}
+		for _, rec := range recs {
+			if path, ok := rec.factPath(groupID); ok && access.path(path) && !seen[pathKey(path)] {
+				seen[pathKey(path)] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	// Shorter chains first; ties keep seed (relevance) order
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i].Edges) < len(paths[j].Edges) })
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+
+	if g.Config == nil || g.Config.Summary.Path == "" {
+		return paths, nil
+	}
+	group, err := g.GetGroup(ctx, groupID)
+	if err != nil && !errors.Is(err, ErrGroupNotFound) {
+		return nil, err
+	}
+	scoped := g.forGroup(group)
+
+	workers := 4
+	if g.Config.Concurrency.EdgeWorkers > 0 {
+		workers = g.Config.Concurrency.EdgeWorkers
+	}
+	var mu sync.Mutex
+	var errs []error
+	forEachBounded(workers, len(paths), func(i int) {
+		facts := make([]string, len(paths[i].Edges))
+		for j, e := range paths[i].Edges {
+			facts[j] = e.Fact
+		}
+		summary, err := scoped.Summarizer.SummarizePath(ctx, query, facts)
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return
+		}
+		paths[i].Summary = summary
+	})
+	if len(errs) > 0 {
+		// Chains are still useful without their summaries
+		fmt.Printf("Path summarization errors: %v\n", errors.Join(errs...))
+	}
+	return paths, nil
+}
+
+// factPath converts a row, rejecting paths that revisit a node.
+func (r pathRecord) factPath(groupID string) (model.FactPath, bool) {
+	n := len(r.EdgeUUIDs)
+	if len(r.NodeUUIDs) != n+1 || len(r.NodeNames) != n+1 || len(r.EdgeSources) != n ||
+		len(r.EdgeTargets) != n || len(r.EdgeNames) != n || len(r.EdgeFacts) != n {
+		return model.FactPath{}, false
+	}
+
+	path := model.FactPath{}
+	for i, uuid := range r.NodeUUIDs {
+		for _, prev := range path.Nodes {
+			if prev.UUID == uuid {
+				return model.FactPath{}, false
+			}
+		}
+		path.Nodes = append(path.Nodes, model.PathNode{UUID: uuid, Name: r.NodeNames[i]})
+	}
+
+	var chain strings.Builder
+	chain.WriteString(r.NodeNames[0])
+	for i := 0; i < n; i++ {
+		path.Edges = append(path.Edges, model.EntityEdge{
+			UUID:       r.EdgeUUIDs[i],
+			SourceUUID: r.EdgeSources[i],
+			TargetUUID: r.EdgeTargets[i],
+			GroupID:    groupID,
+			Name:       r.EdgeNames[i],
+			Fact:       r.EdgeFacts[i],
+		})
+		if r.EdgeSources[i] == r.NodeUUIDs[i] {
+			fmt.Fprintf(&chain, " -%s-> %s", r.EdgeNames[i], r.NodeNames[i+1])
+		} else {
+			fmt.Fprintf(&chain, " <-%s- %s", r.EdgeNames[i], r.NodeNames[i+1])
+		}
+	}
+	path.Chain = chain.String()
+	return path, true
+}
+
+// pathKey identifies a path regardless of the direction it was found in.
+func pathKey(p model.FactPath) string {
+	uuids := make([]string, len(p.Edges))
+	for i, e := range p.Edges {
+		uuids[i] = e.UUID
+	}
+	if len(uuids) > 0 && uuids[0] > uuids[len(uuids)-1] {
+		for i, j := 0, len(uuids)-1; i < j; i, j = i+1, j-1 {
+			uuids[i], uuids[j] = uuids[j], uuids[i]
+		}
+	}
+	return strings.Join(uuids, ",")
+}
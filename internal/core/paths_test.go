@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchPaths(t *testing.T) {
+	ctx := context.Background()
+	var pathPrompt string
+	cfg := &config.Config{Summary: config.SummaryPrompts{Path: "path %s %s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(p string) string {
+		pathPrompt = p
+		return `{"summary": "Alice works in Mountain View."}`
+	}), nil, nil, cfg)
+
+	now := time.Now().UTC()
+	for _, name := range []string{"Alice", "Google", "Mountain View", "Bob"} {
+		require.NoError(t, g.saveEntity(ctx, model.EntityNode{UUID: name, Name: name, GroupID: "g1", CreatedAt: now}))
+	}
+	for _, e := range []struct{ uuid, source, target, name, fact, invalidAt string }{
+		{"e1", "Alice", "Google", "WORKS_AT", "Alice works at Google", ""},
+		{"e2", "Mountain View", "Google", "HOSTS", "Mountain View hosts Google", ""},
+		{"e3", "Bob", "Alice", "KNOWS", "Bob knows Alice", ""},
+		{"e4", "Alice", "Mountain View", "LIVED_IN", "Alice lived in Mountain View near Google", now.Format(time.RFC3339)},
+	} {
+		seedFact(t, g.Driver, "g1", e.uuid, e.source, e.target, e.name, e.fact, map[string]interface{}{"invalid_at": e.invalidAt})
+	}
+
+	paths, err := g.SearchPaths(ctx, "g1", "Google", 0)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	path := paths[0]
+	assert.Equal(t, "Alice -WORKS_AT-> Google <-HOSTS- Mountain View", path.Chain)
+	require.Len(t, path.Edges, 2)
+	assert.Equal(t, "e1", path.Edges[0].UUID)
+	assert.Equal(t, "Mountain View", path.Edges[1].SourceUUID)
+	assert.Equal(t, "Alice works in Mountain View.", path.Summary)
+	assert.True(t, strings.Contains(pathPrompt, "1. Alice works at Google\n2. Mountain View hosts Google"))
+
+	// Without a path prompt chains are returned unsummarized
+	g.Config.Summary.Path = ""
+	paths, err = g.SearchPaths(ctx, "g1", "Google", 0)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Empty(t, paths[0].Summary)
+
+	paths, err = g.SearchPaths(ctx, "g1", "nothing matches", 0)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
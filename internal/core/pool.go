@@ -0,0 +1,23 @@
+package core
+
+import "sync"
+
+// forEachBounded calls fn(i) for i in [0, n) using at most limit goroutines
+// and returns once every call has finished.
+func forEachBounded(limit, n int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
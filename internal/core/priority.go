@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+type priorityKey struct{}
+
+// WithPriority runs the work of the returned context at priority,
+// model.PriorityInteractive or model.PriorityBatch: when workers are busy,
+// queued interactive work gets the next free one before any batch work.
+// Bulk ingestion, ingest jobs and bulk search default to batch, everything
+// else to interactive.
+func WithPriority(ctx context.Context, priority string) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// asBatch marks ctx's work as batch unless the caller chose a priority.
+func asBatch(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(priorityKey{}).(string); ok {
+		return ctx
+	}
+	return WithPriority(ctx, model.PriorityBatch)
+}
+
+func priorityOf(ctx context.Context) string {
+	if p, ok := ctx.Value(priorityKey{}).(string); ok && p == model.PriorityBatch {
+		return p
+	}
+	return model.PriorityInteractive
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// profileContent is ingested by ProfileIngest when the caller sends none.
+const profileContent = "Alice Chen joined Acme Corp in Berlin as head of research in March. " +
+	"She reports to Bob Meyer, who founded Acme Corp with Carol Diaz."
+
+type profileKey struct{}
+
+// stageProfile collects the stage durations of an ingest run under
+// ProfileIngest.
+type stageProfile struct {
+	mu     sync.Mutex
+	stages []model.StageTiming
+}
+
+// timeStage starts timing stage for a profiled ingest; call the returned
+// func when it ends. Outside ProfileIngest it does nothing.
+func timeStage(ctx context.Context, stage string) func() {
+	p, ok := ctx.Value(profileKey{}).(*stageProfile)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.stages = append(p.stages, model.StageTiming{Stage: stage, DurationMS: durationMS(time.Since(start))})
+	}
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// ProfileIngest runs content (a built-in synthetic conversation when empty)
+// through the ingestion pipeline in a throwaway group, which is deleted
+// afterwards, and reports how long each stage took. It uses the configured
+// LLM, embedder and database, so it shows where production ingests spend
+// their time. A failed ingest is reported in the profile, not as an error.
+func (g *Graphiti) ProfileIngest(ctx context.Context, content string) (model.IngestProfile, error) {
+	if content == "" {
+		content = profileContent
+	}
+	groupID := "_profile_" + g.UUIDGenerator()
+	p := &stageProfile{}
+	start := time.Now()
+	err := g.addEpisode(context.WithValue(ctx, profileKey{}, p), groupID, "ingest profile", content, "", "")
+	profile := model.IngestProfile{Stages: p.stages, DurationMS: durationMS(time.Since(start))}
+	if err != nil {
+		profile.Error = err.Error()
+	}
+
+	if _, err := g.DeleteGroup(context.WithoutCancel(ctx), groupID); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// ErrNothingToProfile is returned by SynthesizeProfile for a group without entities.
+var ErrNothingToProfile = errors.New("group has no entities to profile")
+
+const (
+	profileEntities = 20 // Most salient entities given to the profile prompt
+	profileFacts    = 50 // Most often stated facts given to the profile prompt
+)
+
+// SynthesizeProfile returns the group's synthesized user profile, writing it
+// with the [summary] profile prompt when none is stored yet or refresh is set.
+// The prompt gets the most salient entities with their summaries and the
+// facts stated by the most episodes, the group's user entity and its facts
+// first. Profiles are stored per set of facts the caller may read, so no
+// scope is served a profile written from facts hidden from it.
+func (g *Graphiti) SynthesizeProfile(ctx context.Context, groupID string, refresh bool) (*model.SynthesizedProfile, error) {
+	kind := model.ReportKindProfile + g.accessFilter(ctx).cacheKey()
+	if !refresh {
+		var profile model.SynthesizedProfile
+		err := g.loadReport(ctx, kind, groupID, &profile)
+		if err == nil {
+			return &profile, nil
+		}
+		if !errors.Is(err, ErrReportNotFound) {
+			return nil, err
+		}
+	}
+
+	top, err := g.TopEntities(ctx, groupID, profileEntities)
+	if err != nil {
+		return nil, err
+	}
+	if len(top) == 0 {
+		return nil, ErrNothingToProfile
+	}
+	user := userEntityID(groupID)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].UUID == user && top[j].UUID != user })
+	entities := make([]string, len(top))
+	for i, e := range top {
+		entities[i] = e.Name
+		if e.Summary != "" {
+			entities[i] += ": " + e.Summary
+		}
+	}
+
+	edges, err := g.ListFacts(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		aUser, bUser := a.SourceUUID == user || a.TargetUUID == user, b.SourceUUID == user || b.TargetUUID == user
+		if aUser != bUser {
+			return aUser
+		}
+		if len(a.Episodes) != len(b.Episodes) {
+			return len(a.Episodes) > len(b.Episodes)
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	facts := make([]string, 0, min(len(edges), profileFacts))
+	for _, e := range edges[:min(len(edges), profileFacts)] {
+		facts = append(facts, e.Fact)
+	}
+
+	scoped := g
+	if group, err := g.GetGroup(ctx, groupID); err == nil {
+		scoped = g.forGroup(group)
+	}
+	profile, err := scoped.Summarizer.SynthesizeProfile(ctx, entities, facts)
+	if err != nil {
+		return nil, err
+	}
+	profile.GroupID = groupID
+	profile.GeneratedAt = time.Now().UTC()
+	for _, list := range []*[]string{&profile.Preferences, &profile.Facts, &profile.Relationships, &profile.OpenQuestions} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+
+	if err := g.saveReport(ctx, kind, groupID, profile.GeneratedAt, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesizeProfile(t *testing.T) {
+	ctx := context.Background()
+	var prompts []string
+	llmClient := llmFunc(func(prompt string) string {
+		prompts = append(prompts, prompt)
+		return `{"preferences": ["Likes hiking."], "facts": ["Works at Acme."], "open_questions": ["Where does Alice live?"]}`
+	})
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Summary: config.SummaryPrompts{Profile: "entities:\n%sfacts:\n%s"},
+	})
+
+	_, err := g.SynthesizeProfile(ctx, "g1", false)
+	assert.ErrorIs(t, err, ErrNothingToProfile)
+
+	user, err := g.EnsureUserEntity(ctx, "g1", model.UserProfile{Name: "Alice"})
+	require.NoError(t, err)
+	seedEntity(t, g.Driver, "g1", "acme", map[string]interface{}{"summary": "A robotics company"})
+	seedEntity(t, g.Driver, "g1", "bob", map[string]interface{}{"summary": ""})
+	for _, e := range [][4]string{{"e1", "bob", "acme", "Bob founded Acme"}, {"e2", user.UUID, "acme", "Alice works at Acme"}} {
+		seedFact(t, g.Driver, "g1", e[0], e[1], e[2], "RELATED_TO", e[3], nil)
+	}
+
+	profile, err := g.SynthesizeProfile(ctx, "g1", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Likes hiking."}, profile.Preferences)
+	assert.Equal(t, []string{}, profile.Relationships)
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "entities:\n- Alice\n- acme: A robotics company\n")
+	assert.Contains(t, prompts[0], "facts:\n- Alice works at Acme\n- Bob founded Acme\n")
+
+	cached, err := g.SynthesizeProfile(ctx, "g1", false)
+	require.NoError(t, err)
+	assert.Equal(t, profile.Facts, cached.Facts)
+	assert.Len(t, prompts, 1, "served from the stored profile")
+
+	_, err = g.SynthesizeProfile(ctx, "g1", true)
+	require.NoError(t, err)
+	assert.Len(t, prompts, 2)
+}
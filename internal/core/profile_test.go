@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileIngest(t *testing.T) {
+	llmClient := llmFunc(func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return `{"extracted_edges": []}`
+		}
+		return `{"extracted_entities": [{"name": "Alice"}, {"name": "Acme"}]}`
+	})
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+	})
+	ctx := context.Background()
+
+	profile, err := g.ProfileIngest(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, profile.Error)
+	var stages []string
+	for _, s := range profile.Stages {
+		stages = append(stages, s.Stage)
+		assert.LessOrEqual(t, s.DurationMS, profile.DurationMS)
+	}
+	assert.Equal(t, []string{"queue", "save_episode", "load_context", "extract_nodes", "dedupe_nodes", "save_nodes", "extract_edges", "resolve_edges", "summarize"}, stages)
+
+	// The throwaway group is gone
+	groups, err := g.ListGroups(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	// Ingests outside a profile record nothing
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice works at Acme.", "", ""))
+}
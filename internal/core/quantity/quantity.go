@@ -0,0 +1,148 @@
+// Package quantity parses quantities written as text ("$500", "28-year-old",
+// "5km") into numbers in a canonical unit, so attributes holding them compare
+// as numbers.
+package quantity
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a number in a canonical unit: "m", "kg", "l", "s", "day",
+// "month", "year", "%" or an ISO currency code. Unit is empty for plain numbers.
+type Quantity struct {
+	Value float64
+	Unit  string
+}
+
+type unit struct {
+	name   string
+	factor float64
+}
+
+var units = map[string]unit{}
+
+func init() {
+	for _, u := range []struct {
+		unit
+		aliases string
+	}{
+		{unit{"m", 0.001}, "mm millimeter millimeters millimetre millimetres"},
+		{unit{"m", 0.01}, "cm centimeter centimeters centimetre centimetres"},
+		{unit{"m", 1}, "m meter meters metre metres"},
+		{unit{"m", 1000}, "km kilometer kilometers kilometre kilometres"},
+		{unit{"m", 0.0254}, "in inch inches"},
+		{unit{"m", 0.3048}, "ft foot feet"},
+		{unit{"m", 0.9144}, "yd yard yards"},
+		{unit{"m", 1609.344}, "mi mile miles"},
+		{unit{"kg", 0.000001}, "mg milligram milligrams"},
+		{unit{"kg", 0.001}, "g gram grams"},
+		{unit{"kg", 1}, "kg kilogram kilograms kilo kilos"},
+		{unit{"kg", 0.45359237}, "lb lbs pound pounds"},
+		{unit{"kg", 0.028349523125}, "oz ounce ounces"},
+		{unit{"kg", 1000}, "t ton tons tonne tonnes"},
+		{unit{"l", 0.001}, "ml milliliter milliliters millilitre millilitres"},
+		{unit{"l", 1}, "l liter liters litre litres"},
+		{unit{"s", 1}, "s sec secs second seconds"},
+		{unit{"s", 60}, "min mins minute minutes"},
+		{unit{"s", 3600}, "h hr hrs hour hours"},
+		{unit{"day", 1}, "d day days"},
+		{unit{"day", 7}, "wk week weeks"},
+		{unit{"month", 1}, "mo month months"},
+		{unit{"year", 1}, "y yr yrs year years"},
+		{unit{"%", 1}, "% percent pct"},
+		{unit{"USD", 1}, "$ usd dollar dollars"},
+		{unit{"EUR", 1}, "€ eur euro euros"},
+		{unit{"GBP", 1}, "£ gbp"},
+		{unit{"JPY", 1}, "¥ jpy yen"},
+	} {
+		for _, alias := range strings.Fields(u.aliases) {
+			units[alias] = u.unit
+		}
+	}
+}
+
+var scales = map[string]float64{"": 1, "k": 1e3, "thousand": 1e3, "m": 1e6, "mn": 1e6, "million": 1e6, "bn": 1e9, "billion": 1e9}
+
+// A leading currency symbol, the number, a scale word and the unit.
+var quantityRe = regexp.MustCompile(`^([$€£¥])?\s*(\d[\d,]*(?:\.\d+)?|\.\d+)\s*(k|thousand|mn|million|bn|billion)?(?:\s*-\s*|\s*)([a-z%$€£¥][a-z%$€£¥ -]*)?$`)
+
+// ageRe matches the "old" of ages: "28-year-old", "28 years old".
+var ageRe = regexp.MustCompile(`^(y|yr|yrs|year|years)[ -]old$`)
+
+// Parse reads s as a whole as a quantity. It reports false for anything else,
+// including text that merely contains a number.
+func Parse(s string) (Quantity, bool) {
+	m := quantityRe.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return Quantity{}, false
+	}
+	symbol, number, scale, suffix := m[1], m[2], m[3], strings.TrimSpace(m[4])
+	value, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	if err != nil {
+		return Quantity{}, false
+	}
+	// "$5m" is five million dollars, "5m" five meters
+	if symbol != "" && suffix == "m" {
+		scale, suffix = "m", ""
+	}
+	value *= scales[scale]
+	if ageRe.MatchString(suffix) {
+		suffix = "year"
+	}
+
+	var u unit
+	switch {
+	case symbol != "" && suffix != "":
+		// "$5 usd": the suffix must name the symbol's currency
+		if units[suffix] != units[symbol] {
+			return Quantity{}, false
+		}
+		u = units[symbol]
+	case symbol != "":
+		u = units[symbol]
+	case suffix != "":
+		var ok bool
+		if u, ok = units[suffix]; !ok {
+			return Quantity{}, false
+		}
+	default:
+		u = unit{"", 1}
+	}
+	return Quantity{Value: round(value * u.factor), Unit: u.name}, true
+}
+
+// round drops the float noise of unit conversions (5.5 ft is 1.6764 m, not 1.6764000000000001).
+func round(v float64) float64 {
+	return math.Round(v*1e9) / 1e9
+}
+
+// NormalizeAttributes returns attrs with each top-level string value that is
+// a quantity replaced by its number, and its unit stored as "<key>_unit"
+// unless attrs already has that key. attrs itself is not modified.
+func NormalizeAttributes(attrs map[string]interface{}) map[string]interface{} {
+	if len(attrs) == 0 {
+		return attrs
+	}
+	normalized := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		normalized[k] = v
+	}
+	for k, v := range attrs {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		q, ok := Parse(s)
+		if !ok {
+			continue
+		}
+		normalized[k] = q.Value
+		if _, exists := attrs[k+"_unit"]; !exists && q.Unit != "" {
+			normalized[k+"_unit"] = q.Unit
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,68 @@
+package quantity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Quantity
+	}{
+		{"$500", Quantity{500, "USD"}},
+		{"$1,250.50", Quantity{1250.5, "USD"}},
+		{"$5m", Quantity{5000000, "USD"}},
+		{"€20k", Quantity{20000, "EUR"}},
+		{"300 dollars", Quantity{300, "USD"}},
+		{"28-year-old", Quantity{28, "year"}},
+		{"28 years old", Quantity{28, "year"}},
+		{"28", Quantity{28, ""}},
+		{"5km", Quantity{5000, "m"}},
+		{"5 m", Quantity{5, "m"}},
+		{"5.5 ft", Quantity{1.6764, "m"}},
+		{"3 miles", Quantity{4828.032, "m"}},
+		{"70 kg", Quantity{70, "kg"}},
+		{"500g", Quantity{0.5, "kg"}},
+		{"2 hours", Quantity{7200, "s"}},
+		{"2 weeks", Quantity{14, "day"}},
+		{"15%", Quantity{15, "%"}},
+		{"1.5 million", Quantity{1500000, ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := Parse(tt.text)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, text := range []string{"", "Berlin", "about 5 km", "5 parsecs", "$5 eur", "2019-05-01", "v2"} {
+		_, ok := Parse(text)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestNormalizeAttributes(t *testing.T) {
+	attrs := map[string]interface{}{
+		"age":         "28-year-old",
+		"salary":      "$120k",
+		"height":      "180",
+		"height_unit": "cm",
+		"city":        "Berlin",
+		"score":       7,
+	}
+	normalized := NormalizeAttributes(attrs)
+	assert.Equal(t, map[string]interface{}{
+		"age":         28.0,
+		"age_unit":    "year",
+		"salary":      120000.0,
+		"salary_unit": "USD",
+		"height":      180.0,
+		"height_unit": "cm",
+		"city":        "Berlin",
+		"score":       7,
+	}, normalized)
+	assert.Equal(t, "28-year-old", attrs["age"])
+}
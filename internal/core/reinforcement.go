@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// reinforceEdge records that episodeUUID stated the existing fact again. An
+// episode counts once per fact.
+func (g *Graphiti) reinforceEdge(ctx context.Context, edgeUUID, episodeUUID string) error {
+	_, err := g.Driver.ExecuteQuery(ctx, driver.ReinforceEntityEdgeQuery, map[string]interface{}{
+		"uuid":         edgeUUID,
+		"episode_uuid": episodeUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reinforce fact %s: %w", edgeUUID, err)
+	}
+	return nil
+}
+
+func (g *Graphiti) reinforcementWeight() float64 {
+	if g.Config == nil {
+		return 0
+	}
+	return g.Config.Search.ReinforcementWeight
+}
+
+// rankReinforcedEdges reorders search results so facts stated by more
+// episodes move up: each edge's position is divided by 1 + weight *
+// ln(mention count), and ties keep their order.
+func rankReinforcedEdges(edges []model.EntityEdge, weight float64) []model.EntityEdge {
+	rank := make(map[string]float64, len(edges))
+	for i, e := range edges {
+		rank[e.UUID] = float64(i+1) / (1 + weight*math.Log(float64(max(e.MentionCount, 1))))
+	}
+	ranked := append([]model.EntityEdge(nil), edges...)
+	sort.SliceStable(ranked, func(a, b int) bool { return rank[ranked[a].UUID] < rank[ranked[b].UUID] })
+	return ranked
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// getEdgeHistoryFromSource returns every fact of the source node, invalidated
+// ones included, oldest first.
+func (g *Graphiti) getEdgeHistoryFromSource(ctx context.Context, source string) ([]model.EntityEdge, error) {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.GetEdgeHistoryFromSourceQuery, map[string]interface{}{
+		"source_uuid": source,
+	})
+	if err != nil {
+		return nil, err
+	}
+	edges, err := driver.ScanRecords[model.EntityEdge](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edges from source: %w", err)
+	}
+	for i := range edges {
+		edges[i].SourceUUID = source
+	}
+	return edges, nil
+}
+
+// reinstatedEdge returns uuid if it names an invalidated fact of history
+// between the same nodes as the new one, and "" otherwise.
+func reinstatedEdge(history []model.EntityEdge, uuid, targetUUID string) string {
+	for _, e := range history {
+		if e.UUID == uuid && e.InvalidAt != nil && e.TargetUUID == targetUUID {
+			return uuid
+		}
+	}
+	return ""
+}
+
+// reinstateEdge makes an invalidated fact valid again and counts the episode
+// that stated it again as one of its mentions.
+func (g *Graphiti) reinstateEdge(ctx context.Context, edgeUUID, episodeUUID string) error {
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.ReinstateEdgeQuery, map[string]interface{}{
+		"uuid": edgeUUID,
+	}); err != nil {
+		return fmt.Errorf("failed to reinstate fact %s: %w", edgeUUID, err)
+	}
+	return g.reinforceEdge(ctx, edgeUUID, episodeUUID)
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessEdge_ReinstatesFactsStatedAgain(t *testing.T) {
+	var edgesResponse string
+	var historyPrompt string
+	// uuidOf finds the UUID a contradiction prompt lists for fact
+	uuidOf := func(prompt, fact string) string {
+		m := regexp.MustCompile(`UUID: (\S+), Fact: ` + fact).FindStringSubmatch(prompt)
+		if m == nil {
+			return ""
+		}
+		return m[1]
+	}
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "edges"):
+			return edgesResponse
+		case strings.HasPrefix(prompt, "Does the New Fact contradict"):
+			historyPrompt = prompt
+			switch {
+			case strings.Contains(prompt, "New Fact: a moved to c"):
+				return fmt.Sprintf(`{"contradicted_edge_uuids": [%q]}`, uuidOf(prompt, "a lives in b"))
+			case strings.Contains(prompt, "New Fact: a moved back to b"):
+				return fmt.Sprintf(`{"contradicted_edge_uuids": [%q], "reinstated_edge_uuid": %q}`,
+					uuidOf(prompt, "a moved to c"), uuidOf(prompt, "a lives in b"))
+			}
+			return `{"contradicted_edge_uuids": []}`
+		}
+		return `{"summary": "updated"}`
+	})
+	ctx := context.Background()
+	state := func(episode, target, fact string) {
+		edgesResponse = fmt.Sprintf(`{"extracted_edges": [{"source_node_uuid": "a", "target_node_uuid": %q, "relation_type": "LIVES_IN", "fact": %q}]}`, target, fact)
+		require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, episode, "g1", "", nil, time.Now().UTC()))
+	}
+	facts := func() map[string]bool {
+		edges, err := g.getEdgeHistoryFromSource(ctx, "a")
+		require.NoError(t, err)
+		valid := make(map[string]bool)
+		for _, e := range edges {
+			valid[e.Fact] = e.InvalidAt == nil
+		}
+		return valid
+	}
+
+	state("ep1", "b", "a lives in b")
+	state("ep2", "c", "a moved to c")
+	assert.Equal(t, map[string]bool{"a lives in b": false, "a moved to c": true}, facts())
+
+	// The model sees the invalidated fact and reopens it
+	state("ep3", "b", "a moved back to b")
+	assert.Contains(t, historyPrompt, "Fact: a lives in b, No longer true since: ")
+	assert.Equal(t, map[string]bool{"a lives in b": true, "a moved to c": false}, facts())
+	fact, err := g.GetFact(ctx, uuidOf(historyPrompt, "a lives in b"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ep1", "ep3"}, fact.Episodes)
+
+	// An invalidated fact stated word for word is reopened, still invalidating what it contradicts
+	state("ep4", "c", "a moved to c")
+	assert.Equal(t, map[string]bool{"a lives in b": false, "a moved to c": true}, facts())
+}
@@ -0,0 +1,30 @@
+package core
+
+import "github.com/agenthands/carbon/internal/core/model"
+
+// rerankLimits returns the rerank depth and skip margin of a search: as
+// filter sets them, or else as [search] does.
+func (g *Graphiti) rerankLimits(filter *model.SearchFilter) (depth int, margin float64) {
+	if g.Config != nil {
+		depth, margin = g.Config.Search.RerankDepth, g.Config.Search.RerankSkipMargin
+	}
+	if filter != nil && filter.RerankDepth != nil {
+		depth = *filter.RerankDepth
+	}
+	if filter != nil && filter.RerankSkipMargin != nil {
+		margin = *filter.RerankSkipMargin
+	}
+	return depth, margin
+}
+
+// decisiveLead reports whether the first of edges, as retrieved, leads the
+// second by at least margin in vector score. Text matches have no score and
+// never lead.
+func decisiveLead(edges []model.EntityEdge, scores map[string]float64, margin float64) bool {
+	if margin <= 0 || len(edges) < 2 {
+		return false
+	}
+	first, ok1 := scores[edges[0].UUID]
+	second, ok2 := scores[edges[1].UUID]
+	return ok1 && ok2 && first-second >= margin
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// rerankCacheKey identifies a reranking by its normalized query and the set
+// of candidate facts, whatever their retrieval order.
+func rerankCacheKey(query string, edges []model.EntityEdge) string {
+	uuids := edgeUUIDs(edges)
+	sort.Strings(uuids)
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	sum := sha256.Sum256([]byte("rerank\x00" + normalized + "\x00" + strings.Join(uuids, ",")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// rankEdges returns the reranker's order of edges as indices into edges. The
+// order of a candidate set ranked before for the same query comes from the
+// RerankCache, and cached reports it.
+func (g *Graphiti) rankEdges(ctx context.Context, groupID, query string, edges []model.EntityEdge) (indices []int, cached bool, err error) {
+	var key string
+	var gen int64
+	if g.RerankCache != nil {
+		key = rerankCacheKey(query, edges)
+		gen = g.RerankCache.Generation(ctx, groupID)
+		if order, ok := g.RerankCache.Get(ctx, groupID, key); ok {
+			position := make(map[string]int, len(edges))
+			for i, e := range edges {
+				position[e.UUID] = i
+			}
+			for _, e := range order {
+				if i, ok := position[e.UUID]; ok {
+					indices = append(indices, i)
+				}
+			}
+			return indices, true, nil
+		}
+	}
+
+	facts := make([]string, len(edges))
+	for i, e := range edges {
+		facts[i] = e.Fact
+	}
+	indices, err = g.Reranker.Rank(ctx, query, facts)
+	if err != nil || g.RerankCache == nil || len(indices) == 0 {
+		return indices, false, err
+	}
+	// Only the UUIDs are needed to replay the order
+	order := make([]model.EntityEdge, 0, len(indices))
+	for _, i := range indices {
+		if i >= 0 && i < len(edges) {
+			order = append(order, model.EntityEdge{UUID: edges[i].UUID})
+		}
+	}
+	g.RerankCache.Set(ctx, groupID, key, gen, order)
+	return indices, false, nil
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReranker is a reverseReranker that counts its calls.
+type countingReranker struct {
+	reverseReranker
+	calls int
+}
+
+func (r *countingReranker) Rank(ctx context.Context, query string, documents []string) ([]int, error) {
+	r.calls++
+	return r.reverseReranker.Rank(ctx, query, documents)
+}
+
+func TestRerankCache(t *testing.T) {
+	ctx := context.Background()
+	g := filterTestGraph(t)
+	reranker := &countingReranker{}
+	g.Reranker = reranker
+	g.RerankCache = NewRerankCache(config.SearchCacheConfig{Rerank: true})
+	search := func(query string) ([]string, bool) {
+		edges, trace, err := g.SearchDebug(ctx, "g1", query, nil)
+		require.NoError(t, err)
+		return edgeUUIDs(edges), trace.RerankCached
+	}
+
+	uuids, cached := search("fact")
+	assert.Equal(t, []string{"e3", "e2", "e1"}, uuids)
+	assert.False(t, cached)
+
+	// The same candidates for the same query keep their order without the reranker
+	uuids, cached = search("fact")
+	assert.Equal(t, []string{"e3", "e2", "e1"}, uuids)
+	assert.True(t, cached)
+	assert.Equal(t, 1, reranker.calls)
+
+	// Other candidates are ranked again
+	_, cached = search("e1 fact")
+	assert.False(t, cached)
+	assert.Equal(t, 1, reranker.calls) // A single candidate needs no reranking
+	_, cached = search("fac")
+	assert.False(t, cached)
+	assert.Equal(t, 2, reranker.calls)
+
+	// A change to the group drops the orderings
+	g.invalidateSearchCache(ctx, "g1")
+	_, cached = search("fact")
+	assert.False(t, cached)
+	assert.Equal(t, 3, reranker.calls)
+
+	assert.Nil(t, NewRerankCache(config.SearchCacheConfig{Enabled: true}))
+}
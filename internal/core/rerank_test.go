@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scoredTestGraph returns a graph whose facts f1, f2 and f3 score 1, 0.6 and
+// 0 against the query "q".
+func scoredTestGraph(t *testing.T, reranker llm.RerankerClient) *Graphiti {
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, mapEmbedder{"q": {1, 0}}, reranker, &config.Config{})
+	seedEntities(t, g.Driver, "g1", "a", "b")
+	for _, e := range []struct {
+		uuid string
+		vec  []float32
+	}{{"f1", []float32{1, 0}}, {"f2", []float32{0.6, 0.8}}, {"f3", []float32{0, 1}}} {
+		seedFact(t, g.Driver, "g1", e.uuid, "a", "b", "KNOWS", e.uuid, map[string]interface{}{
+			"created_at": "2024-01-01T00:00:00Z", "fact_embedding": e.vec, "fact_embedding_model": embeddingTag(g.EmbeddingModel, e.vec),
+		})
+	}
+	return g
+}
+
+func TestRerankLimits(t *testing.T) {
+	ctx := context.Background()
+	reranker := &countingReranker{}
+	g := scoredTestGraph(t, reranker)
+	search := func(filter *model.SearchFilter) ([]string, *model.SearchTrace) {
+		edges, trace, err := g.SearchDebug(ctx, "g1", "q", filter)
+		require.NoError(t, err)
+		return edgeUUIDs(edges), trace
+	}
+	two, decisive, narrow := 2, 0.3, 0.5
+
+	uuids, _ := search(nil)
+	assert.Equal(t, []string{"f3", "f2", "f1"}, uuids)
+
+	// Only the top two are reranked
+	uuids, _ = search(&model.SearchFilter{RerankDepth: &two})
+	assert.Equal(t, []string{"f2", "f1", "f3"}, uuids)
+	g.Config.Search.RerankDepth = 2
+	uuids, _ = search(nil)
+	assert.Equal(t, []string{"f2", "f1", "f3"}, uuids)
+
+	// f1 scores 1 and f2 0.6: a lead of 0.4
+	calls := reranker.calls
+	uuids, trace := search(&model.SearchFilter{RerankSkipMargin: &decisive})
+	assert.Equal(t, []string{"f1", "f2", "f3"}, uuids)
+	assert.True(t, trace.RerankSkipped)
+	assert.Equal(t, calls, reranker.calls)
+	uuids, trace = search(&model.SearchFilter{RerankSkipMargin: &narrow})
+	assert.Equal(t, []string{"f2", "f1", "f3"}, uuids)
+	assert.False(t, trace.RerankSkipped)
+
+	negative := -1
+	assert.Error(t, (&model.SearchFilter{RerankDepth: &negative}).Validate())
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// ExpireEpisodes deletes the group's episodes older than its retention_days
+// setting and returns how many it deleted. Entities and facts are kept; those
+// only the deleted episodes supported are left to the orphan GC.
+func (g *Graphiti) ExpireEpisodes(ctx context.Context, groupID string) (int, error) {
+	group, err := g.GetGroup(ctx, groupID)
+	if errors.Is(err, ErrGroupNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	days := group.Settings.RetentionDays
+	if days <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -days)
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesCreatedBeforeQuery, map[string]interface{}{
+		"group_id":       groupID,
+		"created_before": cutoff.Format(time.RFC3339),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired episodes: %w", err)
+	}
+	deleted := 0
+	for _, rec := range res.Records {
+		uuid, _ := rec.Get("uuid")
+		id, _ := uuid.(string)
+		if err := g.DeleteEpisode(ctx, id); err != nil && !errors.Is(err, ErrEpisodeNotFound) {
+			return deleted, fmt.Errorf("failed to delete expired episode %s: %w", id, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunRetention expires the episodes of every group each interval until ctx is done.
+func (g *Graphiti) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			groups, err := g.ListGroups(ctx)
+			if err != nil {
+				log.Printf("Retention: failed to list groups: %v", err)
+				continue
+			}
+			for _, group := range groups {
+				deleted, err := g.ExpireEpisodes(ctx, group.GroupID)
+				if err != nil {
+					log.Printf("Retention failed for group %s: %v", group.GroupID, err)
+					continue
+				}
+				if deleted > 0 {
+					log.Printf("Retention: deleted %d expired episodes in group %s", deleted, group.GroupID)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireEpisodes(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(string) string { return "{}" }), nil, nil, &config.Config{})
+
+	now := time.Now().UTC()
+	require.NoError(t, g.saveEpisodeNode(ctx, "old", "old", "g1", "hi", now.AddDate(0, 0, -40)))
+	require.NoError(t, g.saveEpisodeNode(ctx, "recent", "recent", "g1", "hi", now.AddDate(0, 0, -10)))
+	require.NoError(t, g.saveEpisodeNode(ctx, "other", "other", "g2", "hi", now.AddDate(0, 0, -40)))
+
+	// Without retention_days episodes are kept
+	deleted, err := g.ExpireEpisodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{RetentionDays: 30}})
+	require.NoError(t, err)
+	deleted, err = g.ExpireEpisodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	for uuid, kept := range map[string]bool{"old": false, "recent": true, "other": true} {
+		res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesByUUIDQuery, map[string]interface{}{"uuids": []string{uuid}})
+		require.NoError(t, err)
+		assert.Equal(t, kept, len(res.Records) == 1, uuid)
+	}
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{RetentionDays: -1}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
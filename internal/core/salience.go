@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+const DefaultTopEntities = 10
+
+// salienceRecord mirrors a GetEntitySalienceQuery row.
+type salienceRecord struct {
+	UUID            string     `db:"uuid"`
+	Name            string     `db:"name"`
+	Summary         string     `db:"summary"`
+	Labels          []string   `db:"labels"`
+	Relations       []string   `db:"relations"`
+	Mentions        int        `db:"mentions"`
+	CreatedAt       *time.Time `db:"created_at"`
+	LastFactAt      *time.Time `db:"last_fact_at"`
+	LastMentionedAt *time.Time `db:"last_mentioned_at"`
+}
+
+// TopEntities returns the group's limit most salient entities, highest Score
+// first: those with the most valid facts, mentioned by the most episodes and
+// seen most recently. Facts the caller may not read don't count.
+func (g *Graphiti) TopEntities(ctx context.Context, groupID string, limit int) ([]model.EntitySalience, error) {
+	if limit <= 0 {
+		limit = DefaultTopEntities
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEntitySalienceQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entity salience: %w", err)
+	}
+	records, err := driver.ScanRecords[salienceRecord](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity salience: %w", err)
+	}
+
+	access := g.accessFilter(ctx)
+	entities := make([]model.EntitySalience, 0, len(records))
+	var maxDegree, maxMentions int
+	var oldest, newest time.Time
+	for _, r := range records {
+		e := model.EntitySalience{UUID: r.UUID, Name: r.Name, Summary: r.Summary, Labels: r.Labels, Mentions: r.Mentions}
+		for _, rel := range r.Relations {
+			if access.allows(rel) {
+				e.Degree++
+			}
+		}
+		for _, t := range []*time.Time{r.CreatedAt, r.LastFactAt, r.LastMentionedAt} {
+			if t != nil && !t.IsZero() && (e.LastSeenAt == nil || t.After(*e.LastSeenAt)) {
+				e.LastSeenAt = t
+			}
+		}
+		maxDegree = max(maxDegree, e.Degree)
+		maxMentions = max(maxMentions, e.Mentions)
+		if e.LastSeenAt != nil {
+			if oldest.IsZero() || e.LastSeenAt.Before(oldest) {
+				oldest = *e.LastSeenAt
+			}
+			if e.LastSeenAt.After(newest) {
+				newest = *e.LastSeenAt
+			}
+		}
+		entities = append(entities, e)
+	}
+
+	span := newest.Sub(oldest)
+	for i := range entities {
+		e := &entities[i]
+		var degree, mentions, recency float64
+		if maxDegree > 0 {
+			degree = float64(e.Degree) / float64(maxDegree)
+		}
+		if maxMentions > 0 {
+			mentions = float64(e.Mentions) / float64(maxMentions)
+		}
+		if e.LastSeenAt != nil {
+			recency = 1
+			if span > 0 {
+				recency = float64(e.LastSeenAt.Sub(oldest)) / float64(span)
+			}
+		}
+		e.Score = (degree + mentions + recency) / 3
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Score != entities[j].Score {
+			return entities[i].Score > entities[j].Score
+		}
+		return entities[i].Name < entities[j].Name
+	})
+	if len(entities) > limit {
+		entities = entities[:limit]
+	}
+	return entities, nil
+}
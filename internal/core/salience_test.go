@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopEntities(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{})
+	g.Config.Access.Policies = []config.AccessPolicy{{Scope: "hr", Relations: []string{"EARNS"}}}
+
+	for _, n := range [][2]string{{"alice", "2024-01-01T00:00:00Z"}, {"bob", "2024-01-01T00:00:00Z"}, {"carol", "2023-01-01T00:00:00Z"}} {
+		seedEntity(t, d, "g1", n[0], map[string]interface{}{"created_at": n[1]})
+	}
+	for _, e := range [][4]string{{"e1", "alice", "bob", "KNOWS"}, {"e2", "alice", "carol", "EARNS"}, {"e3", "carol", "bob", "EARNS"}} {
+		seedFact(t, d, "g1", e[0], e[1], e[2], e[3], e[0], map[string]interface{}{"created_at": "2024-02-01T00:00:00Z"})
+	}
+	for _, ep := range []string{"ep1", "ep2"} {
+		_, err := d.ExecuteQuery(ctx, driver.SaveEpisodicNodeQuery, map[string]interface{}{"uuid": ep, "group_id": "g1", "created_at": "2024-03-01T00:00:00Z"})
+		require.NoError(t, err)
+		_, err = d.ExecuteQuery(ctx, driver.SaveEpisodicEdgeQuery, map[string]interface{}{"uuid": ep + "-alice", "source_uuid": ep, "target_uuid": "alice", "group_id": "g1"})
+		require.NoError(t, err)
+	}
+
+	top, err := g.TopEntities(ctx, "g1", 0)
+	require.NoError(t, err)
+	require.Len(t, top, 3)
+	assert.Equal(t, "alice", top[0].Name)
+	assert.Equal(t, 2, top[0].Degree)
+	assert.Equal(t, 2, top[0].Mentions)
+	assert.InDelta(t, 1.0, top[0].Score, 1e-9)
+	assert.Equal(t, "2024-03-01T00:00:00Z", top[0].LastSeenAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	// Facts hidden from the caller don't count toward degree
+	top, err = g.TopEntities(WithScopes(ctx, nil), "g1", 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "alice", top[0].Name)
+	assert.Equal(t, 1, top[0].Degree)
+}
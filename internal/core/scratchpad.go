@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrScratchNotFound = errors.New("scratchpad entry not found")
+
+// SetScratch stores value under key in the group's scratchpad, replacing any
+// earlier value. A positive ttl expires the entry that long from now; entries
+// set without one are kept until deleted. Expired entries of the group are
+// purged on the way.
+func (g *Graphiti) SetScratch(ctx context.Context, groupID, key string, value interface{}, ttl time.Duration) (*model.ScratchEntry, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scratchpad value: %w", err)
+	}
+	now := time.Now().UTC()
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.DeleteExpiredScratchEntriesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"now":      now.Format(time.RFC3339),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to purge expired scratchpad entries: %w", err)
+	}
+
+	var expiresAt interface{}
+	if ttl > 0 {
+		expiresAt = now.Add(ttl).Format(time.RFC3339)
+	}
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.SaveScratchEntryQuery, map[string]interface{}{
+		"uuid":       g.UUIDGenerator(),
+		"group_id":   groupID,
+		"key":        key,
+		"value":      string(data),
+		"updated_at": now.Format(time.RFC3339),
+		"expires_at": expiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save scratchpad entry: %w", err)
+	}
+	return g.GetScratch(ctx, groupID, key)
+}
+
+// GetScratch returns the group's scratchpad entry named key, or ErrScratchNotFound.
+func (g *Graphiti) GetScratch(ctx context.Context, groupID, key string) (*model.ScratchEntry, error) {
+	entries, err := g.scratchEntries(ctx, groupID, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrScratchNotFound
+	}
+	return &entries[0], nil
+}
+
+// ListScratch returns the group's unexpired scratchpad entries ordered by key.
+func (g *Graphiti) ListScratch(ctx context.Context, groupID string) ([]model.ScratchEntry, error) {
+	return g.scratchEntries(ctx, groupID, nil)
+}
+
+// DeleteScratch removes the group's scratchpad entry named key, or returns
+// ErrScratchNotFound if there is none that hasn't expired.
+func (g *Graphiti) DeleteScratch(ctx context.Context, groupID, key string) error {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.DeleteScratchEntryQuery, map[string]interface{}{
+		"group_id": groupID,
+		"key":      key,
+		"now":      time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete scratchpad entry: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return ErrScratchNotFound
+	}
+	if live, _ := res.Records[0].Get("live"); live != true {
+		return ErrScratchNotFound
+	}
+	return nil
+}
+
+// scratchEntries reads the group's live scratchpad entries, all of them when key is nil.
+func (g *Graphiti) scratchEntries(ctx context.Context, groupID string, key interface{}) ([]model.ScratchEntry, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetScratchEntriesQuery, map[string]interface{}{
+		"group_id": groupID,
+		"key":      key,
+		"now":      time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch scratchpad entries: %w", err)
+	}
+	entries, err := driver.ScanRecords[model.ScratchEntry](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scratchpad entries: %w", err)
+	}
+	for i := range entries {
+		entries[i].GroupID = groupID
+		if err := decodeJSONField(res.Records[i], "value", &entries[i].Value); err != nil {
+			return nil, fmt.Errorf("invalid scratchpad value %q: %w", entries[i].Key, err)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScratchpad(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+
+	_, err := g.GetScratch(ctx, "g1", "plan")
+	assert.ErrorIs(t, err, ErrScratchNotFound)
+
+	entry, err := g.SetScratch(ctx, "g1", "plan", map[string]interface{}{"step": 1}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "g1", entry.GroupID)
+	assert.Equal(t, map[string]interface{}{"step": float64(1)}, entry.Value)
+	assert.Nil(t, entry.ExpiresAt)
+
+	entry, err = g.SetScratch(ctx, "g1", "plan", "done", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "done", entry.Value)
+	require.NotNil(t, entry.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *entry.ExpiresAt, time.Minute)
+
+	// Scratchpads are per group and stay out of the graph
+	_, err = g.GetScratch(ctx, "g2", "plan")
+	assert.ErrorIs(t, err, ErrScratchNotFound)
+	stats, err := g.GetGroupStats(ctx, "g1")
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entities)
+
+	// Expired entries read as missing and are purged by the next set
+	past := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	_, err = g.Driver.ExecuteQuery(ctx, driver.SaveScratchEntryQuery, map[string]interface{}{
+		"uuid": "old", "group_id": "g1", "key": "cursor", "value": `"x"`, "updated_at": past, "expires_at": past,
+	})
+	require.NoError(t, err)
+	_, err = g.GetScratch(ctx, "g1", "cursor")
+	assert.ErrorIs(t, err, ErrScratchNotFound)
+	assert.ErrorIs(t, g.DeleteScratch(ctx, "g1", "cursor"), ErrScratchNotFound)
+
+	_, err = g.SetScratch(ctx, "g1", "seen", []string{"a"}, 0)
+	require.NoError(t, err)
+	entries, err := g.ListScratch(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "plan", entries[0].Key)
+	assert.Equal(t, "seen", entries[1].Key)
+
+	require.NoError(t, g.DeleteScratch(ctx, "g1", "plan"))
+	assert.ErrorIs(t, g.DeleteScratch(ctx, "g1", "plan"), ErrScratchNotFound)
+}
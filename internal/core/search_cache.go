@@ -0,0 +1,183 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// SearchCache stores search results per group. Invalidate drops every entry
+// of a group; it is called whenever the group's facts change.
+//
+// Each group has a generation that Invalidate advances. A search reads the
+// generation before it reads the graph and passes it to Set, which drops the
+// results if the group was invalidated in between, so a search racing a write
+// can't cache what the write changed.
+type SearchCache interface {
+	Generation(ctx context.Context, groupID string) int64
+	Get(ctx context.Context, groupID, key string) ([]model.EntityEdge, bool)
+	Set(ctx context.Context, groupID, key string, gen int64, edges []model.EntityEdge)
+	Invalidate(ctx context.Context, groupID string)
+}
+
+// NewSearchCache builds the cache selected by cfg, or returns nil when caching is disabled.
+func NewSearchCache(cfg config.SearchCacheConfig) SearchCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	return newCacheBackend(cfg)
+}
+
+// NewRerankCache builds the cache of reranker orderings selected by cfg, or
+// returns nil when rerank caching is disabled.
+func NewRerankCache(cfg config.SearchCacheConfig) SearchCache {
+	if !cfg.Rerank {
+		return nil
+	}
+	return newCacheBackend(cfg)
+}
+
+func newCacheBackend(cfg config.SearchCacheConfig) SearchCache {
+	ttl := 5 * time.Minute
+	if cfg.TTLSeconds > 0 {
+		ttl = time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	if cfg.Backend == "redis" {
+		return NewRedisSearchCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, ttl)
+	}
+	maxEntries := 1000
+	if cfg.MaxEntries > 0 {
+		maxEntries = cfg.MaxEntries
+	}
+	return NewMemorySearchCache(maxEntries, ttl)
+}
+
+// invalidateSearchCache drops cached results for a group whose facts changed
+// and tells the GroupChangeHooks.
+func (g *Graphiti) invalidateSearchCache(ctx context.Context, groupID string) {
+	if g.SearchCache != nil {
+		g.SearchCache.Invalidate(ctx, groupID)
+	}
+	if g.RerankCache != nil {
+		g.RerankCache.Invalidate(ctx, groupID)
+	}
+	g.hooks.runGroupChanged(ctx, groupID)
+}
+
+// searchCacheKey identifies a search by its normalized query and filter.
+func searchCacheKey(query string, filter *model.SearchFilter) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	filterJSON := []byte("null")
+	if filter != nil {
+		filterJSON, _ = json.Marshal(filter) // Map keys marshal in sorted order
+	}
+	sum := sha256.Sum256([]byte(normalized + "\x00" + string(filterJSON)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// MemorySearchCache is a size- and TTL-bounded in-process SearchCache.
+type MemorySearchCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // Oldest first
+	entries map[string]*list.Element
+	groups  map[string]map[string]bool // groupID -> entry keys
+	gens    map[string]int64
+}
+
+type memoryCacheEntry struct {
+	groupID, key string
+	edges        []model.EntityEdge
+	expires      time.Time
+}
+
+func NewMemorySearchCache(maxEntries int, ttl time.Duration) *MemorySearchCache {
+	return &MemorySearchCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		groups:     make(map[string]map[string]bool),
+		gens:       make(map[string]int64),
+	}
+}
+
+func (c *MemorySearchCache) Generation(ctx context.Context, groupID string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gens[groupID]
+}
+
+func (c *MemorySearchCache) Get(ctx context.Context, groupID, key string) ([]model.EntityEdge, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[groupID+"\x00"+key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryCacheEntry)
+	if c.now().After(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	return append([]model.EntityEdge(nil), entry.edges...), true
+}
+
+func (c *MemorySearchCache) Set(ctx context.Context, groupID, key string, gen int64, edges []model.EntityEdge) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gens[groupID] {
+		return
+	}
+	id := groupID + "\x00" + key
+	if el, ok := c.entries[id]; ok {
+		c.remove(el)
+	}
+	entry := &memoryCacheEntry{groupID: groupID, key: id, edges: append([]model.EntityEdge(nil), edges...), expires: c.now().Add(c.ttl)}
+	c.entries[id] = c.order.PushBack(entry)
+	if c.groups[groupID] == nil {
+		c.groups[groupID] = make(map[string]bool)
+	}
+	c.groups[groupID][id] = true
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+func (c *MemorySearchCache) Invalidate(ctx context.Context, groupID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gens[groupID]++
+	for id := range c.groups[groupID] {
+		c.remove(c.entries[id])
+	}
+}
+
+// Len returns the number of cached results, including expired ones not yet evicted.
+func (c *MemorySearchCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemorySearchCache) remove(el *list.Element) {
+	entry := el.Value.(*memoryCacheEntry)
+	c.order.Remove(el)
+	delete(c.entries, entry.key)
+	delete(c.groups[entry.groupID], entry.key)
+	if len(c.groups[entry.groupID]) == 0 {
+		delete(c.groups, entry.groupID)
+	}
+}
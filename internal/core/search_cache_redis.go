@@ -0,0 +1,225 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// RedisSearchCache shares search results between carbon instances through Redis.
+// Each group has a generation counter that is part of every entry key, so
+// invalidating a group is a single INCR and stale entries expire by TTL.
+// Results are stored under the generation their search started at, where
+// lookups no longer find them once the group was invalidated.
+// Redis errors are logged and treated as cache misses.
+type RedisSearchCache struct {
+	addr     string
+	password string
+	db       int
+	ttl      time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+func NewRedisSearchCache(addr, password string, db int, ttl time.Duration) *RedisSearchCache {
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	return &RedisSearchCache{addr: addr, password: password, db: db, ttl: ttl}
+}
+
+// Generation returns the group's generation, or -1 when Redis can't be read,
+// which Set ignores.
+func (c *RedisSearchCache) Generation(ctx context.Context, groupID string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gen, err := c.generation(ctx, groupID)
+	if err != nil {
+		log.Printf("Search cache generation of group %s failed: %v", groupID, err)
+		return -1
+	}
+	return gen
+}
+
+func (c *RedisSearchCache) Get(ctx context.Context, groupID, key string) ([]model.EntityEdge, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gen, err := c.generation(ctx, groupID)
+	if err != nil {
+		log.Printf("Search cache get failed: %v", err)
+		return nil, false
+	}
+	reply, err := c.do(ctx, "GET", entryKey(groupID, gen, key))
+	if err != nil {
+		log.Printf("Search cache get failed: %v", err)
+		return nil, false
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return nil, false
+	}
+	var edges []model.EntityEdge
+	if err := json.Unmarshal([]byte(data), &edges); err != nil {
+		return nil, false
+	}
+	return edges, true
+}
+
+func (c *RedisSearchCache) Set(ctx context.Context, groupID, key string, gen int64, edges []model.EntityEdge) {
+	if gen < 0 {
+		return
+	}
+	data, err := json.Marshal(edges)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.do(ctx, "SET", entryKey(groupID, gen, key), string(data), "EX", strconv.Itoa(max(1, int(c.ttl.Seconds())))); err != nil {
+		log.Printf("Search cache set failed: %v", err)
+	}
+}
+
+func (c *RedisSearchCache) Invalidate(ctx context.Context, groupID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.do(ctx, "INCR", "carbon:search:gen:"+groupID); err != nil {
+		log.Printf("Search cache invalidation of group %s failed: %v", groupID, err)
+	}
+}
+
+// Close releases the Redis connection.
+func (c *RedisSearchCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reset()
+}
+
+// generation reads the group's counter. Callers hold c.mu.
+func (c *RedisSearchCache) generation(ctx context.Context, groupID string) (int64, error) {
+	reply, err := c.do(ctx, "GET", "carbon:search:gen:"+groupID)
+	if err != nil {
+		return 0, err
+	}
+	gen, _ := reply.(string) // A group never invalidated has no counter yet
+	if gen == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(gen, 10, 64)
+}
+
+func entryKey(groupID string, gen int64, key string) string {
+	return fmt.Sprintf("carbon:search:%s:%d:%s", groupID, gen, key)
+}
+
+// do sends one command and reads its reply, dialing on first use. Any error
+// drops the connection so the next call starts clean. Callers hold c.mu.
+func (c *RedisSearchCache) do(ctx context.Context, args ...string) (any, error) {
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(ctx, args...)
+	if err != nil {
+		c.reset()
+	}
+	return reply, err
+}
+
+func (c *RedisSearchCache) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	c.conn = conn
+	c.rw = bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if c.password != "" {
+		if _, err := c.roundTrip(ctx, "AUTH", c.password); err != nil {
+			c.reset()
+			return fmt.Errorf("failed to authenticate with redis: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip(ctx, "SELECT", strconv.Itoa(c.db)); err != nil {
+			c.reset()
+			return fmt.Errorf("failed to select redis db %d: %w", c.db, err)
+		}
+	}
+	return nil
+}
+
+func (c *RedisSearchCache) reset() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.rw = nil, nil
+	return err
+}
+
+func (c *RedisSearchCache) roundTrip(ctx context.Context, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(2 * time.Second)
+	}
+	c.conn.SetDeadline(deadline)
+
+	fmt.Fprintf(c.rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.rw.Flush(); err != nil {
+		return nil, err
+	}
+	return readRESP(c.rw.Reader)
+}
+
+// readRESP reads a single RESP2 reply. Bulk strings become string, a nil bulk
+// becomes nil, integers become int64 and arrays are not expected.
+func readRESP(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, errors.New("malformed redis reply")
+	}
+	body := line[1 : len(line)-2]
+	switch line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line[0])
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCacheKey_NormalizesQuery(t *testing.T) {
+	assert.Equal(t, searchCacheKey("Where does  Alice live?", nil), searchCacheKey(" where does alice LIVE? ", nil))
+	assert.NotEqual(t, searchCacheKey("alice", nil), searchCacheKey("alice", &model.SearchFilter{RelationTypes: []string{"LIVES_IN"}}))
+}
+
+func TestMemorySearchCache_ExpiryAndEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemorySearchCache(2, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "g1", "a", 0, []model.EntityEdge{{UUID: "e1"}})
+	c.Set(ctx, "g1", "b", 0, nil)
+	c.Set(ctx, "g2", "a", 0, nil)
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get(ctx, "g1", "a")
+	assert.False(t, ok, "oldest entry is evicted")
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get(ctx, "g1", "b")
+	assert.False(t, ok, "expired entry is a miss")
+
+	c.Invalidate(ctx, "g2")
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestMemorySearchCache_SkipsResultsFromBeforeInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemorySearchCache(10, time.Minute)
+
+	gen := c.Generation(ctx, "g1")
+	c.Invalidate(ctx, "g1") // A write lands while the search runs
+	c.Set(ctx, "g1", "k", gen, []model.EntityEdge{{UUID: "e1"}})
+	_, ok := c.Get(ctx, "g1", "k")
+	assert.False(t, ok)
+
+	c.Set(ctx, "g1", "k", c.Generation(ctx, "g1"), []model.EntityEdge{{UUID: "e1"}})
+	_, ok = c.Get(ctx, "g1", "k")
+	assert.True(t, ok)
+}
+
+func TestSearch_CachedUntilIngest(t *testing.T) {
+	ctx := context.Background()
+	g := linkingTestGraph(t, nil)
+	g.Config.Search.EntityLinking = false
+	g.SearchCache = NewSearchCache(config.SearchCacheConfig{Enabled: true})
+
+	edges, err := g.Search(ctx, "g1", "likes tea")
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+
+	seedFact(t, g.Driver, "g1", "e3", "Alice", "Bob", "LIKES", "Alice likes tea with Bob", map[string]interface{}{
+		"fact_embedding": []float32{0.5, 0.5},
+	})
+	edges, err = g.Search(ctx, "g1", "Likes  TEA")
+	require.NoError(t, err)
+	assert.Len(t, edges, 2, "repeated query is served from the cache")
+
+	// Ingest invalidates the group even when extraction yields nothing
+	g.AddEpisode(ctx, "g1", "msg", "nothing new", "", "")
+	edges, err = g.Search(ctx, "g1", "likes tea")
+	require.NoError(t, err)
+	assert.Len(t, edges, 3)
+}
+
+// fakeRedis serves GET, SET and INCR over RESP for a single test.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func startFakeRedis(t *testing.T) (string, *fakeRedis) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeRedis{data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return ln.Addr().String(), f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			r.ReadString('\n')
+			arg, _ := r.ReadString('\n')
+			args[i] = strings.TrimSuffix(arg, "\r\n")
+		}
+		f.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if v, ok := f.data[args[1]]; ok {
+				conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+			} else {
+				conn.Write([]byte("$-1\r\n"))
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			conn.Write([]byte("+OK\r\n"))
+		case "INCR":
+			v, _ := strconv.Atoi(f.data[args[1]])
+			f.data[args[1]] = strconv.Itoa(v + 1)
+			conn.Write([]byte(":" + f.data[args[1]] + "\r\n"))
+		default:
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestRedisSearchCache(t *testing.T) {
+	ctx := context.Background()
+	addr, f := startFakeRedis(t)
+	c := NewRedisSearchCache(addr, "", 0, time.Minute)
+	defer c.Close()
+
+	_, ok := c.Get(ctx, "g1", "k")
+	assert.False(t, ok)
+
+	gen := c.Generation(ctx, "g1")
+	assert.Zero(t, gen)
+	c.Set(ctx, "g1", "k", gen, []model.EntityEdge{{UUID: "e1", Fact: "Alice likes tea"}})
+	edges, ok := c.Get(ctx, "g1", "k")
+	require.True(t, ok)
+	assert.Equal(t, "Alice likes tea", edges[0].Fact)
+	_, ok = c.Get(ctx, "g2", "k")
+	assert.False(t, ok)
+
+	c.Invalidate(ctx, "g1")
+	_, ok = c.Get(ctx, "g1", "k")
+	assert.False(t, ok)
+	f.mu.Lock()
+	assert.Equal(t, "1", f.data["carbon:search:gen:g1"])
+	f.mu.Unlock()
+
+	// Results of a search that started before the invalidation stay unread
+	c.Set(ctx, "g1", "k", gen, []model.EntityEdge{{UUID: "e1"}})
+	_, ok = c.Get(ctx, "g1", "k")
+	assert.False(t, ok)
+}
+
+func TestRedisSearchCache_UnreachableIsMiss(t *testing.T) {
+	c := NewRedisSearchCache("127.0.0.1:1", "", 0, time.Minute)
+	assert.EqualValues(t, -1, c.Generation(context.Background(), "g1"))
+	c.Set(context.Background(), "g1", "k", 0, nil)
+	_, ok := c.Get(context.Background(), "g1", "k")
+	assert.False(t, ok)
+}
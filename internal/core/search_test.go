@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSearch(t *testing.T) {
@@ -80,3 +83,103 @@ func TestSaveEntityNode(t *testing.T) {
 	assert.Equal(t, "EntityName", node.Name)
 	assert.Equal(t, []float32{1.0, 2.0}, node.NameEmbedding)
 }
+
+func TestSearch_RoutesToReader(t *testing.T) {
+	mockDriver := &MockDriver{}
+	g := NewGraphiti(mockDriver, &MockLLM{}, &MockEmbedder{}, nil, &config.Config{})
+
+	_, err := g.Search(context.Background(), "g1", "query")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mockDriver.ReadQueries) // The group's synonyms, then the text search
+}
+
+func TestSearch_MalformedRecordReturnsError(t *testing.T) {
+	mockDriver := &MockDriver{
+		MockResult: neo4j.EagerResult{
+			Records: []*neo4j.Record{
+				{Keys: []string{"uuid", "fact"}, Values: []interface{}{int64(7), nil}},
+			},
+		},
+	}
+	g := NewGraphiti(mockDriver, &MockLLM{}, &MockEmbedder{}, nil, &config.Config{})
+
+	_, err := g.Search(context.Background(), "g1", "query")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "uuid")
+}
+
+// reverseReranker ranks documents in reverse order.
+type reverseReranker struct{}
+
+func (reverseReranker) Rank(ctx context.Context, query string, documents []string) ([]int, error) {
+	indices := make([]int, len(documents))
+	for i := range documents {
+		indices[i] = len(documents) - 1 - i
+	}
+	return indices, nil
+}
+
+func TestSearchDebug(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, mapEmbedder{"q": {1, 0}}, reverseReranker{},
+		&config.Config{SearchCache: config.SearchCacheConfig{Enabled: true}})
+	seedEntities(t, g.Driver, "g1", "a", "b")
+	for uuid, emb := range map[string][]float32{"close": {1, 0.1}, "far": {0.1, 1}} {
+		seedFact(t, g.Driver, "g1", uuid, "a", "b", "KNOWS", uuid, map[string]interface{}{"fact_embedding": emb})
+	}
+
+	// A cached result is not used: the trace describes a real run
+	_, err := g.Search(ctx, "g1", "q")
+	require.NoError(t, err)
+
+	edges, trace, err := g.SearchDebug(ctx, "g1", "q", nil)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	assert.Equal(t, "vector", trace.Strategy)
+	assert.Equal(t, []string{"default@2"}, trace.EmbeddingModels)
+	assert.Equal(t, model.MetricCosine, trace.Metric)
+	require.Len(t, trace.Candidates, 2)
+	assert.Equal(t, "close", trace.Candidates[0].UUID)
+	require.NotNil(t, trace.Candidates[0].Score)
+	assert.Greater(t, *trace.Candidates[0].Score, *trace.Candidates[1].Score)
+	assert.Equal(t, []string{"far", "close"}, trace.Reranked)
+	assert.Equal(t, []string{"far", "close"}, trace.Results)
+
+	var stages []string
+	for _, st := range trace.Timings {
+		stages = append(stages, st.Stage)
+	}
+	assert.Equal(t, []string{"embed", "retrieve", "rerank", "link"}, stages)
+}
+
+func TestSearchDebugTextFallback(t *testing.T) {
+	g := filterTestGraph(t)
+	_, trace, err := g.SearchDebug(context.Background(), "g1", "fact", &model.SearchFilter{Attributes: map[string]interface{}{"location": "Rome"}})
+	require.NoError(t, err)
+	assert.Equal(t, "text", trace.Strategy)
+	assert.Empty(t, trace.Metric)
+	assert.Len(t, trace.Candidates, 3)
+	assert.Nil(t, trace.Candidates[0].Score)
+	// alice's nested location passes the Cypher prefilter but not the exact check
+	assert.Equal(t, []string{"e1"}, trace.Filtered)
+	assert.Equal(t, []string{"e2", "e3"}, trace.Results)
+}
+
+func TestRankReinforcedEdges(t *testing.T) {
+	edges := []model.EntityEdge{
+		{UUID: "a", MentionCount: 1},
+		{UUID: "b", MentionCount: 1},
+		{UUID: "c", MentionCount: 8},
+		{UUID: "d"}, // Saved before mention counts
+	}
+	uuids := func(edges []model.EntityEdge) []string {
+		var out []string
+		for _, e := range edges {
+			out = append(out, e.UUID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"a", "c", "b", "d"}, uuids(rankReinforcedEdges(edges, 0.5)))
+	assert.Equal(t, []string{"c", "a", "b", "d"}, uuids(rankReinforcedEdges(edges, 2)))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, uuids(edges))
+}
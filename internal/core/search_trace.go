@@ -0,0 +1,82 @@
+package core
+
+import (
+	"slices"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// traceStage records the time since start as a stage of trace and returns the
+// start of the next stage. A nil trace records nothing.
+func traceStage(trace *model.SearchTrace, stage string, start time.Time) time.Time {
+	now := time.Now()
+	if trace != nil {
+		trace.Timings = append(trace.Timings, model.StageTiming{
+			Stage:      stage,
+			DurationMS: float64(now.Sub(start).Microseconds()) / 1000,
+		})
+	}
+	return now
+}
+
+// traceCandidates records the matches of a search query that the caller may
+// read, with their scores from result.
+func traceCandidates(trace *model.SearchTrace, result neo4j.EagerResult, edges []model.EntityEdge, embeddingModel string) {
+	if trace == nil {
+		return
+	}
+	scores := recordScores(result)
+	for _, e := range edges {
+		c := model.SearchCandidate{UUID: e.UUID, Fact: e.Fact, EmbeddingModel: embeddingModel}
+		if score, ok := scores[e.UUID]; ok {
+			c.Score = &score
+		}
+		trace.Candidates = append(trace.Candidates, c)
+	}
+}
+
+// traceFiltered records the candidates the attribute filter dropped.
+func traceFiltered(trace *model.SearchTrace, kept []model.EntityEdge) {
+	if trace == nil {
+		return
+	}
+	isKept := make(map[string]bool, len(kept))
+	for _, e := range kept {
+		isKept[e.UUID] = true
+	}
+	for _, c := range trace.Candidates {
+		if !isKept[c.UUID] && !slices.Contains(trace.Filtered, c.UUID) {
+			trace.Filtered = append(trace.Filtered, c.UUID)
+		}
+	}
+}
+
+func edgeUUIDs(edges []model.EntityEdge) []string {
+	uuids := make([]string, len(edges))
+	for i, e := range edges {
+		uuids[i] = e.UUID
+	}
+	return uuids
+}
+
+// recordScores maps the UUID of each scored record to its score.
+func recordScores(result neo4j.EagerResult) map[string]float64 {
+	return recordFloats(result, "score")
+}
+
+// recordFloats maps the UUID of each record to its float value of key.
+func recordFloats(result neo4j.EagerResult, key string) map[string]float64 {
+	scores := make(map[string]float64, len(result.Records))
+	for _, rec := range result.Records {
+		uuid, _ := rec.Get("uuid")
+		score, _ := rec.Get(key)
+		if u, ok := uuid.(string); ok {
+			if s, ok := score.(float64); ok {
+				scores[u] = s
+			}
+		}
+	}
+	return scores
+}
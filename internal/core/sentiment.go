@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// factSentiment returns the attributes annotating a new fact with the user's
+// sentiment and emotion when [ingest] sentiment is on and the fact is about
+// the group's user entity, else nil. Annotation is best effort: a failure is
+// logged and leaves the fact unannotated.
+func (g *Graphiti) factSentiment(ctx context.Context, groupID string, e model.ExtractedEdge) map[string]interface{} {
+	if g.Config == nil || !g.Config.Ingest.Sentiment {
+		return nil
+	}
+	user := userEntityID(groupID)
+	if e.SourceNodeUUID != user && e.TargetNodeUUID != user {
+		return nil
+	}
+	name := g.userName()
+	if node, err := g.getEntity(ctx, user); err == nil {
+		name = node.Name
+	}
+	result, err := g.Extractor.ExtractSentiment(ctx, name, e.Fact)
+	if err != nil {
+		log.Printf("Failed to annotate sentiment of %q: %v", e.Fact, err)
+		return nil
+	}
+	sentiment := strings.ToLower(strings.TrimSpace(result.Sentiment))
+	if !model.ValidSentiment(sentiment) {
+		log.Printf("Failed to annotate sentiment of %q: unknown sentiment %q", e.Fact, result.Sentiment)
+		return nil
+	}
+	attrs := map[string]interface{}{"sentiment": sentiment}
+	if emotion := normalizeEmotion(result.Emotion); emotion != "" {
+		attrs["emotion"] = emotion
+	}
+	return attrs
+}
+
+// normalizeEmotion is how emotions are stored and matched: trimmed and lowercase.
+func normalizeEmotion(emotion string) string {
+	return strings.ToLower(strings.TrimSpace(emotion))
+}
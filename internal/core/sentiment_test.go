@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactSentiment(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{
+		Ingest: config.IngestConfig{Sentiment: true},
+	})
+	g.Extractor.Prompts.Sentiment = "%s %s"
+	g.Extractor.LLM = llmFunc(func(prompt string) string {
+		if strings.Contains(prompt, "traffic") {
+			return `{"sentiment": "Negative", "emotion": "Frustrated"}`
+		}
+		return `{"sentiment": "positive", "emotion": "likes"}`
+	})
+	user, err := g.EnsureUserEntity(ctx, "g1", model.UserProfile{Name: "Sam"})
+	require.NoError(t, err)
+	for _, n := range []string{"acme", "bob"} {
+		_, err = g.SaveEntityNodeWithUUID(ctx, n, n, "g1", "")
+		require.NoError(t, err)
+	}
+
+	now := time.Now().UTC()
+	for _, e := range []model.ExtractedEdge{
+		{SourceNodeUUID: user.UUID, TargetNodeUUID: "acme", RelationType: "LIKES", Fact: "Sam likes the fact that Acme is remote"},
+		{SourceNodeUUID: "acme", TargetNodeUUID: user.UUID, RelationType: "STRESSES", Fact: "The traffic fact of the commute to Acme stresses Sam"},
+		{SourceNodeUUID: "bob", TargetNodeUUID: "acme", RelationType: "WORKS_AT", Fact: "Bob works at Acme, in fact"},
+	} {
+		_, err := g.processEdge(ctx, e, "ep1", "g1", now)
+		require.NoError(t, err)
+	}
+
+	edges, err := g.getGroupEdges(ctx, "g1")
+	require.NoError(t, err)
+	sentiments := map[string]interface{}{}
+	for _, e := range edges {
+		fact, err := g.GetFact(ctx, e.UUID)
+		require.NoError(t, err)
+		sentiments[e.Name] = fact.Attributes["emotion"]
+	}
+	assert.Equal(t, map[string]interface{}{"LIKES": "likes", "STRESSES": "frustrated", "WORKS_AT": nil}, sentiments, "only facts about the user are annotated")
+
+	names := func(filter *model.SearchFilter) []string {
+		found, err := g.SearchWithFilter(ctx, "g1", "fact", filter)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range found {
+			names = append(names, e.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"STRESSES"}, names(&model.SearchFilter{Sentiments: []string{model.SentimentNegative}}))
+	assert.Equal(t, []string{"LIKES"}, names(&model.SearchFilter{Emotions: []string{"Likes", "loves"}}))
+	_, err = g.SearchWithFilter(ctx, "g1", "fact", &model.SearchFilter{Sentiments: []string{"angry"}})
+	assert.Error(t, err)
+}
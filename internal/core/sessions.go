@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// CreateSession starts a conversation session in the group, backed by the
+// saga named name. A session is created for an existing saga of that name
+// too, so clients can resume a conversation by name; without a name a new
+// saga is always started.
+func (g *Graphiti) CreateSession(ctx context.Context, groupID, name string) (*model.Session, error) {
+	now := time.Now().UTC().Truncate(time.Second) // As stored
+	if name == "" {
+		name = "session-" + g.UUIDGenerator()
+	}
+	saga, err := g.getOrCreateSaga(ctx, name, groupID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return &model.Session{ID: saga.UUID, GroupID: saga.GroupID, Name: saga.Name, CreatedAt: saga.CreatedAt}, nil
+}
+
+// GetSession returns the session with the given ID, or ErrSessionNotFound.
+func (g *Graphiti) GetSession(ctx context.Context, sessionID string) (*model.Session, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetSagaQuery, map[string]interface{}{"uuid": sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch session: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, ErrSessionNotFound
+	}
+	var session model.Session
+	if err := driver.ScanRecord(res.Records[0], &session); err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return &session, nil
+}
+
+// AddSessionMessage ingests a message of the session into the session's
+// group and saga, after the session's earlier messages. The episode is named
+// after the speaker's role and its content says who spoke.
+func (g *Graphiti) AddSessionMessage(ctx context.Context, sessionID, role, content, schema string) error {
+	session, err := g.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	return g.AddEpisode(ctx, session.GroupID, sessionEpisodeName(role), sessionEpisodeContent(role, content), session.Name, schema)
+}
+
+func sessionEpisodeName(role string) string {
+	if role = strings.TrimSpace(role); role == "" {
+		return "message"
+	}
+	return role + " message"
+}
+
+// sessionEpisodeContent prefixes content with the role that said it, so
+// extraction can tell the user's statements from the assistant's.
+func sessionEpisodeContent(role, content string) string {
+	if role = strings.TrimSpace(role); role == "" {
+		return content
+	}
+	return role + ": " + content
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	llmClient := llmFunc(func(prompt string) string { return `{"extracted_entities": []}` })
+	cfg := &config.Config{Extraction: config.ExtractionPrompts{Nodes: "%s|%s"}}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	_, err := g.GetSession(ctx, "missing")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.ErrorIs(t, g.AddSessionMessage(ctx, "missing", "user", "hi", ""), ErrSessionNotFound)
+
+	session, err := g.CreateSession(ctx, "g1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "g1", session.GroupID)
+	assert.NotEmpty(t, session.Name)
+	other, err := g.CreateSession(ctx, "g1", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, session.ID, other.ID)
+
+	// Named sessions resume their saga
+	named, err := g.CreateSession(ctx, "g1", "support")
+	require.NoError(t, err)
+	again, err := g.CreateSession(ctx, "g1", "support")
+	require.NoError(t, err)
+	assert.Equal(t, named, again)
+
+	got, err := g.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session, got)
+
+	// Messages within the same second are still chained in order
+	for _, m := range []struct{ role, content string }{
+		{"user", "I moved to Lisbon."},
+		{"assistant", "Noted, you live in Lisbon."},
+		{"", "Thanks!"},
+	} {
+		require.NoError(t, g.AddSessionMessage(ctx, session.ID, m.role, m.content, ""))
+	}
+
+	export, err := g.ExportGraph(ctx, "g1")
+	require.NoError(t, err)
+	episodes := make(map[string]map[string]interface{})
+	for _, n := range export.Nodes {
+		if n.Labels[0] == "Episodic" {
+			episodes[n.Properties["uuid"].(string)] = n.Properties
+		}
+	}
+	next := make(map[string]string)
+	var members int
+	for _, e := range export.Edges {
+		switch {
+		case e.Type == "NEXT_EPISODE":
+			next[e.SourceUUID] = e.TargetUUID
+		case e.Type == "HAS_EPISODE" && e.SourceUUID == session.ID:
+			members++
+		}
+	}
+	assert.Equal(t, 3, members)
+	var first string
+	for uuid := range episodes {
+		isNext := false
+		for _, target := range next {
+			isNext = isNext || target == uuid
+		}
+		if !isNext {
+			first = uuid
+		}
+	}
+	var chain []string
+	for uuid := first; uuid != ""; uuid = next[uuid] {
+		chain = append(chain, episodes[uuid]["name"].(string)+": "+episodes[uuid]["content"].(string))
+	}
+	assert.Equal(t, []string{
+		"user message: user: I moved to Lisbon.",
+		"assistant message: assistant: Noted, you live in Lisbon.",
+		"message: Thanks!",
+	}, chain)
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// GetGroupStats returns aggregate counts and freshness timestamps for a group.
+func (g *Graphiti) GetGroupStats(ctx context.Context, groupID string) (*model.GroupStats, error) {
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetGroupStatsQuery, map[string]interface{}{
+		"group_id": groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group stats: %w", err)
+	}
+
+	stats := &model.GroupStats{}
+	if len(res.Records) > 0 {
+		if err := driver.ScanRecord(res.Records[0], stats); err != nil {
+			return nil, fmt.Errorf("failed to read group stats: %w", err)
+		}
+	}
+	stats.GroupID = groupID
+	stats.InvalidEdges = stats.Edges - stats.ValidEdges
+	if stats.Entities > 0 {
+		stats.AverageDegree = 2 * float64(stats.ValidEdges) / float64(stats.Entities)
+	}
+	for _, t := range []*time.Time{stats.LastEntityAt, stats.LastEdgeAt, stats.LastEpisodeAt} {
+		if t != nil && (stats.LastUpdatedAt == nil || t.After(*stats.LastUpdatedAt)) {
+			stats.LastUpdatedAt = t
+		}
+	}
+	return stats, nil
+}
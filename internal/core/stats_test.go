@@ -0,0 +1,36 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGroupStats(t *testing.T) {
+	ctx := context.Background()
+	d := driver.NewMemoryDriver()
+	g := NewGraphiti(d, &MockLLM{}, nil, nil, &config.Config{})
+
+	for _, n := range []string{"a", "b"} {
+		seedEntity(t, d, "g1", n, map[string]interface{}{"created_at": "2024-01-01T00:00:00Z"})
+	}
+	for _, e := range []struct{ uuid, invalidAt string }{{"e1", ""}, {"e2", "2024-02-01T00:00:00Z"}} {
+		seedFact(t, d, "g1", e.uuid, "a", "b", "KNOWS", e.uuid, map[string]interface{}{"invalid_at": e.invalidAt, "created_at": "2024-01-02T00:00:00Z"})
+	}
+	require.NoError(t, g.saveEpisodeNode(ctx, "ep1", "message", "g1", "hello", mustTime("2024-03-01T00:00:00Z")))
+
+	stats, err := g.GetGroupStats(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Entities)
+	assert.Equal(t, 2, stats.Edges)
+	assert.Equal(t, 1, stats.ValidEdges)
+	assert.Equal(t, 1, stats.InvalidEdges)
+	assert.Equal(t, 1, stats.Episodes)
+	assert.Equal(t, 1.0, stats.AverageDegree)
+	require.NotNil(t, stats.LastUpdatedAt)
+	assert.Equal(t, mustTime("2024-03-01T00:00:00Z"), *stats.LastUpdatedAt)
+}
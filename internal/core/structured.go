@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/core/quantity"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+var ErrInvalidStructuredEpisode = errors.New("invalid structured episode")
+
+// AddStructuredEpisode ingests entities and facts given directly, making no
+// LLM calls: entities are matched to the group's by name (ignoring case) and
+// first-person names to the user entity, facts are deduplicated, exclusive
+// facts invalidate the ones they replace, and everything is embedded and
+// saved like extracted data. Summaries are only those the entities carry.
+func (g *Graphiti) AddStructuredEpisode(ctx context.Context, groupID string, ep model.StructuredEpisode) (*model.StructuredResult, error) {
+	if err := validateStructured(ep); err != nil {
+		return nil, err
+	}
+	episodeUUID := g.UUIDGenerator()
+	now := time.Now().UTC()
+	defer g.invalidateSearchCache(ctx, groupID)
+
+	group, err := g.ensureGroup(ctx, groupID, now)
+	if err != nil {
+		return nil, err
+	}
+	g = g.forGroup(group)
+	if err := g.enforceGroupLimits(ctx, group); err != nil {
+		return nil, err
+	}
+
+	content := ep.Content
+	if content == "" {
+		facts := make([]string, len(ep.Facts))
+		for i, f := range ep.Facts {
+			facts[i] = f.Fact
+		}
+		content = strings.Join(facts, "\n")
+	}
+	verdict, err := g.moderate(ctx, group, ep.Name, content)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.saveEpisode(ctx, model.EpisodicNode{
+		UUID: episodeUUID, Name: ep.Name, GroupID: groupID, Content: content, CreatedAt: now, ValidAt: now,
+		Source: model.EpisodeSourceStructured, SourceDescription: "structured input",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save episode: %w", err)
+	}
+	if err := g.recordModeration(ctx, groupID, ep.Name, episodeUUID, content, verdict); err != nil {
+		return nil, err
+	}
+	if g.Config != nil && g.Config.Ingest.Topics == TopicsKeywords {
+		g.tagTopics(ctx, episodeUUID, content)
+	}
+
+	nodes, uuids, err := g.resolveStructuredEntities(ctx, groupID, ep.Entities, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.saveNewEntitiesAndMentions(ctx, nodes, episodeUUID, groupID, now); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, f := range ep.Facts {
+		e := model.ExtractedEdge{
+			SourceNodeUUID: uuids[strings.ToLower(strings.TrimSpace(f.Source))],
+			TargetNodeUUID: uuids[strings.ToLower(strings.TrimSpace(f.Target))],
+			RelationType:   f.Relation,
+			Fact:           f.Fact,
+		}
+		if _, err := g.resolveEdge(ctx, e, &f, episodeUUID, groupID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to save facts: %w", errors.Join(errs...))
+	}
+
+	if ep.Saga != "" {
+		if err := g.handleSaga(ctx, ep.Saga, groupID, episodeUUID, now); err != nil {
+			return nil, fmt.Errorf("failed to handle saga: %w", err)
+		}
+	}
+
+	result := &model.StructuredResult{EpisodeUUID: episodeUUID, Entities: make(map[string]string, len(ep.Entities))}
+	for _, ent := range ep.Entities {
+		result.Entities[ent.Name] = uuids[strings.ToLower(strings.TrimSpace(ent.Name))]
+	}
+	return result, nil
+}
+
+// validateStructured checks that entity names are unique and that facts only
+// name listed entities.
+func validateStructured(ep model.StructuredEpisode) error {
+	if len(ep.Entities) == 0 {
+		return fmt.Errorf("%w: no entities", ErrInvalidStructuredEpisode)
+	}
+	names := make(map[string]bool, len(ep.Entities))
+	for _, ent := range ep.Entities {
+		key := strings.ToLower(strings.TrimSpace(ent.Name))
+		if key == "" {
+			return fmt.Errorf("%w: entity without a name", ErrInvalidStructuredEpisode)
+		}
+		if names[key] {
+			return fmt.Errorf("%w: entity %q is listed twice", ErrInvalidStructuredEpisode, ent.Name)
+		}
+		names[key] = true
+	}
+	for i, f := range ep.Facts {
+		for _, name := range []string{f.Source, f.Target} {
+			if !names[strings.ToLower(strings.TrimSpace(name))] {
+				return fmt.Errorf("%w: fact %d names unknown entity %q", ErrInvalidStructuredEpisode, i, name)
+			}
+		}
+		if strings.TrimSpace(f.Relation) == "" || strings.TrimSpace(f.Fact) == "" {
+			return fmt.Errorf("%w: fact %d needs a relation and a fact", ErrInvalidStructuredEpisode, i)
+		}
+	}
+	return nil
+}
+
+// resolveStructuredEntities turns structured entities into the nodes to save,
+// the group's existing entity for a name it already has, updated with the
+// given type, summary and attributes. It also returns their UUIDs by
+// lowercase name.
+func (g *Graphiti) resolveStructuredEntities(ctx context.Context, groupID string, entities []model.StructuredEntity, now time.Time) ([]model.EntityNode, map[string]string, error) {
+	existing, err := g.getGroupNodes(ctx, groupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch group nodes: %w", err)
+	}
+	byName := make(map[string]string, len(existing))
+	for _, n := range existing {
+		byName[strings.ToLower(n.Name)] = n.UUID
+	}
+
+	var nodes []model.EntityNode
+	for _, ent := range entities {
+		attrs := ent.Attributes
+		if g.Config != nil && g.Config.Ingest.NormalizeQuantities {
+			attrs = quantity.NormalizeAttributes(attrs)
+		}
+		node := model.EntityNode{UUID: g.UUIDGenerator(), Name: strings.TrimSpace(ent.Name), GroupID: groupID, CreatedAt: now, Labels: []string{"Entity"}}
+		if uuid, ok := byName[strings.ToLower(node.Name)]; ok {
+			found, err := g.getEntity(ctx, uuid)
+			if err != nil {
+				return nil, nil, err
+			}
+			node = *found
+		}
+		if label := driver.LabelName(ent.Type); label != "" && !slices.Contains(node.Labels, label) {
+			node.Labels = append(node.Labels, label)
+		}
+		if ent.Summary != "" {
+			node.Summary = ent.Summary
+		}
+		if len(attrs) > 0 && node.Attributes == nil {
+			node.Attributes = make(map[string]interface{}, len(attrs))
+		}
+		for k, v := range attrs {
+			node.Attributes[k] = v
+		}
+		nodes = append(nodes, node)
+	}
+
+	rest, user, err := g.bindUserEntity(ctx, groupID, nodes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to bind user entity: %w", err)
+	}
+	uuids := make(map[string]string, len(entities))
+	for _, n := range nodes {
+		if user != nil && refersToUser([]model.EntityNode{n}, user.Name) {
+			uuids[strings.ToLower(n.Name)] = user.UUID
+		} else {
+			uuids[strings.ToLower(n.Name)] = n.UUID
+		}
+	}
+	if user != nil {
+		rest = append([]model.EntityNode{*user}, rest...)
+	}
+	return rest, uuids, nil
+}
+
+// exclusiveContradictions is the contradiction check of structured facts: an
+// exclusive fact contradicts its source's valid facts of the same relation
+// type with other targets, and nothing else does.
+func exclusiveContradictions(e model.ExtractedEdge, exclusive bool, history []model.EntityEdge) *model.ContradictionResult {
+	result := &model.ContradictionResult{}
+	if !exclusive {
+		return result
+	}
+	for _, h := range history {
+		if h.InvalidAt == nil && h.TargetUUID != e.TargetNodeUUID && normalizeRelation(h.Name) == normalizeRelation(e.RelationType) {
+			result.ContradictedEdgeUUIDs = append(result.ContradictedEdgeUUIDs, h.UUID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddStructuredEpisode(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		t.Fatalf("structured ingest called the LLM: %s", prompt)
+		return ""
+	}), nil, nil, &config.Config{})
+
+	validAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	first, err := g.AddStructuredEpisode(ctx, "g1", model.StructuredEpisode{
+		Entities: []model.StructuredEntity{
+			{Name: "Alice", Type: "Person", Summary: "An engineer.", Attributes: map[string]interface{}{"role": "engineer"}},
+			{Name: "Berlin", Type: "Place"},
+		},
+		Facts: []model.StructuredFact{
+			{Source: "alice", Target: "Berlin", Relation: "LIVES_IN", Fact: "Alice lives in Berlin", ValidAt: &validAt},
+		},
+	})
+	require.NoError(t, err)
+	alice := first.Entities["Alice"]
+	facts, err := g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	fact, err := g.GetFact(ctx, facts[0].UUID)
+	require.NoError(t, err)
+	require.NotNil(t, fact.ValidAt)
+	assert.Equal(t, validAt, fact.ValidAt.UTC())
+
+	second, err := g.AddStructuredEpisode(ctx, "g1", model.StructuredEpisode{
+		Entities: []model.StructuredEntity{
+			{Name: "ALICE", Attributes: map[string]interface{}{"age": 30.0}},
+			{Name: "Paris", Type: "Place"},
+			{Name: "Berlin"},
+		},
+		Facts: []model.StructuredFact{
+			{Source: "ALICE", Target: "Paris", Relation: "LIVES_IN", Fact: "Alice lives in Paris", Exclusive: true, Attributes: map[string]interface{}{"source": "crm"}},
+			{Source: "ALICE", Target: "Berlin", Relation: "VISITED", Fact: "Alice visited Berlin"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, alice, second.Entities["ALICE"], "entities are matched by name")
+
+	node, err := g.GetEntity(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "An engineer.", node.Summary)
+	assert.Equal(t, map[string]interface{}{"role": "engineer", "age": 30.0}, node.Attributes)
+	assert.Contains(t, node.Labels, "Person")
+
+	facts, err = g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	var valid []string
+	for _, f := range facts {
+		valid = append(valid, f.Fact)
+	}
+	assert.ElementsMatch(t, []string{"Alice lives in Paris", "Alice visited Berlin"}, valid, "the exclusive fact replaces the old one")
+	fact, err = g.GetFact(ctx, fact.UUID)
+	require.NoError(t, err)
+	assert.NotNil(t, fact.InvalidAt)
+
+	// Stating a fact again adds a mention instead of a duplicate
+	_, err = g.AddStructuredEpisode(ctx, "g1", model.StructuredEpisode{
+		Entities: []model.StructuredEntity{{Name: "Alice"}, {Name: "Paris"}},
+		Facts:    []model.StructuredFact{{Source: "Alice", Target: "Paris", Relation: "LIVES_IN", Fact: "Alice lives in Paris"}},
+	})
+	require.NoError(t, err)
+	facts, err = g.ListFacts(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, facts, 2)
+
+	_, err = g.AddStructuredEpisode(ctx, "g1", model.StructuredEpisode{
+		Entities: []model.StructuredEntity{{Name: "Alice"}},
+		Facts:    []model.StructuredFact{{Source: "Alice", Target: "Bob", Relation: "KNOWS", Fact: "Alice knows Bob"}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidStructuredEpisode)
+}
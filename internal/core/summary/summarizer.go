@@ -3,6 +3,7 @@ package summary
 import (
 	"context"
 	"fmt"
+	"strings"
 	
 	"github.com/agenthands/carbon/internal/config"
 	"github.com/agenthands/carbon/internal/core/common"
@@ -131,3 +132,134 @@ func (s *Summarizer) GenerateCommunityName(ctx context.Context, summary string)
 	// Or maybe it's just a name in quotes.
 	return response, nil
 }
+
+// CheckConsistency asks the LLM whether node's summary contradicts its currently valid facts.
+func (s *Summarizer) CheckConsistency(ctx context.Context, node model.EntityNode, facts []string) (*model.SummaryConsistency, error) {
+	if s.Prompts.Consistency == "" {
+		return nil, fmt.Errorf("consistency prompt is not configured")
+	}
+
+	factsList := ""
+	for _, f := range facts {
+		factsList += fmt.Sprintf("- %s\n", f)
+	}
+
+	prompt := fmt.Sprintf(s.Prompts.Consistency, node.Summary, factsList)
+
+	response, err := s.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check summary consistency: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.SummaryConsistency](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse consistency result: %w", err)
+	}
+	return &result, nil
+}
+
+// SummarizePath explains, with respect to query, what a chain of facts (in path order) says as a whole.
+func (s *Summarizer) SummarizePath(ctx context.Context, query string, facts []string) (string, error) {
+	if s.Prompts.Path == "" {
+		return "", fmt.Errorf("path prompt is not configured")
+	}
+
+	chain := ""
+	for i, f := range facts {
+		chain += fmt.Sprintf("%d. %s\n", i+1, f)
+	}
+
+	prompt := fmt.Sprintf(s.Prompts.Path, query, chain)
+
+	response, err := s.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize path: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.EntitySummary](response)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse path summary: %w", err)
+	}
+	return result.Summary, nil
+}
+
+// DigestEpisodes condenses consecutive episodes (oldest first) into one text that keeps their facts.
+func (s *Summarizer) DigestEpisodes(ctx context.Context, episodes []string) (string, error) {
+	if s.Prompts.Episodes == "" {
+		return "", fmt.Errorf("episodes prompt is not configured")
+	}
+
+	list := ""
+	for i, ep := range episodes {
+		list += fmt.Sprintf("[%d] %s\n", i+1, ep)
+	}
+
+	prompt := fmt.Sprintf(s.Prompts.Episodes, list)
+
+	response, err := s.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to digest episodes: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.EntitySummary](response)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse episode digest: %w", err)
+	}
+	return result.Summary, nil
+}
+
+// SynthesizeProfile writes a structured profile of a group's user from its
+// most salient entities ("Name: summary" lines) and facts.
+func (s *Summarizer) SynthesizeProfile(ctx context.Context, entities []string, facts []string) (*model.SynthesizedProfile, error) {
+	if s.Prompts.Profile == "" {
+		return nil, fmt.Errorf("profile prompt is not configured")
+	}
+
+	entityList := ""
+	for _, e := range entities {
+		entityList += fmt.Sprintf("- %s\n", e)
+	}
+	factList := ""
+	for _, f := range facts {
+		factList += fmt.Sprintf("- %s\n", f)
+	}
+
+	prompt := fmt.Sprintf(s.Prompts.Profile, entityList, factList)
+
+	response, err := s.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to synthesize profile: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.SynthesizedProfile](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+	return &result, nil
+}
+
+// SuggestQuestions writes questions that would fill the gaps of entities.
+func (s *Summarizer) SuggestQuestions(ctx context.Context, gaps []model.EntityGaps) ([]model.GapQuestion, error) {
+	if s.Prompts.GapQuestions == "" {
+		return nil, fmt.Errorf("gap questions prompt is not configured")
+	}
+
+	gapList := ""
+	for _, g := range gaps {
+		gapList += fmt.Sprintf("- UUID: %s, Name: %s, Type: %s, Missing attributes: %s, Missing relations: %s\n",
+			g.UUID, g.Name, g.Type, strings.Join(g.MissingAttributes, ", "), strings.Join(g.MissingRelations, ", "))
+	}
+
+	prompt := fmt.Sprintf(s.Prompts.GapQuestions, gapList)
+
+	response, err := s.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest questions: %w", err)
+	}
+
+	result, err := common.ParseJSON[model.GapQuestions](response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse questions: %w", err)
+	}
+	return result.Questions, nil
+}
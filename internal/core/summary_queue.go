@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+// maxSummaryAttempts is how often a node's summary update is tried before its
+// facts are dropped; they stay in the graph, only the summary misses them.
+const maxSummaryAttempts = 5
+
+// SummaryQueue defers node summarization out of AddEpisode. Facts for the same
+// node are coalesced so each node is re-summarized at most once per interval.
+// Run must be started for queued summaries to be written.
+type SummaryQueue struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	pending    map[string]*pendingSummary
+	lastUpdate map[string]time.Time
+}
+
+type pendingSummary struct {
+	g        *Graphiti // Group-scoped engine, so group prompt overrides apply
+	node     model.EntityNode
+	facts    []string
+	due      time.Time
+	attempts int // Failed so far
+}
+
+func NewSummaryQueue(interval time.Duration) *SummaryQueue {
+	return &SummaryQueue{
+		interval:   interval,
+		now:        time.Now,
+		pending:    make(map[string]*pendingSummary),
+		lastUpdate: make(map[string]time.Time),
+	}
+}
+
+// Enqueue schedules a summary update of node with new facts.
+func (q *SummaryQueue) Enqueue(g *Graphiti, node model.EntityNode, facts []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if p, ok := q.pending[node.UUID]; ok {
+		p.g = g
+		p.node = node
+		p.facts = appendUnique(p.facts, facts...)
+		return
+	}
+	due := q.now()
+	if last, ok := q.lastUpdate[node.UUID]; ok && last.Add(q.interval).After(due) {
+		due = last.Add(q.interval)
+	}
+	q.pending[node.UUID] = &pendingSummary{g: g, node: node, facts: appendUnique(nil, facts...), due: due}
+}
+
+// Pending returns the number of nodes waiting for a summary update.
+func (q *SummaryQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Run processes due updates every tick until ctx is done.
+func (q *SummaryQueue) Run(ctx context.Context) {
+	tick := time.Second
+	if q.interval > 0 && q.interval < tick {
+		tick = q.interval
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.process(ctx, false); err != nil {
+				log.Printf("Deferred summarization errors: %v", err)
+			}
+		}
+	}
+}
+
+// Flush writes every pending update now, ignoring the coalescing interval.
+// Call it on shutdown so queued summaries aren't lost.
+func (q *SummaryQueue) Flush(ctx context.Context) error {
+	return q.process(ctx, true)
+}
+
+func (q *SummaryQueue) process(ctx context.Context, all bool) error {
+	now := q.now()
+	q.mu.Lock()
+	var batch []*pendingSummary
+	for id, p := range q.pending {
+		if all || !p.due.After(now) {
+			batch = append(batch, p)
+			delete(q.pending, id)
+		}
+	}
+	// Updates older than the interval no longer delay the next one
+	for id, last := range q.lastUpdate {
+		if !last.Add(q.interval).After(now) {
+			delete(q.lastUpdate, id)
+		}
+	}
+	q.mu.Unlock()
+
+	// The queued node snapshot may predate other summary updates; start from the stored summary
+	current := make(map[string]map[string]string)
+	var errs []error
+	for _, p := range batch {
+		summaries, ok := current[p.node.GroupID]
+		if !ok {
+			nodes, err := p.g.getGroupNodes(ctx, p.node.GroupID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to load group %s: %w", p.node.GroupID, err))
+				q.requeue(p)
+				continue
+			}
+			summaries = make(map[string]string, len(nodes))
+			for _, n := range nodes {
+				summaries[n.UUID] = n.Summary
+			}
+			current[p.node.GroupID] = summaries
+		}
+
+		node := p.node
+		node.Summary = summaries[node.UUID]
+		newSummary, err := p.g.Summarizer.SummarizeNode(ctx, node, p.facts)
+		if err == nil {
+			err = p.g.setEntitySummary(ctx, node, newSummary)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to summarize %s: %w", node.Name, err))
+			q.requeue(p)
+			continue
+		}
+		summaries[node.UUID] = newSummary
+
+		q.mu.Lock()
+		q.lastUpdate[node.UUID] = now
+		q.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d summaries failed: %v", len(errs), len(batch), errs)
+	}
+	return nil
+}
+
+// requeue puts a failed update back, merging with facts that arrived
+// meanwhile, until it has failed maxSummaryAttempts times.
+func (q *SummaryQueue) requeue(p *pendingSummary) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p.attempts++
+	if p.attempts >= maxSummaryAttempts {
+		log.Printf("Dropping summary update of %s after %d failed attempts", p.node.UUID, p.attempts)
+		return
+	}
+	p.due = q.now().Add(q.interval)
+	if newer, ok := q.pending[p.node.UUID]; ok {
+		newer.facts = appendUnique(p.facts, newer.facts...)
+		newer.attempts = max(newer.attempts, p.attempts)
+		return
+	}
+	q.pending[p.node.UUID] = p
+}
+
+// setEntitySummary writes node's new summary. An entity deleted meanwhile
+// stays deleted.
+func (g *Graphiti) setEntitySummary(ctx context.Context, node model.EntityNode, summary string) error {
+	res, err := g.Driver.ExecuteQuery(ctx, driver.SetEntitySummaryQuery, map[string]interface{}{
+		"uuid":    node.UUID,
+		"summary": summary,
+	})
+	if err != nil || len(res.Records) == 0 {
+		return err
+	}
+	return g.recordChange(ctx, node.GroupID, model.ChangeKindEntity, node.UUID, model.ChangeOpUpdate)
+}
+
+func appendUnique(dst []string, items ...string) []string {
+	for _, item := range items {
+		dup := false
+		for _, existing := range dst {
+			if existing == item {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, item)
+		}
+	}
+	return dst
+}
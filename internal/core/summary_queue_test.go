@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryQueue_DefersAndCoalesces(t *testing.T) {
+	ctx := context.Background()
+	var summaryCalls int32
+	var lastPrompt atomic.Value
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			return threeSourceEdges
+		}
+		atomic.AddInt32(&summaryCalls, 1)
+		lastPrompt.Store(prompt)
+		return `{"summary": "updated"}`
+	})
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	q := NewSummaryQueue(5 * time.Minute)
+	q.now = func() time.Time { return clock }
+	g.SummaryQueue = q
+
+	// Ingest only queues the updates
+	require.NoError(t, g.processEntityEdgesAndSummaries(ctx, nodes, "ep1", "g1", "", nil, clock))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&summaryCalls))
+	assert.Equal(t, 3, q.Pending())
+
+	require.NoError(t, q.process(ctx, false))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&summaryCalls))
+	stored, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	for _, n := range stored {
+		assert.Equal(t, "updated", n.Summary)
+	}
+
+	// New facts inside the interval wait, and coalesce into one update
+	q.Enqueue(g, nodes[0], []string{"a likes tea"})
+	q.Enqueue(g, nodes[0], []string{"a likes coffee"})
+	require.NoError(t, q.process(ctx, false))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&summaryCalls))
+
+	clock = clock.Add(5 * time.Minute)
+	require.NoError(t, q.process(ctx, false))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&summaryCalls))
+	prompt := lastPrompt.Load().(string)
+	assert.Contains(t, prompt, "a likes tea")
+	assert.Contains(t, prompt, "a likes coffee")
+	assert.Contains(t, prompt, "updated") // Starts from the stored summary
+	assert.Equal(t, 0, q.Pending())
+
+	// Update times are only kept while they delay the next update
+	assert.Len(t, q.lastUpdate, 1)
+	clock = clock.Add(5 * time.Minute)
+	require.NoError(t, q.process(ctx, false))
+	assert.Empty(t, q.lastUpdate)
+}
+
+func TestSummaryQueue_WritesOnlySummaryAndGivesUp(t *testing.T) {
+	ctx := context.Background()
+	var failing atomic.Bool
+	g, nodes := edgeTestGraph(t, driver.NewMemoryDriver(), func(prompt string) string {
+		if failing.Load() {
+			return "not json"
+		}
+		return `{"summary": "updated"}`
+	})
+	q := NewSummaryQueue(0)
+	g.SummaryQueue = q
+
+	// A rename landing while the summary is computed is kept
+	q.Enqueue(g, nodes[0], []string{"a likes tea"})
+	renamed := nodes[0]
+	renamed.Name = "renamed"
+	require.NoError(t, g.saveEntity(ctx, renamed))
+	require.NoError(t, q.Flush(ctx))
+	stored, err := g.GetEntity(ctx, nodes[0].UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", stored.Name)
+	assert.Equal(t, "updated", stored.Summary)
+
+	// An update failing every time is dropped after maxSummaryAttempts
+	failing.Store(true)
+	q.Enqueue(g, nodes[1], []string{"b likes tea"})
+	for i := 1; i < maxSummaryAttempts; i++ {
+		assert.Error(t, q.Flush(ctx))
+		assert.Equal(t, 1, q.Pending())
+	}
+	assert.Error(t, q.Flush(ctx))
+	assert.Equal(t, 0, q.Pending())
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// GetSynonyms returns the group's synonym table, empty for unknown groups.
+func (g *Graphiti) GetSynonyms(ctx context.Context, groupID string) (map[string]string, error) {
+	group, err := g.GetGroup(ctx, groupID)
+	if errors.Is(err, ErrGroupNotFound) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if group.Settings.Synonyms == nil {
+		return map[string]string{}, nil
+	}
+	return group.Settings.Synonyms, nil
+}
+
+// UpdateSynonyms merges patch into the group's synonym table: a term mapped to
+// nil is removed, any other replaces its entry. It returns the new table.
+func (g *Graphiti) UpdateSynonyms(ctx context.Context, groupID string, patch map[string]*string) (map[string]string, error) {
+	settings := model.GroupSettings{}
+	group, err := g.GetGroup(ctx, groupID)
+	if err != nil && !errors.Is(err, ErrGroupNotFound) {
+		return nil, err
+	}
+	if group != nil {
+		settings = group.Settings
+	}
+	synonyms := make(map[string]string, len(settings.Synonyms)+len(patch))
+	for term, meaning := range settings.Synonyms {
+		synonyms[term] = meaning
+	}
+	for term, meaning := range patch {
+		if meaning == nil {
+			delete(synonyms, term)
+		} else {
+			synonyms[term] = *meaning
+		}
+	}
+	settings.Synonyms = synonyms
+	if _, err := g.UpdateGroup(ctx, groupID, model.GroupPatch{Settings: &settings}); err != nil {
+		return nil, err
+	}
+	g.invalidateSearchCache(ctx, groupID)
+	return synonyms, nil
+}
+
+func validateSynonyms(synonyms map[string]string) error {
+	for term, meaning := range synonyms {
+		if strings.TrimSpace(term) == "" || strings.TrimSpace(meaning) == "" {
+			return fmt.Errorf("%w: synonyms must map a term to a non-empty phrase", ErrInvalidGroupSettings)
+		}
+		if strings.EqualFold(strings.TrimSpace(term), strings.TrimSpace(meaning)) {
+			return fmt.Errorf("%w: synonym %q maps to itself", ErrInvalidGroupSettings, term)
+		}
+	}
+	return nil
+}
+
+// synonymPair is an entry of a group's synonym table; each side stands for
+// the other.
+type synonymPair struct {
+	term, meaning string
+}
+
+// groupSynonyms returns the entries of the group's synonym table ordered by
+// term. Search goes on without synonyms when the group can't be read.
+func (g *Graphiti) groupSynonyms(ctx context.Context, groupID string) []synonymPair {
+	group, err := g.GetGroup(ctx, groupID)
+	if err != nil {
+		if !errors.Is(err, ErrGroupNotFound) {
+			log.Printf("Failed to read synonyms of group %s: %v", groupID, err)
+		}
+		return nil
+	}
+	pairs := make([]synonymPair, 0, len(group.Settings.Synonyms))
+	for term, meaning := range group.Settings.Synonyms {
+		pairs = append(pairs, synonymPair{strings.TrimSpace(term), strings.TrimSpace(meaning)})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].term < pairs[j].term })
+	return pairs
+}
+
+// queryVariants returns query followed by each rewrite of it that swaps one
+// side of a synonym pair, found as a whole word ignoring case, for the other.
+func queryVariants(query string, synonyms []synonymPair) []string {
+	variants := []string{query}
+	for _, p := range synonyms {
+		for _, swap := range [][2]string{{p.term, p.meaning}, {p.meaning, p.term}} {
+			pattern := regexp.MustCompile(`(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(swap[0]) + `($|[^\pL\pN])`)
+			if pattern.MatchString(query) {
+				variants = appendUnique(variants, pattern.ReplaceAllString(query, "${1}"+strings.ReplaceAll(swap[1], "$", "$$")+"${2}"))
+			}
+		}
+	}
+	return variants
+}
+
+// expandMentions adds to mentions the other side of each synonym pair one of
+// them names, ignoring case.
+func expandMentions(mentions []string, synonyms []synonymPair) []string {
+	expanded := append([]string(nil), mentions...)
+	for _, mention := range mentions {
+		for _, p := range synonyms {
+			switch {
+			case strings.EqualFold(mention, p.term):
+				expanded = appendUnique(expanded, p.meaning)
+			case strings.EqualFold(mention, p.meaning):
+				expanded = appendUnique(expanded, p.term)
+			}
+		}
+	}
+	return expanded
+}
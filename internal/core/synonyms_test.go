@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynonyms(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{})
+	seedEntities(t, g.Driver, "g1", "alice", "sf")
+	seedFact(t, g.Driver, "g1", "f1", "alice", "sf", "LIVES_IN", "Alice lives in San Francisco", map[string]interface{}{"created_at": "2024-01-01T00:00:00Z"})
+	search := func(query string) int {
+		edges, err := g.Search(ctx, "g1", query)
+		require.NoError(t, err)
+		return len(edges)
+	}
+	assert.Zero(t, search("SF"))
+
+	sf, ml := "San Francisco", "machine learning"
+	synonyms, err := g.UpdateSynonyms(ctx, "g1", map[string]*string{"SF": &sf, "ML": &ml})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"SF": sf, "ML": ml}, synonyms)
+	assert.Equal(t, 1, search("SF"))
+	assert.Zero(t, search("SFO"))
+
+	// null removes an entry
+	synonyms, err = g.UpdateSynonyms(ctx, "g1", map[string]*string{"ML": nil})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"SF": sf}, synonyms)
+	synonyms, err = g.GetSynonyms(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"SF": sf}, synonyms)
+	synonyms, err = g.GetSynonyms(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, synonyms)
+
+	empty := " "
+	_, err = g.UpdateSynonyms(ctx, "g1", map[string]*string{"NYC": &empty})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+}
+
+func TestQueryVariants(t *testing.T) {
+	synonyms := []synonymPair{{"ML", "machine learning"}, {"SF", "San Francisco"}}
+	assert.Equal(t, []string{"who does ML in sf?", "who does machine learning in sf?", "who does ML in San Francisco?"},
+		queryVariants("who does ML in sf?", synonyms))
+	assert.Equal(t, []string{"jobs in San Francisco", "jobs in SF"}, queryVariants("jobs in San Francisco", synonyms))
+	assert.Equal(t, []string{"SFO flights"}, queryVariants("SFO flights", synonyms))
+	assert.Equal(t, []string{"sf", "Alice", "San Francisco"}, expandMentions([]string{"sf", "Alice"}, synonyms))
+}
@@ -0,0 +1,221 @@
+// Package temporal resolves the date expressions of a text ("last Tuesday",
+// "in 2019", "next month", "2021-03-04") to timestamps relative to a
+// reference time, usually the episode's.
+package temporal
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Granularity of a resolved expression: its Time is the start of that period.
+const (
+	Day   = "day"
+	Week  = "week"
+	Month = "month"
+	Year  = "year"
+)
+
+// Expression is a date expression found in a text.
+type Expression struct {
+	Text        string    `json:"text"`
+	Time        time.Time `json:"time"`
+	Granularity string    `json:"granularity"`
+}
+
+const monthNames = `(january|february|march|april|may|june|july|august|september|october|november|december|` +
+	`jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec)`
+
+const weekdayNames = `(monday|tuesday|wednesday|thursday|friday|saturday|sunday)`
+
+const counts = `(\d+|a|an|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve)`
+
+const units = `(day|week|month|year)s?`
+
+// resolver turns a match (its submatches) into a time and granularity.
+type resolver func(m []string, ref time.Time) (time.Time, string, bool)
+
+// patterns are tried in order; a later pattern never matches inside text an
+// earlier one already resolved, so more specific patterns come first.
+var patterns = []struct {
+	re      *regexp.Regexp
+	resolve resolver
+}{
+	{regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return date(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3]), ref)
+	}},
+	{regexp.MustCompile(`\b` + monthNames + `\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return date(atoi(m[3]), month(m[1]), atoi(m[2]), ref)
+	}},
+	{regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthNames + `\.?,?\s+(\d{4})\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return date(atoi(m[3]), month(m[2]), atoi(m[1]), ref)
+	}},
+	{regexp.MustCompile(`\b` + monthNames + `\.?\s+(\d{4})\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return time.Date(atoi(m[2]), month(m[1]), 1, 0, 0, 0, 0, ref.Location()), Month, true
+	}},
+	{regexp.MustCompile(`\b(?:in|since|during|by|until|before|after)\s+(\d{4})\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return time.Date(atoi(m[1]), time.January, 1, 0, 0, 0, 0, ref.Location()), Year, true
+	}},
+	{regexp.MustCompile(`\b` + counts + `\s+` + units + `\s+ago\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return offset(ref, -count(m[1]), m[2])
+	}},
+	{regexp.MustCompile(`\bin\s+` + counts + `\s+` + units + `\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return offset(ref, count(m[1]), m[2])
+	}},
+	{regexp.MustCompile(`\b(last|next|this)\s+` + weekdayNames + `\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return weekday(ref, m[1], m[2])
+	}},
+	{regexp.MustCompile(`\b(last|next|this)\s+(week|month|year)\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		n := map[string]int{"last": -1, "this": 0, "next": 1}[m[1]]
+		start := startOf(ref, m[2])
+		switch m[2] {
+		case Week:
+			return start.AddDate(0, 0, 7*n), Week, true
+		case Month:
+			return start.AddDate(0, n, 0), Month, true
+		}
+		return start.AddDate(n, 0, 0), Year, true
+	}},
+	{regexp.MustCompile(`\b(today|tonight|yesterday|tomorrow)\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		n := map[string]int{"today": 0, "tonight": 0, "yesterday": -1, "tomorrow": 1}[m[1]]
+		return startOf(ref, Day).AddDate(0, 0, n), Day, true
+	}},
+	{regexp.MustCompile(`\bin\s+` + monthNames + `\b`), func(m []string, ref time.Time) (time.Time, string, bool) {
+		return time.Date(ref.Year(), month(m[1]), 1, 0, 0, 0, 0, ref.Location()), Month, true
+	}},
+}
+
+// Normalize returns the date expressions of text in the order they appear,
+// resolved against ref.
+func Normalize(text string, ref time.Time) []Expression {
+	lower := strings.ToLower(text)
+	type span struct {
+		start, end int
+		expr       Expression
+	}
+	var found []span
+	overlaps := func(start, end int) bool {
+		for _, s := range found {
+			if start < s.end && s.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range patterns {
+		for _, loc := range p.re.FindAllStringSubmatchIndex(lower, -1) {
+			if overlaps(loc[0], loc[1]) {
+				continue
+			}
+			m := make([]string, len(loc)/2)
+			for i := range m {
+				if loc[2*i] >= 0 {
+					m[i] = lower[loc[2*i]:loc[2*i+1]]
+				}
+			}
+			t, granularity, ok := p.resolve(m, ref)
+			if !ok {
+				continue
+			}
+			found = append(found, span{loc[0], loc[1], Expression{Text: text[loc[0]:loc[1]], Time: t, Granularity: granularity}})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+
+	exprs := make([]Expression, len(found))
+	for i, s := range found {
+		exprs[i] = s.expr
+	}
+	return exprs
+}
+
+// date validates a calendar date; time.Date would roll February 30 over.
+func date(year int, month time.Month, day int, ref time.Time) (time.Time, string, bool) {
+	t := time.Date(year, month, day, 0, 0, 0, 0, ref.Location())
+	if month < time.January || month > time.December || t.Day() != day {
+		return time.Time{}, "", false
+	}
+	return t, Day, true
+}
+
+// startOf truncates t to the start of its day, week (Monday), month or year.
+func startOf(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch granularity {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	case Year:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+func offset(ref time.Time, n int, unit string) (time.Time, string, bool) {
+	day := startOf(ref, Day)
+	switch unit {
+	case Day:
+		return day.AddDate(0, 0, n), Day, true
+	case Week:
+		return day.AddDate(0, 0, 7*n), Day, true
+	case Month:
+		return startOf(ref, Month).AddDate(0, n, 0), Month, true
+	}
+	return startOf(ref, Year).AddDate(n, 0, 0), Year, true
+}
+
+// weekday resolves "last", "this" and "next" weekdays: the closest such day
+// before ref, on or after it, and after it.
+func weekday(ref time.Time, which, name string) (time.Time, string, bool) {
+	var target time.Weekday
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			target = d
+		}
+	}
+	day := startOf(ref, Day)
+	diff := (int(target) - int(day.Weekday()) + 7) % 7
+	switch which {
+	case "last":
+		if diff == 0 {
+			diff = 7
+		}
+		return day.AddDate(0, 0, diff-7), Day, true
+	case "next":
+		if diff == 0 {
+			diff = 7
+		}
+	}
+	return day.AddDate(0, 0, diff), Day, true
+}
+
+func month(name string) time.Month {
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), name[:3]) {
+			return m
+		}
+	}
+	return 0
+}
+
+func count(s string) int {
+	words := []string{"a", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve"}
+	if s == "an" {
+		return 1
+	}
+	for i, w := range words {
+		if s == w {
+			return max(i, 1)
+		}
+	}
+	return atoi(s)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
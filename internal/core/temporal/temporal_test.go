@@ -0,0 +1,49 @@
+package temporal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	// A Thursday
+	ref := time.Date(2026, time.October, 15, 17, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		text string
+		want []Expression
+	}{
+		{"Alice met Bob last Tuesday", []Expression{{"last Tuesday", day(2026, time.October, 13), Day}}},
+		{"see you next Thursday", []Expression{{"next Thursday", day(2026, time.October, 22), Day}}},
+		{"this Thursday works", []Expression{{"this Thursday", day(2026, time.October, 15), Day}}},
+		{"She moved to Berlin in 2019", []Expression{{"in 2019", day(2019, time.January, 1), Year}}},
+		{"He starts next month", []Expression{{"next month", day(2026, time.November, 1), Month}}},
+		{"last week was busy", []Expression{{"last week", day(2026, time.October, 5), Week}}},
+		{"Yesterday and tomorrow", []Expression{
+			{"Yesterday", day(2026, time.October, 14), Day},
+			{"tomorrow", day(2026, time.October, 16), Day},
+		}},
+		{"three days ago", []Expression{{"three days ago", day(2026, time.October, 12), Day}}},
+		{"in 2 weeks", []Expression{{"in 2 weeks", day(2026, time.October, 29), Day}}},
+		{"born on March 4th, 1990", []Expression{{"March 4th, 1990", day(1990, time.March, 4), Day}}},
+		{"on 4 March 1990", []Expression{{"4 March 1990", day(1990, time.March, 4), Day}}},
+		{"since 2021-02-03", []Expression{{"2021-02-03", day(2021, time.February, 3), Day}}},
+		{"joined in May 2020", []Expression{{"May 2020", day(2020, time.May, 1), Month}}},
+		{"a trip in June", []Expression{{"in June", day(2026, time.June, 1), Month}}},
+		{"invalid 2021-02-30 date", nil},
+		{"Alice may call Bob", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Normalize(tt.text, ref)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+)
+
+const (
+	TopicsLLM      = "llm"
+	TopicsKeywords = "keywords"
+
+	defaultMaxTopics = 3
+)
+
+// normalizeTopic is how topics are stored and matched: trimmed and lowercase.
+func normalizeTopic(topic string) string {
+	return strings.ToLower(strings.TrimSpace(topic))
+}
+
+// tagTopics tags a saved episode with its topics as [ingest] topics says.
+// Tagging is best effort: a failure is logged and leaves the episode untagged.
+func (g *Graphiti) tagTopics(ctx context.Context, episodeUUID, content string) {
+	if g.Config == nil || g.Config.Ingest.Topics == "" {
+		return
+	}
+	defer timeStage(ctx, "tag_topics")()
+	topics, err := g.episodeTopics(ctx, content)
+	if err != nil {
+		log.Printf("Failed to tag topics of episode %s: %v", episodeUUID, err)
+		return
+	}
+	if len(topics) == 0 {
+		return
+	}
+	if _, err := g.Driver.ExecuteQuery(ctx, driver.SetEpisodeTopicsQuery, map[string]interface{}{
+		"uuid":   episodeUUID,
+		"topics": topics,
+	}); err != nil {
+		log.Printf("Failed to save topics of episode %s: %v", episodeUUID, err)
+	}
+}
+
+// episodeTopics returns up to [ingest] max_topics normalized topics of content.
+func (g *Graphiti) episodeTopics(ctx context.Context, content string) ([]string, error) {
+	cfg := g.Config.Ingest
+	allowed := make([]string, 0, len(cfg.TopicKeywords))
+	for topic := range cfg.TopicKeywords {
+		allowed = append(allowed, normalizeTopic(topic))
+	}
+	sort.Strings(allowed)
+
+	var found []string
+	switch cfg.Topics {
+	case TopicsKeywords:
+		found = keywordTopics(content, cfg.TopicKeywords)
+	case TopicsLLM:
+		extracted, err := g.Extractor.ExtractTopics(ctx, content, allowed)
+		if err != nil {
+			return nil, err
+		}
+		found = extracted
+	default:
+		return nil, fmt.Errorf("unknown topics mode %q: must be %q or %q", cfg.Topics, TopicsLLM, TopicsKeywords)
+	}
+
+	limit := cfg.MaxTopics
+	if limit <= 0 {
+		limit = defaultMaxTopics
+	}
+	var topics []string
+	for _, t := range found {
+		t = normalizeTopic(t)
+		if t == "" || slices.Contains(topics, t) || (len(allowed) > 0 && !slices.Contains(allowed, t)) {
+			continue
+		}
+		if topics = append(topics, t); len(topics) == limit {
+			break
+		}
+	}
+	return topics, nil
+}
+
+// keywordTopics returns the topics with a keyword that content contains as a
+// whole word or phrase, ignoring case, those with the most matching keywords first.
+func keywordTopics(content string, keywords map[string][]string) []string {
+	matches := make(map[string]int)
+	for topic, words := range keywords {
+		for _, w := range words {
+			if w = strings.TrimSpace(w); w == "" {
+				continue
+			}
+			if regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(w) + `\b`).MatchString(content) {
+				matches[topic]++
+			}
+		}
+	}
+	topics := make([]string, 0, len(matches))
+	for topic := range matches {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if matches[topics[i]] != matches[topics[j]] {
+			return matches[topics[i]] > matches[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+	return topics
+}
+
+// GetEpisodesByTopic returns the group's lastN most recent episodes tagged
+// with any of topics, newest first, like GetEpisodes.
+func (g *Graphiti) GetEpisodesByTopic(ctx context.Context, groupID string, topics []string, lastN int) ([]model.EpisodicNode, error) {
+	normalized := make([]string, len(topics))
+	for i, t := range topics {
+		normalized[i] = normalizeTopic(t)
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetEpisodesByTopicQuery, map[string]interface{}{
+		"group_id": groupID,
+		"topics":   normalized,
+		"limit":    lastN,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	return g.readEpisodes(ctx, res)
+}
+
+// topicEpisodes returns the UUIDs of the group's episodes tagged with any of
+// topics, for the topic filter of fact search.
+func (g *Graphiti) topicEpisodes(ctx context.Context, groupID string, topics []string) ([]string, error) {
+	normalized := make([]string, len(topics))
+	for i, t := range topics {
+		normalized[i] = normalizeTopic(t)
+	}
+	res, err := g.Driver.ExecuteReadQuery(ctx, driver.GetTopicEpisodeUUIDsQuery, map[string]interface{}{
+		"group_id": groupID,
+		"topics":   normalized,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch topic episodes: %w", err)
+	}
+	uuids := make([]string, 0, len(res.Records))
+	for _, rec := range res.Records {
+		if uuid, ok := rec.Get("uuid"); ok {
+			if s, ok := uuid.(string); ok {
+				uuids = append(uuids, s)
+			}
+		}
+	}
+	return uuids, nil
+}
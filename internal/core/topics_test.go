@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{
+		Ingest: config.IngestConfig{
+			Topics:        TopicsKeywords,
+			TopicKeywords: map[string][]string{"Travel": {"flight", "hotel"}, "work": {"meeting"}},
+			MaxTopics:     1,
+		},
+	})
+
+	now := time.Now().UTC()
+	for _, ep := range [][2]string{{"ep1", "Booked a Flight and a hotel for the meeting."}, {"ep2", "The team meeting moved."}, {"ep3", "Meetings are fun."}} {
+		require.NoError(t, g.saveEpisodeNode(ctx, ep[0], ep[0], "g1", ep[1], now))
+		g.tagTopics(ctx, ep[0], ep[1])
+		now = now.Add(time.Second)
+	}
+	episodes, err := g.GetEpisodesByTopic(ctx, "g1", []string{"Travel"}, 10)
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, "ep1", episodes[0].UUID)
+	assert.Equal(t, []string{"travel"}, episodes[0].Topics, "most matching keywords first, up to max_topics")
+	episodes, err = g.GetEpisodesByTopic(ctx, "g1", []string{"work"}, 10)
+	require.NoError(t, err)
+	require.Len(t, episodes, 1, "keywords match whole words")
+	assert.Equal(t, "ep2", episodes[0].UUID)
+
+	seedEntities(t, g.Driver, "g1", "alice", "acme")
+	for _, e := range [][2]string{{"e1", "ep1"}, {"e2", "ep2"}} {
+		seedFact(t, g.Driver, "g1", e[0], "alice", "acme", "RELATED_TO", e[0]+" fact", map[string]interface{}{"episodes": []string{e[1]}})
+	}
+	assert.Equal(t, []string{"e2"}, searchUUIDs(t, g, &model.SearchFilter{Topics: []string{"work"}}))
+	assert.Empty(t, searchUUIDs(t, g, &model.SearchFilter{Topics: []string{"health"}}))
+
+	// LLM topics are kept to the configured ones
+	g.Config.Ingest.Topics = TopicsLLM
+	g.Config.Ingest.MaxTopics = 0
+	g.Extractor.Prompts.Topics = "%s %s"
+	g.Extractor.LLM = llmFunc(func(string) string { return `{"topics": ["Work", "sports", "work"]}` })
+	topics, err := g.episodeTopics(ctx, "Quarterly planning.")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, topics)
+}
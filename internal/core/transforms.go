@@ -0,0 +1,181 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+const (
+	defaultTransformTimeout = 2 * time.Second
+	// maxTransformOutput caps what a transform may write to stdout.
+	maxTransformOutput = 16 << 20
+)
+
+// transformIO is what a transform reads on stdin and writes to stdout: the
+// same object with the list it was given changed or filtered.
+type transformIO struct {
+	GroupID  string                  `json:"group_id"`
+	Entities []model.ExtractedEntity `json:"entities,omitempty"`
+	Facts    []model.ExtractedEdge   `json:"facts,omitempty"`
+}
+
+// transformHook runs the [[transforms]] a group's settings name on what is
+// extracted from its episodes. A transform that fails, times out or answers
+// with something other than the list it was given fails the episode.
+type transformHook struct {
+	g          *Graphiti
+	transforms map[string]config.TransformConfig
+}
+
+func newTransformHook(g *Graphiti, transforms []config.TransformConfig) *transformHook {
+	h := &transformHook{g: g, transforms: make(map[string]config.TransformConfig, len(transforms))}
+	for _, t := range transforms {
+		h.transforms[t.Name] = t
+	}
+	return h
+}
+
+func (h *transformHook) PostExtractEntities(ctx context.Context, groupID string, entities []model.ExtractedEntity) ([]model.ExtractedEntity, error) {
+	names, err := h.groupTransforms(ctx, groupID)
+	if err != nil || len(entities) == 0 {
+		return entities, err
+	}
+	for _, name := range names {
+		out, err := h.run(ctx, name, transformIO{GroupID: groupID, Entities: entities})
+		if err != nil {
+			return nil, err
+		}
+		kept := out.Entities[:0]
+		for _, e := range out.Entities {
+			if strings.TrimSpace(e.Name) != "" {
+				kept = append(kept, e)
+			}
+		}
+		if entities = kept; len(entities) == 0 {
+			break
+		}
+	}
+	return entities, nil
+}
+
+// PostExtractFacts passes facts through the group's transforms. Facts they
+// return must be between entities of the facts they were given.
+func (h *transformHook) PostExtractFacts(ctx context.Context, groupID string, facts []model.ExtractedEdge) ([]model.ExtractedEdge, error) {
+	names, err := h.groupTransforms(ctx, groupID)
+	if err != nil || len(facts) == 0 {
+		return facts, err
+	}
+	known := make(map[string]bool, 2*len(facts))
+	for _, f := range facts {
+		known[f.SourceNodeUUID] = true
+		known[f.TargetNodeUUID] = true
+	}
+	for _, name := range names {
+		out, err := h.run(ctx, name, transformIO{GroupID: groupID, Facts: facts})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range out.Facts {
+			if !known[f.SourceNodeUUID] || !known[f.TargetNodeUUID] {
+				return nil, fmt.Errorf("transform %s returned a fact between unknown entities: %q", name, f.Fact)
+			}
+		}
+		if facts = out.Facts; len(facts) == 0 {
+			break
+		}
+	}
+	return facts, nil
+}
+
+// groupTransforms returns the transforms the group's settings name.
+func (h *transformHook) groupTransforms(ctx context.Context, groupID string) ([]string, error) {
+	group, err := h.g.GetGroup(ctx, groupID)
+	if errors.Is(err, ErrGroupNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transforms of group %s: %w", groupID, err)
+	}
+	return group.Settings.Transforms, nil
+}
+
+// run runs one transform on in. The program gets no environment variables and
+// is killed after its timeout; it is not otherwise confined.
+func (h *transformHook) run(ctx context.Context, name string, in transformIO) (*transformIO, error) {
+	t, ok := h.transforms[name]
+	if !ok || len(t.Command) == 0 {
+		return nil, fmt.Errorf("transform %s is not configured", name)
+	}
+	timeout := defaultTransformTimeout
+	if t.TimeoutMs > 0 {
+		timeout = time.Duration(t.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	input, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr limitedBuffer
+	stdout.limit, stderr.limit = maxTransformOutput, 4096
+	cmd := exec.CommandContext(ctx, t.Command[0], t.Command[1:]...)
+	cmd.Env = []string{}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("transform %s timed out after %s", name, timeout)
+		}
+		return nil, fmt.Errorf("transform %s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("transform %s wrote more than %d bytes", name, maxTransformOutput)
+	}
+	var out transformIO
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("transform %s returned invalid JSON: %w", name, err)
+	}
+	return &out, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// validateTransforms rejects group settings naming transforms that aren't configured.
+func (g *Graphiti) validateTransforms(names []string) error {
+	for _, name := range names {
+		configured := false
+		if g.Config != nil {
+			for _, t := range g.Config.Transforms {
+				configured = configured || t.Name == name
+			}
+		}
+		if !configured {
+			return fmt.Errorf("%w: transform %q is not configured", ErrInvalidGroupSettings, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransforms(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraphiti(driver.NewMemoryDriver(), &MockLLM{}, nil, nil, &config.Config{
+		Transforms: []config.TransformConfig{
+			{Name: "units", Command: []string{"sed", `s/"unit":"km"/"unit":"kilometers"/g`}},
+			{Name: "fail", Command: []string{"false"}},
+			{Name: "slow", Command: []string{"sleep", "5"}, TimeoutMs: 50},
+		},
+	})
+	hook := newTransformHook(g, g.Config.Transforms)
+	entities := []model.ExtractedEntity{{Name: "run", Attributes: map[string]interface{}{"unit": "km"}}}
+
+	// Groups without transforms pass everything through
+	out, err := hook.PostExtractEntities(ctx, "g1", entities)
+	require.NoError(t, err)
+	assert.Equal(t, entities, out)
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Transforms: []string{"missing"}}})
+	assert.ErrorIs(t, err, ErrInvalidGroupSettings)
+
+	_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Transforms: []string{"units"}}})
+	require.NoError(t, err)
+	out, err = hook.PostExtractEntities(ctx, "g1", entities)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, "kilometers", out[0].Attributes["unit"])
+
+	for _, name := range []string{"fail", "slow"} {
+		_, err = g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Transforms: []string{name}}})
+		require.NoError(t, err)
+		_, err = hook.PostExtractFacts(ctx, "g1", []model.ExtractedEdge{{SourceNodeUUID: "a", TargetNodeUUID: "b", RelationType: "KNOWS", Fact: "a knows b"}})
+		assert.ErrorContains(t, err, "transform "+name)
+	}
+}
+
+func TestTransforms_FailEpisode(t *testing.T) {
+	ctx := context.Background()
+	nodeLine := regexp.MustCompile(`UUID: (\S+), Name: (\w+)`)
+	g := NewGraphiti(driver.NewMemoryDriver(), llmFunc(func(prompt string) string {
+		if strings.HasPrefix(prompt, "edges") {
+			uuids := map[string]string{}
+			for _, m := range nodeLine.FindAllStringSubmatch(prompt, -1) {
+				uuids[m[2]] = m[1]
+			}
+			return `{"extracted_edges": [{"source_node_uuid": "` + uuids["Alice"] + `", "target_node_uuid": "` + uuids["Bob"] + `", "relation_type": "KNOWS", "fact": "Alice knows Bob"}]}`
+		}
+		return `{"extracted_entities": [{"name": "Alice"}, {"name": "Bob"}]}`
+	}), nil, nil, &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s", Edges: "edges %s"},
+		// Passes entities through and fails on facts
+		Transforms: []config.TransformConfig{{Name: "no-facts", Command: []string{"grep", "-v", `"facts"`}}},
+	})
+	_, err := g.UpdateGroup(ctx, "g1", model.GroupPatch{Settings: &model.GroupSettings{Transforms: []string{"no-facts"}}})
+	require.NoError(t, err)
+
+	err = g.AddEpisode(ctx, "g1", "message", "Alice knows Bob", "", "")
+	assert.ErrorContains(t, err, "transform no-facts failed")
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, nodes, 2, "entities passed the transform")
+	letters, err := g.ListDeadLetters(ctx, "g1")
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// rrfK damps the weight of top ranks in reciprocal rank fusion, as in the
+// original RRF paper.
+const rrfK = 60
+
+// BulkSearchUnion runs queries like BulkSearch and merges their results into
+// one list without duplicates. Facts are ranked by reciprocal rank fusion, so
+// those found by several queries or ranked high by one come first; ties keep
+// the order of the first query that found them.
+func (g *Graphiti) BulkSearchUnion(ctx context.Context, groupID string, queries []model.BulkSearchQuery) ([]model.UnionSearchResult, error) {
+	results, err := g.BulkSearch(ctx, groupID, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []model.UnionSearchResult
+	index := make(map[string]int)
+	scores := make(map[string]float64)
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		if seen[q.QueryID] {
+			continue
+		}
+		seen[q.QueryID] = true
+		for rank, edge := range results[q.QueryID] {
+			i, ok := index[edge.UUID]
+			if !ok {
+				i = len(merged)
+				index[edge.UUID] = i
+				merged = append(merged, model.UnionSearchResult{EntityEdge: edge})
+			} else if slices.Contains(merged[i].Queries, q.QueryID) {
+				continue
+			}
+			merged[i].Queries = append(merged[i].Queries, q.QueryID)
+			scores[edge.UUID] += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return scores[merged[i].UUID] > scores[merged[j].UUID]
+	})
+	return merged, nil
+}
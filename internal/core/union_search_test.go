@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkSearchUnion(t *testing.T) {
+	g := filterTestGraph(t)
+
+	merged, err := g.BulkSearchUnion(context.Background(), "g1", []model.BulkSearchQuery{
+		{QueryID: "a", Query: "e3"},
+		{QueryID: "b", Query: "fact"},
+		{QueryID: "b", Query: "fact"},
+	})
+	require.NoError(t, err)
+	var uuids []string
+	for _, r := range merged {
+		uuids = append(uuids, r.UUID)
+	}
+	// e3 is found by both queries, the rest only by "b" in its order
+	assert.Equal(t, []string{"e3", "e1", "e2"}, uuids)
+	assert.Equal(t, []string{"a", "b"}, merged[0].Queries)
+	assert.Equal(t, []string{"b"}, merged[1].Queries)
+	assert.Equal(t, "e3 fact", merged[0].Fact)
+}
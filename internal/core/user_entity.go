@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/google/uuid"
+)
+
+// userEntityNamespace derives the UUID of each group's user entity from the group ID.
+var userEntityNamespace = uuid.MustParse("8b3f2c61-4d7e-4a19-a0c5-6e9d1f2b7a43")
+
+// firstPerson are the extracted entity names that refer to the speaker.
+var firstPerson = map[string]bool{
+	"i": true, "me": true, "my": true, "myself": true, "mine": true,
+	"user": true, "the user": true, "speaker": true, "the speaker": true,
+}
+
+func userEntityID(groupID string) string {
+	return uuid.NewSHA1(userEntityNamespace, []byte(groupID)).String()
+}
+
+func (g *Graphiti) userName() string {
+	if g.Config != nil && g.Config.Ingest.UserName != "" {
+		return g.Config.Ingest.UserName
+	}
+	return "User"
+}
+
+// EnsureUserEntity creates or updates the group's user entity, the one
+// first-person statements in its episodes are linked to. The profile's name
+// and attributes replace those already known; an entity created without a
+// name is called [ingest] user_name.
+func (g *Graphiti) EnsureUserEntity(ctx context.Context, groupID string, profile model.UserProfile) (*model.EntityNode, error) {
+	node, err := g.getEntity(ctx, userEntityID(groupID))
+	if errors.Is(err, ErrEntityNotFound) {
+		node = &model.EntityNode{
+			UUID:      userEntityID(groupID),
+			Name:      g.userName(),
+			GroupID:   groupID,
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if profile.Name != "" {
+		node.Name = profile.Name
+	}
+	if len(profile.Attributes) > 0 && node.Attributes == nil {
+		node.Attributes = make(map[string]interface{}, len(profile.Attributes))
+	}
+	for k, v := range profile.Attributes {
+		node.Attributes[k] = v
+	}
+	node.Labels = []string{"Entity", "Person"}
+	if err := g.saveEntity(ctx, *node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// bindUserEntity resolves the extracted nodes that refer to the speaker to
+// the group's user entity, created if [ingest] user_entity is on. It returns
+// the nodes with those merged into one, and whether that one is the user
+// entity, which needs no further deduplication.
+func (g *Graphiti) bindUserEntity(ctx context.Context, groupID string, nodes []model.EntityNode) ([]model.EntityNode, *model.EntityNode, error) {
+	user, err := g.getEntity(ctx, userEntityID(groupID))
+	if errors.Is(err, ErrEntityNotFound) {
+		if g.Config == nil || !g.Config.Ingest.UserEntity || !refersToUser(nodes, "") {
+			return nodes, nil, nil
+		}
+		user, err = g.EnsureUserEntity(ctx, groupID, model.UserProfile{})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bound *model.EntityNode
+	var rest []model.EntityNode
+	for _, n := range nodes {
+		if !refersToUser([]model.EntityNode{n}, user.Name) {
+			rest = append(rest, n)
+			continue
+		}
+		if bound == nil {
+			bound = &model.EntityNode{
+				UUID:       user.UUID,
+				Name:       user.Name,
+				GroupID:    user.GroupID,
+				CreatedAt:  user.CreatedAt,
+				Summary:    user.Summary,
+				Attributes: make(map[string]interface{}, len(user.Attributes)),
+				Labels:     user.Labels,
+			}
+			for k, v := range user.Attributes {
+				bound.Attributes[k] = v
+			}
+		}
+		// What the episode says about the user is newer than the profile
+		for k, v := range n.Attributes {
+			bound.Attributes[k] = v
+		}
+	}
+	return rest, bound, nil
+}
+
+// refersToUser reports whether any of nodes is named as the speaker, or name.
+func refersToUser(nodes []model.EntityNode, name string) bool {
+	for _, n := range nodes {
+		key := strings.ToLower(strings.TrimSpace(n.Name))
+		if firstPerson[key] || (name != "" && key == strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
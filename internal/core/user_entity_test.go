@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEntity(t *testing.T) {
+	ctx := context.Background()
+	llmClient := llmFunc(func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "I like tea"):
+			return `{"extracted_entities": [{"name": "I", "attributes": {"drink": "tea"}}, {"name": "Bob"}]}`
+		case strings.Contains(prompt, "Alice"):
+			return `{"extracted_entities": [{"name": "me"}, {"name": "Alice", "attributes": {"city": "Lisbon"}}]}`
+		}
+		return `{"extracted_entities": [{"name": "I"}]}`
+	})
+	cfg := &config.Config{
+		Extraction: config.ExtractionPrompts{Nodes: "%s|%s"},
+		Ingest:     config.IngestConfig{UserEntity: true},
+	}
+	g := NewGraphiti(driver.NewMemoryDriver(), llmClient, nil, nil, cfg)
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep1", "I like tea, Bob doesn't.", "", ""))
+	user, err := g.getEntity(ctx, userEntityID("g1"))
+	require.NoError(t, err)
+	assert.Equal(t, "User", user.Name)
+	assert.Equal(t, []string{"Entity", "Person"}, user.Labels)
+	assert.Equal(t, "tea", user.Attributes["drink"])
+
+	// The profile names the user; first-person references and the name link to it
+	user, err = g.EnsureUserEntity(ctx, "g1", model.UserProfile{Name: "Alice", Attributes: map[string]interface{}{"email": "alice@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, userEntityID("g1"), user.UUID)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "ep2", "Alice here, call me in Lisbon.", "", ""))
+
+	nodes, err := g.getGroupNodes(ctx, "g1")
+	require.NoError(t, err)
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	assert.ElementsMatch(t, []string{"Alice", "Bob"}, names)
+	user, err = g.getEntity(ctx, userEntityID("g1"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"drink": "tea", "email": "alice@example.com", "city": "Lisbon"}, user.Attributes)
+
+	// Off, groups without a user entity keep first-person entities as extracted
+	cfg.Ingest.UserEntity = false
+	require.NoError(t, g.AddEpisode(ctx, "g2", "ep1", "I moved.", "", ""))
+	_, err = g.getEntity(ctx, userEntityID("g2"))
+	assert.ErrorIs(t, err, ErrEntityNotFound)
+}
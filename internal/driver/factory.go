@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+// NewFromConfig opens the GraphDriver selected by cfg.Graph.Backend.
+func NewFromConfig(cfg *config.Config) (GraphDriver, error) {
+	if len(cfg.Graph.Routes)+len(cfg.Graph.Shards) > 0 && cfg.Graph.Backend != "" && cfg.Graph.Backend != "memgraph" {
+		return nil, fmt.Errorf("[[graph.routes]] and [[graph.shards]] need the memgraph backend, not %s", cfg.Graph.Backend)
+	}
+	switch cfg.Graph.Backend {
+	case "memory":
+		log.Println("Using in-memory graph driver (data is not persisted)")
+		return NewMemoryDriver(), nil
+
+	case "sqlite":
+		path := cfg.Graph.Path
+		if path == "" {
+			path = "carbon.db"
+		}
+		d, err := NewSQLiteDriver(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open SQLite graph: %w", err)
+		}
+		return d, nil
+
+	case "", "memgraph":
+		return newMemgraphBackends(cfg)
+
+	default:
+		return nil, fmt.Errorf("unsupported graph backend: %s", cfg.Graph.Backend)
+	}
+}
+
+// newMemgraphBackends connects to [memgraph], or hashes groups over the
+// [[graph.shards]] when there are any, and routes [[graph.routes]] prefixes to
+// their own backends on top.
+func newMemgraphBackends(cfg *config.Config) (GraphDriver, error) {
+	var opened []GraphDriver
+	fail := func(err error) (GraphDriver, error) {
+		for _, d := range opened {
+			d.Close(context.Background())
+		}
+		return nil, err
+	}
+
+	var def GraphDriver
+	if len(cfg.Graph.Shards) == 0 {
+		d, err := newMemgraphBackend(cfg, cfg.Memgraph.URI, cfg.Graph.ReadURI, cfg.Memgraph.Database, cfg.Memgraph.User, cfg.Memgraph.Password)
+		if err != nil {
+			return nil, err
+		}
+		opened, def = append(opened, d), d
+	} else {
+		var shards []Shard
+		for i, sc := range cfg.Graph.Shards {
+			name := sc.Name
+			if name == "" {
+				name = fmt.Sprintf("shard-%d", i)
+			}
+			d, err := newMemgraphBackend(cfg, sc.URI, sc.ReadURI, sc.Database, sc.User, sc.Password)
+			if err != nil {
+				return fail(fmt.Errorf("graph shard %s: %w", name, err))
+			}
+			opened = append(opened, d)
+			shards = append(shards, Shard{Name: name, Driver: d})
+		}
+		sharded, err := NewShardedDriver(shards)
+		if err != nil {
+			return fail(err)
+		}
+		log.Printf("Sharding groups over %d graph backends", len(shards))
+		def = sharded
+	}
+	if len(cfg.Graph.Routes) == 0 {
+		return def, nil
+	}
+
+	var routes []Route
+	for _, rc := range cfg.Graph.Routes {
+		if rc.Prefix == "" {
+			return fail(fmt.Errorf("[[graph.routes]] entries need a prefix"))
+		}
+		d, err := newMemgraphBackend(cfg, rc.URI, rc.ReadURI, rc.Database, rc.User, rc.Password)
+		if err != nil {
+			return fail(fmt.Errorf("graph route %q: %w", rc.Prefix, err))
+		}
+		opened = append(opened, d)
+		log.Printf("Routing groups with prefix %q to their own graph backend", rc.Prefix)
+		routes = append(routes, Route{Prefix: rc.Prefix, Driver: d})
+	}
+	return NewRoutedDriver(def, routes), nil
+}
+
+// newMemgraphBackend connects to one Memgraph database. Empty settings fall
+// back to [memgraph], and an empty readURI to [graph] read_uri when uri is
+// empty too.
+func newMemgraphBackend(cfg *config.Config, uri, readURI, database, user, password string) (*MemgraphDriver, error) {
+	if uri == "" {
+		uri = cfg.Memgraph.URI
+		if readURI == "" {
+			readURI = cfg.Graph.ReadURI
+		}
+	}
+	if uri == "" {
+		uri = "bolt://localhost:7687"
+	}
+	if database == "" {
+		database = cfg.Memgraph.Database
+	}
+	if user == "" {
+		user, password = cfg.Memgraph.User, cfg.Memgraph.Password
+	}
+	d, err := NewMemgraphDriverWithReader(uri, readURI, user, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Memgraph: %w", err)
+	}
+	retry := cfg.Graph.Retry
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 3
+	}
+	d.Retry = RetryPolicy{
+		MaxAttempts:    retry.MaxAttempts,
+		InitialBackoff: time.Duration(retry.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(retry.MaxBackoffMs) * time.Millisecond,
+	}
+	d.Database = database
+	return d, nil
+}
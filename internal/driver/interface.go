@@ -8,6 +8,8 @@ import (
 
 type GraphDriver interface {
 	ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error)
+	// ExecuteReadQuery runs a read-only query. Cluster-aware drivers route it to a reader.
+	ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error)
 	BuildIndices(ctx context.Context) error
 	Close(ctx context.Context) error
 }
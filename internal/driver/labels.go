@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// labelsClause marks where a query sets labels taken from a parameter. Cypher
+// can't parameterize labels, so drivers that run Cypher replace it with a SET
+// of the validated labels (see WithLabels); unreplaced it is a comment.
+const labelsClause = "/* labels */"
+
+var labelPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RelationshipTypes are the relationship types carbon writes, the only ones
+// spliced into queries (see ImportEdgesQueries).
+var RelationshipTypes = []string{"RELATES_TO", "MENTIONS", "NEXT_EPISODE", "HAS_EPISODE", "HAS_MEMBER"}
+
+// ValidLabel reports whether label can be spliced into a query as a label.
+func ValidLabel(label string) bool {
+	return labelPattern.MatchString(label)
+}
+
+// LabelName turns an entity type name into a label: "software project" and
+// "Software-Project" both become "SoftwareProject". It is empty when nothing
+// of the name is usable.
+func LabelName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	label := b.String()
+	if label != "" && !ValidLabel(label) {
+		label = "_" + label // Leading digit
+	}
+	return label
+}
+
+// WithLabels returns query with its labels clause replaced by a SET of labels
+// on variable. Duplicate and empty labels are skipped; invalid ones are an error.
+func WithLabels(query, variable string, labels []string) (string, error) {
+	var set strings.Builder
+	seen := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		if l == "" || seen[l] {
+			continue
+		}
+		if !ValidLabel(l) {
+			return "", fmt.Errorf("invalid label %q", l)
+		}
+		seen[l] = true
+		set.WriteString(":" + l)
+	}
+	if set.Len() == 0 {
+		return query, nil
+	}
+	return strings.Replace(query, labelsClause, "SET "+variable+set.String(), 1), nil
+}
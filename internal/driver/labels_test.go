@@ -0,0 +1,56 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Person":           "Person",
+		"software project": "SoftwareProject",
+		"Non-profit":       "NonProfit",
+		"3D model":         "_3DModel",
+		"Café":             "Caf",
+		"":                 "",
+		"--":               "",
+	} {
+		assert.Equal(t, want, LabelName(name), name)
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	query, err := WithLabels(SaveEntityNodeQuery, "n", []string{"Entity", "Person", "Person", ""})
+	require.NoError(t, err)
+	assert.Contains(t, query, "SET n:Entity:Person // $labels")
+	assert.NotContains(t, query, labelsClause)
+
+	query, err = WithLabels(SaveEntityNodeQuery, "n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, SaveEntityNodeQuery, query)
+
+	for _, label := range []string{"Person) DETACH DELETE (n", "a b", "1st", "Person`"} {
+		_, err := WithLabels(SaveEntityNodeQuery, "n", []string{label})
+		assert.Error(t, err, label)
+	}
+}
+
+func TestMemoryDriver_RejectsInvalidLabels(t *testing.T) {
+	d := NewMemoryDriver()
+	_, err := d.ExecuteQuery(context.Background(), SaveEntityNodeQuery, map[string]interface{}{
+		"uuid": "a", "name": "Alice", "group_id": "g1", "labels": []string{"Entity", "Person:Admin"},
+	})
+	assert.Error(t, err)
+}
+
+// Relationship types are spliced into ImportEdgesQueries unchecked
+func TestRelationshipTypes(t *testing.T) {
+	require.Len(t, ImportEdgesQueries, len(RelationshipTypes))
+	for _, relType := range RelationshipTypes {
+		assert.True(t, ValidLabel(relType), relType)
+		assert.Contains(t, ImportEdgesQueries[relType], "CREATE (s)-[e:"+relType+"]->(t)")
+	}
+}
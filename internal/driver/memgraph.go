@@ -4,40 +4,163 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 type MemgraphDriver struct {
 	Driver neo4j.DriverWithContext
+	// ReadDriver points at a read replica. Nil means reads go to Driver.
+	ReadDriver neo4j.DriverWithContext
+	// Retry reruns queries failing with transient errors, on top of the Bolt
+	// driver's own retries.
+	Retry RetryPolicy
+	// Database selects a database on a multi-tenant instance. Empty uses the
+	// instance's default database.
+	Database string
 }
 
 func NewMemgraphDriver(uri, username, password string) (*MemgraphDriver, error) {
+	return NewMemgraphDriverWithReader(uri, "", username, password)
+}
+
+// NewMemgraphDriverWithReader connects to the writer at uri and, if readURI is set,
+// to a separate reader used by ExecuteReadQuery.
+func NewMemgraphDriverWithReader(uri, readURI, username, password string) (*MemgraphDriver, error) {
+	driver, err := connect(uri, username, password)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Connected to Memgraph")
+
+	d := &MemgraphDriver{Driver: driver}
+	if readURI != "" && readURI != uri {
+		reader, err := connect(readURI, username, password)
+		if err != nil {
+			driver.Close(context.Background())
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		log.Printf("Connected to Memgraph read replica at %s", readURI)
+		d.ReadDriver = reader
+	}
+	return d, nil
+}
+
+func connect(uri, username, password string) (neo4j.DriverWithContext, error) {
 	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
 	if err != nil {
 		return nil, err
 	}
 	
 	if err := driver.VerifyConnectivity(context.Background()); err != nil {
+		driver.Close(context.Background())
 		return nil, err
 	}
-	
-	log.Println("Connected to Memgraph")
-	return &MemgraphDriver{Driver: driver}, nil
+	return driver, nil
 }
 
 func (d *MemgraphDriver) Close(ctx context.Context) error {
+	if d.ReadDriver != nil {
+		if err := d.ReadDriver.Close(ctx); err != nil {
+			log.Printf("Warning: failed to close read replica driver: %v", err)
+		}
+	}
 	return d.Driver.Close(ctx)
 }
 
 func (d *MemgraphDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
-	result, err := neo4j.ExecuteQuery(ctx, d.Driver, query, params, neo4j.EagerResultTransformer)
+	switch query {
+	case SaveEntityNodeQuery:
+		labels, _ := params["labels"].([]string)
+		labeled, err := WithLabels(query, "n", labels)
+		if err != nil {
+			return neo4j.EagerResult{}, fmt.Errorf("failed to save entity node: %w", err)
+		}
+		query = labeled
+	case ImportNodesQuery:
+		return d.importNodes(ctx, params)
+	}
+	return d.execute(ctx, query, params)
+}
+
+func (d *MemgraphDriver) execute(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	var result *neo4j.EagerResult
+	err := d.Retry.Do(ctx, func() (err error) {
+		result, err = neo4j.ExecuteQuery(ctx, d.Driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(d.Database))
+		return err
+	})
 	if err != nil {
 		return neo4j.EagerResult{}, fmt.Errorf("failed to execute query: %w", err)
 	}
 	return *result, nil
 }
 
+// importNodes runs ImportNodesQuery once per set of labels among the nodes,
+// summing the imported counts.
+func (d *MemgraphDriver) importNodes(ctx context.Context, params map[string]interface{}) (neo4j.EagerResult, error) {
+	var order []string
+	batches := make(map[string][]map[string]interface{})
+	queries := make(map[string]string)
+	nodes, _ := params["nodes"].([]map[string]interface{})
+	for _, node := range nodes {
+		var labels []string
+		switch l := node["labels"].(type) {
+		case []string:
+			labels = l
+		case []interface{}:
+			for _, v := range l {
+				if s, ok := v.(string); ok {
+					labels = append(labels, s)
+				}
+			}
+		}
+		key := strings.Join(labels, ":")
+		if _, ok := queries[key]; !ok {
+			query, err := WithLabels(ImportNodesQuery, "n", labels)
+			if err != nil {
+				return neo4j.EagerResult{}, fmt.Errorf("failed to import nodes: %w", err)
+			}
+			queries[key] = query
+			order = append(order, key)
+		}
+		batches[key] = append(batches[key], node)
+	}
+
+	imported := int64(0)
+	for _, key := range order {
+		res, err := d.execute(ctx, queries[key], map[string]interface{}{"nodes": batches[key]})
+		if err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		if len(res.Records) > 0 {
+			if n, ok := res.Records[0].Values[0].(int64); ok {
+				imported += n
+			}
+		}
+	}
+	keys := []string{"imported"}
+	return neo4j.EagerResult{Keys: keys, Records: []*neo4j.Record{{Keys: keys, Values: []any{imported}}}}, nil
+}
+
+func (d *MemgraphDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	reader := d.Driver
+	if d.ReadDriver != nil {
+		reader = d.ReadDriver
+	}
+	// Readers routing also lets routed (neo4j://) URIs pick a follower within a single cluster.
+	var result *neo4j.EagerResult
+	err := d.Retry.Do(ctx, func() (err error) {
+		result, err = neo4j.ExecuteQuery(ctx, reader, query, params, neo4j.EagerResultTransformer,
+			neo4j.ExecuteQueryWithReadersRouting(), neo4j.ExecuteQueryWithDatabase(d.Database))
+		return err
+	})
+	if err != nil {
+		return neo4j.EagerResult{}, fmt.Errorf("failed to execute read query: %w", err)
+	}
+	return *result, nil
+}
+
 func (d *MemgraphDriver) BuildIndices(ctx context.Context) error {
 	// Basic constraints and indices for Graphiti
 	// Memgraph supports Cypher index creation
@@ -47,18 +170,27 @@ func (d *MemgraphDriver) BuildIndices(ctx context.Context) error {
 		"CREATE INDEX ON :Episodic(uuid);",
 		"CREATE INDEX ON :Community(uuid);",
 		"CREATE INDEX ON :Saga(uuid);",
+		"CREATE INDEX ON :IngestJob(uuid);",
+		"CREATE INDEX ON :MaintenanceReport(group_id);",
+		"CREATE INDEX ON :QuarantinedEntity(group_id);",
+		"CREATE INDEX ON :ScratchEntry(group_id);",
+		"CREATE INDEX ON :Change(group_id);",
+		"CREATE INDEX ON :ChangeSequence(group_id);",
+		"CREATE INDEX ON :SchemaVersion(id);",
 		
 		"CREATE INDEX ON :Entity(group_id);",
 		"CREATE INDEX ON :Episodic(group_id);",
 		"CREATE INDEX ON :Community(group_id);",
 		"CREATE INDEX ON :Saga(group_id);",
+		"CREATE INDEX ON :Group(group_id);",
+		"CREATE EDGE INDEX ON :RELATES_TO(citation);",
 
 		// Vector indices setup would go here if using Memgraph's vector search capabilities
 		// Example: CALL vector_search.create_index("Entity", "name_embedding", 1536, "COSINE");
 		// Need to verify if Memgraph Mage is running with vector modules.
 	}
 
-	session := d.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	session := d.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: d.Database})
 	defer session.Close(ctx)
 
 	for _, q := range queries {
@@ -0,0 +1,2669 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// MemoryDriver is a pure-Go GraphDriver that keeps the graph in maps.
+// It does not parse Cypher: each query constant in queries.go is mapped to a
+// typed method that performs the equivalent operation, so it is suitable for
+// tests and embedded use but only understands the queries carbon itself issues.
+type MemoryDriver struct {
+	mu       sync.RWMutex
+	nodes    map[string]*MemoryNode
+	edges    map[string]*MemoryEdge
+	handlers map[string]memoryHandler
+	// store, when set, receives every node and edge write (see SQLiteDriver).
+	store memoryStore
+}
+
+// memoryStore persists writes made through a MemoryDriver. Calls happen under the write lock.
+type memoryStore interface {
+	putNode(n *MemoryNode) error
+	putEdge(e *MemoryEdge) error
+	deleteEdge(uuid string) error
+	deleteNode(uuid string) error
+}
+
+type MemoryNode struct {
+	UUID   string
+	Labels []string
+	Props  map[string]interface{}
+}
+
+type MemoryEdge struct {
+	UUID       string
+	Type       string
+	SourceUUID string
+	TargetUUID string
+	Props      map[string]interface{}
+}
+
+type memoryHandler func(params map[string]interface{}) (neo4j.EagerResult, error)
+
+func NewMemoryDriver() *MemoryDriver {
+	d := &MemoryDriver{
+		nodes: make(map[string]*MemoryNode),
+		edges: make(map[string]*MemoryEdge),
+	}
+	d.handlers = map[string]memoryHandler{
+		SaveEntityNodeQuery:              d.saveEntityNode,
+		UpdateEntityNodeQuery:            d.updateEntityNode,
+		SetEntitySummaryQuery:            d.setEntitySummary,
+		SaveEpisodicNodeQuery:            d.saveEpisodicNode,
+		SaveCommunityNodeQuery:           d.saveCommunityNode,
+		SaveSagaNodeQuery:                d.saveSagaNode,
+		SaveEntityEdgeQuery:              d.saveEntityEdge,
+		SaveEpisodicEdgeQuery:            d.saveEpisodicEdge,
+		SaveNextEpisodeEdgeQuery:         d.saveNextEpisodeEdge,
+		SaveHasEpisodeEdgeQuery:          d.saveHasEpisodeEdge,
+		SaveCommunityEdgeQuery:           d.saveCommunityEdge,
+		GetGroupCommunitiesQuery:         d.getGroupCommunities,
+		DeleteCommunityMembersQuery:      d.deleteCommunityMembers,
+		DeleteCommunityQuery:             d.deleteCommunity,
+		GetSagaByNameQuery:               d.getSagaByName,
+		GetSagaQuery:                     d.getSaga,
+		GetPreviousEpisodeInSagaQuery:    d.getPreviousEpisodeInSaga,
+		InvalidateEdgeQuery:              d.invalidateEdge,
+		GetActiveEdgesQuery:              d.getActiveEdges,
+		GetActiveEdgesFromSourceQuery:    d.getActiveEdgesFromSource,
+		GetEdgeHistoryFromSourceQuery:    d.getEdgeHistoryFromSource,
+		ReinstateEdgeQuery:               d.reinstateEdge,
+		SetEdgeExpiryQuery:               d.setEdgeExpiry,
+		GetGroupNodesQuery:               d.getGroupNodes,
+		GetGroupEdgesQuery:               d.getGroupEdges,
+		GetRecentEpisodesQuery:           d.getRecentEpisodes,
+		GetEpisodesByTopicQuery:          d.getEpisodesByTopic,
+		GetTopicEpisodeUUIDsQuery:        d.getTopicEpisodeUUIDs,
+		SetEpisodeTopicsQuery:            d.setEpisodeTopics,
+		GetEpisodesByUUIDQuery:           d.getEpisodesByUUID,
+		SearchEdgesByTextQuery:           d.searchEdgesByText,
+		SearchEdgesByVectorQuery:         d.searchEdgesByVector,
+		SearchEdgesQuery:                 d.searchEdges,
+		GetGroupStatsQuery:               d.getGroupStats,
+		ListGroupsQuery:                  d.listGroups,
+		EnsureGroupQuery:                 d.ensureGroup,
+		GetGroupQuery:                    d.getGroup,
+		SaveGroupQuery:                   d.saveGroup,
+		SaveIngestJobQuery:               d.saveIngestJob,
+		GetIngestJobQuery:                d.getIngestJob,
+		SaveIngestJobBatchQuery:          d.saveIngestJobBatch,
+		GetIngestJobBatchQuery:           d.getIngestJobBatch,
+		FailIngestJobQuery:               d.failIngestJob,
+		ListIngestJobsByStatusQuery:      d.listIngestJobsByStatus,
+		SaveDeadLetterQuery:              d.saveDeadLetter,
+		GetDeadLetterQuery:               d.getDeadLetter,
+		ListDeadLettersQuery:             d.listDeadLetters,
+		DeleteDeadLetterQuery:            d.deleteDeadLetter,
+		SaveModerationRecordQuery:        d.saveModerationRecord,
+		ListModerationRecordsQuery:       d.listModerationRecords,
+		GetEntityNodeQuery:               d.getEntityNode,
+		GetEntityFactsQuery:              d.getEntityFacts,
+		GetEntitySalienceQuery:           d.getEntitySalience,
+		GetEntityNodesQuery:              d.getEntityNodes,
+		GetNeighborEdgesQuery:            d.getNeighborEdges,
+		GetEntityCommunitiesQuery:        d.getEntityCommunities,
+		SaveMaintenanceReportQuery:       d.saveMaintenanceReport,
+		GetMaintenanceReportQuery:        d.getMaintenanceReport,
+		SaveScratchEntryQuery:            d.saveScratchEntry,
+		GetScratchEntriesQuery:           d.getScratchEntries,
+		DeleteScratchEntryQuery:          d.deleteScratchEntry,
+		DeleteExpiredScratchEntriesQuery: d.deleteExpiredScratchEntries,
+		AppendChangeQuery:                d.appendChange,
+		GetChangesQuery:                  d.getChanges,
+		GetGroupEdgeEpisodesQuery:        d.getGroupEdgeEpisodes,
+		SetEdgeEpisodesQuery:             d.setEdgeEpisodes,
+		ReinforceEntityEdgeQuery:         d.reinforceEntityEdge,
+		GetEntityEdgeQuery:               d.getEntityEdge,
+		GetEntityEdgeByCitationQuery:     d.getEntityEdgeByCitation,
+		DeleteEpisodeQuery:               d.deleteEpisode,
+		DeleteGroupQuery:                 d.deleteGroup,
+		ClearGraphQuery:                  d.clearGraph,
+		ExportNodesQuery:                 d.exportNodes,
+		ExportEdgesQuery:                 d.exportEdges,
+		DeleteEntityEdgeQuery:            d.deleteEntityEdge,
+		GetOrphanEntitiesQuery:           d.getOrphanEntities,
+		QuarantineEntityQuery:            d.quarantineEntity,
+		DeleteEntityNodeQuery:            d.deleteEntityNode,
+		GetOldestEpisodesQuery:           d.getOldestEpisodes,
+		GetEpisodesCreatedBeforeQuery:    d.getEpisodesCreatedBefore,
+		GetOldestEntitiesQuery:           d.getOldestEntities,
+		GetOldestEdgesQuery:              d.getOldestEdges,
+		GetCoMentionsQuery:               d.getCoMentions,
+		GetEpisodeMentionsQuery:          d.getEpisodeMentions,
+		GetEpisodeFactsQuery:             d.getEpisodeFacts,
+		FindPathsQuery:                   d.findPaths,
+		SearchEntitiesByNameVectorQuery:  d.searchEntitiesByNameVector,
+		GetSchemaVersionQuery:            d.getSchemaVersion,
+		SetSchemaVersionQuery:            d.setSchemaVersion,
+		BackfillEntityAttributesQuery:    d.backfillEntityAttributes,
+		BackfillEdgeEpisodesQuery:        d.backfillEdgeEpisodes,
+		BackfillEdgeCitationsQuery:       d.backfillEdgeCitations,
+		BackfillGroupsQuery:              d.backfillGroups,
+		BackfillContentRefsQuery:         d.backfillContentRefs,
+		GetStaleEntityEmbeddingsQuery:    d.getStaleEntityEmbeddings,
+		GetStaleCommunityEmbeddingsQuery: d.getStaleCommunityEmbeddings,
+		GetStaleFactEmbeddingsQuery:      d.getStaleFactEmbeddings,
+		StageNameEmbeddingsQuery:         d.stageNameEmbeddings,
+		StageFactEmbeddingsQuery:         d.stageFactEmbeddings,
+		PromoteEmbeddingsQuery:           d.promoteEmbeddings,
+		ImportNodesQuery:                 d.importNodes,
+	}
+	for relType, query := range ImportEdgesQueries {
+		d.handlers[query] = d.importEdges(relType)
+	}
+	return d
+}
+
+func (d *MemoryDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	handler, ok := d.handlers[query]
+	if !ok {
+		return neo4j.EagerResult{}, fmt.Errorf("query not supported by in-memory driver: %s", strings.TrimSpace(query))
+	}
+	return handler(params)
+}
+
+func (d *MemoryDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.ExecuteQuery(ctx, query, params)
+}
+
+func (d *MemoryDriver) BuildIndices(ctx context.Context) error {
+	return nil
+}
+
+func (d *MemoryDriver) Close(ctx context.Context) error {
+	return nil
+}
+
+// ---------------- Storage Helpers ----------------
+
+func (d *MemoryDriver) mergeNode(label string, params map[string]interface{}, keys ...string) *MemoryNode {
+	uuid := paramString(params, "uuid")
+	n, ok := d.nodes[uuid]
+	if !ok || !n.hasLabel(label) {
+		n = &MemoryNode{UUID: uuid, Labels: []string{label}, Props: map[string]interface{}{"uuid": uuid}}
+		d.nodes[uuid] = n
+	}
+	for _, k := range keys {
+		n.Props[k] = params[k]
+	}
+	return n
+}
+
+func (d *MemoryDriver) saveNode(label string, params map[string]interface{}, keys ...string) (neo4j.EagerResult, error) {
+	n := d.mergeNode(label, params, keys...)
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(n.UUID), nil
+}
+
+func (d *MemoryDriver) persistNode(n *MemoryNode) error {
+	if d.store == nil {
+		return nil
+	}
+	if err := d.store.putNode(n); err != nil {
+		return fmt.Errorf("failed to persist node %s: %w", n.UUID, err)
+	}
+	return nil
+}
+
+func (d *MemoryDriver) persistEdge(e *MemoryEdge) error {
+	if d.store == nil {
+		return nil
+	}
+	if err := d.store.putEdge(e); err != nil {
+		return fmt.Errorf("failed to persist edge %s: %w", e.UUID, err)
+	}
+	return nil
+}
+
+func (d *MemoryDriver) removeEdge(uuid string) error {
+	delete(d.edges, uuid)
+	if d.store == nil {
+		return nil
+	}
+	if err := d.store.deleteEdge(uuid); err != nil {
+		return fmt.Errorf("failed to delete edge %s: %w", uuid, err)
+	}
+	return nil
+}
+
+// removeNode deletes a node and every edge attached to it, like DETACH DELETE.
+func (d *MemoryDriver) removeNode(uuid string) error {
+	for _, e := range d.edges {
+		if e.SourceUUID == uuid || e.TargetUUID == uuid {
+			if err := d.removeEdge(e.UUID); err != nil {
+				return err
+			}
+		}
+	}
+	delete(d.nodes, uuid)
+	if d.store == nil {
+		return nil
+	}
+	if err := d.store.deleteNode(uuid); err != nil {
+		return fmt.Errorf("failed to delete node %s: %w", uuid, err)
+	}
+	return nil
+}
+
+func (d *MemoryDriver) mergeEdge(relType, sourceLabel, targetLabel string, params map[string]interface{}, keys ...string) (neo4j.EagerResult, error) {
+	source, ok := d.nodes[paramString(params, "source_uuid")]
+	if !ok || !source.hasLabel(sourceLabel) {
+		return uuidResult(), nil
+	}
+	target, ok := d.nodes[paramString(params, "target_uuid")]
+	if !ok || !target.hasLabel(targetLabel) {
+		return uuidResult(), nil
+	}
+
+	uuid := paramString(params, "uuid")
+	e, ok := d.edges[uuid]
+	if !ok {
+		e = &MemoryEdge{UUID: uuid, Type: relType, SourceUUID: source.UUID, TargetUUID: target.UUID, Props: map[string]interface{}{"uuid": uuid}}
+		d.edges[uuid] = e
+	}
+	for _, k := range keys {
+		e.Props[k] = params[k]
+	}
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(uuid), nil
+}
+
+func (d *MemoryDriver) nodesWithLabel(label string) []*MemoryNode {
+	var nodes []*MemoryNode
+	for _, n := range d.nodes {
+		if n.hasLabel(label) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].UUID < nodes[j].UUID })
+	return nodes
+}
+
+func (d *MemoryDriver) edgesOfType(relType string) []*MemoryEdge {
+	var edges []*MemoryEdge
+	for _, e := range d.edges {
+		if e.Type == relType {
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].UUID < edges[j].UUID })
+	return edges
+}
+
+func (n *MemoryNode) hasLabel(label string) bool {
+	for _, l := range n.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *MemoryEdge) isActive() bool {
+	v := e.Props["invalid_at"]
+	return v == nil || v == ""
+}
+
+// ---------------- Write Handlers ----------------
+
+func (d *MemoryDriver) saveEntityNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	labels, _ := params["labels"].([]string)
+	if _, err := WithLabels(SaveEntityNodeQuery, "n", labels); err != nil {
+		return neo4j.EagerResult{}, fmt.Errorf("failed to save entity node: %w", err)
+	}
+	n := d.mergeNode("Entity", params, "name", "group_id", "created_at", "summary", "name_embedding", "name_embedding_model", "attributes")
+	n.Props["version"] = nodeVersion(n.Props) + 1
+	for _, l := range labels {
+		if l != "" && !n.hasLabel(l) {
+			n.Labels = append(n.Labels, l)
+		}
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	keys := []string{"uuid", "version"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID, n.Props["version"])}), nil
+}
+
+func (d *MemoryDriver) updateEntityNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"uuid", "version"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Entity") {
+		return newResult(keys, nil), nil
+	}
+	if params["version"] != nil && nodeVersion(n.Props) != nodeVersion(params) {
+		return newResult(keys, nil), nil
+	}
+	for _, k := range []string{"name", "summary", "attributes"} {
+		n.Props[k] = params[k]
+	}
+	if params["name_embedding"] != nil { // Kept when not re-embedded
+		n.Props["name_embedding"] = params["name_embedding"]
+		n.Props["name_embedding_model"] = params["name_embedding_model"]
+	}
+	n.Props["version"] = nodeVersion(n.Props) + 1
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID, n.Props["version"])}), nil
+}
+
+func (d *MemoryDriver) setEntitySummary(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"uuid", "version"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Entity") {
+		return newResult(keys, nil), nil
+	}
+	n.Props["summary"] = params["summary"]
+	n.Props["version"] = nodeVersion(n.Props) + 1
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID, n.Props["version"])}), nil
+}
+
+func (d *MemoryDriver) saveEpisodicNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.saveNode("Episodic", params, "name", "group_id", "created_at", "valid_at", "content", "content_ref", "source", "source_description", "entity_edges")
+}
+
+func (d *MemoryDriver) saveCommunityNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.saveNode("Community", params, "name", "group_id", "created_at", "summary", "name_embedding", "name_embedding_model")
+}
+
+func (d *MemoryDriver) saveSagaNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.saveNode("Saga", params, "name", "group_id", "created_at")
+}
+
+func (d *MemoryDriver) saveEntityEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mergeEdge("RELATES_TO", "Entity", "Entity", params,
+		"name", "fact", "group_id", "created_at", "expired_at", "valid_at", "invalid_at", "episodes", "fact_embedding", "fact_embedding_model", "attributes", "citation")
+}
+
+func (d *MemoryDriver) saveEpisodicEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mergeEdge("MENTIONS", "Episodic", "Entity", params, "group_id", "created_at")
+}
+
+func (d *MemoryDriver) saveNextEpisodeEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mergeEdge("NEXT_EPISODE", "Episodic", "Episodic", params, "group_id", "created_at")
+}
+
+func (d *MemoryDriver) saveHasEpisodeEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mergeEdge("HAS_EPISODE", "Saga", "Episodic", params, "group_id", "created_at")
+}
+
+func (d *MemoryDriver) saveCommunityEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mergeEdge("HAS_MEMBER", "Community", "Entity", params, "group_id", "created_at")
+}
+
+func (d *MemoryDriver) getGroupCommunities(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var communities []*MemoryNode
+	for _, n := range d.nodesWithLabel("Community") {
+		if n.Props["group_id"] == params["group_id"] {
+			communities = append(communities, n)
+		}
+	}
+	sort.Slice(communities, func(i, j int) bool {
+		a, b := propString(communities[i].Props, "created_at"), propString(communities[j].Props, "created_at")
+		if a != b {
+			return a < b
+		}
+		return communities[i].UUID < communities[j].UUID
+	})
+	members := make(map[string][]interface{})
+	for _, e := range d.edgesOfType("HAS_MEMBER") {
+		if _, ok := d.nodes[e.TargetUUID]; ok {
+			members[e.SourceUUID] = append(members[e.SourceUUID], e.TargetUUID)
+		}
+	}
+	keys := []string{"uuid", "name", "summary", "created_at", "members"}
+	var records []*neo4j.Record
+	for _, c := range communities {
+		m := members[c.UUID]
+		if m == nil {
+			m = []interface{}{}
+		}
+		records = append(records, newRecord(keys, c.UUID, c.Props["name"], c.Props["summary"], c.Props["created_at"], m))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) deleteCommunityMembers(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var deleted []string
+	for _, e := range d.edgesOfType("HAS_MEMBER") {
+		if e.SourceUUID != paramString(params, "uuid") {
+			continue
+		}
+		if err := d.removeEdge(e.UUID); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		deleted = append(deleted, e.UUID)
+	}
+	return uuidResult(deleted...), nil
+}
+
+func (d *MemoryDriver) deleteCommunity(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Community") {
+		return uuidResult(), nil
+	}
+	if err := d.removeNode(n.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(n.UUID), nil
+}
+
+func (d *MemoryDriver) invalidateEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return uuidResult(), nil
+	}
+	e.Props["invalid_at"] = params["invalid_at"]
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(e.UUID), nil
+}
+
+func (d *MemoryDriver) setEdgeExpiry(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return uuidResult(), nil
+	}
+	e.Props["expired_at"] = params["expired_at"]
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(e.UUID), nil
+}
+
+func (d *MemoryDriver) reinstateEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return uuidResult(), nil
+	}
+	e.Props["invalid_at"] = ""
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(e.UUID), nil
+}
+
+func (d *MemoryDriver) setEdgeEpisodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return uuidResult(), nil
+	}
+	e.Props["episodes"] = params["episodes"]
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(e.UUID), nil
+}
+
+func (d *MemoryDriver) reinforceEntityEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"uuid", "mention_count"}
+	e, ok := d.edges[paramString(params, "uuid")]
+	episode := paramString(params, "episode_uuid")
+	if !ok || e.Type != "RELATES_TO" || slices.Contains(paramStrings(e.Props, "episodes"), episode) {
+		return newResult(keys, nil), nil
+	}
+	e.Props["episodes"] = append(paramStrings(e.Props, "episodes"), episode)
+	e.Props["mention_count"] = mentionCount(e.Props) + 1
+	if err := d.persistEdge(e); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, e.UUID, e.Props["mention_count"])}), nil
+}
+
+func (d *MemoryDriver) deleteEntityEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return uuidResult(), nil
+	}
+	if err := d.removeEdge(e.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return deletedResult(e.UUID, e.Props), nil
+}
+
+func (d *MemoryDriver) quarantineEntity(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Entity") {
+		return uuidResult(), nil
+	}
+	labels := []string{"QuarantinedEntity"}
+	for _, l := range n.Labels {
+		if l != "Entity" && l != "QuarantinedEntity" {
+			labels = append(labels, l)
+		}
+	}
+	n.Labels = labels
+	n.Props["quarantined_at"] = params["quarantined_at"]
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return deletedResult(n.UUID, n.Props), nil
+}
+
+func (d *MemoryDriver) deleteEntityNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Entity") {
+		return deletedResult("", nil), nil
+	}
+	if err := d.removeNode(n.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return deletedResult(n.UUID, n.Props), nil
+}
+
+// deletedResult answers the delete queries returning the uuid and group_id of
+// what they removed; an empty uuid means nothing was.
+func deletedResult(uuid string, props map[string]interface{}) neo4j.EagerResult {
+	keys := []string{"uuid", "group_id"}
+	if uuid == "" {
+		return newResult(keys, nil)
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, uuid, props["group_id"])})
+}
+
+func (d *MemoryDriver) getOldestEpisodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.oldestNodes("Episodic", params), nil
+}
+
+func (d *MemoryDriver) getEpisodesCreatedBefore(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	cutoff := paramString(params, "created_before")
+	var episodes []*MemoryNode
+	for _, n := range d.nodesWithLabel("Episodic") {
+		if n.Props["group_id"] == params["group_id"] && propString(n.Props, "created_at") < cutoff {
+			episodes = append(episodes, n)
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return propString(episodes[i].Props, "created_at") < propString(episodes[j].Props, "created_at")
+	})
+	var uuids []string
+	for _, n := range episodes {
+		uuids = append(uuids, n.UUID)
+	}
+	return uuidResult(uuids...), nil
+}
+
+func (d *MemoryDriver) getOldestEntities(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.oldestNodes("Entity", params), nil
+}
+
+// oldestNodes returns the UUIDs of the group's nodes with label, oldest first.
+func (d *MemoryDriver) oldestNodes(label string, params map[string]interface{}) neo4j.EagerResult {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	exclude := paramStrings(params, "exclude")
+	var nodes []*MemoryNode
+	for _, n := range d.nodesWithLabel(label) {
+		if n.Props["group_id"] == params["group_id"] && !slices.Contains(exclude, n.UUID) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return propString(nodes[i].Props, "created_at") < propString(nodes[j].Props, "created_at")
+	})
+	var uuids []string
+	for _, n := range limitSlice(nodes, params["limit"]) {
+		uuids = append(uuids, n.UUID)
+	}
+	return uuidResult(uuids...)
+}
+
+func (d *MemoryDriver) getOldestEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var edges []*MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] == params["group_id"] {
+			edges = append(edges, e)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].isActive() != edges[j].isActive() {
+			return !edges[i].isActive()
+		}
+		return propString(edges[i].Props, "created_at") < propString(edges[j].Props, "created_at")
+	})
+	var uuids []string
+	for _, e := range limitSlice(edges, params["limit"]) {
+		uuids = append(uuids, e.UUID)
+	}
+	return uuidResult(uuids...), nil
+}
+
+func (d *MemoryDriver) getEpisodeMentions(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	episodes := paramStrings(params, "uuids")
+	var uuids []string
+	for _, e := range d.edgesOfType("MENTIONS") {
+		if slices.Contains(episodes, e.SourceUUID) && !slices.Contains(uuids, e.TargetUUID) {
+			uuids = append(uuids, e.TargetUUID)
+		}
+	}
+	return uuidResult(uuids...), nil
+}
+
+func (d *MemoryDriver) getEpisodeFacts(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "episodes"}
+	episodes := paramStrings(params, "uuids")
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] {
+			continue
+		}
+		if slices.ContainsFunc(paramStrings(e.Props, "episodes"), func(uuid string) bool { return slices.Contains(episodes, uuid) }) {
+			records = append(records, newRecord(keys, e.UUID, e.Props["episodes"]))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) deleteEpisode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"uuid", "content_ref", "group_id"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Episodic") {
+		return newResult(keys, nil), nil
+	}
+	if err := d.removeNode(n.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID, n.Props["content_ref"], n.Props["group_id"])}), nil
+}
+
+func (d *MemoryDriver) deleteGroup(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.deleteNodes(func(n *MemoryNode) bool { return n.Props["group_id"] == params["group_id"] && !n.isChangeLog() })
+}
+
+func (d *MemoryDriver) clearGraph(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.deleteNodes(func(n *MemoryNode) bool { return !n.hasLabel("SchemaVersion") && !n.isChangeLog() })
+}
+
+// isChangeLog reports whether n belongs to a group's change log, which
+// outlives the deletion of the group's data.
+func (n *MemoryNode) isChangeLog() bool {
+	return n.hasLabel("Change") || n.hasLabel("ChangeSequence")
+}
+
+// deleteNodes detach-deletes every node matching and returns the count as "deleted".
+func (d *MemoryDriver) deleteNodes(match func(*MemoryNode) bool) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var matched []string
+	for uuid, n := range d.nodes {
+		if match(n) {
+			matched = append(matched, uuid)
+		}
+	}
+	for _, uuid := range matched {
+		if err := d.removeNode(uuid); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+	}
+	keys := []string{"deleted"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(len(matched)))}), nil
+}
+
+func (d *MemoryDriver) exportNodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var nodes []*MemoryNode
+	for _, n := range d.nodes {
+		if params["group_id"] == nil && !n.hasLabel("SchemaVersion") || params["group_id"] != nil && n.Props["group_id"] == params["group_id"] {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].UUID < nodes[j].UUID })
+	keys := []string{"labels", "properties"}
+	records := make([]*neo4j.Record, 0, len(nodes))
+	for _, n := range nodes {
+		records = append(records, newRecord(keys, slices.Clone(n.Labels), maps.Clone(n.Props)))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) exportEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var edges []*MemoryEdge
+	for _, e := range d.edges {
+		if params["group_id"] == nil || e.Props["group_id"] == params["group_id"] {
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].UUID < edges[j].UUID })
+	keys := []string{"type", "source_uuid", "target_uuid", "properties"}
+	records := make([]*neo4j.Record, 0, len(edges))
+	for _, e := range edges {
+		records = append(records, newRecord(keys, e.Type, e.SourceUUID, e.TargetUUID, maps.Clone(e.Props)))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) importNodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	imported := 0
+	for _, node := range paramMaps(params, "nodes") {
+		n := &MemoryNode{Props: map[string]interface{}{}}
+		for _, l := range toList(node["labels"]) {
+			if label, ok := l.(string); ok {
+				n.Labels = append(n.Labels, label)
+			}
+		}
+		if props, ok := node["properties"].(map[string]interface{}); ok {
+			n.Props = maps.Clone(props)
+		}
+		// Nodes without a uuid are keyed like the handlers that create them
+		switch {
+		case propString(n.Props, "uuid") != "":
+			n.UUID = propString(n.Props, "uuid")
+		case n.hasLabel("Group"):
+			n.UUID = groupNodeKey(propString(n.Props, "group_id"))
+		case n.hasLabel("MaintenanceReport"):
+			n.UUID = reportNodeKey(propString(n.Props, "kind"), propString(n.Props, "group_id"))
+		default:
+			return neo4j.EagerResult{}, fmt.Errorf("cannot import %v node without a uuid", n.Labels)
+		}
+		d.nodes[n.UUID] = n
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		imported++
+	}
+	keys := []string{"imported"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(imported))}), nil
+}
+
+func (d *MemoryDriver) importEdges(relType string) memoryHandler {
+	return func(params map[string]interface{}) (neo4j.EagerResult, error) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		imported := 0
+		for _, edge := range paramMaps(params, "edges") {
+			source, target := propString(edge, "source_uuid"), propString(edge, "target_uuid")
+			if d.nodes[source] == nil || d.nodes[target] == nil {
+				continue
+			}
+			e := &MemoryEdge{Type: relType, SourceUUID: source, TargetUUID: target, Props: map[string]interface{}{}}
+			if props, ok := edge["properties"].(map[string]interface{}); ok {
+				e.Props = maps.Clone(props)
+			}
+			e.UUID = propString(e.Props, "uuid")
+			d.edges[e.UUID] = e
+			if err := d.persistEdge(e); err != nil {
+				return neo4j.EagerResult{}, err
+			}
+			imported++
+		}
+		keys := []string{"imported"}
+		return newResult(keys, []*neo4j.Record{newRecord(keys, int64(imported))}), nil
+	}
+}
+
+const schemaVersionKey = "schema:graph"
+
+func (d *MemoryDriver) getSchemaVersion(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"version", "dirty"}
+	n, ok := d.nodes[schemaVersionKey]
+	if !ok {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.Props["version"], n.Props["dirty"])}), nil
+}
+
+func (d *MemoryDriver) setSchemaVersion(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := &MemoryNode{UUID: schemaVersionKey, Labels: []string{"SchemaVersion"}, Props: map[string]interface{}{
+		"id":         "graph",
+		"version":    params["version"],
+		"name":       params["name"],
+		"dirty":      params["dirty"],
+		"applied_at": params["applied_at"],
+	}}
+	d.nodes[n.UUID] = n
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	keys := []string{"version"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, params["version"])}), nil
+}
+
+func (d *MemoryDriver) backfillEntityAttributes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated := 0
+	for _, n := range d.nodesWithLabel("Entity") {
+		if v := n.Props["attributes"]; v != nil && v != "" {
+			continue
+		}
+		n.Props["attributes"] = "{}"
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		updated++
+	}
+	keys := []string{"updated"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(updated))}), nil
+}
+
+func (d *MemoryDriver) backfillEdgeEpisodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated := 0
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["episodes"] != nil {
+			continue
+		}
+		e.Props["episodes"] = []string{}
+		if err := d.persistEdge(e); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		updated++
+	}
+	keys := []string{"updated"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(updated))}), nil
+}
+
+func (d *MemoryDriver) backfillEdgeCitations(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	taken := make(map[string]bool)
+	shared := make(map[string]int)
+	var pending []*MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		groupID := propString(e.Props, "group_id")
+		if c := propString(e.Props, "citation"); c != "" {
+			taken[groupID+"/"+c] = true
+			continue
+		}
+		pending = append(pending, e)
+		shared[groupID+"/F-"+leftString(e.UUID, 8)]++
+	}
+	for _, e := range pending {
+		key := propString(e.Props, "group_id") + "/F-" + leftString(e.UUID, 8)
+		e.Props["citation"] = "F-" + e.UUID
+		if shared[key] == 1 && !taken[key] {
+			e.Props["citation"] = "F-" + leftString(e.UUID, 8)
+		}
+		if err := d.persistEdge(e); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+	}
+	keys := []string{"updated"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(len(pending)))}), nil
+}
+
+func (d *MemoryDriver) backfillGroups(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	firstEpisode := make(map[string]string)
+	var order []string
+	for _, ep := range d.nodesWithLabel("Episodic") {
+		groupID, ok := ep.Props["group_id"].(string)
+		if !ok {
+			continue
+		}
+		createdAt, seen := firstEpisode[groupID]
+		if !seen {
+			order = append(order, groupID)
+		}
+		if at := propString(ep.Props, "created_at"); !seen || at < createdAt {
+			firstEpisode[groupID] = at
+		}
+	}
+	created := 0
+	for _, groupID := range order {
+		if _, ok := d.nodes[groupNodeKey(groupID)]; ok {
+			continue
+		}
+		createdAt := firstEpisode[groupID]
+		n := &MemoryNode{UUID: groupNodeKey(groupID), Labels: []string{"Group"}, Props: map[string]interface{}{
+			"group_id":   groupID,
+			"name":       groupID,
+			"created_at": createdAt,
+			"updated_at": createdAt,
+			"settings":   "{}",
+			"metadata":   "{}",
+		}}
+		d.nodes[n.UUID] = n
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		created++
+	}
+	keys := []string{"updated"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(created))}), nil
+}
+
+func (d *MemoryDriver) backfillContentRefs(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	updated := 0
+	for _, ep := range d.nodesWithLabel("Episodic") {
+		key, ok := strings.CutPrefix(propString(ep.Props, "content"), "blob:v1:")
+		if !ok || ep.Props["content_ref"] != nil {
+			continue
+		}
+		ep.Props["content_ref"], ep.Props["content"] = key, ""
+		if err := d.persistNode(ep); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		updated++
+	}
+	keys := []string{"updated"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(updated))}), nil
+}
+
+func leftString(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// Group nodes are keyed by group_id rather than a uuid.
+func groupNodeKey(groupID string) string {
+	return "group:" + groupID
+}
+
+func (d *MemoryDriver) ensureGroup(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	groupID := paramString(params, "group_id")
+	n, ok := d.nodes[groupNodeKey(groupID)]
+	if !ok {
+		n = &MemoryNode{UUID: groupNodeKey(groupID), Labels: []string{"Group"}, Props: map[string]interface{}{
+			"group_id":   groupID,
+			"name":       groupID,
+			"created_at": params["created_at"],
+			"updated_at": params["created_at"],
+			"settings":   "{}",
+			"metadata":   "{}",
+		}}
+		d.nodes[n.UUID] = n
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+	}
+	return groupResult(n), nil
+}
+
+func (d *MemoryDriver) saveGroup(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	groupID := paramString(params, "group_id")
+	n, ok := d.nodes[groupNodeKey(groupID)]
+	if !ok {
+		n = &MemoryNode{UUID: groupNodeKey(groupID), Labels: []string{"Group"}, Props: map[string]interface{}{
+			"group_id":   groupID,
+			"created_at": params["created_at"],
+		}}
+		d.nodes[n.UUID] = n
+	}
+	for _, k := range []string{"name", "owner", "updated_at", "settings", "metadata"} {
+		n.Props[k] = params[k]
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult([]string{"group_id"}, []*neo4j.Record{newRecord([]string{"group_id"}, groupID)}), nil
+}
+
+func groupResult(n *MemoryNode) neo4j.EagerResult {
+	keys := []string{"group_id", "name", "owner", "created_at", "updated_at", "settings", "metadata"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys,
+		n.Props["group_id"], n.Props["name"], n.Props["owner"], n.Props["created_at"],
+		n.Props["updated_at"], n.Props["settings"], n.Props["metadata"],
+	)})
+}
+
+// Maintenance reports are keyed by kind and group_id.
+func reportNodeKey(kind, groupID string) string {
+	return "report:" + kind + ":" + groupID
+}
+
+func (d *MemoryDriver) saveMaintenanceReport(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	kind, groupID := paramString(params, "kind"), paramString(params, "group_id")
+	n := &MemoryNode{UUID: reportNodeKey(kind, groupID), Labels: []string{"MaintenanceReport"}, Props: map[string]interface{}{
+		"kind":       kind,
+		"group_id":   groupID,
+		"created_at": params["created_at"],
+		"report":     params["report"],
+	}}
+	d.nodes[n.UUID] = n
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult([]string{"kind"}, []*neo4j.Record{newRecord([]string{"kind"}, kind)}), nil
+}
+
+// scratchEntry finds the group's scratchpad entry named key.
+func (d *MemoryDriver) scratchEntry(groupID, key string) *MemoryNode {
+	for _, n := range d.nodesWithLabel("ScratchEntry") {
+		if propString(n.Props, "group_id") == groupID && propString(n.Props, "key") == key {
+			return n
+		}
+	}
+	return nil
+}
+
+// scratchLive reports whether a scratchpad entry has not expired by now.
+func scratchLive(n *MemoryNode, now string) bool {
+	return n.Props["expires_at"] == nil || propString(n.Props, "expires_at") > now
+}
+
+func (d *MemoryDriver) saveScratchEntry(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	groupID, key := paramString(params, "group_id"), paramString(params, "key")
+	n := d.scratchEntry(groupID, key)
+	if n == nil {
+		uuid := paramString(params, "uuid")
+		n = &MemoryNode{UUID: uuid, Labels: []string{"ScratchEntry"}, Props: map[string]interface{}{
+			"uuid":       uuid,
+			"group_id":   groupID,
+			"key":        key,
+			"created_at": params["updated_at"],
+		}}
+		d.nodes[uuid] = n
+	}
+	for _, k := range []string{"value", "updated_at", "expires_at"} {
+		n.Props[k] = params[k]
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	keys := []string{"key"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, key)}), nil
+}
+
+func (d *MemoryDriver) deleteScratchEntry(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"key", "live"}
+	n := d.scratchEntry(paramString(params, "group_id"), paramString(params, "key"))
+	if n == nil {
+		return newResult(keys, nil), nil
+	}
+	live := scratchLive(n, paramString(params, "now"))
+	if err := d.removeNode(n.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, propString(n.Props, "key"), live)}), nil
+}
+
+func (d *MemoryDriver) deleteExpiredScratchEntries(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"key"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel("ScratchEntry") {
+		if n.Props["group_id"] != params["group_id"] || scratchLive(n, paramString(params, "now")) {
+			continue
+		}
+		if err := d.removeNode(n.UUID); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		records = append(records, newRecord(keys, propString(n.Props, "key")))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) appendChange(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var seq *MemoryNode
+	for _, n := range d.nodesWithLabel("ChangeSequence") {
+		if n.Props["group_id"] == params["group_id"] {
+			seq = n
+			break
+		}
+	}
+	if seq == nil {
+		uuid := paramString(params, "sequence_uuid")
+		seq = &MemoryNode{UUID: uuid, Labels: []string{"ChangeSequence"}, Props: map[string]interface{}{"uuid": uuid, "group_id": params["group_id"]}}
+		d.nodes[uuid] = seq
+	}
+	seq.Props["seq"] = propInt(seq.Props, "seq") + 1
+	if err := d.persistNode(seq); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	uuid := paramString(params, "uuid")
+	change := &MemoryNode{UUID: uuid, Labels: []string{"Change"}, Props: map[string]interface{}{
+		"uuid": uuid, "group_id": params["group_id"], "seq": seq.Props["seq"], "kind": params["kind"],
+		"target_uuid": params["target_uuid"], "op": params["op"], "at": params["at"],
+	}}
+	d.nodes[uuid] = change
+	if err := d.persistNode(change); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	keys := []string{"seq"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, seq.Props["seq"])}), nil
+}
+
+func (d *MemoryDriver) getChanges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var changes []*MemoryNode
+	for _, n := range d.nodesWithLabel("Change") {
+		if n.Props["group_id"] == params["group_id"] && propInt(n.Props, "seq") > propInt(params, "since") {
+			changes = append(changes, n)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return propInt(changes[i].Props, "seq") < propInt(changes[j].Props, "seq") })
+	keys := []string{"group_id", "seq", "kind", "uuid", "op", "at"}
+	var records []*neo4j.Record
+	for _, n := range limitSlice(changes, params["limit"]) {
+		records = append(records, newRecord(keys, n.Props["group_id"], propInt(n.Props, "seq"), n.Props["kind"], n.Props["target_uuid"], n.Props["op"], n.Props["at"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) saveIngestJob(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	uuid := paramString(params, "uuid")
+	n, ok := d.nodes[uuid]
+	if !ok || !n.hasLabel("IngestJob") {
+		n = &MemoryNode{UUID: uuid, Labels: []string{"IngestJob"}, Props: map[string]interface{}{
+			"uuid":       uuid,
+			"created_at": params["created_at"],
+			"total":      params["total"],
+		}}
+		d.nodes[uuid] = n
+	}
+	for _, k := range []string{"group_id", "status", "processed", "failed", "entity_map", "error", "updated_at"} {
+		n.Props[k] = params[k]
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(uuid), nil
+}
+
+func (d *MemoryDriver) failIngestJob(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("IngestJob") {
+		return newResult([]string{"uuid"}, nil), nil
+	}
+	for _, k := range []string{"status", "error", "updated_at"} {
+		n.Props[k] = params[k]
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(n.UUID), nil
+}
+
+func (d *MemoryDriver) saveDeadLetter(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	uuid := paramString(params, "uuid")
+	n, ok := d.nodes[uuid]
+	if !ok || !n.hasLabel("DeadLetter") {
+		n = &MemoryNode{UUID: uuid, Labels: []string{"DeadLetter"}, Props: map[string]interface{}{
+			"uuid":       uuid,
+			"created_at": params["updated_at"],
+			"attempts":   0,
+		}}
+		d.nodes[uuid] = n
+	}
+	for _, k := range []string{"group_id", "name", "episode", "error", "updated_at"} {
+		n.Props[k] = params[k]
+	}
+	// Numbers come back as float64 from the SQLite store's JSON
+	switch attempts := n.Props["attempts"].(type) {
+	case int:
+		n.Props["attempts"] = attempts + 1
+	case float64:
+		n.Props["attempts"] = int(attempts) + 1
+	default:
+		n.Props["attempts"] = 1
+	}
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(uuid), nil
+}
+
+func (d *MemoryDriver) deleteDeadLetter(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("DeadLetter") {
+		return uuidResult(), nil
+	}
+	if err := d.removeNode(n.UUID); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(n.UUID), nil
+}
+
+// ---------------- Read Handlers ----------------
+
+func (d *MemoryDriver) getMaintenanceReport(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"report", "created_at"}
+	n, ok := d.nodes[reportNodeKey(paramString(params, "kind"), paramString(params, "group_id"))]
+	if !ok {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.Props["report"], n.Props["created_at"])}), nil
+}
+
+func (d *MemoryDriver) getScratchEntries(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var entries []*MemoryNode
+	for _, n := range d.nodesWithLabel("ScratchEntry") {
+		if n.Props["group_id"] != params["group_id"] || !scratchLive(n, paramString(params, "now")) {
+			continue
+		}
+		if params["key"] != nil && n.Props["key"] != params["key"] {
+			continue
+		}
+		entries = append(entries, n)
+	}
+	sort.Slice(entries, func(i, j int) bool { return propString(entries[i].Props, "key") < propString(entries[j].Props, "key") })
+	keys := []string{"key", "value", "created_at", "updated_at", "expires_at"}
+	var records []*neo4j.Record
+	for _, n := range entries {
+		records = append(records, newRecord(keys, n.Props["key"], n.Props["value"], n.Props["created_at"], n.Props["updated_at"], n.Props["expires_at"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getIngestJob(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "group_id", "status", "total", "processed", "failed", "error", "created_at", "updated_at", "entity_map"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("IngestJob") {
+		return newResult(keys, nil), nil
+	}
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		values[i] = n.Props[k]
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, values...)}), nil
+}
+
+func (d *MemoryDriver) saveIngestJobBatch(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	uuid := paramString(params, "uuid")
+	n := &MemoryNode{UUID: uuid, Labels: []string{"IngestJobBatch"}, Props: map[string]interface{}{"uuid": uuid}}
+	for _, k := range []string{"job_uuid", "group_id", "seq", "episodes"} {
+		n.Props[k] = params[k]
+	}
+	d.nodes[uuid] = n
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(uuid), nil
+}
+
+func (d *MemoryDriver) getIngestJobBatch(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"episodes"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("IngestJobBatch") {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.Props["episodes"])}), nil
+}
+
+var deadLetterKeys = []string{"uuid", "group_id", "name", "episode", "error", "attempts", "created_at", "updated_at"}
+
+func deadLetterRecord(n *MemoryNode) *neo4j.Record {
+	values := make([]interface{}, len(deadLetterKeys))
+	for i, k := range deadLetterKeys {
+		values[i] = n.Props[k]
+	}
+	return newRecord(deadLetterKeys, values...)
+}
+
+func (d *MemoryDriver) getDeadLetter(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("DeadLetter") {
+		return newResult(deadLetterKeys, nil), nil
+	}
+	return newResult(deadLetterKeys, []*neo4j.Record{deadLetterRecord(n)}), nil
+}
+
+func (d *MemoryDriver) listDeadLetters(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var letters []*MemoryNode
+	for _, n := range d.nodesWithLabel("DeadLetter") {
+		if params["group_id"] == nil || n.Props["group_id"] == params["group_id"] {
+			letters = append(letters, n)
+		}
+	}
+	sort.SliceStable(letters, func(i, j int) bool {
+		return propString(letters[i].Props, "updated_at") > propString(letters[j].Props, "updated_at")
+	})
+	records := make([]*neo4j.Record, 0, len(letters))
+	for _, n := range letters {
+		records = append(records, deadLetterRecord(n))
+	}
+	return newResult(deadLetterKeys, records), nil
+}
+
+var moderationRecordKeys = []string{"uuid", "group_id", "episode_name", "episode_uuid", "action", "categories", "content_sha256", "created_at"}
+
+func (d *MemoryDriver) saveModerationRecord(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	uuid := paramString(params, "uuid")
+	n := &MemoryNode{UUID: uuid, Labels: []string{"ModerationRecord"}, Props: map[string]interface{}{}}
+	for _, k := range moderationRecordKeys {
+		n.Props[k] = params[k]
+	}
+	d.nodes[uuid] = n
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return uuidResult(uuid), nil
+}
+
+func (d *MemoryDriver) listModerationRecords(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var found []*MemoryNode
+	for _, n := range d.nodesWithLabel("ModerationRecord") {
+		if n.Props["group_id"] == params["group_id"] {
+			found = append(found, n)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return propString(found[i].Props, "created_at") > propString(found[j].Props, "created_at")
+	})
+	if limit, ok := params["limit"].(int); ok && limit >= 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	records := make([]*neo4j.Record, 0, len(found))
+	for _, n := range found {
+		values := make([]interface{}, len(moderationRecordKeys))
+		for i, k := range moderationRecordKeys {
+			values[i] = n.Props[k]
+		}
+		records = append(records, newRecord(moderationRecordKeys, values...))
+	}
+	return newResult(moderationRecordKeys, records), nil
+}
+
+func (d *MemoryDriver) listIngestJobsByStatus(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	statuses := make(map[string]bool)
+	if list, ok := params["statuses"].([]string); ok {
+		for _, s := range list {
+			statuses[s] = true
+		}
+	}
+	jobs := d.nodesWithLabel("IngestJob")
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return propString(jobs[i].Props, "created_at") < propString(jobs[j].Props, "created_at")
+	})
+	var uuids []string
+	for _, n := range jobs {
+		if statuses[propString(n.Props, "status")] {
+			uuids = append(uuids, n.UUID)
+		}
+	}
+	return uuidResult(uuids...), nil
+}
+
+func (d *MemoryDriver) getGroup(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	n, ok := d.nodes[groupNodeKey(paramString(params, "group_id"))]
+	if !ok {
+		return newResult(nil, nil), nil
+	}
+	return groupResult(n), nil
+}
+
+func (d *MemoryDriver) getSagaByName(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "name", "group_id", "created_at"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel("Saga") {
+		if n.Props["name"] == params["name"] && n.Props["group_id"] == params["group_id"] {
+			records = append(records, newRecord(keys, n.UUID, n.Props["name"], n.Props["group_id"], n.Props["created_at"]))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getSaga(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "name", "group_id", "created_at"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Saga") {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID, n.Props["name"], n.Props["group_id"], n.Props["created_at"])}), nil
+}
+
+func (d *MemoryDriver) getPreviousEpisodeInSaga(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	sagaUUID := paramString(params, "saga_uuid")
+	current := paramString(params, "current_episode_uuid")
+
+	var episodes []*MemoryNode
+	for _, e := range d.edgesOfType("HAS_EPISODE") {
+		if e.SourceUUID != sagaUUID || e.TargetUUID == current {
+			continue
+		}
+		if ep, ok := d.nodes[e.TargetUUID]; ok {
+			episodes = append(episodes, ep)
+		}
+	}
+	followed := make(map[string]bool)
+	for _, e := range d.edgesOfType("NEXT_EPISODE") {
+		followed[e.SourceUUID] = true
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		vi, vj := propString(episodes[i].Props, "valid_at"), propString(episodes[j].Props, "valid_at")
+		if vi != vj {
+			return vi > vj
+		}
+		ci, cj := propString(episodes[i].Props, "created_at"), propString(episodes[j].Props, "created_at")
+		if ci != cj {
+			return ci > cj
+		}
+		return !followed[episodes[i].UUID] && followed[episodes[j].UUID]
+	})
+
+	keys := []string{"uuid"}
+	var records []*neo4j.Record
+	if len(episodes) > 0 {
+		records = append(records, newRecord(keys, episodes[0].UUID))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getActiveEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "fact"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.SourceUUID == paramString(params, "source_uuid") && e.TargetUUID == paramString(params, "target_uuid") &&
+			e.Props["name"] == params["name"] && e.isActive() {
+			records = append(records, newRecord(keys, e.UUID, e.Props["fact"]))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getActiveEdgesFromSource(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "fact", "name", "target_uuid"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.SourceUUID == paramString(params, "source_uuid") && e.isActive() {
+			records = append(records, newRecord(keys, e.UUID, e.Props["fact"], e.Props["name"], e.TargetUUID))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getEdgeHistoryFromSource(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "fact", "name", "target_uuid", "valid_at", "invalid_at"}
+	var edges []*MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.SourceUUID == paramString(params, "source_uuid") {
+			edges = append(edges, e)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return propString(edges[i].Props, "valid_at") < propString(edges[j].Props, "valid_at")
+	})
+	records := make([]*neo4j.Record, len(edges))
+	for i, e := range edges {
+		records[i] = newRecord(keys, e.UUID, e.Props["fact"], e.Props["name"], e.TargetUUID, e.Props["valid_at"], e.Props["invalid_at"])
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getEntityNode(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "name", "group_id", "created_at", "summary", "attributes", "labels", "version"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Entity") {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys,
+		n.UUID, n.Props["name"], n.Props["group_id"], n.Props["created_at"], n.Props["summary"], n.Props["attributes"], slices.Clone(n.Labels), nodeVersion(n.Props),
+	)}), nil
+}
+
+func (d *MemoryDriver) getEntityFacts(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	uuid := paramString(params, "uuid")
+	var matched []*MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if (e.SourceUUID == uuid || e.TargetUUID == uuid) && e.isActive() {
+			matched = append(matched, e)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return propString(matched[i].Props, "created_at") < propString(matched[j].Props, "created_at")
+	})
+	keys := []string{"fact", "name"}
+	var records []*neo4j.Record
+	for _, e := range matched {
+		records = append(records, newRecord(keys, e.Props["fact"], e.Props["name"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getEntityNodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "name", "group_id", "created_at", "summary", "attributes", "labels", "version"}
+	var records []*neo4j.Record
+	for _, uuid := range paramStrings(params, "uuids") {
+		n, ok := d.nodes[uuid]
+		if !ok || !n.hasLabel("Entity") {
+			continue
+		}
+		records = append(records, newRecord(keys,
+			n.UUID, n.Props["name"], n.Props["group_id"], n.Props["created_at"], n.Props["summary"], n.Props["attributes"], slices.Clone(n.Labels), nodeVersion(n.Props),
+		))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getNeighborEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	from := make(map[string]bool)
+	for _, uuid := range paramStrings(params, "uuids") {
+		from[uuid] = true
+	}
+	var matched []*MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if !e.isActive() || !d.inGroup(e.SourceUUID, params["group_id"]) || !d.inGroup(e.TargetUUID, params["group_id"]) {
+			continue
+		}
+		if from[e.SourceUUID] || from[e.TargetUUID] {
+			matched = append(matched, e)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := propString(matched[i].Props, "created_at"), propString(matched[j].Props, "created_at")
+		if a != b {
+			return a < b
+		}
+		return matched[i].UUID < matched[j].UUID
+	})
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact", "created_at", "valid_at", "attributes", "mention_count", "citation"}
+	records := make([]*neo4j.Record, len(matched))
+	for i, e := range matched {
+		records[i] = newRecord(keys, e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"], e.Props["created_at"],
+			e.Props["valid_at"], e.Props["attributes"], mentionCount(e.Props), e.Props["citation"])
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getEntityCommunities(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	entities := make(map[string]bool)
+	for _, uuid := range paramStrings(params, "uuids") {
+		entities[uuid] = true
+	}
+	members := make(map[string][]interface{})
+	for _, e := range d.edgesOfType("HAS_MEMBER") {
+		if _, ok := d.nodes[e.TargetUUID]; ok && entities[e.TargetUUID] {
+			members[e.SourceUUID] = append(members[e.SourceUUID], e.TargetUUID)
+		}
+	}
+	communities := make([]string, 0, len(members))
+	for uuid := range members {
+		if c, ok := d.nodes[uuid]; ok && c.hasLabel("Community") {
+			communities = append(communities, uuid)
+		}
+	}
+	sort.Strings(communities)
+	keys := []string{"uuid", "name", "summary", "members"}
+	records := make([]*neo4j.Record, len(communities))
+	for i, uuid := range communities {
+		c := d.nodes[uuid]
+		records[i] = newRecord(keys, c.UUID, c.Props["name"], c.Props["summary"], members[uuid])
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getGroupNodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "name", "summary"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel("Entity") {
+		if n.Props["group_id"] == params["group_id"] {
+			records = append(records, newRecord(keys, n.UUID, n.Props["name"], n.Props["summary"]))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getGroupEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact", "mention_count"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if !e.isActive() || !d.inGroup(e.SourceUUID, params["group_id"]) || !d.inGroup(e.TargetUUID, params["group_id"]) {
+			continue
+		}
+		records = append(records, newRecord(keys, e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"], mentionCount(e.Props)))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getCoMentions(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	mentioned := make(map[string][]string) // episode -> entities
+	for _, e := range d.edgesOfType("MENTIONS") {
+		if d.inGroup(e.TargetUUID, params["group_id"]) && !slices.Contains(mentioned[e.SourceUUID], e.TargetUUID) {
+			mentioned[e.SourceUUID] = append(mentioned[e.SourceUUID], e.TargetUUID)
+		}
+	}
+	type pair struct{ source, target string }
+	counts := make(map[pair]int64)
+	for _, entities := range mentioned {
+		for _, a := range entities {
+			for _, b := range entities {
+				if a < b {
+					counts[pair{a, b}]++
+				}
+			}
+		}
+	}
+	pairs := slices.Collect(maps.Keys(counts))
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].source != pairs[j].source {
+			return pairs[i].source < pairs[j].source
+		}
+		return pairs[i].target < pairs[j].target
+	})
+
+	keys := []string{"source_uuid", "target_uuid", "mention_count"}
+	var records []*neo4j.Record
+	minCount, _ := params["min_count"].(int)
+	for _, p := range pairs {
+		if counts[p] >= int64(minCount) {
+			records = append(records, newRecord(keys, p.source, p.target, counts[p]))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getGroupEdgeEpisodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact", "created_at", "episodes", "citation"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if !e.isActive() || !d.inGroup(e.SourceUUID, params["group_id"]) || !d.inGroup(e.TargetUUID, params["group_id"]) {
+			continue
+		}
+		records = append(records, newRecord(keys,
+			e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"], e.Props["created_at"], e.Props["episodes"], e.Props["citation"],
+		))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getEntityEdge(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"uuid", "source_uuid", "target_uuid", "group_id", "name", "fact", "created_at", "valid_at", "invalid_at", "episodes", "attributes", "mention_count", "citation"}
+	e, ok := d.edges[paramString(params, "uuid")]
+	if !ok || e.Type != "RELATES_TO" {
+		return newResult(keys, nil), nil
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys,
+		e.UUID, e.SourceUUID, e.TargetUUID, e.Props["group_id"], e.Props["name"], e.Props["fact"],
+		e.Props["created_at"], e.Props["valid_at"], e.Props["invalid_at"], e.Props["episodes"], e.Props["attributes"], mentionCount(e.Props), e.Props["citation"],
+	)}), nil
+}
+
+func (d *MemoryDriver) getEntityEdgeByCitation(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var match *MemoryEdge
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] || e.Props["citation"] != params["citation"] {
+			continue
+		}
+		if match == nil || propString(e.Props, "created_at") < propString(match.Props, "created_at") ||
+			(e.Props["created_at"] == match.Props["created_at"] && e.UUID < match.UUID) {
+			match = e
+		}
+	}
+	if match == nil {
+		return uuidResult(), nil
+	}
+	return uuidResult(match.UUID), nil
+}
+
+func (d *MemoryDriver) getOrphanEntities(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	referenced := make(map[string]bool)
+	for _, e := range d.edges {
+		switch {
+		case e.Type == "MENTIONS":
+			referenced[e.TargetUUID] = true
+		case e.Type == "RELATES_TO" && e.isActive():
+			referenced[e.SourceUUID] = true
+			referenced[e.TargetUUID] = true
+		}
+	}
+
+	keys := []string{"uuid", "name", "group_id", "created_at"}
+	cutoff := paramString(params, "created_before")
+	var orphans []*MemoryNode
+	for _, n := range d.nodesWithLabel("Entity") {
+		if n.Props["group_id"] != params["group_id"] || referenced[n.UUID] || propString(n.Props, "created_at") >= cutoff {
+			continue
+		}
+		orphans = append(orphans, n)
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return propString(orphans[i].Props, "name") < propString(orphans[j].Props, "name")
+	})
+
+	records := make([]*neo4j.Record, 0, len(orphans))
+	for _, n := range orphans {
+		records = append(records, newRecord(keys, n.UUID, n.Props["name"], n.Props["group_id"], n.Props["created_at"]))
+	}
+	return newResult(keys, records), nil
+}
+
+// findPaths enumerates simple 2-3 hop paths over valid RELATES_TO edges,
+// shortest first, matching FindPathsQuery.
+func (d *MemoryDriver) findPaths(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	keys := []string{"node_uuids", "node_names", "edge_uuids", "edge_sources", "edge_targets", "edge_names", "edge_facts"}
+
+	targets := make(map[string]bool)
+	for _, t := range paramStrings(params, "target_uuids") {
+		targets[t] = true
+	}
+	adj := make(map[string][]*MemoryEdge)
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.isActive() {
+			adj[e.SourceUUID] = append(adj[e.SourceUUID], e)
+			adj[e.TargetUUID] = append(adj[e.TargetUUID], e)
+		}
+	}
+
+	var paths [][]*MemoryEdge
+	nodes := []string{paramString(params, "source_uuid")}
+	var path []*MemoryEdge
+	var walk func()
+	walk = func() {
+		at := nodes[len(nodes)-1]
+		if len(path) >= 2 && targets[at] && d.inGroup(at, params["group_id"]) {
+			paths = append(paths, append([]*MemoryEdge(nil), path...))
+		}
+		if len(path) == 3 {
+			return
+		}
+		for _, e := range adj[at] {
+			next := e.TargetUUID
+			if next == at {
+				next = e.SourceUUID
+			}
+			if slices.Contains(nodes, next) {
+				continue
+			}
+			nodes, path = append(nodes, next), append(path, e)
+			walk()
+			nodes, path = nodes[:len(nodes)-1], path[:len(path)-1]
+		}
+	}
+	if n, ok := d.nodes[nodes[0]]; ok && n.hasLabel("Entity") {
+		walk()
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+
+	limit := len(paths)
+	if l, ok := params["limit"].(int); ok && l < limit {
+		limit = l
+	}
+	records := make([]*neo4j.Record, 0, limit)
+	for _, p := range paths[:limit] {
+		at := paramString(params, "source_uuid")
+		nodeUUIDs, nodeNames := []string{at}, []interface{}{d.nodes[at].Props["name"]}
+		var uuids, sources, targets []string
+		var names, facts []interface{}
+		for _, e := range p {
+			at = e.TargetUUID
+			if at == nodeUUIDs[len(nodeUUIDs)-1] {
+				at = e.SourceUUID
+			}
+			nodeUUIDs, nodeNames = append(nodeUUIDs, at), append(nodeNames, d.nodes[at].Props["name"])
+			uuids, sources, targets = append(uuids, e.UUID), append(sources, e.SourceUUID), append(targets, e.TargetUUID)
+			names, facts = append(names, e.Props["name"]), append(facts, e.Props["fact"])
+		}
+		records = append(records, newRecord(keys, nodeUUIDs, nodeNames, uuids, sources, targets, names, facts))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getRecentEpisodes(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var episodes []*MemoryNode
+	for _, n := range d.nodesWithLabel("Episodic") {
+		if n.Props["group_id"] == params["group_id"] {
+			episodes = append(episodes, n)
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return propString(episodes[i].Props, "created_at") > propString(episodes[j].Props, "created_at")
+	})
+	return episodeResult(limitSlice(episodes, params["limit"])), nil
+}
+
+func (d *MemoryDriver) getEpisodesByTopic(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	topics := paramStrings(params, "topics")
+	var episodes []*MemoryNode
+	for _, n := range d.nodesWithLabel("Episodic") {
+		if n.Props["group_id"] == params["group_id"] && hasTopic(n.Props, topics) {
+			episodes = append(episodes, n)
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return propString(episodes[i].Props, "created_at") > propString(episodes[j].Props, "created_at")
+	})
+	return episodeResult(limitSlice(episodes, params["limit"])), nil
+}
+
+func (d *MemoryDriver) getTopicEpisodeUUIDs(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	topics := paramStrings(params, "topics")
+	keys := []string{"uuid"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel("Episodic") {
+		if n.Props["group_id"] == params["group_id"] && hasTopic(n.Props, topics) {
+			records = append(records, newRecord(keys, n.UUID))
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func hasTopic(props map[string]interface{}, topics []string) bool {
+	return slices.ContainsFunc(paramStrings(props, "topics"), func(t string) bool { return slices.Contains(topics, t) })
+}
+
+func (d *MemoryDriver) setEpisodeTopics(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{"uuid"}
+	n, ok := d.nodes[paramString(params, "uuid")]
+	if !ok || !n.hasLabel("Episodic") {
+		return newResult(keys, nil), nil
+	}
+	n.Props["topics"] = paramStrings(params, "topics")
+	if err := d.persistNode(n); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, n.UUID)}), nil
+}
+
+func (d *MemoryDriver) getEpisodesByUUID(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var episodes []*MemoryNode
+	for _, uuid := range paramStrings(params, "uuids") {
+		if n, ok := d.nodes[uuid]; ok && n.hasLabel("Episodic") {
+			episodes = append(episodes, n)
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return propString(episodes[i].Props, "created_at") < propString(episodes[j].Props, "created_at")
+	})
+	return episodeResult(episodes), nil
+}
+
+func episodeResult(episodes []*MemoryNode) neo4j.EagerResult {
+	keys := []string{"uuid", "name", "group_id", "content", "content_ref", "created_at", "valid_at", "source", "source_description", "topics"}
+	var records []*neo4j.Record
+	for _, n := range episodes {
+		records = append(records, newRecord(keys,
+			n.UUID, n.Props["name"], n.Props["group_id"], n.Props["content"], n.Props["content_ref"], n.Props["created_at"],
+			n.Props["valid_at"], n.Props["source"], n.Props["source_description"], n.Props["topics"],
+		))
+	}
+	return newResult(keys, records)
+}
+
+func (d *MemoryDriver) searchEdgesByText(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	query := paramString(params, "query")
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact", "created_at", "episodes", "mention_count", "expired_at", "citation"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] || !strings.Contains(propString(e.Props, "fact"), query) || !d.matchesSearchFilter(e, params) {
+			continue
+		}
+		records = append(records, newRecord(keys, e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"], e.Props["created_at"], toList(e.Props["episodes"]), mentionCount(e.Props), e.Props["expired_at"], e.Props["citation"]))
+		if len(records) >= 20 {
+			break
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) searchEdgesByVector(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	query := toFloats(params["embedding"])
+
+	type scored struct {
+		edge  *MemoryEdge
+		score float64
+	}
+	var hits []scored
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		emb := toFloats(e.Props["fact_embedding"])
+		if e.Props["group_id"] != params["group_id"] || emb == nil || !embeddingMatches(e.Props, "fact_embedding_model", emb, params) || !d.matchesSearchFilter(e, params) {
+			continue
+		}
+		score := similarity(paramString(params, "metric"), emb, query)
+		if minScore, ok := params["min_score"].(float64); ok && score < minScore {
+			continue
+		}
+		hits = append(hits, scored{edge: e, score: score})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	if len(hits) > 20 {
+		hits = hits[:20]
+	}
+
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact", "created_at", "episodes", "mention_count", "expired_at", "citation", "score", "fact_norm"}
+	var records []*neo4j.Record
+	for _, h := range hits {
+		e := h.edge
+		emb := toFloats(e.Props["fact_embedding"])
+		records = append(records, newRecord(keys, e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"], e.Props["created_at"], toList(e.Props["episodes"]), mentionCount(e.Props), e.Props["expired_at"], e.Props["citation"], h.score, math.Sqrt(dotProduct(emb, emb))))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) searchEntitiesByNameVector(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	query := toFloats(params["embedding"])
+	minScore, _ := params["min_score"].(float64)
+
+	type scored struct {
+		node  *MemoryNode
+		score float64
+	}
+	var hits []scored
+	for _, n := range d.nodesWithLabel("Entity") {
+		emb := toFloats(n.Props["name_embedding"])
+		if n.Props["group_id"] != params["group_id"] || emb == nil || !embeddingMatches(n.Props, "name_embedding_model", emb, params) {
+			continue
+		}
+		if score := similarity(paramString(params, "metric"), emb, query); score >= minScore {
+			hits = append(hits, scored{node: n, score: score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	if limit, ok := params["limit"].(int); ok && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	keys := []string{"uuid", "name", "score"}
+	records := make([]*neo4j.Record, 0, len(hits))
+	for _, h := range hits {
+		records = append(records, newRecord(keys, h.node.UUID, h.node.Props["name"], h.score))
+	}
+	return newResult(keys, records), nil
+}
+
+// embeddingMatches reports whether a stored embedding was produced by the
+// query's $embedding_model. Untagged embeddings match on dimension alone.
+func embeddingMatches(props map[string]interface{}, tagKey string, emb []float64, params map[string]interface{}) bool {
+	want := paramString(params, "embedding_model")
+	if want == "" {
+		return true
+	}
+	if tag := propString(props, tagKey); tag != "" {
+		return tag == want
+	}
+	return len(emb) == len(toFloats(params["embedding"]))
+}
+
+func (d *MemoryDriver) getStaleEntityEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.staleNameEmbeddings("Entity", params)
+}
+
+func (d *MemoryDriver) getStaleCommunityEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	return d.staleNameEmbeddings("Community", params)
+}
+
+func (d *MemoryDriver) staleNameEmbeddings(label string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	prefix := paramString(params, "model_prefix")
+	keys := []string{"uuid", "name"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel(label) {
+		if n.Props["group_id"] != params["group_id"] || strings.HasPrefix(propString(n.Props, "name_embedding_model"), prefix) {
+			continue
+		}
+		records = append(records, newRecord(keys, n.UUID, n.Props["name"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getStaleFactEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	prefix := paramString(params, "model_prefix")
+	keys := []string{"uuid", "fact"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] || strings.HasPrefix(propString(e.Props, "fact_embedding_model"), prefix) {
+			continue
+		}
+		records = append(records, newRecord(keys, e.UUID, e.Props["fact"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) stageNameEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var staged []string
+	for _, emb := range paramMaps(params, "embeddings") {
+		n, ok := d.nodes[paramString(emb, "uuid")]
+		if !ok || !(n.hasLabel("Entity") || n.hasLabel("Community")) {
+			continue
+		}
+		n.Props["name_embedding_next"] = emb["embedding"]
+		n.Props["name_embedding_next_model"] = emb["embedding_model"]
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		staged = append(staged, n.UUID)
+	}
+	return uuidResult(staged...), nil
+}
+
+func (d *MemoryDriver) stageFactEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var staged []string
+	for _, emb := range paramMaps(params, "embeddings") {
+		e, ok := d.edges[paramString(emb, "uuid")]
+		if !ok || e.Type != "RELATES_TO" {
+			continue
+		}
+		e.Props["fact_embedding_next"] = emb["embedding"]
+		e.Props["fact_embedding_next_model"] = emb["embedding_model"]
+		if err := d.persistEdge(e); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		staged = append(staged, e.UUID)
+	}
+	return uuidResult(staged...), nil
+}
+
+// promoteEmbeddings swaps a group's staged vectors in under one lock, like the single Cypher statement.
+func (d *MemoryDriver) promoteEmbeddings(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	nodes, facts := 0, 0
+	for _, n := range d.nodes {
+		if n.Props["group_id"] != params["group_id"] || n.Props["name_embedding_next"] == nil || !(n.hasLabel("Entity") || n.hasLabel("Community")) {
+			continue
+		}
+		n.Props["name_embedding"] = n.Props["name_embedding_next"]
+		n.Props["name_embedding_model"] = n.Props["name_embedding_next_model"]
+		delete(n.Props, "name_embedding_next")
+		delete(n.Props, "name_embedding_next_model")
+		if err := d.persistNode(n); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		nodes++
+	}
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] || e.Props["fact_embedding_next"] == nil {
+			continue
+		}
+		e.Props["fact_embedding"] = e.Props["fact_embedding_next"]
+		e.Props["fact_embedding_model"] = e.Props["fact_embedding_next_model"]
+		delete(e.Props, "fact_embedding_next")
+		delete(e.Props, "fact_embedding_next_model")
+		if err := d.persistEdge(e); err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		facts++
+	}
+	keys := []string{"nodes", "facts"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys, int64(nodes), int64(facts))}), nil
+}
+
+// matchesSearchFilter applies the optional filter predicates of the search queries.
+func (d *MemoryDriver) matchesSearchFilter(e *MemoryEdge, params map[string]interface{}) bool {
+	if types := paramStrings(params, "relation_types"); params["relation_types"] != nil && !slices.Contains(types, propString(e.Props, "name")) {
+		return false
+	}
+	source, target := d.nodes[e.SourceUUID], d.nodes[e.TargetUUID]
+	if source == nil || target == nil {
+		return false
+	}
+	if params["entity_labels"] != nil {
+		labels := paramStrings(params, "entity_labels")
+		if !slices.ContainsFunc(append(slices.Clone(source.Labels), target.Labels...), func(l string) bool { return slices.Contains(labels, l) }) {
+			return false
+		}
+	}
+	for _, f := range paramStrings(params, "attribute_fragments") {
+		if !strings.Contains(propString(source.Props, "attributes"), f) && !strings.Contains(propString(target.Props, "attributes"), f) {
+			return false
+		}
+	}
+	for _, r := range []struct{ prop, from, to string }{{"valid_at", "valid_from", "valid_to"}, {"created_at", "created_from", "created_to"}} {
+		v := propString(e.Props, r.prop)
+		if from := paramString(params, r.from); from != "" && v < from {
+			return false
+		}
+		if to := paramString(params, r.to); to != "" && v >= to {
+			return false
+		}
+	}
+	if at := paramString(params, "live_at"); at != "" {
+		if expired := propString(e.Props, "expired_at"); expired != "" && expired <= at {
+			return false
+		}
+	}
+	if params["topic_episodes"] != nil {
+		topicEpisodes := paramStrings(params, "topic_episodes")
+		if !slices.ContainsFunc(paramStrings(e.Props, "episodes"), func(ep string) bool { return slices.Contains(topicEpisodes, ep) }) {
+			return false
+		}
+	}
+	for _, p := range []string{"sentiment_fragments", "emotion_fragments"} {
+		if params[p] != nil && !slices.ContainsFunc(paramStrings(params, p), func(f string) bool {
+			return strings.Contains(propString(e.Props, "attributes"), f)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *MemoryDriver) searchEdges(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	query := paramString(params, "query")
+	keys := []string{"uuid", "source_uuid", "target_uuid", "name", "fact"}
+	var records []*neo4j.Record
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != params["group_id"] || !strings.Contains(propString(e.Props, "fact"), query) {
+			continue
+		}
+		records = append(records, newRecord(keys, e.UUID, e.SourceUUID, e.TargetUUID, e.Props["name"], e.Props["fact"]))
+		if len(records) >= 10 {
+			break
+		}
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) getGroupStats(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	groupID := params["group_id"]
+
+	counts := make(map[string]int64)
+	latest := make(map[string]string)
+	track := func(key string, props map[string]interface{}) {
+		if at := propString(props, "created_at"); at > latest[key] {
+			latest[key] = at
+		}
+	}
+	for _, n := range d.nodes {
+		if n.Props["group_id"] != groupID {
+			continue
+		}
+		for _, label := range []string{"Entity", "Episodic", "Saga", "Community"} {
+			if n.hasLabel(label) {
+				counts[label]++
+				track(label, n.Props)
+			}
+		}
+	}
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if e.Props["group_id"] != groupID {
+			continue
+		}
+		counts["edges"]++
+		if e.isActive() {
+			counts["valid_edges"]++
+		}
+		track("edges", e.Props)
+	}
+
+	orNil := func(s string) interface{} {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	keys := []string{"entities", "edges", "valid_edges", "episodes", "sagas", "communities", "last_entity_at", "last_edge_at", "last_episode_at"}
+	return newResult(keys, []*neo4j.Record{newRecord(keys,
+		counts["Entity"], counts["edges"], counts["valid_edges"], counts["Episodic"], counts["Saga"], counts["Community"],
+		orNil(latest["Entity"]), orNil(latest["edges"]), orNil(latest["Episodic"]),
+	)}), nil
+}
+
+func (d *MemoryDriver) getEntitySalience(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	relations := make(map[string][]interface{})
+	lastFact := make(map[string]string)
+	for _, e := range d.edgesOfType("RELATES_TO") {
+		if !e.isActive() {
+			continue
+		}
+		for _, n := range []string{e.SourceUUID, e.TargetUUID} {
+			relations[n] = append(relations[n], e.Props["name"])
+			if at := propString(e.Props, "created_at"); at > lastFact[n] {
+				lastFact[n] = at
+			}
+		}
+	}
+	mentions := make(map[string]int64)
+	lastMentioned := make(map[string]string)
+	for _, e := range d.edgesOfType("MENTIONS") {
+		ep, ok := d.nodes[e.SourceUUID]
+		if !ok {
+			continue
+		}
+		mentions[e.TargetUUID]++
+		if at := propString(ep.Props, "created_at"); at > lastMentioned[e.TargetUUID] {
+			lastMentioned[e.TargetUUID] = at
+		}
+	}
+
+	orNil := func(s string) interface{} {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	keys := []string{"uuid", "name", "summary", "labels", "relations", "mentions", "created_at", "last_fact_at", "last_mentioned_at"}
+	var records []*neo4j.Record
+	for _, n := range d.nodesWithLabel("Entity") {
+		if n.Props["group_id"] != params["group_id"] {
+			continue
+		}
+		rels := relations[n.UUID]
+		if rels == nil {
+			rels = []interface{}{}
+		}
+		records = append(records, newRecord(keys, n.UUID, n.Props["name"], n.Props["summary"], slices.Clone(n.Labels),
+			rels, mentions[n.UUID], n.Props["created_at"], orNil(lastFact[n.UUID]), orNil(lastMentioned[n.UUID])))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) listGroups(params map[string]interface{}) (neo4j.EagerResult, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	type groupAgg struct {
+		episodes int64
+		last     string
+	}
+	aggs := make(map[string]*groupAgg)
+	groups := d.nodesWithLabel("Group")
+	for _, n := range groups {
+		aggs[propString(n.Props, "group_id")] = &groupAgg{}
+	}
+	for _, n := range d.nodesWithLabel("Episodic") {
+		a, ok := aggs[propString(n.Props, "group_id")]
+		if !ok {
+			continue
+		}
+		a.episodes++
+		if at := propString(n.Props, "created_at"); at > a.last {
+			a.last = at
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return propString(groups[i].Props, "group_id") < propString(groups[j].Props, "group_id")
+	})
+
+	keys := []string{"group_id", "episodes", "created_at", "last_episode_at", "name", "metadata"}
+	var records []*neo4j.Record
+	for _, n := range groups {
+		a := aggs[propString(n.Props, "group_id")]
+		var last interface{}
+		if a.last != "" {
+			last = a.last
+		}
+		records = append(records, newRecord(keys,
+			n.Props["group_id"], a.episodes, n.Props["created_at"], last, n.Props["name"], n.Props["metadata"]))
+	}
+	return newResult(keys, records), nil
+}
+
+func (d *MemoryDriver) inGroup(nodeUUID string, groupID interface{}) bool {
+	n, ok := d.nodes[nodeUUID]
+	return ok && n.hasLabel("Entity") && n.Props["group_id"] == groupID
+}
+
+// ---------------- Value Helpers ----------------
+
+func newRecord(keys []string, values ...interface{}) *neo4j.Record {
+	return &neo4j.Record{Keys: keys, Values: values}
+}
+
+func newResult(keys []string, records []*neo4j.Record) neo4j.EagerResult {
+	return neo4j.EagerResult{Keys: keys, Records: records}
+}
+
+func uuidResult(uuids ...string) neo4j.EagerResult {
+	keys := []string{"uuid"}
+	var records []*neo4j.Record
+	for _, u := range uuids {
+		records = append(records, newRecord(keys, u))
+	}
+	return newResult(keys, records)
+}
+
+func paramString(params map[string]interface{}, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
+
+func paramStrings(params map[string]interface{}, key string) []string {
+	switch v := params[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func paramMaps(params map[string]interface{}, key string) []map[string]interface{} {
+	switch v := params[key].(type) {
+	case []map[string]interface{}:
+		return v
+	case []interface{}:
+		out := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// propString renders a property for ordering comparisons (timestamps are RFC3339 strings or time.Time).
+// mentionCount is how many episodes stated a fact; facts saved before mention
+// counts count as mentioned once.
+func mentionCount(props map[string]interface{}) int64 {
+	switch v := props["mention_count"].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64: // Decoded from JSON
+		return int64(v)
+	}
+	return 1
+}
+
+// nodeVersion is the version of an entity, 0 before its first versioned write.
+func nodeVersion(props map[string]interface{}) int64 {
+	return propInt(props, "version")
+}
+
+func propInt(props map[string]interface{}, key string) int64 {
+	switch v := props[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64: // Decoded from JSON
+		return int64(v)
+	}
+	return 0
+}
+
+func propString(props map[string]interface{}, key string) string {
+	switch v := props[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func limitSlice[T any](items []T, limit interface{}) []T {
+	var n int
+	switch v := limit.(type) {
+	case int:
+		n = v
+	case int64:
+		n = int(v)
+	default:
+		return items
+	}
+	if n >= 0 && len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+// toList converts stored slices into the []interface{} shape Bolt drivers return for lists.
+func toList(v interface{}) []interface{} {
+	switch l := v.(type) {
+	case []interface{}:
+		return l
+	case []string:
+		out := make([]interface{}, len(l))
+		for i, s := range l {
+			out[i] = s
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func toFloats(v interface{}) []float64 {
+	switch l := v.(type) {
+	case []float32:
+		out := make([]float64, len(l))
+		for i, f := range l {
+			out[i] = float64(f)
+		}
+		return out
+	case []float64:
+		return l
+	case []interface{}:
+		out := make([]float64, 0, len(l))
+		for _, x := range l {
+			switch f := x.(type) {
+			case float64:
+				out = append(out, f)
+			case float32:
+				out = append(out, float64(f))
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// similarity scores a against b with the metric of the vector search queries.
+func similarity(metric string, a, b []float64) float64 {
+	switch metric {
+	case "dot":
+		return dotProduct(a, b)
+	case "euclidean":
+		return 1 / (1 + euclideanDistance(a, b))
+	default:
+		return cosineSimilarity(a, b)
+	}
+}
+
+func dotProduct(a, b []float64) float64 {
+	var dot float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+func euclideanDistance(a, b []float64) float64 {
+	var sum float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 if either is empty or zero.
+func cosineSimilarity(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var dot, na, nb float64
+	for i := 0; i < n; i++ {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,90 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDriver_EntityEdgeLifecycle(t *testing.T) {
+	d := NewMemoryDriver()
+	ctx := context.Background()
+
+	for _, n := range []struct{ uuid, name string }{{"a", "Alice"}, {"b", "Bob"}} {
+		_, err := d.ExecuteQuery(ctx, SaveEntityNodeQuery, map[string]interface{}{
+			"uuid": n.uuid, "name": n.name, "group_id": "g1", "summary": "", "labels": []string{"Entity"},
+		})
+		require.NoError(t, err)
+	}
+
+	res, err := d.ExecuteQuery(ctx, SaveEntityEdgeQuery, map[string]interface{}{
+		"uuid": "e1", "source_uuid": "a", "target_uuid": "b", "name": "KNOWS",
+		"fact": "Alice knows Bob", "group_id": "g1", "invalid_at": "",
+		"episodes": []string{"ep1"}, "fact_embedding": []float32{1, 0},
+	})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 1)
+
+	// Edge to a missing node is not created, matching MATCH semantics
+	res, err = d.ExecuteQuery(ctx, SaveEntityEdgeQuery, map[string]interface{}{
+		"uuid": "e2", "source_uuid": "a", "target_uuid": "missing",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.Records)
+
+	res, err = d.ExecuteReadQuery(ctx, GetGroupNodesQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 2)
+
+	res, err = d.ExecuteReadQuery(ctx, SearchEdgesByTextQuery, map[string]interface{}{"group_id": "g1", "query": "knows"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	episodes, _ := res.Records[0].Get("episodes")
+	assert.Equal(t, []interface{}{"ep1"}, episodes)
+
+	res, err = d.ExecuteReadQuery(ctx, SearchEdgesByVectorQuery, map[string]interface{}{"group_id": "g1", "embedding": []float32{1, 0}})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	score, _ := res.Records[0].Get("score")
+	assert.InDelta(t, 1.0, score, 1e-9)
+
+	_, err = d.ExecuteQuery(ctx, InvalidateEdgeQuery, map[string]interface{}{"uuid": "e1", "invalid_at": "2024-01-01T00:00:00Z"})
+	require.NoError(t, err)
+
+	res, err = d.ExecuteReadQuery(ctx, GetGroupEdgesQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Records)
+}
+
+func TestMemoryDriver_SagaOrdering(t *testing.T) {
+	d := NewMemoryDriver()
+	ctx := context.Background()
+
+	_, _ = d.ExecuteQuery(ctx, SaveSagaNodeQuery, map[string]interface{}{"uuid": "s1", "name": "chat", "group_id": "g1"})
+	for _, ep := range []struct{ uuid, at string }{{"ep1", "2024-01-01T00:00:00Z"}, {"ep2", "2024-01-02T00:00:00Z"}, {"ep3", "2024-01-03T00:00:00Z"}} {
+		_, _ = d.ExecuteQuery(ctx, SaveEpisodicNodeQuery, map[string]interface{}{"uuid": ep.uuid, "group_id": "g1", "valid_at": ep.at, "created_at": ep.at})
+		_, _ = d.ExecuteQuery(ctx, SaveHasEpisodeEdgeQuery, map[string]interface{}{"uuid": "h-" + ep.uuid, "source_uuid": "s1", "target_uuid": ep.uuid})
+	}
+
+	res, err := d.ExecuteQuery(ctx, GetSagaByNameQuery, map[string]interface{}{"name": "chat", "group_id": "g1"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+
+	res, err = d.ExecuteQuery(ctx, GetPreviousEpisodeInSagaQuery, map[string]interface{}{"saga_uuid": "s1", "current_episode_uuid": "ep3"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	prev, _ := res.Records[0].Get("uuid")
+	assert.Equal(t, "ep2", prev)
+
+	res, err = d.ExecuteReadQuery(ctx, GetRecentEpisodesQuery, map[string]interface{}{"group_id": "g1", "limit": 2})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 2)
+}
+
+func TestMemoryDriver_UnsupportedQuery(t *testing.T) {
+	d := NewMemoryDriver()
+	_, err := d.ExecuteQuery(context.Background(), "MATCH (n) RETURN n", nil)
+	assert.Error(t, err)
+}
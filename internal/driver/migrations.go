@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// Graph data migrations. Each migration is a numbered list of Cypher
+// statements that rewrites existing nodes and edges when their properties
+// change shape. The applied version is kept on a single SchemaVersion node;
+// pending migrations run in order on startup. Like golang-migrate, a migration
+// that fails leaves the version marked dirty and later runs refuse to start
+// until the data has been repaired and the dirty flag cleared by hand.
+//
+// Statements must be idempotent, and each needs a MemoryDriver handler.
+
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+// Migrations is the ordered migration history of the graph schema.
+var Migrations = []Migration{
+	{Version: 1, Name: "backfill_entity_attributes", Statements: []string{BackfillEntityAttributesQuery}},
+	{Version: 2, Name: "backfill_edge_episodes", Statements: []string{BackfillEdgeEpisodesQuery}},
+	{Version: 3, Name: "backfill_edge_citations", Statements: []string{BackfillEdgeCitationsQuery}},
+	{Version: 4, Name: "backfill_groups", Statements: []string{BackfillGroupsQuery}},
+	{Version: 5, Name: "backfill_content_refs", Statements: []string{BackfillContentRefsQuery}},
+}
+
+// SchemaVersion returns the applied migration version (0 for a new graph) and whether it is dirty.
+func SchemaVersion(ctx context.Context, d GraphDriver) (int, bool, error) {
+	res, err := d.ExecuteQuery(ctx, GetSchemaVersionQuery, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return 0, false, nil
+	}
+	var state struct {
+		Version int  `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	if err := ScanRecord(res.Records[0], &state); err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return state.Version, state.Dirty, nil
+}
+
+// Migrate applies every migration newer than the stored schema version and
+// returns the versions it applied. A driver spread over several backends has
+// each of them migrated on its own.
+func Migrate(ctx context.Context, d GraphDriver, migrations []Migration) ([]int, error) {
+	if multi, ok := d.(interface{ Backends() []GraphDriver }); ok {
+		var applied []int
+		for _, b := range multi.Backends() {
+			versions, err := Migrate(ctx, b, migrations)
+			for _, v := range versions {
+				if !slices.Contains(applied, v) {
+					applied = append(applied, v)
+				}
+			}
+			if err != nil {
+				return applied, err
+			}
+		}
+		slices.Sort(applied)
+		return applied, nil
+	}
+	current, dirty, err := SchemaVersion(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("schema version %d is dirty: a previous migration failed and needs manual repair", current)
+	}
+
+	var applied []int
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := setSchemaVersion(ctx, d, m, true); err != nil {
+			return applied, err
+		}
+		for i, stmt := range m.Statements {
+			if _, err := d.ExecuteQuery(ctx, stmt, nil); err != nil {
+				return applied, fmt.Errorf("migration %d (%s) failed at statement %d: %w", m.Version, m.Name, i+1, err)
+			}
+		}
+		if err := setSchemaVersion(ctx, d, m, false); err != nil {
+			return applied, err
+		}
+		log.Printf("Applied graph migration %d (%s)", m.Version, m.Name)
+		current = m.Version
+		applied = append(applied, m.Version)
+	}
+	return applied, nil
+}
+
+func setSchemaVersion(ctx context.Context, d GraphDriver, m Migration, dirty bool) error {
+	_, err := d.ExecuteQuery(ctx, SetSchemaVersionQuery, map[string]interface{}{
+		"version":    m.Version,
+		"name":       m.Name,
+		"dirty":      dirty,
+		"applied_at": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", m.Version, err)
+	}
+	return nil
+}
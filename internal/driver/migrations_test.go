@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_BackfillsAndRecordsVersion(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryDriver()
+	for _, uuid := range []string{"a", "b"} {
+		_, err := d.ExecuteQuery(ctx, SaveEntityNodeQuery, map[string]interface{}{"uuid": uuid, "name": uuid, "group_id": "g1"})
+		require.NoError(t, err)
+	}
+	_, err := d.ExecuteQuery(ctx, SaveEntityEdgeQuery, map[string]interface{}{
+		"uuid": "e1", "source_uuid": "a", "target_uuid": "b", "name": "KNOWS", "fact": "a knows b", "group_id": "g1",
+	})
+	require.NoError(t, err)
+	for _, ep := range []struct{ uuid, at string }{{"ep2", "2024-01-02T00:00:00Z"}, {"ep1", "2024-01-01T00:00:00Z"}} {
+		_, err = d.ExecuteQuery(ctx, SaveEpisodicNodeQuery, map[string]interface{}{"uuid": ep.uuid, "group_id": "g1", "valid_at": ep.at, "created_at": ep.at})
+		require.NoError(t, err)
+	}
+	d.nodes["ep2"].Props["content"] = "blob:v1:episodes/g1/ep2"
+
+	version, dirty, err := SchemaVersion(ctx, d)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+	assert.False(t, dirty)
+
+	applied, err := Migrate(ctx, d, Migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, applied)
+	assert.Equal(t, "{}", d.nodes["a"].Props["attributes"])
+	assert.Equal(t, "episodes/g1/ep2", d.nodes["ep2"].Props["content_ref"])
+	assert.Equal(t, "", d.nodes["ep2"].Props["content"])
+	assert.Equal(t, []string{}, d.edges["e1"].Props["episodes"])
+	assert.Equal(t, "F-e1", d.edges["e1"].Props["citation"])
+
+	res, err := d.ExecuteReadQuery(ctx, GetGroupQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	name, _ := res.Records[0].Get("name")
+	createdAt, _ := res.Records[0].Get("created_at")
+	settings, _ := res.Records[0].Get("settings")
+	assert.Equal(t, "g1", name)
+	assert.Equal(t, "2024-01-01T00:00:00Z", createdAt)
+	assert.Equal(t, "{}", settings)
+
+	version, _, err = SchemaVersion(ctx, d)
+	require.NoError(t, err)
+	assert.Equal(t, 5, version)
+
+	// Already at the latest version
+	applied, err = Migrate(ctx, d, Migrations)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+// failingStatementDriver fails one query and passes the rest to a memory driver.
+type failingStatementDriver struct {
+	*MemoryDriver
+	fail string
+}
+
+func (d failingStatementDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if query == d.fail {
+		return neo4j.EagerResult{}, errors.New("syntax error")
+	}
+	return d.MemoryDriver.ExecuteQuery(ctx, query, params)
+}
+
+func TestMigrate_FailureLeavesVersionDirty(t *testing.T) {
+	ctx := context.Background()
+	d := failingStatementDriver{MemoryDriver: NewMemoryDriver(), fail: BackfillEdgeEpisodesQuery}
+
+	applied, err := Migrate(ctx, d, Migrations)
+	require.Error(t, err)
+	assert.Equal(t, []int{1}, applied)
+
+	version, dirty, err := SchemaVersion(ctx, d)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+	assert.True(t, dirty)
+
+	// Later runs refuse to continue past a dirty version
+	d.fail = ""
+	_, err = Migrate(ctx, d, Migrations)
+	assert.ErrorContains(t, err, "dirty")
+}
+
+func TestMigrate_VersionSurvivesSQLiteReopen(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/graph.db"
+	d, err := NewSQLiteDriver(path)
+	require.NoError(t, err)
+	_, err = Migrate(ctx, d, Migrations)
+	require.NoError(t, err)
+	require.NoError(t, d.Close(ctx))
+
+	d, err = NewSQLiteDriver(path)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+	applied, err := Migrate(ctx, d, Migrations)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
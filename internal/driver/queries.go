@@ -8,10 +8,35 @@ const (
 			n.created_at = $created_at,
 			n.summary = $summary,
 			n.name_embedding = $name_embedding,
-			n.attributes = $attributes
+			n.name_embedding_model = $name_embedding_model,
+			n.attributes = $attributes,
+			n.version = coalesce(n.version, 0) + 1
 		WITH n
-		FOREACH (label IN $labels | SET n:label)
-		RETURN n.uuid AS uuid
+		` + labelsClause + ` // $labels
+		RETURN n.uuid AS uuid, n.version AS version
+	`
+
+	// Every write of an entity bumps its version; UpdateEntityNodeQuery with a
+	// $version only applies while the entity is still at that version.
+	UpdateEntityNodeQuery = `
+		MATCH (n:Entity {uuid: $uuid})
+		WHERE $version IS NULL OR coalesce(n.version, 0) = $version
+		SET n.name = $name,
+			n.summary = $summary,
+			n.name_embedding = coalesce($name_embedding, n.name_embedding),
+			n.name_embedding_model = coalesce($name_embedding_model, n.name_embedding_model),
+			n.attributes = $attributes,
+			n.version = coalesce(n.version, 0) + 1
+		RETURN n.uuid AS uuid, n.version AS version
+	`
+
+	// SetEntitySummaryQuery writes only the summary, leaving fields other
+	// writers may have changed since the summary was computed alone.
+	SetEntitySummaryQuery = `
+		MATCH (n:Entity {uuid: $uuid})
+		SET n.summary = $summary,
+			n.version = coalesce(n.version, 0) + 1
+		RETURN n.uuid AS uuid, n.version AS version
 	`
 
 	SaveEpisodicNodeQuery = `
@@ -21,6 +46,7 @@ const (
 			n.created_at = $created_at,
 			n.valid_at = $valid_at,
 			n.content = $content,
+			n.content_ref = $content_ref,
 			n.source = $source,
 			n.source_description = $source_description,
 			n.entity_edges = $entity_edges
@@ -33,7 +59,8 @@ const (
 			n.group_id = $group_id,
 			n.created_at = $created_at,
 			n.summary = $summary,
-			n.name_embedding = $name_embedding
+			n.name_embedding = $name_embedding,
+			n.name_embedding_model = $name_embedding_model
 		RETURN n.uuid AS uuid
 	`
 	
@@ -50,7 +77,9 @@ const (
 			e.invalid_at = $invalid_at,
 			e.episodes = $episodes,
 			e.fact_embedding = $fact_embedding,
-			e.attributes = $attributes
+			e.fact_embedding_model = $fact_embedding_model,
+			e.attributes = $attributes,
+			e.citation = $citation
 		RETURN e.uuid AS uuid
 	`
 
@@ -76,11 +105,20 @@ const (
 		RETURN s.uuid as uuid, s.name as name, s.group_id as group_id, s.created_at as created_at
 	`
 
+	GetSagaQuery = `
+		MATCH (s:Saga {uuid: $uuid})
+		RETURN s.uuid as uuid, s.name as name, s.group_id as group_id, s.created_at as created_at
+	`
+
+	// Episodes saved within the same second tie on their timestamps; the one
+	// no episode follows yet is the latest.
 	GetPreviousEpisodeInSagaQuery = `
 		MATCH (s:Saga {uuid: $saga_uuid})-[:HAS_EPISODE]->(e:Episodic)
 		WHERE e.uuid <> $current_episode_uuid
+		OPTIONAL MATCH (e)-[n:NEXT_EPISODE]->(:Episodic)
+		WITH e, count(n) AS next
 		RETURN e.uuid AS uuid
-		ORDER BY e.valid_at DESC, e.created_at DESC
+		ORDER BY e.valid_at DESC, e.created_at DESC, next
 		LIMIT 1
 	`
 	
@@ -119,6 +157,27 @@ const (
 		WHERE (e.invalid_at IS NULL OR e.invalid_at = "")
 		RETURN e.uuid AS uuid, e.fact AS fact, e.name AS name, target.uuid AS target_uuid
 	`
+
+	// GetEdgeHistoryFromSourceQuery is GetActiveEdgesFromSourceQuery including
+	// invalidated facts.
+	GetEdgeHistoryFromSourceQuery = `
+		MATCH (source:Entity {uuid: $source_uuid})-[e:RELATES_TO]->(target:Entity)
+		RETURN e.uuid AS uuid, e.fact AS fact, e.name AS name, target.uuid AS target_uuid,
+			e.valid_at AS valid_at, e.invalid_at AS invalid_at
+		ORDER BY e.valid_at
+	`
+
+	SetEdgeExpiryQuery = `
+		MATCH ()-[e:RELATES_TO {uuid: $uuid}]->()
+		SET e.expired_at = $expired_at
+		RETURN e.uuid AS uuid
+	`
+
+	ReinstateEdgeQuery = `
+		MATCH ()-[e:RELATES_TO {uuid: $uuid}]->()
+		SET e.invalid_at = ""
+		RETURN e.uuid AS uuid
+	`
 	
 	GetGroupNodesQuery = `
 		MATCH (n:Entity {group_id: $group_id})
@@ -128,9 +187,43 @@ const (
 	GetGroupEdgesQuery = `
 		MATCH (n:Entity {group_id: $group_id})-[e:RELATES_TO]->(m:Entity {group_id: $group_id})
 		WHERE (e.invalid_at IS NULL OR e.invalid_at = "")
-		RETURN e.uuid AS uuid, n.uuid AS source_uuid, m.uuid AS target_uuid, e.fact as fact
+		RETURN e.uuid AS uuid, n.uuid AS source_uuid, m.uuid AS target_uuid, e.name AS name, e.fact as fact,
+		       coalesce(e.mention_count, 1) AS mention_count
 	`
 	
+	// Pairs of the group's entities mentioned by the same episodes, with the
+	// number of those episodes; community detection links them.
+	GetCoMentionsQuery = `
+		MATCH (n:Entity {group_id: $group_id})<-[:MENTIONS]-(ep:Episodic)-[:MENTIONS]->(m:Entity {group_id: $group_id})
+		WHERE n.uuid < m.uuid
+		WITH n, m, count(DISTINCT ep) AS mention_count
+		WHERE mention_count >= $min_count
+		RETURN n.uuid AS source_uuid, m.uuid AS target_uuid, mention_count
+	`
+
+	// The group's communities with the UUIDs of their members.
+	GetGroupCommunitiesQuery = `
+		MATCH (c:Community {group_id: $group_id})
+		OPTIONAL MATCH (c)-[:HAS_MEMBER]->(e:Entity)
+		RETURN c.uuid AS uuid, c.name AS name, c.summary AS summary, c.created_at AS created_at,
+		       collect(e.uuid) AS members
+		ORDER BY c.created_at, c.uuid
+	`
+
+	DeleteCommunityMembersQuery = `
+		MATCH (c:Community {uuid: $uuid})-[r:HAS_MEMBER]->(:Entity)
+		WITH r, r.uuid AS uuid
+		DELETE r
+		RETURN uuid
+	`
+
+	DeleteCommunityQuery = `
+		MATCH (c:Community {uuid: $uuid})
+		WITH c, c.uuid AS uuid
+		DETACH DELETE c
+		RETURN uuid
+	`
+
 	SaveCommunityEdgeQuery = `
 		MATCH (c:Community {uuid: $source_uuid})
 		MATCH (e:Entity {uuid: $target_uuid})
@@ -142,8 +235,768 @@ const (
 	GetRecentEpisodesQuery = `
 		MATCH (e:Episodic)
 		WHERE e.group_id = $group_id
-		RETURN e.uuid AS uuid, e.content AS content, e.created_at AS created_at
+		RETURN e.uuid AS uuid, e.name AS name, e.group_id AS group_id, e.content AS content,
+		       e.content_ref AS content_ref, e.created_at AS created_at, e.valid_at AS valid_at,
+		       e.source AS source, e.source_description AS source_description, e.topics AS topics
+		ORDER BY e.created_at DESC
+		LIMIT $limit
+	`
+
+	GetEpisodesByTopicQuery = `
+		MATCH (e:Episodic)
+		WHERE e.group_id = $group_id AND any(t IN coalesce(e.topics, []) WHERE t IN $topics)
+		RETURN e.uuid AS uuid, e.name AS name, e.group_id AS group_id, e.content AS content,
+		       e.content_ref AS content_ref, e.created_at AS created_at, e.valid_at AS valid_at,
+		       e.source AS source, e.source_description AS source_description, e.topics AS topics
 		ORDER BY e.created_at DESC
 		LIMIT $limit
 	`
+
+	// UUIDs only, for the topic filter of fact search
+	GetTopicEpisodeUUIDsQuery = `
+		MATCH (e:Episodic {group_id: $group_id})
+		WHERE any(t IN coalesce(e.topics, []) WHERE t IN $topics)
+		RETURN e.uuid AS uuid
+	`
+
+	SetEpisodeTopicsQuery = `
+		MATCH (e:Episodic {uuid: $uuid})
+		SET e.topics = $topics
+		RETURN e.uuid AS uuid
+	`
+
+	GetEpisodesByUUIDQuery = `
+		MATCH (e:Episodic)
+		WHERE e.uuid IN $uuids
+		RETURN e.uuid AS uuid, e.name AS name, e.group_id AS group_id, e.content AS content,
+		       e.content_ref AS content_ref, e.created_at AS created_at, e.valid_at AS valid_at,
+		       e.source AS source, e.source_description AS source_description, e.topics AS topics
+		ORDER BY e.created_at
+	`
+
+	// Search filters (SearchFilter) are optional parameters: a null parameter
+	// disables its predicate, so the query text never depends on user input.
+	SearchEdgesByTextQuery = `
+		MATCH (n:Entity)-[e:RELATES_TO]->(m:Entity)
+		WHERE e.group_id = $group_id AND e.fact CONTAINS $query
+		  AND ($relation_types IS NULL OR e.name IN $relation_types)
+		  AND ($entity_labels IS NULL OR any(l IN labels(n) + labels(m) WHERE l IN $entity_labels))
+		  AND ($attribute_fragments IS NULL OR
+		       all(f IN $attribute_fragments WHERE n.attributes CONTAINS f OR m.attributes CONTAINS f))
+		  AND ($valid_from IS NULL OR e.valid_at >= $valid_from)
+		  AND ($valid_to IS NULL OR e.valid_at < $valid_to)
+		  AND ($created_from IS NULL OR e.created_at >= $created_from)
+		  AND ($created_to IS NULL OR e.created_at < $created_to)
+		  AND ($live_at IS NULL OR coalesce(e.expired_at, "") = "" OR e.expired_at > $live_at)
+		  AND ($topic_episodes IS NULL OR any(ep IN coalesce(e.episodes, []) WHERE ep IN $topic_episodes))
+		  AND ($sentiment_fragments IS NULL OR any(f IN $sentiment_fragments WHERE e.attributes CONTAINS f))
+		  AND ($emotion_fragments IS NULL OR any(f IN $emotion_fragments WHERE e.attributes CONTAINS f))
+		RETURN e.uuid AS uuid, 
+		       n.uuid AS source_uuid, 
+		       m.uuid AS target_uuid, 
+		       e.name AS name,
+		       e.fact AS fact, 
+		       e.created_at AS created_at,
+		       e.episodes AS episodes,
+		       coalesce(e.mention_count, 1) AS mention_count,
+		       e.expired_at AS expired_at,
+		       e.citation AS citation
+		LIMIT 20
+	`
+
+	// Vector scores use $metric: "cosine" (default), "dot" (dot product) or
+	// "euclidean" (1 / (1 + distance)); higher is always more similar.
+	SearchEdgesByVectorQuery = `
+		MATCH (n:Entity)-[e:RELATES_TO]->(m:Entity)
+		WHERE e.group_id = $group_id AND e.fact_embedding IS NOT NULL
+		  AND ($relation_types IS NULL OR e.name IN $relation_types)
+		  AND ($entity_labels IS NULL OR any(l IN labels(n) + labels(m) WHERE l IN $entity_labels))
+		  AND ($attribute_fragments IS NULL OR
+		       all(f IN $attribute_fragments WHERE n.attributes CONTAINS f OR m.attributes CONTAINS f))
+		  AND ($valid_from IS NULL OR e.valid_at >= $valid_from)
+		  AND ($valid_to IS NULL OR e.valid_at < $valid_to)
+		  AND ($created_from IS NULL OR e.created_at >= $created_from)
+		  AND ($created_to IS NULL OR e.created_at < $created_to)
+		  AND ($live_at IS NULL OR coalesce(e.expired_at, "") = "" OR e.expired_at > $live_at)
+		  AND ($topic_episodes IS NULL OR any(ep IN coalesce(e.episodes, []) WHERE ep IN $topic_episodes))
+		  AND ($sentiment_fragments IS NULL OR any(f IN $sentiment_fragments WHERE e.attributes CONTAINS f))
+		  AND ($emotion_fragments IS NULL OR any(f IN $emotion_fragments WHERE e.attributes CONTAINS f))
+		  AND ($embedding_model IS NULL OR e.fact_embedding_model = $embedding_model OR
+		       (coalesce(e.fact_embedding_model, "") = "" AND size(e.fact_embedding) = size($embedding)))
+		WITH e, n, m,
+		     CASE $metric
+		       WHEN "dot" THEN reduce(dot = 0.0, i in range(0, size(e.fact_embedding)-1) | dot + e.fact_embedding[i] * $embedding[i])
+		       WHEN "euclidean" THEN 1.0 / (1.0 + sqrt(reduce(d = 0.0, i in range(0, size(e.fact_embedding)-1) | d + (e.fact_embedding[i] - $embedding[i])^2)))
+		       ELSE reduce(dot = 0.0, i in range(0, size(e.fact_embedding)-1) | dot + e.fact_embedding[i] * $embedding[i]) / 
+		            (sqrt(reduce(s1 = 0.0, x in e.fact_embedding | s1 + x^2)) * sqrt(reduce(s2 = 0.0, y in $embedding | s2 + y^2)))
+		     END AS score
+		WHERE $min_score IS NULL OR score >= $min_score
+		ORDER BY score DESC
+		RETURN e.uuid AS uuid, 
+		       n.uuid AS source_uuid, 
+		       m.uuid AS target_uuid, 
+		       e.name AS name,
+		       e.fact AS fact, 
+		       e.created_at AS created_at,
+		       e.episodes AS episodes,
+		       coalesce(e.mention_count, 1) AS mention_count,
+		       e.expired_at AS expired_at,
+		       e.citation AS citation,
+		       score,
+		       sqrt(reduce(s1 = 0.0, x in e.fact_embedding | s1 + x^2)) AS fact_norm
+		LIMIT 20
+	`
+
+	// Multi-hop path search: 2-3 hop chains of valid facts from one query-relevant
+	// entity to another, traversed in either direction.
+	FindPathsQuery = `
+		MATCH p = (a:Entity {uuid: $source_uuid})-[:RELATES_TO*2..3]-(b:Entity {group_id: $group_id})
+		WHERE b.uuid IN $target_uuids
+		  AND all(r IN relationships(p) WHERE r.invalid_at IS NULL OR r.invalid_at = "")
+		RETURN [n IN nodes(p) | n.uuid] AS node_uuids,
+		       [n IN nodes(p) | n.name] AS node_names,
+		       [r IN relationships(p) | r.uuid] AS edge_uuids,
+		       [r IN relationships(p) | startNode(r).uuid] AS edge_sources,
+		       [r IN relationships(p) | endNode(r).uuid] AS edge_targets,
+		       [r IN relationships(p) | r.name] AS edge_names,
+		       [r IN relationships(p) | r.fact] AS edge_facts
+		ORDER BY size(relationships(p))
+		LIMIT $limit
+	`
+
+	// Entity linking: the group's entity whose name embedding best matches a query mention.
+	SearchEntitiesByNameVectorQuery = `
+		MATCH (n:Entity {group_id: $group_id})
+		WHERE n.name_embedding IS NOT NULL
+		  AND ($embedding_model IS NULL OR n.name_embedding_model = $embedding_model OR
+		       (coalesce(n.name_embedding_model, "") = "" AND size(n.name_embedding) = size($embedding)))
+		WITH n,
+		     CASE $metric
+		       WHEN "dot" THEN reduce(dot = 0.0, i in range(0, size(n.name_embedding)-1) | dot + n.name_embedding[i] * $embedding[i])
+		       WHEN "euclidean" THEN 1.0 / (1.0 + sqrt(reduce(d = 0.0, i in range(0, size(n.name_embedding)-1) | d + (n.name_embedding[i] - $embedding[i])^2)))
+		       ELSE reduce(dot = 0.0, i in range(0, size(n.name_embedding)-1) | dot + n.name_embedding[i] * $embedding[i]) /
+		            (sqrt(reduce(s1 = 0.0, x in n.name_embedding | s1 + x^2)) * sqrt(reduce(s2 = 0.0, y in $embedding | s2 + y^2)))
+		     END AS score
+		WHERE score >= $min_score
+		RETURN n.uuid AS uuid, n.name AS name, score
+		ORDER BY score DESC
+		LIMIT $limit
+	`
+
+	SearchEdgesQuery = `
+		MATCH (s:Entity)-[e:RELATES_TO]->(t:Entity)
+		WHERE e.group_id = $group_id AND e.fact CONTAINS $query
+		RETURN e.uuid as uuid, s.uuid as source_uuid, t.uuid as target_uuid, e.name as name, e.fact as fact
+		LIMIT 10
+	`
+
+	GetGroupStatsQuery = `
+		OPTIONAL MATCH (n:Entity {group_id: $group_id})
+		WITH count(n) AS entities, max(n.created_at) AS last_entity_at
+		OPTIONAL MATCH (:Entity)-[e:RELATES_TO {group_id: $group_id}]->(:Entity)
+		WITH entities, last_entity_at,
+		     count(e) AS edges,
+		     sum(CASE WHEN e.invalid_at IS NULL OR e.invalid_at = "" THEN 1 ELSE 0 END) AS valid_edges,
+		     max(e.created_at) AS last_edge_at
+		OPTIONAL MATCH (ep:Episodic {group_id: $group_id})
+		WITH entities, last_entity_at, edges, valid_edges, last_edge_at,
+		     count(ep) AS episodes, max(ep.created_at) AS last_episode_at
+		OPTIONAL MATCH (s:Saga {group_id: $group_id})
+		WITH entities, last_entity_at, edges, valid_edges, last_edge_at, episodes, last_episode_at,
+		     count(s) AS sagas
+		OPTIONAL MATCH (c:Community {group_id: $group_id})
+		RETURN entities, edges, valid_edges, episodes, sagas, count(c) AS communities,
+		       last_entity_at, last_edge_at, last_episode_at
+	`
+
+	// Per-entity inputs of the salience ranking: the relation types of its
+	// currently valid facts, the episodes mentioning it and when it was last seen.
+	GetEntitySalienceQuery = `
+		MATCH (n:Entity {group_id: $group_id})
+		OPTIONAL MATCH (n)-[e:RELATES_TO]-(:Entity)
+		WHERE e.invalid_at IS NULL OR e.invalid_at = ""
+		WITH n, collect(e.name) AS relations, max(e.created_at) AS last_fact_at
+		OPTIONAL MATCH (ep:Episodic)-[:MENTIONS]->(n)
+		RETURN n.uuid AS uuid, n.name AS name, n.summary AS summary, labels(n) AS labels,
+		       relations, count(ep) AS mentions, n.created_at AS created_at, last_fact_at,
+		       max(ep.created_at) AS last_mentioned_at
+	`
+
+	// Counts each group's episodes through the Group and Episodic group_id indices
+	ListGroupsQuery = `
+		MATCH (g:Group)
+		OPTIONAL MATCH (ep:Episodic {group_id: g.group_id})
+		WITH g, count(ep) AS episodes, max(ep.created_at) AS last_episode_at
+		RETURN g.group_id AS group_id, episodes, g.created_at AS created_at, last_episode_at,
+		       g.name AS name, g.metadata AS metadata
+		ORDER BY group_id
+	`
+
+	EnsureGroupQuery = `
+		MERGE (g:Group {group_id: $group_id})
+		ON CREATE SET g.name = $group_id,
+			g.created_at = $created_at,
+			g.updated_at = $created_at,
+			g.settings = "{}",
+			g.metadata = "{}"
+		RETURN g.group_id AS group_id, g.name AS name, g.owner AS owner, g.created_at AS created_at,
+		       g.updated_at AS updated_at, g.settings AS settings, g.metadata AS metadata
+	`
+
+	GetGroupQuery = `
+		MATCH (g:Group {group_id: $group_id})
+		RETURN g.group_id AS group_id, g.name AS name, g.owner AS owner, g.created_at AS created_at,
+		       g.updated_at AS updated_at, g.settings AS settings, g.metadata AS metadata
+	`
+
+	SaveGroupQuery = `
+		MERGE (g:Group {group_id: $group_id})
+		ON CREATE SET g.created_at = $created_at
+		SET g.name = $name,
+			g.owner = $owner,
+			g.updated_at = $updated_at,
+			g.settings = $settings,
+			g.metadata = $metadata
+		RETURN g.group_id AS group_id
+	`
+
+	// Ingest jobs checkpoint long bulk loads. The job node holds progress and the
+	// resolved entity map; its episodes are written once on creation, one
+	// IngestJobBatch node per checkpoint, so checkpoints never rewrite them.
+	SaveIngestJobQuery = `
+		MERGE (j:IngestJob {uuid: $uuid})
+		ON CREATE SET j.created_at = $created_at,
+			j.total = $total
+		SET j.group_id = $group_id,
+			j.status = $status,
+			j.processed = $processed,
+			j.failed = $failed,
+			j.entity_map = $entity_map,
+			j.error = $error,
+			j.updated_at = $updated_at
+		RETURN j.uuid AS uuid
+	`
+
+	GetIngestJobQuery = `
+		MATCH (j:IngestJob {uuid: $uuid})
+		RETURN j.uuid AS uuid, j.group_id AS group_id, j.status AS status, j.total AS total,
+		       j.processed AS processed, j.failed AS failed, j.error AS error,
+		       j.created_at AS created_at, j.updated_at AS updated_at,
+		       j.entity_map AS entity_map
+	`
+
+	SaveIngestJobBatchQuery = `
+		MERGE (b:IngestJobBatch {uuid: $uuid})
+		SET b.job_uuid = $job_uuid,
+			b.group_id = $group_id,
+			b.seq = $seq,
+			b.episodes = $episodes
+		RETURN b.uuid AS uuid
+	`
+
+	GetIngestJobBatchQuery = `
+		MATCH (b:IngestJobBatch {uuid: $uuid})
+		RETURN b.episodes AS episodes
+	`
+
+	// FailIngestJobQuery marks a job failed without touching its progress.
+	FailIngestJobQuery = `
+		MATCH (j:IngestJob {uuid: $uuid})
+		SET j.status = $status,
+			j.error = $error,
+			j.updated_at = $updated_at
+		RETURN j.uuid AS uuid
+	`
+
+	ListIngestJobsByStatusQuery = `
+		MATCH (j:IngestJob)
+		WHERE j.status IN $statuses
+		RETURN j.uuid AS uuid
+		ORDER BY j.created_at
+	`
+
+	// Dead letters are keyed by their payload, so a repeated failure bumps attempts.
+	SaveDeadLetterQuery = `
+		MERGE (d:DeadLetter {uuid: $uuid})
+		ON CREATE SET d.created_at = $updated_at,
+			d.attempts = 0
+		SET d.group_id = $group_id,
+			d.name = $name,
+			d.episode = $episode,
+			d.error = $error,
+			d.attempts = d.attempts + 1,
+			d.updated_at = $updated_at
+		RETURN d.uuid AS uuid
+	`
+
+	GetDeadLetterQuery = `
+		MATCH (d:DeadLetter {uuid: $uuid})
+		RETURN d.uuid AS uuid, d.group_id AS group_id, d.name AS name, d.episode AS episode,
+		       d.error AS error, d.attempts AS attempts, d.created_at AS created_at, d.updated_at AS updated_at
+	`
+
+	// A null $group_id lists the dead letters of every group.
+	ListDeadLettersQuery = `
+		MATCH (d:DeadLetter)
+		WHERE $group_id IS NULL OR d.group_id = $group_id
+		RETURN d.uuid AS uuid, d.group_id AS group_id, d.name AS name, d.episode AS episode,
+		       d.error AS error, d.attempts AS attempts, d.created_at AS created_at, d.updated_at AS updated_at
+		ORDER BY d.updated_at DESC
+	`
+
+	DeleteDeadLetterQuery = `
+		MATCH (d:DeadLetter {uuid: $uuid})
+		WITH d, d.uuid AS uuid
+		DETACH DELETE d
+		RETURN uuid
+	`
+
+	SaveModerationRecordQuery = `
+		CREATE (m:ModerationRecord {uuid: $uuid})
+		SET m.group_id = $group_id,
+			m.episode_name = $episode_name,
+			m.episode_uuid = $episode_uuid,
+			m.action = $action,
+			m.categories = $categories,
+			m.content_sha256 = $content_sha256,
+			m.created_at = $created_at
+		RETURN m.uuid AS uuid
+	`
+
+	ListModerationRecordsQuery = `
+		MATCH (m:ModerationRecord {group_id: $group_id})
+		RETURN m.uuid AS uuid, m.group_id AS group_id, m.episode_name AS episode_name, m.episode_uuid AS episode_uuid,
+		       m.action AS action, m.categories AS categories, m.content_sha256 AS content_sha256, m.created_at AS created_at
+		ORDER BY m.created_at DESC
+		LIMIT $limit
+	`
+
+	GetEntityNodeQuery = `
+		MATCH (n:Entity {uuid: $uuid})
+		RETURN n.uuid AS uuid, n.name AS name, n.group_id AS group_id, n.created_at AS created_at,
+		       n.summary AS summary, n.attributes AS attributes, labels(n) AS labels,
+		       coalesce(n.version, 0) AS version
+	`
+
+	// Currently valid facts touching an entity, in either direction.
+	GetEntityFactsQuery = `
+		MATCH (n:Entity {uuid: $uuid})-[e:RELATES_TO]-(:Entity)
+		WHERE (e.invalid_at IS NULL OR e.invalid_at = "")
+		RETURN e.fact AS fact, e.name AS name
+		ORDER BY e.created_at
+	`
+
+	GetEntityNodesQuery = `
+		MATCH (n:Entity)
+		WHERE n.uuid IN $uuids
+		RETURN n.uuid AS uuid, n.name AS name, n.group_id AS group_id, n.created_at AS created_at,
+		       n.summary AS summary, n.attributes AS attributes, labels(n) AS labels,
+		       coalesce(n.version, 0) AS version
+	`
+
+	// Currently valid facts of the group touching any of the given entities,
+	// one hop of a neighborhood expansion.
+	GetNeighborEdgesQuery = `
+		MATCH (n:Entity)-[e:RELATES_TO]-(m:Entity {group_id: $group_id})
+		WHERE n.uuid IN $uuids AND n.group_id = $group_id
+		  AND (e.invalid_at IS NULL OR e.invalid_at = "")
+		RETURN DISTINCT e.uuid AS uuid, startNode(e).uuid AS source_uuid, endNode(e).uuid AS target_uuid,
+		       e.name AS name, e.fact AS fact, e.created_at AS created_at, e.valid_at AS valid_at,
+		       e.attributes AS attributes, coalesce(e.mention_count, 1) AS mention_count,
+		       e.citation AS citation
+		ORDER BY created_at, uuid
+	`
+
+	// Communities with any of the given entities as members, listing only those members.
+	GetEntityCommunitiesQuery = `
+		MATCH (c:Community)-[:HAS_MEMBER]->(e:Entity)
+		WHERE e.uuid IN $uuids
+		RETURN c.uuid AS uuid, c.name AS name, c.summary AS summary, collect(e.uuid) AS members
+		ORDER BY uuid
+	`
+
+	// Maintenance reports keep the latest run of each kind per group as a JSON document.
+	SaveMaintenanceReportQuery = `
+		MERGE (r:MaintenanceReport {group_id: $group_id, kind: $kind})
+		SET r.created_at = $created_at,
+			r.report = $report
+		RETURN r.kind AS kind
+	`
+
+	GetMaintenanceReportQuery = `
+		MATCH (r:MaintenanceReport {group_id: $group_id, kind: $kind})
+		RETURN r.report AS report, r.created_at AS created_at
+	`
+
+	// Scratchpad entries hold an agent's short-term state per group, keyed by
+	// name, as JSON values outside the graph. Expired entries read as missing.
+	SaveScratchEntryQuery = `
+		MERGE (s:ScratchEntry {group_id: $group_id, key: $key})
+		ON CREATE SET s.uuid = $uuid, s.created_at = $updated_at
+		SET s.value = $value,
+			s.updated_at = $updated_at,
+			s.expires_at = $expires_at
+		RETURN s.key AS key
+	`
+
+	GetScratchEntriesQuery = `
+		MATCH (s:ScratchEntry {group_id: $group_id})
+		WHERE ($key IS NULL OR s.key = $key) AND (s.expires_at IS NULL OR s.expires_at > $now)
+		RETURN s.key AS key, s.value AS value, s.created_at AS created_at,
+		       s.updated_at AS updated_at, s.expires_at AS expires_at
+		ORDER BY s.key
+	`
+
+	DeleteScratchEntryQuery = `
+		MATCH (s:ScratchEntry {group_id: $group_id, key: $key})
+		WITH s, s.key AS key, (s.expires_at IS NULL OR s.expires_at > $now) AS live
+		DETACH DELETE s
+		RETURN key, live
+	`
+
+	DeleteExpiredScratchEntriesQuery = `
+		MATCH (s:ScratchEntry {group_id: $group_id})
+		WHERE s.expires_at IS NOT NULL AND s.expires_at <= $now
+		WITH s, s.key AS key
+		DETACH DELETE s
+		RETURN key
+	`
+
+	// The change log: each group's ChangeSequence counts its changes, so every
+	// Change gets the next sequence number of its group.
+	AppendChangeQuery = `
+		MERGE (s:ChangeSequence {group_id: $group_id})
+		ON CREATE SET s.uuid = $sequence_uuid
+		SET s.seq = coalesce(s.seq, 0) + 1
+		CREATE (c:Change {uuid: $uuid, group_id: $group_id, seq: s.seq, kind: $kind,
+			target_uuid: $target_uuid, op: $op, at: $at})
+		RETURN c.seq AS seq
+	`
+
+	GetChangesQuery = `
+		MATCH (c:Change {group_id: $group_id})
+		WHERE c.seq > $since
+		RETURN c.group_id AS group_id, c.seq AS seq, c.kind AS kind, c.target_uuid AS uuid, c.op AS op, c.at AS at
+		ORDER BY c.seq
+		LIMIT $limit
+	`
+
+	// Edge maintenance (dedupe-edges) reads the provenance of every active fact.
+	GetGroupEdgeEpisodesQuery = `
+		MATCH (n:Entity {group_id: $group_id})-[e:RELATES_TO]->(m:Entity {group_id: $group_id})
+		WHERE (e.invalid_at IS NULL OR e.invalid_at = "")
+		RETURN e.uuid AS uuid, n.uuid AS source_uuid, m.uuid AS target_uuid, e.name AS name, e.fact AS fact,
+			e.created_at AS created_at, e.episodes AS episodes, e.citation AS citation
+	`
+
+	// A fact extracted again from another episode: the episode joins its
+	// provenance and its mention count goes up. Facts saved before mention
+	// counts count as mentioned once.
+	ReinforceEntityEdgeQuery = `
+		MATCH ()-[e:RELATES_TO {uuid: $uuid}]->()
+		WHERE NOT $episode_uuid IN coalesce(e.episodes, [])
+		SET e.episodes = coalesce(e.episodes, []) + $episode_uuid,
+			e.mention_count = coalesce(e.mention_count, 1) + 1
+		RETURN e.uuid AS uuid, e.mention_count AS mention_count
+	`
+
+	SetEdgeEpisodesQuery = `
+		MATCH ()-[e:RELATES_TO {uuid: $uuid}]->()
+		SET e.episodes = $episodes
+		RETURN e.uuid AS uuid
+	`
+
+	GetEntityEdgeQuery = `
+		MATCH (n:Entity)-[e:RELATES_TO {uuid: $uuid}]->(m:Entity)
+		RETURN e.uuid AS uuid, n.uuid AS source_uuid, m.uuid AS target_uuid, e.group_id AS group_id,
+			e.name AS name, e.fact AS fact, e.created_at AS created_at, e.valid_at AS valid_at,
+			e.invalid_at AS invalid_at, e.episodes AS episodes, e.attributes AS attributes,
+			coalesce(e.mention_count, 1) AS mention_count, e.citation AS citation
+	`
+
+	// Looks a citation handle up through the RELATES_TO(citation) index
+	GetEntityEdgeByCitationQuery = `
+		MATCH ()-[e:RELATES_TO {citation: $citation}]->()
+		WHERE e.group_id = $group_id
+		RETURN e.uuid AS uuid
+		ORDER BY e.created_at, e.uuid
+		LIMIT 1
+	`
+
+	DeleteEntityEdgeQuery = `
+		MATCH ()-[e:RELATES_TO {uuid: $uuid}]->()
+		WITH e, e.uuid AS uuid, e.group_id AS group_id
+		DELETE e
+		RETURN uuid, group_id
+	`
+
+	// Orphans are entities no episode mentions and no valid fact touches. The
+	// created_before cutoff skips entities whose episode is still being ingested.
+	GetOrphanEntitiesQuery = `
+		MATCH (n:Entity {group_id: $group_id})
+		WHERE n.created_at < $created_before AND NOT ()-[:MENTIONS]->(n)
+		OPTIONAL MATCH (n)-[e:RELATES_TO]-()
+		WHERE e.invalid_at IS NULL OR e.invalid_at = ""
+		WITH n, count(e) AS valid_edges
+		WHERE valid_edges = 0
+		RETURN n.uuid AS uuid, n.name AS name, n.group_id AS group_id, n.created_at AS created_at
+		ORDER BY n.name
+	`
+
+	// Quarantined entities lose the Entity label, so search and ingest no longer see them.
+	QuarantineEntityQuery = `
+		MATCH (n:Entity {uuid: $uuid})
+		REMOVE n:Entity
+		SET n:QuarantinedEntity, n.quarantined_at = $quarantined_at
+		RETURN n.uuid AS uuid, n.group_id AS group_id
+	`
+
+	// The oldest of a group's episodes, entities and facts, which group limits
+	// evict first. Invalidated facts go before current ones.
+	GetOldestEpisodesQuery = `
+		MATCH (e:Episodic {group_id: $group_id})
+		RETURN e.uuid AS uuid
+		ORDER BY e.created_at
+		LIMIT $limit
+	`
+
+	// Episodes past their group's retention_days, which the retention sweep deletes.
+	GetEpisodesCreatedBeforeQuery = `
+		MATCH (e:Episodic {group_id: $group_id})
+		WHERE e.created_at < $created_before
+		RETURN e.uuid AS uuid
+		ORDER BY e.created_at
+	`
+
+	GetOldestEntitiesQuery = `
+		MATCH (n:Entity {group_id: $group_id})
+		WHERE NOT n.uuid IN $exclude
+		RETURN n.uuid AS uuid
+		ORDER BY n.created_at
+		LIMIT $limit
+	`
+
+	GetOldestEdgesQuery = `
+		MATCH (:Entity)-[e:RELATES_TO {group_id: $group_id}]->(:Entity)
+		RETURN e.uuid AS uuid
+		ORDER BY CASE WHEN e.invalid_at IS NULL OR e.invalid_at = "" THEN 1 ELSE 0 END, e.created_at
+		LIMIT $limit
+	`
+
+	// Entities mentioned by, and facts derived from, any of a set of episodes,
+	// which episode compaction moves to the digest replacing them and repeated
+	// episodes are linked to.
+	GetEpisodeMentionsQuery = `
+		MATCH (ep:Episodic)-[:MENTIONS]->(n:Entity)
+		WHERE ep.uuid IN $uuids
+		RETURN DISTINCT n.uuid AS uuid
+	`
+
+	GetEpisodeFactsQuery = `
+		MATCH (:Entity)-[e:RELATES_TO {group_id: $group_id}]->(:Entity)
+		WHERE any(uuid IN e.episodes WHERE uuid IN $uuids)
+		RETURN e.uuid AS uuid, e.episodes AS episodes
+	`
+
+	DeleteEntityNodeQuery = `
+		MATCH (n:Entity {uuid: $uuid})
+		WITH n, n.uuid AS uuid, n.group_id AS group_id
+		DETACH DELETE n
+		RETURN uuid, group_id
+	`
+
+	DeleteEpisodeQuery = `
+		MATCH (n:Episodic {uuid: $uuid})
+		WITH n, n.uuid AS uuid, n.content_ref AS content_ref, n.group_id AS group_id
+		DETACH DELETE n
+		RETURN uuid, content_ref, group_id
+	`
+
+	// Every node of a group: entities, episodes, communities, sagas, jobs, reports
+	// and the group itself. Its change log is kept to record the deletion.
+	DeleteGroupQuery = `
+		MATCH (n {group_id: $group_id})
+		WHERE NOT n:Change AND NOT n:ChangeSequence
+		WITH collect(n) AS nodes, count(n) AS deleted
+		FOREACH (n IN nodes | DETACH DELETE n)
+		RETURN deleted
+	`
+
+	// Removes all data but keeps the schema version, so migrations don't re-run,
+	// and the change logs, which record the deletion.
+	ClearGraphQuery = `
+		MATCH (n)
+		WHERE NOT n:SchemaVersion AND NOT n:Change AND NOT n:ChangeSequence
+		WITH collect(n) AS nodes, count(n) AS deleted
+		FOREACH (n IN nodes | DETACH DELETE n)
+		RETURN deleted
+	`
+
+	// Exports copy every node and relationship of a group, or of the whole graph
+	// (except the schema version) when $group_id is null.
+	ExportNodesQuery = `
+		MATCH (n)
+		WHERE ($group_id IS NULL AND NOT n:SchemaVersion) OR n.group_id = $group_id
+		RETURN labels(n) AS labels, properties(n) AS properties
+		ORDER BY n.uuid
+	`
+
+	ExportEdgesQuery = `
+		MATCH (s)-[e]->(t)
+		WHERE $group_id IS NULL OR e.group_id = $group_id
+		RETURN type(e) AS type, s.uuid AS source_uuid, t.uuid AS target_uuid, properties(e) AS properties
+		ORDER BY e.uuid
+	`
+
+	// Imports recreate exported nodes and relationships (see ExportNodesQuery).
+	// Drivers running Cypher import nodes in batches of the same labels.
+	ImportNodesQuery = `
+		UNWIND $nodes AS node
+		CREATE (n)
+		SET n = node.properties
+		WITH n
+		` + labelsClause + ` // node.labels
+		RETURN count(n) AS imported
+	`
+
+	// Schema migrations (see migrations.go)
+	GetSchemaVersionQuery = `
+		MATCH (s:SchemaVersion {id: "graph"})
+		RETURN s.version AS version, s.dirty AS dirty
+	`
+
+	SetSchemaVersionQuery = `
+		MERGE (s:SchemaVersion {id: "graph"})
+		SET s.version = $version,
+			s.name = $name,
+			s.dirty = $dirty,
+			s.applied_at = $applied_at
+		RETURN s.version AS version
+	`
+
+	BackfillEntityAttributesQuery = `
+		MATCH (n:Entity)
+		WHERE n.attributes IS NULL OR n.attributes = ""
+		SET n.attributes = "{}"
+		RETURN count(n) AS updated
+	`
+
+	BackfillEdgeEpisodesQuery = `
+		MATCH ()-[e:RELATES_TO]->()
+		WHERE e.episodes IS NULL
+		SET e.episodes = []
+		RETURN count(e) AS updated
+	`
+
+	// Facts saved before citation handles get "F-" and the first 8 characters
+	// of their UUID, or their whole UUID where that prefix is shared in the group.
+	BackfillEdgeCitationsQuery = `
+		MATCH ()-[e:RELATES_TO]->()
+		WHERE e.citation IS NULL
+		WITH e.group_id AS group_id, left(e.uuid, 8) AS prefix, collect(e) AS edges
+		OPTIONAL MATCH ()-[taken:RELATES_TO {citation: "F-" + prefix}]->()
+		WHERE taken.group_id = group_id
+		WITH prefix, edges, count(taken) = 0 AND size(edges) = 1 AS is_unique
+		UNWIND edges AS e
+		SET e.citation = "F-" + CASE WHEN is_unique THEN prefix ELSE e.uuid END
+		RETURN count(e) AS updated
+	`
+
+	// Groups whose episodes were added before Group nodes existed get one,
+	// created when their first episode was.
+	BackfillGroupsQuery = `
+		MATCH (ep:Episodic)
+		WHERE ep.group_id IS NOT NULL
+		WITH ep.group_id AS group_id, min(ep.created_at) AS created_at
+		OPTIONAL MATCH (existing:Group {group_id: group_id})
+		WITH group_id, created_at WHERE existing IS NULL
+		CREATE (g:Group {group_id: group_id, name: group_id, created_at: created_at, updated_at: created_at,
+		                 settings: "{}", metadata: "{}"})
+		RETURN count(g) AS updated
+	`
+
+	// Offloaded content used to be kept as "blob:v1:<key>" in content; the
+	// key is checked against the episode when read.
+	BackfillContentRefsQuery = `
+		MATCH (ep:Episodic)
+		WHERE ep.content STARTS WITH "blob:v1:" AND ep.content_ref IS NULL
+		SET ep.content_ref = substring(ep.content, 8), ep.content = ""
+		RETURN count(ep) AS updated
+	`
+
+	// Re-embedding: embeddings are tagged "<model>@<dimension>"; those whose tag
+	// does not start with $model_prefix (or that have none) are stale. New vectors
+	// are staged in *_next properties and promoted for the whole group at once,
+	// so search never mixes models mid-run.
+	GetStaleEntityEmbeddingsQuery = `
+		MATCH (n:Entity {group_id: $group_id})
+		WHERE NOT coalesce(n.name_embedding_model, "") STARTS WITH $model_prefix
+		RETURN n.uuid AS uuid, n.name AS name
+		ORDER BY n.uuid
+	`
+
+	GetStaleCommunityEmbeddingsQuery = `
+		MATCH (n:Community {group_id: $group_id})
+		WHERE NOT coalesce(n.name_embedding_model, "") STARTS WITH $model_prefix
+		RETURN n.uuid AS uuid, n.name AS name
+		ORDER BY n.uuid
+	`
+
+	GetStaleFactEmbeddingsQuery = `
+		MATCH (:Entity)-[e:RELATES_TO {group_id: $group_id}]->(:Entity)
+		WHERE NOT coalesce(e.fact_embedding_model, "") STARTS WITH $model_prefix
+		RETURN e.uuid AS uuid, e.fact AS fact
+		ORDER BY e.uuid
+	`
+
+	StageNameEmbeddingsQuery = `
+		UNWIND $embeddings AS emb
+		MATCH (n {uuid: emb.uuid})
+		WHERE n:Entity OR n:Community
+		SET n.name_embedding_next = emb.embedding,
+			n.name_embedding_next_model = emb.embedding_model
+		RETURN n.uuid AS uuid
+	`
+
+	StageFactEmbeddingsQuery = `
+		UNWIND $embeddings AS emb
+		MATCH ()-[e:RELATES_TO {uuid: emb.uuid}]->()
+		SET e.fact_embedding_next = emb.embedding,
+			e.fact_embedding_next_model = emb.embedding_model
+		RETURN e.uuid AS uuid
+	`
+
+	PromoteEmbeddingsQuery = `
+		OPTIONAL MATCH (n {group_id: $group_id})
+		WHERE (n:Entity OR n:Community) AND n.name_embedding_next IS NOT NULL
+		SET n.name_embedding = n.name_embedding_next,
+			n.name_embedding_model = n.name_embedding_next_model
+		REMOVE n.name_embedding_next, n.name_embedding_next_model
+		WITH count(n) AS nodes
+		OPTIONAL MATCH ()-[e:RELATES_TO {group_id: $group_id}]->()
+		WHERE e.fact_embedding_next IS NOT NULL
+		SET e.fact_embedding = e.fact_embedding_next,
+			e.fact_embedding_model = e.fact_embedding_next_model
+		REMOVE e.fact_embedding_next, e.fact_embedding_next_model
+		RETURN nodes, count(e) AS facts
+	`
 )
+
+// ImportEdgesQueries maps each relationship type carbon writes to the query
+// importing $edges of that type; Cypher can't take the type as a parameter.
+var ImportEdgesQueries = importEdgesQueries()
+
+func importEdgesQueries() map[string]string {
+	queries := make(map[string]string, len(RelationshipTypes))
+	for _, relType := range RelationshipTypes {
+		queries[relType] = `
+		UNWIND $edges AS edge
+		MATCH (s {uuid: edge.source_uuid})
+		MATCH (t {uuid: edge.target_uuid})
+		CREATE (s)-[e:` + relType + `]->(t)
+		SET e = edge.properties
+		RETURN count(e) AS imported
+	`
+	}
+	return queries
+}
@@ -0,0 +1,155 @@
+package driver
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// RecordMapper support: struct fields tagged `db:"key"` are filled from the record
+// value returned under that key. Missing keys and nulls leave the zero value;
+// values of the wrong type produce an error naming the key instead of panicking.
+//
+// Supported field types are string, bool, the int and float kinds, time.Time
+// (from driver temporal values or RFC3339 strings), slices of those, and interface{}.
+
+// ScanRecord copies rec into the struct pointed to by dest.
+func ScanRecord(rec *neo4j.Record, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("scan destination must be a pointer to a struct, got %T", dest)
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		raw, ok := rec.Get(key)
+		if !ok || raw == nil {
+			continue
+		}
+		if err := assign(v.Field(i), raw); err != nil {
+			return fmt.Errorf("record field '%s': %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ScanRecords maps every record of res into a T.
+func ScanRecords[T any](res neo4j.EagerResult) ([]T, error) {
+	out := make([]T, 0, len(res.Records))
+	for i, rec := range res.Records {
+		var item T
+		if err := ScanRecord(rec, &item); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func assign(dst reflect.Value, raw interface{}) error {
+	src := reflect.ValueOf(raw)
+
+	if dst.Type() == timeType {
+		t, err := toTime(raw)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		dst.Set(src)
+		return nil
+	case reflect.String:
+		if s, ok := raw.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := raw.(bool); ok {
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if src.CanInt() {
+			dst.SetInt(src.Int())
+			return nil
+		}
+		// Properties decoded from JSON (SQLite backend) carry numbers as float64
+		if src.CanFloat() && src.Float() == math.Trunc(src.Float()) {
+			dst.SetInt(int64(src.Float()))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if src.CanFloat() {
+			dst.SetFloat(src.Float())
+			return nil
+		}
+		if src.CanInt() {
+			dst.SetFloat(float64(src.Int()))
+			return nil
+		}
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			elem := src.Index(i).Interface()
+			if elem == nil {
+				continue
+			}
+			if err := assign(out.Index(i), elem); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Pointer:
+		if s, ok := raw.(string); ok && s == "" && dst.Type().Elem() == timeType {
+			return nil // Unset timestamps are stored as empty strings
+		}
+		ptr := reflect.New(dst.Type().Elem())
+		if err := assign(ptr.Elem(), raw); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+	return fmt.Errorf("cannot assign %T to %s", raw, dst.Type())
+}
+
+func toTime(raw interface{}) (time.Time, error) {
+	switch v := raw.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", v, err)
+		}
+		return t, nil
+	}
+	// Driver temporal types (LocalDateTime, Date, ...) are defined over time.Time.
+	if rv := reflect.ValueOf(raw); rv.Type().ConvertibleTo(timeType) {
+		return rv.Convert(timeType).Interface().(time.Time), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot assign %T to time.Time", raw)
+}
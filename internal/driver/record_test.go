@@ -0,0 +1,72 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRow struct {
+	UUID      string     `db:"uuid"`
+	Summary   string     `db:"summary"`
+	Count     int        `db:"count"`
+	Score     float64    `db:"score"`
+	CreatedAt time.Time  `db:"created_at"`
+	InvalidAt *time.Time `db:"invalid_at"`
+	Episodes  []string   `db:"episodes"`
+	Embedding []float32  `db:"embedding"`
+	Ignored   string
+}
+
+func TestScanRecord(t *testing.T) {
+	rec := &neo4j.Record{
+		Keys: []string{"uuid", "summary", "count", "score", "created_at", "invalid_at", "episodes", "embedding"},
+		Values: []interface{}{
+			"u1", nil, int64(3), int64(2), "2024-05-01T10:00:00Z", "",
+			[]interface{}{"ep1", "ep2"}, []interface{}{0.5, 1.0},
+		},
+	}
+
+	var row testRow
+	require.NoError(t, ScanRecord(rec, &row))
+	assert.Equal(t, "u1", row.UUID)
+	assert.Equal(t, "", row.Summary) // null leaves zero value
+	assert.Equal(t, 3, row.Count)
+	assert.Equal(t, 2.0, row.Score)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), row.CreatedAt)
+	assert.Nil(t, row.InvalidAt)
+	assert.Equal(t, []string{"ep1", "ep2"}, row.Episodes)
+	assert.Equal(t, []float32{0.5, 1.0}, row.Embedding)
+}
+
+func TestScanRecords_TypeMismatch(t *testing.T) {
+	res := neo4j.EagerResult{Records: []*neo4j.Record{
+		{Keys: []string{"uuid"}, Values: []interface{}{"ok"}},
+		{Keys: []string{"uuid"}, Values: []interface{}{int64(42)}},
+	}}
+
+	_, err := ScanRecords[testRow](res)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "record 1")
+	assert.Contains(t, err.Error(), "'uuid'")
+}
+
+func TestScanRecord_InvalidTimestamp(t *testing.T) {
+	rec := &neo4j.Record{Keys: []string{"created_at"}, Values: []interface{}{"yesterday"}}
+	var row testRow
+	err := ScanRecord(rec, &row)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "created_at")
+}
+
+func TestScanRecord_IntegralFloatToInt(t *testing.T) {
+	var row testRow
+	require.NoError(t, ScanRecord(&neo4j.Record{Keys: []string{"count"}, Values: []interface{}{float64(7)}}, &row))
+	assert.Equal(t, 7, row.Count)
+
+	err := ScanRecord(&neo4j.Record{Keys: []string{"count"}, Values: []interface{}{7.5}}, &row)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// RetryPolicy reruns queries that failed with a transient error (see
+// IsTransient), waiting an exponentially growing, jittered backoff between
+// attempts. MaxAttempts below 2 runs each query once.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	sleep func(ctx context.Context, d time.Duration) error // Tests replace the wait
+}
+
+// memgraphTransientMessages are Memgraph errors reported with a client error
+// code that go away on their own: write conflicts and a replica answering
+// while the cluster fails over to a new main.
+var memgraphTransientMessages = []string{
+	"cannot resolve conflicting transactions",
+	"serialization error",
+	"write queries are forbidden on the replica",
+	"not the main instance",
+}
+
+// IsTransient reports whether err is worth retrying: a Neo4j/Memgraph
+// transient error code, a leader switch, a write conflict or a dropped or
+// refused connection. Errors from the caller's context are not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var limit *neo4j.TransactionExecutionLimit
+	if errors.As(err, &limit) {
+		// The Bolt driver gave up retrying on its own; its last error decides
+		return len(limit.Errors) > 0 && IsTransient(limit.Errors[len(limit.Errors)-1])
+	}
+	var dbErr *neo4j.Neo4jError
+	if errors.As(err, &dbErr) {
+		if dbErr.IsRetriable() {
+			return true
+		}
+		msg := strings.ToLower(dbErr.Msg)
+		for _, m := range memgraphTransientMessages {
+			if strings.Contains(msg, m) {
+				return true
+			}
+		}
+		return false
+	}
+	var connErr *neo4j.ConnectivityError
+	if errors.As(err, &connErr) {
+		// Except a commit whose outcome is unknown
+		return neo4j.IsRetryable(connErr)
+	}
+	var netErr net.Error
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// Do runs query until it succeeds, fails with an error that isn't transient,
+// runs out of attempts or ctx ends. It returns the last error.
+func (p RetryPolicy) Do(ctx context.Context, query func() error) error {
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Second
+	}
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := query()
+		if err == nil || attempt >= p.MaxAttempts || !IsTransient(err) {
+			return err
+		}
+		// Jitter spreads the retries of concurrent ingestion workers
+		wait := backoff/2 + rand.N(backoff/2+1)
+		log.Printf("Retrying query after transient error (attempt %d of %d, in %s): %v", attempt+1, p.MaxAttempts, wait, err)
+		if sleepErr := sleep(ctx, wait); sleepErr != nil {
+			return err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,83 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	for _, err := range []error{
+		&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"},
+		&neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"},
+		&neo4j.Neo4jError{Code: "Memgraph.TransientError.MemgraphError.MemgraphError", Msg: "Cannot resolve conflicting transactions."},
+		&neo4j.Neo4jError{Code: "Memgraph.ClientError.MemgraphError.MemgraphError", Msg: "Write queries are forbidden on the replica instance."},
+		&neo4j.ConnectivityError{Inner: syscall.ECONNRESET},
+		&neo4j.TransactionExecutionLimit{Errors: []error{&neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"}}},
+		fmt.Errorf("read: %w", syscall.ECONNRESET),
+	} {
+		assert.True(t, IsTransient(err), err.Error())
+	}
+	for _, err := range []error{
+		nil,
+		&neo4j.Neo4jError{Code: "Memgraph.ClientError.MemgraphError.MemgraphError", Msg: "Unbound variable: x."},
+		&neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"},
+		context.Canceled,
+		errors.New("invalid label"),
+	} {
+		assert.False(t, IsTransient(err), fmt.Sprint(err))
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var waits []time.Duration
+	p := RetryPolicy{MaxAttempts: 4, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond,
+		sleep: func(ctx context.Context, d time.Duration) error { waits = append(waits, d); return nil }}
+	transient := &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}
+
+	calls := 0
+	err := p.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	// Jittered into [backoff/2, backoff]
+	assert.Len(t, waits, 2)
+	assert.True(t, waits[0] >= 50*time.Millisecond && waits[0] <= 100*time.Millisecond, waits[0])
+	assert.True(t, waits[1] >= 100*time.Millisecond && waits[1] <= 200*time.Millisecond, waits[1])
+
+	// Gives up after MaxAttempts, with the backoff capped
+	calls, waits = 0, nil
+	err = p.Do(context.Background(), func() error { calls++; return transient })
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 4, calls)
+	assert.True(t, waits[2] >= 150*time.Millisecond && waits[2] <= 300*time.Millisecond, waits[2])
+
+	// Permanent errors and disabled retries run once
+	calls = 0
+	err = p.Do(context.Background(), func() error { calls++; return errors.New("syntax error") })
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	calls = 0
+	_ = RetryPolicy{}.Do(context.Background(), func() error { calls++; return transient })
+	assert.Equal(t, 1, calls)
+
+	// A cancelled wait returns the query's error
+	p.sleep = sleepContext
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = p.Do(ctx, func() error { calls++; return transient })
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 1, calls)
+}
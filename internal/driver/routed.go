@@ -0,0 +1,232 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Route keeps the groups whose group_id starts with Prefix on Driver.
+type Route struct {
+	Prefix string
+	Driver GraphDriver
+}
+
+// RoutedDriver spreads groups over several backends by group_id prefix, so a
+// large tenant can live in its own database or instance. A query naming a
+// $group_id runs on the backend owning that group; one that doesn't (lookups
+// and updates by uuid, listings across groups, clearing the graph) runs on
+// every backend and their results are gathered (see scatter), with the
+// counts of deleted, updated or imported nodes summed.
+type RoutedDriver struct {
+	Default GraphDriver
+	// Routes is kept longest prefix first.
+	Routes []Route
+}
+
+// NewRoutedDriver routes groups matching a route's prefix to its driver and
+// every other group to def.
+func NewRoutedDriver(def GraphDriver, routes []Route) *RoutedDriver {
+	routes = append([]Route(nil), routes...)
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].Prefix) > len(routes[j].Prefix) })
+	return &RoutedDriver{Default: def, Routes: routes}
+}
+
+// Backend returns the driver holding groupID.
+func (d *RoutedDriver) Backend(groupID string) GraphDriver {
+	for _, r := range d.Routes {
+		if strings.HasPrefix(groupID, r.Prefix) {
+			return r.Driver
+		}
+	}
+	return d.Default
+}
+
+// Backends returns every driver, the default one first.
+func (d *RoutedDriver) Backends() []GraphDriver {
+	backends := []GraphDriver{d.Default}
+	for _, r := range d.Routes {
+		backends = append(backends, r.Driver)
+	}
+	return backends
+}
+
+func (d *RoutedDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if query == ImportNodesQuery {
+		return importByGroup(ctx, d.Backend, params)
+	}
+	if groupID, ok := params["group_id"].(string); ok {
+		return d.Backend(groupID).ExecuteQuery(ctx, query, params)
+	}
+	return scatter(ctx, query, d.Backends(), func(b GraphDriver) (neo4j.EagerResult, error) {
+		return b.ExecuteQuery(ctx, query, params)
+	})
+}
+
+func (d *RoutedDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if groupID, ok := params["group_id"].(string); ok {
+		return d.Backend(groupID).ExecuteReadQuery(ctx, query, params)
+	}
+	return scatter(ctx, query, d.Backends(), func(b GraphDriver) (neo4j.EagerResult, error) {
+		return b.ExecuteReadQuery(ctx, query, params)
+	})
+}
+
+func (d *RoutedDriver) BuildIndices(ctx context.Context) error {
+	for _, b := range d.Backends() {
+		if err := b.BuildIndices(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *RoutedDriver) Close(ctx context.Context) error {
+	var errs []error
+	for _, b := range d.Backends() {
+		errs = append(errs, b.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// countKeys are the single-value results summed when a query runs on several backends.
+var countKeys = map[string]bool{"deleted": true, "updated": true, "imported": true}
+
+// scatter runs a query on every backend at once and gathers the results in
+// backend order. Listings across groups are sorted again as their query
+// orders them. A write fails if it failed on any backend, though it may have
+// been applied on others.
+func scatter(ctx context.Context, query string, backends []GraphDriver, run func(GraphDriver) (neo4j.EagerResult, error)) (neo4j.EagerResult, error) {
+	results := make([]neo4j.EagerResult, len(backends))
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	for i, b := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = run(b)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+
+	var merged neo4j.EagerResult
+	for _, res := range results {
+		if merged.Keys == nil {
+			merged.Keys = res.Keys
+		}
+		if sumCounts(&merged, res) {
+			continue
+		}
+		merged.Records = append(merged.Records, res.Records...)
+	}
+	if order, ok := gatherOrder[query]; ok {
+		sort.SliceStable(merged.Records, func(i, j int) bool {
+			a, b := order.key(merged.Records[i]), order.key(merged.Records[j])
+			if order.desc {
+				return a > b
+			}
+			return a < b
+		})
+	}
+	return merged, nil
+}
+
+// recordOrder is how a query spanning groups orders its records.
+type recordOrder struct {
+	key  func(*neo4j.Record) string
+	desc bool
+}
+
+// gatherOrder lists the queries spanning groups whose order callers rely on.
+var gatherOrder = map[string]recordOrder{
+	ListGroupsQuery:      {key: recordKey("group_id")},
+	ListDeadLettersQuery: {key: recordKey("updated_at"), desc: true},
+	ExportNodesQuery:     {key: propertyKey("uuid")},
+	ExportEdgesQuery:     {key: propertyKey("uuid")},
+}
+
+func recordKey(key string) func(*neo4j.Record) string {
+	return func(rec *neo4j.Record) string {
+		v, _ := rec.Get(key)
+		return fmt.Sprint(v)
+	}
+}
+
+func propertyKey(key string) func(*neo4j.Record) string {
+	return func(rec *neo4j.Record) string {
+		props, _ := rec.Get("properties")
+		m, _ := props.(map[string]interface{})
+		return fmt.Sprint(m[key])
+	}
+}
+
+// sumCounts adds the count in res to the one in merged when both are a
+// single count record.
+func sumCounts(merged *neo4j.EagerResult, res neo4j.EagerResult) bool {
+	if len(merged.Records) != 1 || len(res.Records) != 1 {
+		return false
+	}
+	a, b := merged.Records[0], res.Records[0]
+	if len(a.Keys) != 1 || len(b.Keys) != 1 || a.Keys[0] != b.Keys[0] || !countKeys[a.Keys[0]] {
+		return false
+	}
+	x, okA := toInt64(a.Values[0])
+	y, okB := toInt64(b.Values[0])
+	if !okA || !okB {
+		return false
+	}
+	merged.Records[0] = &neo4j.Record{Keys: a.Keys, Values: []any{x + y}}
+	return true
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// importByGroup splits ImportNodesQuery by the group_id property of each
+// node, importing every batch on the backend owning its group.
+func importByGroup(ctx context.Context, backend func(groupID string) GraphDriver, params map[string]interface{}) (neo4j.EagerResult, error) {
+	var order []GraphDriver
+	batches := make(map[GraphDriver][]map[string]interface{})
+	nodes, _ := params["nodes"].([]map[string]interface{})
+	for _, node := range nodes {
+		groupID := ""
+		if props, ok := node["properties"].(map[string]interface{}); ok {
+			groupID, _ = props["group_id"].(string)
+		}
+		b := backend(groupID)
+		if _, ok := batches[b]; !ok {
+			order = append(order, b)
+		}
+		batches[b] = append(batches[b], node)
+	}
+
+	imported := int64(0)
+	for _, b := range order {
+		res, err := b.ExecuteQuery(ctx, ImportNodesQuery, map[string]interface{}{"nodes": batches[b]})
+		if err != nil {
+			return neo4j.EagerResult{}, err
+		}
+		if len(res.Records) > 0 {
+			if n, ok := toInt64(res.Records[0].Values[0]); ok {
+				imported += n
+			}
+		}
+	}
+	keys := []string{"imported"}
+	return neo4j.EagerResult{Keys: keys, Records: []*neo4j.Record{{Keys: keys, Values: []any{imported}}}}, nil
+}
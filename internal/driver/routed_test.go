@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutedDriver(t *testing.T) {
+	ctx := context.Background()
+	def, big, vip := NewMemoryDriver(), NewMemoryDriver(), NewMemoryDriver()
+	d := NewRoutedDriver(def, []Route{{Prefix: "big-", Driver: big}, {Prefix: "big-vip-", Driver: vip}})
+
+	for _, g := range []struct{ group, uuid string }{{"small", "a"}, {"big-1", "b"}, {"big-vip-1", "c"}} {
+		_, err := d.ExecuteQuery(ctx, EnsureGroupQuery, map[string]interface{}{"group_id": g.group, "created_at": "2024-01-01T00:00:00Z"})
+		require.NoError(t, err)
+		_, err = d.ExecuteQuery(ctx, SaveEntityNodeQuery, map[string]interface{}{
+			"uuid": g.uuid, "name": g.uuid, "group_id": g.group, "summary": "", "labels": []string{"Entity"},
+		})
+		require.NoError(t, err)
+	}
+
+	// Each group lives on the backend of its longest matching prefix only
+	for _, c := range []struct {
+		backend *MemoryDriver
+		group   string
+	}{{def, "small"}, {big, "big-1"}, {vip, "big-vip-1"}} {
+		res, err := c.backend.ExecuteReadQuery(ctx, ListGroupsQuery, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Records, 1)
+		groupID, _ := res.Records[0].Get("group_id")
+		assert.Equal(t, c.group, groupID)
+	}
+	res, err := d.ExecuteReadQuery(ctx, GetGroupNodesQuery, map[string]interface{}{"group_id": "big-1"})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 1)
+
+	// Queries naming no group run everywhere
+	res, err = d.ExecuteReadQuery(ctx, ListGroupsQuery, nil)
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 3)
+	res, err = d.ExecuteReadQuery(ctx, GetEntityNodeQuery, map[string]interface{}{"uuid": "c"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	groupID, _ := res.Records[0].Get("group_id")
+	assert.Equal(t, "big-vip-1", groupID)
+
+	// Imports are split by group and counts summed
+	res, err = d.ExecuteQuery(ctx, ImportNodesQuery, map[string]interface{}{"nodes": []map[string]interface{}{
+		{"labels": []string{"Entity"}, "properties": map[string]interface{}{"uuid": "d", "group_id": "big-2"}},
+		{"labels": []string{"Entity"}, "properties": map[string]interface{}{"uuid": "e", "group_id": "other"}},
+	}})
+	require.NoError(t, err)
+	imported, _ := res.Records[0].Get("imported")
+	assert.EqualValues(t, 2, imported)
+	res, err = big.ExecuteReadQuery(ctx, GetEntityNodeQuery, map[string]interface{}{"uuid": "d"})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 1)
+
+	res, err = d.ExecuteQuery(ctx, ClearGraphQuery, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	deleted, _ := res.Records[0].Get("deleted")
+	assert.EqualValues(t, 8, deleted)
+
+	applied, err := Migrate(ctx, d, Migrations)
+	require.NoError(t, err)
+	latest := Migrations[len(Migrations)-1].Version
+	assert.Len(t, applied, len(Migrations))
+	for _, b := range d.Backends() {
+		version, _, err := SchemaVersion(ctx, b)
+		require.NoError(t, err)
+		assert.Equal(t, latest, version)
+	}
+}
@@ -0,0 +1,114 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Shard is one of the backends of a ShardedDriver. Name decides which groups
+// it holds, so it must stay the same when shards are added.
+type Shard struct {
+	Name   string
+	Driver GraphDriver
+}
+
+// ShardedDriver spreads groups over several backends by hashing their
+// group_id, scaling writes out horizontally. It uses rendezvous hashing: a
+// group lives on the shard whose name hashes highest together with its
+// group_id, so adding a shard only moves the groups it now wins. Queries are
+// routed and gathered like those of a RoutedDriver.
+type ShardedDriver struct {
+	Shards []Shard
+}
+
+func NewShardedDriver(shards []Shard) (*ShardedDriver, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("a sharded driver needs at least one shard")
+	}
+	seen := make(map[string]bool, len(shards))
+	for _, s := range shards {
+		if seen[s.Name] {
+			return nil, fmt.Errorf("shard name %q is used twice", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return &ShardedDriver{Shards: shards}, nil
+}
+
+// Backend returns the driver holding groupID.
+func (d *ShardedDriver) Backend(groupID string) GraphDriver {
+	best, bestScore := 0, uint64(0)
+	for i, s := range d.Shards {
+		if score := shardScore(s.Name, groupID); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return d.Shards[best].Driver
+}
+
+// shardScore hashes a shard name with a group_id.
+func shardScore(shard, groupID string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(shard))
+	h.Write([]byte{0})
+	h.Write([]byte(groupID))
+	// FNV alone spreads similar inputs poorly; finish with splitmix64.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Backends returns the driver of every shard.
+func (d *ShardedDriver) Backends() []GraphDriver {
+	backends := make([]GraphDriver, len(d.Shards))
+	for i, s := range d.Shards {
+		backends[i] = s.Driver
+	}
+	return backends
+}
+
+func (d *ShardedDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if query == ImportNodesQuery {
+		return importByGroup(ctx, d.Backend, params)
+	}
+	if groupID, ok := params["group_id"].(string); ok {
+		return d.Backend(groupID).ExecuteQuery(ctx, query, params)
+	}
+	return scatter(ctx, query, d.Backends(), func(b GraphDriver) (neo4j.EagerResult, error) {
+		return b.ExecuteQuery(ctx, query, params)
+	})
+}
+
+func (d *ShardedDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if groupID, ok := params["group_id"].(string); ok {
+		return d.Backend(groupID).ExecuteReadQuery(ctx, query, params)
+	}
+	return scatter(ctx, query, d.Backends(), func(b GraphDriver) (neo4j.EagerResult, error) {
+		return b.ExecuteReadQuery(ctx, query, params)
+	})
+}
+
+func (d *ShardedDriver) BuildIndices(ctx context.Context) error {
+	for _, b := range d.Backends() {
+		if err := b.BuildIndices(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *ShardedDriver) Close(ctx context.Context) error {
+	var errs []error
+	for _, b := range d.Backends() {
+		errs = append(errs, b.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
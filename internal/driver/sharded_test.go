@@ -0,0 +1,87 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedDriver_Placement(t *testing.T) {
+	shards := []Shard{{"a", NewMemoryDriver()}, {"b", NewMemoryDriver()}, {"c", NewMemoryDriver()}}
+	d, err := NewShardedDriver(shards)
+	require.NoError(t, err)
+
+	counts := map[GraphDriver]int{}
+	for i := 0; i < 3000; i++ {
+		counts[d.Backend(fmt.Sprintf("group-%d", i))]++
+	}
+	for _, s := range shards {
+		assert.InDelta(t, 1000, counts[s.Driver], 150, s.Name)
+	}
+
+	// A new shard only takes groups; none move between the old ones
+	grown, err := NewShardedDriver(append(shards, Shard{"d", NewMemoryDriver()}))
+	require.NoError(t, err)
+	moved := 0
+	for i := 0; i < 3000; i++ {
+		group := fmt.Sprintf("group-%d", i)
+		if before, after := d.Backend(group), grown.Backend(group); before != after {
+			assert.Same(t, grown.Shards[3].Driver, after)
+			moved++
+		}
+	}
+	assert.InDelta(t, 750, moved, 150)
+
+	_, err = NewShardedDriver([]Shard{{"a", NewMemoryDriver()}, {"a", NewMemoryDriver()}})
+	assert.Error(t, err)
+}
+
+func TestShardedDriver_ScatterGather(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewShardedDriver([]Shard{{"a", NewMemoryDriver()}, {"b", NewMemoryDriver()}, {"c", NewMemoryDriver()}})
+	require.NoError(t, err)
+
+	var groups []string
+	for i := 0; i < 12; i++ {
+		group := fmt.Sprintf("g%02d", i)
+		groups = append(groups, group)
+		_, err := d.ExecuteQuery(ctx, EnsureGroupQuery, map[string]interface{}{"group_id": group, "created_at": "2024-01-01T00:00:00Z"})
+		require.NoError(t, err)
+		_, err = d.ExecuteQuery(ctx, SaveDeadLetterQuery, map[string]interface{}{
+			"uuid": "dl-" + group, "group_id": group, "name": "ep", "episode": "{}", "error": "boom",
+			"updated_at": fmt.Sprintf("2024-01-%02dT00:00:00Z", i+1),
+		})
+		require.NoError(t, err)
+	}
+
+	// Listings across groups come back in their query's order
+	res, err := d.ExecuteReadQuery(ctx, ListGroupsQuery, nil)
+	require.NoError(t, err)
+	var listed []string
+	for _, rec := range res.Records {
+		g, _ := rec.Get("group_id")
+		listed = append(listed, g.(string))
+	}
+	assert.Equal(t, groups, listed)
+
+	res, err = d.ExecuteReadQuery(ctx, ListDeadLettersQuery, map[string]interface{}{"group_id": nil})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 12)
+	first, _ := res.Records[0].Get("group_id")
+	last, _ := res.Records[11].Get("group_id")
+	assert.Equal(t, "g11", first)
+	assert.Equal(t, "g00", last)
+
+	res, err = d.ExecuteReadQuery(ctx, ListDeadLettersQuery, map[string]interface{}{"group_id": "g03"})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 1)
+
+	res, err = d.ExecuteQuery(ctx, ClearGraphQuery, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	deleted, _ := res.Records[0].Get("deleted")
+	assert.EqualValues(t, 24, deleted)
+}
@@ -0,0 +1,155 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver persists the graph to a single SQLite file for deployments where
+// running Memgraph is overkill. Queries are served by an embedded MemoryDriver
+// (vector search is brute-force); every write is written through to SQLite and
+// the full graph is loaded back into memory on open.
+type SQLiteDriver struct {
+	*MemoryDriver
+	DB *sql.DB
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS nodes (
+	uuid       TEXT PRIMARY KEY,
+	labels     TEXT NOT NULL,
+	group_id   TEXT,
+	properties TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS edges (
+	uuid        TEXT PRIMARY KEY,
+	type        TEXT NOT NULL,
+	source_uuid TEXT NOT NULL,
+	target_uuid TEXT NOT NULL,
+	group_id    TEXT,
+	properties  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_nodes_group_id ON nodes(group_id);
+CREATE INDEX IF NOT EXISTS idx_edges_group_id ON edges(group_id);
+CREATE INDEX IF NOT EXISTS idx_edges_source ON edges(source_uuid);
+`
+
+func NewSQLiteDriver(path string) (*SQLiteDriver, error) {
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database '%s': %w", path, err)
+	}
+	// Writes are serialized by the MemoryDriver lock; a single connection avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+
+	d := &SQLiteDriver{MemoryDriver: NewMemoryDriver(), DB: db}
+	if err := d.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	d.MemoryDriver.store = d
+
+	log.Printf("Opened SQLite graph at %s (%d nodes, %d edges)", path, len(d.nodes), len(d.edges))
+	return d, nil
+}
+
+func (d *SQLiteDriver) Close(ctx context.Context) error {
+	return d.DB.Close()
+}
+
+func (d *SQLiteDriver) BuildIndices(ctx context.Context) error {
+	_, err := d.DB.ExecContext(ctx, sqliteSchema)
+	return err
+}
+
+func (d *SQLiteDriver) putNode(n *MemoryNode) error {
+	labels, err := json.Marshal(n.Labels)
+	if err != nil {
+		return err
+	}
+	props, err := json.Marshal(n.Props)
+	if err != nil {
+		return err
+	}
+	_, err = d.DB.Exec(`
+		INSERT INTO nodes (uuid, labels, group_id, properties) VALUES (?, ?, ?, ?)
+		ON CONFLICT(uuid) DO UPDATE SET labels = excluded.labels, group_id = excluded.group_id, properties = excluded.properties`,
+		n.UUID, string(labels), propString(n.Props, "group_id"), string(props))
+	return err
+}
+
+func (d *SQLiteDriver) putEdge(e *MemoryEdge) error {
+	props, err := json.Marshal(e.Props)
+	if err != nil {
+		return err
+	}
+	_, err = d.DB.Exec(`
+		INSERT INTO edges (uuid, type, source_uuid, target_uuid, group_id, properties) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(uuid) DO UPDATE SET type = excluded.type, source_uuid = excluded.source_uuid,
+			target_uuid = excluded.target_uuid, group_id = excluded.group_id, properties = excluded.properties`,
+		e.UUID, e.Type, e.SourceUUID, e.TargetUUID, propString(e.Props, "group_id"), string(props))
+	return err
+}
+
+func (d *SQLiteDriver) deleteEdge(uuid string) error {
+	_, err := d.DB.Exec(`DELETE FROM edges WHERE uuid = ?`, uuid)
+	return err
+}
+
+func (d *SQLiteDriver) deleteNode(uuid string) error {
+	_, err := d.DB.Exec(`DELETE FROM nodes WHERE uuid = ?`, uuid)
+	return err
+}
+
+func (d *SQLiteDriver) load() error {
+	rows, err := d.DB.Query(`SELECT uuid, labels, properties FROM nodes`)
+	if err != nil {
+		return fmt.Errorf("failed to load nodes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var uuid, labels, props string
+		if err := rows.Scan(&uuid, &labels, &props); err != nil {
+			return fmt.Errorf("failed to scan node: %w", err)
+		}
+		n := &MemoryNode{UUID: uuid}
+		if err := json.Unmarshal([]byte(labels), &n.Labels); err != nil {
+			return fmt.Errorf("failed to decode labels for node %s: %w", uuid, err)
+		}
+		if err := json.Unmarshal([]byte(props), &n.Props); err != nil {
+			return fmt.Errorf("failed to decode properties for node %s: %w", uuid, err)
+		}
+		d.nodes[uuid] = n
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	edgeRows, err := d.DB.Query(`SELECT uuid, type, source_uuid, target_uuid, properties FROM edges`)
+	if err != nil {
+		return fmt.Errorf("failed to load edges: %w", err)
+	}
+	defer edgeRows.Close()
+	for edgeRows.Next() {
+		e := &MemoryEdge{}
+		var props string
+		if err := edgeRows.Scan(&e.UUID, &e.Type, &e.SourceUUID, &e.TargetUUID, &props); err != nil {
+			return fmt.Errorf("failed to scan edge: %w", err)
+		}
+		if err := json.Unmarshal([]byte(props), &e.Props); err != nil {
+			return fmt.Errorf("failed to decode properties for edge %s: %w", e.UUID, err)
+		}
+		d.edges[e.UUID] = e
+	}
+	return edgeRows.Err()
+}
@@ -0,0 +1,82 @@
+package driver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDriver_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.db")
+	ctx := context.Background()
+
+	d, err := NewSQLiteDriver(path)
+	require.NoError(t, err)
+	for _, n := range []struct{ uuid, name string }{{"a", "Alice"}, {"b", "Bob"}} {
+		_, err := d.ExecuteQuery(ctx, SaveEntityNodeQuery, map[string]interface{}{
+			"uuid": n.uuid, "name": n.name, "group_id": "g1", "summary": "", "labels": []string{"Entity"},
+		})
+		require.NoError(t, err)
+	}
+	_, err = d.ExecuteQuery(ctx, SaveEntityEdgeQuery, map[string]interface{}{
+		"uuid": "e1", "source_uuid": "a", "target_uuid": "b", "name": "KNOWS", "fact": "Alice knows Bob",
+		"group_id": "g1", "invalid_at": "", "episodes": []string{"ep1"}, "fact_embedding": []float32{0.6, 0.8},
+	})
+	require.NoError(t, err)
+	require.NoError(t, d.Close(ctx))
+
+	d, err = NewSQLiteDriver(path)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+
+	res, err := d.ExecuteReadQuery(ctx, GetGroupNodesQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	assert.Len(t, res.Records, 2)
+
+	res, err = d.ExecuteReadQuery(ctx, SearchEdgesByVectorQuery, map[string]interface{}{"group_id": "g1", "embedding": []float32{0.6, 0.8}})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	score, _ := res.Records[0].Get("score")
+	assert.InDelta(t, 1.0, score, 1e-6)
+	episodes, _ := res.Records[0].Get("episodes")
+	assert.Equal(t, []interface{}{"ep1"}, episodes)
+}
+
+func TestSQLiteDriver_DeletesEdgesAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.db")
+	ctx := context.Background()
+
+	d, err := NewSQLiteDriver(path)
+	require.NoError(t, err)
+	for _, uuid := range []string{"a", "b"} {
+		_, err := d.ExecuteQuery(ctx, SaveEntityNodeQuery, map[string]interface{}{"uuid": uuid, "name": uuid, "group_id": "g1"})
+		require.NoError(t, err)
+	}
+	for _, uuid := range []string{"e1", "e2"} {
+		_, err := d.ExecuteQuery(ctx, SaveEntityEdgeQuery, map[string]interface{}{
+			"uuid": uuid, "source_uuid": "a", "target_uuid": "b", "name": "KNOWS", "fact": "a knows b",
+			"group_id": "g1", "invalid_at": "", "episodes": []string{"ep-" + uuid},
+		})
+		require.NoError(t, err)
+	}
+	_, err = d.ExecuteQuery(ctx, DeleteEntityEdgeQuery, map[string]interface{}{"uuid": "e2"})
+	require.NoError(t, err)
+	_, err = d.ExecuteQuery(ctx, SetEdgeEpisodesQuery, map[string]interface{}{"uuid": "e1", "episodes": []string{"ep-e1", "ep-e2"}})
+	require.NoError(t, err)
+	require.NoError(t, d.Close(ctx))
+
+	d, err = NewSQLiteDriver(path)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+
+	res, err := d.ExecuteReadQuery(ctx, GetGroupEdgeEpisodesQuery, map[string]interface{}{"group_id": "g1"})
+	require.NoError(t, err)
+	require.Len(t, res.Records, 1)
+	uuid, _ := res.Records[0].Get("uuid")
+	assert.Equal(t, "e1", uuid)
+	episodes, _ := res.Records[0].Get("episodes")
+	assert.Equal(t, []interface{}{"ep-e1", "ep-e2"}, episodes)
+}
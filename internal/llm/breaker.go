@@ -0,0 +1,216 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+// ErrCircuitOpen is returned without calling the provider while the breaker is open.
+var ErrCircuitOpen = errors.New("llm circuit breaker is open")
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// CircuitBreaker wraps the clients of one provider endpoint. When the share of
+// failed calls in a window reaches the configured rate, calls fail with
+// ErrCircuitOpen for the cooldown; then a few probe calls are let through and
+// the breaker closes once they all succeed, or opens again on the first failure.
+// Calls ended by their own context, and malformed JSON output, don't count as
+// failures: the provider did answer.
+type CircuitBreaker struct {
+	LLM      LLMClient
+	Embedder EmbedderClient
+	state    *breakerState
+}
+
+type breakerState struct {
+	mu  sync.Mutex
+	cfg config.BreakerConfig
+	now func() time.Time
+
+	state       string
+	generation  int // Bumped on every transition so calls admitted before it are ignored
+	windowStart time.Time
+	calls       int
+	failures    int
+	openedAt    time.Time
+	probes      int // Probe calls in flight while half-open
+	successes   int // Successful probes while half-open
+}
+
+// NewCircuitBreaker wraps llmClient and embedder (either may be nil) in one
+// breaker, filling in the defaults of cfg.
+func NewCircuitBreaker(llmClient LLMClient, embedder EmbedderClient, cfg config.BreakerConfig) *CircuitBreaker {
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.WindowSeconds <= 0 {
+		cfg.WindowSeconds = 60
+	}
+	if cfg.CooldownSeconds <= 0 {
+		cfg.CooldownSeconds = 30
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{
+		LLM:      llmClient,
+		Embedder: embedder,
+		state:    &breakerState{cfg: cfg, now: time.Now, state: BreakerClosed},
+	}
+}
+
+// State returns BreakerClosed, BreakerOpen or BreakerHalfOpen.
+func (b *CircuitBreaker) State() string {
+	s := b.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == BreakerOpen && s.cooledDown() {
+		return BreakerHalfOpen
+	}
+	return s.state
+}
+
+func (b *CircuitBreaker) Generate(ctx context.Context, prompt string) (string, error) {
+	gen, err := b.state.acquire()
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.LLM.Generate(ctx, prompt)
+	b.state.release(ctx, gen, err)
+	return resp, err
+}
+
+func (b *CircuitBreaker) Embed(ctx context.Context, text string) ([]float32, error) {
+	if b.Embedder == nil {
+		return nil, fmt.Errorf("no embedder configured")
+	}
+	gen, err := b.state.acquire()
+	if err != nil {
+		return nil, err
+	}
+	vec, err := b.Embedder.Embed(ctx, text)
+	b.state.release(ctx, gen, err)
+	return vec, err
+}
+
+// EmbedBatch embeds texts through the breaker as one call, batched when the
+// wrapped embedder supports it.
+func (b *CircuitBreaker) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if b.Embedder == nil {
+		return nil, fmt.Errorf("no embedder configured")
+	}
+	gen, err := b.state.acquire()
+	if err != nil {
+		return nil, err
+	}
+	vecs, err := EmbedAll(ctx, b.Embedder, texts)
+	b.state.release(ctx, gen, err)
+	return vecs, err
+}
+
+// WithModel returns a breaker for another model of the same provider. It
+// shares this breaker's state, since both talk to the same endpoint.
+func (b *CircuitBreaker) WithModel(model string) LLMClient {
+	overrider, ok := b.LLM.(ModelOverrider)
+	if !ok {
+		return b
+	}
+	return &CircuitBreaker{LLM: overrider.WithModel(model), Embedder: b.Embedder, state: b.state}
+}
+
+// CountTokens counts with the wrapped client's tokenizer, without going
+// through the breaker, or estimates when it has none.
+func (b *CircuitBreaker) CountTokens(text string) int {
+	return CountTokens(b.LLM, text)
+}
+
+// ForOperation returns a breaker for the client tuned for op, sharing this breaker's state.
+func (b *CircuitBreaker) ForOperation(op string) LLMClient {
+	return &CircuitBreaker{LLM: ForOperation(b.LLM, op), Embedder: b.Embedder, state: b.state}
+}
+
+func (s *breakerState) cooledDown() bool {
+	return s.now().Sub(s.openedAt) >= time.Duration(s.cfg.CooldownSeconds)*time.Second
+}
+
+// acquire admits a call, returning the generation to release it with, or
+// returns ErrCircuitOpen.
+func (s *breakerState) acquire() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == BreakerOpen {
+		if !s.cooledDown() {
+			return 0, ErrCircuitOpen
+		}
+		s.transition(BreakerHalfOpen)
+	}
+	if s.state == BreakerHalfOpen {
+		if s.probes+s.successes >= s.cfg.HalfOpenProbes {
+			return 0, ErrCircuitOpen
+		}
+		s.probes++
+	}
+	return s.generation, nil
+}
+
+// release records the outcome of a call admitted by acquire.
+func (s *breakerState) release(ctx context.Context, gen int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.generation {
+		return
+	}
+	failed := err != nil && ctx.Err() == nil && !errors.Is(err, ErrMalformedJSON)
+
+	switch s.state {
+	case BreakerHalfOpen:
+		s.probes--
+		if failed {
+			log.Printf("LLM circuit breaker reopened, probe failed: %v", err)
+			s.transition(BreakerOpen)
+		} else if err == nil {
+			if s.successes++; s.successes >= s.cfg.HalfOpenProbes {
+				s.transition(BreakerClosed)
+			}
+		}
+	case BreakerClosed:
+		if err != nil && !failed {
+			return
+		}
+		now := s.now()
+		if now.Sub(s.windowStart) >= time.Duration(s.cfg.WindowSeconds)*time.Second {
+			s.windowStart, s.calls, s.failures = now, 0, 0
+		}
+		s.calls++
+		if failed {
+			s.failures++
+		}
+		if s.calls >= s.cfg.MinRequests && float64(s.failures) >= s.cfg.FailureRate*float64(s.calls) {
+			log.Printf("LLM circuit breaker opened: %d of %d calls failed, last error: %v", s.failures, s.calls, err)
+			s.transition(BreakerOpen)
+		}
+	}
+}
+
+func (s *breakerState) transition(state string) {
+	s.state = state
+	s.generation++
+	s.probes, s.successes = 0, 0
+	switch state {
+	case BreakerOpen:
+		s.openedAt = s.now()
+	case BreakerClosed:
+		s.windowStart, s.calls, s.failures = s.now(), 0, 0
+		log.Printf("LLM circuit breaker closed")
+	}
+}
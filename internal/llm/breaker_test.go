@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type switchLLM struct {
+	err   error
+	calls int
+}
+
+func (s *switchLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	s.calls++
+	return "ok", s.err
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	inner := &switchLLM{}
+	b := NewCircuitBreaker(inner, nil, config.BreakerConfig{FailureRate: 0.5, MinRequests: 4, CooldownSeconds: 30, HalfOpenProbes: 2})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.state.now = func() time.Time { return now }
+
+	// Two failures in four calls trip it
+	for _, err := range []error{nil, errors.New("timeout"), nil, errors.New("timeout")} {
+		inner.err = err
+		b.Generate(ctx, "p")
+	}
+	assert.Equal(t, BreakerOpen, b.State())
+	_, err := b.Generate(ctx, "p")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 4, inner.calls)
+
+	// A failed probe reopens it
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	_, err = b.Generate(ctx, "p")
+	assert.EqualError(t, err, "timeout")
+	_, err = b.Generate(ctx, "p")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// Two successful probes close it
+	now = now.Add(30 * time.Second)
+	inner.err = nil
+	for i := 0; i < 2; i++ {
+		_, err = b.Generate(ctx, "p")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, BreakerClosed, b.State())
+
+	// Calls cancelled by the caller don't count
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	inner.err = context.Canceled
+	for i := 0; i < 4; i++ {
+		b.Generate(cancelled, "p")
+	}
+	assert.Equal(t, BreakerClosed, b.State())
+
+	// Failures spread over windows don't trip it
+	inner.err = errors.New("timeout")
+	for i := 0; i < 6; i++ {
+		now = now.Add(20 * time.Second)
+		if i%2 == 0 {
+			b.Generate(ctx, "p")
+		} else {
+			inner.err = nil
+			b.Generate(ctx, "p")
+			inner.err = errors.New("timeout")
+		}
+	}
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+// wordCounter counts a token per word.
+type wordCounter struct{ switchLLM }
+
+func (w *wordCounter) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestCircuitBreaker_CountTokens(t *testing.T) {
+	text := "one two three four five six seven eight"
+	b := NewCircuitBreaker(&wordCounter{}, nil, config.BreakerConfig{})
+	assert.Equal(t, 8, CountTokens(b, text))
+	assert.Equal(t, 8, CountTokens(ForOperation(b, OpExtraction), text))
+
+	// Clients without a tokenizer are estimated
+	b = NewCircuitBreaker(&switchLLM{}, nil, config.BreakerConfig{})
+	assert.Equal(t, EstimateTokens(text), CountTokens(b, text))
+}
@@ -58,3 +58,11 @@ func (c *ClaudeClient) Embed(ctx context.Context, text string) ([]float32, error
 	// If returned error, graphiti logic skips embedding (which is desired).
 	return nil, fmt.Errorf("embeddings not supported by Claude client")
 }
+
+// WithModel returns a client sharing the same connection but generating with model.
+func (c *ClaudeClient) WithModel(model string) LLMClient {
+	return &ClaudeClient{
+		client: c.client,
+		model:  model,
+	}
+}
@@ -2,16 +2,75 @@ package llm
 
 import (
 	"context"
+	"fmt"
 )
 
 type LLMClient interface {
 	Generate(ctx context.Context, prompt string) (string, error)
 }
 
+// ModelOverrider is implemented by clients that can serve the same provider with a different model.
+type ModelOverrider interface {
+	WithModel(model string) LLMClient
+}
+
+// Operations the engine calls the LLM for, as named in [llm.operations].
+const (
+	OpExtraction    = "extraction"
+	OpDeduplication = "deduplication"
+	OpSummary       = "summary"
+	OpRerank        = "rerank"
+)
+
+// OperationOverrider is implemented by clients that tune generation per operation.
+type OperationOverrider interface {
+	ForOperation(op string) LLMClient
+}
+
+// ForOperation returns c tuned for op, or c itself when it has no per-operation settings.
+func ForOperation(c LLMClient, op string) LLMClient {
+	if o, ok := c.(OperationOverrider); ok {
+		return o.ForOperation(op)
+	}
+	return c
+}
+
 type EmbedderClient interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
 
+// BatchEmbedder is implemented by embedders that can embed several texts in one request.
+type BatchEmbedder interface {
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedAll embeds texts with e in one request when it supports batches, or
+// one text at a time otherwise. Vectors are returned in the order of texts.
+func EmbedAll(ctx context.Context, e EmbedderClient, texts []string) ([][]float32, error) {
+	if b, ok := e.(BatchEmbedder); ok {
+		vecs, err := b.EmbedBatch(ctx, texts)
+		if err == nil && len(vecs) != len(texts) {
+			err = fmt.Errorf("got %d embeddings for %d texts", len(vecs), len(texts))
+		}
+		return vecs, err
+	}
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = vec
+	}
+	return vecs, nil
+}
+
+// NamedEmbedder is an embedder together with the model name its vectors are tagged with.
+type NamedEmbedder struct {
+	Name string
+	EmbedderClient
+}
+
 type RerankerClient interface {
 	Rank(ctx context.Context, query string, documents []string) ([]int, error)
 }
@@ -8,6 +8,47 @@ import (
 )
 
 func NewClient(ctx context.Context, cfg config.LLMConfig) (LLMClient, EmbedderClient, error) {
+	switch strings.ToLower(cfg.ReplayMode) {
+	case "":
+		return newBreakerClient(ctx, cfg)
+	case "replay":
+		c, err := NewReplayClient(cfg.ReplayDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, c, nil
+	case "record":
+		l, e, err := newBreakerClient(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		c, err := NewRecordingClient(l, e, cfg.ReplayDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if e == nil {
+			return c, nil, nil
+		}
+		return c, c, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported llm replay mode: %s", cfg.ReplayMode)
+	}
+}
+
+// newBreakerClient is newProviderClient wrapped in a CircuitBreaker when [llm.breaker] is enabled.
+func newBreakerClient(ctx context.Context, cfg config.LLMConfig) (LLMClient, EmbedderClient, error) {
+	l, e, err := newProviderClient(ctx, cfg)
+	if err != nil || cfg.Breaker.FailureRate <= 0 {
+		return l, e, err
+	}
+	b := NewCircuitBreaker(l, e, cfg.Breaker)
+	if e == nil {
+		return b, nil, nil
+	}
+	return b, b, nil
+}
+
+func newProviderClient(ctx context.Context, cfg config.LLMConfig) (LLMClient, EmbedderClient, error) {
 	provider := strings.ToLower(cfg.Provider)
 	
 	switch provider {
@@ -27,25 +68,40 @@ func NewClient(ctx context.Context, cfg config.LLMConfig) (LLMClient, EmbedderCl
 		return c, nil, nil // Return nil for EmbedderClient so application knows it's not supported
 	
 	case "ollama":
-		// Switch to OpenAI-compatible client for Ollama to enable usage tracking
-		baseURL := cfg.BaseURL
-		if !strings.HasSuffix(baseURL, "/v1") {
-			baseURL = fmt.Sprintf("%s/v1", strings.TrimRight(baseURL, "/"))
-		}
-		
-		fmt.Printf("Initializing Ollama via OpenAI-compatible API at %s (enables usage tracking)\n", baseURL)
-		
-		// Create OpenAI client pointing to Ollama
-		// Note: API Key is ignored by Ollama but required by client config (can be dummy)
-		apiKey := cfg.APIKey
-		if apiKey == "" {
-			apiKey = "ollama" // Dummy key
-		}
-		
-		c := NewOpenAIClient(apiKey, cfg.Model, cfg.EmbeddingModel, baseURL)
+		// Native API: streaming with JSON early-abort, and keep_alive/num_ctx/temperature per operation
+		c := NewOllamaClient(cfg)
+		fmt.Printf("Initializing Ollama at %s\n", c.BaseURL)
 		return c, c, nil
 		
 	default:
 		return nil, nil, fmt.Errorf("unsupported llm provider: %s", provider)
 	}
 }
+
+// NewEmbedders creates the embedders listed in an [embedding] config, in order.
+func NewEmbedders(ctx context.Context, cfgs []config.EmbedderConfig) ([]NamedEmbedder, error) {
+	embedders := make([]NamedEmbedder, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		name := cfg.Name
+		if name == "" {
+			name = cfg.Model
+		}
+		if name == "" {
+			return nil, fmt.Errorf("embedder for provider %s needs a name or model", cfg.Provider)
+		}
+		_, e, err := newProviderClient(ctx, config.LLMConfig{
+			Provider:       cfg.Provider,
+			EmbeddingModel: cfg.Model,
+			APIKey:         cfg.APIKey,
+			BaseURL:        cfg.BaseURL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create embedder %s: %w", name, err)
+		}
+		if e == nil {
+			return nil, fmt.Errorf("provider %s of embedder %s does not support embeddings", cfg.Provider, name)
+		}
+		embedders = append(embedders, NamedEmbedder{Name: name, EmbedderClient: e})
+	}
+	return embedders, nil
+}
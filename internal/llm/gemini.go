@@ -60,3 +60,12 @@ func (c *GeminiClient) Embed(ctx context.Context, text string) ([]float32, error
 	}
 	return nil, fmt.Errorf("no embedding values")
 }
+
+// WithModel returns a client sharing the same connection but generating with model.
+func (c *GeminiClient) WithModel(model string) LLMClient {
+	return &GeminiClient{
+		client:         c.client,
+		model:          model,
+		embeddingModel: c.embeddingModel,
+	}
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Moderator checks text for disallowed content and returns the categories it
+// falls under, none for acceptable text.
+type Moderator interface {
+	Moderate(ctx context.Context, text string) ([]string, error)
+}
+
+// OpenAIModerator is a Moderator backed by the OpenAI moderation API.
+type OpenAIModerator struct {
+	client *openai.Client
+	model  string
+}
+
+func NewOpenAIModerator(apiKey, model, baseURL string) *OpenAIModerator {
+	config := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	if model == "" {
+		model = openai.ModerationOmniLatest
+	}
+	return &OpenAIModerator{client: openai.NewClientWithConfig(config), model: model}
+}
+
+func (m *OpenAIModerator) Moderate(ctx context.Context, text string) ([]string, error) {
+	resp, err := m.client.Moderations(ctx, openai.ModerationRequest{Input: text, Model: m.model})
+	if err != nil {
+		return nil, err
+	}
+	var categories []string
+	for _, r := range resp.Results {
+		if !r.Flagged {
+			continue
+		}
+		// Categories is a struct of booleans named by their JSON tags ("self-harm", ...)
+		b, err := json.Marshal(r.Categories)
+		if err != nil {
+			return nil, err
+		}
+		var flags map[string]bool
+		if err := json.Unmarshal(b, &flags); err != nil {
+			return nil, err
+		}
+		for category, on := range flags {
+			if on {
+				categories = append(categories, category)
+			}
+		}
+		if len(categories) == 0 {
+			categories = append(categories, "flagged")
+		}
+	}
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("no moderation results")
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
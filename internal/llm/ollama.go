@@ -1,50 +1,308 @@
 package llm
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
-	"github.com/XiaoConstantine/dspy-go/pkg/core"
-	"github.com/XiaoConstantine/dspy-go/pkg/llms"
+	"github.com/agenthands/carbon/internal/config"
 )
 
+// ErrMalformedJSON is returned when a streamed response that must be JSON
+// stops being valid JSON; generation is aborted at that point.
+var ErrMalformedJSON = errors.New("model output is not valid JSON")
+
+// jsonOperations expect a JSON object back, so their responses are generated
+// in Ollama's JSON mode and checked while streaming.
+var jsonOperations = map[string]bool{OpExtraction: true, OpDeduplication: true, OpSummary: true}
+
+// maxJSONWhitespace ends JSON-mode generations stuck emitting whitespace, a
+// known failure mode of small models.
+const maxJSONWhitespace = 512
+
+// OllamaClient talks to Ollama's native API, streaming generations so that
+// malformed JSON aborts early and a finished JSON object ends the call.
 type OllamaClient struct {
-	llm *llms.OllamaLLM
+	BaseURL        string
+	Model          string
+	EmbeddingModel string // Default Model
+	Options        config.LLMOptions
+	Operations     map[string]config.LLMOptions
+	Client         *http.Client // Default http.DefaultClient
+
+	op string
 }
 
-func NewOllamaClient(modelName string, baseURL string) (*OllamaClient, error) {
-	// Initialize Ollama LLM via DSPy
-	// Assuming dspy-go's NewOllamaLLM usage based on earlier successful read
-	// We'll use the "modern" OpenAI-compatible mode by default as per dspy-go code
-    // Model ID needs to be cast to core.ModelID
-	
-	opts := []llms.OllamaOption{
-		llms.WithBaseURL(baseURL),
-		llms.WithOpenAIAPI(), // Use OpenAI compatible API
+func NewOllamaClient(cfg config.LLMConfig) *OllamaClient {
+	baseURL := strings.TrimSuffix(strings.TrimRight(cfg.BaseURL, "/"), "/v1")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
 	}
+	return &OllamaClient{
+		BaseURL:        baseURL,
+		Model:          cfg.Model,
+		EmbeddingModel: cfg.EmbeddingModel,
+		Options:        cfg.Options,
+		Operations:     cfg.Operations,
+	}
+}
 
-	ollamaLLM, err := llms.NewOllamaLLM(core.ModelID(modelName), opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ollama llm: %w", err)
+// WithModel returns a client generating with model, keeping the operation settings.
+func (c *OllamaClient) WithModel(model string) LLMClient {
+	clone := *c
+	clone.Model = model
+	return &clone
+}
+
+// ForOperation returns a client applying op's [llm.operations] settings over the defaults.
+func (c *OllamaClient) ForOperation(op string) LLMClient {
+	clone := *c
+	clone.op = op
+	return &clone
+}
+
+func (c *OllamaClient) options() config.LLMOptions {
+	opts := c.Options
+	override, ok := c.Operations[c.op]
+	if !ok {
+		return opts
+	}
+	if override.KeepAlive != "" {
+		opts.KeepAlive = override.KeepAlive
 	}
+	if override.NumCtx > 0 {
+		opts.NumCtx = override.NumCtx
+	}
+	if override.Temperature != nil {
+		opts.Temperature = override.Temperature
+	}
+	return opts
+}
 
-	return &OllamaClient{llm: ollamaLLM}, nil
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model     string                 `json:"model"`
+	Messages  []ollamaMessage        `json:"messages"`
+	Stream    bool                   `json:"stream"`
+	Format    string                 `json:"format,omitempty"`
+	KeepAlive string                 `json:"keep_alive,omitempty"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+}
+
+type ollamaChatChunk struct {
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	Error           string        `json:"error"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
 }
 
 func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, error) {
-	response, err := c.llm.Generate(ctx, prompt)
+	opts := c.options()
+	req := ollamaChatRequest{
+		Model:     c.Model,
+		Messages:  []ollamaMessage{{Role: "user", Content: prompt}},
+		Stream:    true,
+		KeepAlive: opts.KeepAlive,
+		Options:   map[string]interface{}{},
+	}
+	if opts.NumCtx > 0 {
+		req.Options["num_ctx"] = opts.NumCtx
+	}
+	if opts.Temperature != nil {
+		req.Options["temperature"] = *opts.Temperature
+	}
+	var check *jsonStreamCheck
+	if jsonOperations[c.op] {
+		req.Format = "json"
+		check = &jsonStreamCheck{}
+	}
+
+	// Cancelling ends the stream early; Ollama stops generating once the connection closes
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resp, err := c.post(ctx, "/api/chat", req)
 	if err != nil {
 		return "", err
 	}
-	return response.Content, nil
+	defer resp.Body.Close()
+
+	var out strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var chunk ollamaChatChunk
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return "", fmt.Errorf("invalid ollama stream line: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		out.WriteString(chunk.Message.Content)
+		if chunk.Done {
+			if chunk.EvalCount > 0 {
+				fmt.Printf("LLM Usage: model=%s prompt=%d completion=%d total=%d\n",
+					c.Model, chunk.PromptEvalCount, chunk.EvalCount, chunk.PromptEvalCount+chunk.EvalCount)
+			}
+			return out.String(), nil
+		}
+		if check != nil {
+			complete, err := check.feed(chunk.Message.Content)
+			if err != nil {
+				return "", fmt.Errorf("%w after %d bytes: %v", ErrMalformedJSON, out.Len(), err)
+			}
+			if complete {
+				return out.String(), nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read ollama stream: %w", err)
+	}
+	return "", fmt.Errorf("ollama stream ended before completion")
+}
+
+type ollamaEmbedRequest struct {
+	Model     string      `json:"model"`
+	Input     interface{} `json:"input"` // A string, or a list of them for a batch
+	KeepAlive string      `json:"keep_alive,omitempty"`
 }
 
 func (c *OllamaClient) Embed(ctx context.Context, text string) ([]float32, error) {
-	// Using DSPy's CreateEmbedding
-	result, err := c.llm.CreateEmbedding(ctx, text)
+	vecs, err := c.embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) == 0 {
+		return nil, fmt.Errorf("no embedding data")
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch embeds texts in a single request.
+func (c *OllamaClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs, err := c.embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(vecs), len(texts))
+	}
+	return vecs, nil
+}
+
+func (c *OllamaClient) embed(ctx context.Context, input interface{}) ([][]float32, error) {
+	model := c.EmbeddingModel
+	if model == "" {
+		model = c.Model
+	}
+	resp, err := c.post(ctx, "/api/embed", ollamaEmbedRequest{Model: model, Input: input, KeepAlive: c.Options.KeepAlive})
 	if err != nil {
 		return nil, err
 	}
-	// DSPy returns []float32 directly
-	return result.Vector, nil
+	defer resp.Body.Close()
+	var body struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid ollama embedding response: %w", err)
+	}
+	return body.Embeddings, nil
+}
+
+// post sends body as JSON and returns the response, or an error for non-2xx statuses.
+func (c *OllamaClient) post(ctx context.Context, path string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("ollama returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// jsonStreamCheck follows the structure of streamed JSON text just far enough
+// to tell when it can no longer be valid, or when the top-level value is closed.
+type jsonStreamCheck struct {
+	started    bool
+	stack      []byte // Expected closing brackets
+	inString   bool
+	escaped    bool
+	whitespace int // Consecutive whitespace outside strings
+}
+
+// feed consumes the next chunk, reporting whether the top-level object or
+// array is complete, or an error once the text can't be valid JSON.
+func (j *jsonStreamCheck) feed(chunk string) (complete bool, err error) {
+	for i := 0; i < len(chunk); i++ {
+		c := chunk[i]
+		if j.inString {
+			switch {
+			case j.escaped:
+				j.escaped = false
+			case c == '\\':
+				j.escaped = true
+			case c == '"':
+				j.inString = false
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			if j.whitespace++; j.whitespace > maxJSONWhitespace {
+				return false, fmt.Errorf("runaway whitespace")
+			}
+			continue
+		}
+		j.whitespace = 0
+		if !j.started {
+			if c != '{' && c != '[' {
+				return false, fmt.Errorf("unexpected %q before the JSON value", c)
+			}
+			j.started = true
+		}
+		switch c {
+		case '"':
+			j.inString = true
+		case '{':
+			j.stack = append(j.stack, '}')
+		case '[':
+			j.stack = append(j.stack, ']')
+		case '}', ']':
+			if len(j.stack) == 0 || j.stack[len(j.stack)-1] != c {
+				return false, fmt.Errorf("unexpected %q", c)
+			}
+			j.stack = j.stack[:len(j.stack)-1]
+			if len(j.stack) == 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
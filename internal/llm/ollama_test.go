@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOllama streams each reply as one chunk per token, then a done line.
+func fakeOllama(t *testing.T, reply string, requests *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		if r.URL.Path == "/api/embed" {
+			fmt.Fprint(w, `{"embeddings": [[0.5, 0.25]]}`)
+			return
+		}
+		enc := json.NewEncoder(w)
+		for _, tok := range strings.SplitAfter(reply, " ") {
+			enc.Encode(map[string]interface{}{"message": map[string]string{"role": "assistant", "content": tok}, "done": false})
+		}
+		enc.Encode(map[string]interface{}{"done": true, "prompt_eval_count": 10, "eval_count": 5})
+	}))
+}
+
+func TestOllamaClient(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]interface{}
+	srv := fakeOllama(t, `{"extracted_entities": [{"name": "Alice"}]}`+strings.Repeat(" ", 20), &requests)
+	defer srv.Close()
+
+	cold := 0.0
+	c := NewOllamaClient(config.LLMConfig{
+		Model:      "llama3",
+		BaseURL:    srv.URL + "/v1",
+		Options:    config.LLMOptions{KeepAlive: "10m", NumCtx: 4096},
+		Operations: map[string]config.LLMOptions{OpExtraction: {NumCtx: 16384, Temperature: &cold}},
+	})
+	assert.Equal(t, srv.URL, c.BaseURL)
+
+	// JSON operations stop reading once the object is closed
+	resp, err := ForOperation(c, OpExtraction).Generate(ctx, "extract")
+	require.NoError(t, err)
+	assert.Equal(t, `{"extracted_entities": [{"name": "Alice"}]}`, strings.TrimSpace(resp))
+	assert.Equal(t, "json", requests[0]["format"])
+	assert.Equal(t, "10m", requests[0]["keep_alive"])
+	assert.Equal(t, map[string]interface{}{"num_ctx": 16384.0, "temperature": 0.0}, requests[0]["options"])
+
+	resp, err = ForOperation(c.WithModel("qwen"), OpRerank).Generate(ctx, "rank")
+	require.NoError(t, err)
+	assert.Contains(t, resp, "Alice")
+	assert.Equal(t, "qwen", requests[1]["model"])
+	assert.Nil(t, requests[1]["format"])
+	assert.Equal(t, map[string]interface{}{"num_ctx": 4096.0}, requests[1]["options"])
+
+	vec, err := c.Embed(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.5, 0.25}, vec)
+	assert.Equal(t, "llama3", requests[2]["model"])
+}
+
+func TestOllamaClientMalformedJSON(t *testing.T) {
+	var requests []map[string]interface{}
+	srv := fakeOllama(t, `Sure! Here are the entities: {"a": 1}`, &requests)
+	defer srv.Close()
+
+	c := NewOllamaClient(config.LLMConfig{Model: "llama3", BaseURL: srv.URL})
+	_, err := c.ForOperation(OpSummary).Generate(context.Background(), "summarize")
+	assert.ErrorIs(t, err, ErrMalformedJSON)
+}
+
+func TestJSONStreamCheck(t *testing.T) {
+	for _, tc := range []struct {
+		chunks   []string
+		complete bool
+		err      string
+	}{
+		{chunks: []string{`{"a": "}]\"`, `", "b": [1, {}]}`}, complete: true},
+		{chunks: []string{"\n [", "]"}, complete: true},
+		{chunks: []string{`{"a": [1}`}, err: "unexpected '}'"},
+		{chunks: []string{"```json\n{}"}, err: "unexpected '`' before the JSON value"},
+		{chunks: []string{`{"a":`, strings.Repeat("\n", maxJSONWhitespace+1)}, err: "runaway whitespace"},
+		{chunks: []string{`{"a": "unterminated`}},
+	} {
+		var check jsonStreamCheck
+		var complete bool
+		var err error
+		for _, chunk := range tc.chunks {
+			if complete, err = check.feed(chunk); complete || err != nil {
+				break
+			}
+		}
+		assert.Equal(t, tc.complete, complete, tc.chunks)
+		if tc.err == "" {
+			assert.NoError(t, err, tc.chunks)
+		} else {
+			assert.EqualError(t, err, tc.err, tc.chunks)
+		}
+	}
+}
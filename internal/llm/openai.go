@@ -82,3 +82,42 @@ func (c *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error
 	}
 	return nil, fmt.Errorf("no embedding data")
 }
+
+// EmbedBatch embeds texts in a single request.
+func (c *OpenAIClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	model := c.embeddingModel
+	if model == "" {
+		model = string(openai.SmallEmbedding3)
+	}
+	resp, err := c.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+		Input: texts,
+		Model: openai.EmbeddingModel(model),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if usage := resp.Usage; usage.TotalTokens > 0 {
+		fmt.Printf("LLM Usage (Embedding): model=%s prompt=%d total=%d\n",
+			model, usage.PromptTokens, usage.TotalTokens)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(resp.Data), len(texts))
+	}
+	vecs := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(vecs) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vecs[d.Index] = d.Embedding
+	}
+	return vecs, nil
+}
+
+// WithModel returns a client sharing the same connection but generating with model.
+func (c *OpenAIClient) WithModel(model string) LLMClient {
+	return &OpenAIClient{
+		client:         c.client,
+		model:          model,
+		embeddingModel: c.embeddingModel,
+	}
+}
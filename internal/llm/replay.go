@@ -0,0 +1,171 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Golden-file record/replay for deterministic tests.
+//
+// A RecordingClient forwards calls to a live client and writes each prompt and
+// response to Dir; a ReplayClient serves those files back without a model.
+// Entries are keyed by the prompt with UUIDs masked, because UUIDs are random
+// per run. The UUIDs a recorded response shares with its prompt are stored as
+// placeholders and replaced with the current prompt's UUIDs on replay, so
+// extracted edges still point at this run's nodes.
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// ErrNoRecording is returned by ReplayClient when no golden file matches a prompt.
+var ErrNoRecording = errors.New("no recorded response")
+
+type goldenEntry struct {
+	Prompt    string    `json:"prompt"`
+	Response  string    `json:"response,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+type RecordingClient struct {
+	LLM      LLMClient
+	Embedder EmbedderClient
+	Dir      string
+}
+
+func NewRecordingClient(llmClient LLMClient, embedder EmbedderClient, dir string) (*RecordingClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording dir '%s': %w", dir, err)
+	}
+	return &RecordingClient{LLM: llmClient, Embedder: embedder, Dir: dir}, nil
+}
+
+// CountTokens counts with the recorded client's tokenizer, or estimates when it has none.
+func (c *RecordingClient) CountTokens(text string) int {
+	return CountTokens(c.LLM, text)
+}
+
+func (c *RecordingClient) Generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.LLM.Generate(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	masked, uuids := maskUUIDs(prompt)
+	entry := goldenEntry{Prompt: masked, Response: templateUUIDs(resp, uuids)}
+	if err := writeGolden(c.Dir, "generate", entry); err != nil {
+		return "", err
+	}
+	return resp, nil
+}
+
+func (c *RecordingClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if c.Embedder == nil {
+		return nil, fmt.Errorf("no embedder configured for recording")
+	}
+	vec, err := c.Embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	masked, _ := maskUUIDs(text)
+	if err := writeGolden(c.Dir, "embed", goldenEntry{Prompt: masked, Embedding: vec}); err != nil {
+		return nil, err
+	}
+	return vec, nil
+}
+
+type ReplayClient struct {
+	Dir string
+}
+
+func NewReplayClient(dir string) (*ReplayClient, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("replay dir '%s' is not readable: %w", dir, err)
+	}
+	return &ReplayClient{Dir: dir}, nil
+}
+
+func (c *ReplayClient) Generate(ctx context.Context, prompt string) (string, error) {
+	masked, uuids := maskUUIDs(prompt)
+	entry, err := readGolden(c.Dir, "generate", masked)
+	if err != nil {
+		return "", err
+	}
+	return fillUUIDs(entry.Response, uuids), nil
+}
+
+func (c *ReplayClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	masked, _ := maskUUIDs(text)
+	entry, err := readGolden(c.Dir, "embed", masked)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Embedding, nil
+}
+
+// maskUUIDs replaces each distinct UUID with <uuid-N> in order of first appearance.
+func maskUUIDs(s string) (string, []string) {
+	var uuids []string
+	index := make(map[string]int)
+	masked := uuidPattern.ReplaceAllStringFunc(s, func(u string) string {
+		i, ok := index[u]
+		if !ok {
+			i = len(uuids)
+			index[u] = i
+			uuids = append(uuids, u)
+		}
+		return fmt.Sprintf("<uuid-%d>", i)
+	})
+	return masked, uuids
+}
+
+func templateUUIDs(s string, uuids []string) string {
+	for i, u := range uuids {
+		s = strings.ReplaceAll(s, u, fmt.Sprintf("<uuid-%d>", i))
+	}
+	return s
+}
+
+func fillUUIDs(s string, uuids []string) string {
+	for i, u := range uuids {
+		s = strings.ReplaceAll(s, fmt.Sprintf("<uuid-%d>", i), u)
+	}
+	return s
+}
+
+func goldenPath(dir, kind, maskedPrompt string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + maskedPrompt))
+	return filepath.Join(dir, kind+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func writeGolden(dir, kind string, entry goldenEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode golden entry: %w", err)
+	}
+	if err := os.WriteFile(goldenPath(dir, kind, entry.Prompt), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+	return nil
+}
+
+func readGolden(dir, kind, maskedPrompt string) (*goldenEntry, error) {
+	path := goldenPath(dir, kind, maskedPrompt)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s (expected %s)", ErrNoRecording, kind, filepath.Base(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden file: %w", err)
+	}
+	var entry goldenEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode golden file %s: %w", path, err)
+	}
+	return &entry, nil
+}
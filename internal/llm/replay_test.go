@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+type stubLLM struct {
+	calls int
+}
+
+func (s *stubLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	s.calls++
+	// Echo the UUID back the way extraction responses reference node UUIDs
+	return `{"source": "` + prompt[len(prompt)-36:] + `"}`, nil
+}
+
+func (s *stubLLM) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{0.1, 0.2}, nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	stub := &stubLLM{}
+
+	rec, err := NewRecordingClient(stub, stub, dir)
+	require.NoError(t, err)
+	recorded, err := rec.Generate(ctx, "Extract edges for 11111111-1111-1111-1111-111111111111")
+	require.NoError(t, err)
+	assert.Contains(t, recorded, "11111111-1111-1111-1111-111111111111")
+	_, err = rec.Embed(ctx, "Alice")
+	require.NoError(t, err)
+
+	replay, err := NewReplayClient(dir)
+	require.NoError(t, err)
+
+	// A different run produces different UUIDs; the response follows them
+	resp, err := replay.Generate(ctx, "Extract edges for 22222222-2222-2222-2222-222222222222")
+	require.NoError(t, err)
+	assert.Equal(t, `{"source": "22222222-2222-2222-2222-222222222222"}`, resp)
+	assert.Equal(t, 1, stub.calls)
+
+	vec, err := replay.Embed(ctx, "Alice")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2}, vec)
+
+	_, err = replay.Generate(ctx, "an unrecorded prompt")
+	assert.ErrorIs(t, err, ErrNoRecording)
+}
+
+func TestNewClientReplayMode(t *testing.T) {
+	l, e, err := NewClient(context.Background(), config.LLMConfig{ReplayMode: "replay", ReplayDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &ReplayClient{}, l)
+	assert.NotNil(t, e)
+
+	_, _, err = NewClient(context.Background(), config.LLMConfig{ReplayMode: "bogus"})
+	assert.Error(t, err)
+}
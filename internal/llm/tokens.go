@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"unicode/utf8"
+
+	"github.com/agenthands/carbon/internal/config"
+)
+
+// CharsPerToken is the average characters per token EstimateTokens assumes,
+// typical of English text across the supported providers' tokenizers.
+const CharsPerToken = 4
+
+// TokenCounter is implemented by clients that can count tokens with their
+// model's tokenizer. The built-in providers don't; their prompts are measured
+// with EstimateTokens. Wrappers such as CircuitBreaker forward to the client
+// they wrap.
+type TokenCounter interface {
+	CountTokens(text string) int
+}
+
+// CountTokens counts text's tokens with c's tokenizer, or estimates them when c has none.
+func CountTokens(c LLMClient, text string) int {
+	if tc, ok := c.(TokenCounter); ok {
+		return tc.CountTokens(text)
+	}
+	return EstimateTokens(text)
+}
+
+// PromptBudget returns how many tokens a prompt for op may take under cfg,
+// or 0 when no context window is configured.
+func PromptBudget(cfg config.LLMConfig, op string) int {
+	window := cfg.ContextTokens
+	if window <= 0 {
+		window = cfg.Options.NumCtx
+		if o := cfg.Operations[op]; o.NumCtx > 0 {
+			window = o.NumCtx
+		}
+	}
+	if window <= 0 {
+		return 0
+	}
+	reserve := cfg.ResponseTokens
+	if reserve <= 0 {
+		reserve = 1024
+	}
+	if window-reserve < 1 {
+		return 1
+	}
+	return window - reserve
+}
+
+// EstimateTokens approximates how many tokens text takes in a prompt.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + CharsPerToken - 1) / CharsPerToken
+}
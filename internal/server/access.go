@@ -0,0 +1,86 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// authenticate scopes the request's reads to its API key when [access]
+// policies are configured. Requests without a key hold no scopes; an unknown
+// key is rejected.
+func (s *Server) authenticate(c *gin.Context) {
+	cfg := s.Graphiti.Config
+	if cfg == nil || len(cfg.Access.Policies) == 0 {
+		return
+	}
+	scopes, ok := s.keyScopes(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Error: "Invalid API key"})
+		return
+	}
+	c.Request = c.Request.WithContext(core.WithScopes(c.Request.Context(), scopes))
+}
+
+// PriorityHeader sets the priority of a request's work, "interactive" or
+// "batch", overriding the endpoint's default (see core.WithPriority).
+const PriorityHeader = "X-Carbon-Priority"
+
+// prioritize applies the request's PriorityHeader.
+func (s *Server) prioritize(c *gin.Context) {
+	priority := c.GetHeader(PriorityHeader)
+	if priority == "" {
+		return
+	}
+	if !model.ValidPriority(priority) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, api.ErrorResponse{Error: PriorityHeader + " must be interactive or batch"})
+		return
+	}
+	c.Request = c.Request.WithContext(core.WithPriority(c.Request.Context(), priority))
+}
+
+// requireAdmin restricts a route to API keys holding the "admin" scope.
+// Without [access] keys nobody holds it, so the route is refused.
+func (s *Server) requireAdmin(c *gin.Context) {
+	cfg := s.Graphiti.Config
+	if cfg == nil || len(cfg.Access.Keys) == 0 {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse{Error: "Admin endpoints need an [access] key holding the admin scope"})
+		return
+	}
+	if !strings.HasPrefix(c.GetHeader("Authorization"), "Bearer ") {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Error: "API key required"})
+		return
+	}
+	scopes, ok := s.keyScopes(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{Error: "Invalid API key"})
+		return
+	}
+	if !slices.Contains(scopes, adminScope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse{Error: "API key lacks the admin scope"})
+		return
+	}
+}
+
+const adminScope = "admin"
+
+// keyScopes returns the scopes of the request's bearer key, and false for an
+// unknown key. Requests without a key hold no scopes.
+func (s *Server) keyScopes(c *gin.Context) ([]string, bool) {
+	key, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return nil, true
+	}
+	for _, k := range s.Graphiti.Config.Access.Keys {
+		if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+			return k.Scopes, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/agenthands/carbon/pkg/carbontest"
+)
+
+func TestAdminBackups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	r := (&Server{Graphiti: g}).SetupRouter()
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without keys nobody holds the admin scope
+	assert.Equal(t, http.StatusForbidden, do("GET", "/admin/backups", "", "").Code)
+
+	g.Config.Access.Keys = []config.APIKeyConfig{
+		{Key: "admin-key", Scopes: []string{"admin"}},
+		{Key: "reader-key", Scopes: []string{"medical"}},
+	}
+	assert.Equal(t, http.StatusNotFound, do("GET", "/admin/backups", "admin-key", "").Code)
+
+	g.Backups = blob.NewFileStore(t.TempDir())
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/admin/backups", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/admin/backups", "wrong", "").Code)
+	assert.Equal(t, http.StatusForbidden, do("GET", "/admin/backups", "reader-key", "").Code)
+
+	w := do("POST", "/admin/backups", "admin-key", `{"group_id": "g1"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created api.BackupsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Len(t, created.Backups, 1)
+
+	w = do("GET", "/admin/backups?group_id=g1", "admin-key", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed api.BackupsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	assert.Equal(t, created.Backups, listed.Backups)
+
+	w = do("POST", "/admin/restore", "admin-key", `{"group_id": "g1", "snapshot": "latest"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var report model.RestoreReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, created.Backups[0].ID, report.BackupID)
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/admin/restore", "admin-key", `{"snapshot": "latest"}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/admin/restore", "admin-key", `{"group_id": "g2", "snapshot": "latest"}`).Code)
+	assert.Equal(t, http.StatusForbidden, do("POST", "/admin/restore", "reader-key", `{"group_id": "g1", "snapshot": "latest"}`).Code)
+}
+
+func TestRoutesRequiringAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	r := (&Server{Graphiti: g}).SetupRouter()
+	do := func(method, path, key, body string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	routes := []struct{ method, path, body string }{
+		{"GET", "/groups/g1/export", ""},
+		{"GET", "/groups/g1/moderation", ""},
+		{"PATCH", "/groups/g1", `{"name": "G1"}`},
+		{"PATCH", "/groups/g1/synonyms", `{"synonyms": {"SF": "San Francisco"}}`},
+		{"POST", "/maintenance/contradictions", `{"group_id": "g1"}`},
+		{"GET", "/maintenance/orphans/g1", ""},
+	}
+
+	for _, rt := range routes {
+		assert.Equal(t, http.StatusForbidden, do(rt.method, rt.path, "", rt.body), rt.path)
+	}
+
+	g.Config.Access.Keys = []config.APIKeyConfig{
+		{Key: "admin-key", Scopes: []string{"admin"}},
+		{Key: "reader-key", Scopes: []string{"medical"}},
+	}
+	for _, rt := range routes {
+		assert.Equal(t, http.StatusUnauthorized, do(rt.method, rt.path, "", rt.body), rt.path)
+		assert.Equal(t, http.StatusForbidden, do(rt.method, rt.path, "reader-key", rt.body), rt.path)
+	}
+	assert.Equal(t, http.StatusOK, do("GET", "/groups/g1/export", "admin-key", ""))
+	assert.Equal(t, http.StatusOK, do("PATCH", "/groups/g1", "admin-key", `{"name": "G1"}`))
+}
+
+// withAdminKey configures an admin key on g and returns a handler sending it
+// with every request.
+func withAdminKey(g *core.Graphiti, r http.Handler) http.Handler {
+	g.Config.Access.Keys = append(g.Config.Access.Keys, config.APIKeyConfig{Key: "admin-key", Scopes: []string{"admin"}})
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.Header.Set("Authorization", "Bearer admin-key")
+		r.ServeHTTP(w, req)
+	})
+}
+
+func TestAdminConcurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	r := withAdminKey(g, (&Server{Graphiti: g}).SetupRouter())
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/admin/concurrency", strings.NewReader(body)))
+		return w
+	}
+
+	w := do("PATCH", `{"bulk_ingest": 7}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats model.ConcurrencyStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 7, stats.BulkIngest.Limit)
+
+	w = do("GET", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 7, stats.BulkIngest.Limit)
+	assert.Equal(t, 5, stats.BulkSearch.Limit)
+
+	assert.Equal(t, http.StatusBadRequest, do("PATCH", `{"bulk_search": 0}`).Code)
+}
+
+func TestPriorityHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	r := withAdminKey(g, (&Server{Graphiti: g}).SetupRouter())
+	do := func(priority string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin/concurrency", nil)
+		req.Header.Set(PriorityHeader, priority)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do(""))
+	assert.Equal(t, http.StatusOK, do("batch"))
+	assert.Equal(t, http.StatusBadRequest, do("urgent"))
+}
+
+func TestDebugEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		(&Server{Graphiti: g}).SetupRouter().ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, do("POST", "/debug/ingest-profile", `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/debug/pprof/", "").Code)
+
+	g.Config.Debug.Enabled = true
+	assert.Equal(t, http.StatusForbidden, do("GET", "/debug/pprof/", "").Code)
+
+	r := withAdminKey(g, (&Server{Graphiti: g}).SetupRouter())
+	do = func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	assert.Equal(t, http.StatusOK, do("GET", "/debug/pprof/", "").Code)
+	assert.Equal(t, http.StatusOK, do("GET", "/debug/pprof/goroutine?debug=1", "").Code)
+
+	w := do("POST", "/debug/ingest-profile", `{"content": "Alice works at Acme."}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var profile model.IngestProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Empty(t, profile.Error)
+	assert.NotEmpty(t, profile.Stages)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+func (s *Server) debugEnabled() bool {
+	return s.Graphiti != nil && s.Graphiti.Config != nil && s.Graphiti.Config.Debug.Enabled
+}
+
+// requireDebug hides /debug endpoints unless [debug] is enabled.
+func (s *Server) requireDebug(c *gin.Context) {
+	if !s.debugEnabled() {
+		c.AbortWithStatusJSON(http.StatusNotFound, api.ErrorResponse{Error: "Debug endpoints are not enabled"})
+	}
+}
+
+// pprof serves net/http/pprof's profiles under /debug/pprof/.
+func (s *Server) pprof(c *gin.Context) {
+	switch c.Param("profile") {
+	case "/cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "/profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "/symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "/trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// ProfileIngest runs an episode through the ingestion pipeline in a throwaway
+// group and reports how long each stage took.
+func (s *Server) ProfileIngest(c *gin.Context) {
+	var req api.IngestProfileRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	profile, err := s.Graphiti.ProfileIngest(c.Request.Context(), req.Content)
+	if err != nil {
+		log.Printf("Failed to clean up after ingest profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete the profile's group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
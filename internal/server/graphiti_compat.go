@@ -0,0 +1,346 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// Compatibility router for clients of the Python Graphiti service
+// (graph_service). Paths and payloads follow that API; errors use FastAPI's
+// {"detail": ...} body. Differences: message timestamps are not used as
+// reference times, center_node_uuid is ignored, and deleting an episode keeps
+// the facts extracted from it.
+
+type GraphitiMessage struct {
+	Content           string     `json:"content"`
+	UUID              string     `json:"uuid"`
+	Name              string     `json:"name"`
+	RoleType          string     `json:"role_type"` // "user", "assistant" or "system"
+	Role              string     `json:"role"`
+	Timestamp         *time.Time `json:"timestamp"`
+	SourceDescription string     `json:"source_description"`
+}
+
+type GraphitiAddMessagesRequest struct {
+	GroupID  string            `json:"group_id" binding:"required"`
+	Messages []GraphitiMessage `json:"messages"`
+}
+
+type GraphitiAddEntityNodeRequest struct {
+	UUID    string `json:"uuid" binding:"required"`
+	GroupID string `json:"group_id" binding:"required"`
+	Name    string `json:"name" binding:"required"`
+	Summary string `json:"summary"`
+}
+
+type GraphitiSearchQuery struct {
+	GroupIDs []string `json:"group_ids"` // All groups when empty
+	Query    string   `json:"query"`
+	MaxFacts int      `json:"max_facts"`
+}
+
+type GraphitiGetMemoryRequest struct {
+	GroupID        string            `json:"group_id" binding:"required"`
+	MaxFacts       int               `json:"max_facts"`
+	CenterNodeUUID *string           `json:"center_node_uuid"`
+	Messages       []GraphitiMessage `json:"messages"`
+}
+
+type GraphitiFactResult struct {
+	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"`
+	Fact      string     `json:"fact"`
+	ValidAt   *time.Time `json:"valid_at"`
+	InvalidAt *time.Time `json:"invalid_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+type GraphitiFacts struct {
+	Facts []GraphitiFactResult `json:"facts"`
+}
+
+type GraphitiResult struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+}
+
+const graphitiDefaultMaxFacts = 10
+
+// SetupGraphitiRouter returns a router serving the Graphiti REST API on the same engine.
+func (s *Server) SetupGraphitiRouter() *gin.Engine {
+	r := gin.Default()
+	r.Use(s.authenticate)
+
+	r.GET("/healthcheck", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "healthy"}) })
+	r.POST("/messages", s.GraphitiAddMessages)
+	r.POST("/entity-node", s.GraphitiAddEntityNode)
+	r.GET("/entity-edge/:uuid", s.GraphitiGetEntityEdge)
+	r.DELETE("/entity-edge/:uuid", s.GraphitiDeleteEntityEdge)
+	r.DELETE("/group/:group_id", s.requireAdmin, s.GraphitiDeleteGroup)
+	r.DELETE("/episode/:uuid", s.GraphitiDeleteEpisode)
+	r.GET("/episodes/:group_id", s.GraphitiGetEpisodes)
+	r.POST("/clear", s.requireAdmin, s.GraphitiClear)
+	r.POST("/search", s.GraphitiSearch)
+	r.POST("/get-memory", s.GraphitiGetMemory)
+
+	return r
+}
+
+func toGraphitiFact(e model.EntityEdge) GraphitiFactResult {
+	f := GraphitiFactResult{UUID: e.UUID, Name: e.Name, Fact: e.Fact, CreatedAt: e.CreatedAt, InvalidAt: e.InvalidAt, ExpiredAt: e.ExpiredAt}
+	if !e.ValidAt.IsZero() {
+		validAt := e.ValidAt
+		f.ValidAt = &validAt
+	}
+	return f
+}
+
+// graphitiEpisodeBody renders a message the way graph_service does.
+func graphitiEpisodeBody(m GraphitiMessage) string {
+	return fmt.Sprintf("%s(%s): %s", m.Role, m.RoleType, m.Content)
+}
+
+// groupLock serializes background ingestion per group, like graph_service's per-group queue.
+func (s *Server) groupLock(groupID string) *sync.Mutex {
+	s.groupLocksMu.Lock()
+	defer s.groupLocksMu.Unlock()
+	if s.groupLocks == nil {
+		s.groupLocks = make(map[string]*sync.Mutex)
+	}
+	if s.groupLocks[groupID] == nil {
+		s.groupLocks[groupID] = &sync.Mutex{}
+	}
+	return s.groupLocks[groupID]
+}
+
+// GraphitiAddMessages queues the messages and returns 202 before they are processed.
+func (s *Server) GraphitiAddMessages(c *gin.Context) {
+	var req GraphitiAddMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"detail": err.Error()})
+		return
+	}
+
+	bodies := make([]string, len(req.Messages))
+	for i, m := range req.Messages {
+		bodies[i] = graphitiEpisodeBody(m)
+	}
+	if !s.checkContent(c, "messages[%d].content", bodies) {
+		return
+	}
+
+	lock := s.groupLock(req.GroupID)
+	s.compatIngest.Add(1)
+	go func() {
+		defer s.compatIngest.Done()
+		lock.Lock()
+		defer lock.Unlock()
+		for i, m := range req.Messages {
+			name := m.Name
+			if name == "" {
+				name = "message"
+			}
+			if err := s.Graphiti.AddEpisode(context.Background(), req.GroupID, name, bodies[i], "", ""); err != nil {
+				log.Printf("Failed to add episode to group %s: %v", req.GroupID, err)
+			}
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, GraphitiResult{Message: "Messages added to processing queue", Success: true})
+}
+
+func (s *Server) GraphitiAddEntityNode(c *gin.Context) {
+	var req GraphitiAddEntityNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"detail": err.Error()})
+		return
+	}
+
+	node, err := s.Graphiti.SaveEntityNodeWithUUID(c.Request.Context(), req.UUID, req.Name, req.GroupID, req.Summary)
+	if err != nil {
+		log.Printf("Failed to save entity node: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to save entity node"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, node)
+}
+
+func (s *Server) GraphitiGetEntityEdge(c *gin.Context) {
+	fact, err := s.Graphiti.GetFact(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrFactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"detail": "Entity edge not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get entity edge: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to get entity edge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toGraphitiFact(*fact))
+}
+
+func (s *Server) GraphitiDeleteEntityEdge(c *gin.Context) {
+	err := s.Graphiti.DeleteFact(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrFactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"detail": "Entity edge not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete entity edge: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to delete entity edge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GraphitiResult{Message: "Entity Edge deleted", Success: true})
+}
+
+func (s *Server) GraphitiDeleteGroup(c *gin.Context) {
+	if _, err := s.Graphiti.DeleteGroup(c.Request.Context(), c.Param("group_id")); err != nil {
+		log.Printf("Failed to delete group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to delete group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GraphitiResult{Message: "Group deleted", Success: true})
+}
+
+func (s *Server) GraphitiDeleteEpisode(c *gin.Context) {
+	err := s.Graphiti.DeleteEpisode(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrEpisodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"detail": "Episode not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete episode: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to delete episode"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GraphitiResult{Message: "Episode deleted", Success: true})
+}
+
+func (s *Server) GraphitiGetEpisodes(c *gin.Context) {
+	lastN, err := strconv.Atoi(c.Query("last_n"))
+	if err != nil || lastN < 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"detail": "last_n must be a non-negative integer"})
+		return
+	}
+
+	episodes, err := s.Graphiti.GetEpisodes(c.Request.Context(), c.Param("group_id"), lastN)
+	if err != nil {
+		log.Printf("Failed to get episodes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to get episodes"})
+		return
+	}
+	if episodes == nil {
+		episodes = []model.EpisodicNode{}
+	}
+
+	c.JSON(http.StatusOK, episodes)
+}
+
+func (s *Server) GraphitiClear(c *gin.Context) {
+	if _, err := s.Graphiti.ClearGraph(c.Request.Context()); err != nil {
+		log.Printf("Failed to clear graph: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to clear graph"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GraphitiResult{Message: "Graph cleared", Success: true})
+}
+
+func (s *Server) GraphitiSearch(c *gin.Context) {
+	var req GraphitiSearchQuery
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"detail": err.Error()})
+		return
+	}
+
+	facts, err := s.graphitiSearch(c.Request.Context(), req.GroupIDs, req.Query, req.MaxFacts)
+	if err != nil {
+		log.Printf("Failed to search: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, facts)
+}
+
+// GraphitiGetMemory searches with a query composed from the messages, like graph_service.
+func (s *Server) GraphitiGetMemory(c *gin.Context) {
+	var req GraphitiGetMemoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"detail": err.Error()})
+		return
+	}
+
+	var query string
+	for _, m := range req.Messages {
+		query += fmt.Sprintf("%s(%s): %s\n", m.RoleType, m.Role, m.Content)
+	}
+	facts, err := s.graphitiSearch(c.Request.Context(), []string{req.GroupID}, query, req.MaxFacts)
+	if err != nil {
+		log.Printf("Failed to get memory: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"detail": "Failed to get memory"})
+		return
+	}
+
+	c.JSON(http.StatusOK, facts)
+}
+
+// graphitiSearch searches each group (every group when groupIDs is empty) and
+// keeps the first maxFacts results, taking the groups' top results first.
+func (s *Server) graphitiSearch(ctx context.Context, groupIDs []string, query string, maxFacts int) (GraphitiFacts, error) {
+	if maxFacts <= 0 {
+		maxFacts = graphitiDefaultMaxFacts
+	}
+	if len(groupIDs) == 0 {
+		groups, err := s.Graphiti.ListGroups(ctx)
+		if err != nil {
+			return GraphitiFacts{}, err
+		}
+		for _, g := range groups {
+			groupIDs = append(groupIDs, g.GroupID)
+		}
+	}
+
+	type ranked struct {
+		rank int
+		edge model.EntityEdge
+	}
+	var all []ranked
+	for _, groupID := range groupIDs {
+		edges, err := s.Graphiti.Search(ctx, groupID, query)
+		if err != nil {
+			return GraphitiFacts{}, err
+		}
+		for i, e := range edges {
+			all = append(all, ranked{rank: i, edge: e})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].rank < all[j].rank })
+
+	facts := GraphitiFacts{Facts: []GraphitiFactResult{}}
+	for _, r := range all {
+		if len(facts.Facts) == maxFacts {
+			break
+		}
+		facts.Facts = append(facts.Facts, toGraphitiFact(r.edge))
+	}
+	return facts, nil
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/pkg/carbon"
+	"github.com/agenthands/carbon/pkg/carbontest"
+)
+
+func graphitiRequest(t *testing.T, r http.Handler, method, path string, body interface{}, out interface{}) int {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+	if out != nil {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), out), w.Body.String())
+	}
+	return w.Code
+}
+
+func TestGraphitiCompat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+	_, err := driver.Migrate(ctx, g.Driver, driver.Migrations)
+	require.NoError(t, err)
+	var r http.Handler = (&Server{Graphiti: g}).SetupGraphitiRouter()
+
+	alice := carbontest.NewEntity("g1", "Alice", "")
+	berlin := carbontest.NewEntity("g1", "Berlin", "")
+	bob := carbontest.NewEntity("g2", "Bob", "")
+	paris := carbontest.NewEntity("g2", "Paris", "")
+	aliceFact := carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin, bob, paris},
+		Facts:    []carbon.EntityEdge{aliceFact, carbontest.NewFact(bob, paris, "LIVES_IN", "Bob lives in Paris")},
+	}))
+
+	// Messages are ingested in the background
+	var result GraphitiResult
+	for _, group := range []string{"g1", "g2"} {
+		assert.Equal(t, http.StatusAccepted, graphitiRequest(t, r, "POST", "/messages", map[string]interface{}{
+			"group_id": group,
+			"messages": []map[string]string{{"role_type": "user", "role": "bob", "content": "Hi"}},
+		}, &result))
+		assert.True(t, result.Success)
+		assert.Eventually(t, func() bool {
+			var episodes []carbon.EpisodicNode
+			graphitiRequest(t, r, "GET", "/episodes/"+group+"?last_n=5", nil, &episodes)
+			return len(episodes) == 1 && episodes[0].Content == "bob(user): Hi"
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	var facts GraphitiFacts
+	assert.Equal(t, http.StatusOK, graphitiRequest(t, r, "POST", "/search", map[string]interface{}{"query": "lives in"}, &facts))
+	assert.Len(t, facts.Facts, 2, "no group_ids searches every group")
+	graphitiRequest(t, r, "POST", "/search", map[string]interface{}{"group_ids": []string{"g1"}, "query": "lives in", "max_facts": 1}, &facts)
+	require.Len(t, facts.Facts, 1)
+	assert.Equal(t, aliceFact.UUID, facts.Facts[0].UUID)
+
+	var fact GraphitiFactResult
+	assert.Equal(t, http.StatusOK, graphitiRequest(t, r, "GET", "/entity-edge/"+aliceFact.UUID, nil, &fact))
+	assert.Equal(t, "Alice lives in Berlin", fact.Fact)
+	assert.Equal(t, http.StatusOK, graphitiRequest(t, r, "DELETE", "/entity-edge/"+aliceFact.UUID, nil, &result))
+	var detail map[string]string
+	assert.Equal(t, http.StatusNotFound, graphitiRequest(t, r, "GET", "/entity-edge/"+aliceFact.UUID, nil, &detail))
+	assert.Equal(t, "Entity edge not found", detail["detail"])
+
+	var node carbon.EntityNode
+	assert.Equal(t, http.StatusCreated, graphitiRequest(t, r, "POST", "/entity-node",
+		map[string]string{"uuid": "n1", "group_id": "g1", "name": "Carol"}, &node))
+	assert.Equal(t, "n1", node.UUID)
+
+	assert.Equal(t, http.StatusForbidden, graphitiRequest(t, r, "DELETE", "/group/g2", nil, nil))
+	assert.Equal(t, http.StatusForbidden, graphitiRequest(t, r, "POST", "/clear", nil, nil))
+	r = withAdminKey(g, r)
+	assert.Equal(t, http.StatusOK, graphitiRequest(t, r, "DELETE", "/group/g2", nil, &result))
+	graphitiRequest(t, r, "POST", "/search", map[string]interface{}{"group_ids": []string{"g2"}, "query": "lives in"}, &facts)
+	assert.Empty(t, facts.Facts)
+
+	assert.Equal(t, http.StatusOK, graphitiRequest(t, r, "POST", "/clear", nil, &result))
+	_, err = g.GetEntity(ctx, "n1")
+	assert.ErrorIs(t, err, carbon.ErrEntityNotFound)
+	version, _, err := driver.SchemaVersion(ctx, g.Driver)
+	require.NoError(t, err)
+	assert.Equal(t, len(driver.Migrations), version, "clear keeps the schema version")
+}
+
+func TestGraphitiAddMessages_LimitsAndClose(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+	g.Config.Ingest.MaxContentChars = 20
+	s := &Server{Graphiti: g}
+	r := s.SetupGraphitiRouter()
+
+	var errResp map[string]interface{}
+	assert.Equal(t, http.StatusRequestEntityTooLarge, graphitiRequest(t, r, "POST", "/messages", map[string]interface{}{
+		"group_id": "g1",
+		"messages": []map[string]string{{"role_type": "user", "role": "bob", "content": "This message is well over the limit"}},
+	}, &errResp))
+
+	assert.Equal(t, http.StatusAccepted, graphitiRequest(t, r, "POST", "/messages", map[string]interface{}{
+		"group_id": "g1",
+		"messages": []map[string]string{{"role_type": "user", "role": "bob", "content": "Hi"}},
+	}, nil))
+	// Close waits for the queued message
+	require.NoError(t, s.Close(ctx))
+	episodes, err := g.GetEpisodes(ctx, "g1", 5)
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+	assert.Equal(t, "bob(user): Hi", episodes[0].Content)
+}
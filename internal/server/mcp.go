@@ -0,0 +1,856 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/llm"
+)
+
+// MCP server on the Streamable HTTP transport. Clients POST JSON-RPC requests
+// to /mcp and get their responses as JSON; a GET opens the session's event
+// stream, on which notifications/resources/list_changed is sent whenever a
+// group's memory changes. Memory is browsed through carbon:// resources,
+// searched and added to through tools, and injected into prompts. With an
+// [mcp] group_binding, each session or API key works on a group of its own and
+// may leave group_id out. Batched requests are not supported.
+
+const (
+	mcpSessionHeader = "Mcp-Session-Id"
+	// mcpSessionIdle is how long a session lasts without requests.
+	mcpSessionIdle = time.Hour
+	// mcpEpisodeLimit is how many of its latest episodes a group lists.
+	mcpEpisodeLimit = 50
+)
+
+// mcpProtocolVersions are the protocol versions served, latest first.
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26"}
+
+// JSON-RPC and MCP error codes.
+const (
+	mcpParseError       = -32700
+	mcpInvalidRequest   = -32600
+	mcpMethodNotFound   = -32601
+	mcpInvalidParams    = -32602
+	mcpInternalError    = -32603
+	mcpResourceNotFound = -32002
+)
+
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *mcpError) Error() string { return e.Message }
+
+func mcpErrorf(code int, format string, args ...interface{}) *mcpError {
+	return &mcpError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// mcpSession is an initialized client. Notifications wait in events until its
+// stream reads them; they are dropped when the client reads none. done is
+// closed when the session ends.
+type mcpSession struct {
+	id        string
+	groupID   string // Bound group, empty when group_id is passed
+	events    chan []byte
+	done      chan struct{}
+	lastSeen  time.Time
+	streaming bool
+}
+
+// mcpHub holds the MCP sessions and tells them about changed groups.
+type mcpHub struct {
+	mu       sync.Mutex
+	sessions map[string]*mcpSession
+}
+
+func newMCPHub() *mcpHub {
+	return &mcpHub{sessions: make(map[string]*mcpSession)}
+}
+
+// open starts a session bound to groupID, dropping those idle for longer than
+// mcpSessionIdle.
+func (h *mcpHub) open(id, groupID string) *mcpSession {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	for id, s := range h.sessions {
+		if !s.streaming && now.Sub(s.lastSeen) > mcpSessionIdle {
+			close(s.done)
+			delete(h.sessions, id)
+		}
+	}
+	s := &mcpSession{id: id, groupID: groupID, events: make(chan []byte, 16), done: make(chan struct{}), lastSeen: now}
+	h.sessions[s.id] = s
+	return s
+}
+
+func (h *mcpHub) get(id string) (*mcpSession, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[id]
+	if ok {
+		s.lastSeen = time.Now()
+	}
+	return s, ok
+}
+
+func (h *mcpHub) close(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[id]
+	if ok {
+		close(s.done)
+		delete(h.sessions, id)
+	}
+	return ok
+}
+
+// GroupChanged implements core.GroupChangeHook. Sessions bound to another
+// group are not told; the resource list of the others names every group.
+func (h *mcpHub) GroupChanged(ctx context.Context, groupID string) {
+	msg, _ := json.Marshal(map[string]string{"jsonrpc": "2.0", "method": "notifications/resources/list_changed"})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s := range h.sessions {
+		if s.groupID != "" && s.groupID != groupID {
+			continue
+		}
+		select {
+		case s.events <- msg:
+		default: // One pending list_changed is as good as several
+		}
+	}
+}
+
+// SetupMCPRouter returns a router serving MCP on the same engine.
+func (s *Server) SetupMCPRouter() *gin.Engine {
+	if binding, _ := s.mcpBinding(); binding != "" && binding != "session" && binding != "key" {
+		log.Fatalf("[mcp] group_binding must be session or key, not %q", binding)
+	}
+	if s.mcp == nil {
+		s.mcp = newMCPHub()
+		if err := s.Graphiti.RegisterHook(s.mcp); err != nil {
+			log.Fatalf("Failed to register MCP hook: %v", err)
+		}
+	}
+
+	r := gin.Default()
+	r.Use(s.authenticate)
+
+	r.POST("/mcp", s.MCPPost)
+	r.GET("/mcp", s.MCPStream)
+	r.DELETE("/mcp", s.MCPDelete)
+
+	return r
+}
+
+// MCPPost answers a JSON-RPC request. Notifications and responses from the
+// client are accepted without a body.
+func (s *Server) MCPPost(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, mcpResponse{JSONRPC: "2.0", Error: mcpErrorf(mcpParseError, "Failed to read request")})
+		return
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		c.JSON(http.StatusBadRequest, mcpResponse{JSONRPC: "2.0", Error: mcpErrorf(mcpInvalidRequest, "Batched requests are not supported")})
+		return
+	}
+	var req mcpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, mcpResponse{JSONRPC: "2.0", Error: mcpErrorf(mcpParseError, "Invalid JSON: %v", err)})
+		return
+	}
+	if req.JSONRPC != "2.0" {
+		c.JSON(http.StatusBadRequest, mcpResponse{JSONRPC: "2.0", ID: req.ID, Error: mcpErrorf(mcpInvalidRequest, "jsonrpc must be 2.0")})
+		return
+	}
+
+	if req.Method == "initialize" {
+		id := uuid.NewString()
+		groupID, rpcErr := s.mcpBoundGroup(c, id)
+		if rpcErr != nil {
+			c.JSON(http.StatusOK, mcpResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr})
+			return
+		}
+		session := s.mcp.open(id, groupID)
+		result, rpcErr := s.mcpInitialize(session, req.Params)
+		c.Header(mcpSessionHeader, session.id)
+		c.JSON(http.StatusOK, mcpResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+		return
+	}
+
+	session, ok := s.mcpSession(c)
+	if !ok {
+		return
+	}
+	if req.Method == "" || req.ID == nil {
+		c.Status(http.StatusAccepted)
+		return
+	}
+	result, rpcErr := s.mcpDispatch(c.Request.Context(), session, req.Method, req.Params)
+	if rpcErr != nil {
+		result = nil
+	}
+	c.JSON(http.StatusOK, mcpResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// MCPStream sends the session's notifications as server-sent events until the
+// client disconnects. A session has one stream at a time.
+func (s *Server) MCPStream(c *gin.Context) {
+	session, ok := s.mcpSession(c)
+	if !ok {
+		return
+	}
+	s.mcp.mu.Lock()
+	if session.streaming {
+		s.mcp.mu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "Session already has a stream"})
+		return
+	}
+	session.streaming = true
+	s.mcp.mu.Unlock()
+	defer func() {
+		s.mcp.mu.Lock()
+		session.streaming = false
+		session.lastSeen = time.Now()
+		s.mcp.mu.Unlock()
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-session.done:
+			return
+		case msg := <-session.events:
+			fmt.Fprintf(c.Writer, "event: message\ndata: %s\n\n", msg)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// MCPDelete ends a session.
+func (s *Server) MCPDelete(c *gin.Context) {
+	session, ok := s.mcpSession(c)
+	if !ok {
+		return
+	}
+	s.mcp.close(session.id)
+	c.Status(http.StatusNoContent)
+}
+
+// mcpSession returns the request's session, answering 400 without one and
+// 404 for one that ended. A session bound to an API key's group is only found
+// with that key.
+func (s *Server) mcpSession(c *gin.Context) (*mcpSession, bool) {
+	id := c.GetHeader(mcpSessionHeader)
+	if id == "" {
+		c.JSON(http.StatusBadRequest, mcpResponse{JSONRPC: "2.0", Error: mcpErrorf(mcpInvalidRequest, "%s header is required", mcpSessionHeader)})
+		return nil, false
+	}
+	session, ok := s.mcp.get(id)
+	if binding, _ := s.mcpBinding(); ok && binding == "key" {
+		groupID, _ := s.mcpBoundGroup(c, id)
+		ok = groupID == session.groupID
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, mcpResponse{JSONRPC: "2.0", Error: mcpErrorf(mcpInvalidRequest, "Unknown session")})
+		return nil, false
+	}
+	return session, true
+}
+
+// mcpBinding returns the [mcp] group binding and the prefix of bound groups.
+func (s *Server) mcpBinding() (binding, prefix string) {
+	prefix = "mcp-"
+	if cfg := s.Graphiti.Config; cfg != nil {
+		binding = cfg.MCP.GroupBinding
+		if cfg.MCP.GroupPrefix != "" {
+			prefix = cfg.MCP.GroupPrefix
+		}
+	}
+	return binding, prefix
+}
+
+// mcpBoundGroup returns the group the session sessionID started by c is bound
+// to: its own under the "session" binding, its API key's under "key", and
+// none without a binding. A key's group is named by a hash of the key.
+func (s *Server) mcpBoundGroup(c *gin.Context, sessionID string) (string, *mcpError) {
+	binding, prefix := s.mcpBinding()
+	switch binding {
+	case "session":
+		return prefix + sessionID, nil
+	case "key":
+		key, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || key == "" {
+			return "", mcpErrorf(mcpInvalidRequest, "An API key is required: sessions work on the group of their key")
+		}
+		sum := sha256.Sum256([]byte(key))
+		return prefix + hex.EncodeToString(sum[:8]), nil
+	}
+	return "", nil
+}
+
+// group returns the group of a request naming groupID, which is required
+// unless the session is bound. A bound session may only name its own group.
+func (s *mcpSession) group(groupID string) (string, error) {
+	switch {
+	case s.groupID == "" && groupID == "":
+		return "", errors.New("group_id is required")
+	case s.groupID == "":
+		return groupID, nil
+	case groupID != "" && groupID != s.groupID:
+		return "", fmt.Errorf("this session works on group %s only", s.groupID)
+	}
+	return s.groupID, nil
+}
+
+func (s *Server) mcpInitialize(session *mcpSession, params json.RawMessage) (interface{}, *mcpError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, mcpErrorf(mcpInvalidParams, "Invalid params: %v", err)
+		}
+	}
+	version := mcpProtocolVersions[0]
+	for _, v := range mcpProtocolVersions {
+		if v == p.ProtocolVersion {
+			version = v
+		}
+	}
+	instructions := "Carbon is a temporal knowledge graph memory. Search it before answering and add what you learn to it."
+	if session.groupID != "" {
+		instructions += " This session's memory is group " + session.groupID + "; group_id can be left out."
+	}
+	return gin.H{
+		"protocolVersion": version,
+		"capabilities": gin.H{
+			"resources": gin.H{"listChanged": true},
+			"tools":     gin.H{},
+			"prompts":   gin.H{},
+		},
+		"serverInfo":   gin.H{"name": "carbon", "version": "1"},
+		"instructions": instructions,
+	}, nil
+}
+
+func (s *Server) mcpDispatch(ctx context.Context, session *mcpSession, method string, params json.RawMessage) (interface{}, *mcpError) {
+	switch method {
+	case "ping":
+		return gin.H{}, nil
+	case "tools/list":
+		return gin.H{"tools": mcpToolList(session)}, nil
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := mcpParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.mcpCallTool(ctx, session, p.Name, p.Arguments)
+	case "prompts/list":
+		return gin.H{"prompts": mcpPromptList(session)}, nil
+	case "prompts/get":
+		var p struct {
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments"`
+		}
+		if err := mcpParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.mcpGetPrompt(ctx, session, p.Name, p.Arguments)
+	case "resources/list":
+		return s.mcpListResources(ctx, session)
+	case "resources/templates/list":
+		return gin.H{"resourceTemplates": mcpResourceTemplates}, nil
+	case "resources/read":
+		var p struct {
+			URI string `json:"uri"`
+		}
+		if err := mcpParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.mcpReadResource(ctx, session, p.URI)
+	}
+	return nil, mcpErrorf(mcpMethodNotFound, "Method not found: %s", method)
+}
+
+func mcpParams(params json.RawMessage, v interface{}) *mcpError {
+	if len(params) == 0 {
+		params = []byte("{}")
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return mcpErrorf(mcpInvalidParams, "Invalid params: %v", err)
+	}
+	return nil
+}
+
+// Tools
+
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "search_memory",
+		Description: "Search a group's memory for the facts most relevant to a query.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"group_id":  map[string]interface{}{"type": "string", "description": "Group to search"},
+				"query":     map[string]interface{}{"type": "string", "description": "What to look for"},
+				"max_facts": map[string]interface{}{"type": "integer", "description": "Most facts to return (default 10)"},
+			},
+			"required": []string{"group_id", "query"},
+		},
+	},
+	{
+		Name:        "add_memory",
+		Description: "Add an episode, such as a message or a note, to a group's memory. Entities and facts are extracted from it.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"group_id": map[string]interface{}{"type": "string", "description": "Group to add to"},
+				"content":  map[string]interface{}{"type": "string", "description": "Text of the episode"},
+				"name":     map[string]interface{}{"type": "string", "description": "Name of the episode (default \"message\")"},
+			},
+			"required": []string{"group_id", "content"},
+		},
+	},
+}
+
+// mcpToolList returns the tools, with group_id optional in a bound session.
+func mcpToolList(session *mcpSession) []mcpTool {
+	if session.groupID == "" {
+		return mcpTools
+	}
+	tools := make([]mcpTool, len(mcpTools))
+	for i, tool := range mcpTools {
+		tool.InputSchema = maps.Clone(tool.InputSchema)
+		required := slices.Clone(tool.InputSchema["required"].([]string))
+		tool.InputSchema["required"] = slices.DeleteFunc(required, func(name string) bool { return name == "group_id" })
+		tools[i] = tool
+	}
+	return tools
+}
+
+type mcpSearchArgs struct {
+	GroupID  string `json:"group_id"`
+	Query    string `json:"query"`
+	MaxFacts int    `json:"max_facts"`
+}
+
+type mcpAddArgs struct {
+	GroupID string `json:"group_id"`
+	Content string `json:"content"`
+	Name    string `json:"name"`
+}
+
+// mcpToolText is a tool result of one text block. Failures the model can act
+// on are results with isError set rather than JSON-RPC errors.
+func mcpToolText(text string, isError bool) gin.H {
+	return gin.H{"content": []gin.H{{"type": "text", "text": text}}, "isError": isError}
+}
+
+func (s *Server) mcpCallTool(ctx context.Context, session *mcpSession, name string, args json.RawMessage) (interface{}, *mcpError) {
+	switch name {
+	case "search_memory":
+		var a mcpSearchArgs
+		if err := mcpParams(args, &a); err != nil {
+			return nil, err
+		}
+		groupID, err := session.group(a.GroupID)
+		if err != nil {
+			return mcpToolText(err.Error(), true), nil
+		}
+		if a.Query == "" {
+			return mcpToolText("query is required", true), nil
+		}
+		edges, err := s.mcpSearch(ctx, groupID, a.Query, a.MaxFacts)
+		if err != nil {
+			log.Printf("Failed to search: %v", err)
+			return mcpToolText("Failed to search", true), nil
+		}
+		if len(edges) == 0 {
+			return mcpToolText("No facts found.", false), nil
+		}
+		return mcpToolText(mcpFactList(edges), false), nil
+
+	case "add_memory":
+		var a mcpAddArgs
+		if err := mcpParams(args, &a); err != nil {
+			return nil, err
+		}
+		groupID, err := session.group(a.GroupID)
+		if err != nil {
+			return mcpToolText(err.Error(), true), nil
+		}
+		if a.Content == "" {
+			return mcpToolText("content is required", true), nil
+		}
+		if a.Name == "" {
+			a.Name = "message"
+		}
+		if err := s.Graphiti.CheckContent(a.Content); err != nil {
+			return mcpToolText(err.Error(), true), nil
+		}
+		ctx, changes := core.WithFactChanges(ctx)
+		err = s.Graphiti.AddEpisode(ctx, groupID, a.Name, a.Content, "", "")
+		switch {
+		case errors.Is(err, llm.ErrCircuitOpen):
+			return mcpToolText("LLM provider is unavailable; the episode was kept as a dead letter", true), nil
+		case errors.Is(err, core.ErrGroupLimit), errors.Is(err, core.ErrContentBlocked):
+			return mcpToolText(err.Error(), true), nil
+		case err != nil:
+			log.Printf("Failed to add episode: %v", err)
+			return mcpToolText("Failed to add memory", true), nil
+		}
+		created, invalidated := 0, 0
+		for _, change := range changes.List() {
+			if change.Op == model.ChangeOpInvalidate {
+				invalidated++
+			} else {
+				created++
+			}
+		}
+		return mcpToolText(fmt.Sprintf("Memory added: %d facts created, %d invalidated.", created, invalidated), false), nil
+	}
+	return nil, mcpErrorf(mcpInvalidParams, "Unknown tool: %s", name)
+}
+
+// mcpSearch returns the group's first maxFacts facts for query.
+func (s *Server) mcpSearch(ctx context.Context, groupID, query string, maxFacts int) ([]model.EntityEdge, error) {
+	if maxFacts <= 0 {
+		maxFacts = graphitiDefaultMaxFacts
+	}
+	edges, err := s.Graphiti.Search(ctx, groupID, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(edges) > maxFacts {
+		edges = edges[:maxFacts]
+	}
+	return edges, nil
+}
+
+// mcpFactList renders facts one per line, with when they became true.
+func mcpFactList(edges []model.EntityEdge) string {
+	var b strings.Builder
+	for _, e := range edges {
+		b.WriteString("- " + e.Fact)
+		if !e.ValidAt.IsZero() {
+			b.WriteString(" (since " + e.ValidAt.Format("2006-01-02") + ")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Prompts
+
+type mcpPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+type mcpPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Arguments   []mcpPromptArgument `json:"arguments"`
+}
+
+var mcpPrompts = []mcpPrompt{
+	{
+		Name:        "recall_context",
+		Description: "Recall the facts memory holds about a topic, to answer with.",
+		Arguments: []mcpPromptArgument{
+			{Name: "group_id", Description: "Group to recall from", Required: true},
+			{Name: "query", Description: "Topic or question to recall facts for", Required: true},
+			{Name: "max_facts", Description: "Most facts to recall (default 10)"},
+		},
+	},
+	{
+		Name:        "update_memory_from_conversation",
+		Description: "Compare a conversation with what memory already holds and add what is new or changed.",
+		Arguments: []mcpPromptArgument{
+			{Name: "group_id", Description: "Group to update", Required: true},
+			{Name: "conversation", Description: "Transcript of the conversation", Required: true},
+		},
+	},
+}
+
+// mcpPromptList returns the prompts, with group_id optional in a bound session.
+func mcpPromptList(session *mcpSession) []mcpPrompt {
+	if session.groupID == "" {
+		return mcpPrompts
+	}
+	prompts := make([]mcpPrompt, len(mcpPrompts))
+	for i, prompt := range mcpPrompts {
+		prompt.Arguments = slices.Clone(prompt.Arguments)
+		for j := range prompt.Arguments {
+			if prompt.Arguments[j].Name == "group_id" {
+				prompt.Arguments[j].Required = false
+			}
+		}
+		prompts[i] = prompt
+	}
+	return prompts
+}
+
+// mcpUserMessage is a prompt message from the user.
+func mcpUserMessage(text string) gin.H {
+	return gin.H{"role": "user", "content": gin.H{"type": "text", "text": text}}
+}
+
+// mcpGetPrompt searches memory with a prompt's arguments and returns its
+// messages with the facts found.
+func (s *Server) mcpGetPrompt(ctx context.Context, session *mcpSession, name string, args map[string]string) (interface{}, *mcpError) {
+	var prompt *mcpPrompt
+	prompts := mcpPromptList(session)
+	for i := range prompts {
+		if prompts[i].Name == name {
+			prompt = &prompts[i]
+		}
+	}
+	if prompt == nil {
+		return nil, mcpErrorf(mcpInvalidParams, "Unknown prompt: %s", name)
+	}
+	for _, arg := range prompt.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			return nil, mcpErrorf(mcpInvalidParams, "Argument %s is required", arg.Name)
+		}
+	}
+	groupID, err := session.group(args["group_id"])
+	if err != nil {
+		return nil, mcpErrorf(mcpInvalidParams, "%v", err)
+	}
+	maxFacts := 0
+	if v := args["max_facts"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, mcpErrorf(mcpInvalidParams, "max_facts must be a positive integer")
+		}
+		maxFacts = n
+	}
+
+	query := args["query"]
+	if name == "update_memory_from_conversation" {
+		query = args["conversation"]
+	}
+	edges, err := s.mcpSearch(ctx, groupID, query, maxFacts)
+	if err != nil {
+		log.Printf("Failed to search: %v", err)
+		return nil, mcpErrorf(mcpInternalError, "Failed to search")
+	}
+	known := "Memory holds no facts about this yet.\n"
+	if len(edges) > 0 {
+		known = mcpFactList(edges)
+	}
+
+	var text string
+	switch name {
+	case "recall_context":
+		text = fmt.Sprintf("Here is what memory holds about %q:\n\n%s\nUse these facts to answer. Facts may be out of date; prefer the most recent.", query, known)
+	case "update_memory_from_conversation":
+		text = fmt.Sprintf("Memory already holds these facts related to the conversation below:\n\n%s\n"+
+			"Conversation:\n%s\n\n"+
+			"Call the add_memory tool with group_id %q for each piece of information in the conversation that is new or "+
+			"contradicts the facts above, stated as a short self-contained note. Skip what memory already holds.",
+			known, args["conversation"], groupID)
+	}
+	return gin.H{"description": prompt.Description, "messages": []gin.H{mcpUserMessage(text)}}, nil
+}
+
+// Resources
+
+type mcpResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type mcpResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+var mcpResourceTemplates = []mcpResourceTemplate{
+	{"carbon://group/{group_id}/entities", "entities", "The group's entities with their summaries", "application/json"},
+	{"carbon://group/{group_id}/entity/{uuid}", "entity", "An entity with its facts, neighbors and communities", "application/json"},
+	{"carbon://group/{group_id}/episodes", "episodes", "The group's latest episodes", "application/json"},
+	{"carbon://group/{group_id}/episode/{uuid}", "episode", "An episode with its content", "application/json"},
+	{"carbon://group/{group_id}/communities", "communities", "The group's communities with their summaries", "application/json"},
+}
+
+// mcpURI returns the carbon:// URI of a group's resource.
+func mcpURI(groupID string, path ...string) string {
+	return "carbon://group/" + url.PathEscape(groupID) + "/" + strings.Join(path, "/")
+}
+
+// mcpListResources lists the entities, episodes and communities of every
+// group, or of its own group for a bound session.
+func (s *Server) mcpListResources(ctx context.Context, session *mcpSession) (interface{}, *mcpError) {
+	groupIDs := []string{session.groupID}
+	if session.groupID == "" {
+		groups, err := s.Graphiti.ListGroups(ctx)
+		if err != nil {
+			log.Printf("Failed to list groups: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to list groups")
+		}
+		groupIDs = groupIDs[:0]
+		for _, g := range groups {
+			groupIDs = append(groupIDs, g.GroupID)
+		}
+	}
+	resources := []mcpResource{}
+	for _, groupID := range groupIDs {
+		for _, kind := range []string{"entities", "episodes", "communities"} {
+			resources = append(resources, mcpResource{
+				URI:         mcpURI(groupID, kind),
+				Name:        groupID + " " + kind,
+				Description: fmt.Sprintf("The %s of group %s", kind, groupID),
+				MimeType:    "application/json",
+			})
+		}
+	}
+	return gin.H{"resources": resources}, nil
+}
+
+// mcpRef points to a resource from a listing.
+type mcpRef struct {
+	URI       string     `json:"uri"`
+	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"`
+	Summary   string     `json:"summary,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+func (s *Server) mcpReadResource(ctx context.Context, session *mcpSession, uri string) (interface{}, *mcpError) {
+	rest, ok := strings.CutPrefix(uri, "carbon://group/")
+	parts := strings.Split(rest, "/")
+	if !ok || len(parts) < 2 || len(parts) > 3 {
+		return nil, mcpErrorf(mcpResourceNotFound, "Resource not found: %s", uri)
+	}
+	groupID, err := url.PathUnescape(parts[0])
+	if err != nil || groupID == "" || session.groupID != "" && groupID != session.groupID {
+		return nil, mcpErrorf(mcpResourceNotFound, "Resource not found: %s", uri)
+	}
+
+	var content interface{}
+	switch kind := parts[1]; {
+	case kind == "entities" && len(parts) == 2:
+		entities, err := s.Graphiti.ListEntities(ctx, groupID)
+		if err != nil {
+			log.Printf("Failed to list entities: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to list entities")
+		}
+		refs := []mcpRef{}
+		for _, e := range entities {
+			refs = append(refs, mcpRef{URI: mcpURI(groupID, "entity", e.UUID), UUID: e.UUID, Name: e.Name, Summary: e.Summary})
+		}
+		content = gin.H{"group_id": groupID, "entities": refs}
+
+	case kind == "entity" && len(parts) == 3:
+		hood, err := s.Graphiti.GetNeighborhood(ctx, parts[2], 1)
+		if errors.Is(err, core.ErrEntityNotFound) || err == nil && hood.Nodes[0].Entity.GroupID != groupID {
+			return nil, mcpErrorf(mcpResourceNotFound, "Resource not found: %s", uri)
+		}
+		if err != nil {
+			log.Printf("Failed to get entity: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to get entity")
+		}
+		content = hood
+
+	case kind == "episodes" && len(parts) == 2:
+		episodes, err := s.Graphiti.GetEpisodes(ctx, groupID, mcpEpisodeLimit)
+		if err != nil {
+			log.Printf("Failed to list episodes: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to list episodes")
+		}
+		refs := []mcpRef{}
+		for _, ep := range episodes {
+			createdAt := ep.CreatedAt
+			refs = append(refs, mcpRef{URI: mcpURI(groupID, "episode", ep.UUID), UUID: ep.UUID, Name: ep.Name, CreatedAt: &createdAt})
+		}
+		content = gin.H{"group_id": groupID, "episodes": refs}
+
+	case kind == "episode" && len(parts) == 3:
+		ep, err := s.Graphiti.GetEpisode(ctx, parts[2])
+		if errors.Is(err, core.ErrEpisodeNotFound) || err == nil && ep.GroupID != groupID {
+			return nil, mcpErrorf(mcpResourceNotFound, "Resource not found: %s", uri)
+		}
+		if err != nil {
+			log.Printf("Failed to get episode: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to get episode")
+		}
+		content = ep
+
+	case kind == "communities" && len(parts) == 2:
+		communities, err := s.Graphiti.ListCommunities(ctx, groupID)
+		if err != nil {
+			log.Printf("Failed to list communities: %v", err)
+			return nil, mcpErrorf(mcpInternalError, "Failed to list communities")
+		}
+		content = gin.H{"group_id": groupID, "communities": communities}
+
+	default:
+		return nil, mcpErrorf(mcpResourceNotFound, "Resource not found: %s", uri)
+	}
+
+	text, err := json.Marshal(content)
+	if err != nil {
+		return nil, mcpErrorf(mcpInternalError, "Failed to encode resource")
+	}
+	return gin.H{"contents": []gin.H{{"uri": uri, "mimeType": "application/json", "text": string(text)}}}, nil
+}
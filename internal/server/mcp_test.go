@@ -0,0 +1,326 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/pkg/carbon"
+	"github.com/agenthands/carbon/pkg/carbontest"
+)
+
+// testMCPResponse is an mcpResponse with its result left encoded.
+type testMCPResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *mcpError       `json:"error"`
+}
+
+// mcpCall sends a JSON-RPC request in session and returns the response.
+func mcpCall(t *testing.T, r http.Handler, session, method string, params interface{}) (int, testMCPResponse) {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(body))
+	if session != "" {
+		req.Header.Set(mcpSessionHeader, session)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp testMCPResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	}
+	return w.Code, resp
+}
+
+// mcpInitialize starts a session and returns its ID.
+func mcpInitialize(t *testing.T, r http.Handler) string {
+	body := `{"jsonrpc": "2.0", "id": 0, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "test", "version": "1"}}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/mcp", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Result struct {
+			ProtocolVersion string                     `json:"protocolVersion"`
+			Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2025-03-26", resp.Result.ProtocolVersion)
+	assert.JSONEq(t, `{"listChanged": true}`, string(resp.Result.Capabilities["resources"]))
+	session := w.Header().Get(mcpSessionHeader)
+	require.NotEmpty(t, session)
+	return session
+}
+
+// mcpRead reads a resource and returns its text.
+func mcpRead(t *testing.T, r http.Handler, session, uri string) (string, *mcpError) {
+	_, resp := mcpCall(t, r, session, "resources/read", map[string]string{"uri": uri})
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+	var result struct {
+		Contents []struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, uri, result.Contents[0].URI)
+	return result.Contents[0].Text, nil
+}
+
+func TestMCPResources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+	_, err := driver.Migrate(ctx, g.Driver, driver.Migrations)
+	require.NoError(t, err)
+	r := (&Server{Graphiti: g}).SetupMCPRouter()
+
+	alice := carbontest.NewEntity("g1", "Alice", "Alice is an engineer")
+	berlin := carbontest.NewEntity("g1", "Berlin", "")
+	bob := carbontest.NewEntity("g2", "Bob", "")
+	fact := carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin, bob},
+		Facts:    []carbon.EntityEdge{fact},
+	}))
+	for _, group := range []string{"g1", "g2"} {
+		_, err := g.Driver.ExecuteQuery(ctx, driver.EnsureGroupQuery, map[string]interface{}{"group_id": group, "created_at": "2024-01-01T00:00:00Z"})
+		require.NoError(t, err)
+	}
+
+	// Requests need a live session
+	code, _ := mcpCall(t, r, "", "ping", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = mcpCall(t, r, "unknown", "ping", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	session := mcpInitialize(t, r)
+	code, resp := mcpCall(t, r, session, "ping", nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, resp.Error)
+	_, resp = mcpCall(t, r, session, "nonexistent", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcpMethodNotFound, resp.Error.Code)
+
+	_, resp = mcpCall(t, r, session, "resources/templates/list", nil)
+	assert.Contains(t, string(resp.Result), `"uriTemplate":"carbon://group/{group_id}/entity/{uuid}"`)
+	_, resp = mcpCall(t, r, session, "resources/list", nil)
+	for _, uri := range []string{"carbon://group/g1/entities", "carbon://group/g1/episodes", "carbon://group/g2/communities"} {
+		assert.Contains(t, string(resp.Result), `"uri":"`+uri+`"`)
+	}
+
+	// Listings link to their items, which are read by URI
+	text, rpcErr := mcpRead(t, r, session, "carbon://group/g1/entities")
+	require.Nil(t, rpcErr)
+	var entities struct {
+		Entities []mcpRef `json:"entities"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &entities))
+	require.Len(t, entities.Entities, 2)
+	assert.Equal(t, "Alice", entities.Entities[0].Name)
+	assert.Equal(t, "Alice is an engineer", entities.Entities[0].Summary)
+
+	text, rpcErr = mcpRead(t, r, session, entities.Entities[0].URI)
+	require.Nil(t, rpcErr)
+	assert.Contains(t, text, "Alice lives in Berlin")
+
+	_, rpcErr = mcpRead(t, r, session, "carbon://group/g2/entity/"+alice.UUID)
+	require.NotNil(t, rpcErr, "an entity is only found under its own group")
+	assert.Equal(t, mcpResourceNotFound, rpcErr.Code)
+	_, rpcErr = mcpRead(t, r, session, "carbon://group/g1/facts")
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, mcpResourceNotFound, rpcErr.Code)
+
+	text, rpcErr = mcpRead(t, r, session, "carbon://group/g1/communities")
+	require.Nil(t, rpcErr)
+	assert.JSONEq(t, `{"group_id": "g1", "communities": []}`, text)
+
+	// Tools search memory
+	_, resp = mcpCall(t, r, session, "tools/call", map[string]interface{}{
+		"name": "search_memory", "arguments": map[string]string{"group_id": "g1", "query": "lives in"},
+	})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, string(resp.Result), "Alice lives in Berlin")
+	assert.Contains(t, string(resp.Result), `"isError":false`)
+	_, resp = mcpCall(t, r, session, "tools/call", map[string]interface{}{"name": "search_memory", "arguments": map[string]string{}})
+	assert.Contains(t, string(resp.Result), `"isError":true`)
+
+	// An open stream is told when a group's memory changes
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	req, err := http.NewRequest("GET", srv.URL+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set(mcpSessionHeader, session)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	require.NoError(t, g.DeleteFact(ctx, fact.UUID))
+	lines := bufio.NewScanner(stream.Body)
+	var data string
+	for lines.Scan() {
+		if d, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			data = d
+			break
+		}
+	}
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "method": "notifications/resources/list_changed"}`, data)
+
+	// Ending the session ends its stream
+	w := httptest.NewRecorder()
+	del := httptest.NewRequest("DELETE", "/mcp", nil)
+	del.Header.Set(mcpSessionHeader, session)
+	r.ServeHTTP(w, del)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	for lines.Scan() {
+	}
+	code, _ = mcpCall(t, r, session, "ping", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMCPPrompts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+	r := (&Server{Graphiti: g}).SetupMCPRouter()
+
+	alice := carbontest.NewEntity("g1", "Alice", "")
+	berlin := carbontest.NewEntity("g1", "Berlin", "")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin},
+		Facts:    []carbon.EntityEdge{carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")},
+	}))
+	session := mcpInitialize(t, r)
+
+	_, resp := mcpCall(t, r, session, "prompts/list", nil)
+	assert.Contains(t, string(resp.Result), `"name":"recall_context"`)
+	assert.Contains(t, string(resp.Result), `"name":"update_memory_from_conversation"`)
+
+	getPrompt := func(name string, args map[string]string) (string, *mcpError) {
+		_, resp := mcpCall(t, r, session, "prompts/get", map[string]interface{}{"name": name, "arguments": args})
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		var result struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		require.Len(t, result.Messages, 1)
+		assert.Equal(t, "user", result.Messages[0].Role)
+		return result.Messages[0].Content.Text, nil
+	}
+
+	// Prompts come back with the facts their arguments find
+	text, rpcErr := getPrompt("recall_context", map[string]string{"group_id": "g1", "query": "where does Alice live"})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, text, "- Alice lives in Berlin")
+
+	text, rpcErr = getPrompt("update_memory_from_conversation", map[string]string{
+		"group_id": "g1", "conversation": "user: Alice lives in Munich now",
+	})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, text, "- Alice lives in Berlin")
+	assert.Contains(t, text, "user: Alice lives in Munich now")
+	assert.Contains(t, text, "add_memory")
+
+	text, rpcErr = getPrompt("recall_context", map[string]string{"group_id": "g2", "query": "anything"})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, text, "Memory holds no facts about this yet.")
+
+	for _, c := range []struct {
+		name string
+		args map[string]string
+	}{
+		{"recall_context", map[string]string{"group_id": "g1"}},
+		{"recall_context", map[string]string{"group_id": "g1", "query": "x", "max_facts": "many"}},
+		{"nonexistent", nil},
+	} {
+		_, rpcErr := getPrompt(c.name, c.args)
+		require.NotNil(t, rpcErr, c.name)
+		assert.Equal(t, mcpInvalidParams, rpcErr.Code)
+	}
+}
+
+func TestMCPGroupBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+	g.Config.MCP.GroupBinding = "session"
+	srv := &Server{Graphiti: g}
+	r := srv.SetupMCPRouter()
+
+	a, b := mcpInitialize(t, r), mcpInitialize(t, r)
+	alice := carbontest.NewEntity("mcp-"+a, "Alice", "")
+	berlin := carbontest.NewEntity("mcp-"+a, "Berlin", "")
+	fact := carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin},
+		Facts:    []carbon.EntityEdge{fact},
+	}))
+	search := func(session string, args map[string]string) string {
+		_, resp := mcpCall(t, r, session, "tools/call", map[string]interface{}{"name": "search_memory", "arguments": args})
+		require.Nil(t, resp.Error)
+		return string(resp.Result)
+	}
+
+	// Each session works on its own group without naming it, and on no other
+	assert.Contains(t, search(a, map[string]string{"query": "lives in"}), "Alice lives in Berlin")
+	assert.Contains(t, search(a, map[string]string{"group_id": "mcp-" + a, "query": "lives in"}), "Alice lives in Berlin")
+	assert.Contains(t, search(b, map[string]string{"query": "lives in"}), "No facts found.")
+	assert.Contains(t, search(b, map[string]string{"group_id": "mcp-" + a, "query": "lives in"}), "works on group mcp-"+b+" only")
+
+	_, resp := mcpCall(t, r, a, "tools/list", nil)
+	assert.NotContains(t, string(resp.Result), `"required":["group_id"`)
+	_, resp = mcpCall(t, r, a, "prompts/get", map[string]interface{}{"name": "recall_context", "arguments": map[string]string{"query": "lives in"}})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, string(resp.Result), "Alice lives in Berlin")
+
+	_, resp = mcpCall(t, r, b, "resources/list", nil)
+	assert.Contains(t, string(resp.Result), `"uri":"carbon://group/mcp-`+b+`/entities"`)
+	assert.NotContains(t, string(resp.Result), "mcp-"+a)
+	_, rpcErr := mcpRead(t, r, b, "carbon://group/mcp-"+a+"/entities")
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, mcpResourceNotFound, rpcErr.Code)
+
+	// Only the sessions bound to a changed group are told
+	require.NoError(t, g.DeleteFact(ctx, fact.UUID))
+	assert.Len(t, srv.mcp.sessions[a].events, 1)
+	assert.Len(t, srv.mcp.sessions[b].events, 0)
+
+	// Under the key binding, a key's sessions share its group
+	g.Config.MCP.GroupBinding = "key"
+	withKey := func(key string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if key != "" {
+				req.Header.Set("Authorization", "Bearer "+key)
+			}
+			r.ServeHTTP(w, req)
+		})
+	}
+	_, resp = mcpCall(t, withKey(""), "", "initialize", map[string]interface{}{"protocolVersion": "2025-06-18"})
+	require.NotNil(t, resp.Error, "the key binding needs a key")
+	first, second := mcpInitialize(t, withKey("k1")), mcpInitialize(t, withKey("k1"))
+	assert.Equal(t, srv.mcp.sessions[first].groupID, srv.mcp.sessions[second].groupID)
+	assert.NotEqual(t, srv.mcp.sessions[first].groupID, srv.mcp.sessions[mcpInitialize(t, withKey("k2"))].groupID)
+	code, _ := mcpCall(t, withKey("k2"), first, "ping", nil)
+	assert.Equal(t, http.StatusNotFound, code, "a key's sessions are not found with another key")
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+// mem0-compatible endpoints. A mem0 memory is a carbon fact, and the
+// user_id, agent_id and run_id of a request together name its group.
+
+// mem0Group returns the group of a scope: the ID itself when only one is set,
+// so a user's memories share the group other APIs use for them, and
+// "user:<id>|agent:<id>|run:<id>" with the IDs set otherwise.
+func mem0Group(scope api.Mem0Scope) string {
+	var parts []string
+	for _, id := range []struct{ kind, id string }{{"user", scope.UserID}, {"agent", scope.AgentID}, {"run", scope.RunID}} {
+		if id.id != "" {
+			parts = append(parts, id.kind+":"+id.id)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		_, id, _ := strings.Cut(parts[0], ":")
+		return id
+	}
+	return strings.Join(parts, "|")
+}
+
+func toMem0Memory(e model.EntityEdge, scope api.Mem0Scope, event string) api.Mem0Memory {
+	m := api.Mem0Memory{
+		ID:       e.UUID,
+		Memory:   e.Fact,
+		Event:    event,
+		UserID:   scope.UserID,
+		AgentID:  scope.AgentID,
+		RunID:    scope.RunID,
+		Metadata: map[string]interface{}{"relation": e.Name},
+	}
+	if !e.CreatedAt.IsZero() {
+		created := e.CreatedAt
+		m.CreatedAt = &created
+	}
+	return m
+}
+
+// AddMemories ingests the messages and reports the facts the ingest created
+// ("ADD") or invalidated ("DELETE"), in the order it made the changes.
+func (s *Server) AddMemories(c *gin.Context) {
+	var req api.Mem0AddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	groupID := mem0Group(req.Mem0Scope)
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id, agent_id or run_id is required"})
+		return
+	}
+	ctx, changes := core.WithFactChanges(c.Request.Context())
+
+	for _, msg := range req.Messages {
+		if err := s.Graphiti.AddEpisode(ctx, groupID, "message", msg.Content, "", ""); err != nil {
+			if llmUnavailable(c, err) || groupFull(c, err) || contentBlocked(c, err) {
+				return
+			}
+			log.Printf("Failed to add episode: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add memories"})
+			return
+		}
+	}
+
+	results := []api.Mem0Memory{}
+	for _, change := range changes.List() {
+		fact, err := s.Graphiti.GetFact(ctx, change.UUID)
+		if errors.Is(err, core.ErrFactNotFound) {
+			continue // Deleted since, or hidden from the caller
+		}
+		if err != nil {
+			log.Printf("Failed to get memory: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add memories"})
+			return
+		}
+		event := "ADD"
+		if change.Op == model.ChangeOpInvalidate {
+			event = "DELETE"
+		}
+		results = append(results, toMem0Memory(*fact, req.Mem0Scope, event))
+	}
+
+	c.JSON(http.StatusOK, api.Mem0Results{Results: results})
+}
+
+func (s *Server) ListMemories(c *gin.Context) {
+	scope := api.Mem0Scope{UserID: c.Query("user_id"), AgentID: c.Query("agent_id"), RunID: c.Query("run_id")}
+	groupID := mem0Group(scope)
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id, agent_id or run_id is required"})
+		return
+	}
+
+	facts, err := s.Graphiti.ListFacts(c.Request.Context(), groupID)
+	if err != nil {
+		log.Printf("Failed to list memories: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list memories"})
+		return
+	}
+
+	results := make([]api.Mem0Memory, len(facts))
+	for i, e := range facts {
+		results[i] = toMem0Memory(e, scope, "")
+	}
+	c.JSON(http.StatusOK, api.Mem0Results{Results: results})
+}
+
+func (s *Server) GetMemory(c *gin.Context) {
+	fact, err := s.Graphiti.GetFact(c.Request.Context(), c.Param("memory_id"))
+	if errors.Is(err, core.ErrFactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Memory not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get memory: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get memory"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toMem0Memory(*fact, api.Mem0Scope{}, ""))
+}
+
+func (s *Server) SearchMemories(c *gin.Context) {
+	var req api.Mem0SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	groupID := mem0Group(req.Mem0Scope)
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id, agent_id or run_id is required"})
+		return
+	}
+
+	facts, err := s.Graphiti.Search(c.Request.Context(), groupID, req.Query)
+	if err != nil {
+		log.Printf("Failed to search memories: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search memories"})
+		return
+	}
+	if req.Limit > 0 && len(facts) > req.Limit {
+		facts = facts[:req.Limit]
+	}
+
+	results := make([]api.Mem0Memory, len(facts))
+	for i, e := range facts {
+		results[i] = toMem0Memory(e, req.Mem0Scope, "")
+	}
+	c.JSON(http.StatusOK, api.Mem0Results{Results: results})
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+func TestMem0Group(t *testing.T) {
+	for _, c := range []struct {
+		scope api.Mem0Scope
+		group string
+	}{
+		{api.Mem0Scope{}, ""},
+		{api.Mem0Scope{UserID: "alice"}, "alice"},
+		{api.Mem0Scope{AgentID: "planner"}, "planner"},
+		{api.Mem0Scope{UserID: "alice", AgentID: "planner"}, "user:alice|agent:planner"},
+		{api.Mem0Scope{UserID: "alice", AgentID: "coder"}, "user:alice|agent:coder"},
+		{api.Mem0Scope{UserID: "alice", RunID: "r1"}, "user:alice|run:r1"},
+		{api.Mem0Scope{UserID: "alice", AgentID: "planner", RunID: "r1"}, "user:alice|agent:planner|run:r1"},
+	} {
+		assert.Equal(t, c.group, mem0Group(c.scope), "%+v", c.scope)
+	}
+}
@@ -1,24 +1,112 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/agenthands/carbon/internal/config"
 	"github.com/agenthands/carbon/internal/core"
 	"github.com/agenthands/carbon/internal/core/model"
 	"github.com/agenthands/carbon/internal/driver"
 	"github.com/agenthands/carbon/internal/llm"
+	"github.com/agenthands/carbon/pkg/api"
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
 	Graphiti *core.Graphiti
+
+	groupLocksMu sync.Mutex
+	groupLocks   map[string]*sync.Mutex // Graphiti-compat ingestion, one queue per group
+	compatIngest sync.WaitGroup         // Graphiti-compat ingestion still running, drained by Close
+
+	mcp *mcpHub // Sessions of SetupMCPRouter
 }
 
 func NewServer() *Server {
+	cfg := LoadConfig()
+
+	g, err := NewEngine(cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if g.SummaryQueue != nil {
+		go g.SummaryQueue.Run(context.Background())
+	}
+
+	if cfg.OrphanGC.IntervalMinutes > 0 {
+		go g.RunOrphanGC(context.Background(), time.Duration(cfg.OrphanGC.IntervalMinutes)*time.Minute)
+	}
+
+	if cfg.Compaction.IntervalMinutes > 0 {
+		go g.RunCompaction(context.Background(), time.Duration(cfg.Compaction.IntervalMinutes)*time.Minute)
+	}
+
+	if cfg.Contradictions.IntervalMinutes > 0 {
+		go g.RunContradictionScan(context.Background(), time.Duration(cfg.Contradictions.IntervalMinutes)*time.Minute)
+	}
+
+	// Groups with retention_days set lose their expired episodes hourly
+	go g.RunRetention(context.Background(), time.Hour)
+
+	if cfg.Backup.IntervalMinutes > 0 && g.Backups != nil {
+		go g.RunBackups(context.Background(), time.Duration(cfg.Backup.IntervalMinutes)*time.Minute)
+	}
+
+	// Resume ingest jobs interrupted by a previous crash or restart
+	go func() {
+		resumed, err := g.ResumeIngestJobs(context.Background())
+		if err != nil {
+			log.Printf("Failed to resume ingest jobs: %v", err)
+		}
+		if len(resumed) > 0 {
+			log.Printf("Resumed %d ingest jobs", len(resumed))
+		}
+	}()
+
+	return &Server{
+		Graphiti: g,
+	}
+}
+
+// Close waits for queued Graphiti-compat messages, writes the summaries still
+// queued and closes the graph driver. Call it once the listeners stopped
+// taking requests.
+func (s *Server) Close(ctx context.Context) error {
+	var errs []error
+	drained := make(chan struct{})
+	go func() {
+		s.compatIngest.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("queued messages still processing: %w", ctx.Err()))
+	}
+	if s.Graphiti.SummaryQueue != nil {
+		if err := s.Graphiti.SummaryQueue.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush summaries: %w", err))
+		}
+	}
+	errs = append(errs, s.Graphiti.Driver.Close(ctx))
+	return errors.Join(errs...)
+}
+
+// LoadConfig reads CONFIG_PATH (default config/config.toml) and applies the
+// environment overrides shared by the server and the maintenance command.
+func LoadConfig() *config.Config {
 	// 1. Load Config
 	cfgPath := os.Getenv("CONFIG_PATH")
 	if cfgPath == "" {
@@ -45,68 +133,197 @@ func NewServer() *Server {
 		cfg.LLM.BaseURL = envBaseURL
 	}
 
-	// 3. Initialize Memgraph Driver
-	// Use config URI/User, default if missing
-	if cfg.Memgraph.URI == "" {
-		cfg.Memgraph.URI = "bolt://localhost:7687"
+	// Golden-file record/replay for deterministic runs
+	if envReplayMode := os.Getenv("LLM_REPLAY_MODE"); envReplayMode != "" {
+		cfg.LLM.ReplayMode = envReplayMode
+	}
+	if envReplayDir := os.Getenv("LLM_REPLAY_DIR"); envReplayDir != "" {
+		cfg.LLM.ReplayDir = envReplayDir
 	}
 
-	d, err := driver.NewMemgraphDriver(cfg.Memgraph.URI, cfg.Memgraph.User, cfg.Memgraph.Password)
-	if err != nil {
-		log.Fatalf("Failed to connect to Memgraph: %v", err)
+	// Optional read replica
+	if envReadURI := os.Getenv("GRAPH_READ_URI"); envReadURI != "" {
+		cfg.Graph.ReadURI = envReadURI
 	}
 
-	// 4. Default LLM if missing
+	// 3. Default LLM if missing
 	if cfg.LLM.Provider == "" {
 		cfg.LLM.Provider = "ollama"
 		cfg.LLM.Model = "gpt-oss:latest"
 		cfg.LLM.BaseURL = "http://localhost:11434"
 	}
+	return cfg
+}
+
+// NewEngine connects the graph driver and LLM clients described by cfg.
+func NewEngine(cfg *config.Config) (*core.Graphiti, error) {
+	d, err := driver.NewFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize graph driver: %w", err)
+	}
+	if _, err := driver.Migrate(context.Background(), d, driver.Migrations); err != nil {
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to migrate graph: %w", err)
+	}
 
-	// 5. Initialize LLM Client via Factory
 	llmClient, embedderClient, err := llm.NewClient(context.Background(), cfg.LLM)
 	if err != nil {
-		log.Fatalf("Failed to initialize LLM client: %v", err)
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to initialize LLM client: %w", err)
 	}
 
-	g := core.NewGraphiti(d, llmClient, embedderClient, nil, cfg)
+	embedders, err := llm.NewEmbedders(context.Background(), cfg.Embedding.Models)
+	if err != nil {
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to initialize embedders: %w", err)
+	}
 
-	return &Server{
-		Graphiti: g,
+	cipher, err := core.NewCipher(cfg.Encryption)
+	if err != nil {
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+
+	backups, err := core.NewBackupStore(cfg.Backup)
+	if err != nil {
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to initialize backup store: %w", err)
+	}
+
+	contentStore, err := core.NewContentStore(cfg.ContentStore)
+	if err != nil {
+		d.Close(context.Background())
+		return nil, fmt.Errorf("failed to initialize content store: %w", err)
 	}
+
+	g := core.NewGraphiti(d, llmClient, embedderClient, nil, cfg)
+	g.UseEmbedders(embedders)
+	g.Cipher = cipher
+	g.Backups = backups
+	g.ContentStore = contentStore
+	return g, nil
 }
 
 func (s *Server) SetupRouter() *gin.Engine {
 	r := gin.Default()
+	r.Use(s.authenticate)
+	r.Use(s.prioritize)
 
 	r.POST("/messages", s.AddMessages)
+	r.POST("/episodes/structured", s.AddStructuredEpisode)
+	r.POST("/sessions", s.CreateSession)
+	r.GET("/sessions/:id", s.GetSession)
+	r.POST("/sessions/:id/messages", s.AddSessionMessages)
 	r.POST("/search", s.Search)
 	r.POST("/communities/detect", s.DetectCommunities)
+	r.GET("/communities/detect/:group_id", s.GetCommunityDetection)
 	r.POST("/bulk/messages", s.BulkAddEpisodes)
+	r.POST("/bulk/messages/retry", s.RetryBulkEpisodes)
+	r.POST("/bulk/messages/stream", s.StreamBulkEpisodes)
 	r.POST("/bulk/search", s.BulkSearch)
+	r.GET("/entities/:uuid", s.GetEntity)
+	r.GET("/entities/:uuid/neighborhood", s.GetNeighborhood)
+	r.PATCH("/entities/:uuid", s.UpdateEntity)
+	r.POST("/entities/:uuid/summarize", s.RegenerateSummary)
+	r.POST("/entities/:uuid/gaps", s.FindFactGaps)
+	r.GET("/facts/:uuid", s.GetFact)
+	r.GET("/facts/:uuid/provenance", s.GetProvenance)
+	r.POST("/jobs/ingest", s.CreateIngestJob)
+	r.GET("/jobs/:id", s.GetIngestJob)
+	r.GET("/deadletters", s.ListDeadLetters)
+	r.POST("/deadletters/:id/requeue", s.RequeueDeadLetter)
+	r.GET("/episodes", s.ListEpisodes)
+	r.GET("/changes", s.GetChanges)
+	r.GET("/graph", s.GetGraph)
+	r.POST("/profile", s.SynthesizeProfile)
+	r.GET("/groups", s.ListGroups)
+	r.GET("/groups/:id", s.GetGroup)
+	r.PATCH("/groups/:id", s.requireAdmin, s.UpdateGroup)
+	r.GET("/groups/:id/stats", s.GetGroupStats)
+	r.GET("/groups/:id/top-entities", s.TopEntities)
+	r.GET("/groups/:id/gaps", s.FindKnowledgeGaps)
+	r.GET("/groups/:id/contradictions", s.GetContradictions)
+	r.GET("/groups/:id/moderation", s.requireAdmin, s.ListModerationRecords)
+	r.GET("/groups/:id/export", s.requireAdmin, s.ExportGroup)
+	r.GET("/groups/:id/synonyms", s.GetSynonyms)
+	r.PATCH("/groups/:id/synonyms", s.requireAdmin, s.UpdateSynonyms)
+	r.GET("/groups/:id/scratchpad", s.ListScratch)
+	r.GET("/groups/:id/scratchpad/:key", s.GetScratch)
+	r.PUT("/groups/:id/scratchpad/:key", s.SetScratch)
+	r.DELETE("/groups/:id/scratchpad/:key", s.DeleteScratch)
+	r.GET("/openapi.json", s.OpenAPI)
+
+	maintenance := r.Group("/maintenance", s.requireAdmin)
+	maintenance.POST("/consistency", s.CheckConsistency)
+	maintenance.GET("/consistency/:group_id", s.GetConsistencyReport)
+	maintenance.POST("/contradictions", s.ScanContradictions)
+	maintenance.POST("/dedupe-edges", s.DedupeEdges)
+	maintenance.GET("/dedupe-edges/:group_id", s.GetDedupeEdgesReport)
+	maintenance.POST("/orphans", s.CollectOrphans)
+	maintenance.GET("/orphans/:group_id", s.GetOrphanReport)
+	maintenance.POST("/compact", s.CompactEpisodes)
+	maintenance.GET("/compact/:group_id", s.GetCompactionReport)
+	maintenance.POST("/reembed", s.Reembed)
+	maintenance.GET("/reembed/:group_id", s.GetReembedReport)
+
+	admin := r.Group("/admin", s.requireAdmin)
+	admin.GET("/backups", s.ListBackups)
+	admin.POST("/backups", s.CreateBackup)
+	admin.POST("/restore", s.RestoreBackup)
+	admin.GET("/concurrency", s.GetConcurrency)
+	admin.PATCH("/concurrency", s.UpdateConcurrency)
+
+	debug := r.Group("/debug", s.requireDebug, s.requireAdmin)
+	debug.POST("/ingest-profile", s.ProfileIngest)
+	if s.debugEnabled() {
+		debug.GET("/pprof/*profile", s.pprof)
+		debug.POST("/pprof/*profile", s.pprof)
+	}
+
+	r.POST("/v1/memories/", s.AddMemories)
+	r.GET("/v1/memories/", s.ListMemories)
+	r.POST("/v1/memories/search/", s.SearchMemories)
+	r.GET("/v1/memories/:memory_id/", s.GetMemory)
 
 	return r
 }
 
-type AddMessageRequest struct {
-	GroupID  string `json:"group_id"`
-	Saga     string `json:"saga"`
-	Schema   string `json:"schema"` // Optional schema/instruction
-	Messages []struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	} `json:"messages"`
+// OpenAPI serves the OpenAPI document describing api.Routes.
+func (s *Server) OpenAPI(c *gin.Context) {
+	spec, err := api.OpenAPIJSON()
+	if err != nil {
+		log.Printf("Failed to build OpenAPI document: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build OpenAPI document"})
+		return
+	}
+	c.Data(http.StatusOK, "application/json", spec)
 }
 
 func (s *Server) AddMessages(c *gin.Context) {
-	var req AddMessageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	var req api.AddMessageRequest
+	if !bindJSON(c, &req) {
 		return
 	}
+	contents := make([]string, len(req.Messages))
+	for i, msg := range req.Messages {
+		contents[i] = msg.Content
+	}
+	if !s.checkContent(c, "messages[%d].content", contents) {
+		return
+	}
+	if req.User != nil {
+		if _, err := s.Graphiti.EnsureUserEntity(c.Request.Context(), req.GroupID, *req.User); err != nil {
+			log.Printf("Failed to update user entity: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user entity"})
+			return
+		}
+	}
 
 	for _, msg := range req.Messages {
 		err := s.Graphiti.AddEpisode(c.Request.Context(), req.GroupID, "message", msg.Content, req.Saga, req.Schema)
+		if llmUnavailable(c, err) || groupFull(c, err) || contentBlocked(c, err) {
+			return
+		}
 		if err != nil {
 			log.Printf("Failed to add episode: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process message"})
@@ -114,60 +331,215 @@ func (s *Server) AddMessages(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	c.JSON(http.StatusOK, api.StatusResponse{Status: "success"})
+}
+
+func (s *Server) AddStructuredEpisode(c *gin.Context) {
+	var req api.StructuredEpisodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := s.Graphiti.AddStructuredEpisode(c.Request.Context(), req.GroupID, req.StructuredEpisode)
+	if errors.Is(err, core.ErrInvalidStructuredEpisode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if groupFull(c, err) || contentBlocked(c, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to add structured episode: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process structured episode"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (s *Server) CreateSession(c *gin.Context) {
+	var req api.CreateSessionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	session, err := s.Graphiti.CreateSession(c.Request.Context(), req.GroupID, req.Name)
+	if err != nil {
+		log.Printf("Failed to create session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (s *Server) GetSession(c *gin.Context) {
+	session, err := s.Graphiti.GetSession(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, core.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
 }
 
-type SearchRequest struct {
-	GroupID string `json:"group_id"`
-	Query   string `json:"query"`
+func (s *Server) AddSessionMessages(c *gin.Context) {
+	var req api.SessionMessagesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	contents := make([]string, len(req.Messages))
+	for i, msg := range req.Messages {
+		contents[i] = msg.Content
+	}
+	if !s.checkContent(c, "messages[%d].content", contents) {
+		return
+	}
+
+	for _, msg := range req.Messages {
+		err := s.Graphiti.AddSessionMessage(c.Request.Context(), c.Param("id"), msg.Role, msg.Content, req.Schema)
+		if errors.Is(err, core.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		if llmUnavailable(c, err) || groupFull(c, err) || contentBlocked(c, err) {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to add session message: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process message"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, api.StatusResponse{Status: "success"})
 }
 
 func (s *Server) Search(c *gin.Context) {
-	var req SearchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	var req api.SearchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if req.Filter != nil {
+		if err := req.Filter.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	switch req.Mode {
+	case "", "facts", "entities":
+	case "paths":
+		paths, err := s.Graphiti.SearchPaths(c.Request.Context(), req.GroupID, req.Query, req.Limit)
+		if err != nil {
+			log.Printf("Failed to search paths: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
+			return
+		}
+		c.JSON(http.StatusOK, api.SearchResponse{Paths: paths})
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be 'facts', 'entities' or 'paths'"})
 		return
 	}
 
-	results, err := s.Graphiti.Search(c.Request.Context(), req.GroupID, req.Query)
+	var resp api.SearchResponse
+	var err error
+	if req.Debug {
+		resp.Results, resp.Trace, err = s.Graphiti.SearchDebug(c.Request.Context(), req.GroupID, req.Query, req.Filter)
+	} else {
+		resp.Results, err = s.Graphiti.SearchWithFilter(c.Request.Context(), req.GroupID, req.Query, req.Filter)
+	}
+	if err == nil {
+		coverage := s.Graphiti.Coverage(resp.Results)
+		resp.Coverage = &coverage
+	}
+	if err == nil && req.Mode == "entities" {
+		resp.Entities, err = s.Graphiti.GroupByEntity(c.Request.Context(), resp.Results)
+		resp.Results = nil
+	}
 	if err != nil {
 		log.Printf("Failed to search: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"results": results})
-}
-
-type DetectRequest struct {
-	GroupID string `json:"group_id"`
+	c.JSON(http.StatusOK, resp)
 }
 
 func (s *Server) DetectCommunities(c *gin.Context) {
-	var req DetectRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	var req api.DetectRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	if err := s.Graphiti.DetectAndSummarizeCommunities(c.Request.Context(), req.GroupID); err != nil {
+	err := s.Graphiti.DetectAndSummarizeCommunities(c.Request.Context(), req.GroupID)
+	if errors.Is(err, core.ErrDetectionRunning) {
+		if !req.Wait {
+			c.JSON(http.StatusConflict, gin.H{"error": "Community detection already running for group"})
+			return
+		}
+		// Answer with the outcome of the running detection
+		var detection *model.CommunityDetection
+		if detection, err = s.Graphiti.WaitCommunityDetection(c.Request.Context(), req.GroupID); err == nil && detection.Error != "" {
+			err = errors.New(detection.Error)
+		}
+	}
+	if err != nil {
 		log.Printf("Failed to detect communities: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detect communities"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	c.JSON(http.StatusOK, api.StatusResponse{Status: "success"})
 }
 
-type BulkAddRequest struct {
-	GroupID  string              `json:"group_id"`
-	Episodes []model.EpisodeData `json:"episodes"`
+// GetCommunityDetection reports the group's running or latest community detection.
+func (s *Server) GetCommunityDetection(c *gin.Context) {
+	detection, err := s.Graphiti.GetCommunityDetection(c.Param("group_id"))
+	if errors.Is(err, core.ErrDetectionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No community detection for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get community detection: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get community detection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detection)
 }
 
 func (s *Server) BulkAddEpisodes(c *gin.Context) {
-	var req BulkAddRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	var req api.BulkAddRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	// Partial ingests report oversized episodes among their results
+	if !req.Partial {
+		contents := make([]string, len(req.Episodes))
+		for i, ep := range req.Episodes {
+			contents[i] = ep.Content
+		}
+		if !s.checkContent(c, "episodes[%d].content", contents) {
+			return
+		}
+	}
+
+	if req.Partial {
+		result, err := s.Graphiti.BulkAddEpisodesPartial(c.Request.Context(), req.GroupID, req.Episodes)
+		if err != nil {
+			log.Printf("Failed to bulk add episodes: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process bulk episodes"})
+			return
+		}
+		c.JSON(http.StatusOK, result)
 		return
 	}
 
@@ -177,27 +549,1042 @@ func (s *Server) BulkAddEpisodes(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	c.JSON(http.StatusOK, api.StatusResponse{Status: "success"})
 }
 
-type BulkSearchRequest struct {
-	GroupID string                  `json:"group_id"`
-	Queries []model.BulkSearchQuery `json:"queries"`
+// RetryBulkEpisodes takes the result of a partial bulk ingest and re-ingests its failed episodes.
+func (s *Server) RetryBulkEpisodes(c *gin.Context) {
+	var req model.BulkIngestResult
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := s.Graphiti.RetryFailed(c.Request.Context(), &req)
+	if err != nil {
+		log.Printf("Failed to retry bulk episodes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry bulk episodes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
-func (s *Server) BulkSearch(c *gin.Context) {
-	var req BulkSearchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+// maxStreamLine bounds a single NDJSON episode in the streaming endpoint.
+const maxStreamLine = 4 << 20
+
+// StreamBulkEpisodes reads NDJSON episodes from the request body and writes one
+// NDJSON status line per episode as it completes. The group_id query parameter
+// is the default for lines that don't set their own.
+func (s *Server) StreamBulkEpisodes(c *gin.Context) {
+	ctx := c.Request.Context()
+	defaultGroup := c.Query("group_id")
+
+	// Keep reading the body after the first status line is written
+	if err := http.NewResponseController(c.Writer).EnableFullDuplex(); err != nil {
+		log.Printf("Full duplex unavailable for streaming ingest: %v", err)
+	}
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	in := make(chan model.StreamEpisode)
+	out := make(chan model.StreamResult)
+	go s.Graphiti.StreamEpisodes(ctx, in, out)
+
+	go func() {
+		defer close(in)
+		scanner := bufio.NewScanner(c.Request.Body)
+		scanner.Buffer(make([]byte, 64*1024), maxStreamLine)
+		index := 0
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var ep model.StreamEpisode
+			err := json.Unmarshal(line, &ep)
+			ep.Index = index
+			index++
+			if ep.GroupID == "" {
+				ep.GroupID = defaultGroup
+			}
+			switch {
+			case err != nil:
+				out <- model.StreamResult{Index: ep.Index, Status: model.EpisodeStatusFailed, Error: "invalid episode: " + err.Error()}
+				continue
+			case ep.GroupID == "":
+				out <- model.StreamResult{Index: ep.Index, Status: model.EpisodeStatusFailed, Error: "group_id is required"}
+				continue
+			}
+			in <- ep
+		}
+		if err := scanner.Err(); err != nil {
+			log.Printf("Failed to read streaming ingest body: %v", err)
+			out <- model.StreamResult{Index: index, Status: model.EpisodeStatusFailed, Error: "failed to read request body: " + err.Error()}
+		}
+	}()
+
+	enc := json.NewEncoder(c.Writer)
+	for res := range out {
+		// Keep draining after a client disconnect so the workers can finish
+		if err := enc.Encode(res); err == nil {
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (s *Server) GetEntity(c *gin.Context) {
+	node, err := s.Graphiti.GetEntity(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get entity: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entity"})
 		return
 	}
 
-	results, err := s.Graphiti.BulkSearch(c.Request.Context(), req.GroupID, req.Queries)
+	c.JSON(http.StatusOK, node)
+}
+
+func (s *Server) GetNeighborhood(c *gin.Context) {
+	depth := core.DefaultNeighborhoodDepth
+	if d := c.Query("depth"); d != "" {
+		v, err := strconv.Atoi(d)
+		if err != nil || v < 0 || v > core.MaxNeighborhoodDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("depth must be an integer from 0 to %d", core.MaxNeighborhoodDepth)})
+			return
+		}
+		depth = v
+	}
+
+	hood, err := s.Graphiti.GetNeighborhood(c.Request.Context(), c.Param("uuid"), depth)
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to bulk search: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bulk search"})
+		log.Printf("Failed to get neighborhood: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get neighborhood"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hood)
+}
+
+func (s *Server) UpdateEntity(c *gin.Context) {
+	var req model.EntityPatch
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	node, err := s.Graphiti.UpdateEntity(c.Request.Context(), c.Param("uuid"), req)
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if errors.Is(err, core.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Entity was modified since the given version"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update entity: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update entity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, node)
+}
+
+func (s *Server) RegenerateSummary(c *gin.Context) {
+	node, err := s.Graphiti.RegenerateSummary(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to regenerate summary: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, node)
+}
+
+// GetFact returns a fact by UUID or by citation handle.
+func (s *Server) GetFact(c *gin.Context) {
+	id := c.Param("uuid")
+	var fact *model.EntityEdge
+	var err error
+	if core.IsCitationHandle(id) {
+		fact, err = s.Graphiti.ResolveCitation(c.Request.Context(), c.Query("group_id"), id)
+	} else {
+		fact, err = s.Graphiti.GetFact(c.Request.Context(), id)
+	}
+	if errors.Is(err, core.ErrFactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fact not found"})
+		return
+	}
+	if errors.Is(err, core.ErrCitationGroupRequired) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get fact: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get fact"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fact)
+}
+
+// GetProvenance returns a fact with its source episodes, fetching any
+// content offloaded to the content store.
+func (s *Server) GetProvenance(c *gin.Context) {
+	prov, err := s.Graphiti.GetProvenance(c.Request.Context(), c.Param("uuid"))
+	if errors.Is(err, core.ErrFactNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fact not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get provenance: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get provenance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prov)
+}
+
+func (s *Server) FindFactGaps(c *gin.Context) {
+	var req api.FactGapsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Checklist == "" && len(req.Attributes) == 0 && len(req.Relations) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "checklist, attributes or relations is required"})
+		return
+	}
+
+	gaps, err := s.Graphiti.FindFactGaps(c.Request.Context(), c.Param("uuid"), req.Checklist,
+		model.FactChecklist{Attributes: req.Attributes, Relations: req.Relations})
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if errors.Is(err, core.ErrChecklistNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to find fact gaps: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find fact gaps"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
+
+func (s *Server) CheckConsistency(c *gin.Context) {
+	var req api.ConsistencyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := s.Graphiti.CheckSummaryConsistency(c.Request.Context(), req.GroupID, req.Regenerate)
+	if err != nil {
+		log.Printf("Failed to check summary consistency: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check summary consistency"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) GetConsistencyReport(c *gin.Context) {
+	report, err := s.Graphiti.GetConsistencyReport(c.Request.Context(), c.Param("group_id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No consistency report for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get consistency report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get consistency report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) ScanContradictions(c *gin.Context) {
+	var req api.ContradictionsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := s.Graphiti.ScanContradictions(c.Request.Context(), req.GroupID)
+	if err != nil {
+		log.Printf("Failed to scan for contradictions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan for contradictions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) DedupeEdges(c *gin.Context) {
+	var req api.DedupeEdgesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := s.Graphiti.DedupeEdges(c.Request.Context(), req.GroupID, req.DryRun)
+	if err != nil {
+		log.Printf("Failed to dedupe edges: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dedupe edges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) GetDedupeEdgesReport(c *gin.Context) {
+	report, err := s.Graphiti.GetDedupeEdgesReport(c.Request.Context(), c.Param("group_id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No dedupe-edges report for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get dedupe-edges report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dedupe-edges report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) CollectOrphans(c *gin.Context) {
+	var req api.OrphanGCRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Mode != "" && req.Mode != model.OrphanModeQuarantine && req.Mode != model.OrphanModeDelete {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be 'quarantine' or 'delete'"})
+		return
+	}
+
+	report, err := s.Graphiti.CollectOrphans(c.Request.Context(), req.GroupID, req.Mode, req.DryRun)
+	if err != nil {
+		log.Printf("Failed to collect orphans: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to collect orphans"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) GetOrphanReport(c *gin.Context) {
+	report, err := s.Graphiti.GetOrphanReport(c.Request.Context(), c.Param("group_id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No orphan report for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get orphan report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get orphan report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) CompactEpisodes(c *gin.Context) {
+	var req api.CompactRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := s.Graphiti.CompactEpisodes(c.Request.Context(), req.GroupID, req.DryRun)
+	if err != nil {
+		if llmUnavailable(c, err) {
+			return
+		}
+		log.Printf("Failed to compact episodes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compact episodes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) GetCompactionReport(c *gin.Context) {
+	report, err := s.Graphiti.GetCompactionReport(c.Request.Context(), c.Param("group_id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No compaction report for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get compaction report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get compaction report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Reembed migrates a group's embeddings to a configured embedding model.
+func (s *Server) Reembed(c *gin.Context) {
+	var req api.ReembedRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := s.Graphiti.Reembed(c.Request.Context(), req.GroupID, req.Model, req.DryRun)
+	if errors.Is(err, core.ErrEmbeddingModelNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to re-embed group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to re-embed group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) GetReembedReport(c *gin.Context) {
+	report, err := s.Graphiti.GetReembedReport(c.Request.Context(), c.Param("group_id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No reembed report for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get reembed report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reembed report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CreateIngestJob checkpoints a bulk load and runs it in the background. Poll GET /jobs/:id for progress.
+func (s *Server) CreateIngestJob(c *gin.Context) {
+	var req api.BulkAddRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	contents := make([]string, len(req.Episodes))
+	for i, ep := range req.Episodes {
+		contents[i] = ep.Content
+	}
+	if !s.checkContent(c, "episodes[%d].content", contents) {
+		return
+	}
+
+	job, err := s.Graphiti.CreateIngestJob(c.Request.Context(), req.GroupID, req.Episodes)
+	if err != nil {
+		log.Printf("Failed to create ingest job: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ingest job"})
+		return
+	}
+
+	// The job outlives the request; a restart resumes it from the last checkpoint
+	go func(jobID string) {
+		if _, err := s.Graphiti.RunIngestJob(context.Background(), jobID); err != nil {
+			log.Printf("Ingest job %s failed: %v", jobID, err)
+		}
+	}(job.UUID)
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (s *Server) GetIngestJob(c *gin.Context) {
+	job, err := s.Graphiti.GetIngestJob(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, core.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get ingest job: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get ingest job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (s *Server) ListDeadLetters(c *gin.Context) {
+	letters, err := s.Graphiti.ListDeadLetters(c.Request.Context(), c.Query("group_id"))
+	if err != nil {
+		log.Printf("Failed to list dead letters: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list dead letters"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.DeadLettersResponse{DeadLetters: letters})
+}
+
+// RequeueDeadLetter re-ingests a dead letter. If it fails again the dead
+// letter is kept with the new error.
+func (s *Server) RequeueDeadLetter(c *gin.Context) {
+	letter, err := s.Graphiti.RequeueDeadLetter(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, core.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead letter not found"})
+		return
+	}
+	if llmUnavailable(c, err) || groupFull(c, err) || contentBlocked(c, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to requeue dead letter: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to requeue dead letter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// groupFull answers 429 for episodes rejected by a group's size limits.
+func groupFull(c *gin.Context, err error) bool {
+	if !errors.Is(err, core.ErrGroupLimit) {
+		return false
+	}
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	return true
+}
+
+// contentBlocked answers 422 for episodes moderation blocked.
+func contentBlocked(c *gin.Context, err error) bool {
+	if !errors.Is(err, core.ErrContentBlocked) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	return true
+}
+
+// llmUnavailable answers 503 for errors from an open LLM circuit breaker. The
+// failed episode has been kept as a dead letter.
+func llmUnavailable(c *gin.Context, err error) bool {
+	if !errors.Is(err, llm.ErrCircuitOpen) {
+		return false
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM provider is unavailable; the episode was kept as a dead letter"})
+	return true
+}
+
+func (s *Server) BulkSearch(c *gin.Context) {
+	var req api.BulkSearchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if req.Union {
+		merged, err := s.Graphiti.BulkSearchUnion(c.Request.Context(), req.GroupID, req.Queries)
+		if err != nil {
+			log.Printf("Failed to bulk search: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bulk search"})
+			return
+		}
+		if merged == nil {
+			merged = []model.UnionSearchResult{}
+		}
+		c.JSON(http.StatusOK, api.BulkSearchResponse{Union: merged})
+		return
+	}
+
+	results, err := s.Graphiti.BulkSearch(c.Request.Context(), req.GroupID, req.Queries)
+	if err != nil {
+		log.Printf("Failed to bulk search: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bulk search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.BulkSearchResponse{Results: results})
+}
+
+func (s *Server) ListEpisodes(c *gin.Context) {
+	groupID := c.Query("group_id")
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id is required"})
+		return
+	}
+	lastN := 20
+	if v := c.Query("last_n"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "last_n must be between 1 and 1000"})
+			return
+		}
+		lastN = n
+	}
+
+	var episodes []model.EpisodicNode
+	var err error
+	if topic := c.Query("topic"); topic != "" {
+		episodes, err = s.Graphiti.GetEpisodesByTopic(c.Request.Context(), groupID, []string{topic}, lastN)
+	} else {
+		episodes, err = s.Graphiti.GetEpisodes(c.Request.Context(), groupID, lastN)
+	}
+	if err != nil {
+		log.Printf("Failed to list episodes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list episodes"})
+		return
+	}
+	if episodes == nil {
+		episodes = []model.EpisodicNode{}
+	}
+
+	c.JSON(http.StatusOK, api.EpisodesResponse{Episodes: episodes})
+}
+
+func (s *Server) GetChanges(c *gin.Context) {
+	groupID := c.Query("group_id")
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id is required"})
+		return
+	}
+	var since int64
+	if v := c.Query("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a non-negative integer"})
+			return
+		}
+		since = n
+	}
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		limit = n
+	}
+
+	changes, err := s.Graphiti.GetChanges(c.Request.Context(), groupID, since, limit)
+	if err != nil {
+		log.Printf("Failed to get changes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get changes"})
+		return
+	}
+	if changes == nil {
+		changes = []model.Change{}
+	}
+
+	c.JSON(http.StatusOK, api.ChangesResponse{Changes: changes})
+}
+
+func (s *Server) SynthesizeProfile(c *gin.Context) {
+	var req api.ProfileRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	profile, err := s.Graphiti.SynthesizeProfile(c.Request.Context(), req.GroupID, req.Refresh)
+	if errors.Is(err, core.ErrNothingToProfile) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group has no entities to profile"})
+		return
+	}
+	if err != nil {
+		if llmUnavailable(c, err) {
+			return
+		}
+		log.Printf("Failed to synthesize profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to synthesize profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (s *Server) GetGraph(c *gin.Context) {
+	groupID := c.Query("group_id")
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_id is required"})
+		return
+	}
+
+	depth := 1
+	if d := c.Query("depth"); d != "" {
+		v, err := strconv.Atoi(d)
+		if err != nil || v < 0 || v > core.MaxNeighborhoodDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("depth must be an integer from 0 to %d", core.MaxNeighborhoodDepth)})
+			return
+		}
+		depth = v
+	}
+
+	format := c.DefaultQuery("format", "d3")
+	if format == "html" {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", renderGraphViewer(c.Request.URL.Query()))
+		return
+	}
+
+	view, err := s.Graphiti.GetGraphView(c.Request.Context(), groupID, c.Query("center"), depth)
+	if errors.Is(err, core.ErrEntityNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Center entity not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to build graph view: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build graph"})
+		return
+	}
+
+	switch format {
+	case "d3":
+		c.JSON(http.StatusOK, view)
+	case "cytoscape":
+		c.JSON(http.StatusOK, toCytoscape(view))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of d3, cytoscape, html"})
+	}
+}
+
+func (s *Server) GetGroupStats(c *gin.Context) {
+	stats, err := s.Graphiti.GetGroupStats(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		log.Printf("Failed to get group stats: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get group stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (s *Server) TopEntities(c *gin.Context) {
+	limit := core.DefaultTopEntities
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = n
+	}
+
+	entities, err := s.Graphiti.TopEntities(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		log.Printf("Failed to rank entities: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rank entities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.TopEntitiesResponse{Entities: entities})
+}
+
+func (s *Server) FindKnowledgeGaps(c *gin.Context) {
+	limit := core.DefaultGapEntities
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = n
+	}
+
+	gaps, err := s.Graphiti.FindKnowledgeGaps(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		if llmUnavailable(c, err) {
+			return
+		}
+		log.Printf("Failed to find knowledge gaps: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find knowledge gaps"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
+
+func (s *Server) ListModerationRecords(c *gin.Context) {
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+		limit = n
+	}
+
+	records, err := s.Graphiti.ListModerationRecords(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		log.Printf("Failed to list moderation records: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list moderation records"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.ModerationResponse{Records: records})
+}
+
+func (s *Server) GetContradictions(c *gin.Context) {
+	report, err := s.Graphiti.GetContradictionReport(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, core.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No contradiction scan for group"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get contradictions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get contradictions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) ListGroups(c *gin.Context) {
+	groups, err := s.Graphiti.ListGroups(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to list groups: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.GroupsResponse{Groups: groups})
+}
+
+func (s *Server) GetGroup(c *gin.Context) {
+	group, err := s.Graphiti.GetGroup(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, core.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+func (s *Server) UpdateGroup(c *gin.Context) {
+	var req model.GroupPatch
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	group, err := s.Graphiti.UpdateGroup(c.Request.Context(), c.Param("id"), req)
+	if errors.Is(err, core.ErrInvalidGroupSettings) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+func (s *Server) ExportGroup(c *gin.Context) {
+	export, err := s.Graphiti.ExportGraph(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		log.Printf("Failed to export group: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+func (s *Server) GetSynonyms(c *gin.Context) {
+	synonyms, err := s.Graphiti.GetSynonyms(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		log.Printf("Failed to get synonyms: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get synonyms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SynonymsResponse{Synonyms: synonyms})
+}
+
+func (s *Server) UpdateSynonyms(c *gin.Context) {
+	var req api.SynonymsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	synonyms, err := s.Graphiti.UpdateSynonyms(c.Request.Context(), c.Param("id"), req.Synonyms)
+	if errors.Is(err, core.ErrInvalidGroupSettings) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update synonyms: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update synonyms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SynonymsResponse{Synonyms: synonyms})
+}
+
+func (s *Server) ListScratch(c *gin.Context) {
+	entries, err := s.Graphiti.ListScratch(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		log.Printf("Failed to list scratchpad: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list scratchpad"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.ScratchpadResponse{Entries: entries})
+}
+
+func (s *Server) GetScratch(c *gin.Context) {
+	entry, err := s.Graphiti.GetScratch(c.Request.Context(), c.Param("id"), c.Param("key"))
+	if errors.Is(err, core.ErrScratchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scratchpad entry not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get scratchpad entry: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scratchpad entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (s *Server) SetScratch(c *gin.Context) {
+	var req api.ScratchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	// Checked here: binding "required" would also reject false, 0 and ""
+	if req.Value == nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "Invalid request", Fields: []api.FieldError{{Field: "value", Constraint: "required", Message: "is required"}}})
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	entry, err := s.Graphiti.SetScratch(c.Request.Context(), c.Param("id"), c.Param("key"), req.Value, ttl)
+	if err != nil {
+		log.Printf("Failed to set scratchpad entry: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set scratchpad entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (s *Server) DeleteScratch(c *gin.Context) {
+	err := s.Graphiti.DeleteScratch(c.Request.Context(), c.Param("id"), c.Param("key"))
+	if errors.Is(err, core.ErrScratchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scratchpad entry not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete scratchpad entry: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scratchpad entry"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.StatusResponse{Status: "success"})
+}
+
+func (s *Server) ListBackups(c *gin.Context) {
+	backups, err := s.Graphiti.ListBackups(c.Request.Context(), c.Query("group_id"))
+	if errors.Is(err, core.ErrBackupsDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backups are not configured"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to list backups: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list backups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.BackupsResponse{Backups: backups})
+}
+
+// CreateBackup snapshots the requested group, or runs a scheduled-style backup
+// (including retention) when no group is given.
+func (s *Server) CreateBackup(c *gin.Context) {
+	var req api.BackupRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	var backups []model.BackupInfo
+	var err error
+	if req.GroupID != "" {
+		var info *model.BackupInfo
+		if info, err = s.Graphiti.Backup(c.Request.Context(), req.GroupID); err == nil {
+			backups = append(backups, *info)
+		}
+	} else {
+		backups, err = s.Graphiti.RunBackup(c.Request.Context())
+	}
+	if errors.Is(err, core.ErrBackupsDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backups are not configured"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to back up: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to back up"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, api.BackupsResponse{Backups: backups})
+}
+
+func (s *Server) RestoreBackup(c *gin.Context) {
+	var req api.RestoreRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if (req.GroupID == "") == !req.Graph {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set either group_id or graph"})
+		return
+	}
+
+	report, err := s.Graphiti.RestoreBackup(c.Request.Context(), req.GroupID, req.Snapshot)
+	switch {
+	case errors.Is(err, core.ErrBackupsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backups are not configured"})
+		return
+	case errors.Is(err, core.ErrBackupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+		return
+	case errors.Is(err, core.ErrInvalidBackup):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to restore backup: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore backup"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetConcurrency reports the bulk ingest and bulk search limits with their
+// queue depth and wait times.
+func (s *Server) GetConcurrency(c *gin.Context) {
+	c.JSON(http.StatusOK, s.Graphiti.ConcurrencyStats())
+}
+
+// UpdateConcurrency changes the bulk ingest and bulk search limits until the
+// server restarts.
+func (s *Server) UpdateConcurrency(c *gin.Context) {
+	var req model.ConcurrencyPatch
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	stats, err := s.Graphiti.UpdateConcurrency(req)
+	if errors.Is(err, core.ErrInvalidConcurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update concurrency: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update concurrency"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"results": results})
+	c.JSON(http.StatusOK, stats)
 }
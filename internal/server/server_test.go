@@ -0,0 +1,26 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+// The OpenAPI document and generated clients are built from api.Routes; keep it in sync with the router.
+func TestRoutesMatchAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var registered []string
+	for _, r := range (&Server{}).SetupRouter().Routes() {
+		if r.Path != "/openapi.json" {
+			registered = append(registered, r.Method+" "+r.Path)
+		}
+	}
+	var declared []string
+	for _, r := range api.Routes {
+		declared = append(declared, r.Method+" "+r.Path)
+	}
+	assert.ElementsMatch(t, declared, registered)
+}
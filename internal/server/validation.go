@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agenthands/carbon/internal/llm"
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by their JSON names rather than their Go names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// bindJSON decodes and validates the request body into obj. On failure it
+// writes a 400 listing the offending fields and returns false.
+func bindJSON(c *gin.Context, obj any) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+	c.JSON(http.StatusBadRequest, validationError(err))
+	return false
+}
+
+func validationError(err error) api.ErrorResponse {
+	var verrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &verrs):
+		resp := api.ErrorResponse{Error: "Invalid request"}
+		for _, fe := range verrs {
+			resp.Fields = append(resp.Fields, fieldError(fe))
+		}
+		return resp
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "."
+		}
+		return api.ErrorResponse{Error: "Invalid request", Fields: []api.FieldError{{
+			Field:      field,
+			Constraint: "type",
+			Param:      typeErr.Type.String(),
+			Message:    fmt.Sprintf("must be %s, got %s", jsonType(typeErr.Type), typeErr.Value),
+		}}}
+	case errors.As(err, &syntaxErr):
+		return api.ErrorResponse{Error: fmt.Sprintf("Invalid JSON at offset %d: %v", syntaxErr.Offset, syntaxErr)}
+	case errors.Is(err, io.EOF):
+		return api.ErrorResponse{Error: "Request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return api.ErrorResponse{Error: "Request body is truncated JSON"}
+	}
+	return api.ErrorResponse{Error: "Invalid request: " + err.Error()}
+}
+
+// fieldError describes one failed constraint. Its path drops the request type's name.
+func fieldError(fe validator.FieldError) api.FieldError {
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+	out := api.FieldError{Field: field, Constraint: fe.Tag(), Param: fe.Param()}
+	unit := "characters"
+	if k := fe.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+		unit = "items"
+	}
+	if fe.Param() == "1" {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	switch fe.Tag() {
+	case "required":
+		out.Message = "is required"
+	case "min":
+		out.Message = fmt.Sprintf("must have at least %s %s", fe.Param(), unit)
+	case "max":
+		out.Message = fmt.Sprintf("must have at most %s %s", fe.Param(), unit)
+	default:
+		out.Message = fmt.Sprintf("failed the %q constraint", fe.Tag())
+	}
+	return out
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+// checkContent writes a 413 listing every content the [ingest] limits would
+// reject, named like path ("messages[%d].content"), and reports whether all fit.
+func (s *Server) checkContent(c *gin.Context, path string, contents []string) bool {
+	var fields []api.FieldError
+	for i, content := range contents {
+		if s.Graphiti.CheckContent(content) == nil {
+			continue
+		}
+		limit := s.Graphiti.ContentLimit()
+		fields = append(fields, api.FieldError{
+			Field:      fmt.Sprintf(path, i),
+			Constraint: "max",
+			Param:      strconv.Itoa(limit),
+			Message: fmt.Sprintf("must have at most %d characters (about %d tokens), has %d",
+				limit, limit/llm.CharsPerToken, utf8.RuneCountInString(content)),
+		})
+	}
+	if len(fields) == 0 {
+		return true
+	}
+	c.JSON(http.StatusRequestEntityTooLarge, api.ErrorResponse{Error: "Content too large", Fields: fields})
+	return false
+}
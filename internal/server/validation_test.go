@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/agenthands/carbon/pkg/carbontest"
+)
+
+func TestRequestValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := (&Server{Graphiti: carbontest.NewEngine(nil)}).SetupRouter()
+	post := func(path, body string) (int, api.ErrorResponse) {
+		var resp api.ErrorResponse
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", path, strings.NewReader(body)))
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	code, resp := post("/messages", `{"group_id": "g1", "messages": []}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []api.FieldError{
+		{Field: "messages", Constraint: "min", Param: "1", Message: "must have at least 1 item"},
+	}, resp.Fields)
+
+	code, resp = post("/messages", `{"group_id": "g1", "messages": [{"role": "`+strings.Repeat("x", 65)+`", "content": "Hi"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []api.FieldError{
+		{Field: "messages[0].role", Constraint: "max", Param: "64", Message: "must have at most 64 characters"},
+	}, resp.Fields)
+
+	_, resp = post("/bulk/messages", `{"episodes": [{"content": ""}]}`)
+	assert.Len(t, resp.Fields, 2)
+
+	_, resp = post("/search", `{"group_id": 1, "query": "tea"}`)
+	assert.Equal(t, []api.FieldError{
+		{Field: "group_id", Constraint: "type", Param: "string", Message: "must be a string, got number"},
+	}, resp.Fields)
+
+	code, resp = post("/search", `{"group_id": "g1",`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "Request body is truncated JSON", resp.Error)
+
+	_, resp = post("/search", ``)
+	assert.Equal(t, "Request body is empty", resp.Error)
+}
+
+func TestContentLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(nil)
+	g.Config.Ingest.MaxContentChars = 10
+	r := (&Server{Graphiti: g}).SetupRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/bulk/messages", strings.NewReader(
+		`{"group_id": "g1", "episodes": [{"content": "short"}, {"content": "far too long"}]}`)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp api.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []api.FieldError{{
+		Field: "episodes[1].content", Constraint: "max", Param: "10",
+		Message: "must have at most 10 characters (about 2 tokens), has 12",
+	}}, resp.Fields)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/gin-gonic/gin"
+)
+
+// toCytoscape converts a GraphView into Cytoscape.js "elements" JSON.
+func toCytoscape(view *model.GraphView) gin.H {
+	nodes := make([]gin.H, 0, len(view.Nodes))
+	for _, n := range view.Nodes {
+		nodes = append(nodes, gin.H{"data": n})
+	}
+	edges := make([]gin.H, 0, len(view.Links))
+	for _, l := range view.Links {
+		edges = append(edges, gin.H{"data": l})
+	}
+	return gin.H{"elements": gin.H{"nodes": nodes, "edges": edges}}
+}
+
+var graphViewerTemplate = template.Must(template.New("viewer").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Carbon graph: {{.GroupID}}</title>
+<style>
+  body { margin: 0; font-family: sans-serif; }
+  svg { width: 100vw; height: 100vh; }
+  .link { stroke: #999; stroke-opacity: .6; }
+  .node circle { fill: #4a90d9; stroke: #fff; stroke-width: 1.5px; }
+  .node text { font-size: 11px; }
+</style>
+<script src="https://d3js.org/d3.v7.min.js"></script>
+</head>
+<body>
+<svg></svg>
+<script>
+fetch({{.DataURL}}).then(r => r.json()).then(graph => {
+  const svg = d3.select("svg");
+  const width = window.innerWidth, height = window.innerHeight;
+  const sim = d3.forceSimulation(graph.nodes)
+    .force("link", d3.forceLink(graph.links).id(d => d.id).distance(120))
+    .force("charge", d3.forceManyBody().strength(-300))
+    .force("center", d3.forceCenter(width / 2, height / 2));
+  const link = svg.append("g").selectAll("line").data(graph.links).join("line").attr("class", "link");
+  link.append("title").text(d => d.fact);
+  const node = svg.append("g").selectAll("g").data(graph.nodes).join("g").attr("class", "node");
+  node.append("circle").attr("r", d => d.depth === 0 ? 9 : 6);
+  node.append("text").attr("dx", 10).attr("dy", 4).text(d => d.label);
+  node.append("title").text(d => d.summary || d.label);
+  sim.on("tick", () => {
+    link.attr("x1", d => d.source.x).attr("y1", d => d.source.y)
+        .attr("x2", d => d.target.x).attr("y2", d => d.target.y);
+    node.attr("transform", d => "translate(" + d.x + "," + d.y + ")");
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// renderGraphViewer returns an HTML page that loads the same query as D3 JSON and renders it.
+func renderGraphViewer(query url.Values) []byte {
+	dataQuery := url.Values{}
+	for k, v := range query {
+		dataQuery[k] = v
+	}
+	dataQuery.Set("format", "d3")
+
+	var buf bytes.Buffer
+	graphViewerTemplate.Execute(&buf, map[string]string{
+		"GroupID": query.Get("group_id"),
+		"DataURL": "/graph?" + dataQuery.Encode(),
+	})
+	return buf.Bytes()
+}
@@ -0,0 +1,304 @@
+// Package api defines the request and response bodies of carbon's HTTP API and
+// the route table the server, the OpenAPI document and the generated clients
+// are built from. Graph types in the bodies are the same types package carbon
+// re-exports, e.g. carbon.EntityEdge.
+package api
+
+import (
+	"time"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+type Message struct {
+	Role    string `json:"role" binding:"max=64"`
+	Content string `json:"content" binding:"required"`
+}
+
+type AddMessageRequest struct {
+	GroupID  string    `json:"group_id" binding:"required"`
+	Saga     string    `json:"saga"`
+	Schema   string    `json:"schema"` // Optional schema/instruction
+	Messages []Message `json:"messages" binding:"required,min=1,dive"`
+	// User describes the speaker; it updates the group's user entity
+	User *model.UserProfile `json:"user,omitempty"`
+}
+
+// StructuredEpisodeRequest ingests entities and facts as given, without the LLM.
+type StructuredEpisodeRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	model.StructuredEpisode
+}
+
+type CreateSessionRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	Name    string `json:"name"` // Saga to continue; a new one when empty
+}
+
+type SessionMessagesRequest struct {
+	Schema   string    `json:"schema"`
+	Messages []Message `json:"messages" binding:"required,min=1,dive"`
+}
+
+type SearchRequest struct {
+	GroupID string              `json:"group_id" binding:"required"`
+	Query   string              `json:"query" binding:"required"`
+	Mode    string              `json:"mode"`  // "facts" (default), "entities" to group facts by subject, or "paths" for multi-hop fact chains
+	Limit   int                 `json:"limit"` // Max paths in "paths" mode
+	Filter  *model.SearchFilter `json:"filter"`
+	Debug   bool                `json:"debug"` // Return a trace of the "facts" search stages
+}
+
+// SearchResponse carries Results in "facts" mode, Entities in "entities"
+// mode and Paths in "paths" mode.
+type SearchResponse struct {
+	Results  []model.EntityEdge    `json:"results,omitempty"`
+	Entities []model.EntityResults `json:"entities,omitempty"`
+	Coverage *model.Coverage       `json:"coverage,omitempty"` // How confidently the facts found answer the query
+	Paths    []model.FactPath      `json:"paths,omitempty"`
+	Trace    *model.SearchTrace    `json:"trace,omitempty"` // Set when the request asked for debug
+}
+
+type DetectRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	// Wait joins a detection of the group that is already running instead of failing with 409.
+	Wait bool `json:"wait,omitempty"`
+}
+
+type BulkAddRequest struct {
+	GroupID  string              `json:"group_id" binding:"required"`
+	Episodes []model.EpisodeData `json:"episodes" binding:"required,min=1,dive"`
+	Partial  bool                `json:"partial"` // Continue past failures and return per-episode results
+}
+
+type BulkSearchRequest struct {
+	GroupID string                  `json:"group_id" binding:"required"`
+	Queries []model.BulkSearchQuery `json:"queries" binding:"required,min=1,dive"`
+	Union   bool                    `json:"union"` // Merge the results of all queries into one ranked list
+}
+
+// BulkSearchResponse maps each query's ID to its results, or holds the merged
+// results of a union search.
+type BulkSearchResponse struct {
+	Results map[string][]model.EntityEdge `json:"results,omitempty"`
+	Union   []model.UnionSearchResult     `json:"union,omitempty"`
+}
+
+type FactGapsRequest struct {
+	Checklist  string   `json:"checklist"` // Name of a checklist in the group settings
+	Attributes []string `json:"attributes"`
+	Relations  []string `json:"relations"`
+}
+
+type ConsistencyRequest struct {
+	GroupID    string `json:"group_id" binding:"required"`
+	Regenerate bool   `json:"regenerate"` // Rebuild summaries flagged as inconsistent
+}
+
+type ContradictionsRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+}
+
+type DedupeEdgesRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	DryRun  bool   `json:"dry_run"` // Report duplicates without merging them
+}
+
+type OrphanGCRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	Mode    string `json:"mode"`    // "quarantine" or "delete"; defaults to the configured mode
+	DryRun  bool   `json:"dry_run"` // List orphans without collecting them
+}
+
+type CompactRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	DryRun  bool   `json:"dry_run"` // List the runs of episodes without digesting them
+}
+
+type ProfileRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	Refresh bool   `json:"refresh"` // Write the profile again instead of returning the stored one
+}
+
+type ReembedRequest struct {
+	GroupID string `json:"group_id" binding:"required"`
+	Model   string `json:"model"`   // Name of a configured embedding model; defaults to the active one
+	DryRun  bool   `json:"dry_run"` // Count stale embeddings without re-embedding them
+}
+
+type BackupRequest struct {
+	GroupID string `json:"group_id"` // Group to snapshot; empty snapshots as the [backup] scope says
+}
+
+// BackupQuery holds the query parameters of GET /admin/backups.
+type BackupQuery struct {
+	GroupID string `query:"group_id"` // Only this group's snapshots; all when empty
+}
+
+type RestoreRequest struct {
+	GroupID  string `json:"group_id"`                    // Group to replace; required unless graph is set
+	Snapshot string `json:"snapshot" binding:"required"` // Backup ID, or "latest"
+	Graph    bool   `json:"graph"`                       // Replace the whole graph from a whole-graph snapshot
+}
+
+type IngestProfileRequest struct {
+	Content string `json:"content"` // Episode to ingest; a built-in synthetic one when empty
+}
+
+type BackupsResponse struct {
+	Backups []model.BackupInfo `json:"backups"`
+}
+
+type FactQuery struct {
+	GroupID string `query:"group_id"` // Required to resolve a citation handle
+}
+
+type DeadLetterQuery struct {
+	GroupID string `query:"group_id"` // Only this group's dead letters; all when empty
+}
+
+type DeadLettersResponse struct {
+	DeadLetters []model.DeadLetter `json:"dead_letters"`
+}
+
+// GraphQuery holds the query parameters of GET /graph.
+type GraphQuery struct {
+	GroupID string `query:"group_id" binding:"required"`
+	Center  string `query:"center"` // Entity UUID to expand from; the whole group when empty
+	Depth   *int   `query:"depth"`  // Hops from center, default 1, at most 3
+}
+
+// NeighborhoodQuery holds the query parameters of GET /entities/:uuid/neighborhood.
+type NeighborhoodQuery struct {
+	Depth *int `query:"depth"` // Hops from the entity, default 2, at most 3
+}
+
+// EpisodesQuery holds the query parameters of GET /episodes.
+type EpisodesQuery struct {
+	GroupID string `query:"group_id" binding:"required"`
+	Topic   string `query:"topic"`  // Only episodes tagged with this topic
+	LastN   int    `query:"last_n"` // Default 20, at most 1000
+}
+
+type EpisodesResponse struct {
+	Episodes []model.EpisodicNode `json:"episodes"`
+}
+
+// ChangesQuery holds the query parameters of GET /changes.
+type ChangesQuery struct {
+	GroupID string `query:"group_id" binding:"required"`
+	Since   int    `query:"since"` // Seq of the last change seen; 0 reads from the start
+	Limit   int    `query:"limit"` // Default 100, at most 1000
+}
+
+type ChangesResponse struct {
+	Changes []model.Change `json:"changes"`
+}
+
+// StreamQuery holds the query parameters of POST /bulk/messages/stream.
+type StreamQuery struct {
+	GroupID string `query:"group_id"` // Default for lines that don't set their own
+}
+
+// TopEntitiesQuery holds the query parameters of GET /groups/:id/top-entities.
+type TopEntitiesQuery struct {
+	Limit int `query:"limit"` // Default 10, at most 100
+}
+
+type TopEntitiesResponse struct {
+	Entities []model.EntitySalience `json:"entities"`
+}
+
+// KnowledgeGapsQuery holds the query parameters of GET /groups/:id/gaps.
+type KnowledgeGapsQuery struct {
+	Limit int `query:"limit"` // Entities with gaps to report; default 10, at most 100
+}
+
+// ModerationQuery holds the query parameters of GET /groups/:id/moderation.
+type ModerationQuery struct {
+	Limit int `query:"limit"` // Default 100, at most 1000
+}
+
+type ModerationResponse struct {
+	Records []model.ModerationRecord `json:"records"`
+}
+
+type GroupsResponse struct {
+	Groups []model.GroupSummary `json:"groups"`
+}
+
+// ScratchRequest sets a scratchpad entry.
+type ScratchRequest struct {
+	Value      interface{} `json:"value"`                                           // Any JSON value; null is rejected
+	TTLSeconds int         `json:"ttl_seconds,omitempty" binding:"omitempty,min=0"` // Expire the entry this long after setting it; never when 0
+}
+
+// SynonymsRequest adds, replaces or, with null, removes synonym table entries.
+type SynonymsRequest struct {
+	Synonyms map[string]*string `json:"synonyms" binding:"required"`
+}
+
+type SynonymsResponse struct {
+	Synonyms map[string]string `json:"synonyms"`
+}
+
+type ScratchpadResponse struct {
+	Entries []model.ScratchEntry `json:"entries"`
+}
+
+type StatusResponse struct {
+	Status string `json:"status"`
+}
+
+// ErrorResponse is the body of every 4xx and 5xx response.
+// Fields lists the offending fields of a request that failed validation.
+type ErrorResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError is one request field that failed validation.
+type FieldError struct {
+	Field      string `json:"field"`           // JSON path, e.g. "messages[2].content"
+	Constraint string `json:"constraint"`      // "required", "min", "max" or "type"
+	Param      string `json:"param,omitempty"` // The constraint's limit, or the expected type
+	Message    string `json:"message"`
+}
+
+// Mem0Scope selects the memory owner in mem0-compatible requests. carbon maps
+// the first non-empty of UserID, AgentID and RunID to a group.
+type Mem0Scope struct {
+	UserID  string `json:"user_id,omitempty" query:"user_id"`
+	AgentID string `json:"agent_id,omitempty" query:"agent_id"`
+	RunID   string `json:"run_id,omitempty" query:"run_id"`
+}
+
+// Mem0AddRequest is the body of POST /v1/memories/.
+type Mem0AddRequest struct {
+	Mem0Scope
+	Messages []Message              `json:"messages"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"` // Accepted for compatibility; not stored
+}
+
+// Mem0SearchRequest is the body of POST /v1/memories/search/.
+type Mem0SearchRequest struct {
+	Mem0Scope
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// Mem0Memory is a fact in mem0's memory schema.
+type Mem0Memory struct {
+	ID        string                 `json:"id"`
+	Memory    string                 `json:"memory"`
+	Event     string                 `json:"event,omitempty"` // "ADD" or "DELETE" in add responses
+	UserID    string                 `json:"user_id,omitempty"`
+	AgentID   string                 `json:"agent_id,omitempty"`
+	RunID     string                 `json:"run_id,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt *time.Time             `json:"created_at,omitempty"`
+}
+
+type Mem0Results struct {
+	Results []Mem0Memory `json:"results"`
+}
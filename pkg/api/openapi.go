@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPI builds the OpenAPI 3.1 document of Routes. Schemas are derived from
+// the Go types' json tags; fields tagged binding:"required" are required and
+// binding min/max rules become length or item limits.
+func OpenAPI() map[string]any {
+	b := &specBuilder{schemas: make(map[string]any)}
+	paths := make(map[string]any)
+	for _, r := range Routes {
+		path, params := b.pathParams(r.Path)
+		if r.Query != nil {
+			params = append(params, b.queryParams(reflect.TypeOf(r.Query))...)
+		}
+		contentType := "application/json"
+		if r.Stream {
+			contentType = "application/x-ndjson"
+		}
+		status := r.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		op := map[string]any{
+			"operationId": r.Name,
+			"summary":     r.Summary,
+			"responses": map[string]any{
+				strconv.Itoa(status): map[string]any{
+					"description": http.StatusText(status),
+					"content":     map[string]any{contentType: map[string]any{"schema": b.schema(reflect.TypeOf(r.Response))}},
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content":     map[string]any{"application/json": map[string]any{"schema": b.schema(reflect.TypeOf(ErrorResponse{}))}},
+				},
+			},
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if r.Request != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{contentType: map[string]any{"schema": b.schema(reflect.TypeOf(r.Request))}},
+			}
+		}
+
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi":    "3.1.0",
+		"info":       map[string]any{"title": "carbon", "version": "1"},
+		"paths":      paths,
+		"components": map[string]any{"schemas": b.schemas},
+	}
+}
+
+// OpenAPIJSON returns OpenAPI() encoded as indented JSON.
+func OpenAPIJSON() ([]byte, error) {
+	return json.MarshalIndent(OpenAPI(), "", "  ")
+}
+
+// OpenAPIPath converts a gin route path to OpenAPI syntax ("/groups/{id}").
+func OpenAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// PathParams returns the names of a gin route path's parameters in order.
+func PathParams(path string) []string {
+	var names []string
+	for _, s := range strings.Split(path, "/") {
+		if strings.HasPrefix(s, ":") {
+			names = append(names, s[1:])
+		}
+	}
+	return names
+}
+
+type specBuilder struct {
+	schemas map[string]any
+}
+
+func (b *specBuilder) pathParams(path string) (string, []any) {
+	var params []any
+	for _, name := range PathParams(path) {
+		params = append(params, map[string]any{
+			"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+		})
+	}
+	return OpenAPIPath(path), params
+}
+
+func (b *specBuilder) queryParams(t reflect.Type) []any {
+	var params []any
+	for _, f := range reflect.VisibleFields(t) {
+		name := f.Tag.Get("query")
+		if name == "" {
+			continue
+		}
+		params = append(params, map[string]any{
+			"name": name, "in": "query", "required": isRequired(f), "schema": b.schema(f.Type),
+		})
+	}
+	return params
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (b *specBuilder) schema(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		if _, ok := b.schemas[t.Name()]; !ok {
+			b.schemas[t.Name()] = nil // Reserve the name before recursing
+			b.schemas[t.Name()] = b.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (b *specBuilder) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	for _, f := range JSONFields(t) {
+		props[f.Name] = withLimits(b.schema(f.Field.Type), f.Field)
+		if isRequired(f.Field) {
+			required = append(required, f.Name)
+		}
+	}
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// JSONField is a struct field as encoding/json sees it.
+type JSONField struct {
+	Name      string
+	OmitEmpty bool
+	Field     reflect.StructField
+}
+
+// JSONFields returns the fields encoding/json marshals for struct type t,
+// with embedded structs flattened.
+func JSONFields(t reflect.Type) []JSONField {
+	var fields []JSONField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous && f.Type.Kind() == reflect.Struct {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, JSONField{Name: name, OmitEmpty: strings.Contains(opts, "omitempty"), Field: f})
+	}
+	return fields
+}
+
+func isRequired(f reflect.StructField) bool {
+	return strings.Contains(f.Tag.Get("binding"), "required")
+}
+
+// withLimits adds the min and max constraints of f's binding tag to schema
+// as minLength/maxLength for strings and minItems/maxItems for arrays.
+func withLimits(schema map[string]any, f reflect.StructField) map[string]any {
+	var suffix string
+	switch schema["type"] {
+	case "string":
+		suffix = "Length"
+	case "array":
+		suffix = "Items"
+	default:
+		return schema
+	}
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		if rule == "dive" {
+			break // Later rules apply to the elements
+		}
+		key, param, _ := strings.Cut(rule, "=")
+		n, err := strconv.Atoi(param)
+		if err != nil || key != "min" && key != "max" {
+			continue
+		}
+		schema[key+suffix] = n
+	}
+	return schema
+}
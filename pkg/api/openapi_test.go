@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	data, err := OpenAPIJSON()
+	require.NoError(t, err)
+	var doc struct {
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	for _, r := range Routes {
+		assert.Contains(t, doc.Paths[OpenAPIPath(r.Path)], map[string]string{"GET": "get", "POST": "post", "PUT": "put", "PATCH": "patch", "DELETE": "delete"}[r.Method], r.Name)
+	}
+	assert.Contains(t, doc.Paths, "/groups/{id}/stats")
+	assert.Contains(t, doc.Paths["/jobs/ingest"]["post"]["responses"], "202")
+
+	consistency := doc.Components.Schemas["ConsistencyRequest"]
+	assert.Equal(t, []any{"group_id"}, consistency["required"])
+	addMessages := doc.Components.Schemas["AddMessageRequest"]
+	assert.Equal(t, []any{"group_id", "messages"}, addMessages["required"])
+	assert.Equal(t, 1.0, addMessages["properties"].(map[string]any)["messages"].(map[string]any)["minItems"])
+	role := doc.Components.Schemas["Message"]["properties"].(map[string]any)["role"].(map[string]any)
+	assert.Equal(t, 64.0, role["maxLength"])
+	assert.Contains(t, doc.Components.Schemas, "EntityEdge")
+	assert.Contains(t, doc.Components.Schemas, "ErrorResponse")
+
+	// Embedded fields are flattened
+	stream := doc.Components.Schemas["StreamEpisode"]["properties"].(map[string]any)
+	assert.Contains(t, stream, "content")
+	assert.Contains(t, stream, "group_id")
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/agenthands/carbon/internal/core/model"
+)
+
+// Route describes one endpoint. Path uses gin syntax (":id" segments).
+type Route struct {
+	Name     string // Client method name
+	Method   string
+	Path     string
+	Summary  string
+	Query    any // Struct with `query` tags, or nil
+	Request  any // JSON body, or nil
+	Response any // JSON body of a successful response
+	Status   int // Success status; 200 when zero
+	// Stream marks NDJSON endpoints: Request and Response describe one line each.
+	Stream bool
+}
+
+// Routes lists every endpoint of the HTTP API.
+var Routes = []Route{
+	{Name: "AddMessages", Method: http.MethodPost, Path: "/messages", Summary: "Ingest messages as episodes, one at a time.",
+		Request: AddMessageRequest{}, Response: StatusResponse{}},
+	{Name: "AddStructuredEpisode", Method: http.MethodPost, Path: "/episodes/structured", Summary: "Ingest entities and facts as given, without LLM extraction.",
+		Request: StructuredEpisodeRequest{}, Response: model.StructuredResult{}},
+	{Name: "CreateSession", Method: http.MethodPost, Path: "/sessions", Summary: "Start a conversation session backed by a saga, or resume the saga with the given name.",
+		Request: CreateSessionRequest{}, Response: model.Session{}, Status: http.StatusCreated},
+	{Name: "GetSession", Method: http.MethodGet, Path: "/sessions/:id", Summary: "Get a session.",
+		Response: model.Session{}},
+	{Name: "AddSessionMessages", Method: http.MethodPost, Path: "/sessions/:id/messages", Summary: "Ingest messages into a session's group and saga, in order, recording each speaker's role.",
+		Request: SessionMessagesRequest{}, Response: StatusResponse{}},
+	{Name: "Search", Method: http.MethodPost, Path: "/search", Summary: "Search facts, or multi-hop fact paths in \"paths\" mode.",
+		Request: SearchRequest{}, Response: SearchResponse{}},
+	{Name: "DetectCommunities", Method: http.MethodPost, Path: "/communities/detect", Summary: "Detect and summarize communities of a group.",
+		Request: DetectRequest{}, Response: StatusResponse{}},
+	{Name: "GetCommunityDetection", Method: http.MethodGet, Path: "/communities/detect/:group_id", Summary: "Get the running or latest community detection of a group.",
+		Response: model.CommunityDetection{}},
+	{Name: "BulkAddEpisodes", Method: http.MethodPost, Path: "/bulk/messages", Summary: "Ingest a batch of episodes. Per-episode results are only returned when partial is set.",
+		Request: BulkAddRequest{}, Response: model.BulkIngestResult{}},
+	{Name: "RetryBulkEpisodes", Method: http.MethodPost, Path: "/bulk/messages/retry", Summary: "Re-ingest the failed episodes of a partial bulk result.",
+		Request: model.BulkIngestResult{}, Response: model.BulkIngestResult{}},
+	{Name: "StreamBulkEpisodes", Method: http.MethodPost, Path: "/bulk/messages/stream", Summary: "Ingest NDJSON episodes, answering with one NDJSON status line per episode.",
+		Query: StreamQuery{}, Request: model.StreamEpisode{}, Response: model.StreamResult{}, Stream: true},
+	{Name: "BulkSearch", Method: http.MethodPost, Path: "/bulk/search", Summary: "Run several searches in one request.",
+		Request: BulkSearchRequest{}, Response: BulkSearchResponse{}},
+	{Name: "GetEntity", Method: http.MethodGet, Path: "/entities/:uuid", Summary: "Get an entity with its current version.",
+		Response: model.EntityNode{}},
+	{Name: "GetNeighborhood", Method: http.MethodGet, Path: "/entities/:uuid/neighborhood", Summary: "Get the entities within a few hops of an entity, the valid facts linking them and their communities.",
+		Query: NeighborhoodQuery{}, Response: model.Neighborhood{}},
+	{Name: "UpdateEntity", Method: http.MethodPatch, Path: "/entities/:uuid", Summary: "Update an entity, optionally only if it is still at a given version.",
+		Request: model.EntityPatch{}, Response: model.EntityNode{}},
+	{Name: "RegenerateSummary", Method: http.MethodPost, Path: "/entities/:uuid/summarize", Summary: "Rebuild an entity summary from its facts.",
+		Response: model.EntityNode{}},
+	{Name: "FindFactGaps", Method: http.MethodPost, Path: "/entities/:uuid/gaps", Summary: "List checklist attributes and relations an entity has no facts for.",
+		Request: FactGapsRequest{}, Response: model.FactGaps{}},
+	{Name: "GetFact", Method: http.MethodGet, Path: "/facts/:uuid", Summary: "Get a fact by UUID, or by the group's citation handle (F-...), including invalidated facts.",
+		Query: FactQuery{}, Response: model.EntityEdge{}},
+	{Name: "GetProvenance", Method: http.MethodGet, Path: "/facts/:uuid/provenance", Summary: "Get a fact with the full content of the episodes it was extracted from.",
+		Response: model.Provenance{}},
+	{Name: "CreateIngestJob", Method: http.MethodPost, Path: "/jobs/ingest", Summary: "Start a checkpointed background bulk ingest.",
+		Request: BulkAddRequest{}, Response: model.IngestJob{}, Status: http.StatusAccepted},
+	{Name: "CheckConsistency", Method: http.MethodPost, Path: "/maintenance/consistency", Summary: "Check entity summaries against their facts. Requires the admin scope.",
+		Request: ConsistencyRequest{}, Response: model.ConsistencyReport{}},
+	{Name: "GetConsistencyReport", Method: http.MethodGet, Path: "/maintenance/consistency/:group_id", Summary: "Get the latest consistency report of a group. Requires the admin scope.",
+		Response: model.ConsistencyReport{}},
+	{Name: "ScanContradictions", Method: http.MethodPost, Path: "/maintenance/contradictions", Summary: "Scan a group's valid facts for pairs that can't both be true. Requires the admin scope.",
+		Request: ContradictionsRequest{}, Response: model.ContradictionReport{}},
+	{Name: "DedupeEdges", Method: http.MethodPost, Path: "/maintenance/dedupe-edges", Summary: "Merge duplicate facts of a group. Requires the admin scope.",
+		Request: DedupeEdgesRequest{}, Response: model.DedupeEdgesReport{}},
+	{Name: "GetDedupeEdgesReport", Method: http.MethodGet, Path: "/maintenance/dedupe-edges/:group_id", Summary: "Get the latest dedupe-edges report of a group. Requires the admin scope.",
+		Response: model.DedupeEdgesReport{}},
+	{Name: "CollectOrphans", Method: http.MethodPost, Path: "/maintenance/orphans", Summary: "Quarantine or delete entities without facts or mentions. Requires the admin scope.",
+		Request: OrphanGCRequest{}, Response: model.OrphanReport{}},
+	{Name: "GetOrphanReport", Method: http.MethodGet, Path: "/maintenance/orphans/:group_id", Summary: "Get the latest orphan report of a group. Requires the admin scope.",
+		Response: model.OrphanReport{}},
+	{Name: "CompactEpisodes", Method: http.MethodPost, Path: "/maintenance/compact", Summary: "Replace runs of old episodes with digest episodes. Requires the admin scope.",
+		Request: CompactRequest{}, Response: model.CompactionReport{}},
+	{Name: "GetCompactionReport", Method: http.MethodGet, Path: "/maintenance/compact/:group_id", Summary: "Get the latest compaction report of a group. Requires the admin scope.",
+		Response: model.CompactionReport{}},
+	{Name: "Reembed", Method: http.MethodPost, Path: "/maintenance/reembed", Summary: "Re-embed entity names and facts stored with another embedding model. Requires the admin scope.",
+		Request: ReembedRequest{}, Response: model.ReembedReport{}},
+	{Name: "GetReembedReport", Method: http.MethodGet, Path: "/maintenance/reembed/:group_id", Summary: "Get the latest reembed report of a group. Requires the admin scope.",
+		Response: model.ReembedReport{}},
+	{Name: "ListDeadLetters", Method: http.MethodGet, Path: "/deadletters", Summary: "List episodes that failed to ingest, most recently failed first.",
+		Query: DeadLetterQuery{}, Response: DeadLettersResponse{}},
+	{Name: "RequeueDeadLetter", Method: http.MethodPost, Path: "/deadletters/:id/requeue", Summary: "Ingest a dead letter's episode again, deleting the dead letter once it succeeds.",
+		Response: model.DeadLetter{}},
+	{Name: "GetIngestJob", Method: http.MethodGet, Path: "/jobs/:id", Summary: "Get the progress of an ingest job.",
+		Response: model.IngestJob{}},
+	{Name: "ListEpisodes", Method: http.MethodGet, Path: "/episodes", Summary: "List a group's most recent episodes, newest first, optionally only those tagged with a topic.",
+		Query: EpisodesQuery{}, Response: EpisodesResponse{}},
+	{Name: "GetChanges", Method: http.MethodGet, Path: "/changes", Summary: "List a group's changes after a sequence number, oldest first.",
+		Query: ChangesQuery{}, Response: ChangesResponse{}},
+	{Name: "SynthesizeProfile", Method: http.MethodPost, Path: "/profile", Summary: "Get the group's user profile (preferences, facts, relationships, open questions), written by the LLM from the graph on first use or on refresh.",
+		Request: ProfileRequest{}, Response: model.SynthesizedProfile{}},
+	{Name: "GetGraph", Method: http.MethodGet, Path: "/graph", Summary: "Get a group's graph in D3 format. The server also accepts format=cytoscape and format=html.",
+		Query: GraphQuery{}, Response: model.GraphView{}},
+	{Name: "ListGroups", Method: http.MethodGet, Path: "/groups", Summary: "List groups.",
+		Response: GroupsResponse{}},
+	{Name: "GetGroup", Method: http.MethodGet, Path: "/groups/:id", Summary: "Get a group and its settings.",
+		Response: model.GroupNode{}},
+	{Name: "UpdateGroup", Method: http.MethodPatch, Path: "/groups/:id", Summary: "Update a group's metadata and settings. Requires the admin scope.",
+		Request: model.GroupPatch{}, Response: model.GroupNode{}},
+	{Name: "GetGroupStats", Method: http.MethodGet, Path: "/groups/:id/stats", Summary: "Get node and edge counts of a group.",
+		Response: model.GroupStats{}},
+	{Name: "TopEntities", Method: http.MethodGet, Path: "/groups/:id/top-entities", Summary: "Rank a group's entities by degree, mentions and recency.",
+		Query: TopEntitiesQuery{}, Response: TopEntitiesResponse{}},
+	{Name: "FindKnowledgeGaps", Method: http.MethodGet, Path: "/groups/:id/gaps", Summary: "Report what the group's key entities lack by their entity types' checklists and attribute schemas, with questions to ask the user.",
+		Query: KnowledgeGapsQuery{}, Response: model.KnowledgeGaps{}},
+	{Name: "GetContradictions", Method: http.MethodGet, Path: "/groups/:id/contradictions", Summary: "List the contradicting facts the latest contradiction scan found that are still valid.",
+		Response: model.ContradictionReport{}},
+	{Name: "ListModerationRecords", Method: http.MethodGet, Path: "/groups/:id/moderation", Summary: "List the audit records of the group's episodes that moderation blocked or flagged, newest first. Requires the admin scope.",
+		Query: ModerationQuery{}, Response: ModerationResponse{}},
+	{Name: "ExportGroup", Method: http.MethodGet, Path: "/groups/:id/export", Summary: "Export every node and relationship of a group with their stored properties, unfiltered by access policies. Requires the admin scope.",
+		Response: model.GraphExport{}},
+	{Name: "GetSynonyms", Method: http.MethodGet, Path: "/groups/:id/synonyms", Summary: "Get a group's synonym table, used by text search and entity linking.",
+		Response: SynonymsResponse{}},
+	{Name: "UpdateSynonyms", Method: http.MethodPatch, Path: "/groups/:id/synonyms", Summary: "Add, replace or (with null) remove entries of a group's synonym table. Requires the admin scope.",
+		Request: SynonymsRequest{}, Response: SynonymsResponse{}},
+	{Name: "ListScratch", Method: http.MethodGet, Path: "/groups/:id/scratchpad", Summary: "List a group's unexpired scratchpad entries, ordered by key.",
+		Response: ScratchpadResponse{}},
+	{Name: "GetScratch", Method: http.MethodGet, Path: "/groups/:id/scratchpad/:key", Summary: "Get a scratchpad entry.",
+		Response: model.ScratchEntry{}},
+	{Name: "SetScratch", Method: http.MethodPut, Path: "/groups/:id/scratchpad/:key", Summary: "Set a scratchpad entry, optionally expiring after a TTL. Scratchpad entries are kept outside the knowledge graph.",
+		Request: ScratchRequest{}, Response: model.ScratchEntry{}},
+	{Name: "DeleteScratch", Method: http.MethodDelete, Path: "/groups/:id/scratchpad/:key", Summary: "Delete a scratchpad entry.",
+		Response: StatusResponse{}},
+	{Name: "ListBackups", Method: http.MethodGet, Path: "/admin/backups", Summary: "List stored snapshots, newest first. Requires the admin scope.",
+		Query: BackupQuery{}, Response: BackupsResponse{}},
+	{Name: "CreateBackup", Method: http.MethodPost, Path: "/admin/backups", Summary: "Snapshot a group, or the graph as configured, to the backup store.",
+		Request: BackupRequest{}, Response: BackupsResponse{}, Status: http.StatusCreated},
+	{Name: "RestoreBackup", Method: http.MethodPost, Path: "/admin/restore", Summary: "Replace a group, or the whole graph, with a snapshot after snapshotting its current state.",
+		Request: RestoreRequest{}, Response: model.RestoreReport{}},
+	{Name: "GetConcurrency", Method: http.MethodGet, Path: "/admin/concurrency", Summary: "Get the bulk ingest and bulk search limits with their queue depth and wait times.",
+		Response: model.ConcurrencyStats{}},
+	{Name: "UpdateConcurrency", Method: http.MethodPatch, Path: "/admin/concurrency", Summary: "Change the bulk ingest and bulk search limits until the server restarts.",
+		Request: model.ConcurrencyPatch{}, Response: model.ConcurrencyStats{}},
+	{Name: "ProfileIngest", Method: http.MethodPost, Path: "/debug/ingest-profile", Summary: "Ingest an episode into a throwaway group and report how long each pipeline stage took. Requires [debug] enabled.",
+		Request: IngestProfileRequest{}, Response: model.IngestProfile{}},
+
+	// mem0-compatible memory endpoints for agent frameworks built on mem0's API
+	{Name: "AddMemories", Method: http.MethodPost, Path: "/v1/memories/", Summary: "mem0: ingest messages and return the memories they added or invalidated.",
+		Request: Mem0AddRequest{}, Response: Mem0Results{}},
+	{Name: "ListMemories", Method: http.MethodGet, Path: "/v1/memories/", Summary: "mem0: list the current memories of a user, agent or run.",
+		Query: Mem0Scope{}, Response: Mem0Results{}},
+	{Name: "GetMemory", Method: http.MethodGet, Path: "/v1/memories/:memory_id/", Summary: "mem0: get a memory by ID.",
+		Response: Mem0Memory{}},
+	{Name: "SearchMemories", Method: http.MethodPost, Path: "/v1/memories/search/", Summary: "mem0: search the memories of a user, agent or run.",
+		Request: Mem0SearchRequest{}, Response: Mem0Results{}},
+}
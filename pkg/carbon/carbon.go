@@ -0,0 +1,439 @@
+// Package carbon is the public, embeddable API of the carbon memory engine.
+//
+// It re-exports the engine, graph drivers, LLM clients and configuration from
+// carbon's internal packages so other Go services can run the knowledge graph
+// in-process without the HTTP server:
+//
+//	cfg, err := carbon.LoadConfig("config/config.toml")
+//	if err != nil { ... }
+//	g, err := carbon.Open(ctx, cfg)
+//	if err != nil { ... }
+//	defer g.Driver.Close(ctx)
+//
+//	err = g.AddEpisode(ctx, "group-1", "message", "Alice moved to Berlin.", "", "")
+//	facts, err := g.Search(ctx, "group-1", "Where does Alice live?")
+//
+// For tests and lightweight agents, pair NewMemoryDriver with any LLMClient
+// and call New directly.
+package carbon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agenthands/carbon/internal/blob"
+	"github.com/agenthands/carbon/internal/config"
+	"github.com/agenthands/carbon/internal/core"
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/internal/llm"
+)
+
+// Engine
+
+type (
+	Graphiti  = core.Graphiti
+	Cipher    = core.Cipher
+	BlobStore = blob.Store
+
+	PreExtractHook  = core.PreExtractHook
+	PostExtractHook = core.PostExtractHook
+	PreSaveHook     = core.PreSaveHook
+	PostSearchHook  = core.PostSearchHook
+
+	FactChange  = core.FactChange
+	FactChanges = core.FactChanges
+)
+
+// Configuration
+
+type (
+	Config               = config.Config
+	LLMConfig            = config.LLMConfig
+	BreakerConfig        = config.BreakerConfig
+	MemgraphConfig       = config.MemgraphConfig
+	GraphConfig          = config.GraphConfig
+	GraphRouteConfig     = config.GraphRouteConfig
+	GraphShardConfig     = config.GraphShardConfig
+	ConcurrencyConfig    = config.ConcurrencyConfig
+	ExtractionPrompts    = config.ExtractionPrompts
+	DeduplicationPrompts = config.DeduplicationPrompts
+	SummaryPrompts       = config.SummaryPrompts
+	SummaryQueueConfig   = config.SummaryQueueConfig
+	OrphanGCConfig       = config.OrphanGCConfig
+	CompactionConfig     = config.CompactionConfig
+	ContradictionsConfig = config.ContradictionsConfig
+	SearchConfig         = config.SearchConfig
+	SearchCacheConfig    = config.SearchCacheConfig
+	EmbeddingConfig      = config.EmbeddingConfig
+	EmbedderConfig       = config.EmbedderConfig
+	IngestConfig         = config.IngestConfig
+	AccessConfig         = config.AccessConfig
+	AccessPolicy         = config.AccessPolicy
+	APIKeyConfig         = config.APIKeyConfig
+	EncryptionConfig     = config.EncryptionConfig
+	BackupConfig         = config.BackupConfig
+	BlobStoreConfig      = config.BlobStoreConfig
+	ContentStoreConfig   = config.ContentStoreConfig
+	TransformConfig      = config.TransformConfig
+	ModerationConfig     = config.ModerationConfig
+)
+
+// Drivers
+
+type (
+	GraphDriver    = driver.GraphDriver
+	MemgraphDriver = driver.MemgraphDriver
+	MemoryDriver   = driver.MemoryDriver
+	SQLiteDriver   = driver.SQLiteDriver
+	RoutedDriver   = driver.RoutedDriver
+	Route          = driver.Route
+	ShardedDriver  = driver.ShardedDriver
+	Shard          = driver.Shard
+)
+
+// LLM clients
+
+type (
+	LLMClient      = llm.LLMClient
+	EmbedderClient = llm.EmbedderClient
+	RerankerClient = llm.RerankerClient
+	Moderator      = llm.Moderator
+	NamedEmbedder  = llm.NamedEmbedder
+	CircuitBreaker = llm.CircuitBreaker
+	TokenCounter   = llm.TokenCounter
+)
+
+// Graph model
+
+type (
+	EntityNode        = model.EntityNode
+	EntityPatch       = model.EntityPatch
+	EpisodicNode      = model.EpisodicNode
+	CommunityNode     = model.CommunityNode
+	SagaNode          = model.SagaNode
+	EntityEdge        = model.EntityEdge
+	EpisodeData       = model.EpisodeData
+	EpisodeResult     = model.EpisodeResult
+	BulkIngestResult  = model.BulkIngestResult
+	StreamEpisode     = model.StreamEpisode
+	StreamResult      = model.StreamResult
+	StructuredEpisode = model.StructuredEpisode
+	StructuredEntity  = model.StructuredEntity
+	StructuredFact    = model.StructuredFact
+	StructuredResult  = model.StructuredResult
+	ExtractedEntity   = model.ExtractedEntity
+	ExtractedEdge     = model.ExtractedEdge
+	BulkSearchQuery   = model.BulkSearchQuery
+	UnionSearchResult = model.UnionSearchResult
+	SearchFilter      = model.SearchFilter
+	DateRange         = model.DateRange
+	FactPath          = model.FactPath
+	FactChecklist     = model.FactChecklist
+	FactGaps          = model.FactGaps
+	KnowledgeGaps     = model.KnowledgeGaps
+	EntityGaps        = model.EntityGaps
+	PathNode          = model.PathNode
+	GraphView         = model.GraphView
+	Neighborhood      = model.Neighborhood
+	GroupStats        = model.GroupStats
+	EntitySalience    = model.EntitySalience
+	GroupSummary      = model.GroupSummary
+	GroupNode         = model.GroupNode
+	GroupSettings     = model.GroupSettings
+	GroupPatch        = model.GroupPatch
+	GroupLimits       = model.GroupLimits
+	PromptOverrides   = model.PromptOverrides
+	IngestJob         = model.IngestJob
+	GraphExport       = model.GraphExport
+	ExportNode        = model.ExportNode
+	ExportEdge        = model.ExportEdge
+	BackupInfo        = model.BackupInfo
+	RestoreReport     = model.RestoreReport
+	Provenance        = model.Provenance
+	DeadLetter        = model.DeadLetter
+	ModerationPolicy  = model.ModerationPolicy
+	ModerationRecord  = model.ModerationRecord
+
+	ConsistencyReport   = model.ConsistencyReport
+	EntityConsistency   = model.EntityConsistency
+	DedupeEdgesReport   = model.DedupeEdgesReport
+	MergedEdge          = model.MergedEdge
+	OrphanReport        = model.OrphanReport
+	CompactionReport    = model.CompactionReport
+	EpisodeDigest       = model.EpisodeDigest
+	ReembedReport       = model.ReembedReport
+	ContradictionReport = model.ContradictionReport
+	Contradiction       = model.Contradiction
+
+	CommunityDetection = model.CommunityDetection
+	ScratchEntry       = model.ScratchEntry
+	Session            = model.Session
+	UserProfile        = model.UserProfile
+	SynthesizedProfile = model.SynthesizedProfile
+	Change             = model.Change
+
+	ConcurrencyStats = model.ConcurrencyStats
+	ConcurrencyPatch = model.ConcurrencyPatch
+	LimiterStats     = model.LimiterStats
+	IngestProfile    = model.IngestProfile
+	StageTiming      = model.StageTiming
+)
+
+// ErrGroupNotFound is returned by Graphiti.GetGroup for unknown groups.
+var ErrGroupNotFound = core.ErrGroupNotFound
+
+// ErrInvalidGroupSettings is returned by Graphiti.UpdateGroup for attribute
+// schemas that don't compile.
+var ErrInvalidGroupSettings = core.ErrInvalidGroupSettings
+
+// ErrInvalidConcurrency is returned by Graphiti.UpdateConcurrency for limits below 1.
+var ErrInvalidConcurrency = core.ErrInvalidConcurrency
+
+// ErrEntityNotFound is returned by Graphiti.GetEntity, UpdateEntity and RegenerateSummary for unknown entities.
+var ErrEntityNotFound = core.ErrEntityNotFound
+
+// ErrVersionConflict is returned by Graphiti.UpdateEntity when the entity
+// changed since the version the patch names.
+var ErrVersionConflict = core.ErrVersionConflict
+
+// ErrFactNotFound is returned by Graphiti.GetFact for unknown facts.
+var ErrFactNotFound = core.ErrFactNotFound
+
+// ErrCitationGroupRequired is returned by Graphiti.ResolveCitation without a group.
+var ErrCitationGroupRequired = core.ErrCitationGroupRequired
+
+// ErrEpisodeNotFound is returned by Graphiti.DeleteEpisode for unknown episodes.
+var ErrEpisodeNotFound = core.ErrEpisodeNotFound
+
+// ErrEmbeddingModelNotFound is returned by Graphiti.Reembed for a model that is not configured.
+var ErrEmbeddingModelNotFound = core.ErrEmbeddingModelNotFound
+
+// ErrContentTooLarge is returned when an episode's content exceeds the [ingest] limits in "reject" mode.
+var ErrContentTooLarge = core.ErrContentTooLarge
+
+// ErrGroupLimit is returned when an episode is ingested into a group at one of its [group_limits] that the limit's on_limit action couldn't make room for.
+var ErrGroupLimit = core.ErrGroupLimit
+
+// ErrContentBlocked is returned when [moderation] blocks an episode.
+var ErrContentBlocked = core.ErrContentBlocked
+
+// ErrNoEncryptionKey is returned when reading a value stored encrypted without a Cipher.
+var ErrNoEncryptionKey = core.ErrNoEncryptionKey
+
+// ErrNoContentStore is returned when resolving offloaded episode content without a content store.
+var ErrNoContentStore = core.ErrNoContentStore
+
+// ErrBackupsDisabled is returned by Graphiti.Backup and ListBackups when no backup store is set.
+var ErrBackupsDisabled = core.ErrBackupsDisabled
+
+// ErrBackupNotFound is returned by Graphiti.GetBackup and RestoreBackup for unknown snapshots.
+var ErrBackupNotFound = core.ErrBackupNotFound
+
+// ErrInvalidBackup is returned for snapshots that can't be decoded or belong to another group.
+var ErrInvalidBackup = core.ErrInvalidBackup
+
+// LatestBackup selects a group's newest snapshot in Graphiti.GetBackup and RestoreBackup.
+const LatestBackup = core.LatestBackup
+
+// ErrChecklistNotFound is returned by Graphiti.FindFactGaps for a checklist the group does not define.
+var ErrChecklistNotFound = core.ErrChecklistNotFound
+
+// ErrInvalidStructuredEpisode is returned by Graphiti.AddStructuredEpisode for
+// duplicate entity names and facts naming unlisted entities.
+var ErrInvalidStructuredEpisode = core.ErrInvalidStructuredEpisode
+
+// ErrNothingToProfile is returned by Graphiti.SynthesizeProfile for a group without entities.
+var ErrNothingToProfile = core.ErrNothingToProfile
+
+// ErrDetectionRunning is returned by Graphiti.DetectAndSummarizeCommunities while the group is already being detected.
+var ErrDetectionRunning = core.ErrDetectionRunning
+
+// ErrDetectionNotFound is returned by Graphiti.GetCommunityDetection for groups not detected since the server started.
+var ErrDetectionNotFound = core.ErrDetectionNotFound
+
+// ErrScratchNotFound is returned by Graphiti.GetScratch and DeleteScratch for missing or expired entries.
+var ErrScratchNotFound = core.ErrScratchNotFound
+
+// ErrSessionNotFound is returned by Graphiti.GetSession and AddSessionMessage for unknown sessions.
+var ErrSessionNotFound = core.ErrSessionNotFound
+
+// ErrCircuitOpen is returned by LLM calls, and the ingests that make them, while the [llm.breaker] circuit breaker is open.
+var ErrCircuitOpen = llm.ErrCircuitOpen
+
+// ErrDeadLetterNotFound is returned by Graphiti.GetDeadLetter and RequeueDeadLetter for unknown dead letters.
+var ErrDeadLetterNotFound = core.ErrDeadLetterNotFound
+
+// ErrJobNotFound is returned by Graphiti.GetIngestJob for unknown jobs.
+var ErrJobNotFound = core.ErrJobNotFound
+
+// ErrReportNotFound is returned by Graphiti.GetConsistencyReport before the first check has run.
+var ErrReportNotFound = core.ErrReportNotFound
+
+// Priorities for WithPriority.
+const (
+	PriorityInteractive = model.PriorityInteractive
+	PriorityBatch       = model.PriorityBatch
+)
+
+// WithPriority sets the priority of the engine's work under the returned
+// context: PriorityInteractive work gets the next free worker before queued
+// PriorityBatch work. Bulk ingestion and bulk search default to batch.
+func WithPriority(ctx context.Context, priority string) context.Context {
+	return core.WithPriority(ctx, priority)
+}
+
+// WithScopes limits the engine's reads under the returned context to the
+// attributes and relation types the [access] policies grant to scopes.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return core.WithScopes(ctx, scopes)
+}
+
+// WithFactChanges returns a context under which ingests report the facts they
+// create or invalidate to the returned FactChanges.
+func WithFactChanges(ctx context.Context) (context.Context, *FactChanges) {
+	return core.WithFactChanges(ctx)
+}
+
+// LoadConfig reads a TOML configuration file.
+func LoadConfig(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// New assembles an engine from explicit dependencies. A nil reranker falls back to LLM reranking.
+func New(d GraphDriver, llmClient LLMClient, embedder EmbedderClient, reranker RerankerClient, cfg *Config) *Graphiti {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	return core.NewGraphiti(d, llmClient, embedder, reranker, cfg)
+}
+
+// Open builds the graph driver and LLM clients described by cfg, applies pending
+// graph migrations and returns a ready engine.
+// The caller owns the driver and should Close it when done. When [summary_queue] is
+// enabled, the caller must also run g.SummaryQueue.Run in a goroutine and call
+// g.SummaryQueue.Flush before closing the driver.
+func Open(ctx context.Context, cfg *Config) (*Graphiti, error) {
+	d, err := NewDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Migrate(ctx, d); err != nil {
+		d.Close(ctx)
+		return nil, err
+	}
+	llmClient, embedder, err := NewLLMClient(ctx, cfg.LLM)
+	if err != nil {
+		d.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	embedders, err := NewEmbedders(ctx, cfg.Embedding)
+	if err != nil {
+		d.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize embedders: %w", err)
+	}
+	cipher, err := NewCipher(cfg.Encryption)
+	if err != nil {
+		d.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+	backups, err := NewBackupStore(cfg.Backup)
+	if err != nil {
+		d.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize backup store: %w", err)
+	}
+	contentStore, err := NewContentStore(cfg.ContentStore)
+	if err != nil {
+		d.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize content store: %w", err)
+	}
+	g := New(d, llmClient, embedder, nil, cfg)
+	g.UseEmbedders(embedders)
+	g.Cipher = cipher
+	g.Backups = backups
+	g.ContentStore = contentStore
+	return g, nil
+}
+
+// NewContentStore opens the [content_store] store for large episode content,
+// or returns nil when none is configured.
+func NewContentStore(cfg ContentStoreConfig) (BlobStore, error) {
+	return core.NewContentStore(cfg)
+}
+
+// NewBackupStore opens the [backup] store on local disk or S3, or returns nil
+// when none is configured. Call g.RunBackups in a goroutine to take scheduled snapshots.
+func NewBackupStore(cfg BackupConfig) (BlobStore, error) {
+	return core.NewBackupStore(cfg)
+}
+
+// NewCipher creates the AES-GCM cipher of an [encryption] config from the key
+// in its environment variable, or returns nil when encryption is disabled.
+// Assign it, or a KMS-backed Cipher, to Graphiti.Cipher.
+func NewCipher(cfg EncryptionConfig) (Cipher, error) {
+	return core.NewCipher(cfg)
+}
+
+// NewAESCipher returns an AES-GCM Cipher for a 16, 24 or 32-byte key.
+func NewAESCipher(key []byte) (Cipher, error) {
+	return core.NewAESCipher(key)
+}
+
+// Migrate applies pending graph data migrations to d and returns the versions applied.
+// Call it once after opening a driver directly; Open does so already.
+func Migrate(ctx context.Context, d GraphDriver) ([]int, error) {
+	applied, err := driver.Migrate(ctx, d, driver.Migrations)
+	if err != nil {
+		return applied, fmt.Errorf("failed to migrate graph: %w", err)
+	}
+	return applied, nil
+}
+
+// NewDriver opens the backend selected by cfg.Graph.Backend ("memgraph", "memory" or "sqlite").
+func NewDriver(cfg *Config) (GraphDriver, error) {
+	return driver.NewFromConfig(cfg)
+}
+
+// NewMemgraphDriver connects to Memgraph (or Neo4j). readURI may be empty.
+func NewMemgraphDriver(uri, readURI, username, password string) (*MemgraphDriver, error) {
+	return driver.NewMemgraphDriverWithReader(uri, readURI, username, password)
+}
+
+// NewMemoryDriver returns a non-persistent in-process graph.
+func NewMemoryDriver() *MemoryDriver {
+	return driver.NewMemoryDriver()
+}
+
+// NewSQLiteDriver opens (or creates) a single-file graph database at path.
+func NewSQLiteDriver(path string) (*SQLiteDriver, error) {
+	return driver.NewSQLiteDriver(path)
+}
+
+// NewRoutedDriver keeps groups whose group_id starts with a route's prefix on
+// its driver, and every other group on def.
+func NewRoutedDriver(def GraphDriver, routes []Route) *RoutedDriver {
+	return driver.NewRoutedDriver(def, routes)
+}
+
+// NewShardedDriver spreads groups over shards by hashing their group_id.
+func NewShardedDriver(shards []Shard) (*ShardedDriver, error) {
+	return driver.NewShardedDriver(shards)
+}
+
+// NewLLMClient creates the completion and embedding clients for cfg.Provider,
+// behind a CircuitBreaker when [llm.breaker] is enabled.
+// The embedder is nil for providers without embedding support.
+func NewLLMClient(ctx context.Context, cfg LLMConfig) (LLMClient, EmbedderClient, error) {
+	return llm.NewClient(ctx, cfg)
+}
+
+// NewCircuitBreaker wraps clients of one provider endpoint in a circuit breaker.
+func NewCircuitBreaker(llmClient LLMClient, embedder EmbedderClient, cfg BreakerConfig) *CircuitBreaker {
+	return llm.NewCircuitBreaker(llmClient, embedder, cfg)
+}
+
+// NewEmbedders creates the embedders of an [embedding] config, active model first.
+// Pass them to Graphiti.UseEmbedders.
+func NewEmbedders(ctx context.Context, cfg EmbeddingConfig) ([]NamedEmbedder, error) {
+	return llm.NewEmbedders(ctx, cfg.Models)
+}
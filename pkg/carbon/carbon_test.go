@@ -0,0 +1,50 @@
+package carbon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agenthands/carbon/pkg/carbon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scriptedLLM struct {
+	responses []string
+}
+
+func (s *scriptedLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	if len(s.responses) == 0 {
+		return `{}`, nil
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func TestEmbeddedEngine(t *testing.T) {
+	ctx := context.Background()
+	llmClient := &scriptedLLM{responses: []string{
+		`{"extracted_entities": [{"name": "Alice", "entity_type_id": 1}]}`,
+	}}
+	cfg := &carbon.Config{
+		Extraction: carbon.ExtractionPrompts{Nodes: "%s %s", Edges: "%s"},
+	}
+
+	g := carbon.New(carbon.NewMemoryDriver(), llmClient, nil, nil, cfg)
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice says hi.", "", ""))
+
+	results, err := g.Search(ctx, "g1", "Alice")
+	require.NoError(t, err)
+	assert.Empty(t, results) // A single entity yields no facts
+}
+
+func TestOpen_MemoryBackend(t *testing.T) {
+	cfg := &carbon.Config{
+		Graph: carbon.GraphConfig{Backend: "memory"},
+		LLM:   carbon.LLMConfig{Provider: "openai", APIKey: "test", Model: "test"},
+	}
+	g, err := carbon.Open(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, g.Driver)
+}
@@ -0,0 +1,81 @@
+package carbontest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/pkg/carbon"
+	"github.com/agenthands/carbon/pkg/carbontest"
+)
+
+func TestMockLLM_RulesQueueDefault(t *testing.T) {
+	ctx := context.Background()
+	m := carbontest.NewMockLLM().On("hello", "rule").Queue("first", "second")
+
+	resp, _ := m.Generate(ctx, "say hello")
+	assert.Equal(t, "rule", resp)
+	resp, _ = m.Generate(ctx, "other")
+	assert.Equal(t, "first", resp)
+	resp, _ = m.Generate(ctx, "other")
+	assert.Equal(t, "second", resp)
+	resp, _ = m.Generate(ctx, "other")
+	assert.Equal(t, "{}", resp)
+	assert.Len(t, m.Prompts(), 4)
+}
+
+func TestMockEmbedder_SimilarTextsAreClose(t *testing.T) {
+	e := carbontest.NewMockEmbedder()
+	a, _ := e.Embed(context.Background(), "Alice lives in Berlin")
+	b, _ := e.Embed(context.Background(), "Alice lives in Berlin")
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 32)
+}
+
+func TestSeedAndSearch(t *testing.T) {
+	ctx := context.Background()
+	g := carbontest.NewEngine(nil)
+
+	alice := carbontest.NewEntity("g1", "Alice", "")
+	berlin := carbontest.NewEntity("g1", "Berlin", "")
+	fixture := carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin},
+		Facts:    []carbon.EntityEdge{carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")},
+	}
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, fixture))
+
+	results, err := g.Search(ctx, "g1", "Where does Alice live?")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Alice lives in Berlin", results[0].Fact)
+}
+
+func TestEngineIngestsWithScriptedLLM(t *testing.T) {
+	ctx := context.Background()
+	llm := carbontest.NewMockLLM().
+		On("Extract entities", `{"extracted_entities": [{"name": "Alice", "entity_type_id": 1}, {"name": "Berlin", "entity_type_id": 1}]}`).
+		On("Extract edges", `{"extracted_edges": []}`)
+	g := carbontest.NewEngine(llm)
+
+	require.NoError(t, g.AddEpisode(ctx, "g1", "message", "Alice moved to Berlin.", "", ""))
+	assert.NotEmpty(t, llm.Prompts())
+
+	stats, err := g.GetGroupStats(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Entities)
+}
+
+func TestRecordingDriver_FailInjection(t *testing.T) {
+	d := carbontest.NewRecordingDriver(nil)
+	boom := errors.New("boom")
+	d.Fail("MERGE (n:Entity", boom)
+
+	err := carbontest.Seed(context.Background(), d, nil, carbontest.Fixture{
+		Entities: []carbon.EntityNode{carbontest.NewEntity("g1", "Alice", "")},
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Len(t, d.Calls(), 1)
+}
@@ -0,0 +1,12 @@
+// Package carbontest provides test doubles and fixtures for code built on
+// package carbon, so downstream services can unit test against the engine
+// without Memgraph or a live model:
+//
+//	llm := carbontest.NewMockLLM()
+//	llm.On("Extract entities", `{"extracted_entities": [{"name": "Alice", "entity_type_id": 1}]}`)
+//
+//	g := carbontest.NewEngine(llm)
+//	err := g.AddEpisode(ctx, "g1", "message", "Alice says hi.", "", "")
+//
+// Graph state can be seeded directly with Seed and the NewEntity/NewFact builders.
+package carbontest
@@ -0,0 +1,81 @@
+package carbontest
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/agenthands/carbon/pkg/carbon"
+)
+
+// NewMemoryDriver returns an empty in-process graph.
+func NewMemoryDriver() *carbon.MemoryDriver {
+	return carbon.NewMemoryDriver()
+}
+
+// Call is one query seen by a RecordingDriver.
+type Call struct {
+	Query  string
+	Params map[string]interface{}
+	Read   bool
+}
+
+// RecordingDriver wraps a carbon.GraphDriver, recording every query and
+// optionally failing queries that contain a given fragment.
+type RecordingDriver struct {
+	carbon.GraphDriver
+
+	mu       sync.Mutex
+	calls    []Call
+	failures map[string]error
+}
+
+// NewRecordingDriver wraps inner; a nil inner uses a fresh memory driver.
+func NewRecordingDriver(inner carbon.GraphDriver) *RecordingDriver {
+	if inner == nil {
+		inner = NewMemoryDriver()
+	}
+	return &RecordingDriver{GraphDriver: inner, failures: make(map[string]error)}
+}
+
+// Fail makes every query containing fragment return err.
+func (d *RecordingDriver) Fail(fragment string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[fragment] = err
+}
+
+// Calls returns the queries executed so far, in order.
+func (d *RecordingDriver) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call(nil), d.calls...)
+}
+
+func (d *RecordingDriver) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if err := d.record(query, params, false); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return d.GraphDriver.ExecuteQuery(ctx, query, params)
+}
+
+func (d *RecordingDriver) ExecuteReadQuery(ctx context.Context, query string, params map[string]interface{}) (neo4j.EagerResult, error) {
+	if err := d.record(query, params, true); err != nil {
+		return neo4j.EagerResult{}, err
+	}
+	return d.GraphDriver.ExecuteReadQuery(ctx, query, params)
+}
+
+func (d *RecordingDriver) record(query string, params map[string]interface{}, read bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, Call{Query: query, Params: params, Read: read})
+	for fragment, err := range d.failures {
+		if strings.Contains(query, fragment) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package carbontest
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+)
+
+// MockEmbedder is a deterministic carbon.EmbedderClient. Texts are embedded as
+// hashed bags of lower-cased words, so texts sharing words are close under
+// cosine similarity and vector search behaves plausibly without a model.
+type MockEmbedder struct {
+	// Dim is the vector size; zero means 32.
+	Dim int
+	// Vectors pins exact embeddings for specific texts.
+	Vectors map[string][]float32
+	// Err, when set, fails every call.
+	Err error
+
+	mu    sync.Mutex
+	calls int
+}
+
+func NewMockEmbedder() *MockEmbedder {
+	return &MockEmbedder{Dim: 32, Vectors: make(map[string][]float32)}
+}
+
+// Calls returns the number of Embed calls so far.
+func (m *MockEmbedder) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func (m *MockEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if v, ok := m.Vectors[text]; ok {
+		return v, nil
+	}
+
+	dim := m.Dim
+	if dim <= 0 {
+		dim = 32
+	}
+	vec := make([]float32, dim)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		h.Write([]byte(strings.Trim(word, ".,!?;:\"'")))
+		vec[h.Sum32()%uint32(dim)]++
+	}
+	var norm float64
+	for _, x := range vec {
+		norm += float64(x * x)
+	}
+	if norm > 0 {
+		n := float32(math.Sqrt(norm))
+		for i := range vec {
+			vec[i] /= n
+		}
+	}
+	return vec, nil
+}
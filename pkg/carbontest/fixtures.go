@@ -0,0 +1,161 @@
+package carbontest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/agenthands/carbon/internal/driver"
+	"github.com/agenthands/carbon/pkg/carbon"
+)
+
+// Config returns a configuration with short, recognizable prompt templates so
+// MockLLM rules can match on "Extract entities", "Extract edges", "Deduplicate",
+// "Summarize", "Summarize community" and "Name community".
+func Config() *carbon.Config {
+	return &carbon.Config{
+		Graph: carbon.GraphConfig{Backend: "memory"},
+		Extraction: carbon.ExtractionPrompts{
+			Nodes: "Extract entities.\nTypes: %s\nContent: %s",
+			Edges: "Extract edges between: %s",
+		},
+		Deduplication: carbon.DeduplicationPrompts{
+			Nodes: "Deduplicate nodes: %s vs %s",
+		},
+		Summary: carbon.SummaryPrompts{
+			Nodes:         "Summarize entity: %s\nNew facts: %s",
+			Communities:   "Summarize community: %s",
+			CommunityName: "Name community: %s",
+		},
+	}
+}
+
+// NewEngine returns an engine over a fresh memory driver, the given LLM, a
+// MockEmbedder and Config(). Pass a nil llmClient to use NewMockLLM().
+func NewEngine(llmClient carbon.LLMClient) *carbon.Graphiti {
+	if llmClient == nil {
+		llmClient = NewMockLLM()
+	}
+	return carbon.New(NewMemoryDriver(), llmClient, NewMockEmbedder(), nil, Config())
+}
+
+// NewEntity builds an entity node with a fresh UUID.
+func NewEntity(groupID, name, summary string) carbon.EntityNode {
+	return carbon.EntityNode{
+		UUID:      uuid.New().String(),
+		Name:      name,
+		GroupID:   groupID,
+		Summary:   summary,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewFact builds a currently valid RELATES_TO edge between two entities.
+func NewFact(source, target carbon.EntityNode, relation, fact string) carbon.EntityEdge {
+	now := time.Now().UTC()
+	return carbon.EntityEdge{
+		UUID:       uuid.New().String(),
+		SourceUUID: source.UUID,
+		TargetUUID: target.UUID,
+		GroupID:    source.GroupID,
+		Name:       relation,
+		Fact:       fact,
+		CreatedAt:  now,
+		ValidAt:    now,
+	}
+}
+
+// Fixture is a set of entities and facts to load with Seed.
+type Fixture struct {
+	Entities []carbon.EntityNode
+	Facts    []carbon.EntityEdge
+}
+
+// Seed writes the fixture to d. When embedder is non-nil, entity names and
+// facts without embeddings are embedded so vector search can find them.
+func Seed(ctx context.Context, d carbon.GraphDriver, embedder carbon.EmbedderClient, f Fixture) error {
+	for _, n := range f.Entities {
+		if n.NameEmbedding == nil && embedder != nil {
+			emb, err := embedder.Embed(ctx, n.Name)
+			if err != nil {
+				return fmt.Errorf("failed to embed entity %s: %w", n.Name, err)
+			}
+			n.NameEmbedding = emb
+		}
+		attrs, err := encodeAttributes(n.Attributes)
+		if err != nil {
+			return fmt.Errorf("failed to encode attributes for entity %s: %w", n.Name, err)
+		}
+		_, err = d.ExecuteQuery(ctx, driver.SaveEntityNodeQuery, map[string]interface{}{
+			"uuid":           n.UUID,
+			"name":           n.Name,
+			"group_id":       n.GroupID,
+			"created_at":     n.CreatedAt.Format(time.RFC3339),
+			"summary":        n.Summary,
+			"name_embedding": n.NameEmbedding,
+			// Untagged embeddings are searched by any model of the same dimension
+			"name_embedding_model": "",
+			"attributes":           attrs,
+			"labels":               n.Labels,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed entity %s: %w", n.Name, err)
+		}
+	}
+
+	for _, e := range f.Facts {
+		if e.FactEmbedding == nil && embedder != nil {
+			emb, err := embedder.Embed(ctx, e.Fact)
+			if err != nil {
+				return fmt.Errorf("failed to embed fact %q: %w", e.Fact, err)
+			}
+			e.FactEmbedding = emb
+		}
+		attrs, err := encodeAttributes(e.Attributes)
+		if err != nil {
+			return fmt.Errorf("failed to encode attributes for fact %q: %w", e.Fact, err)
+		}
+		episodes := e.Episodes
+		if episodes == nil {
+			episodes = []string{}
+		}
+		_, err = d.ExecuteQuery(ctx, driver.SaveEntityEdgeQuery, map[string]interface{}{
+			"uuid":                 e.UUID,
+			"source_uuid":          e.SourceUUID,
+			"target_uuid":          e.TargetUUID,
+			"name":                 e.Name,
+			"fact":                 e.Fact,
+			"group_id":             e.GroupID,
+			"created_at":           e.CreatedAt.Format(time.RFC3339),
+			"expired_at":           formatOptional(e.ExpiredAt),
+			"valid_at":             e.ValidAt.Format(time.RFC3339),
+			"invalid_at":           formatOptional(e.InvalidAt),
+			"episodes":             episodes,
+			"fact_embedding":       e.FactEmbedding,
+			"fact_embedding_model": "",
+			"attributes":           attrs,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed fact %q: %w", e.Fact, err)
+		}
+	}
+	return nil
+}
+
+func formatOptional(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func encodeAttributes(attrs map[string]interface{}) (string, error) {
+	if len(attrs) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(attrs)
+	return string(b), err
+}
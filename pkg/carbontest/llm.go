@@ -0,0 +1,80 @@
+package carbontest
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// MockLLM is a scriptable carbon.LLMClient. Each call is answered by, in order:
+// the first matching rule registered with On/OnFunc, the next queued response,
+// then Default. Safe for the engine's concurrent use.
+type MockLLM struct {
+	// Default is returned when no rule matches and the queue is empty.
+	Default string
+	// Err, when set, fails every call.
+	Err error
+
+	mu      sync.Mutex
+	rules   []rule
+	queue   []string
+	prompts []string
+}
+
+type rule struct {
+	match    func(prompt string) bool
+	response string
+}
+
+// NewMockLLM returns a mock that answers "{}" until scripted.
+func NewMockLLM() *MockLLM {
+	return &MockLLM{Default: "{}"}
+}
+
+// On answers every prompt containing substr with response.
+func (m *MockLLM) On(substr, response string) *MockLLM {
+	return m.OnFunc(func(prompt string) bool { return strings.Contains(prompt, substr) }, response)
+}
+
+// OnFunc answers every prompt accepted by match with response.
+func (m *MockLLM) OnFunc(match func(prompt string) bool, response string) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, rule{match: match, response: response})
+	return m
+}
+
+// Queue appends responses consumed one per call by prompts no rule matches.
+func (m *MockLLM) Queue(responses ...string) *MockLLM {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, responses...)
+	return m
+}
+
+// Prompts returns every prompt received so far, in call order.
+func (m *MockLLM) Prompts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.prompts...)
+}
+
+func (m *MockLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompts = append(m.prompts, prompt)
+	if m.Err != nil {
+		return "", m.Err
+	}
+	for _, r := range m.rules {
+		if r.match(prompt) {
+			return r.response, nil
+		}
+	}
+	if len(m.queue) > 0 {
+		resp := m.queue[0]
+		m.queue = m.queue[1:]
+		return resp, nil
+	}
+	return m.Default, nil
+}
@@ -0,0 +1,136 @@
+// Package client is a typed Go client for carbon's HTTP API:
+//
+//	c := client.New("http://localhost:8080")
+//	resp, err := c.Search(ctx, &api.SearchRequest{GroupID: "g1", Query: "Where does Alice live?"})
+//
+// Methods are generated from api.Routes; run `go generate ./pkg/client` after
+// changing the routes or their types.
+package client
+
+//go:generate go run ../../cmd/apigen -root ../..
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+// Client calls a carbon server.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	APIKey     string // Sent as a bearer token; its scopes decide which guarded data is returned
+	Priority   string // "interactive" or "batch", overriding the endpoint's default when set
+}
+
+// New returns a client for the server at baseURL using http.DefaultClient.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// Error is returned for non-2xx responses. Fields lists the offending
+// fields when the request failed validation.
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     []api.FieldError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("carbon: %d %s", e.StatusCode, e.Message)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	resp, err := c.send(ctx, method, path, query, "application/json", reader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	if c.Priority != "" {
+		req.Header.Set("X-Carbon-Priority", c.Priority)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var e api.ErrorResponse
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &e) != nil || e.Error == "" {
+			e.Error = strings.TrimSpace(string(data))
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Message: e.Error, Fields: e.Fields}
+	}
+	return resp, nil
+}
+
+// StreamBulkEpisodes sends episodes to POST /bulk/messages/stream and returns
+// one result per episode. defaultGroup applies to episodes without a group.
+func (c *Client) StreamBulkEpisodes(ctx context.Context, defaultGroup string, episodes []model.StreamEpisode) ([]model.StreamResult, error) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, ep := range episodes {
+		if err := enc.Encode(ep); err != nil {
+			return nil, fmt.Errorf("failed to encode episode: %w", err)
+		}
+	}
+	var query url.Values
+	if defaultGroup != "" {
+		query = url.Values{"group_id": {defaultGroup}}
+	}
+	resp, err := c.send(ctx, http.MethodPost, "/bulk/messages/stream", query, "application/x-ndjson", &body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var results []model.StreamResult
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var res model.StreamResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			return results, fmt.Errorf("failed to decode stream result: %w", err)
+		}
+		results = append(results, res)
+	}
+	return results, scanner.Err()
+}
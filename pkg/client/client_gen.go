@@ -0,0 +1,638 @@
+// Code generated by cmd/apigen from pkg/api. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/agenthands/carbon/internal/core/model"
+	"github.com/agenthands/carbon/pkg/api"
+)
+
+// AddMessages calls POST /messages. Ingest messages as episodes, one at a time.
+func (c *Client) AddMessages(ctx context.Context, req *api.AddMessageRequest) (*api.StatusResponse, error) {
+	var resp api.StatusResponse
+	if err := c.do(ctx, "POST", "/messages", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddStructuredEpisode calls POST /episodes/structured. Ingest entities and facts as given, without LLM extraction.
+func (c *Client) AddStructuredEpisode(ctx context.Context, req *api.StructuredEpisodeRequest) (*model.StructuredResult, error) {
+	var resp model.StructuredResult
+	if err := c.do(ctx, "POST", "/episodes/structured", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateSession calls POST /sessions. Start a conversation session backed by a saga, or resume the saga with the given name.
+func (c *Client) CreateSession(ctx context.Context, req *api.CreateSessionRequest) (*model.Session, error) {
+	var resp model.Session
+	if err := c.do(ctx, "POST", "/sessions", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSession calls GET /sessions/:id. Get a session.
+func (c *Client) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	var resp model.Session
+	if err := c.do(ctx, "GET", "/sessions/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddSessionMessages calls POST /sessions/:id/messages. Ingest messages into a session's group and saga, in order, recording each speaker's role.
+func (c *Client) AddSessionMessages(ctx context.Context, id string, req *api.SessionMessagesRequest) (*api.StatusResponse, error) {
+	var resp api.StatusResponse
+	if err := c.do(ctx, "POST", "/sessions/"+url.PathEscape(id)+"/messages", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Search calls POST /search. Search facts, or multi-hop fact paths in "paths" mode.
+func (c *Client) Search(ctx context.Context, req *api.SearchRequest) (*api.SearchResponse, error) {
+	var resp api.SearchResponse
+	if err := c.do(ctx, "POST", "/search", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DetectCommunities calls POST /communities/detect. Detect and summarize communities of a group.
+func (c *Client) DetectCommunities(ctx context.Context, req *api.DetectRequest) (*api.StatusResponse, error) {
+	var resp api.StatusResponse
+	if err := c.do(ctx, "POST", "/communities/detect", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCommunityDetection calls GET /communities/detect/:group_id. Get the running or latest community detection of a group.
+func (c *Client) GetCommunityDetection(ctx context.Context, groupID string) (*model.CommunityDetection, error) {
+	var resp model.CommunityDetection
+	if err := c.do(ctx, "GET", "/communities/detect/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BulkAddEpisodes calls POST /bulk/messages. Ingest a batch of episodes. Per-episode results are only returned when partial is set.
+func (c *Client) BulkAddEpisodes(ctx context.Context, req *api.BulkAddRequest) (*model.BulkIngestResult, error) {
+	var resp model.BulkIngestResult
+	if err := c.do(ctx, "POST", "/bulk/messages", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RetryBulkEpisodes calls POST /bulk/messages/retry. Re-ingest the failed episodes of a partial bulk result.
+func (c *Client) RetryBulkEpisodes(ctx context.Context, req *model.BulkIngestResult) (*model.BulkIngestResult, error) {
+	var resp model.BulkIngestResult
+	if err := c.do(ctx, "POST", "/bulk/messages/retry", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BulkSearch calls POST /bulk/search. Run several searches in one request.
+func (c *Client) BulkSearch(ctx context.Context, req *api.BulkSearchRequest) (*api.BulkSearchResponse, error) {
+	var resp api.BulkSearchResponse
+	if err := c.do(ctx, "POST", "/bulk/search", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetEntity calls GET /entities/:uuid. Get an entity with its current version.
+func (c *Client) GetEntity(ctx context.Context, uuid string) (*model.EntityNode, error) {
+	var resp model.EntityNode
+	if err := c.do(ctx, "GET", "/entities/"+url.PathEscape(uuid), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetNeighborhood calls GET /entities/:uuid/neighborhood. Get the entities within a few hops of an entity, the valid facts linking them and their communities.
+func (c *Client) GetNeighborhood(ctx context.Context, uuid string, q api.NeighborhoodQuery) (*model.Neighborhood, error) {
+	query := url.Values{}
+	if q.Depth != nil {
+		query.Set("depth", strconv.Itoa(*q.Depth))
+	}
+	var resp model.Neighborhood
+	if err := c.do(ctx, "GET", "/entities/"+url.PathEscape(uuid)+"/neighborhood", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateEntity calls PATCH /entities/:uuid. Update an entity, optionally only if it is still at a given version.
+func (c *Client) UpdateEntity(ctx context.Context, uuid string, req *model.EntityPatch) (*model.EntityNode, error) {
+	var resp model.EntityNode
+	if err := c.do(ctx, "PATCH", "/entities/"+url.PathEscape(uuid), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RegenerateSummary calls POST /entities/:uuid/summarize. Rebuild an entity summary from its facts.
+func (c *Client) RegenerateSummary(ctx context.Context, uuid string) (*model.EntityNode, error) {
+	var resp model.EntityNode
+	if err := c.do(ctx, "POST", "/entities/"+url.PathEscape(uuid)+"/summarize", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FindFactGaps calls POST /entities/:uuid/gaps. List checklist attributes and relations an entity has no facts for.
+func (c *Client) FindFactGaps(ctx context.Context, uuid string, req *api.FactGapsRequest) (*model.FactGaps, error) {
+	var resp model.FactGaps
+	if err := c.do(ctx, "POST", "/entities/"+url.PathEscape(uuid)+"/gaps", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetFact calls GET /facts/:uuid. Get a fact by UUID, or by the group's citation handle (F-...), including invalidated facts.
+func (c *Client) GetFact(ctx context.Context, uuid string, q api.FactQuery) (*model.EntityEdge, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	var resp model.EntityEdge
+	if err := c.do(ctx, "GET", "/facts/"+url.PathEscape(uuid), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetProvenance calls GET /facts/:uuid/provenance. Get a fact with the full content of the episodes it was extracted from.
+func (c *Client) GetProvenance(ctx context.Context, uuid string) (*model.Provenance, error) {
+	var resp model.Provenance
+	if err := c.do(ctx, "GET", "/facts/"+url.PathEscape(uuid)+"/provenance", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateIngestJob calls POST /jobs/ingest. Start a checkpointed background bulk ingest.
+func (c *Client) CreateIngestJob(ctx context.Context, req *api.BulkAddRequest) (*model.IngestJob, error) {
+	var resp model.IngestJob
+	if err := c.do(ctx, "POST", "/jobs/ingest", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CheckConsistency calls POST /maintenance/consistency. Check entity summaries against their facts. Requires the admin scope.
+func (c *Client) CheckConsistency(ctx context.Context, req *api.ConsistencyRequest) (*model.ConsistencyReport, error) {
+	var resp model.ConsistencyReport
+	if err := c.do(ctx, "POST", "/maintenance/consistency", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetConsistencyReport calls GET /maintenance/consistency/:group_id. Get the latest consistency report of a group. Requires the admin scope.
+func (c *Client) GetConsistencyReport(ctx context.Context, groupID string) (*model.ConsistencyReport, error) {
+	var resp model.ConsistencyReport
+	if err := c.do(ctx, "GET", "/maintenance/consistency/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScanContradictions calls POST /maintenance/contradictions. Scan a group's valid facts for pairs that can't both be true. Requires the admin scope.
+func (c *Client) ScanContradictions(ctx context.Context, req *api.ContradictionsRequest) (*model.ContradictionReport, error) {
+	var resp model.ContradictionReport
+	if err := c.do(ctx, "POST", "/maintenance/contradictions", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DedupeEdges calls POST /maintenance/dedupe-edges. Merge duplicate facts of a group. Requires the admin scope.
+func (c *Client) DedupeEdges(ctx context.Context, req *api.DedupeEdgesRequest) (*model.DedupeEdgesReport, error) {
+	var resp model.DedupeEdgesReport
+	if err := c.do(ctx, "POST", "/maintenance/dedupe-edges", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDedupeEdgesReport calls GET /maintenance/dedupe-edges/:group_id. Get the latest dedupe-edges report of a group. Requires the admin scope.
+func (c *Client) GetDedupeEdgesReport(ctx context.Context, groupID string) (*model.DedupeEdgesReport, error) {
+	var resp model.DedupeEdgesReport
+	if err := c.do(ctx, "GET", "/maintenance/dedupe-edges/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CollectOrphans calls POST /maintenance/orphans. Quarantine or delete entities without facts or mentions. Requires the admin scope.
+func (c *Client) CollectOrphans(ctx context.Context, req *api.OrphanGCRequest) (*model.OrphanReport, error) {
+	var resp model.OrphanReport
+	if err := c.do(ctx, "POST", "/maintenance/orphans", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetOrphanReport calls GET /maintenance/orphans/:group_id. Get the latest orphan report of a group. Requires the admin scope.
+func (c *Client) GetOrphanReport(ctx context.Context, groupID string) (*model.OrphanReport, error) {
+	var resp model.OrphanReport
+	if err := c.do(ctx, "GET", "/maintenance/orphans/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CompactEpisodes calls POST /maintenance/compact. Replace runs of old episodes with digest episodes. Requires the admin scope.
+func (c *Client) CompactEpisodes(ctx context.Context, req *api.CompactRequest) (*model.CompactionReport, error) {
+	var resp model.CompactionReport
+	if err := c.do(ctx, "POST", "/maintenance/compact", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCompactionReport calls GET /maintenance/compact/:group_id. Get the latest compaction report of a group. Requires the admin scope.
+func (c *Client) GetCompactionReport(ctx context.Context, groupID string) (*model.CompactionReport, error) {
+	var resp model.CompactionReport
+	if err := c.do(ctx, "GET", "/maintenance/compact/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Reembed calls POST /maintenance/reembed. Re-embed entity names and facts stored with another embedding model. Requires the admin scope.
+func (c *Client) Reembed(ctx context.Context, req *api.ReembedRequest) (*model.ReembedReport, error) {
+	var resp model.ReembedReport
+	if err := c.do(ctx, "POST", "/maintenance/reembed", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetReembedReport calls GET /maintenance/reembed/:group_id. Get the latest reembed report of a group. Requires the admin scope.
+func (c *Client) GetReembedReport(ctx context.Context, groupID string) (*model.ReembedReport, error) {
+	var resp model.ReembedReport
+	if err := c.do(ctx, "GET", "/maintenance/reembed/"+url.PathEscape(groupID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListDeadLetters calls GET /deadletters. List episodes that failed to ingest, most recently failed first.
+func (c *Client) ListDeadLetters(ctx context.Context, q api.DeadLetterQuery) (*api.DeadLettersResponse, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	var resp api.DeadLettersResponse
+	if err := c.do(ctx, "GET", "/deadletters", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RequeueDeadLetter calls POST /deadletters/:id/requeue. Ingest a dead letter's episode again, deleting the dead letter once it succeeds.
+func (c *Client) RequeueDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	var resp model.DeadLetter
+	if err := c.do(ctx, "POST", "/deadletters/"+url.PathEscape(id)+"/requeue", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetIngestJob calls GET /jobs/:id. Get the progress of an ingest job.
+func (c *Client) GetIngestJob(ctx context.Context, id string) (*model.IngestJob, error) {
+	var resp model.IngestJob
+	if err := c.do(ctx, "GET", "/jobs/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListEpisodes calls GET /episodes. List a group's most recent episodes, newest first, optionally only those tagged with a topic.
+func (c *Client) ListEpisodes(ctx context.Context, q api.EpisodesQuery) (*api.EpisodesResponse, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	if q.Topic != "" {
+		query.Set("topic", q.Topic)
+	}
+	if q.LastN != 0 {
+		query.Set("last_n", strconv.Itoa(q.LastN))
+	}
+	var resp api.EpisodesResponse
+	if err := c.do(ctx, "GET", "/episodes", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetChanges calls GET /changes. List a group's changes after a sequence number, oldest first.
+func (c *Client) GetChanges(ctx context.Context, q api.ChangesQuery) (*api.ChangesResponse, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	if q.Since != 0 {
+		query.Set("since", strconv.Itoa(q.Since))
+	}
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var resp api.ChangesResponse
+	if err := c.do(ctx, "GET", "/changes", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SynthesizeProfile calls POST /profile. Get the group's user profile (preferences, facts, relationships, open questions), written by the LLM from the graph on first use or on refresh.
+func (c *Client) SynthesizeProfile(ctx context.Context, req *api.ProfileRequest) (*model.SynthesizedProfile, error) {
+	var resp model.SynthesizedProfile
+	if err := c.do(ctx, "POST", "/profile", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGraph calls GET /graph. Get a group's graph in D3 format. The server also accepts format=cytoscape and format=html.
+func (c *Client) GetGraph(ctx context.Context, q api.GraphQuery) (*model.GraphView, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	if q.Center != "" {
+		query.Set("center", q.Center)
+	}
+	if q.Depth != nil {
+		query.Set("depth", strconv.Itoa(*q.Depth))
+	}
+	var resp model.GraphView
+	if err := c.do(ctx, "GET", "/graph", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListGroups calls GET /groups. List groups.
+func (c *Client) ListGroups(ctx context.Context) (*api.GroupsResponse, error) {
+	var resp api.GroupsResponse
+	if err := c.do(ctx, "GET", "/groups", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGroup calls GET /groups/:id. Get a group and its settings.
+func (c *Client) GetGroup(ctx context.Context, id string) (*model.GroupNode, error) {
+	var resp model.GroupNode
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateGroup calls PATCH /groups/:id. Update a group's metadata and settings. Requires the admin scope.
+func (c *Client) UpdateGroup(ctx context.Context, id string, req *model.GroupPatch) (*model.GroupNode, error) {
+	var resp model.GroupNode
+	if err := c.do(ctx, "PATCH", "/groups/"+url.PathEscape(id), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGroupStats calls GET /groups/:id/stats. Get node and edge counts of a group.
+func (c *Client) GetGroupStats(ctx context.Context, id string) (*model.GroupStats, error) {
+	var resp model.GroupStats
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/stats", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TopEntities calls GET /groups/:id/top-entities. Rank a group's entities by degree, mentions and recency.
+func (c *Client) TopEntities(ctx context.Context, id string, q api.TopEntitiesQuery) (*api.TopEntitiesResponse, error) {
+	query := url.Values{}
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var resp api.TopEntitiesResponse
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/top-entities", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FindKnowledgeGaps calls GET /groups/:id/gaps. Report what the group's key entities lack by their entity types' checklists and attribute schemas, with questions to ask the user.
+func (c *Client) FindKnowledgeGaps(ctx context.Context, id string, q api.KnowledgeGapsQuery) (*model.KnowledgeGaps, error) {
+	query := url.Values{}
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var resp model.KnowledgeGaps
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/gaps", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetContradictions calls GET /groups/:id/contradictions. List the contradicting facts the latest contradiction scan found that are still valid.
+func (c *Client) GetContradictions(ctx context.Context, id string) (*model.ContradictionReport, error) {
+	var resp model.ContradictionReport
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/contradictions", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListModerationRecords calls GET /groups/:id/moderation. List the audit records of the group's episodes that moderation blocked or flagged, newest first. Requires the admin scope.
+func (c *Client) ListModerationRecords(ctx context.Context, id string, q api.ModerationQuery) (*api.ModerationResponse, error) {
+	query := url.Values{}
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var resp api.ModerationResponse
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/moderation", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExportGroup calls GET /groups/:id/export. Export every node and relationship of a group with their stored properties, unfiltered by access policies. Requires the admin scope.
+func (c *Client) ExportGroup(ctx context.Context, id string) (*model.GraphExport, error) {
+	var resp model.GraphExport
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/export", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSynonyms calls GET /groups/:id/synonyms. Get a group's synonym table, used by text search and entity linking.
+func (c *Client) GetSynonyms(ctx context.Context, id string) (*api.SynonymsResponse, error) {
+	var resp api.SynonymsResponse
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/synonyms", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateSynonyms calls PATCH /groups/:id/synonyms. Add, replace or (with null) remove entries of a group's synonym table. Requires the admin scope.
+func (c *Client) UpdateSynonyms(ctx context.Context, id string, req *api.SynonymsRequest) (*api.SynonymsResponse, error) {
+	var resp api.SynonymsResponse
+	if err := c.do(ctx, "PATCH", "/groups/"+url.PathEscape(id)+"/synonyms", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListScratch calls GET /groups/:id/scratchpad. List a group's unexpired scratchpad entries, ordered by key.
+func (c *Client) ListScratch(ctx context.Context, id string) (*api.ScratchpadResponse, error) {
+	var resp api.ScratchpadResponse
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/scratchpad", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetScratch calls GET /groups/:id/scratchpad/:key. Get a scratchpad entry.
+func (c *Client) GetScratch(ctx context.Context, id string, key string) (*model.ScratchEntry, error) {
+	var resp model.ScratchEntry
+	if err := c.do(ctx, "GET", "/groups/"+url.PathEscape(id)+"/scratchpad/"+url.PathEscape(key), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetScratch calls PUT /groups/:id/scratchpad/:key. Set a scratchpad entry, optionally expiring after a TTL. Scratchpad entries are kept outside the knowledge graph.
+func (c *Client) SetScratch(ctx context.Context, id string, key string, req *api.ScratchRequest) (*model.ScratchEntry, error) {
+	var resp model.ScratchEntry
+	if err := c.do(ctx, "PUT", "/groups/"+url.PathEscape(id)+"/scratchpad/"+url.PathEscape(key), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteScratch calls DELETE /groups/:id/scratchpad/:key. Delete a scratchpad entry.
+func (c *Client) DeleteScratch(ctx context.Context, id string, key string) (*api.StatusResponse, error) {
+	var resp api.StatusResponse
+	if err := c.do(ctx, "DELETE", "/groups/"+url.PathEscape(id)+"/scratchpad/"+url.PathEscape(key), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListBackups calls GET /admin/backups. List stored snapshots, newest first. Requires the admin scope.
+func (c *Client) ListBackups(ctx context.Context, q api.BackupQuery) (*api.BackupsResponse, error) {
+	query := url.Values{}
+	if q.GroupID != "" {
+		query.Set("group_id", q.GroupID)
+	}
+	var resp api.BackupsResponse
+	if err := c.do(ctx, "GET", "/admin/backups", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateBackup calls POST /admin/backups. Snapshot a group, or the graph as configured, to the backup store.
+func (c *Client) CreateBackup(ctx context.Context, req *api.BackupRequest) (*api.BackupsResponse, error) {
+	var resp api.BackupsResponse
+	if err := c.do(ctx, "POST", "/admin/backups", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RestoreBackup calls POST /admin/restore. Replace a group, or the whole graph, with a snapshot after snapshotting its current state.
+func (c *Client) RestoreBackup(ctx context.Context, req *api.RestoreRequest) (*model.RestoreReport, error) {
+	var resp model.RestoreReport
+	if err := c.do(ctx, "POST", "/admin/restore", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetConcurrency calls GET /admin/concurrency. Get the bulk ingest and bulk search limits with their queue depth and wait times.
+func (c *Client) GetConcurrency(ctx context.Context) (*model.ConcurrencyStats, error) {
+	var resp model.ConcurrencyStats
+	if err := c.do(ctx, "GET", "/admin/concurrency", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateConcurrency calls PATCH /admin/concurrency. Change the bulk ingest and bulk search limits until the server restarts.
+func (c *Client) UpdateConcurrency(ctx context.Context, req *model.ConcurrencyPatch) (*model.ConcurrencyStats, error) {
+	var resp model.ConcurrencyStats
+	if err := c.do(ctx, "PATCH", "/admin/concurrency", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProfileIngest calls POST /debug/ingest-profile. Ingest an episode into a throwaway group and report how long each pipeline stage took. Requires [debug] enabled.
+func (c *Client) ProfileIngest(ctx context.Context, req *api.IngestProfileRequest) (*model.IngestProfile, error) {
+	var resp model.IngestProfile
+	if err := c.do(ctx, "POST", "/debug/ingest-profile", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddMemories calls POST /v1/memories/. mem0: ingest messages and return the memories they added or invalidated.
+func (c *Client) AddMemories(ctx context.Context, req *api.Mem0AddRequest) (*api.Mem0Results, error) {
+	var resp api.Mem0Results
+	if err := c.do(ctx, "POST", "/v1/memories/", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListMemories calls GET /v1/memories/. mem0: list the current memories of a user, agent or run.
+func (c *Client) ListMemories(ctx context.Context, q api.Mem0Scope) (*api.Mem0Results, error) {
+	query := url.Values{}
+	if q.UserID != "" {
+		query.Set("user_id", q.UserID)
+	}
+	if q.AgentID != "" {
+		query.Set("agent_id", q.AgentID)
+	}
+	if q.RunID != "" {
+		query.Set("run_id", q.RunID)
+	}
+	var resp api.Mem0Results
+	if err := c.do(ctx, "GET", "/v1/memories/", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetMemory calls GET /v1/memories/:memory_id/. mem0: get a memory by ID.
+func (c *Client) GetMemory(ctx context.Context, memoryID string) (*api.Mem0Memory, error) {
+	var resp api.Mem0Memory
+	if err := c.do(ctx, "GET", "/v1/memories/"+url.PathEscape(memoryID)+"/", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchMemories calls POST /v1/memories/search/. mem0: search the memories of a user, agent or run.
+func (c *Client) SearchMemories(ctx context.Context, req *api.Mem0SearchRequest) (*api.Mem0Results, error) {
+	var resp api.Mem0Results
+	if err := c.do(ctx, "POST", "/v1/memories/search/", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,177 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/agenthands/carbon/internal/server"
+	"github.com/agenthands/carbon/pkg/api"
+	"github.com/agenthands/carbon/pkg/carbon"
+	"github.com/agenthands/carbon/pkg/carbontest"
+	"github.com/agenthands/carbon/pkg/client"
+)
+
+func newTestClient(t *testing.T, llm carbon.LLMClient) (*client.Client, *carbon.Graphiti) {
+	gin.SetMode(gin.TestMode)
+	g := carbontest.NewEngine(llm)
+	srv := httptest.NewServer((&server.Server{Graphiti: g}).SetupRouter())
+	t.Cleanup(srv.Close)
+	return client.New(srv.URL), g
+}
+
+func TestClient_IngestAndSearch(t *testing.T) {
+	ctx := context.Background()
+	llm := carbontest.NewMockLLM().
+		On("Extract entities", `{"extracted_entities": [{"name": "Alice", "entity_type_id": 1}]}`).
+		On("Extract edges", `{"extracted_edges": []}`)
+	c, g := newTestClient(t, llm)
+
+	status, err := c.AddMessages(ctx, &api.AddMessageRequest{
+		GroupID:  "g1",
+		Messages: []api.Message{{Role: "user", Content: "Hi, I'm Alice."}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "success", status.Status)
+
+	group, err := c.GetGroup(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, "g1", group.GroupID)
+
+	alice := carbontest.NewEntity("g1", "Alice", "")
+	berlin := carbontest.NewEntity("g1", "Berlin", "")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin},
+		Facts:    []carbon.EntityEdge{carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")},
+	}))
+	resp, err := c.Search(ctx, &api.SearchRequest{GroupID: "g1", Query: "Where does Alice live?"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "Alice lives in Berlin", resp.Results[0].Fact)
+	assert.Nil(t, resp.Trace)
+
+	resp, err = c.Search(ctx, &api.SearchRequest{GroupID: "g1", Query: "Where does Alice live?", Debug: true})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Trace)
+	assert.Equal(t, "vector", resp.Trace.Strategy)
+	assert.Equal(t, []string{resp.Results[0].UUID}, resp.Trace.Results)
+
+	depth := 0
+	view, err := c.GetGraph(ctx, api.GraphQuery{GroupID: "g1", Center: alice.UUID, Depth: &depth})
+	require.NoError(t, err)
+	assert.Len(t, view.Nodes, 1)
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+
+	_, err := c.GetGroup(context.Background(), "missing")
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Group not found", apiErr.Message)
+}
+
+func TestClient_ValidationError(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+
+	_, err := c.AddMessages(context.Background(), &api.AddMessageRequest{
+		Messages: []api.Message{{Role: "user", Content: "Hi"}, {Role: "user"}},
+	})
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, []api.FieldError{
+		{Field: "group_id", Constraint: "required", Message: "is required"},
+		{Field: "messages[1].content", Constraint: "required", Message: "is required"},
+	}, apiErr.Fields)
+}
+
+func TestClient_APIKeyScopes(t *testing.T) {
+	ctx := context.Background()
+	c, g := newTestClient(t, nil)
+	g.Config.Access = carbon.AccessConfig{
+		Policies: []carbon.AccessPolicy{{Scope: "medical", Relations: []string{"DIAGNOSED_WITH"}}},
+		Keys:     []carbon.APIKeyConfig{{Key: "doctor-key", Scopes: []string{"medical"}}},
+	}
+	alice := carbontest.NewEntity("g1", "Alice", "")
+	flu := carbontest.NewEntity("g1", "Flu", "")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, flu},
+		Facts:    []carbon.EntityEdge{carbontest.NewFact(alice, flu, "DIAGNOSED_WITH", "Alice has the flu")},
+	}))
+	search := &api.SearchRequest{GroupID: "g1", Query: "Is Alice sick?"}
+
+	resp, err := c.Search(ctx, search)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Results)
+
+	c.APIKey = "doctor-key"
+	resp, err = c.Search(ctx, search)
+	require.NoError(t, err)
+	assert.Len(t, resp.Results, 1)
+
+	c.APIKey = "stolen-key"
+	_, err = c.Search(ctx, search)
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestClient_StreamBulkEpisodes(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+
+	results, err := c.StreamBulkEpisodes(context.Background(), "g1", []carbon.StreamEpisode{
+		{EpisodeData: carbon.EpisodeData{Content: "Hello."}},
+		{EpisodeData: carbon.EpisodeData{Content: "Bye."}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "g1", results[0].GroupID)
+}
+
+func TestClient_Mem0Memories(t *testing.T) {
+	ctx := context.Background()
+	c, g := newTestClient(t, nil)
+
+	alice := carbontest.NewEntity("alice", "Alice", "")
+	berlin := carbontest.NewEntity("alice", "Berlin", "")
+	tea := carbontest.NewEntity("alice", "Tea", "")
+	fact := carbontest.NewFact(alice, berlin, "LIVES_IN", "Alice lives in Berlin")
+	require.NoError(t, carbontest.Seed(ctx, g.Driver, g.Embedder, carbontest.Fixture{
+		Entities: []carbon.EntityNode{alice, berlin, tea},
+		Facts:    []carbon.EntityEdge{fact, carbontest.NewFact(alice, tea, "LIKES", "Alice likes tea")},
+	}))
+
+	added, err := c.AddMemories(ctx, &api.Mem0AddRequest{
+		Mem0Scope: api.Mem0Scope{UserID: "alice"},
+		Messages:  []api.Message{{Role: "user", Content: "Hello again."}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, added.Results)
+
+	list, err := c.ListMemories(ctx, api.Mem0Scope{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, list.Results, 2)
+	assert.Equal(t, "alice", list.Results[0].UserID)
+
+	memory, err := c.GetMemory(ctx, fact.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice lives in Berlin", memory.Memory)
+	assert.Equal(t, "LIVES_IN", memory.Metadata["relation"])
+
+	found, err := c.SearchMemories(ctx, &api.Mem0SearchRequest{Mem0Scope: api.Mem0Scope{UserID: "alice"}, Query: "Where does Alice live?", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, found.Results, 1)
+
+	_, err = c.ListMemories(ctx, api.Mem0Scope{})
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
@@ -56,6 +56,7 @@ func TestCommunityDetection(t *testing.T) {
 	defer d.Close(context.Background())
 
 	ctx := context.Background()
+	applyReplayEnv(cfg)
 	llmClient, embedder, err := llm.NewClient(ctx, cfg.LLM)
 	require.NoError(t, err)
 
@@ -85,6 +85,7 @@ Example JSON:
 	defer d.Close(context.Background())
 
 	ctx := context.Background()
+	applyReplayEnv(cfg)
 	llmClient, embedder, err := llm.NewClient(ctx, cfg.LLM)
 	require.NoError(t, err)
 
@@ -69,6 +69,7 @@ func TestFullFlow(t *testing.T) {
 	defer d.Close(context.Background())
 
 	ctx := context.Background()
+	applyReplayEnv(cfg)
 	llmClient, embedder, err := llm.NewClient(ctx, cfg.LLM)
 	require.NoError(t, err)
 
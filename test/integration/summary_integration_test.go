@@ -50,6 +50,7 @@ func TestSummarizationIntegration(t *testing.T) {
 	defer d.Close(context.Background())
 
 	ctx := context.Background()
+	applyReplayEnv(cfg)
 	llmClient, embedder, err := llm.NewClient(ctx, cfg.LLM)
 	require.NoError(t, err)
 